@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 	"unicode/utf8"
 )
@@ -14,8 +16,12 @@ import (
 const (
 	BodyPlaceholder = "[[SECTOOL_BODY_FILE: body]]"
 	DefaultDir      = "sectool-requests"
+	RedactedValue   = "[REDACTED]"
 )
 
+// DefaultRedactHeaders are the credential-bearing headers masked when exporting with redaction.
+var DefaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
 // Meta is request bundle metadata.
 type Meta struct {
 	FlowID     string `json:"flow_id"`
@@ -24,13 +30,26 @@ type Meta struct {
 	Method     string `json:"method"`
 	BodyIsUTF8 bool   `json:"body_is_utf8"`
 	BodySize   int    `json:"body_size"`
+
+	RedactedHeaders []string `json:"redacted_headers,omitempty"` // headers masked in request.http / response.http
 }
 
 // Write writes a request bundle to ./sectool-requests/<flowID>/.
+// Values of headers named in redactHeaders are masked in both request and response headers.
 // Uses restrictive permissions (0700 dirs, 0600 files) and rejects symlinks.
-func Write(flowID, url, method, reqHeaders string, reqBody []byte, respHeaders string, respBody []byte) (string, error) {
+func Write(flowID, url, method, reqHeaders string, reqBody []byte, respHeaders string, respBody []byte, redactHeaders []string) (string, error) {
 	bundleDir := filepath.Join(DefaultDir, flowID)
 
+	var redacted []string
+	if len(redactHeaders) > 0 {
+		var reqRedacted, respRedacted []string
+		reqHeaders, reqRedacted = RedactHeaders(reqHeaders, redactHeaders)
+		respHeaders, respRedacted = RedactHeaders(respHeaders, redactHeaders)
+		redacted = append(reqRedacted, respRedacted...)
+		slices.Sort(redacted)
+		redacted = slices.Compact(redacted)
+	}
+
 	if err := mkdirAllSafe(bundleDir, 0700); err != nil {
 		return "", fmt.Errorf("create bundle directory: %w", err)
 	}
@@ -57,6 +76,8 @@ func Write(flowID, url, method, reqHeaders string, reqBody []byte, respHeaders s
 		Method:     method,
 		BodyIsUTF8: utf8.Valid(reqBody),
 		BodySize:   len(reqBody),
+
+		RedactedHeaders: redacted,
 	}
 	metaBytes, err := json.MarshalIndent(meta, "", "  ")
 	if err != nil {
@@ -79,6 +100,88 @@ func Write(flowID, url, method, reqHeaders string, reqBody []byte, respHeaders s
 	return bundleDir, nil
 }
 
+// IndexFile is the name of the export index kept in DefaultDir.
+const IndexFile = "index.json"
+
+// IndexEntry describes one exported bundle in the export index.
+type IndexEntry struct {
+	FlowID     string `json:"flow_id"`
+	SessionID  string `json:"session_id,omitempty"`
+	URL        string `json:"url"`
+	Method     string `json:"method"`
+	Status     int    `json:"status,omitempty"`
+	ExportedAt string `json:"exported_at"`
+}
+
+// UpdateIndex merges entries into ./sectool-requests/index.json, replacing entries with
+// the same flow ID and appending new ones in order. Returns the index path.
+func UpdateIndex(entries []IndexEntry) (string, error) {
+	indexPath := filepath.Join(DefaultDir, IndexFile)
+
+	var index []IndexEntry
+	if data, err := os.ReadFile(indexPath); err == nil {
+		if err := json.Unmarshal(data, &index); err != nil {
+			return "", fmt.Errorf("parse %s: %w", IndexFile, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("read %s: %w", IndexFile, err)
+	}
+
+	for _, entry := range entries {
+		if i := slices.IndexFunc(index, func(e IndexEntry) bool { return e.FlowID == entry.FlowID }); i >= 0 {
+			index[i] = entry
+		} else {
+			index = append(index, entry)
+		}
+	}
+
+	if err := mkdirAllSafe(DefaultDir, 0700); err != nil {
+		return "", fmt.Errorf("create bundle directory: %w", err)
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal index: %w", err)
+	} else if err := writeFileSafe(indexPath, data, 0600); err != nil {
+		return "", fmt.Errorf("write %s: %w", IndexFile, err)
+	}
+	return indexPath, nil
+}
+
+// RedactHeaders replaces the values of headers matching names (case-insensitive) with RedactedValue.
+// The first line (request or status line) is never modified.
+// Returns the modified headers and the lowercase names that were redacted.
+func RedactHeaders(headers string, names []string) (string, []string) {
+	if headers == "" || len(names) == 0 {
+		return headers, nil
+	}
+
+	lines := strings.SplitAfter(headers, "\n")
+	var redacted []string
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		name, _, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if !slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, name) }) {
+			continue
+		}
+
+		var ending string
+		if strings.HasSuffix(line, "\r\n") {
+			ending = "\r\n"
+		} else if strings.HasSuffix(line, "\n") {
+			ending = "\n"
+		}
+		lines[i] = name + ": " + RedactedValue + ending
+		if lower := strings.ToLower(name); !slices.Contains(redacted, lower) {
+			redacted = append(redacted, lower)
+		}
+	}
+	return strings.Join(lines, ""), redacted
+}
+
 // mkdirAllSafe creates directories with symlink protection.
 func mkdirAllSafe(path string, perm os.FileMode) error {
 	path = filepath.Clean(path)
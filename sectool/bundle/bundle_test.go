@@ -1,6 +1,7 @@
 package bundle
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -28,6 +29,7 @@ func TestWrite(t *testing.T) {
 			[]byte(`{"key":"value"}`),
 			"",
 			nil,
+			nil,
 		)
 		require.NoError(t, err)
 		assert.DirExists(t, bundleDir)
@@ -45,6 +47,7 @@ func TestWrite(t *testing.T) {
 			[]byte{},
 			"",
 			nil,
+			nil,
 		)
 		require.NoError(t, err)
 
@@ -62,6 +65,7 @@ func TestWrite(t *testing.T) {
 			[]byte{},
 			"HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n",
 			[]byte("<html>OK</html>"),
+			nil,
 		)
 		require.NoError(t, err)
 		assert.FileExists(t, filepath.Join(bundleDir, "response.http"))
@@ -82,6 +86,7 @@ func TestWrite(t *testing.T) {
 			binaryBody,
 			"",
 			nil,
+			nil,
 		)
 		require.NoError(t, err)
 
@@ -93,6 +98,35 @@ func TestWrite(t *testing.T) {
 		require.NoError(t, err)
 		assert.Contains(t, string(metaBytes), `"body_is_utf8": false`)
 	})
+
+	t.Run("redacts_headers", func(t *testing.T) {
+		bundleDir, err := Write(
+			"flow-redact",
+			"https://example.com/",
+			"GET",
+			"GET / HTTP/1.1\r\nHost: example.com\r\nAuthorization: Bearer secret\r\nX-Api-Key: k123\r\n",
+			[]byte{},
+			"HTTP/1.1 200 OK\r\nSet-Cookie: sid=abc\r\n",
+			nil,
+			[]string{"Authorization", "Set-Cookie", "X-Api-Key"},
+		)
+		require.NoError(t, err)
+
+		reqHeaders, err := os.ReadFile(filepath.Join(bundleDir, "request.http"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(reqHeaders), "secret")
+		assert.NotContains(t, string(reqHeaders), "k123")
+		assert.Contains(t, string(reqHeaders), "Authorization: "+RedactedValue+"\r\n")
+		assert.Contains(t, string(reqHeaders), "Host: example.com")
+
+		respHeaders, err := os.ReadFile(filepath.Join(bundleDir, "response.http"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(respHeaders), "sid=abc")
+
+		_, _, meta, err := Read(bundleDir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"authorization", "set-cookie", "x-api-key"}, meta.RedactedHeaders)
+	})
 }
 
 func TestRead(t *testing.T) {
@@ -114,6 +148,7 @@ func TestRead(t *testing.T) {
 			[]byte("test body content"),
 			"",
 			nil,
+			nil,
 		)
 		require.NoError(t, err)
 
@@ -158,6 +193,35 @@ func TestRead(t *testing.T) {
 	})
 }
 
+func TestRedactHeaders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("case_insensitive", func(t *testing.T) {
+		out, redacted := RedactHeaders("GET / HTTP/1.1\r\ncookie: a=b\r\nAccept: */*\r\n", []string{"Cookie"})
+		assert.Equal(t, "GET / HTTP/1.1\r\ncookie: "+RedactedValue+"\r\nAccept: */*\r\n", out)
+		assert.Equal(t, []string{"cookie"}, redacted)
+	})
+
+	t.Run("lf_line_endings", func(t *testing.T) {
+		out, redacted := RedactHeaders("HTTP/1.1 200 OK\nSet-Cookie: a=1\nSet-Cookie: b=2\n", []string{"Set-Cookie"})
+		assert.Equal(t, "HTTP/1.1 200 OK\nSet-Cookie: "+RedactedValue+"\nSet-Cookie: "+RedactedValue+"\n", out)
+		assert.Equal(t, []string{"set-cookie"}, redacted)
+	})
+
+	t.Run("no_match", func(t *testing.T) {
+		in := "GET / HTTP/1.1\r\nHost: example.com\r\n"
+		out, redacted := RedactHeaders(in, DefaultRedactHeaders)
+		assert.Equal(t, in, out)
+		assert.Empty(t, redacted)
+	})
+
+	t.Run("start_line_untouched", func(t *testing.T) {
+		in := "Cookie: x HTTP/1.1\r\n"
+		out, _ := RedactHeaders(in, []string{"Cookie"})
+		assert.Equal(t, in, out)
+	})
+}
+
 func TestReconstructRequest(t *testing.T) {
 	t.Parallel()
 
@@ -229,3 +293,36 @@ func TestResolvePath(t *testing.T) {
 		assert.Contains(t, err.Error(), "not found")
 	})
 }
+
+func TestUpdateIndex(t *testing.T) {
+	// Not parallel - uses os.Chdir
+
+	origDir, err := os.Getwd()
+	require.NoError(t, err)
+	tempDir := t.TempDir()
+	require.NoError(t, os.Chdir(tempDir))
+	t.Cleanup(func() { _ = os.Chdir(origDir) })
+
+	path, err := UpdateIndex([]IndexEntry{
+		{FlowID: "flow-1", URL: "https://example.com/", Method: "GET", Status: 200},
+		{FlowID: "flow-2", URL: "https://example.com/a", Method: "GET", Status: 404},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(DefaultDir, IndexFile), path)
+
+	_, err = UpdateIndex([]IndexEntry{
+		{FlowID: "flow-3", URL: "https://example.com/b", Method: "POST", Status: 201},
+		{FlowID: "flow-1", URL: "https://example.com/", Method: "GET", Status: 304},
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var index []IndexEntry
+	require.NoError(t, json.Unmarshal(data, &index))
+	require.Len(t, index, 3)
+	assert.Equal(t, "flow-1", index[0].FlowID)
+	assert.Equal(t, 304, index[0].Status)
+	assert.Equal(t, "flow-2", index[1].FlowID)
+	assert.Equal(t, "flow-3", index[2].FlowID)
+}
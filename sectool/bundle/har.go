@@ -0,0 +1,211 @@
+package bundle
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-analyze/bulk"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+// HAREntry is one request/response exchange to include in a HAR export.
+// Headers are raw HTTP header blocks including the request or status line.
+type HAREntry struct {
+	URL         string
+	Method      string
+	ReqHeaders  string
+	ReqBody     []byte
+	RespHeaders string
+	RespBody    []byte
+}
+
+// HAR 1.2 document types (http://www.softwareishard.com/blog/har-12-spec/).
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            int         `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    int `json:"send"`
+	Wait    int `json:"wait"`
+	Receive int `json:"receive"`
+}
+
+// WriteHAR writes entries as a HAR 1.2 file at path. Values of headers named in
+// redactHeaders are masked. Capture times are not tracked, so every entry uses
+// the export time. Uses the same symlink protections as Write.
+func WriteHAR(path string, entries []HAREntry, redactHeaders []string) error {
+	startedAt := time.Now().UTC().Format(time.RFC3339)
+	doc := harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "sectool", Version: config.Version},
+		Entries: make([]harEntry, 0, len(entries)),
+	}}
+	for _, e := range entries {
+		reqHeaders, _ := RedactHeaders(e.ReqHeaders, redactHeaders)
+		respHeaders, _ := RedactHeaders(e.RespHeaders, redactHeaders)
+		doc.Log.Entries = append(doc.Log.Entries, harEntry{
+			StartedDateTime: startedAt,
+			Request:         buildHARRequest(e, reqHeaders),
+			Response:        buildHARResponse(e, respHeaders),
+		})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal har: %w", err)
+	} else if err := mkdirAllSafe(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create har directory: %w", err)
+	} else if err := writeFileSafe(path, data, 0600); err != nil {
+		return fmt.Errorf("write har: %w", err)
+	}
+	return nil
+}
+
+func buildHARRequest(e HAREntry, rawHeaders string) harRequest {
+	firstLine, headers := parseHeaderBlock(rawHeaders)
+	req := harRequest{
+		Method:      e.Method,
+		URL:         e.URL,
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []harNameValue{},
+		Headers:     headers,
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(e.ReqBody),
+	}
+	if parts := strings.SplitN(firstLine, " ", 3); len(parts) == 3 {
+		req.HTTPVersion = parts[2]
+	}
+	if u, err := url.Parse(e.URL); err == nil {
+		query := u.Query()
+		names := bulk.MapKeysSlice(query)
+		slices.Sort(names)
+		for _, name := range names {
+			for _, v := range query[name] {
+				req.QueryString = append(req.QueryString, harNameValue{Name: name, Value: v})
+			}
+		}
+	}
+	if len(e.ReqBody) > 0 {
+		req.PostData = &harPostData{MimeType: headerValue(headers, "Content-Type"), Text: string(e.ReqBody)}
+	}
+	return req
+}
+
+func buildHARResponse(e HAREntry, rawHeaders string) harResponse {
+	statusLine, headers := parseHeaderBlock(rawHeaders)
+	resp := harResponse{
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []harNameValue{},
+		Headers:     headers,
+		Content:     harContent{Size: len(e.RespBody), MimeType: headerValue(headers, "Content-Type")},
+		RedirectURL: headerValue(headers, "Location"),
+		HeadersSize: -1,
+		BodySize:    len(e.RespBody),
+	}
+	if parts := strings.SplitN(statusLine, " ", 3); len(parts) >= 2 {
+		resp.HTTPVersion = parts[0]
+		resp.Status, _ = strconv.Atoi(parts[1])
+		if len(parts) == 3 {
+			resp.StatusText = parts[2]
+		}
+	}
+	if utf8.Valid(e.RespBody) {
+		resp.Content.Text = string(e.RespBody)
+	} else {
+		resp.Content.Text = base64.StdEncoding.EncodeToString(e.RespBody)
+		resp.Content.Encoding = "base64"
+	}
+	return resp
+}
+
+// parseHeaderBlock splits a raw header block into its first line and ordered headers.
+func parseHeaderBlock(raw string) (string, []harNameValue) {
+	headers := []harNameValue{}
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	for _, line := range lines[1:] {
+		if name, value, ok := strings.Cut(line, ":"); ok {
+			headers = append(headers, harNameValue{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+		}
+	}
+	return strings.TrimSpace(lines[0]), headers
+}
+
+func headerValue(headers []harNameValue, name string) string {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value
+		}
+	}
+	return ""
+}
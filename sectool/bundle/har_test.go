@@ -0,0 +1,67 @@
+package bundle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteHAR(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "out", "export.har")
+	err := WriteHAR(path, []HAREntry{
+		{
+			URL:         "https://example.com/api/items?b=2&a=1",
+			Method:      "POST",
+			ReqHeaders:  "POST /api/items?b=2&a=1 HTTP/1.1\r\nHost: example.com\r\nContent-Type: application/json\r\nAuthorization: Bearer secret\r\n",
+			ReqBody:     []byte(`{"name":"x"}`),
+			RespHeaders: "HTTP/1.1 201 Created\r\nContent-Type: application/json\r\nSet-Cookie: sid=abc\r\n",
+			RespBody:    []byte(`{"id":1}`),
+		},
+		{
+			URL:         "https://example.com/logo.png",
+			Method:      "GET",
+			ReqHeaders:  "GET /logo.png HTTP/2\r\nHost: example.com\r\n",
+			RespHeaders: "HTTP/2 302 Found\r\nLocation: /img/logo.png\r\n",
+			RespBody:    []byte{0x89, 0x50, 0x4e, 0xff},
+		},
+	}, []string{"Authorization", "Set-Cookie"})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var doc harFile
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	assert.Equal(t, "1.2", doc.Log.Version)
+	assert.Equal(t, "sectool", doc.Log.Creator.Name)
+	require.Len(t, doc.Log.Entries, 2)
+
+	first := doc.Log.Entries[0]
+	assert.Equal(t, "POST", first.Request.Method)
+	assert.Equal(t, "HTTP/1.1", first.Request.HTTPVersion)
+	assert.Equal(t, []harNameValue{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}}, first.Request.QueryString)
+	assert.Equal(t, RedactedValue, headerValue(first.Request.Headers, "Authorization"))
+	require.NotNil(t, first.Request.PostData)
+	assert.Equal(t, "application/json", first.Request.PostData.MimeType)
+	assert.JSONEq(t, `{"name":"x"}`, first.Request.PostData.Text)
+	assert.Equal(t, 201, first.Response.Status)
+	assert.Equal(t, "Created", first.Response.StatusText)
+	assert.Equal(t, RedactedValue, headerValue(first.Response.Headers, "Set-Cookie"))
+	assert.JSONEq(t, `{"id":1}`, first.Response.Content.Text)
+	assert.Empty(t, first.Response.Content.Encoding)
+
+	second := doc.Log.Entries[1]
+	assert.Nil(t, second.Request.PostData)
+	assert.Equal(t, "HTTP/2", second.Request.HTTPVersion)
+	assert.Equal(t, 302, second.Response.Status)
+	assert.Equal(t, "/img/logo.png", second.Response.RedirectURL)
+	assert.Equal(t, "base64", second.Response.Content.Encoding)
+	assert.Equal(t, "iVBO/w==", second.Response.Content.Text)
+	assert.Equal(t, 4, second.Response.Content.Size)
+}
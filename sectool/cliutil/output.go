@@ -1,8 +1,11 @@
 package cliutil
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"golang.org/x/term"
 )
@@ -36,6 +39,20 @@ func init() {
 	}
 }
 
+// ParseColorMode parses a --color value: auto, always, or never.
+func ParseColorMode(s string) (ColorMode, error) {
+	switch strings.ToLower(s) {
+	case "auto":
+		return ColorAuto, nil
+	case "always":
+		return ColorAlways, nil
+	case "never":
+		return ColorNever, nil
+	default:
+		return ColorAuto, fmt.Errorf("invalid color mode %q: use auto, always, or never", s)
+	}
+}
+
 // IsTTY returns true if output should be formatted for a terminal.
 func (o *OutputConfig) IsTTY() bool {
 	if o == nil {
@@ -53,3 +70,11 @@ func (o *OutputConfig) IsTTY() bool {
 func (o *OutputConfig) ColorsEnabled() bool {
 	return o.IsTTY()
 }
+
+// WriteJSON writes v to w as indented JSON, for --json output of a response struct.
+func WriteJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
@@ -2,10 +2,14 @@ package cliutil
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
 )
 
 func TestOutputConfig_IsTTY(t *testing.T) {
@@ -51,3 +55,74 @@ func TestOutputConfig_IsTTY(t *testing.T) {
 		})
 	}
 }
+
+func TestParseColorMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    ColorMode
+		wantErr bool
+	}{
+		{input: "auto", want: ColorAuto},
+		{input: "always", want: ColorAlways},
+		{input: "NEVER", want: ColorNever},
+		{input: "sometimes", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseColorMode(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("list_round_trip", func(t *testing.T) {
+		t.Parallel()
+		want := protocol.CrawlPollResponse{
+			SessionID: "s1",
+			State:     "running",
+			Flows: []protocol.CrawlFlow{
+				{FlowID: "f1", Method: "GET", Host: "example.com", Path: "/a?x=<b>&y=1", Status: 200, ResponseLength: 42, Duration: "12ms"},
+			},
+			Errors: []protocol.CrawlError{{URL: "https://example.com/missing", Error: "Not Found", Status: 404}},
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteJSON(&buf, want))
+		assert.Contains(t, buf.String(), "\n  \"session_id\": \"s1\"")
+		assert.Contains(t, buf.String(), "/a?x=<b>&y=1")
+
+		var got protocol.CrawlPollResponse
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("status_round_trip", func(t *testing.T) {
+		t.Parallel()
+		want := protocol.CrawlStatusResponse{
+			State:          "completed",
+			URLsVisited:    7,
+			Duration:       "3s",
+			LastActivity:   "2024-01-01T00:00:00Z",
+			HostQuotaSkips: map[string]int{"example.com": 2},
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteJSON(&buf, want))
+
+		var got protocol.CrawlStatusResponse
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Equal(t, want, got)
+	})
+}
@@ -33,12 +33,19 @@ func DefaultPath() string {
 	return filepath.Join(home, ".sectool", "config.json")
 }
 
+// PIDPath returns the file, next to configPath, where the server listening on mcpPort
+// records its process ID for `sectool stop`.
+func PIDPath(configPath string, mcpPort int) string {
+	return filepath.Join(filepath.Dir(configPath), fmt.Sprintf("server-%d.pid", mcpPort))
+}
+
 type Config struct {
 	Version             string        `json:"version"`
 	MCPPort             int           `json:"mcp_port"`
 	ProxyPort           int           `json:"proxy_port"`
 	BurpRequired        *bool         `json:"burp_required"`
-	MaxBodyBytes        int           `json:"max_body_bytes"` // limits request/response body sizes
+	MaxBodyBytes        int           `json:"max_body_bytes"`   // limits request/response body sizes
+	MaxHeaderBytes      int           `json:"max_header_bytes"` // limits captured crawl request/response header sizes; larger response headers fail the request
 	IncludeSubdomains   *bool         `json:"include_subdomains"`
 	AllowedDomains      []string      `json:"allowed_domains"`
 	ExcludeDomains      []string      `json:"exclude_domains"`
@@ -62,6 +69,39 @@ type CrawlerConfig struct {
 	ExtractForms    *bool    `json:"extract_forms"`
 	SubmitForms     *bool    `json:"submit_forms"`
 	Recon           *bool    `json:"recon"`
+	SearchIndex     *bool    `json:"search_index"`
+	// SessionTTLSecs deletes completed or stopped sessions idle for longer; negative keeps them
+	SessionTTLSecs   int `json:"session_ttl_secs"`
+	SessionSweepSecs int `json:"session_sweep_secs"` // how often expired sessions are looked for
+	// PersistFlows keeps sessions and flows in PersistDir (default ~/.sectool/crawl) across restarts
+	PersistFlows *bool  `json:"persist_flows"`
+	PersistDir   string `json:"persist_dir"`
+	// SecretPatterns are matched against crawled response bodies to flag leaked secrets;
+	// an empty list disables the scan
+	SecretPatterns []SecretPattern `json:"secret_patterns"`
+}
+
+// SecretPattern is a named regular expression flagging sensitive data in crawled responses.
+type SecretPattern struct {
+	Name  string `json:"name"`
+	Regex string `json:"regex"` // RE2; when it has a capture group, the first group is the secret
+	// MinEntropy rejects matches whose secret has less Shannon entropy (bits per character),
+	// keeping generic token patterns from flagging placeholders and repeated characters
+	MinEntropy float64 `json:"min_entropy,omitempty"`
+	Luhn       bool    `json:"luhn,omitempty"` // secret digits must pass the Luhn checksum
+}
+
+// DefaultSecretPatterns returns the built-in sensitive data patterns.
+func DefaultSecretPatterns() []SecretPattern {
+	return []SecretPattern{
+		{Name: "aws-access-key", Regex: `\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`},
+		{Name: "google-api-key", Regex: `\b(AIza[0-9A-Za-z_\-]{35})(?:[^0-9A-Za-z_\-]|$)`},
+		{Name: "jwt", Regex: `\b(eyJ[A-Za-z0-9_\-]{8,}\.eyJ[A-Za-z0-9_\-]{8,}\.[A-Za-z0-9_\-]{16,})`},
+		{Name: "private-key", Regex: `-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----`},
+		{Name: "email", Regex: `\b([A-Za-z0-9._%+\-]+@[A-Za-z][A-Za-z0-9\-]*(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,})\b`},
+		{Name: "credit-card", Regex: `(?:^|[^0-9])((?:4\d{3}|5[1-5]\d{2}|6011)(?:[ \-]?\d{4}){3}|3[47]\d{2}[ \-]?\d{6}[ \-]?\d{5})(?:[^0-9]|$)`, Luhn: true},
+		{Name: "generic-secret", Regex: `(?i)(?:api[_\-]?key|secret|access[_\-]?token|auth[_\-]?token|passw(?:or)?d)["']?\s*[:=]\s*["']([A-Za-z0-9_\-+/=.]{16,})["']`, MinEntropy: 3.5},
+	}
 }
 
 // DefaultConfig returns a Config with default values.
@@ -74,6 +114,7 @@ func DefaultConfig() *Config {
 		ProxyPort:         DefaultProxyPort,
 		BurpRequired:      &f,
 		MaxBodyBytes:      10485760, // 10MB
+		MaxHeaderBytes:    1048576,  // 1MB
 		IncludeSubdomains: &t,
 		AllowedDomains:    []string{},
 		ExcludeDomains:    []string{},
@@ -97,6 +138,12 @@ func DefaultConfig() *Config {
 			ExtractForms: &t,
 			SubmitForms:  &f,
 			Recon:        &f,
+			SearchIndex:  &t,
+
+			SessionTTLSecs:   3600,
+			SessionSweepSecs: 60,
+			PersistFlows:     &f,
+			SecretPatterns:   DefaultSecretPatterns(),
 		},
 	}
 }
@@ -126,6 +173,9 @@ func loadConfig(path string) (*Config, error) {
 	if cfg.MaxBodyBytes == 0 {
 		cfg.MaxBodyBytes = defaults.MaxBodyBytes
 	}
+	if cfg.MaxHeaderBytes == 0 {
+		cfg.MaxHeaderBytes = defaults.MaxHeaderBytes
+	}
 	if cfg.IncludeSubdomains == nil {
 		cfg.IncludeSubdomains = defaults.IncludeSubdomains
 	}
@@ -162,6 +212,21 @@ func loadConfig(path string) (*Config, error) {
 	if cfg.Crawler.Recon == nil {
 		cfg.Crawler.Recon = defaults.Crawler.Recon
 	}
+	if cfg.Crawler.SearchIndex == nil {
+		cfg.Crawler.SearchIndex = defaults.Crawler.SearchIndex
+	}
+	if cfg.Crawler.SessionTTLSecs == 0 {
+		cfg.Crawler.SessionTTLSecs = defaults.Crawler.SessionTTLSecs
+	}
+	if cfg.Crawler.SessionSweepSecs <= 0 {
+		cfg.Crawler.SessionSweepSecs = defaults.Crawler.SessionSweepSecs
+	}
+	if cfg.Crawler.PersistFlows == nil {
+		cfg.Crawler.PersistFlows = defaults.Crawler.PersistFlows
+	}
+	if cfg.Crawler.SecretPatterns == nil {
+		cfg.Crawler.SecretPatterns = defaults.Crawler.SecretPatterns
+	}
 
 	return &cfg, nil
 }
@@ -52,6 +52,10 @@ func TestLoadAppliesDefaults(t *testing.T) {
 	cfg, err := loadConfig(path)
 	require.NoError(t, err)
 	assert.Equal(t, DefaultMCPPort, cfg.MCPPort)
+	assert.Equal(t, DefaultConfig().MaxHeaderBytes, cfg.MaxHeaderBytes)
+	assert.Equal(t, 3600, cfg.Crawler.SessionTTLSecs)
+	assert.Equal(t, 60, cfg.Crawler.SessionSweepSecs)
+	assert.Equal(t, DefaultSecretPatterns(), cfg.Crawler.SecretPatterns)
 }
 
 func TestLoadInvalidJSON(t *testing.T) {
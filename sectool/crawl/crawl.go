@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-analyze/bulk"
 	"github.com/jedib0t/go-pretty/v6/table"
 
 	"github.com/go-appsec/toolbox/sectool/bundle"
@@ -17,7 +18,7 @@ import (
 	"github.com/go-appsec/toolbox/sectool/protocol"
 )
 
-func create(mcpURL string, urls, flows, domains []string, label string, maxDepth, maxRequests int, delay time.Duration, parallelism int, submitForms, ignoreRobots bool) error {
+func create(mcpURL string, opts mcpclient.CrawlCreateOpts) error {
 	ctx := context.Background()
 
 	client, err := mcpclient.Connect(ctx, mcpURL)
@@ -26,23 +27,7 @@ func create(mcpURL string, urls, flows, domains []string, label string, maxDepth
 	}
 	defer func() { _ = client.Close() }()
 
-	var delayStr string
-	if delay > 0 {
-		delayStr = delay.String()
-	}
-
-	resp, err := client.CrawlCreate(ctx, mcpclient.CrawlCreateOpts{
-		Label:        label,
-		SeedURLs:     strings.Join(urls, ","),
-		SeedFlows:    strings.Join(flows, ","),
-		Domains:      strings.Join(domains, ","),
-		MaxDepth:     maxDepth,
-		MaxRequests:  maxRequests,
-		Delay:        delayStr,
-		Parallelism:  parallelism,
-		SubmitForms:  submitForms,
-		IgnoreRobots: ignoreRobots,
-	})
+	resp, err := client.CrawlCreate(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("crawl create failed: %w", err)
 	}
@@ -88,7 +73,10 @@ func seed(mcpURL string, sessionID string, urls, flows []string) error {
 	return nil
 }
 
-func status(mcpURL string, sessionID string) error {
+// statusWatchWait is how long each --watch request waits for crawl activity.
+const statusWatchWait = "30s"
+
+func status(mcpURL string, sessionID string, watch, jsonOut bool) error {
 	ctx := context.Background()
 
 	client, err := mcpclient.Connect(ctx, mcpURL)
@@ -97,28 +85,176 @@ func status(mcpURL string, sessionID string) error {
 	}
 	defer func() { _ = client.Close() }()
 
-	resp, err := client.CrawlStatus(ctx, sessionID)
+	resp, err := client.CrawlStatus(ctx, sessionID, "")
 	if err != nil {
 		return fmt.Errorf("crawl status failed: %w", err)
 	}
+	if jsonOut && !watch {
+		return cliutil.WriteJSON(os.Stdout, resp)
+	} else if !watch {
+		printStatus(resp)
+		return nil
+	}
+
+	// Redraw in place on a terminal; otherwise print each update as a new block (one JSON
+	// document per update with --json)
+	tty := cliutil.Output.IsTTY() && !jsonOut
+	for {
+		if tty {
+			fmt.Print("\x1b[H\x1b[2J")
+		}
+		if jsonOut {
+			if err := cliutil.WriteJSON(os.Stdout, resp); err != nil {
+				return err
+			}
+		} else {
+			printStatus(resp)
+		}
+		if resp.State != "running" {
+			return nil
+		}
+		if !tty && !jsonOut {
+			fmt.Println()
+		}
+
+		resp, err = client.CrawlStatus(ctx, sessionID, statusWatchWait)
+		if err != nil {
+			return fmt.Errorf("crawl status failed: %w", err)
+		}
+	}
+}
 
+func printStatus(resp *protocol.CrawlStatusResponse) {
 	fmt.Println(cliutil.Bold("Crawl Status"))
 	fmt.Println()
-	fmt.Printf("State: %s\n", cliutil.Bold(resp.State))
+	fmt.Printf("State: %s\n", formatState(resp.State))
 	fmt.Printf("URLs Queued: %d\n", resp.URLsQueued)
 	fmt.Printf("URLs Visited: %d\n", resp.URLsVisited)
-	fmt.Printf("URLs Errored: %d\n", resp.URLsErrored)
+	if resp.URLsErrored > 0 {
+		fmt.Printf("URLs Errored: %s\n", cliutil.Warning(strconv.Itoa(resp.URLsErrored)))
+	} else {
+		fmt.Printf("URLs Errored: %d\n", resp.URLsErrored)
+	}
 	fmt.Printf("Forms Discovered: %d\n", resp.FormsDiscovered)
 	fmt.Printf("Duration: %s\n", resp.Duration)
 	fmt.Printf("Last Activity: %s\n", resp.LastActivity)
 	if resp.ErrorMessage != "" {
 		fmt.Printf("Error: %s\n", cliutil.Error(resp.ErrorMessage))
 	}
+	if resp.MergedSlashVariants > 0 {
+		fmt.Printf("Merged Slash Variants: %d\n", resp.MergedSlashVariants)
+	}
+	if resp.BlockedNote != "" {
+		fmt.Printf("Blocked: %s\n", cliutil.Warning(resp.BlockedNote))
+	}
+	if len(resp.TokenRefreshes) > 0 {
+		fmt.Printf("Token Refreshes: %d\n", len(resp.TokenRefreshes))
+		for _, r := range resp.TokenRefreshes {
+			if r.Error != "" {
+				fmt.Printf("  %s after 401 from %s: %s\n", r.Time, r.TriggerURL, cliutil.Error(r.Error))
+			} else {
+				fmt.Printf("  %s after 401 from %s\n", r.Time, r.TriggerURL)
+			}
+		}
+	}
+	if len(resp.DiscoveredPaths) > 0 {
+		fmt.Printf("Discovered Paths: %d\n", len(resp.DiscoveredPaths))
+		for _, p := range resp.DiscoveredPaths {
+			fmt.Printf("  %s (%s)\n", p.URL, p.Source)
+		}
+	}
+	printHosts(resp.Hosts, resp.SkippedHosts, resp.HostQuotaSkips)
+}
+
+// printHosts lists distinct hosts requested, any skipped by --max-hosts, and
+// hosts that reached --max-pages-per-host.
+func printHosts(hosts, skipped []string, quotaSkips map[string]int) {
+	if len(hosts) > 0 {
+		fmt.Printf("Hosts (%d): %s\n", len(hosts), strings.Join(hosts, ", "))
+	}
+	if len(skipped) > 0 {
+		fmt.Printf("Skipped Hosts (%d): %s\n", len(skipped), cliutil.Warning(strings.Join(skipped, ", ")))
+	}
+	if len(quotaSkips) > 0 {
+		quotaHosts := bulk.MapKeysSlice(quotaSkips)
+		slices.Sort(quotaHosts)
+		parts := make([]string, 0, len(quotaHosts))
+		for _, h := range quotaHosts {
+			parts = append(parts, fmt.Sprintf("%s (%d skipped)", h, quotaSkips[h]))
+		}
+		fmt.Printf("Host Quota Reached (%d): %s\n", len(quotaHosts), cliutil.Warning(strings.Join(parts, ", ")))
+	}
+}
+
+func stats(mcpURL string, sessionID string) error {
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.CrawlStats(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("crawl stats failed: %w", err)
+	}
+
+	fmt.Println(cliutil.Bold("Crawl Stats"))
+	fmt.Println()
+	fmt.Printf("Session: %s | Flows: %d\n", cliutil.ID(resp.SessionID), resp.TotalFlows)
+	fmt.Println()
+
+	if resp.TotalFlows == 0 {
+		cliutil.NoResults(os.Stdout, "No traffic captured.")
+		return nil
+	}
+
+	fmt.Println(cliutil.Bold("Status Codes"))
+	t := cliutil.NewTable(os.Stdout)
+	t.AppendHeader(table.Row{"Status", "Count", "Percent"})
+	t.SetRowPainter(cliutil.StatusRowPainter(0))
+	for _, sc := range resp.StatusCodes {
+		t.AppendRow(table.Row{sc.Status, sc.Count, formatPercent(sc.Count, resp.TotalFlows)})
+	}
+	t.Render()
+	fmt.Println()
+
+	fmt.Println(cliutil.Bold("Content Types"))
+	t = cliutil.NewTable(os.Stdout)
+	t.AppendHeader(table.Row{"Content Type", "Count", "Percent"})
+	for _, ct := range resp.ContentTypes {
+		name := ct.ContentType
+		if name == "" {
+			name = "(none)"
+		}
+		t.AppendRow(table.Row{name, ct.Count, formatPercent(ct.Count, resp.TotalFlows)})
+	}
+	t.Render()
+
+	if s := resp.Search; s.Searches > 0 || s.IndexEnabled {
+		fmt.Println()
+		fmt.Println(cliutil.Bold("Search"))
+		index := "disabled"
+		if s.IndexEnabled {
+			index = fmt.Sprintf("%d tokens", s.IndexedTokens)
+		}
+		fmt.Printf("Index: %s\n", index)
+		fmt.Printf("Searches: %d (%d indexed, %d full scan)\n", s.Searches, s.IndexedSearches, s.ScanSearches)
+		if s.Searches > 0 {
+			fmt.Printf("Latency: avg %s, max %s\n", s.AvgLatency, s.MaxLatency)
+		}
+	}
 
 	return nil
 }
 
-func summary(mcpURL string, sessionID, host, path, method, status, searchHeader, searchBody, excludeHost, excludePath string) error {
+// formatPercent formats count as a percentage of total with one decimal place.
+func formatPercent(count, total int) string {
+	return strconv.FormatFloat(float64(count)*100/float64(total), 'f', 1, 64) + "%"
+}
+
+func summary(mcpURL string, sessionID, host, path, method, status, searchHeader, searchBody, excludeHost, excludePath string, jsonOut bool) error {
 	ctx := context.Background()
 
 	client, err := mcpclient.Connect(ctx, mcpURL)
@@ -140,11 +276,14 @@ func summary(mcpURL string, sessionID, host, path, method, status, searchHeader,
 	})
 	if err != nil {
 		return fmt.Errorf("crawl summary failed: %w", err)
+	} else if jsonOut {
+		return cliutil.WriteJSON(os.Stdout, resp)
 	}
 
 	fmt.Println(cliutil.Bold("Crawl Summary"))
 	fmt.Println()
-	fmt.Printf("Session: %s | State: %s | Duration: %s\n", cliutil.ID(resp.SessionID), cliutil.Bold(resp.State), resp.Duration)
+	fmt.Printf("Session: %s | State: %s | Duration: %s\n", cliutil.ID(resp.SessionID), formatState(resp.State), resp.Duration)
+	printHosts(resp.Hosts, resp.SkippedHosts, resp.HostQuotaSkips)
 	fmt.Println()
 
 	if len(resp.Aggregates) == 0 {
@@ -170,7 +309,7 @@ func summary(mcpURL string, sessionID, host, path, method, status, searchHeader,
 	return nil
 }
 
-func list(mcpURL string, sessionID, listType, host, path, method, status, searchHeader, searchBody, excludeHost, excludePath, since string, limit, offset int) error {
+func list(mcpURL string, sessionID string, opts mcpclient.CrawlPollOpts, jsonOut bool) error {
 	ctx := context.Background()
 
 	client, err := mcpclient.Connect(ctx, mcpURL)
@@ -179,34 +318,14 @@ func list(mcpURL string, sessionID, listType, host, path, method, status, search
 	}
 	defer func() { _ = client.Close() }()
 
-	// Map CLI listType to output_mode
-	outputMode := "flows"
-	switch listType {
-	case "forms":
-		outputMode = "forms"
-	case "errors":
-		outputMode = "errors"
-	}
-
-	resp, err := client.CrawlPoll(ctx, sessionID, mcpclient.CrawlPollOpts{
-		OutputMode:   outputMode,
-		Host:         host,
-		Path:         path,
-		Method:       method,
-		Status:       status,
-		SearchHeader: searchHeader,
-		SearchBody:   searchBody,
-		ExcludeHost:  excludeHost,
-		ExcludePath:  excludePath,
-		Since:        since,
-		Limit:        limit,
-		Offset:       offset,
-	})
+	resp, err := client.CrawlPoll(ctx, sessionID, opts)
 	if err != nil {
 		return fmt.Errorf("crawl list failed: %w", err)
+	} else if jsonOut {
+		return cliutil.WriteJSON(os.Stdout, resp)
 	}
 
-	switch outputMode {
+	switch opts.OutputMode {
 	case "forms":
 		if len(resp.Forms) == 0 {
 			cliutil.NoResults(os.Stdout, "No forms discovered.")
@@ -222,6 +341,32 @@ func list(mcpURL string, sessionID, listType, host, path, method, status, search
 			if form.HasCSRF {
 				fmt.Printf("CSRF Token: %s\n", cliutil.Success("detected"))
 			}
+			if form.AutoSubmit {
+				fmt.Printf("Auto-Submit: %s\n", cliutil.Warning("yes (submitted by script on load)"))
+			}
+			if form.HasJSHandlers {
+				fmt.Printf("JS Handlers: %s\n", cliutil.Warning("yes"))
+			}
+			if len(form.SubmissionFlowIDs) > 0 {
+				flowIDs := make([]string, 0, len(form.SubmissionFlowIDs))
+				for _, id := range form.SubmissionFlowIDs {
+					flowIDs = append(flowIDs, cliutil.ID(id))
+				}
+				fmt.Printf("Submission Flows: %s\n", strings.Join(flowIDs, ", "))
+			}
+			if mc := form.MethodCheck; mc != nil {
+				verdict := mc.Verdict
+				if mc.GetStatus > 0 || mc.PostStatus > 0 {
+					verdict += fmt.Sprintf(" (GET %d, POST %d)", mc.GetStatus, mc.PostStatus)
+				}
+				if mc.WrongMethodAccepted {
+					verdict = cliutil.Warning(verdict + " - accepts both methods")
+				}
+				fmt.Printf("Method Check: %s\n", verdict)
+				if mc.GetFlowID != "" || mc.PostFlowID != "" {
+					fmt.Printf("Method Flows: GET %s, POST %s\n", cliutil.ID(mc.GetFlowID), cliutil.ID(mc.PostFlowID))
+				}
+			}
 			if len(form.Inputs) > 0 {
 				fmt.Println()
 				t := cliutil.NewTable(os.Stdout)
@@ -244,34 +389,104 @@ func list(mcpURL string, sessionID, listType, host, path, method, status, search
 			return nil
 		}
 		t := cliutil.NewTable(os.Stdout)
-		t.AppendHeader(table.Row{"URL", "Status", "Error"})
+		t.AppendHeader(table.Row{"URL", "Status", "Error", "Flow"})
 		for _, e := range resp.Errors {
 			statusStr := ""
 			if e.Status > 0 {
-				statusStr = strconv.Itoa(e.Status)
+				statusStr = cliutil.FormatStatus(e.Status)
 			}
-			t.AppendRow(table.Row{e.URL, statusStr, e.Error})
+			t.AppendRow(table.Row{e.URL, statusStr, e.Error, cliutil.ID(e.FlowID)})
 		}
 		t.Render()
 		cliutil.Summary(os.Stdout, len(resp.Errors), "error", "errors")
 
-	default: // flows
-		if len(resp.Flows) == 0 {
-			cliutil.NoResults(os.Stdout, "No flows found.")
+	case "external":
+		if len(resp.External) == 0 {
+			cliutil.NoResults(os.Stdout, "No external links found.")
+			return nil
+		}
+		t := cliutil.NewTable(os.Stdout)
+		t.AppendHeader(table.Row{"Host", "URL", "Found On"})
+		for _, l := range resp.External {
+			t.AppendRow(table.Row{l.Host, l.URL, l.FoundOn})
+		}
+		t.Render()
+		cliutil.Summary(os.Stdout, len(resp.External), "external link", "external links")
+
+	case "routes":
+		if len(resp.Routes) == 0 {
+			cliutil.NoResults(os.Stdout, "No client-side routes found (create the crawl with --extract-js-routes).")
+			return nil
+		}
+		t := cliutil.NewTable(os.Stdout)
+		t.AppendHeader(table.Row{"Route", "Kind", "Found On"})
+		for _, r := range resp.Routes {
+			t.AppendRow(table.Row{r.Route, r.Kind, r.FoundOn})
+		}
+		t.Render()
+		cliutil.Summary(os.Stdout, len(resp.Routes), "client-side route", "client-side routes")
+
+	case "endpoints":
+		if len(resp.Endpoints) == 0 {
+			cliutil.NoResults(os.Stdout, "No JavaScript endpoints found (create the crawl with --extract-js-endpoints).")
 			return nil
 		}
 		t := cliutil.NewTable(os.Stdout)
-		t.AppendHeader(table.Row{"Flow ID", "Method", "Host", "Path", "Status", "Size"})
-		t.SetRowPainter(cliutil.StatusRowPainter(4))
-		for _, flow := range resp.Flows {
-			t.AppendRow(table.Row{flow.FlowID, flow.Method, flow.Host, flow.Path, flow.Status, flow.ResponseLength})
+		t.AppendHeader(table.Row{"URL", "Method", "Kind", "Found On"})
+		for _, e := range resp.Endpoints {
+			t.AppendRow(table.Row{e.URL, e.Method, e.Kind, e.FoundOn})
+		}
+		t.Render()
+		cliutil.Summary(os.Stdout, len(resp.Endpoints), "JavaScript endpoint", "JavaScript endpoints")
+
+	case "similar":
+		if len(resp.Similar) == 0 {
+			cliutil.NoResults(os.Stdout, "No similar responses clustered (create the crawl with --similarity-threshold).")
+			return nil
+		}
+		t := cliutil.NewTable(os.Stdout)
+		t.AppendHeader(table.Row{"Representative", "Host", "Path", "Status", "Type", "Size", "Flows"})
+		t.SetRowPainter(cliutil.StatusRowPainter(3))
+		for _, c := range resp.Similar {
+			flows := strings.Join(c.FlowIDs, ", ")
+			if c.Size > len(c.FlowIDs) {
+				flows += fmt.Sprintf(", ... (+%d)", c.Size-len(c.FlowIDs))
+			}
+			t.AppendRow(table.Row{c.RepresentativeID, c.Host, c.Path, c.Status, c.ContentType, c.Size, flows})
 		}
 		t.Render()
+		cliutil.Summary(os.Stdout, len(resp.Similar), "cluster", "clusters")
+
+	case "findings":
+		if len(resp.Findings) == 0 {
+			cliutil.NoResults(os.Stdout, "No findings.")
+			return nil
+		}
+		t := cliutil.NewTable(os.Stdout)
+		t.AppendHeader(table.Row{"Flow ID", "Finding", "Pattern", "Offset", "Snippet", "URL"})
+		for _, f := range resp.Findings {
+			var offset any
+			if f.Pattern != "" {
+				offset = f.Offset
+			}
+			t.AppendRow(table.Row{f.FlowID, f.Finding, f.Pattern, offset, f.Snippet, f.URL})
+		}
+		t.Render()
+		cliutil.Summary(os.Stdout, len(resp.Findings), "finding", "findings")
+
+	default: // flows
+		if len(resp.Flows) == 0 {
+			cliutil.NoResults(os.Stdout, "No flows found.")
+			return nil
+		}
+		renderFlows(resp.Flows)
 		cliutil.Summary(os.Stdout, len(resp.Flows), "flow", "flows")
-		if len(resp.Flows) == limit && limit > 0 {
-			cliutil.Hint(os.Stdout, fmt.Sprintf("More results may be available. Use --offset %d to paginate.", offset+limit))
+		if len(resp.Flows) == opts.Limit && opts.Limit > 0 {
+			cliutil.Hint(os.Stdout, fmt.Sprintf("More results may be available. Use --offset %d to paginate.", opts.Offset+opts.Limit))
 		}
-		if len(resp.Flows) > 0 {
+		if opts.Cursor != "" {
+			cliutil.HintCommand(os.Stdout, "To list new flows for this cursor", fmt.Sprintf("sectool crawl list %s --cursor %s", sessionID, opts.Cursor))
+		} else if len(resp.Flows) > 0 {
 			lastFlow := resp.Flows[len(resp.Flows)-1]
 			cliutil.HintCommand(os.Stdout, "To list flows after this", fmt.Sprintf("sectool crawl list %s --since %s", sessionID, lastFlow.FlowID))
 		}
@@ -286,7 +501,82 @@ func list(mcpURL string, sessionID, listType, host, path, method, status, search
 	return nil
 }
 
-func sessions(mcpURL string, limit int) error {
+// formatState colors a crawl session state: running bold, completed green, stopped yellow.
+func formatState(state string) string {
+	switch state {
+	case "running":
+		return cliutil.Bold(state)
+	case "completed":
+		return cliutil.Success(state)
+	case "stopped":
+		return cliutil.Warning(state)
+	default:
+		return state
+	}
+}
+
+// renderFlows prints crawl flows as a table, with redirect target and findings columns
+// when any flow has them.
+func renderFlows(flows []protocol.CrawlFlow) {
+	hasRedirects := slices.ContainsFunc(flows, func(f protocol.CrawlFlow) bool { return f.RedirectTo != "" })
+	hasFindings := slices.ContainsFunc(flows, func(f protocol.CrawlFlow) bool { return len(f.Findings) > 0 })
+	t := cliutil.NewTable(os.Stdout)
+	header := table.Row{"Flow ID", "Method", "Host", "Path", "Status", "Size"}
+	if hasRedirects {
+		header = append(header, "Redirect To")
+	}
+	if hasFindings {
+		header = append(header, "Findings")
+	}
+	t.AppendHeader(header)
+	t.SetRowPainter(cliutil.StatusRowPainter(4))
+	for _, flow := range flows {
+		row := table.Row{flow.FlowID, flow.Method, flow.Host, flow.Path, flow.Status, flow.ResponseLength}
+		if hasRedirects {
+			row = append(row, flow.RedirectTo)
+		}
+		if hasFindings {
+			row = append(row, cliutil.Warning(strings.Join(flow.Findings, ", ")))
+		}
+		t.AppendRow(row)
+	}
+	t.Render()
+}
+
+func poll(mcpURL string, sessionID, host, path, method, status, since, cursor string, wait time.Duration, limit int) error {
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.CrawlPoll(ctx, sessionID, mcpclient.CrawlPollOpts{
+		OutputMode: "flows",
+		Host:       host,
+		Path:       path,
+		Method:     method,
+		Status:     status,
+		Since:      since,
+		Cursor:     cursor,
+		Limit:      limit,
+		Wait:       wait.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("crawl poll failed: %w", err)
+	}
+
+	if len(resp.Flows) == 0 {
+		cliutil.NoResults(os.Stdout, "No new flows.")
+		return nil
+	}
+	renderFlows(resp.Flows)
+	cliutil.Summary(os.Stdout, len(resp.Flows), "flow", "flows")
+	return nil
+}
+
+func sessions(mcpURL string, limit int, jsonOut bool) error {
 	ctx := context.Background()
 
 	client, err := mcpclient.Connect(ctx, mcpURL)
@@ -298,6 +588,8 @@ func sessions(mcpURL string, limit int) error {
 	resp, err := client.CrawlSessions(ctx, limit)
 	if err != nil {
 		return fmt.Errorf("crawl sessions failed: %w", err)
+	} else if jsonOut {
+		return cliutil.WriteJSON(os.Stdout, resp)
 	}
 
 	if len(resp.Sessions) == 0 {
@@ -315,12 +607,12 @@ func sessions(mcpURL string, limit int) error {
 	if hasLabels {
 		t.AppendHeader(table.Row{"Session ID", "Label", "State", "Created At"})
 		for _, sess := range resp.Sessions {
-			t.AppendRow(table.Row{sess.SessionID, sess.Label, sess.State, sess.CreatedAt})
+			t.AppendRow(table.Row{sess.SessionID, sess.Label, formatState(sess.State), sess.CreatedAt})
 		}
 	} else {
 		t.AppendHeader(table.Row{"Session ID", "State", "Created At"})
 		for _, sess := range resp.Sessions {
-			t.AppendRow(table.Row{sess.SessionID, sess.State, sess.CreatedAt})
+			t.AppendRow(table.Row{sess.SessionID, formatState(sess.State), sess.CreatedAt})
 		}
 	}
 	t.Render()
@@ -347,6 +639,79 @@ func stop(mcpURL string, sessionID string) error {
 	return nil
 }
 
+func resume(mcpURL string, sessionID string) error {
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.CrawlResume(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("crawl resume failed: %w", err)
+	}
+
+	fmt.Printf("Crawl session `%s` resumed (%s) with %d URLs queued.\n", sessionID, formatState(resp.State), resp.URLsRequeued)
+	cliutil.HintCommand(os.Stdout, "Check progress", fmt.Sprintf("sectool crawl status %s", sessionID))
+
+	return nil
+}
+
+func deleteFlows(mcpURL string, sessionID string, opts mcpclient.CrawlDeleteOpts) error {
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.CrawlDelete(ctx, sessionID, opts)
+	if err != nil {
+		return fmt.Errorf("crawl delete failed: %w", err)
+	}
+
+	if opts.FlowID != "" {
+		fmt.Printf("Deleted flow `%s`.\n", opts.FlowID)
+	} else if resp.SessionDeleted {
+		fmt.Printf("Deleted session `%s` with %d flow(s).\n", sessionID, resp.Deleted)
+	} else {
+		fmt.Printf("Deleted %d flow(s) from session `%s`.\n", resp.Deleted, sessionID)
+	}
+	return nil
+}
+
+func extract(mcpURL string, flowID, source string) error {
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ExtractLinks(ctx, flowID, source)
+	if err != nil {
+		return fmt.Errorf("crawl extract failed: %w", err)
+	}
+
+	if len(resp.Links) == 0 {
+		cliutil.NoResults(os.Stdout, "No links found in the response.")
+		return nil
+	}
+	t := cliutil.NewTable(os.Stdout)
+	t.AppendHeader(table.Row{"URL", "Source"})
+	for _, l := range resp.Links {
+		t.AppendRow(table.Row{l.URL, l.Source})
+	}
+	t.Render()
+	cliutil.Summary(os.Stdout, len(resp.Links), "link", "links")
+
+	return nil
+}
+
 func get(mcpURL string, flowID, scope, pattern string) error {
 	ctx := context.Background()
 
@@ -373,8 +738,41 @@ func get(mcpURL string, flowID, scope, pattern string) error {
 	if resp.FoundOn != "" {
 		fmt.Printf("Found On: %s\n", resp.FoundOn)
 	}
+	if resp.VariantOf != "" {
+		fmt.Printf("Variant Of: %s (varied %s)\n", cliutil.ID(resp.VariantOf), resp.VariedHeader)
+	}
+	if resp.ReferrerPolicy != "" {
+		fmt.Printf("Referrer Policy: %s\n", resp.ReferrerPolicy)
+	}
+	if resp.OriginalURL != "" {
+		fmt.Printf("Original URL: %s\n", resp.OriginalURL)
+	}
+	if resp.JSONPCallback != "" {
+		fmt.Printf("JSONP Callback: %s\n", resp.JSONPCallback)
+	}
+	for _, comment := range resp.HTMLComments {
+		fmt.Printf("HTML Comment: <!-- %s -->\n", comment)
+	}
+	if resp.CookieAfter != "" {
+		fmt.Printf("Session Cookie Rotated: %s -> %s\n", resp.CookieBefore, resp.CookieAfter)
+	}
+	if resp.RedirectTo != "" {
+		fmt.Printf("Redirect To: %s\n", resp.RedirectTo)
+	}
+	if resp.RedirectedFrom != "" {
+		fmt.Printf("Redirected From: %s (%s)\n", cliutil.ID(resp.RedirectedFrom), strings.Join(resp.RedirectChain, " -> "))
+	}
+	if len(resp.Findings) > 0 {
+		fmt.Printf("Findings: %s\n", cliutil.Warning(strings.Join(resp.Findings, ", ")))
+	}
+	for _, m := range resp.SensitiveData {
+		fmt.Printf("Sensitive Data: %s at offset %d: %s\n", m.Pattern, m.Offset, m.Snippet)
+	}
 	fmt.Printf("Request Size: %d bytes\n", resp.ReqSize)
 	fmt.Printf("Response Size: %d bytes\n", resp.RespSize)
+	if resp.HeadersTruncated {
+		fmt.Println(cliutil.Warning("Headers truncated at max_header_bytes"))
+	}
 
 	if resp.ReqHeaders != "" {
 		fmt.Println()
@@ -402,49 +800,317 @@ func get(mcpURL string, flowID, scope, pattern string) error {
 	return nil
 }
 
-func export(mcpURL string, flowID string) error {
+func export(mcpURL string, flowID string, redact bool, extraRedactHeaders []string) error {
 	ctx := context.Background()
 
+	var redactHeaders []string
+	if redact {
+		redactHeaders = append(slices.Clone(bundle.DefaultRedactHeaders), extraRedactHeaders...)
+	}
+
 	client, err := mcpclient.Connect(ctx, mcpURL)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = client.Close() }()
 
+	resp, bundleDir, err := writeFlowBundle(ctx, client, flowID, redactHeaders)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported flow `%s` to `%s/`\n", flowID, bundleDir)
+	if len(redactHeaders) > 0 {
+		fmt.Println(cliutil.Muted("Sensitive header values redacted (recorded in request.meta.json)"))
+	}
+	fmt.Println()
+	fmt.Println("Files:")
+	fmt.Println("- request.http - HTTP request headers")
+	fmt.Println("- body - request body (edit this)")
+	fmt.Println("- request.meta.json - metadata")
+	if resp.RespHeaders != "" {
+		fmt.Println("- response.http - response headers")
+		fmt.Println("- response.body - response body")
+	}
+	fmt.Println()
+	fmt.Printf("To replay: `sectool replay send --bundle %s`\n", flowID)
+
+	return nil
+}
+
+// exportSince exports every flow in a session after the since/cursor position and
+// records them in the bundle index.
+func exportSince(mcpURL, sessionID, since, cursor string, redact bool, extraRedactHeaders []string) error {
+	ctx := context.Background()
+
+	var redactHeaders []string
+	if redact {
+		redactHeaders = append(slices.Clone(bundle.DefaultRedactHeaders), extraRedactHeaders...)
+	}
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	list, err := client.CrawlPoll(ctx, sessionID, mcpclient.CrawlPollOpts{
+		OutputMode: "flows",
+		Since:      since,
+		Cursor:     cursor,
+	})
+	if err != nil {
+		return fmt.Errorf("list flows: %w", err)
+	} else if len(list.Flows) == 0 {
+		cliutil.NoResults(os.Stdout, "No new flows to export.")
+		return nil
+	}
+
+	exportedAt := time.Now().UTC().Format(time.RFC3339)
+	entries := make([]bundle.IndexEntry, 0, len(list.Flows))
+	for _, flow := range list.Flows {
+		resp, _, err := writeFlowBundle(ctx, client, flow.FlowID, redactHeaders)
+		if err != nil {
+			return fmt.Errorf("export flow %s: %w", flow.FlowID, err)
+		}
+		entries = append(entries, bundle.IndexEntry{
+			FlowID:     flow.FlowID,
+			SessionID:  sessionID,
+			URL:        resp.URL,
+			Method:     resp.Method,
+			Status:     resp.Status,
+			ExportedAt: exportedAt,
+		})
+	}
+
+	indexPath, err := bundle.UpdateIndex(entries)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d flows from session `%s` to `%s/`\n", len(entries), sessionID, bundle.DefaultDir)
+	if len(redactHeaders) > 0 {
+		fmt.Println(cliutil.Muted("Sensitive header values redacted (recorded in request.meta.json)"))
+	}
+	fmt.Println()
+	for _, e := range entries {
+		fmt.Printf("- %s %s %s (%s)\n", cliutil.ID(e.FlowID), e.Method, e.URL, cliutil.FormatStatus(e.Status))
+	}
+	fmt.Println()
+	fmt.Printf("Index: `%s`\n", indexPath)
+
+	return nil
+}
+
+// exportSession writes the session's flow metadata as NDJSON or CSV to out, or stdout.
+func exportSession(mcpURL, sessionID, format, out string) error {
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	data, err := client.CrawlExportSession(ctx, sessionID, format)
+	if err != nil {
+		return fmt.Errorf("crawl export-session failed: %w", err)
+	}
+
+	if out == "" {
+		fmt.Print(data)
+		return nil
+	} else if err := os.WriteFile(out, []byte(data), 0600); err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+	fmt.Printf("Exported session `%s` flows as %s to `%s`\n", sessionID, format, out)
+	return nil
+}
+
+// writeFlowBundle fetches a crawled flow with full bodies and writes it as a bundle.
+func writeFlowBundle(ctx context.Context, client *mcpclient.Client, flowID string, redactHeaders []string) (*protocol.CrawlGetResponse, string, error) {
 	resp, err := client.CrawlGet(ctx, flowID, mcpclient.CrawlGetOpts{FullBody: true})
 	if err != nil {
-		return fmt.Errorf("get flow: %w", err)
+		return nil, "", fmt.Errorf("get flow: %w", err)
 	}
 
 	reqBody, err := bundle.DecodeBase64Body(resp.ReqBody)
 	if err != nil {
-		return fmt.Errorf("decode request body: %w", err)
+		return nil, "", fmt.Errorf("decode request body: %w", err)
 	}
 
 	respBody, err := bundle.DecodeBase64Body(resp.RespBody)
 	if err != nil {
-		return fmt.Errorf("decode response body: %w", err)
+		return nil, "", fmt.Errorf("decode response body: %w", err)
 	}
 
 	bundleDir, err := bundle.Write(flowID,
 		resp.URL, resp.Method, resp.ReqHeaders, reqBody,
-		resp.RespHeaders, respBody)
+		resp.RespHeaders, respBody, redactHeaders)
 	if err != nil {
-		return fmt.Errorf("write bundle: %w", err)
+		return nil, "", fmt.Errorf("write bundle: %w", err)
 	}
+	return resp, bundleDir, nil
+}
 
-	fmt.Printf("Exported flow `%s` to `%s/`\n", flowID, bundleDir)
-	fmt.Println()
-	fmt.Println("Files:")
-	fmt.Println("- request.http - HTTP request headers")
-	fmt.Println("- body - request body (edit this)")
-	fmt.Println("- request.meta.json - metadata")
-	if resp.RespHeaders != "" {
-		fmt.Println("- response.http - response headers")
-		fmt.Println("- response.body - response body")
+// findingSeverity ranks known finding types for crawl reports; unknown types are "info".
+var findingSeverity = map[string]string{
+	"jsonp":             "medium",
+	"directory-listing": "low",
+	"html-comment":      "low",
+}
+
+var severityOrder = []string{"high", "medium", "low", "info"}
+
+const reportSnippetLen = 160
+
+// reportFinding is one finding on one flow, as rendered in a crawl report.
+type reportFinding struct {
+	finding string
+	flowID  string
+	method  string
+	url     string
+	status  int
+	snippet string
+}
+
+// report renders all findings of a session as Markdown, to out or stdout.
+func report(mcpURL, sessionID, out string) error {
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	status, err := client.CrawlStatus(ctx, sessionID, "")
+	if err != nil {
+		return fmt.Errorf("crawl status failed: %w", err)
+	}
+	list, err := client.CrawlPoll(ctx, sessionID, mcpclient.CrawlPollOpts{
+		OutputMode: "flows",
+		Limit:      1_000_000_000,
+	})
+	if err != nil {
+		return fmt.Errorf("list flows: %w", err)
 	}
-	fmt.Println()
-	fmt.Printf("To replay: `sectool replay send --bundle %s`\n", flowID)
 
+	var findings []reportFinding
+	for _, flow := range list.Flows {
+		if len(flow.Findings) == 0 {
+			continue
+		}
+		resp, err := client.CrawlGet(ctx, flow.FlowID, mcpclient.CrawlGetOpts{Scope: "response_body"})
+		if err != nil {
+			return fmt.Errorf("get flow %s: %w", flow.FlowID, err)
+		}
+		for _, f := range flow.Findings {
+			findings = append(findings, reportFinding{
+				finding: f,
+				flowID:  flow.FlowID,
+				method:  flow.Method,
+				url:     resp.URL,
+				status:  flow.Status,
+				snippet: findingSnippet(f, resp),
+			})
+		}
+	}
+
+	md := renderReport(sessionID, status, findings)
+	if out == "" {
+		fmt.Print(md)
+		return nil
+	} else if err := os.WriteFile(out, []byte(md), 0600); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	fmt.Printf("Wrote report for session `%s` with %d findings to `%s`\n", sessionID, len(findings), out)
 	return nil
 }
+
+// findingSnippet returns the evidence shown for a finding: the recorded comment for
+// "html-comment", the cookie names for "session-cookie-rotated", otherwise the start of
+// the response body.
+func findingSnippet(finding string, resp *protocol.CrawlGetResponse) string {
+	if finding == "html-comment" && len(resp.HTMLComments) > 0 {
+		return reportSnippet(resp.HTMLComments[0])
+	} else if finding == "session-cookie-rotated" && resp.CookieAfter != "" {
+		return resp.CookieBefore + " -> " + resp.CookieAfter
+	}
+	return reportSnippet(resp.RespBody)
+}
+
+// renderReport formats the scope summary and findings grouped by severity, then type.
+func renderReport(sessionID string, status *protocol.CrawlStatusResponse, findings []reportFinding) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Crawl Report: %s\n\n", sessionID)
+	fmt.Fprintf(&sb, "Generated: %s\n\n", time.Now().UTC().Format(time.RFC3339))
+
+	sb.WriteString("## Scope\n\n")
+	fmt.Fprintf(&sb, "- State: %s\n", status.State)
+	fmt.Fprintf(&sb, "- Duration: %s\n", status.Duration)
+	fmt.Fprintf(&sb, "- URLs visited: %d\n", status.URLsVisited)
+	fmt.Fprintf(&sb, "- URLs errored: %d\n", status.URLsErrored)
+	fmt.Fprintf(&sb, "- Forms discovered: %d\n", status.FormsDiscovered)
+	if len(status.Hosts) > 0 {
+		fmt.Fprintf(&sb, "- Hosts: %s\n", strings.Join(status.Hosts, ", "))
+	}
+	if len(status.SkippedHosts) > 0 {
+		fmt.Fprintf(&sb, "- Skipped hosts: %s\n", strings.Join(status.SkippedHosts, ", "))
+	}
+	sb.WriteString("\n## Findings\n\n")
+
+	if len(findings) == 0 {
+		sb.WriteString("No findings recorded.\n")
+		return sb.String()
+	}
+
+	bySeverity := make(map[string]map[string][]reportFinding)
+	for _, f := range findings {
+		severity := findingSeverity[f.finding]
+		if severity == "" {
+			severity = "info"
+		}
+		if bySeverity[severity] == nil {
+			bySeverity[severity] = make(map[string][]reportFinding)
+		}
+		bySeverity[severity][f.finding] = append(bySeverity[severity][f.finding], f)
+	}
+
+	sb.WriteString("| Severity | Finding | Flows |\n|---|---|---|\n")
+	for _, severity := range severityOrder {
+		types := bulk.MapKeysSlice(bySeverity[severity])
+		slices.Sort(types)
+		for _, typ := range types {
+			fmt.Fprintf(&sb, "| %s | %s | %d |\n", severity, typ, len(bySeverity[severity][typ]))
+		}
+	}
+
+	for _, severity := range severityOrder {
+		if len(bySeverity[severity]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n### %s\n", strings.ToUpper(severity[:1])+severity[1:])
+		types := bulk.MapKeysSlice(bySeverity[severity])
+		slices.Sort(types)
+		for _, typ := range types {
+			fmt.Fprintf(&sb, "\n#### %s\n\n", typ)
+			for _, f := range bySeverity[severity][typ] {
+				fmt.Fprintf(&sb, "- `%s` %s %s (%d)\n", f.flowID, f.method, f.url, f.status)
+				if f.snippet != "" {
+					fmt.Fprintf(&sb, "  > `%s`\n", strings.ReplaceAll(f.snippet, "`", "'"))
+				}
+			}
+		}
+	}
+	return sb.String()
+}
+
+// reportSnippet collapses whitespace in a response body preview and caps its length.
+func reportSnippet(body string) string {
+	snippet := strings.Join(strings.Fields(body), " ")
+	if runes := []rune(snippet); len(runes) > reportSnippetLen {
+		snippet = string(runes[:reportSnippetLen]) + "..."
+	}
+	return snippet
+}
@@ -4,11 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
 
 	"github.com/go-appsec/toolbox/sectool/cliutil"
+	"github.com/go-appsec/toolbox/sectool/mcpclient"
 )
 
 const (
@@ -16,7 +18,7 @@ const (
 	subcmdErrors = "errors"
 )
 
-var crawlSubcommands = []string{"create", "seed", "status", "summary", "list", "get", subcmdForms, subcmdErrors, "sessions", "stop", "export", "help"}
+var crawlSubcommands = []string{"create", "seed", "status", "stats", "summary", "list", "poll", "get", "extract", subcmdForms, subcmdErrors, "sessions", "stop", "resume", "delete", "export", "export-session", "report", "help"}
 
 func Parse(args []string, mcpURL string) error {
 	if len(args) < 1 {
@@ -31,12 +33,18 @@ func Parse(args []string, mcpURL string) error {
 		return parseSeed(args[1:], mcpURL)
 	case "status":
 		return parseStatus(args[1:], mcpURL)
+	case "stats":
+		return parseStats(args[1:], mcpURL)
 	case "summary":
 		return parseSummary(args[1:], mcpURL)
 	case "list":
 		return parseList(args[1:], mcpURL)
+	case "poll":
+		return parsePoll(args[1:], mcpURL)
 	case "get":
 		return parseGet(args[1:], mcpURL)
+	case "extract":
+		return parseExtract(args[1:], mcpURL)
 	case subcmdForms:
 		return parseForms(args[1:], mcpURL)
 	case subcmdErrors:
@@ -45,8 +53,16 @@ func Parse(args []string, mcpURL string) error {
 		return parseSessions(args[1:], mcpURL)
 	case "stop":
 		return parseStop(args[1:], mcpURL)
+	case "resume":
+		return parseResume(args[1:], mcpURL)
+	case "delete":
+		return parseDelete(args[1:], mcpURL)
 	case "export":
 		return parseExport(args[1:], mcpURL)
+	case "export-session":
+		return parseExportSession(args[1:], mcpURL)
+	case "report":
+		return parseReport(args[1:], mcpURL)
 	case "help", "--help", "-h":
 		printUsage()
 		return nil
@@ -73,10 +89,44 @@ crawl create [options]
     --label <str>          optional unique label for easier reference
     --max-depth <n>        maximum crawl depth (0 = unlimited)
     --max-requests <n>     maximum total requests (0 = unlimited)
+    --max-hosts <n>        maximum distinct hosts; new hosts beyond this are skipped (0 = unlimited)
+    --max-pages-per-host <n>  maximum requests per host, balancing max-requests across hosts (0 = unlimited)
+    --block-threshold <n>  stop after n consecutive identical 403/429/CAPTCHA responses from a host (0 = disabled)
+    --max-in-flight-bytes <n>  maximum response bytes buffered at once across requests (0 = unlimited)
+    --max-body-bytes <n>   response body bytes captured per flow (0 = server config, -1 = unlimited)
     --delay <dur>          delay between requests (default: 200ms)
     --parallelism <n>      concurrent requests (default: 2)
+    --limit <host=delay,parallelism>  rate limit for hosts matching a glob, e.g. 'origin.example.com=2s,1' (can specify multiple times)
+    --deterministic        one request at a time in sorted breadth-first order (reproducible)
+    --strategy <s>         link visit order: bfs (default) or dfs, exhausting each branch first (one request at a time)
     --submit-forms         automatically submit discovered forms
+    --check-form-methods   send each form as GET and POST, flag forms accepting both
+    --follow-links-on-error  record 4xx/5xx pages as flows and follow their links
     --ignore-robots        ignore robots.txt restrictions
+    --extract-json-urls    follow URLs found in JSON response values (API links)
+    --extract-css-urls     fetch stylesheets and follow url()/@import references
+    --extract-comment-urls follow URLs/paths in HTML comments, flag notable comments
+    --extract-js-routes    fetch scripts and record client-side SPA routes (list --type routes)
+    --extract-js-endpoints  fetch scripts, record fetch/axios/XHR URLs and paths, follow same-origin ones (list --type endpoints)
+    --detect-dir-listing   flag directory-listing (index of) pages as findings
+    --merge-trailing-slash  crawl one of /path and /path/ (first found) unless it fails
+    --vary-variants        re-request Vary responses per listed header; flag variants that differ
+    --similarity-threshold <f>  cluster near-identical responses at this similarity, 0-1 (e.g. 0.9)
+    --ignore-query-param <glob>  strip matching query parameters (e.g. utm_*) from links before dedup (can specify multiple times)
+    --allow-re <regex>     only request URL paths matching regex, e.g. '^/users/\d+' (can specify multiple times)
+    --deny-re <regex>      skip URL paths matching regex (can specify multiple times)
+    --content-type <prefix>  record only responses with this Content-Type prefix, replacing the text/JSON/XML/JS defaults (can specify multiple times)
+    --extra-content-type <prefix>  also record responses with this Content-Type prefix, e.g. application/pdf (can specify multiple times)
+    --resolve <host=ip>    pin hostname to IP, Host/SNI unchanged (can specify multiple times)
+    --completion-webhook <url>  POST a JSON summary to url when the crawl completes or stops
+    --doh-resolver <url>   resolve hostnames via this DNS-over-HTTPS endpoint (RFC 8484)
+    --skip-preflight       start even if the first seed is unreachable or behind an auth wall
+    --no-cookie-jar        send seed cookies unchanged; ignore cookies set by responses
+    --token-refresh-url <url>  on 401, fetch a new bearer token here and retry the request
+    --token-refresh-path <path>  JSON path to the token in the response (e.g. access_token)
+    --token-refresh-method <m>  token request method (default: POST with a body, else GET)
+    --token-refresh-body <str>  token request body (JSON, or form-encoded)
+    --token-refresh-header "Name: Value"  token request header (can specify multiple times)
 
   Output: session_id and initial state
 
@@ -94,14 +144,26 @@ crawl seed <session_id> [options]
 
 ---
 
-crawl status <session_id>
+crawl status <session_id> [options]
 
   Get progress metrics for a crawl session.
 
+  Options:
+    --watch                redraw the status on crawl activity until the crawl ends
+    --json                 print the status response as indented JSON
+
   Output: URLs queued, visited, errored, forms discovered
 
 ---
 
+crawl stats <session_id>
+
+  Get status code and content type distributions across all crawled flows.
+
+  Output: Markdown tables with counts per status code and content type
+
+---
+
 crawl summary <session_id> [options]
 
   Get aggregated summary grouped by host/path/method/status.
@@ -115,6 +177,7 @@ crawl summary <session_id> [options]
     --search-body <regex>     regex search in request/response body (RE2)
     --exclude-host <pat>      exclude hosts matching pattern
     --exclude-path <pat>      exclude paths matching pattern
+    --json                    print the summary response as indented JSON
 
   Output: Markdown table with host, path, method, status, count
 
@@ -125,6 +188,7 @@ crawl list <session_id> [options]
   List crawled URLs from a session.
 
   Options:
+    --type <type>             result type: urls (default), forms, errors, external, routes, endpoints, similar, findings
     --host <pattern>          filter by host pattern (glob: *, ?)
     --path <pattern>          filter by path pattern (glob: *, ?)
     --method <list>           filter by HTTP method (comma-separated)
@@ -133,10 +197,43 @@ crawl list <session_id> [options]
     --search-body <regex>     regex search in request/response body (RE2)
     --exclude-host <pat>      exclude hosts matching pattern
     --exclude-path <pat>      exclude paths matching pattern
+    --invert                  return flows that do NOT match the filters
+    --duplicate-of <flow_id>  only flows of the same endpoint (method, path with IDs
+                              normalized, parameter names); for IDOR comparison
     --since <val>             flows after: flow_id, timestamp, or 'last'
+    --cursor <name>           independent 'last' position per consumer (implies --since last)
+    --json                    print the list response as indented JSON
     --limit <n>               maximum result count
     --offset <n>              skip first N results
 
+  Output: Markdown table with flow_id, method, host, path, status, size
+          (--type external: out-of-scope link targets with referring page; not fetched)
+          (--type routes: client-side SPA routes from scripts, needs --extract-js-routes)
+          (--type endpoints: request URLs from scripts, needs --extract-js-endpoints)
+          (--type similar: clusters of near-identical responses, needs --similarity-threshold)
+          (--type findings: passive findings per flow; sensitive data such as leaked keys
+           is listed per match with pattern, body offset and a redacted snippet)
+
+---
+
+crawl poll <session_id> [options]
+
+  Wait for new crawled flows. Returns immediately when flows exist after the
+  cursor, otherwise blocks until one arrives, the crawl ends, or --wait elapses.
+
+  Options:
+    --since <val>             flows after: flow_id, timestamp, or 'last' (default: last)
+    --cursor <name>           independent 'last' position per consumer
+    --wait <dur>              max wait time for new flows (default: 30s, max: 2m)
+    --host <pattern>          filter by host pattern (glob: *, ?)
+    --path <pattern>          filter by path pattern (glob: *, ?)
+    --method <list>           filter by HTTP method (comma-separated)
+    --status <list>           filter by status codes (comma-separated, e.g., 200,4XX)
+    --limit <n>               maximum result count
+
+  Examples:
+    sectool crawl poll abc123 --wait 30s      # next batch of flows, waiting up to 30s
+
   Output: Markdown table with flow_id, method, host, path, status, size
 
 ---
@@ -155,6 +252,20 @@ crawl get <flow_id> [options]
 
 ---
 
+crawl extract <flow_id> [options]
+
+  Extract links and endpoints from one flow's response without crawling.
+  Works on proxy, replay and crawl flows. HTML, JavaScript, JSON and CSS
+  responses are supported; relative links are resolved against the flow URL.
+
+  Options:
+    --source <list>           only these sources (comma-separated): anchor,
+                              form, script, json, css, comment
+
+  Output: Markdown table with url, source
+
+---
+
 crawl forms <session_id> [options]
 
   List forms discovered during crawling.
@@ -183,6 +294,7 @@ crawl sessions [options]
 
   Options:
     --limit <n>            maximum sessions to return
+    --json                 print the sessions response as indented JSON
 
   Output: Markdown table with session_id, label, state, created_at
 
@@ -196,33 +308,149 @@ crawl stop <session_id>
 
 ---
 
+crawl resume <session_id>
+
+  Resume a stopped or completed crawl session with its original options.
+  Discovered URLs never requested (including those abandoned by a stop) are
+  queued again; captured flows and forms are kept.
+
+  Output: Confirmation message with the number of URLs queued
+
+---
+
+crawl delete <session_id> [filter options]
+crawl delete --flow <flow_id>
+crawl delete <session_id> [--force]
+
+  Permanently delete crawled flows, e.g. tracking pixels and static assets,
+  before export or reporting. Deleted URLs are not crawled again. Without
+  filters, deletes the whole session with its flows and forms to free memory.
+
+  Options:
+    --flow <flow_id>          delete a single flow (no session_id needed)
+    --mime <pattern>          response media type glob (image/*, text/css)
+    --host <pattern>          host glob pattern (*, ?)
+    --path <pattern>          path glob pattern (*, ?)
+    --method <list>           comma-separated methods
+    --status <list>           status codes or ranges (404,3XX)
+    --search-header <regex>   regex search in request/response headers (RE2)
+    --search-body <regex>     regex search in request/response body (RE2)
+    --exclude-host <pat>      keep hosts matching pattern
+    --exclude-path <pat>      keep paths matching pattern
+    --duplicate-of <flow_id>  flows of the same endpoint as flow_id
+    --invert                  delete flows that do NOT match the filters
+    --force                   stop a running session before deleting it
+
+  Examples:
+    sectool crawl delete <session_id> --mime "image/*"
+    sectool crawl delete <session_id> --mime text/html --invert   # keep only HTML
+    sectool crawl delete --flow f7k2x
+    sectool crawl delete <session_id> --force                     # whole session
+
+  Output: Number of flows deleted
+
+---
+
 crawl export <flow_id>
+crawl export <session_id> --since <flow_id|last>
+
+  Export a crawled flow to an editable bundle on disk. With --since or --cursor,
+  export every session flow after that position (same semantics as crawl list)
+  and record them in sectool-requests/index.json.
+
+  Options:
+    --since <val>           export session flows after: flow_id or 'last'
+    --cursor <name>         independent 'last' position per consumer (implies --since last)
+    --redact                mask Authorization, Proxy-Authorization, Cookie, Set-Cookie
+    --redact-header <name>  additional header to mask (repeatable, implies --redact)
+
+  Output: Bundle path and list of created files (--since: exported flows and index path)
+
+---
+
+crawl export-session <session_id> [options]
+
+  Export the metadata of every flow in a session for bulk triage, without raw
+  request or response bytes.
+
+  Options:
+    --format <fmt>          ndjson (default, one flow object per line) or csv
+                            (flow_id, method, host, path, status, length,
+                            content_type, discovered_at)
+    --out <path>            write the export to path (default: stdout)
+
+  Output: NDJSON or CSV (--out: export path)
+
+---
+
+crawl report <session_id> [options]
 
-  Export a crawled flow to an editable bundle on disk.
+  Render the session's findings as a Markdown report: a crawl scope summary,
+  then findings grouped by severity and type with flow_id, URL, and a
+  response snippet for each affected flow.
 
-  Output: Bundle path and list of created files
+  Options:
+    --out <path>            write the report to path (default: stdout)
+
+  Output: Markdown report (--out: report path and finding count)
 `)
 }
 
 func parseCreate(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("crawl create", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
+	var opts mcpclient.CrawlCreateOpts
 	var delay time.Duration
-	var urls, flows, domains []string
-	var label string
-	var maxDepth, maxRequests, parallelism int
-	var submitForms, ignoreRobots bool
+	var urls, flows, domains, ignoreQueryParams, contentTypes, extraContentTypes []string
+	var tokenURL, tokenPath, tokenMethod, tokenBody string
+	var tokenHeaders []string
 
 	fs.StringArrayVar(&urls, "url", nil, "seed URL (can specify multiple times)")
 	fs.StringArrayVar(&flows, "flow", nil, "seed from proxy flow_id (can specify multiple times)")
 	fs.StringArrayVar(&domains, "domain", nil, "additional allowed domain (can specify multiple times)")
-	fs.StringVar(&label, "label", "", "optional unique label for easier reference")
-	fs.IntVar(&maxDepth, "max-depth", 0, "maximum crawl depth (0 = unlimited)")
-	fs.IntVar(&maxRequests, "max-requests", 0, "maximum total requests (0 = unlimited)")
+	fs.StringVar(&opts.Label, "label", "", "optional unique label for easier reference")
+	fs.IntVar(&opts.MaxDepth, "max-depth", 0, "maximum crawl depth (0 = unlimited)")
+	fs.IntVar(&opts.MaxRequests, "max-requests", 0, "maximum total requests (0 = unlimited)")
+	fs.IntVar(&opts.MaxHosts, "max-hosts", 0, "maximum distinct hosts to request (0 = unlimited)")
+	fs.IntVar(&opts.MaxPagesPerHost, "max-pages-per-host", 0, "maximum requests per host (0 = unlimited)")
+	fs.IntVar(&opts.BlockThreshold, "block-threshold", 0, "stop after N consecutive identical 403/429/CAPTCHA responses from a host (0 = disabled)")
+	fs.Int64Var(&opts.MaxInFlightBytes, "max-in-flight-bytes", 0, "maximum response bytes buffered at once (0 = unlimited)")
+	fs.IntVar(&opts.MaxBodyBytes, "max-body-bytes", 0, "response body bytes captured per flow (0 = server config, negative = unlimited)")
 	fs.DurationVar(&delay, "delay", 0, "delay between requests")
-	fs.IntVar(&parallelism, "parallelism", 0, "concurrent requests")
-	fs.BoolVar(&submitForms, "submit-forms", false, "automatically submit discovered forms")
-	fs.BoolVar(&ignoreRobots, "ignore-robots", false, "ignore robots.txt restrictions")
+	fs.IntVar(&opts.Parallelism, "parallelism", 0, "concurrent requests")
+	fs.StringArrayVar(&opts.HostLimits, "limit", nil, "host_glob=delay[,parallelism[,random_delay]] rate limit for matching hosts (can specify multiple times)")
+	fs.BoolVar(&opts.Deterministic, "deterministic", false, "one request at a time in sorted breadth-first order for reproducible crawls")
+	fs.StringVar(&opts.Strategy, "strategy", "", "link visit order: bfs (default) or dfs to exhaust each branch first; dfs makes one request at a time")
+	fs.BoolVar(&opts.SubmitForms, "submit-forms", false, "automatically submit discovered forms")
+	fs.BoolVar(&opts.CheckFormMethods, "check-form-methods", false, "send each form as both GET and POST and compare responses")
+	fs.BoolVar(&opts.FollowLinksOnError, "follow-links-on-error", false, "record 4xx/5xx pages as flows and follow their links")
+	fs.BoolVar(&opts.IgnoreRobots, "ignore-robots", false, "ignore robots.txt restrictions")
+	fs.BoolVar(&opts.ExtractJSONURLs, "extract-json-urls", false, "follow URLs found in JSON response values")
+	fs.BoolVar(&opts.ExtractCSSURLs, "extract-css-urls", false, "fetch stylesheets and follow url()/@import references")
+	fs.BoolVar(&opts.ExtractCommentURLs, "extract-comment-urls", false, "follow URLs/paths in HTML comments, flag notable comments")
+	fs.BoolVar(&opts.ExtractJSRoutes, "extract-js-routes", false, "fetch scripts and record client-side SPA routes (crawl list --type routes)")
+	fs.BoolVar(&opts.ExtractJSEndpoints, "extract-js-endpoints", false, "fetch scripts, record fetch/axios/XHR URLs and quoted paths, follow same-origin ones (crawl list --type endpoints)")
+	fs.Float64Var(&opts.SimilarityThreshold, "similarity-threshold", 0, "cluster near-identical responses at this SimHash similarity, 0-1 (crawl list --type similar; 0 = disabled)")
+	fs.BoolVar(&opts.DetectDirListing, "detect-dir-listing", false, "flag directory-listing (index of) pages as findings")
+	fs.BoolVar(&opts.MergeTrailingSlash, "merge-trailing-slash", false, "crawl only the first-discovered of /path and /path/ unless it fails")
+	fs.BoolVar(&opts.VaryVariants, "vary-variants", false, "re-request responses with a Vary header, varying each listed header, and flag differing variants")
+	fs.BoolVar(&opts.AllowDestructive, "allow-destructive", false, "disable safe mode: allow DELETE/PUT/PATCH and destructive form submissions")
+	fs.BoolVar(&opts.SkipPreflight, "skip-preflight", false, "start even if the first seed is unreachable or behind an auth wall")
+	fs.BoolVar(&opts.NoCookieJar, "no-cookie-jar", false, "send seed cookies unchanged on every request, ignoring cookies set by responses")
+	fs.StringArrayVar(&opts.AllowPathRegex, "allow-re", nil, "regex URL paths must match to be requested (can specify multiple times)")
+	fs.StringArrayVar(&opts.DenyPathRegex, "deny-re", nil, "regex for URL paths not to request (can specify multiple times)")
+	fs.StringArrayVar(&contentTypes, "content-type", nil, "Content-Type prefix of responses to record, replacing the defaults (can specify multiple times)")
+	fs.StringArrayVar(&extraContentTypes, "extra-content-type", nil, "additional Content-Type prefix of responses to record (can specify multiple times)")
+	fs.StringArrayVar(&ignoreQueryParams, "ignore-query-param", nil, "query parameter name glob to strip from discovered links, e.g. utm_* (can specify multiple times)")
+	fs.StringArrayVar(&opts.Resolve, "resolve", nil, "pin hostname to IP, like curl --resolve (can specify multiple times)")
+	fs.StringVar(&opts.CompletionWebhook, "completion-webhook", "", "URL to POST a JSON summary to when the crawl completes or stops")
+	fs.StringVar(&opts.Referer, "referer", "", "Referer for followed links: 'none' to omit, or a fixed value (default: parent page per its referrer policy)")
+	fs.StringVar(&opts.DoHResolver, "doh-resolver", "", "DNS-over-HTTPS resolver URL for crawl name resolution")
+	fs.StringVar(&tokenURL, "token-refresh-url", "", "token endpoint called on 401; the new token is sent as a bearer Authorization header")
+	fs.StringVar(&tokenPath, "token-refresh-path", "", "dot-notation JSON path to the token in the token response (e.g. access_token)")
+	fs.StringVar(&tokenMethod, "token-refresh-method", "", "token request method (default: POST with a body, else GET)")
+	fs.StringVar(&tokenBody, "token-refresh-body", "", "token request body, sent as JSON when valid JSON, else form-encoded")
+	fs.StringArrayVar(&tokenHeaders, "token-refresh-header", nil, "token request header 'Name: Value' (can specify multiple times)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl create [options]
@@ -241,7 +469,37 @@ Options:
 		return errors.New("at least one --url or --flow is required")
 	}
 
-	return create(mcpURL, urls, flows, domains, label, maxDepth, maxRequests, delay, parallelism, submitForms, ignoreRobots)
+	if tokenURL != "" || tokenPath != "" {
+		if tokenURL == "" || tokenPath == "" {
+			return errors.New("--token-refresh-url and --token-refresh-path must be used together")
+		}
+		tokenRefresh := &mcpclient.CrawlTokenRefresh{URL: tokenURL, Method: tokenMethod, Body: tokenBody, TokenPath: tokenPath}
+		for _, h := range tokenHeaders {
+			name, value, ok := strings.Cut(h, ":")
+			if !ok {
+				return fmt.Errorf("invalid --token-refresh-header %q: expected 'Name: Value'", h)
+			}
+			if tokenRefresh.Headers == nil {
+				tokenRefresh.Headers = make(map[string]string)
+			}
+			tokenRefresh.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+		opts.TokenRefresh = tokenRefresh
+	} else if tokenMethod != "" || tokenBody != "" || len(tokenHeaders) > 0 {
+		return errors.New("--token-refresh-method, --token-refresh-body and --token-refresh-header require --token-refresh-url")
+	}
+
+	opts.SeedURLs = strings.Join(urls, ",")
+	opts.SeedFlows = strings.Join(flows, ",")
+	opts.Domains = strings.Join(domains, ",")
+	opts.IgnoreQueryParams = strings.Join(ignoreQueryParams, ",")
+	opts.AllowedContentTypes = strings.Join(contentTypes, ",")
+	opts.ExtraContentTypes = strings.Join(extraContentTypes, ",")
+	if delay > 0 {
+		opts.Delay = delay.String()
+	}
+
+	return create(mcpURL, opts)
 }
 
 func parseSeed(args []string, mcpURL string) error {
@@ -278,6 +536,9 @@ Options:
 func parseStatus(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("crawl status", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
+	var watch, jsonOut bool
+	fs.BoolVar(&watch, "watch", false, "redraw the status as the crawl progresses until it ends")
+	fs.BoolVar(&jsonOut, "json", false, "print the response as JSON instead of tables")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl status <session_id> [options]
@@ -296,13 +557,38 @@ Options:
 		return errors.New("session_id required")
 	}
 
-	return status(mcpURL, fs.Args()[0])
+	return status(mcpURL, fs.Args()[0], watch, jsonOut)
+}
+
+func parseStats(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl stats", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl stats <session_id> [options]
+
+Get status code and content type distributions for a crawl session.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) < 1 {
+		fs.Usage()
+		return errors.New("session_id required")
+	}
+
+	return stats(mcpURL, fs.Args()[0])
 }
 
 func parseSummary(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("crawl summary", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
 	var host, path, method, status, searchHeader, searchBody, excludeHost, excludePath string
+	var jsonOut bool
 
 	fs.StringVar(&host, "host", "", "filter by host pattern (glob: *, ?)")
 	fs.StringVar(&path, "path", "", "filter by path pattern (glob: *, ?)")
@@ -312,6 +598,7 @@ func parseSummary(args []string, mcpURL string) error {
 	fs.StringVar(&searchBody, "search-body", "", "regex search in request/response body (RE2)")
 	fs.StringVar(&excludeHost, "exclude-host", "", "exclude hosts matching pattern")
 	fs.StringVar(&excludePath, "exclude-path", "", "exclude paths matching pattern")
+	fs.BoolVar(&jsonOut, "json", false, "print the response as JSON instead of tables")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl summary <session_id> [options]
@@ -330,31 +617,103 @@ Options:
 		return errors.New("session_id required")
 	}
 
-	return summary(mcpURL, fs.Args()[0], host, path, method, status, searchHeader, searchBody, excludeHost, excludePath)
+	return summary(mcpURL, fs.Args()[0], host, path, method, status, searchHeader, searchBody, excludeHost, excludePath, jsonOut)
 }
 
 func parseList(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("crawl list", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
-	var host, path, method, status, searchHeader, searchBody, excludeHost, excludePath, since string
-	var limit, offset int
+	var opts mcpclient.CrawlPollOpts
+	var listType string
+	var jsonOut bool
+
+	fs.StringVar(&listType, "type", "urls", "result type: urls, forms, errors, external, routes, endpoints, similar, findings")
+	fs.StringVar(&opts.Host, "host", "", "filter by host pattern (glob: *, ?)")
+	fs.StringVar(&opts.Path, "path", "", "filter by path pattern (glob: *, ?)")
+	fs.StringVar(&opts.Method, "method", "", "filter by HTTP method (comma-separated)")
+	fs.StringVar(&opts.Status, "status", "", "filter by status codes (e.g., 200,4XX)")
+	fs.StringVar(&opts.SearchHeader, "search-header", "", "regex search in request/response headers (RE2)")
+	fs.StringVar(&opts.SearchBody, "search-body", "", "regex search in request/response body (RE2)")
+	fs.StringVar(&opts.ExcludeHost, "exclude-host", "", "exclude hosts matching pattern")
+	fs.StringVar(&opts.ExcludePath, "exclude-path", "", "exclude paths matching pattern")
+	fs.BoolVar(&opts.Invert, "invert", false, "return flows that do NOT match the filters")
+	fs.StringVar(&opts.DuplicateOf, "duplicate-of", "", "only flows of the same endpoint as this flow_id (method, ID-normalized path, param names)")
+	fs.StringVar(&opts.Since, "since", "", "flows after flow_id or timestamp")
+	fs.StringVar(&opts.Cursor, "cursor", "", "named cursor: only flows not yet returned to this cursor")
+	fs.IntVar(&opts.Limit, "limit", 0, "maximum result count")
+	fs.IntVar(&opts.Offset, "offset", 0, "skip first N results")
+	fs.BoolVar(&jsonOut, "json", false, "print the response as JSON instead of tables")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl list <session_id> [options]
+
+List crawled URLs from a session.
+
+Use --invert to list flows that do NOT match the filters, e.g.
+  sectool crawl list <session_id> --status 2XX --invert
+
+Use --duplicate-of to gather every instance of a flow's endpoint, e.g. all
+/api/users/<id> requests with the same parameter names, for access-control comparison:
+  sectool crawl list <session_id> --duplicate-of f7k2x
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) < 1 {
+		fs.Usage()
+		return errors.New("session_id required")
+	}
+
+	switch listType {
+	case "urls", subcmdForms, subcmdErrors, "external", "routes", "endpoints", "similar", "findings":
+	default:
+		return fmt.Errorf("invalid --type %q: use urls, forms, errors, external, routes, endpoints, similar, or findings", listType)
+	}
+
+	if opts.DuplicateOf != "" && listType != "urls" {
+		return errors.New("--duplicate-of only applies to --type urls")
+	}
+
+	// Auto-set large limit if no filters provided (MCP refuses list with no limits or filters)
+	if opts.Limit == 0 && opts.Host == "" && opts.Path == "" && opts.Method == "" && opts.Status == "" &&
+		opts.SearchHeader == "" && opts.SearchBody == "" && opts.ExcludeHost == "" && opts.ExcludePath == "" &&
+		opts.DuplicateOf == "" && opts.Since == "" && opts.Cursor == "" {
+		opts.Limit = 1_000_000_000
+	}
+
+	// The urls type lists flows; the other types share their output_mode name
+	opts.OutputMode = listType
+	if listType == "urls" {
+		opts.OutputMode = "flows"
+	}
+
+	return list(mcpURL, fs.Args()[0], opts, jsonOut)
+}
+
+func parsePoll(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl poll", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var host, path, method, status, since, cursor string
+	var wait time.Duration
+	var limit int
 
+	fs.StringVar(&since, "since", "last", "flows after flow_id, timestamp, or 'last'")
+	fs.StringVar(&cursor, "cursor", "", "named cursor: only flows not yet returned to this cursor")
+	fs.DurationVar(&wait, "wait", 30*time.Second, "max wait time for new flows (max 120s)")
 	fs.StringVar(&host, "host", "", "filter by host pattern (glob: *, ?)")
 	fs.StringVar(&path, "path", "", "filter by path pattern (glob: *, ?)")
 	fs.StringVar(&method, "method", "", "filter by HTTP method (comma-separated)")
 	fs.StringVar(&status, "status", "", "filter by status codes (e.g., 200,4XX)")
-	fs.StringVar(&searchHeader, "search-header", "", "regex search in request/response headers (RE2)")
-	fs.StringVar(&searchBody, "search-body", "", "regex search in request/response body (RE2)")
-	fs.StringVar(&excludeHost, "exclude-host", "", "exclude hosts matching pattern")
-	fs.StringVar(&excludePath, "exclude-path", "", "exclude paths matching pattern")
-	fs.StringVar(&since, "since", "", "flows after flow_id or timestamp")
 	fs.IntVar(&limit, "limit", 0, "maximum result count")
-	fs.IntVar(&offset, "offset", 0, "skip first N results")
 
 	fs.Usage = func() {
-		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl list <session_id> [options]
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl poll <session_id> [options]
 
-List crawled URLs from a session.
+Wait for new crawled flows, returning as soon as any are available.
 
 Options:
 `)
@@ -368,12 +727,7 @@ Options:
 		return errors.New("session_id required")
 	}
 
-	// Auto-set large limit if no filters provided (MCP refuses list with no limits or filters)
-	if limit == 0 && host == "" && path == "" && method == "" && status == "" && searchHeader == "" && searchBody == "" && excludeHost == "" && excludePath == "" && since == "" {
-		limit = 1_000_000_000
-	}
-
-	return list(mcpURL, fs.Args()[0], "urls", host, path, method, status, searchHeader, searchBody, excludeHost, excludePath, since, limit, offset)
+	return poll(mcpURL, fs.Args()[0], host, path, method, status, since, cursor, wait, limit)
 }
 
 func parseGet(args []string, mcpURL string) error {
@@ -404,6 +758,33 @@ Options:
 	return get(mcpURL, fs.Args()[0], scope, pattern)
 }
 
+func parseExtract(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl extract", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var source string
+
+	fs.StringVar(&source, "source", "", "only these sources (comma-separated): anchor, form, script, json, css, comment")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl extract <flow_id> [options]
+
+Extract links and endpoints from a single flow's response without crawling.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) < 1 {
+		fs.Usage()
+		return errors.New("flow_id required (get from 'sectool crawl list' or 'sectool proxy list')")
+	}
+
+	return extract(mcpURL, fs.Args()[0], source)
+}
+
 func parseForms(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("crawl forms", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
@@ -428,7 +809,7 @@ Options:
 		return errors.New("session_id required")
 	}
 
-	return list(mcpURL, fs.Args()[0], "forms", "", "", "", "", "", "", "", "", "", limit, 0)
+	return list(mcpURL, fs.Args()[0], mcpclient.CrawlPollOpts{OutputMode: subcmdForms, Limit: limit}, false)
 }
 
 func parseErrors(args []string, mcpURL string) error {
@@ -455,15 +836,17 @@ Options:
 		return errors.New("session_id required")
 	}
 
-	return list(mcpURL, fs.Args()[0], "errors", "", "", "", "", "", "", "", "", "", limit, 0)
+	return list(mcpURL, fs.Args()[0], mcpclient.CrawlPollOpts{OutputMode: subcmdErrors, Limit: limit}, false)
 }
 
 func parseSessions(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("crawl sessions", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
 	var limit int
+	var jsonOut bool
 
 	fs.IntVar(&limit, "limit", 0, "maximum sessions to return")
+	fs.BoolVar(&jsonOut, "json", false, "print the response as JSON instead of tables")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl sessions [options]
@@ -479,7 +862,7 @@ Options:
 		return err
 	}
 
-	return sessions(mcpURL, limit)
+	return sessions(mcpURL, limit, jsonOut)
 }
 
 func parseStop(args []string, mcpURL string) error {
@@ -506,14 +889,107 @@ Options:
 	return stop(mcpURL, fs.Args()[0])
 }
 
+func parseResume(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl resume", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl resume <session_id> [options]
+
+Resume a stopped or completed crawl session.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) < 1 {
+		fs.Usage()
+		return errors.New("session_id required")
+	}
+
+	return resume(mcpURL, fs.Args()[0])
+}
+
+func parseDelete(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl delete", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var opts mcpclient.CrawlDeleteOpts
+
+	fs.StringVar(&opts.FlowID, "flow", "", "delete a single flow by flow_id")
+	fs.StringVar(&opts.ContentType, "mime", "", "filter by response media type (glob, e.g. image/*)")
+	fs.StringVar(&opts.Host, "host", "", "filter by host pattern (glob: *, ?)")
+	fs.StringVar(&opts.Path, "path", "", "filter by path pattern (glob: *, ?)")
+	fs.StringVar(&opts.Method, "method", "", "filter by HTTP method (comma-separated)")
+	fs.StringVar(&opts.Status, "status", "", "filter by status codes (e.g., 404,3XX)")
+	fs.StringVar(&opts.SearchHeader, "search-header", "", "regex search in request/response headers (RE2)")
+	fs.StringVar(&opts.SearchBody, "search-body", "", "regex search in request/response body (RE2)")
+	fs.StringVar(&opts.ExcludeHost, "exclude-host", "", "keep hosts matching pattern")
+	fs.StringVar(&opts.ExcludePath, "exclude-path", "", "keep paths matching pattern")
+	fs.StringVar(&opts.DuplicateOf, "duplicate-of", "", "flows of the same endpoint as this flow_id")
+	fs.BoolVar(&opts.Invert, "invert", false, "delete flows that do NOT match the filters")
+	fs.BoolVar(&opts.Force, "force", false, "stop a running session before deleting it (no filters)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl delete <session_id> [filter options]
+       sectool crawl delete --flow <flow_id>
+       sectool crawl delete <session_id> [--force]
+
+Permanently delete crawled flows matching the filters, e.g. static assets:
+  sectool crawl delete <session_id> --mime "image/*"
+
+Preview with 'crawl list' using the same filters. Without filters, the whole
+session is deleted with its flows and forms to free memory; a running session
+is refused unless --force stops it first.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var sessionID string
+	if len(fs.Args()) > 0 {
+		sessionID = fs.Args()[0]
+	} else if opts.FlowID == "" {
+		fs.Usage()
+		return errors.New("session_id or --flow required")
+	}
+	if opts.FlowID == "" {
+		opts.Session = true
+		fs.Visit(func(f *pflag.Flag) {
+			if f.Name != "force" {
+				opts.Session = false
+			}
+		})
+	}
+
+	return deleteFlows(mcpURL, sessionID, opts)
+}
+
 func parseExport(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("crawl export", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
+	var redact bool
+	var redactHeaders []string
+	var since, cursor string
+
+	fs.StringVar(&since, "since", "", "export session flows after flow_id or 'last' (positional argument is a session_id)")
+	fs.StringVar(&cursor, "cursor", "", "named cursor: only flows not yet returned to this cursor")
+	fs.BoolVar(&redact, "redact", false, "mask Authorization, Proxy-Authorization, Cookie, and Set-Cookie header values")
+	fs.StringArrayVar(&redactHeaders, "redact-header", nil, "additional header to mask (can specify multiple times, implies --redact)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl export <flow_id> [options]
+       sectool crawl export <session_id> --since <flow_id|last> [options]
 
-Export a crawled flow to an editable bundle on disk.
+Export a crawled flow to an editable bundle on disk, or with --since/--cursor
+every session flow after that position, updating sectool-requests/index.json.
 
 Options:
 `)
@@ -527,5 +1003,65 @@ Options:
 		return errors.New("flow_id required (get from 'sectool crawl list')")
 	}
 
-	return export(mcpURL, fs.Args()[0])
+	if since != "" || cursor != "" {
+		return exportSince(mcpURL, fs.Args()[0], since, cursor, redact || len(redactHeaders) > 0, redactHeaders)
+	}
+	return export(mcpURL, fs.Args()[0], redact || len(redactHeaders) > 0, redactHeaders)
+}
+
+func parseExportSession(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl export-session", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var format, out string
+
+	fs.StringVar(&format, "format", "ndjson", "export format: ndjson or csv")
+	fs.StringVar(&out, "out", "", "write the export to path instead of stdout")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl export-session <session_id> [options]
+
+Export the metadata of every flow in a session as NDJSON or CSV.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) < 1 {
+		fs.Usage()
+		return errors.New("session_id required")
+	} else if format != "ndjson" && format != "csv" {
+		return fmt.Errorf("invalid --format %q: use ndjson or csv", format)
+	}
+
+	return exportSession(mcpURL, fs.Args()[0], format, out)
+}
+
+func parseReport(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("crawl report", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var out string
+
+	fs.StringVar(&out, "out", "", "write the Markdown report to path instead of stdout")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool crawl report <session_id> [options]
+
+Render crawl findings as a Markdown report, grouped by severity and type.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) < 1 {
+		fs.Usage()
+		return errors.New("session_id required")
+	}
+
+	return report(mcpURL, fs.Args()[0], out)
 }
@@ -3,15 +3,31 @@ package diff
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
+	"github.com/jedib0t/go-pretty/v6/table"
+
 	"github.com/go-appsec/toolbox/sectool/cliutil"
 	"github.com/go-appsec/toolbox/sectool/mcpclient"
 	"github.com/go-appsec/toolbox/sectool/protocol"
+	"github.com/go-appsec/toolbox/sectool/util"
 	"github.com/pmezard/go-difflib/difflib"
 )
 
-func run(mcpURL, flowA, flowB, scope string, maxDiffLines int) error {
+// maxMultiCellLen caps each value shown in the diff multi table.
+const maxMultiCellLen = 40
+
+// Scopes lists the scope values accepted by diff_flow.
+var Scopes = []string{
+	"request", "response",
+	"request_headers", "response_headers",
+	"request_body", "response_body",
+	"request_raw", "response_raw",
+}
+
+func run(mcpURL, flowA, flowB, scope string, maxDiffLines int, ignoreJSONPaths []string) error {
 	ctx := context.Background()
 
 	client, err := mcpclient.Connect(ctx, mcpURL)
@@ -21,10 +37,11 @@ func run(mcpURL, flowA, flowB, scope string, maxDiffLines int) error {
 	defer func() { _ = client.Close() }()
 
 	resp, err := client.DiffFlow(ctx, mcpclient.DiffFlowOpts{
-		FlowA:        flowA,
-		FlowB:        flowB,
-		Scope:        scope,
-		MaxDiffLines: maxDiffLines,
+		FlowA:           flowA,
+		FlowB:           flowB,
+		Scope:           scope,
+		MaxDiffLines:    maxDiffLines,
+		IgnoreJSONPaths: ignoreJSONPaths,
 	})
 	if err != nil {
 		return fmt.Errorf("diff failed: %w", err)
@@ -33,9 +50,15 @@ func run(mcpURL, flowA, flowB, scope string, maxDiffLines int) error {
 	fmt.Printf("%s\n\n", cliutil.Bold("Diff Result"))
 	fmt.Printf("Comparing %s vs %s (scope: %s)\n\n", cliutil.ID(flowA), cliutil.ID(flowB), scope)
 
+	PrintResult(resp)
+	return nil
+}
+
+// PrintResult renders the sections of a diff_flow response that differ.
+func PrintResult(resp *protocol.DiffFlowResponse) {
 	if resp.Same {
 		fmt.Println("Flows are identical (within the selected scope).")
-		return nil
+		return
 	}
 
 	if resp.Request != nil {
@@ -44,8 +67,6 @@ func run(mcpURL, flowA, flowB, scope string, maxDiffLines int) error {
 	if resp.Response != nil {
 		printResponseDiff(resp.Response)
 	}
-
-	return nil
 }
 
 func printRequestDiff(d *protocol.RequestDiff) {
@@ -64,7 +85,10 @@ func printRequestDiff(d *protocol.RequestDiff) {
 		printParamsDiff("Headers", d.Headers)
 	}
 	if d.Body != nil {
-		printBodyDiff(d.Body)
+		printBodyDiff("Body", d.Body)
+	}
+	if d.Raw != nil {
+		printBodyDiff("Raw", d.Raw)
 	}
 
 	fmt.Println()
@@ -80,7 +104,10 @@ func printResponseDiff(d *protocol.ResponseDiff) {
 		printParamsDiff("Headers", d.Headers)
 	}
 	if d.Body != nil {
-		printBodyDiff(d.Body)
+		printBodyDiff("Body", d.Body)
+	}
+	if d.Raw != nil {
+		printBodyDiff("Raw", d.Raw)
 	}
 
 	fmt.Println()
@@ -106,10 +133,10 @@ func printParamsDiff(label string, d *protocol.ParamsDiff) {
 	}
 }
 
-func printBodyDiff(d *protocol.BodyDiff) {
+func printBodyDiff(label string, d *protocol.BodyDiff) {
 	switch d.Format {
 	case "json":
-		fmt.Printf("\n  %s\n", cliutil.Bold("Body (json)"))
+		fmt.Printf("\n  %s\n", cliutil.Bold(label+" (json)"))
 
 		for _, a := range d.Added {
 			fmt.Printf("    %s %s: %v\n", cliutil.Success("+"), a.Path, a.Value)
@@ -135,7 +162,7 @@ func printBodyDiff(d *protocol.BodyDiff) {
 		if d.ASize > 0 || d.BSize > 0 {
 			sizeInfo = fmt.Sprintf(", %d → %d bytes", d.ASize, d.BSize)
 		}
-		fmt.Printf("\n  %s\n", cliutil.Bold(fmt.Sprintf("Body (text%s)", sizeInfo)))
+		fmt.Printf("\n  %s\n", cliutil.Bold(fmt.Sprintf("%s (text%s)", label, sizeInfo)))
 
 		if d.Summary != "" {
 			fmt.Printf("    %s\n", d.Summary)
@@ -155,8 +182,74 @@ func printBodyDiff(d *protocol.BodyDiff) {
 		if d.ASize > 0 || d.BSize > 0 {
 			sizeInfo = fmt.Sprintf(", %d → %d bytes", d.ASize, d.BSize)
 		}
-		fmt.Printf("\n  %s\n", cliutil.Bold(fmt.Sprintf("Body (binary%s)", sizeInfo)))
+		fmt.Printf("\n  %s\n", cliutil.Bold(fmt.Sprintf("%s (binary%s)", label, sizeInfo)))
+	}
+}
+
+func runMulti(mcpURL string, flowIDs []string, scope string, maxFields int, ignoreJSONPaths []string) error {
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.DiffMulti(ctx, mcpclient.DiffMultiOpts{
+		FlowIDs:         flowIDs,
+		Scope:           scope,
+		MaxFields:       maxFields,
+		IgnoreJSONPaths: ignoreJSONPaths,
+	})
+	if err != nil {
+		return fmt.Errorf("diff failed: %w", err)
+	}
+
+	fmt.Printf("%s\n\n", cliutil.Bold("Diff Result"))
+	fmt.Printf("Comparing %d flows (scope: %s)\n\n", len(flowIDs), scope)
+
+	PrintMultiResult(os.Stdout, resp)
+	return nil
+}
+
+// PrintMultiResult renders a diff_multi response as a table with one column per flow.
+func PrintMultiResult(w io.Writer, resp *protocol.DiffMultiResponse) {
+	if resp.Same {
+		_, _ = fmt.Fprintln(w, "Flows are identical (within the selected scope).")
+		return
+	}
+
+	t := cliutil.NewTable(w)
+	header := table.Row{"Field"}
+	for _, id := range resp.FlowIDs {
+		header = append(header, id)
+	}
+	t.AppendHeader(header)
+	for _, f := range resp.Fields {
+		row := table.Row{f.Field}
+		for i, v := range f.Values {
+			row = append(row, multiCell(v, f.Values[0], i == 0))
+		}
+		t.AppendRow(row)
+	}
+	t.Render()
+
+	_, _ = fmt.Fprintf(w, "%d fields differ, %d unchanged\n", len(resp.Fields), resp.UnchangedCount)
+	if resp.Truncated {
+		_, _ = fmt.Fprintln(w, cliutil.Muted("(more differing fields omitted, raise --max-fields)"))
+	}
+}
+
+// multiCell formats one value, highlighting it when it differs from the first flow's value.
+func multiCell(v, base *string, first bool) string {
+	s := cliutil.Muted("(absent)")
+	if v != nil {
+		s = util.TruncateString(*v, maxMultiCellLen)
+	}
+	if first || (v == nil && base == nil) || (v != nil && base != nil && *v == *base) {
+		return s
 	}
+	return cliutil.Warning(s)
 }
 
 // colorDiffLine applies color to unified diff lines
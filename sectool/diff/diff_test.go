@@ -1,6 +1,7 @@
 package diff
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -142,3 +143,34 @@ func TestInlineHighlight_with_colors(t *testing.T) {
 	assert.Contains(t, gotA, "abc")
 	assert.Contains(t, gotB, "xyz")
 }
+
+func TestMultiCell(t *testing.T) {
+	// No t.Parallel(): mutates global cliutil.Output.ColorMode
+	orig := cliutil.Output.ColorMode
+	cliutil.Output.ColorMode = cliutil.ColorAlways
+	t.Cleanup(func() { cliutil.Output.ColorMode = orig })
+
+	strPtr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name  string
+		v     *string
+		base  *string
+		first bool
+		want  string
+	}{
+		{"first_column_plain", strPtr("200"), strPtr("200"), true, "200"},
+		{"same_as_first", strPtr("200"), strPtr("200"), false, "200"},
+		{"differs_from_first", strPtr("401"), strPtr("200"), false, cliutil.Warning("401")},
+		{"absent", nil, strPtr("200"), false, cliutil.Warning(cliutil.Muted("(absent)"))},
+		{"absent_in_both", nil, nil, false, cliutil.Muted("(absent)")},
+		{"present_when_first_absent", strPtr("x"), nil, false, cliutil.Warning("x")},
+		{"truncated", strPtr(strings.Repeat("a", 50)), nil, true, strings.Repeat("a", 38) + ".."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, multiCell(tt.v, tt.base, tt.first))
+		})
+	}
+}
@@ -10,19 +10,26 @@ import (
 
 // Parse handles the "sectool diff" command.
 func Parse(args []string, mcpURL string) error {
+	if len(args) > 0 && args[0] == "multi" {
+		return parseMulti(args[1:], mcpURL)
+	}
+
 	fs := pflag.NewFlagSet("diff", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
 
 	var scope string
 	var maxDiffLines int
+	var ignoreJSONPaths []string
 
-	fs.StringVar(&scope, "scope", "", "what to compare: request, response, request_headers, response_headers, request_body, response_body")
+	fs.StringVar(&scope, "scope", "", "what to compare: request, response, request_headers, response_headers, request_body, response_body, request_raw, response_raw")
 	fs.IntVar(&maxDiffLines, "max-diff-lines", 0, "cap body diff output (default: 50 text, 20 JSON)")
+	fs.StringArrayVar(&ignoreJSONPaths, "ignore-json-path", nil, "drop JSON body path from both flows before comparing, e.g. data.timestamp (can specify multiple times)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool diff <flow_a> <flow_b> --scope <scope> [options]
 
 Compare two captured flows, showing exactly what differs.
+Use "sectool diff multi" to compare three or more flows side by side.
 
 Arguments:
   <flow_a>    First flow ID (from proxy_poll, replay_send, or crawl_poll)
@@ -35,6 +42,8 @@ Scope (required):
   response_headers  Status, response headers only
   request_body      Request body only
   response_body     Response body only
+  request_raw       Unified diff of the exact raw request bytes
+  response_raw      Unified diff of the exact raw response bytes
 
 Options:
 `)
@@ -44,6 +53,8 @@ Examples:
   sectool diff f7k2x rpl_abc --scope response
   sectool diff f7k2x f9m3z --scope request_headers
   sectool diff f7k2x f9m3z --scope request_body --max-diff-lines 100
+  sectool diff f7k2x rpl_abc --scope request_raw
+  sectool diff f7k2x rpl_abc --scope response_body --ignore-json-path data.timestamp --ignore-json-path meta.requestId
 `)
 	}
 
@@ -60,5 +71,59 @@ Examples:
 		return errors.New("--scope is required")
 	}
 
-	return run(mcpURL, posArgs[0], posArgs[1], scope, maxDiffLines)
+	return run(mcpURL, posArgs[0], posArgs[1], scope, maxDiffLines, ignoreJSONPaths)
+}
+
+func parseMulti(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("diff multi", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+
+	var scope string
+	var maxFields int
+	var ignoreJSONPaths []string
+
+	fs.StringVar(&scope, "scope", "", "what to compare: request, response, request_headers, response_headers, request_body, response_body")
+	fs.IntVar(&maxFields, "max-fields", 0, "cap differing fields shown (default: 50)")
+	fs.StringArrayVar(&ignoreJSONPaths, "ignore-json-path", nil, "drop JSON body path from every flow before comparing, e.g. data.timestamp (can specify multiple times)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool diff multi <flow_a> <flow_b> <flow_c> ... --scope <scope> [options]
+
+Compare several captured flows side by side. Each row is a field (method,
+path, status, query.<name>, header.<Name>, body.<json path> or body) that
+differs across the flows, with one column per flow. Values that differ
+from the first flow are highlighted.
+
+Scope (required):
+  request           Method, path, query, request headers, request body
+  response          Status, response headers, response body
+  request_headers   Method, path, query, request headers only
+  response_headers  Status, response headers only
+  request_body      Request body only
+  response_body     Response body only
+
+Options:
+`)
+		fs.PrintDefaults()
+		_, _ = fmt.Fprint(os.Stderr, `
+Examples:
+  sectool diff multi f7k2x f9m3z rpl_abc --scope response
+  sectool diff multi f7k2x f9m3z rpl_abc rpl_def --scope response_body --ignore-json-path data.timestamp
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	posArgs := fs.Args()
+	if len(posArgs) < 2 {
+		fs.Usage()
+		return errors.New("at least two flow IDs required: sectool diff multi <flow_a> <flow_b> <flow_c> ... --scope <scope>")
+	} else if scope == "" {
+		fs.Usage()
+		return errors.New("--scope is required")
+	}
+
+	return runMulti(mcpURL, posArgs, scope, maxFields, ignoreJSONPaths)
 }
@@ -2,35 +2,68 @@ package encoding
 
 import (
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"html"
 	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
 )
 
 const (
 	typeURL    = "url"
 	typeBase64 = "base64"
 	typeHTML   = "html"
+	typeHex    = "hex"
+	typeOctal  = "octal"
 )
 
-var errInvalidType = errors.New("invalid type: use 'url', 'base64', or 'html'")
+// Base64 variants for EncodeBase64 and DecodeBase64.
+const (
+	base64Std    = "std"
+	base64URL    = "url"
+	base64RawStd = "rawstd"
+	base64RawURL = "rawurl"
+)
+
+var errInvalidType = errors.New("invalid type: use 'url', 'base64', 'html', 'hex', or 'octal'")
+
+var errInvalidBase64Variant = errors.New("invalid base64 variant: use 'std', 'url', 'rawstd', or 'rawurl'")
 
-// Encode encodes input using the specified type (url, base64, html).
+var base64Variants = map[string]*base64.Encoding{
+	base64Std:    base64.StdEncoding,
+	base64URL:    base64.URLEncoding,
+	base64RawStd: base64.RawStdEncoding,
+	base64RawURL: base64.RawURLEncoding,
+}
+
+// Encode encodes input using the specified type (url, base64, html, hex, octal).
 func Encode(input, typ string) (string, error) {
 	switch typ {
 	case typeURL:
 		return url.QueryEscape(input), nil
 	case typeBase64:
-		return base64.StdEncoding.EncodeToString([]byte(input)), nil
+		return EncodeBase64(input, "")
 	case typeHTML:
 		return html.EscapeString(input), nil
+	case typeHex:
+		return hex.EncodeToString([]byte(input)), nil
+	case typeOctal:
+		var b strings.Builder
+		for _, c := range []byte(input) {
+			_, _ = fmt.Fprintf(&b, "\\%03o", c)
+		}
+		return b.String(), nil
 	default:
 		return "", errInvalidType
 	}
 }
 
-// Decode decodes input using the specified type (url, base64, html).
+// Decode decodes input using the specified type (url, base64, html, hex, octal).
+// Hex accepts plain digits, \x41 escapes, or 0x41 tokens; octal accepts \101 escapes
+// or whitespace-separated digits.
 func Decode(input, typ string) (string, error) {
 	switch typ {
 	case typeURL:
@@ -40,14 +73,77 @@ func Decode(input, typ string) (string, error) {
 		}
 		return decoded, nil
 	case typeBase64:
-		decoded, err := base64.StdEncoding.DecodeString(input)
+		return DecodeBase64(input, "")
+	case typeHTML:
+		return html.UnescapeString(input), nil
+	case typeHex:
+		var digits strings.Builder
+		for _, tok := range escapeTokens(input, `\x`, `\X`) {
+			digits.WriteString(strings.TrimPrefix(strings.TrimPrefix(tok, "0x"), "0X"))
+		}
+		decoded, err := hex.DecodeString(digits.String())
 		if err != nil {
-			return "", fmt.Errorf("base64 decode error: %w", err)
+			return "", fmt.Errorf("hex decode error: %w", err)
+		}
+		return string(decoded), nil
+	case typeOctal:
+		tokens := escapeTokens(input, `\`)
+		decoded := make([]byte, 0, len(tokens))
+		for _, tok := range tokens {
+			c, err := strconv.ParseUint(tok, 8, 8)
+			if err != nil {
+				return "", fmt.Errorf("octal decode error: %w", err)
+			}
+			decoded = append(decoded, byte(c))
 		}
 		return string(decoded), nil
-	case typeHTML:
-		return html.UnescapeString(input), nil
 	default:
 		return "", errInvalidType
 	}
 }
+
+// EncodeBase64 encodes input with a base64 variant (std, url, rawstd, rawurl); empty means std.
+func EncodeBase64(input, variant string) (string, error) {
+	if variant == "" {
+		variant = base64Std
+	}
+	enc, ok := base64Variants[variant]
+	if !ok {
+		return "", errInvalidBase64Variant
+	}
+	return enc.EncodeToString([]byte(input)), nil
+}
+
+// DecodeBase64 decodes input with a base64 variant. With no variant it tries std, then url,
+// then their unpadded forms, so URL-safe tokens and JWT segments decode without choosing one.
+// Errors report the first variant attempted.
+func DecodeBase64(input, variant string) (string, error) {
+	attempts := []string{base64Std, base64URL, base64RawStd, base64RawURL}
+	if variant != "" {
+		if _, ok := base64Variants[variant]; !ok {
+			return "", errInvalidBase64Variant
+		}
+		attempts = []string{variant}
+	}
+
+	var firstErr error
+	for _, v := range attempts {
+		decoded, err := base64Variants[v].DecodeString(input)
+		if err == nil {
+			return string(decoded), nil
+		} else if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return "", fmt.Errorf("base64 decode error: %w", firstErr)
+}
+
+// escapeTokens splits input into tokens at the escape prefixes and at whitespace, colons or commas.
+func escapeTokens(input string, prefixes ...string) []string {
+	for _, p := range prefixes {
+		input = strings.ReplaceAll(input, p, " ")
+	}
+	return strings.FieldsFunc(input, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ':' || r == ','
+	})
+}
@@ -20,6 +20,8 @@ func TestEncode(t *testing.T) {
 		{name: "url_special_chars", input: "a&b=c", typ: "url", expect: "a%26b%3Dc"},
 		{name: "base64", input: "data", typ: "base64", expect: "ZGF0YQ=="},
 		{name: "html", input: "<a>", typ: "html", expect: "&lt;a&gt;"},
+		{name: "hex", input: "<A\n", typ: "hex", expect: "3c410a"},
+		{name: "octal", input: "<A\n", typ: "octal", expect: `\074\101\012`},
 	}
 
 	for _, tt := range tests {
@@ -55,6 +57,16 @@ func TestDecode(t *testing.T) {
 		{name: "base64_valid", input: "ZGF0YQ==", typ: "base64", expect: "data"},
 		{name: "base64_invalid", input: "@@@", typ: "base64", wantErr: "base64 decode error"},
 		{name: "html", input: "&lt;a&gt;", typ: "html", expect: "<a>"},
+		{name: "hex_plain", input: "3c410A", typ: "hex", expect: "<A\n"},
+		{name: "hex_escapes", input: `\x3c\x41\X0a`, typ: "hex", expect: "<A\n"},
+		{name: "hex_prefixed_tokens", input: "0x3c 0x41, 0x0a", typ: "hex", expect: "<A\n"},
+		{name: "hex_colon_separated", input: "3c:41:0a", typ: "hex", expect: "<A\n"},
+		{name: "hex_odd_length", input: "3c4", typ: "hex", wantErr: "hex decode error"},
+		{name: "hex_invalid", input: "zz", typ: "hex", wantErr: "hex decode error"},
+		{name: "octal_escapes", input: `\074\101\12`, typ: "octal", expect: "<A\n"},
+		{name: "octal_spaced", input: "074 101 012", typ: "octal", expect: "<A\n"},
+		{name: "octal_invalid_digit", input: `\089`, typ: "octal", wantErr: "octal decode error"},
+		{name: "octal_out_of_range", input: `\400`, typ: "octal", wantErr: "octal decode error"},
 	}
 
 	for _, tt := range tests {
@@ -78,3 +90,89 @@ func TestDecode_invalid_type(t *testing.T) {
 	require.Error(t, err)
 	assert.ErrorContains(t, err, "invalid type")
 }
+
+func TestEncodeBase64(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		variant string
+		expect  string
+		wantErr string
+	}{
+		{name: "default_std", input: "<<??", expect: "PDw/Pw=="},
+		{name: "std", input: "<<??", variant: "std", expect: "PDw/Pw=="},
+		{name: "url", input: "<<??", variant: "url", expect: "PDw_Pw=="},
+		{name: "rawstd", input: "<<??", variant: "rawstd", expect: "PDw/Pw"},
+		{name: "rawurl", input: "<<??", variant: "rawurl", expect: "PDw_Pw"},
+		{name: "no_padding_needed", input: "abc", variant: "url", expect: "YWJj"},
+		{name: "one_padding_char", input: "ab", variant: "url", expect: "YWI="},
+		{name: "invalid_variant", input: "a", variant: "base32", wantErr: "invalid base64 variant"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := EncodeBase64(tt.input, tt.variant)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, result)
+		})
+	}
+}
+
+func TestDecodeBase64(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		variant string
+		expect  string
+		wantErr string
+	}{
+		{name: "auto_std", input: "PDw/Pw==", expect: "<<??"},
+		{name: "auto_url", input: "PDw_Pw==", expect: "<<??"},
+		{name: "auto_rawstd", input: "PDw/Pw", expect: "<<??"},
+		{name: "auto_rawurl_jwt_segment", input: "eyJzdWIiOiIxMjM0NTY3ODkwIn0", expect: `{"sub":"1234567890"}`},
+		{name: "explicit_url", input: "PDw_Pw==", variant: "url", expect: "<<??"},
+		{name: "explicit_std_rejects_url_alphabet", input: "PDw_Pw==", variant: "std", wantErr: "base64 decode error"},
+		{name: "explicit_raw_rejects_padding", input: "YWI=", variant: "rawurl", wantErr: "base64 decode error"},
+		{name: "explicit_padded_requires_padding", input: "YWI", variant: "url", wantErr: "base64 decode error"},
+		{name: "auto_invalid_chars", input: "YW*j", wantErr: "illegal base64 data at input byte 2"},
+		{name: "auto_bad_length", input: "YWJjZ", wantErr: "base64 decode error"},
+		{name: "invalid_variant", input: "YWJj", variant: "base32", wantErr: "invalid base64 variant"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DecodeBase64(tt.input, tt.variant)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, result)
+		})
+	}
+}
+
+func TestEncodeDecode_round_trip(t *testing.T) {
+	t.Parallel()
+
+	inputs := []string{"", "hello world", "<script>alert('x')</script>", "\x00\xff\r\n", "ünïcødé"}
+	for _, typ := range []string{"url", "base64", "html", "hex", "octal"} {
+		t.Run(typ, func(t *testing.T) {
+			for _, input := range inputs {
+				encoded, err := Encode(input, typ)
+				require.NoError(t, err)
+				decoded, err := Decode(encoded, typ)
+				require.NoError(t, err)
+				assert.Equal(t, input, decoded)
+			}
+		})
+	}
+}
@@ -10,9 +10,10 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/go-appsec/toolbox/sectool/cliutil"
+	"github.com/go-appsec/toolbox/sectool/jwt"
 )
 
-var encodeTypes = []string{"url", "base64", "html", "help"}
+var encodeTypes = []string{"url", "base64", "html", "hex", "octal", "jwt", "help"}
 
 // ParseEncode is the entry point for `sectool encode <type> <input>`.
 func ParseEncode(args []string) error {
@@ -22,9 +23,16 @@ func ParseEncode(args []string) error {
 	}
 
 	switch args[0] {
-	case "url", "base64", "html":
+	case "url", "base64", "html", "hex", "octal":
 		encType := args[0]
-		return parseAndRun("encode", encType, args[1:], func(s string) (string, error) { return Encode(s, encType) })
+		return parseAndRun("encode", encType, args[1:], func(s, variant string) (string, error) {
+			if encType == typeBase64 {
+				return EncodeBase64(s, variant)
+			}
+			return Encode(s, encType)
+		})
+	case "jwt": // inspection only; same as `sectool jwt`
+		return jwt.Parse(args[1:])
 	case "help", "--help", "-h":
 		printEncodeUsage()
 		return nil
@@ -41,9 +49,16 @@ func ParseDecode(args []string) error {
 	}
 
 	switch args[0] {
-	case "url", "base64", "html":
+	case "url", "base64", "html", "hex", "octal":
 		encType := args[0]
-		return parseAndRun("decode", encType, args[1:], func(s string) (string, error) { return Decode(s, encType) })
+		return parseAndRun("decode", encType, args[1:], func(s, variant string) (string, error) {
+			if encType == typeBase64 {
+				return DecodeBase64(s, variant)
+			}
+			return Decode(s, encType)
+		})
+	case "jwt":
+		return jwt.Parse(args[1:])
 	case "help", "--help", "-h":
 		printDecodeUsage()
 		return nil
@@ -58,17 +73,21 @@ func printEncodeUsage() {
 Encode strings for security testing payloads.
 Runs locally, no service required.
 
-Types: url, base64, html
+Types: url, base64, html, hex, octal, jwt (decodes and inspects a token, same as sectool jwt)
 
 Examples:
   sectool encode url "hello world"           # hello+world
   sectool encode base64 "secret"             # c2VjcmV0
   sectool encode html "<script>"             # &lt;script&gt;
+  sectool encode hex "AB"                    # 4142
+  sectool encode octal "AB"                  # \101\102
   sectool encode base64 -f payload.bin       # encode file contents
+  sectool encode base64 --variant rawurl "<<??"  # PDw_Pw (URL-safe, unpadded)
 
 Options:
   -f, --file PATH   read input from file (- for stdin)
   --raw             output without trailing newline
+  --variant NAME    base64 only: std (default), url, rawstd, rawurl
 `)
 }
 
@@ -78,27 +97,34 @@ func printDecodeUsage() {
 Decode strings for security testing payloads.
 Runs locally, no service required.
 
-Types: url, base64, html
+Types: url, base64, html, hex, octal, jwt (decodes and inspects a token, same as sectool jwt)
 
 Examples:
   sectool decode url "hello+world"           # hello world
   sectool decode base64 "c2VjcmV0"           # secret
+  sectool decode base64 "eyJhbGciOiJIUzI1NiJ9"  # {"alg":"HS256"} (url/unpadded tried after std)
   sectool decode html "&lt;script&gt;"       # <script>
+  sectool decode hex "\x41\x42"              # AB (also 4142, 0x41 0x42)
+  sectool decode octal "\101\102"            # AB
 
 Options:
   -f, --file PATH   read input from file (- for stdin)
   --raw             output without trailing newline
+  --variant NAME    base64 only: std (default), url, rawstd, rawurl
 `)
 }
 
-func parseAndRun(command, typeName string, args []string, fn func(string) (string, error)) error {
+func parseAndRun(command, typeName string, args []string, fn func(input, variant string) (string, error)) error {
 	fs := pflag.NewFlagSet(command+" "+typeName, pflag.ContinueOnError)
 	fs.SetInterspersed(true)
 	var raw bool
-	var file string
+	var file, variant string
 
 	fs.StringVarP(&file, "file", "f", "", "read input from file (- for stdin)")
 	fs.BoolVar(&raw, "raw", false, "output without trailing newline")
+	if typeName == typeBase64 {
+		fs.StringVar(&variant, "variant", "", "base64 variant: std, url, rawstd, rawurl")
+	}
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprintf(os.Stderr, "Usage: sectool %s %s [options] <string>\n\nOptions:\n", command, typeName)
@@ -128,7 +154,7 @@ func parseAndRun(command, typeName string, args []string, fn func(string) (strin
 		return errors.New("input required: provide string argument or use -f")
 	}
 
-	result, err := fn(input)
+	result, err := fn(input, variant)
 	if err != nil {
 		return err
 	}
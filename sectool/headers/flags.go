@@ -0,0 +1,44 @@
+package headers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+// Parse handles the "sectool headers" command.
+func Parse(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("headers", pflag.ContinueOnError)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool headers <flow_id>
+
+Check the security headers and cookie flags of a captured response.
+
+Reports Content-Security-Policy, Strict-Transport-Security, X-Frame-Options,
+X-Content-Type-Options, Referrer-Policy and the Secure, HttpOnly and SameSite
+flags of each Set-Cookie as pass, warn or fail.
+
+Arguments:
+  <flow_id>    Flow ID (from proxy, replay, or crawl)
+
+Examples:
+  sectool headers f7k2x
+  sectool headers rpl_abc
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	posArgs := fs.Args()
+	if len(posArgs) < 1 {
+		fs.Usage()
+		return errors.New("flow_id required: sectool headers <flow_id>")
+	}
+
+	return run(mcpURL, posArgs[0])
+}
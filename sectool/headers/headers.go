@@ -0,0 +1,55 @@
+package headers
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/go-appsec/toolbox/sectool/cliutil"
+	"github.com/go-appsec/toolbox/sectool/mcpclient"
+)
+
+func run(mcpURL, flowID string) error {
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.SecurityHeaders(ctx, flowID)
+	if err != nil {
+		return fmt.Errorf("security_headers failed: %w", err)
+	}
+
+	fmt.Printf("%s\n\n", cliutil.Bold("Security Headers"))
+	fmt.Printf("Flow %s\n\n", cliutil.ID(flowID))
+
+	counts := make(map[string]int, 3)
+	t := cliutil.NewTable(os.Stdout)
+	t.AppendHeader(table.Row{"Check", "Status", "Value", "Detail"})
+	for _, c := range resp.Checks {
+		counts[c.Status]++
+		t.AppendRow(table.Row{c.Name, formatStatus(c.Status), c.Value, c.Detail})
+	}
+	t.Render()
+
+	fmt.Printf("\n%d pass, %d warn, %d fail\n", counts["pass"], counts["warn"], counts["fail"])
+	return nil
+}
+
+func formatStatus(status string) string {
+	switch status {
+	case "pass":
+		return cliutil.Success(status)
+	case "warn":
+		return cliutil.Warning(status)
+	case "fail":
+		return cliutil.Error(status)
+	default:
+		return status
+	}
+}
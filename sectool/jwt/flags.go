@@ -50,8 +50,11 @@ func printUsage() {
 Decode and inspect a JWT token.
 Runs locally, no service required.
 
-Strips "Bearer " prefix automatically. Reports security issues:
+Strips "Bearer " prefix automatically. Header and payload are pretty-printed
+and the signature is shown base64url-decoded as hex; it is never verified.
+Reports security issues:
 - Algorithm set to 'none'
+- Missing or extra segments (header and payload still shown)
 - Missing expiry claim
 - Expired token
 - Long-lived token (>30 days)
@@ -59,5 +62,6 @@ Strips "Bearer " prefix automatically. Reports security issues:
 Examples:
   sectool jwt eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxMjMifQ.signature
   sectool jwt "Bearer eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxMjMifQ.sig"
+  sectool encode jwt eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxMjMifQ.sig
 `)
 }
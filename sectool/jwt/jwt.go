@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -10,11 +11,12 @@ import (
 )
 
 type Result struct {
-	Header    map[string]interface{} `json:"header"`
-	Payload   map[string]interface{} `json:"payload"`
-	Signature string                 `json:"signature"`
-	Expiry    string                 `json:"expiry,omitempty"`
-	Issues    []string               `json:"issues,omitempty"`
+	Header       map[string]interface{} `json:"header"`
+	Payload      map[string]interface{} `json:"payload"`
+	Signature    string                 `json:"signature"`
+	SignatureHex string                 `json:"signature_hex,omitempty"` // decoded signature bytes; never verified
+	Expiry       string                 `json:"expiry,omitempty"`
+	Issues       []string               `json:"issues,omitempty"`
 }
 
 func DecodeJWT(token string) (*Result, error) {
@@ -22,17 +24,18 @@ func DecodeJWT(token string) (*Result, error) {
 	token = strings.TrimPrefix(token, "bearer ")
 	token = strings.TrimSpace(token)
 
+	// Header and payload are still shown for a missing or extra segment, with an issue noting it
 	parts := strings.Split(token, ".")
-	if len(parts) != 3 {
+	if len(parts) < 2 {
 		return nil, fmt.Errorf("invalid JWT: expected 3 parts, got %d", len(parts))
 	}
 
-	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	headerBytes, err := decodeSegment(parts[0])
 	if err != nil {
 		return nil, fmt.Errorf("invalid JWT header: %w", err)
 	}
 
-	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	payloadBytes, err := decodeSegment(parts[1])
 	if err != nil {
 		return nil, fmt.Errorf("invalid JWT payload: %w", err)
 	}
@@ -48,9 +51,20 @@ func DecodeJWT(token string) (*Result, error) {
 	}
 
 	result := &Result{
-		Header:    header,
-		Payload:   payload,
-		Signature: parts[2],
+		Header:  header,
+		Payload: payload,
+	}
+
+	if len(parts) != 3 {
+		result.Issues = append(result.Issues, fmt.Sprintf("malformed token: %d segments, expected 3", len(parts)))
+	}
+	if len(parts) > 2 {
+		result.Signature = parts[2]
+		if sig, err := decodeSegment(parts[2]); err != nil {
+			result.Issues = append(result.Issues, "signature is not valid base64url")
+		} else {
+			result.SignatureHex = hex.EncodeToString(sig)
+		}
 	}
 
 	if alg, ok := header["alg"]; ok {
@@ -86,6 +100,11 @@ func DecodeJWT(token string) (*Result, error) {
 	return result, nil
 }
 
+// decodeSegment base64url-decodes a JWT segment, tolerating padding some encoders leave in.
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
 func getNumericClaim(payload map[string]interface{}, key string) (float64, bool) {
 	v, ok := payload[key]
 	if !ok {
@@ -3,6 +3,7 @@ package jwt
 import (
 	"encoding/base64"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -124,6 +125,91 @@ func TestDecodeJWT_bearer_prefix(t *testing.T) {
 	assert.Equal(t, "HS256", result.Header["alg"])
 }
 
+func TestDecodeJWT_signature_hex(t *testing.T) {
+	t.Parallel()
+
+	h, _ := json.Marshal(map[string]interface{}{"alg": "HS256"})
+	p, _ := json.Marshal(map[string]interface{}{"sub": "123", "exp": float64(time.Now().Add(time.Hour).Unix())})
+	sig := base64.RawURLEncoding.EncodeToString([]byte{0xde, 0xad, 0xbe, 0xef, 0xfb})
+
+	result, err := DecodeJWT(base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(p) + "." + sig)
+	require.NoError(t, err)
+	assert.Equal(t, sig, result.Signature)
+	assert.Equal(t, "deadbeeffb", result.SignatureHex)
+	assert.Empty(t, result.Issues)
+}
+
+func TestDecodeJWT_segments(t *testing.T) {
+	t.Parallel()
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"123","exp":4102444800}`))
+
+	t.Run("missing_signature", func(t *testing.T) {
+		result, err := DecodeJWT(header + "." + payload)
+		require.NoError(t, err)
+		assert.Equal(t, "123", result.Payload["sub"])
+		assert.Empty(t, result.Signature)
+		assert.Contains(t, result.Issues, "malformed token: 2 segments, expected 3")
+		assert.Contains(t, result.Issues, "algorithm set to 'none' - signature not verified")
+	})
+
+	t.Run("extra_segment", func(t *testing.T) {
+		result, err := DecodeJWT(header + "." + payload + ".c2ln.extra")
+		require.NoError(t, err)
+		assert.Equal(t, "736967", result.SignatureHex)
+		assert.Contains(t, result.Issues, "malformed token: 4 segments, expected 3")
+	})
+
+	t.Run("empty_signature", func(t *testing.T) {
+		result, err := DecodeJWT(header + "." + payload + ".")
+		require.NoError(t, err)
+		assert.Empty(t, result.SignatureHex)
+		assert.Equal(t, []string{"algorithm set to 'none' - signature not verified"}, result.Issues)
+	})
+
+	t.Run("padded_segments", func(t *testing.T) {
+		padded := base64.URLEncoding.EncodeToString([]byte(`{"alg":"HS256","x":12}`))
+		require.True(t, strings.HasSuffix(padded, "="))
+		result, err := DecodeJWT(padded + "." + payload + ".c2ln")
+		require.NoError(t, err)
+		assert.Equal(t, "HS256", result.Header["alg"])
+	})
+
+	t.Run("invalid_signature", func(t *testing.T) {
+		result, err := DecodeJWT(header + "." + payload + ".!!")
+		require.NoError(t, err)
+		assert.Equal(t, "!!", result.Signature)
+		assert.Contains(t, result.Issues, "signature is not valid base64url")
+	})
+}
+
+func TestDecodeJWT_nested_claims(t *testing.T) {
+	t.Parallel()
+
+	token := makeJWT(
+		map[string]interface{}{"alg": "RS256", "kid": "k1"},
+		map[string]interface{}{
+			"sub": "123",
+			"exp": float64(time.Now().Add(-time.Minute).Unix()),
+			"realm_access": map[string]interface{}{
+				"roles": []interface{}{"admin", "user"},
+			},
+			"org": map[string]interface{}{"id": float64(7), "meta": map[string]interface{}{"tier": "gold"}},
+		},
+	)
+
+	result, err := DecodeJWT(token)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"admin", "user"}, result.Payload["realm_access"].(map[string]interface{})["roles"])
+	assert.Equal(t, "gold", result.Payload["org"].(map[string]interface{})["meta"].(map[string]interface{})["tier"])
+	assert.Contains(t, result.Expiry, "expired")
+
+	out, err := json.MarshalIndent(result, "", "  ")
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "\n      \"roles\": [\n        \"admin\",")
+}
+
 func TestDecodeJWT_malformed(t *testing.T) {
 	t.Parallel()
 
@@ -132,7 +218,7 @@ func TestDecodeJWT_malformed(t *testing.T) {
 		token   string
 		wantErr string
 	}{
-		{name: "too_few_parts", token: "abc.def", wantErr: "expected 3 parts"},
+		{name: "too_few_parts", token: "abcdef", wantErr: "expected 3 parts, got 1"},
 		{name: "invalid_header_base64", token: "!!!.def.ghi", wantErr: "invalid JWT header"},
 		{name: "invalid_payload_base64", token: base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256"}`)) + ".!!!.sig", wantErr: "invalid JWT payload"},
 		{name: "invalid_header_json", token: base64.RawURLEncoding.EncodeToString([]byte("not-json")) + "." + base64.RawURLEncoding.EncodeToString([]byte(`{}`)) + ".sig", wantErr: "invalid JWT header JSON"},
@@ -0,0 +1,51 @@
+package killswitch
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Parse handles the "sectool panic" command.
+func Parse(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("panic", pflag.ContinueOnError)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool panic [reason...]
+       sectool panic clear
+       sectool panic status
+
+Emergency stop for all target traffic. Cancels in-flight replay, request and
+smuggle probes, stops every running crawl, makes the built-in proxy drop
+browser connections, and refuses new traffic until cleared.
+
+Use it the moment a target owner asks to stop. Crawls stopped by the kill
+switch are not resumed when it is cleared.
+
+Arguments:
+  [reason...]  why testing was stopped (logged and shown to refused calls)
+  clear        re-enable traffic
+  status       show whether the kill switch is engaged
+
+Examples:
+  sectool panic "client requested stop"
+  sectool panic status
+  sectool panic clear
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	posArgs := fs.Args()
+	if len(posArgs) == 1 {
+		switch posArgs[0] {
+		case "clear", "status":
+			return run(mcpURL, posArgs[0], "")
+		}
+	}
+	return run(mcpURL, "engage", strings.Join(posArgs, " "))
+}
@@ -0,0 +1,46 @@
+package killswitch
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-appsec/toolbox/sectool/cliutil"
+	"github.com/go-appsec/toolbox/sectool/mcpclient"
+)
+
+func run(mcpURL, action, reason string) error {
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.KillSwitch(ctx, action, reason)
+	if err != nil {
+		return fmt.Errorf("kill switch %s failed: %w", action, err)
+	}
+
+	if !resp.Engaged {
+		fmt.Println(cliutil.Bold("Kill switch is not engaged") + ": target traffic is allowed")
+		return nil
+	}
+
+	fmt.Println(cliutil.BoldRed("KILL SWITCH ENGAGED") + ": all target traffic is halted")
+	fmt.Println()
+	fmt.Printf("Engaged At: %s\n", resp.EngagedAt)
+	if resp.Reason != "" {
+		fmt.Printf("Reason: %s\n", resp.Reason)
+	}
+	if action == "engage" {
+		fmt.Printf("Crawls Stopped: %d\n", resp.StoppedCrawls)
+		if resp.ProxyHalted {
+			fmt.Println("Proxy: dropping browser connections")
+		}
+	}
+	fmt.Println()
+	cliutil.HintCommand(os.Stdout, "To re-enable traffic", "sectool panic clear")
+	return nil
+}
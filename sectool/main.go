@@ -17,18 +17,26 @@ import (
 	"github.com/go-appsec/toolbox/sectool/diff"
 	"github.com/go-appsec/toolbox/sectool/encoding"
 	"github.com/go-appsec/toolbox/sectool/hash"
+	"github.com/go-appsec/toolbox/sectool/headers"
 	"github.com/go-appsec/toolbox/sectool/jwt"
+	"github.com/go-appsec/toolbox/sectool/killswitch"
 	"github.com/go-appsec/toolbox/sectool/oast"
 	"github.com/go-appsec/toolbox/sectool/proxy"
 	"github.com/go-appsec/toolbox/sectool/reflected"
 	"github.com/go-appsec/toolbox/sectool/replay"
 	"github.com/go-appsec/toolbox/sectool/service"
+	"github.com/go-appsec/toolbox/sectool/status"
+	"github.com/go-appsec/toolbox/sectool/stop"
 )
 
 func main() {
 	log.SetFlags(log.Ltime)
 
 	globalFlags, args := parseGlobalFlags(os.Args[1:])
+	if err := applyColorFlags(globalFlags); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 	if len(args) < 1 {
 		printRootUsage()
 		os.Exit(1)
@@ -64,7 +72,7 @@ func main() {
 		return
 
 	// Commands that need MCP client
-	case "proxy", "replay", "oast", "crawl", "diff", "reflected":
+	case "proxy", "replay", "oast", "crawl", "diff", "reflected", "headers", "panic", "status", "stop":
 		var mcpURL string
 		mcpURL, err = getMCPURL(globalFlags)
 		if err != nil {
@@ -84,10 +92,18 @@ func main() {
 			err = diff.Parse(args[1:], mcpURL)
 		case "reflected":
 			err = reflected.Parse(args[1:], mcpURL)
+		case "headers":
+			err = headers.Parse(args[1:], mcpURL)
+		case "panic":
+			err = killswitch.Parse(args[1:], mcpURL)
+		case "status":
+			err = status.Parse(args[1:], mcpURL)
+		case "stop":
+			err = stop.Parse(args[1:], mcpURL, globalFlags.ConfigPath)
 		}
 
 	default:
-		validCommands := []string{"mcp", "proxy", "replay", "oast", "crawl", "diff", "reflected", "encode", "decode", "hash", "jwt", "version", "help"}
+		validCommands := []string{"mcp", "proxy", "replay", "oast", "crawl", "diff", "reflected", "headers", "panic", "status", "stop", "encode", "decode", "hash", "jwt", "version", "help"}
 		err = cliutil.UnknownCommandError(args[0], validCommands)
 	}
 
@@ -128,6 +144,10 @@ Commands:
   crawl      Web crawler for URL and form discovery
   diff       Compare two captured flows
   reflected  Detect reflected parameters in a flow
+  headers    Check security headers and cookie flags of a flow
+  panic      Emergency stop: halt all crawl, replay and proxy traffic
+  status     Show whether the MCP server is running and its health
+  stop       Shut down the MCP server, draining running crawls
   encode     Encode strings (url, base64, html)
   decode     Decode strings (url, base64, html)
   hash       Compute hash digests (md5, sha1, sha256, sha512)
@@ -136,6 +156,8 @@ Commands:
 Global Options:
   --config <path>    Config file path (default: ~/.sectool/config.json)
   --mcp-url <url>    MCP server URL (default: http://127.0.0.1:<port from config>/mcp)
+  --color <mode>     Colorize output: auto, always, never (default: auto; honors NO_COLOR)
+  --no-color         Disable colored output (same as --color never)
 
 Use "sectool <command> --help" for specific command usage.
 `)
@@ -145,6 +167,8 @@ Use "sectool <command> --help" for specific command usage.
 type globalFlags struct {
 	ConfigPath string
 	MCPURL     string
+	Color      string
+	NoColor    bool
 }
 
 // parseGlobalFlags extracts global flags from args, returning remaining args.
@@ -174,12 +198,43 @@ func parseGlobalFlags(args []string) (globalFlags, []string) {
 			continue
 		}
 
+		// --color <mode> or --color=<mode>, --no-color
+		if arg == "--color" && i+1 < len(args) {
+			flags.Color = args[i+1]
+			i++
+			continue
+		} else if strings.HasPrefix(arg, "--color=") {
+			flags.Color = strings.TrimPrefix(arg, "--color=")
+			continue
+		} else if arg == "--no-color" {
+			flags.NoColor = true
+			continue
+		}
+
 		remaining = append(remaining, arg)
 	}
 
 	return flags, remaining
 }
 
+// applyColorFlags sets the output color mode from --no-color or --color.
+// Without either flag the NO_COLOR/FORCE_COLOR environment default stands.
+func applyColorFlags(flags globalFlags) error {
+	if flags.NoColor {
+		cliutil.Output.ColorMode = cliutil.ColorNever
+		return nil
+	} else if flags.Color == "" {
+		return nil
+	}
+
+	mode, err := cliutil.ParseColorMode(flags.Color)
+	if err != nil {
+		return err
+	}
+	cliutil.Output.ColorMode = mode
+	return nil
+}
+
 // getMCPURL returns the MCP server URL from flags or config.
 func getMCPURL(flags globalFlags) (string, error) {
 	if flags.MCPURL != "" {
@@ -109,6 +109,15 @@ func (c *Client) ProxyRuleAdd(ctx context.Context, opts RuleAddOpts) (*protocol.
 	if opts.IsRegex {
 		args["is_regex"] = opts.IsRegex
 	}
+	if opts.Host != "" {
+		args["host"] = opts.Host
+	}
+	if opts.Path != "" {
+		args["path"] = opts.Path
+	}
+	if opts.Method != "" {
+		args["method"] = opts.Method
+	}
 
 	var resp protocol.RuleEntry
 	if err := c.CallToolJSON(ctx, "proxy_rule_add", args, &resp); err != nil {
@@ -123,6 +132,32 @@ func (c *Client) ProxyRuleDelete(ctx context.Context, ruleID string) error {
 	return err
 }
 
+// ProxyIntercept calls proxy_intercept and returns the intercept state and held requests.
+func (c *Client) ProxyIntercept(ctx context.Context, opts InterceptOpts) (*protocol.ProxyInterceptResponse, error) {
+	args := make(map[string]interface{})
+	if opts.Action != "" {
+		args["action"] = opts.Action
+	}
+	if opts.Host != "" {
+		args["host"] = opts.Host
+	}
+	if opts.Path != "" {
+		args["path"] = opts.Path
+	}
+	if opts.Method != "" {
+		args["method"] = opts.Method
+	}
+	if opts.HeldID != "" {
+		args["held_id"] = opts.HeldID
+	}
+
+	var resp protocol.ProxyInterceptResponse
+	if err := c.CallToolJSON(ctx, "proxy_intercept", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // CookieJar calls cookie_jar and returns extracted cookies.
 func (c *Client) CookieJar(ctx context.Context, opts CookieJarOpts) (*protocol.CookieJarResponse, error) {
 	args := make(map[string]interface{})
@@ -183,6 +218,12 @@ func (c *Client) ReplaySend(ctx context.Context, opts ReplaySendOpts) (*protocol
 	if opts.Force {
 		args["force"] = opts.Force
 	}
+	if len(opts.Resolve) > 0 {
+		args["resolve"] = opts.Resolve
+	}
+	if opts.Timeout != "" {
+		args["timeout"] = opts.Timeout
+	}
 
 	var resp protocol.ReplaySendResponse
 	if err := c.CallToolJSON(ctx, "replay_send", args, &resp); err != nil {
@@ -218,6 +259,12 @@ func (c *Client) RequestSend(ctx context.Context, opts RequestSendOpts) (*protoc
 	if opts.FollowRedirects {
 		args["follow_redirects"] = opts.FollowRedirects
 	}
+	if len(opts.Resolve) > 0 {
+		args["resolve"] = opts.Resolve
+	}
+	if opts.Timeout != "" {
+		args["timeout"] = opts.Timeout
+	}
 
 	var resp protocol.ReplaySendResponse
 	if err := c.CallToolJSON(ctx, "request_send", args, &resp); err != nil {
@@ -226,6 +273,29 @@ func (c *Client) RequestSend(ctx context.Context, opts RequestSendOpts) (*protoc
 	return &resp, nil
 }
 
+// ReplaySmuggle calls replay_smuggle and returns the desync verdict.
+func (c *Client) ReplaySmuggle(ctx context.Context, opts ReplaySmuggleOpts) (*protocol.SmuggleProbeResponse, error) {
+	args := map[string]interface{}{
+		"url":               opts.URL,
+		"confirm_intrusive": opts.ConfirmIntrusive,
+	}
+	if opts.Method != "" {
+		args["method"] = opts.Method
+	}
+	if len(opts.Headers) > 0 {
+		args["headers"] = opts.Headers
+	}
+	if opts.Timeout != "" {
+		args["timeout"] = opts.Timeout
+	}
+
+	var resp protocol.SmuggleProbeResponse
+	if err := c.CallToolJSON(ctx, "replay_smuggle", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // OastCreate calls oast_create and returns the session.
 func (c *Client) OastCreate(ctx context.Context, label string) (*protocol.OastCreateResponse, error) {
 	args := make(map[string]interface{})
@@ -320,24 +390,136 @@ func (c *Client) CrawlCreate(ctx context.Context, opts CrawlCreateOpts) (*protoc
 	if len(opts.Headers) > 0 {
 		args["headers"] = opts.Headers
 	}
+	if len(opts.DomainHeaders) > 0 {
+		args["domain_headers"] = opts.DomainHeaders
+	}
+	if len(opts.Resolve) > 0 {
+		args["resolve"] = opts.Resolve
+	}
 	if opts.MaxDepth > 0 {
 		args["max_depth"] = opts.MaxDepth
 	}
 	if opts.MaxRequests > 0 {
 		args["max_requests"] = opts.MaxRequests
 	}
+	if opts.MaxHosts > 0 {
+		args["max_hosts"] = opts.MaxHosts
+	}
+	if opts.MaxPagesPerHost > 0 {
+		args["max_pages_per_host"] = opts.MaxPagesPerHost
+	}
+	if opts.BlockThreshold > 0 {
+		args["block_threshold"] = opts.BlockThreshold
+	}
+	if opts.MaxInFlightBytes > 0 {
+		args["max_in_flight_bytes"] = opts.MaxInFlightBytes
+	}
+	if opts.MaxBodyBytes != 0 {
+		args["max_body_bytes"] = opts.MaxBodyBytes
+	}
 	if opts.Delay != "" {
 		args["delay"] = opts.Delay
 	}
 	if opts.Parallelism > 0 {
 		args["parallelism"] = opts.Parallelism
 	}
+	if len(opts.HostLimits) > 0 {
+		args["host_limits"] = opts.HostLimits
+	}
+	if opts.Deterministic {
+		args["deterministic"] = opts.Deterministic
+	}
+	if opts.Strategy != "" {
+		args["strategy"] = opts.Strategy
+	}
 	if opts.SubmitForms {
 		args["submit_forms"] = opts.SubmitForms
 	}
 	if opts.IgnoreRobots {
 		args["ignore_robots"] = opts.IgnoreRobots
 	}
+	if opts.ExtractJSONURLs {
+		args["extract_json_urls"] = opts.ExtractJSONURLs
+	}
+	if opts.ExtractCSSURLs {
+		args["extract_css_urls"] = opts.ExtractCSSURLs
+	}
+	if opts.ExtractCommentURLs {
+		args["extract_comment_urls"] = opts.ExtractCommentURLs
+	}
+	if opts.ExtractJSRoutes {
+		args["extract_js_routes"] = opts.ExtractJSRoutes
+	}
+	if opts.ExtractJSEndpoints {
+		args["extract_js_endpoints"] = opts.ExtractJSEndpoints
+	}
+	if opts.SimilarityThreshold > 0 {
+		args["similarity_threshold"] = opts.SimilarityThreshold
+	}
+	if opts.DetectDirListing {
+		args["detect_dir_listing"] = opts.DetectDirListing
+	}
+	if opts.MergeTrailingSlash {
+		args["merge_trailing_slash"] = opts.MergeTrailingSlash
+	}
+	if opts.VaryVariants {
+		args["vary_variants"] = opts.VaryVariants
+	}
+	if opts.CompletionWebhook != "" {
+		args["completion_webhook"] = opts.CompletionWebhook
+	}
+	if opts.DoHResolver != "" {
+		args["doh_resolver"] = opts.DoHResolver
+	}
+	if opts.Referer != "" {
+		args["referer"] = opts.Referer
+	}
+	if opts.IgnoreQueryParams != "" {
+		args["ignore_query_params"] = opts.IgnoreQueryParams
+	}
+	if opts.AllowedContentTypes != "" {
+		args["allowed_content_types"] = opts.AllowedContentTypes
+	}
+	if opts.ExtraContentTypes != "" {
+		args["extra_content_types"] = opts.ExtraContentTypes
+	}
+	if len(opts.AllowPathRegex) > 0 {
+		args["allow_path_regex"] = opts.AllowPathRegex
+	}
+	if len(opts.DenyPathRegex) > 0 {
+		args["deny_path_regex"] = opts.DenyPathRegex
+	}
+	if opts.AllowDestructive {
+		args["safe_mode"] = false
+	}
+	if opts.SkipPreflight {
+		args["preflight_seed"] = false
+	}
+	if opts.NoCookieJar {
+		args["no_cookie_jar"] = true
+	}
+	if tr := opts.TokenRefresh; tr != nil {
+		refresh := map[string]interface{}{
+			"url":        tr.URL,
+			"token_path": tr.TokenPath,
+		}
+		if tr.Method != "" {
+			refresh["method"] = tr.Method
+		}
+		if tr.Body != "" {
+			refresh["body"] = tr.Body
+		}
+		if len(tr.Headers) > 0 {
+			refresh["headers"] = tr.Headers
+		}
+		args["token_refresh"] = refresh
+	}
+	if opts.CheckFormMethods {
+		args["check_form_methods"] = opts.CheckFormMethods
+	}
+	if opts.FollowLinksOnError {
+		args["follow_links_on_error"] = opts.FollowLinksOnError
+	}
 
 	var resp protocol.CrawlCreateResponse
 	if err := c.CallToolJSON(ctx, "crawl_create", args, &resp); err != nil {
@@ -365,16 +547,30 @@ func (c *Client) CrawlSeed(ctx context.Context, sessionID string, seedURLs, seed
 	return &resp, nil
 }
 
-// CrawlStatus calls crawl_status and returns session status.
-func (c *Client) CrawlStatus(ctx context.Context, sessionID string) (*protocol.CrawlStatusResponse, error) {
+// CrawlStatus calls crawl_status and returns session status. A non-empty wait (e.g. "30s")
+// long-polls until crawl activity changes or the session ends.
+func (c *Client) CrawlStatus(ctx context.Context, sessionID, wait string) (*protocol.CrawlStatusResponse, error) {
+	args := map[string]interface{}{"session_id": sessionID}
+	if wait != "" {
+		args["wait"] = wait
+	}
 	var resp protocol.CrawlStatusResponse
-	if err := c.CallToolJSON(ctx, "crawl_status", map[string]interface{}{"session_id": sessionID}, &resp); err != nil {
+	if err := c.CallToolJSON(ctx, "crawl_status", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CrawlStats calls crawl_stats and returns status code and content type distributions.
+func (c *Client) CrawlStats(ctx context.Context, sessionID string) (*protocol.CrawlStatsResponse, error) {
+	var resp protocol.CrawlStatsResponse
+	if err := c.CallToolJSON(ctx, "crawl_stats", map[string]interface{}{"session_id": sessionID}, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// CrawlPoll calls crawl_poll and returns summary, flows, forms, or errors.
+// CrawlPoll calls crawl_poll and returns summary, flows, forms, errors, or external links.
 func (c *Client) CrawlPoll(ctx context.Context, sessionID string, opts CrawlPollOpts) (*protocol.CrawlPollResponse, error) {
 	args := map[string]interface{}{
 		"session_id": sessionID,
@@ -406,15 +602,27 @@ func (c *Client) CrawlPoll(ctx context.Context, sessionID string, opts CrawlPoll
 	if opts.ExcludePath != "" {
 		args["exclude_path"] = opts.ExcludePath
 	}
+	if opts.Invert {
+		args["invert"] = true
+	}
+	if opts.DuplicateOf != "" {
+		args["duplicate_of"] = opts.DuplicateOf
+	}
 	if opts.Since != "" {
 		args["since"] = opts.Since
 	}
+	if opts.Cursor != "" {
+		args["cursor"] = opts.Cursor
+	}
 	if opts.Limit > 0 {
 		args["limit"] = opts.Limit
 	}
 	if opts.Offset > 0 {
 		args["offset"] = opts.Offset
 	}
+	if opts.Wait != "" {
+		args["wait"] = opts.Wait
+	}
 
 	var resp protocol.CrawlPollResponse
 	if err := c.CallToolJSON(ctx, "crawl_poll", args, &resp); err != nil {
@@ -423,6 +631,54 @@ func (c *Client) CrawlPoll(ctx context.Context, sessionID string, opts CrawlPoll
 	return &resp, nil
 }
 
+// CrawlDelete calls crawl_delete and returns the number of flows deleted.
+func (c *Client) CrawlDelete(ctx context.Context, sessionID string, opts CrawlDeleteOpts) (*protocol.CrawlDeleteResponse, error) {
+	args := make(map[string]interface{})
+	for name, value := range map[string]string{
+		"session_id":    sessionID,
+		"flow_id":       opts.FlowID,
+		"host":          opts.Host,
+		"path":          opts.Path,
+		"method":        opts.Method,
+		"status":        opts.Status,
+		"content_type":  opts.ContentType,
+		"search_header": opts.SearchHeader,
+		"search_body":   opts.SearchBody,
+		"exclude_host":  opts.ExcludeHost,
+		"exclude_path":  opts.ExcludePath,
+		"duplicate_of":  opts.DuplicateOf,
+	} {
+		if value != "" {
+			args[name] = value
+		}
+	}
+	if opts.Invert {
+		args["invert"] = true
+	}
+	if opts.Session {
+		args["delete_session"] = true
+	}
+	if opts.Force {
+		args["force"] = true
+	}
+
+	var resp protocol.CrawlDeleteResponse
+	if err := c.CallToolJSON(ctx, "crawl_delete", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CrawlExportSession calls crawl_export_session and returns the session's flow metadata
+// in format (ndjson or csv).
+func (c *Client) CrawlExportSession(ctx context.Context, sessionID, format string) (string, error) {
+	args := map[string]interface{}{"session_id": sessionID}
+	if format != "" {
+		args["format"] = format
+	}
+	return c.CallToolText(ctx, "crawl_export_session", args)
+}
+
 // CrawlSessions calls crawl_sessions and returns all sessions.
 func (c *Client) CrawlSessions(ctx context.Context, limit int) (*protocol.CrawlSessionsResponse, error) {
 	args := make(map[string]interface{})
@@ -443,6 +699,29 @@ func (c *Client) CrawlStop(ctx context.Context, sessionID string) error {
 	return err
 }
 
+// CrawlResume calls crawl_resume to restart a stopped or completed session.
+func (c *Client) CrawlResume(ctx context.Context, sessionID string) (*protocol.CrawlResumeResponse, error) {
+	var resp protocol.CrawlResumeResponse
+	if err := c.CallToolJSON(ctx, "crawl_resume", map[string]interface{}{"session_id": sessionID}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ExtractLinks calls extract_links to list the links in a flow's response.
+// source optionally restricts results to a comma-separated list of link sources.
+func (c *Client) ExtractLinks(ctx context.Context, flowID, source string) (*protocol.ExtractLinksResponse, error) {
+	args := map[string]interface{}{"flow_id": flowID}
+	if source != "" {
+		args["source"] = source
+	}
+	var resp protocol.ExtractLinksResponse
+	if err := c.CallToolJSON(ctx, "extract_links", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // DiffFlow calls diff_flow and returns the structured diff.
 func (c *Client) DiffFlow(ctx context.Context, opts DiffFlowOpts) (*protocol.DiffFlowResponse, error) {
 	args := map[string]interface{}{
@@ -453,6 +732,9 @@ func (c *Client) DiffFlow(ctx context.Context, opts DiffFlowOpts) (*protocol.Dif
 	if opts.MaxDiffLines > 0 {
 		args["max_diff_lines"] = opts.MaxDiffLines
 	}
+	if len(opts.IgnoreJSONPaths) > 0 {
+		args["ignore_json_paths"] = opts.IgnoreJSONPaths
+	}
 
 	var resp protocol.DiffFlowResponse
 	if err := c.CallToolJSON(ctx, "diff_flow", args, &resp); err != nil {
@@ -461,6 +743,26 @@ func (c *Client) DiffFlow(ctx context.Context, opts DiffFlowOpts) (*protocol.Dif
 	return &resp, nil
 }
 
+// DiffMulti calls diff_multi and returns the per-field comparison.
+func (c *Client) DiffMulti(ctx context.Context, opts DiffMultiOpts) (*protocol.DiffMultiResponse, error) {
+	args := map[string]interface{}{
+		"flow_ids": opts.FlowIDs,
+		"scope":    opts.Scope,
+	}
+	if opts.MaxFields > 0 {
+		args["max_fields"] = opts.MaxFields
+	}
+	if len(opts.IgnoreJSONPaths) > 0 {
+		args["ignore_json_paths"] = opts.IgnoreJSONPaths
+	}
+
+	var resp protocol.DiffMultiResponse
+	if err := c.CallToolJSON(ctx, "diff_multi", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // CrawlGet calls crawl_get and returns full flow data.
 func (c *Client) CrawlGet(ctx context.Context, flowID string, opts CrawlGetOpts) (*protocol.CrawlGetResponse, error) {
 	args := map[string]interface{}{"flow_id": flowID}
@@ -481,11 +783,64 @@ func (c *Client) CrawlGet(ctx context.Context, flowID string, opts CrawlGetOpts)
 }
 
 // FindReflected calls find_reflected and returns detected reflections.
-func (c *Client) FindReflected(ctx context.Context, flowID string) (*protocol.FindReflectedResponse, error) {
+func (c *Client) FindReflected(ctx context.Context, flowID string, opts FindReflectedOpts) (*protocol.FindReflectedResponse, error) {
 	args := map[string]interface{}{"flow_id": flowID}
+	if len(opts.Locations) > 0 {
+		args["locations"] = opts.Locations
+	}
+	if opts.Limit > 0 {
+		args["limit"] = opts.Limit
+	}
+	if opts.MinLength > 0 {
+		args["min_length"] = opts.MinLength
+	}
 	var resp protocol.FindReflectedResponse
 	if err := c.CallToolJSON(ctx, "find_reflected", args, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
+
+// SecurityHeaders calls security_headers and returns the header and cookie checks.
+func (c *Client) SecurityHeaders(ctx context.Context, flowID string) (*protocol.SecurityHeadersResponse, error) {
+	var resp protocol.SecurityHeadersResponse
+	if err := c.CallToolJSON(ctx, "security_headers", map[string]interface{}{"flow_id": flowID}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// KillSwitch calls kill_switch with action engage, clear, or status.
+func (c *Client) KillSwitch(ctx context.Context, action, reason string) (*protocol.KillSwitchResponse, error) {
+	args := map[string]interface{}{"action": action}
+	if reason != "" {
+		args["reason"] = reason
+	}
+	var resp protocol.KillSwitchResponse
+	if err := c.CallToolJSON(ctx, "kill_switch", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ServerStatus calls server_status and returns the running server's health.
+func (c *Client) ServerStatus(ctx context.Context) (*protocol.ServerStatusResponse, error) {
+	var resp protocol.ServerStatusResponse
+	if err := c.CallToolJSON(ctx, "server_status", map[string]interface{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ServerStop calls server_stop, which drains crawls for up to timeout and shuts the server down.
+func (c *Client) ServerStop(ctx context.Context, timeout string) (*protocol.ServerStopResponse, error) {
+	args := map[string]interface{}{}
+	if timeout != "" {
+		args["timeout"] = timeout
+	}
+	var resp protocol.ServerStopResponse
+	if err := c.CallToolJSON(ctx, "server_stop", args, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
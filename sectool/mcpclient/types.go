@@ -35,6 +35,18 @@ type RuleAddOpts struct {
 	Replace string
 	Label   string
 	IsRegex bool
+	Host    string // block/redirect scope
+	Path    string
+	Method  string
+}
+
+// InterceptOpts are options for ProxyIntercept.
+type InterceptOpts struct {
+	Action string // status, on, off, release, or drop
+	Host   string
+	Path   string
+	Method string
+	HeldID string
 }
 
 // =============================================================================
@@ -57,6 +69,8 @@ type ReplaySendOpts struct {
 	RemoveJSON      []string
 	FollowRedirects bool
 	Force           bool
+	Resolve         []string // "host=ip" dial overrides
+	Timeout         string   // per-request timeout, e.g. "5s"
 }
 
 // RequestSendOpts are options for RequestSend.
@@ -66,6 +80,17 @@ type RequestSendOpts struct {
 	Headers         map[string]string
 	Body            string
 	FollowRedirects bool
+	Resolve         []string // "host=ip" dial overrides
+	Timeout         string   // per-request timeout, e.g. "5s"
+}
+
+// ReplaySmuggleOpts are options for ReplaySmuggle.
+type ReplaySmuggleOpts struct {
+	URL              string
+	Method           string
+	Headers          map[string]string
+	Timeout          string
+	ConfirmIntrusive bool
 }
 
 // =============================================================================
@@ -74,22 +99,64 @@ type RequestSendOpts struct {
 
 // CrawlCreateOpts are options for CrawlCreate.
 type CrawlCreateOpts struct {
-	Label        string
-	SeedURLs     string
-	SeedFlows    string
-	Domains      string
-	Headers      map[string]string
-	MaxDepth     int
-	MaxRequests  int
-	Delay        string
-	Parallelism  int
-	SubmitForms  bool
-	IgnoreRobots bool
+	Label               string
+	SeedURLs            string
+	SeedFlows           string
+	Domains             string
+	Headers             map[string]string
+	DomainHeaders       map[string]map[string]string
+	Resolve             []string // "host=ip" dial overrides
+	MaxDepth            int
+	MaxRequests         int
+	MaxHosts            int
+	MaxPagesPerHost     int
+	BlockThreshold      int
+	MaxInFlightBytes    int64
+	MaxBodyBytes        int // 0 = server default, negative = unlimited
+	Delay               string
+	Parallelism         int
+	HostLimits          []string // "host_glob=delay[,parallelism[,random_delay]]" rate limits
+	Deterministic       bool
+	Strategy            string // bfs or dfs; empty for the server default
+	SubmitForms         bool
+	IgnoreRobots        bool
+	ExtractJSONURLs     bool
+	ExtractCSSURLs      bool
+	ExtractCommentURLs  bool
+	ExtractJSRoutes     bool
+	ExtractJSEndpoints  bool
+	SimilarityThreshold float64
+	DetectDirListing    bool
+	MergeTrailingSlash  bool
+	VaryVariants        bool
+	CheckFormMethods    bool
+	FollowLinksOnError  bool
+	AllowDestructive    bool // Disables the server's default safe_mode
+	SkipPreflight       bool // Disables the server's default seed preflight
+	NoCookieJar         bool // Sends seed cookies statically, ignoring cookies set by responses
+	CompletionWebhook   string
+	DoHResolver         string
+	Referer             string
+	IgnoreQueryParams   string // Comma-separated parameter name globs
+	AllowPathRegex      []string
+	AllowedContentTypes string // Comma-separated Content-Type prefixes
+	ExtraContentTypes   string // Comma-separated Content-Type prefixes
+	DenyPathRegex       []string
+	TokenRefresh        *CrawlTokenRefresh
+}
+
+// CrawlTokenRefresh describes the token endpoint a crawl calls after a 401.
+type CrawlTokenRefresh struct {
+	URL       string
+	Method    string
+	Body      string
+	Headers   map[string]string
+	TokenPath string // Dot-notation JSON path to the token in the response
 }
 
 // CrawlPollOpts are options for CrawlPoll.
 type CrawlPollOpts struct {
-	OutputMode   string // "summary", "flows", "forms", "errors"
+	OutputMode   string // "summary", "flows", "forms", "errors", "external", "routes", "endpoints", "similar", "findings"
 	Host         string
 	Path         string
 	Method       string
@@ -98,9 +165,32 @@ type CrawlPollOpts struct {
 	SearchBody   string
 	ExcludeHost  string
 	ExcludePath  string
+	Invert       bool   // return flows not matching the filters
+	DuplicateOf  string // flow_id whose endpoint fingerprint results must share
 	Since        string // flows mode
+	Cursor       string // named cursor for since=last
 	Limit        int
 	Offset       int
+	Wait         string // flows mode long-poll duration
+}
+
+// CrawlDeleteOpts are options for CrawlDelete. FlowID deletes a single flow and
+// Session the whole session; otherwise the filters select flows in the session.
+type CrawlDeleteOpts struct {
+	FlowID       string
+	Host         string
+	Path         string
+	Method       string
+	Status       string
+	ContentType  string
+	SearchHeader string
+	SearchBody   string
+	ExcludeHost  string
+	ExcludePath  string
+	DuplicateOf  string
+	Invert       bool
+	Session      bool
+	Force        bool // with Session, stop a running session first
 }
 
 // CrawlGetOpts are options for CrawlGet.
@@ -116,12 +206,28 @@ type CookieJarOpts struct {
 	Domain string
 }
 
+// FindReflectedOpts are options for FindReflected.
+type FindReflectedOpts struct {
+	Locations []string // response sections to search (body, headers); empty searches both
+	Limit     int
+	MinLength int // shortest parameter value searched for; 0 uses the server default
+}
+
 // DiffFlowOpts are options for DiffFlow.
 type DiffFlowOpts struct {
-	FlowA        string
-	FlowB        string
-	Scope        string
-	MaxDiffLines int
+	FlowA           string
+	FlowB           string
+	Scope           string
+	MaxDiffLines    int
+	IgnoreJSONPaths []string
+}
+
+// DiffMultiOpts are options for DiffMulti.
+type DiffMultiOpts struct {
+	FlowIDs         []string
+	Scope           string
+	MaxFields       int
+	IgnoreJSONPaths []string
 }
 
 // OastPollOpts are options for OastPoll.
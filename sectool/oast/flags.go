@@ -100,7 +100,8 @@ oast get <oast_id|label|domain> <event_id>
     sectool oast poll abc123          # find event_id
     sectool oast get abc123 evt_xyz   # get full details
 
-  Output: Complete raw request/response data
+  Output: Complete raw request/response data; DNS events include the query
+  name, query type, and a hex dump of the captured request
 
 ---
 
@@ -70,9 +70,7 @@ func summary(mcpURL string, oastID, since, eventType string, wait time.Duration,
 
 	if len(resp.Aggregates) == 0 {
 		cliutil.NoResults(os.Stdout, "No events received.")
-		if resp.DroppedCount > 0 {
-			cliutil.Hint(os.Stdout, fmt.Sprintf("Note: %d events were dropped due to buffer limit", resp.DroppedCount))
-		}
+		printPollNotes(resp)
 		return nil
 	}
 
@@ -84,9 +82,7 @@ func summary(mcpURL string, oastID, since, eventType string, wait time.Duration,
 	t.Render()
 	cliutil.Summary(os.Stdout, len(resp.Aggregates), "unique interaction pattern", "unique interaction patterns")
 
-	if resp.DroppedCount > 0 {
-		cliutil.Hint(os.Stdout, fmt.Sprintf("Note: %d events were dropped due to buffer limit", resp.DroppedCount))
-	}
+	printPollNotes(resp)
 
 	return nil
 }
@@ -113,9 +109,7 @@ func poll(mcpURL string, oastID, since, eventType string, wait time.Duration, li
 
 	if len(resp.Events) == 0 {
 		cliutil.NoResults(os.Stdout, "No events received.")
-		if resp.DroppedCount > 0 {
-			cliutil.Hint(os.Stdout, fmt.Sprintf("Note: %d events were dropped due to buffer limit", resp.DroppedCount))
-		}
+		printPollNotes(resp)
 		return nil
 	}
 
@@ -127,9 +121,7 @@ func poll(mcpURL string, oastID, since, eventType string, wait time.Duration, li
 	t.Render()
 	cliutil.Summary(os.Stdout, len(resp.Events), "event", "events")
 
-	if resp.DroppedCount > 0 {
-		cliutil.Hint(os.Stdout, fmt.Sprintf("Note: %d events were dropped due to buffer limit", resp.DroppedCount))
-	}
+	printPollNotes(resp)
 
 	// Show hints for next actions
 	cliutil.HintCommand(os.Stdout, "To view event details", fmt.Sprintf("sectool oast get %s <event_id>", oastID))
@@ -141,6 +133,16 @@ func poll(mcpURL string, oastID, since, eventType string, wait time.Duration, li
 	return nil
 }
 
+// printPollNotes reports events the service dropped or could not decrypt.
+func printPollNotes(resp *protocol.OastPollResponse) {
+	if resp.DroppedCount > 0 {
+		cliutil.Hint(os.Stdout, fmt.Sprintf("Note: %d events were dropped due to buffer limit", resp.DroppedCount))
+	}
+	if resp.FailedCount > 0 {
+		fmt.Printf("%s %d interactions could not be decrypted or parsed and are not listed\n", cliutil.Warning("!"), resp.FailedCount)
+	}
+}
+
 func get(mcpURL string, oastID, eventID string) error {
 	ctx := context.Background()
 
@@ -161,31 +163,60 @@ func get(mcpURL string, oastID, eventID string) error {
 	fmt.Printf("Source IP: %s\n", resp.SourceIP)
 	fmt.Printf("Subdomain: %s\n", cliutil.ID(resp.Subdomain))
 
-	if len(resp.Details) > 0 {
-		fmt.Println()
-		for k, v := range resp.Details {
-			// Convert snake_case key to Title Case
-			title := strings.ReplaceAll(k, "_", " ")
-			words := strings.Fields(title)
-			for i, word := range words {
-				if len(word) > 0 {
-					words[i] = strings.ToUpper(word[:1]) + word[1:]
-				}
-			}
-			title = strings.Join(words, " ")
-
-			if s, ok := v.(string); ok && len(s) > 0 {
-				fmt.Printf("### %s\n\n", title)
-				fmt.Println("```")
-				fmt.Println(s)
-				fmt.Println("```")
-			} else {
-				fmt.Printf("%s: %v\n", title, v)
-			}
+	printEventDetails(resp.Details)
+	return nil
+}
+
+// eventDetailOrder lists the detail keys printed inline, then as blocks, before any others.
+var eventDetailOrder = []string{
+	"query_name", "query_type", "smtp_from",
+	"raw_request", "raw_response",
+}
+
+// printEventDetails prints event details in full: short values inline, raw request and
+// response data as fenced blocks.
+func printEventDetails(details map[string]interface{}) {
+	if len(details) == 0 {
+		return
+	}
+	var others []string
+	for k := range details {
+		if !slices.Contains(eventDetailOrder, k) {
+			others = append(others, k)
+		}
+	}
+	slices.Sort(others)
+	keys := make([]string, 0, len(details))
+	for _, k := range eventDetailOrder {
+		if _, ok := details[k]; ok {
+			keys = append(keys, k)
 		}
 	}
+	keys = append(keys, others...)
 
-	return nil
+	fmt.Println()
+	for _, k := range keys {
+		s, ok := details[k].(string)
+		if !ok || !strings.HasPrefix(k, "raw_") {
+			fmt.Printf("%s: %v\n", detailTitle(k), details[k])
+			continue
+		} else if s == "" {
+			continue
+		}
+		fmt.Printf("\n### %s\n\n", detailTitle(k))
+		fmt.Println("```")
+		fmt.Println(s)
+		fmt.Println("```")
+	}
+}
+
+// detailTitle converts a snake_case detail key to Title Case.
+func detailTitle(key string) string {
+	words := strings.Fields(strings.ReplaceAll(key, "_", " "))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
 }
 
 func list(mcpURL string, limit int) error {
@@ -51,12 +51,14 @@ type ProxyGetResponse struct {
 	ReqLine           *RequestLine        `json:"request_line,omitempty"`
 	ReqBody           string              `json:"request_body"`
 	ReqSize           int                 `json:"request_size"`
+	ReqTruncated      bool                `json:"request_truncated,omitempty"` // body hit max_body_bytes when captured
 	Status            int                 `json:"status"`
 	StatusLine        string              `json:"status_line"`
 	RespHeaders       string              `json:"response_headers"`
 	RespHeadersParsed map[string][]string `json:"response_headers_parsed,omitempty"`
 	RespBody          string              `json:"response_body"`
 	RespSize          int                 `json:"response_size"`
+	RespTruncated     bool                `json:"response_truncated,omitempty"` // body hit max_body_bytes when captured
 	Note              string              `json:"note,omitempty"`
 }
 
@@ -84,10 +86,24 @@ type ReplaySendResponse struct {
 	ResponseDetails
 }
 
+// FetchResponse is the response for fetch.
+type FetchResponse struct {
+	FlowID      string `json:"flow_id"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int    `json:"size"`
+	Duration    string `json:"duration"`
+}
+
 // ReplayGetResponse is the response for replay_get.
 type ReplayGetResponse struct {
 	ReplayID          string              `json:"replay_id"`
+	SourceFlowID      string              `json:"source_flow_id,omitempty"`
 	Duration          string              `json:"duration"`
+	ReqHeaders        string              `json:"request_headers"`
+	ReqBody           string              `json:"request_body"`
+	ReqSize           int                 `json:"request_size"`
+	Redirects         []ReplayRedirect    `json:"redirects,omitempty"`
 	Status            int                 `json:"status"`
 	StatusLine        string              `json:"status_line"`
 	RespHeaders       string              `json:"response_headers"`
@@ -96,6 +112,37 @@ type ReplayGetResponse struct {
 	RespSize          int                 `json:"response_size"`
 }
 
+// ReplayRedirect is one redirect followed while sending a replay.
+type ReplayRedirect struct {
+	Status   int    `json:"status"`
+	URL      string `json:"url"`
+	Location string `json:"location"`
+}
+
+// SmuggleProbeResponse is the response for replay_smuggle.
+type SmuggleProbeResponse struct {
+	Verdict  string         `json:"verdict"` // likely, possible, unlikely, inconclusive
+	Baseline SmuggleAttempt `json:"baseline"`
+	Probes   []SmuggleProbe `json:"probes"`
+	Note     string         `json:"note,omitempty"`
+}
+
+// SmuggleProbe is the outcome of one desync technique.
+type SmuggleProbe struct {
+	Technique string           `json:"technique"` // CL.TE or TE.CL
+	Verdict   string           `json:"verdict"`
+	Skipped   string           `json:"skipped,omitempty"` // reason when not sent
+	Attempts  []SmuggleAttempt `json:"attempts,omitempty"`
+}
+
+// SmuggleAttempt is a single timed request.
+type SmuggleAttempt struct {
+	Status   int    `json:"status,omitempty"`
+	Duration string `json:"duration"`
+	TimedOut bool   `json:"timed_out,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
 // =============================================================================
 // OAST Types
 // =============================================================================
@@ -120,6 +167,7 @@ type OastPollResponse struct {
 	Aggregates   []OastSummaryEntry `json:"aggregates,omitempty"` // summary mode
 	Events       []OastEvent        `json:"events,omitempty"`     // list mode
 	DroppedCount int                `json:"dropped_count,omitempty"`
+	FailedCount  int                `json:"failed_count,omitempty"` // interactions that could not be decrypted or parsed
 }
 
 // OastEvent represents a single OAST interaction event.
@@ -172,6 +220,9 @@ type RuleEntry struct {
 	IsRegex bool   `json:"is_regex,omitempty"`
 	Match   string `json:"match,omitempty"`
 	Replace string `json:"replace,omitempty"`
+	Host    string `json:"host,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Method  string `json:"method,omitempty"`
 }
 
 // =============================================================================
@@ -191,50 +242,135 @@ type CrawlSeedResponse struct {
 	AddedCount int `json:"added_count"`
 }
 
+// CrawlResumeResponse is the response for crawl_resume.
+type CrawlResumeResponse struct {
+	State        string `json:"state"`
+	URLsRequeued int    `json:"urls_requeued"`
+}
+
 // CrawlStatusResponse is the response for crawl_status.
 type CrawlStatusResponse struct {
-	State           string `json:"state"`
-	URLsQueued      int    `json:"urls_queued"`
-	URLsVisited     int    `json:"urls_visited"`
-	URLsErrored     int    `json:"urls_errored"`
-	FormsDiscovered int    `json:"forms_discovered"`
-	Duration        string `json:"duration"`
-	LastActivity    string `json:"last_activity"`
-	ErrorMessage    string `json:"error_message,omitempty"`
+	State               string              `json:"state"`
+	URLsQueued          int                 `json:"urls_queued"`
+	URLsVisited         int                 `json:"urls_visited"`
+	URLsErrored         int                 `json:"urls_errored"`
+	FormsDiscovered     int                 `json:"forms_discovered"`
+	Duration            string              `json:"duration"`
+	LastActivity        string              `json:"last_activity"`
+	ErrorMessage        string              `json:"error_message,omitempty"`
+	Hosts               []string            `json:"hosts,omitempty"`
+	SkippedHosts        []string            `json:"skipped_hosts,omitempty"`
+	HostQuotaSkips      map[string]int      `json:"host_quota_skips,omitempty"`      // host -> requests skipped by max_pages_per_host
+	MergedSlashVariants int                 `json:"merged_slash_variants,omitempty"` // trailing-slash variants skipped by merge_trailing_slash
+	BlockedNote         string              `json:"blocked_note,omitempty"`          // why block_threshold stopped the crawl
+	TokenRefreshes      []CrawlTokenRefresh `json:"token_refreshes,omitempty"`
+	DiscoveredPaths     []DiscoveredPath    `json:"discovered_paths,omitempty"` // recon hits not crawled, e.g. robots.txt Disallow
+}
+
+// DiscoveredPath is a URL found during crawl recon that the crawler did not visit.
+type DiscoveredPath struct {
+	URL    string `json:"url"`
+	Source string `json:"source"`
+}
+
+// CrawlTokenRefresh is one call to the token_refresh endpoint during a crawl.
+type CrawlTokenRefresh struct {
+	Time       string `json:"time"`
+	TriggerURL string `json:"trigger_url"`
+	Error      string `json:"error,omitempty"`
+}
+
+// CrawlStatsResponse is the response for crawl_stats.
+type CrawlStatsResponse struct {
+	SessionID    string             `json:"session_id"`
+	TotalFlows   int                `json:"total_flows"`
+	StatusCodes  []StatusCodeCount  `json:"status_codes"`
+	ContentTypes []ContentTypeCount `json:"content_types"`
+	Search       CrawlSearchStats   `json:"search"`
+}
+
+// CrawlSearchStats reports flow search activity and latency for a crawl session.
+type CrawlSearchStats struct {
+	IndexEnabled    bool   `json:"index_enabled"`
+	IndexedTokens   int    `json:"indexed_tokens,omitempty"`
+	Searches        int    `json:"searches"`
+	IndexedSearches int    `json:"indexed_searches"`
+	ScanSearches    int    `json:"scan_searches"`
+	AvgLatency      string `json:"avg_latency,omitempty"`
+	MaxLatency      string `json:"max_latency,omitempty"`
+}
+
+// StatusCodeCount is the number of flows with a given status code.
+type StatusCodeCount struct {
+	Status int `json:"status"`
+	Count  int `json:"count"`
+}
+
+// ContentTypeCount is the number of flows with a given response media type.
+type ContentTypeCount struct {
+	ContentType string `json:"content_type"`
+	Count       int    `json:"count"`
 }
 
 // CrawlPollResponse is the unified response for crawl_poll.
 type CrawlPollResponse struct {
-	SessionID  string         `json:"session_id"`
-	State      string         `json:"state,omitempty"`
-	Duration   string         `json:"duration,omitempty"` // summary only
-	Aggregates []SummaryEntry `json:"aggregates,omitempty"`
-	Flows      []CrawlFlow    `json:"flows,omitempty"`
-	Forms      []CrawlForm    `json:"forms,omitempty"`
-	Errors     []CrawlError   `json:"errors,omitempty"`
-	Note       string         `json:"note,omitempty"`
+	SessionID      string           `json:"session_id"`
+	State          string           `json:"state,omitempty"`
+	Duration       string           `json:"duration,omitempty"`         // summary only
+	Hosts          []string         `json:"hosts,omitempty"`            // summary only
+	SkippedHosts   []string         `json:"skipped_hosts,omitempty"`    // summary only
+	HostQuotaSkips map[string]int   `json:"host_quota_skips,omitempty"` // summary only
+	Aggregates     []SummaryEntry   `json:"aggregates,omitempty"`
+	Flows          []CrawlFlow      `json:"flows,omitempty"`
+	Forms          []CrawlForm      `json:"forms,omitempty"`
+	Errors         []CrawlError     `json:"errors,omitempty"`
+	External       []ExternalLink   `json:"external_links,omitempty"`
+	Routes         []ClientRoute    `json:"routes,omitempty"`
+	Endpoints      []JSEndpoint     `json:"endpoints,omitempty"`
+	Similar        []SimilarCluster `json:"similar,omitempty"`
+	Findings       []CrawlFinding   `json:"findings,omitempty"`
+	Note           string           `json:"note,omitempty"`
 }
 
 // CrawlFlow is a crawled request/response summary.
 type CrawlFlow struct {
-	FlowID         string `json:"flow_id"`
-	Method         string `json:"method"`
-	Host           string `json:"host"`
-	Path           string `json:"path"`
-	Status         int    `json:"status"`
-	ResponseLength int    `json:"response_length"`
-	Duration       string `json:"duration"`
-	FoundOn        string `json:"found_on,omitempty"`
+	FlowID         string   `json:"flow_id"`
+	Method         string   `json:"method"`
+	Host           string   `json:"host"`
+	Path           string   `json:"path"`
+	Status         int      `json:"status"`
+	ResponseLength int      `json:"response_length"`
+	Duration       string   `json:"duration"`
+	FoundOn        string   `json:"found_on,omitempty"`
+	Findings       []string `json:"findings,omitempty"`
+	VariantOf      string   `json:"variant_of,omitempty"`      // flow_id this Vary variant re-requested
+	VariedHeader   string   `json:"varied_header,omitempty"`   // request header changed from variant_of
+	RedirectTo     string   `json:"redirect_to,omitempty"`     // resolved Location of a 3xx response
+	RedirectedFrom string   `json:"redirected_from,omitempty"` // flow_id of the redirect hop that led here
 }
 
 // CrawlForm is a discovered form.
 type CrawlForm struct {
-	FormID  string      `json:"form_id"`
-	URL     string      `json:"url"`
-	Action  string      `json:"action"`
-	Method  string      `json:"method"`
-	HasCSRF bool        `json:"has_csrf"`
-	Inputs  []FormInput `json:"inputs"`
+	FormID            string           `json:"form_id"`
+	URL               string           `json:"url"`
+	Action            string           `json:"action"`
+	Method            string           `json:"method"`
+	HasCSRF           bool             `json:"has_csrf"`
+	HasJSHandlers     bool             `json:"has_js_handlers,omitempty"`
+	AutoSubmit        bool             `json:"auto_submit,omitempty"`
+	Inputs            []FormInput      `json:"inputs"`
+	MethodCheck       *FormMethodCheck `json:"method_check,omitempty"`
+	SubmissionFlowIDs []string         `json:"submission_flow_ids,omitempty"`
+}
+
+// FormMethodCheck compares a form's responses when submitted as GET and as POST.
+type FormMethodCheck struct {
+	GetFlowID           string `json:"get_flow_id,omitempty"`
+	PostFlowID          string `json:"post_flow_id,omitempty"`
+	GetStatus           int    `json:"get_status,omitempty"`
+	PostStatus          int    `json:"post_status,omitempty"`
+	Verdict             string `json:"verdict"`
+	WrongMethodAccepted bool   `json:"wrong_method_accepted,omitempty"`
 }
 
 // FormInput is a form input field.
@@ -245,13 +381,71 @@ type FormInput struct {
 	Required bool   `json:"required,omitempty"`
 }
 
+// ExternalLink is an out-of-scope link discovered (but not fetched) during crawling.
+type ExternalLink struct {
+	URL     string `json:"url"`
+	Host    string `json:"host"`
+	FoundOn string `json:"found_on"`
+}
+
+// SimilarCluster groups near-identical crawled responses (same host, status and media type).
+type SimilarCluster struct {
+	RepresentativeID string   `json:"representative_flow_id"`
+	Host             string   `json:"host"`
+	Path             string   `json:"path"`
+	Status           int      `json:"status"`
+	ContentType      string   `json:"content_type,omitempty"`
+	Size             int      `json:"size"`
+	FlowIDs          []string `json:"flow_ids"` // representative first; capped, size has the full count
+}
+
+// ClientRoute is a client-side (SPA) route declared in a crawled script (never fetched).
+type ClientRoute struct {
+	Route   string `json:"route"`
+	Kind    string `json:"kind"` // route-table, hashbang, or history
+	FoundOn string `json:"found_on"`
+}
+
+// SensitiveMatch is a secret or other sensitive value found in a crawled response body.
+type SensitiveMatch struct {
+	Pattern string `json:"pattern"` // e.g. aws-access-key, jwt, email
+	Offset  int    `json:"offset"`  // byte offset in the response body
+	Snippet string `json:"snippet"` // match with its middle masked, in context
+}
+
+// CrawlFinding is a passive detection on a crawled flow; sensitive-data findings are
+// listed once per match.
+type CrawlFinding struct {
+	FlowID  string `json:"flow_id"`
+	URL     string `json:"url"`
+	Finding string `json:"finding"`
+	Pattern string `json:"pattern,omitempty"`
+	Offset  int    `json:"offset,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// JSEndpoint is a request URL found in a crawled script.
+type JSEndpoint struct {
+	URL     string `json:"url"`
+	Method  string `json:"method,omitempty"` // declared by axios.<method> or XHR open
+	Kind    string `json:"kind"`             // fetch, axios, xhr, or string
+	FoundOn string `json:"found_on"`
+}
+
 // CrawlError is a crawl error.
 type CrawlError struct {
+	FlowID string `json:"flow_id,omitempty"`
 	URL    string `json:"url"`
 	Status int    `json:"status,omitempty"`
 	Error  string `json:"error"`
 }
 
+// CrawlDeleteResponse is the response for crawl_delete.
+type CrawlDeleteResponse struct {
+	Deleted        int  `json:"deleted"`
+	SessionDeleted bool `json:"session_deleted,omitempty"`
+}
+
 // CrawlSessionsResponse is the response for crawl_sessions.
 type CrawlSessionsResponse struct {
 	Sessions []CrawlSession `json:"sessions"`
@@ -271,6 +465,18 @@ type CrawlGetResponse struct {
 	Method            string              `json:"method"`
 	URL               string              `json:"url"`
 	FoundOn           string              `json:"found_on,omitempty"`
+	VariantOf         string              `json:"variant_of,omitempty"`
+	VariedHeader      string              `json:"varied_header,omitempty"`
+	ReferrerPolicy    string              `json:"referrer_policy,omitempty"`
+	OriginalURL       string              `json:"original_url,omitempty"`
+	JSONPCallback     string              `json:"jsonp_callback,omitempty"`
+	HTMLComments      []string            `json:"html_comments,omitempty"`
+	CookieBefore      string              `json:"cookie_before,omitempty"`
+	CookieAfter       string              `json:"cookie_after,omitempty"`
+	RedirectTo        string              `json:"redirect_to,omitempty"`
+	RedirectedFrom    string              `json:"redirected_from,omitempty"`
+	RedirectChain     []string            `json:"redirect_chain,omitempty"`
+	SensitiveData     []SensitiveMatch    `json:"sensitive_data,omitempty"`
 	Depth             int                 `json:"depth"`
 	ReqHeaders        string              `json:"request_headers"`
 	ReqHeadersParsed  map[string][]string `json:"request_headers_parsed,omitempty"`
@@ -283,10 +489,61 @@ type CrawlGetResponse struct {
 	RespBody          string              `json:"response_body"`
 	RespSize          int                 `json:"response_size"`
 	Truncated         bool                `json:"truncated,omitempty"`
+	HeadersTruncated  bool                `json:"headers_truncated,omitempty"` // headers hit max_header_bytes when captured
+	Findings          []string            `json:"findings,omitempty"`
 	Duration          string              `json:"duration"`
 	Note              string              `json:"note,omitempty"`
 }
 
+// StructuredFlow is a decoded, parsed view of a crawl flow for crawl_get format=structured.
+type StructuredFlow struct {
+	FlowID    string             `json:"flow_id"`
+	URL       string             `json:"url"`
+	FoundOn   string             `json:"found_on,omitempty"`
+	Findings  []string           `json:"findings,omitempty"`
+	Truncated bool               `json:"truncated,omitempty"`
+	Duration  string             `json:"duration"`
+	Request   StructuredRequest  `json:"request"`
+	Response  StructuredResponse `json:"response"`
+}
+
+// StructuredRequest is a parsed request. Body is the decoded text (omitted when JSON is set).
+type StructuredRequest struct {
+	Method      string              `json:"method"`
+	Path        string              `json:"path"`
+	Version     string              `json:"version"`
+	Headers     map[string][]string `json:"headers"`
+	Query       map[string][]string `json:"query,omitempty"`
+	Form        map[string][]string `json:"form,omitempty"`
+	ContentType string              `json:"content_type,omitempty"`
+	JSON        interface{}         `json:"json,omitempty"`
+	Body        string              `json:"body,omitempty"`
+}
+
+// StructuredResponse is a parsed response. Body is the decoded text (omitted when JSON is set).
+type StructuredResponse struct {
+	Status      int                 `json:"status"`
+	StatusLine  string              `json:"status_line"`
+	Headers     map[string][]string `json:"headers"`
+	ContentType string              `json:"content_type,omitempty"`
+	JSON        interface{}         `json:"json,omitempty"`
+	Body        string              `json:"body,omitempty"`
+}
+
+// ExtractLinksResponse is the response for extract_links.
+type ExtractLinksResponse struct {
+	FlowID      string          `json:"flow_id"`
+	BaseURL     string          `json:"base_url,omitempty"` // URL relative references were resolved against
+	ContentType string          `json:"content_type,omitempty"`
+	Links       []ExtractedLink `json:"links"`
+}
+
+// ExtractedLink is a URL found in a flow's response.
+type ExtractedLink struct {
+	URL    string `json:"url"`
+	Source string `json:"source"` // anchor, form, script, json, css, comment
+}
+
 // =============================================================================
 // Cookie Types
 // =============================================================================
@@ -321,6 +578,21 @@ type DiffFlowResponse struct {
 	Response *ResponseDiff `json:"response,omitempty"`
 }
 
+// DiffMultiResponse is the response for diff_multi.
+type DiffMultiResponse struct {
+	FlowIDs        []string         `json:"flow_ids"`
+	Same           bool             `json:"same,omitempty"`
+	Fields         []MultiDiffField `json:"fields,omitempty"`
+	UnchangedCount int              `json:"unchanged_count,omitempty"`
+	Truncated      bool             `json:"truncated,omitempty"`
+}
+
+// MultiDiffField is a field that differs across flows, with its value in each flow.
+type MultiDiffField struct {
+	Field  string    `json:"field"`
+	Values []*string `json:"values"` // in flow_ids order; nil when the flow lacks the field
+}
+
 // RequestDiff contains differences in the request.
 type RequestDiff struct {
 	Method  *ABPair     `json:"method,omitempty"`
@@ -328,6 +600,7 @@ type RequestDiff struct {
 	Query   *ParamsDiff `json:"query,omitempty"`
 	Headers *ParamsDiff `json:"headers,omitempty"`
 	Body    *BodyDiff   `json:"body,omitempty"`
+	Raw     *BodyDiff   `json:"raw,omitempty"` // request_raw scope only
 }
 
 // ResponseDiff contains differences in the response.
@@ -335,6 +608,7 @@ type ResponseDiff struct {
 	Status  *ABIntPair  `json:"status,omitempty"`
 	Headers *ParamsDiff `json:"headers,omitempty"`
 	Body    *BodyDiff   `json:"body,omitempty"`
+	Raw     *BodyDiff   `json:"raw,omitempty"` // response_raw scope only
 }
 
 // ABPair represents a string value that differs between flow A and B.
@@ -418,14 +692,93 @@ type PathABChange struct {
 
 // FindReflectedResponse is the response for find_reflected.
 type FindReflectedResponse struct {
-	Reflections []Reflection `json:"reflections"`
+	Reflections   []Reflection `json:"reflections"`
+	Truncated     bool         `json:"truncated,omitempty"`      // more reflections exist beyond limit
+	JSONPCallback string       `json:"jsonp_callback,omitempty"` // parameter whose value the JavaScript response calls
 }
 
 // Reflection represents a request parameter value found in the response.
 type Reflection struct {
-	Name         string   `json:"name"`
-	Source       string   `json:"source"`
-	Value        string   `json:"value"`
-	Locations    []string `json:"locations"`
-	RawReflected bool     `json:"raw_reflected,omitempty"` // value has special chars and appears unencoded
+	Name         string            `json:"name"`
+	Source       string            `json:"source"`
+	Value        string            `json:"value"`
+	Locations    []string          `json:"locations"`
+	Encodings    map[string]string `json:"encodings,omitempty"`     // location -> matched encoding; "none" is an unencoded reflection
+	RawReflected bool              `json:"raw_reflected,omitempty"` // value has special chars and appears unencoded
+
+	Occurrences       map[string]int `json:"occurrences,omitempty"` // location -> number of matches there
+	OccurrenceCount   int            `json:"occurrence_count,omitempty"`
+	OccurrencesCapped bool           `json:"occurrences_capped,omitempty"` // per-location counts stop short of occurrence_count
+}
+
+// SecurityHeadersResponse is the response for security_headers.
+type SecurityHeadersResponse struct {
+	FlowID string                `json:"flow_id"`
+	Checks []SecurityHeaderCheck `json:"checks"`
+}
+
+// SecurityHeaderCheck is the verdict for one security header or Set-Cookie.
+type SecurityHeaderCheck struct {
+	Name   string `json:"name"`   // header name, or "Set-Cookie: <cookie name>"
+	Status string `json:"status"` // pass, warn, or fail
+	Value  string `json:"value,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ProxyInterceptResponse is the response for proxy_intercept.
+type ProxyInterceptResponse struct {
+	Enabled  bool               `json:"enabled"`
+	Host     string             `json:"host,omitempty"`
+	Path     string             `json:"path,omitempty"`
+	Method   string             `json:"method,omitempty"`
+	Held     []HeldRequestEntry `json:"held"`
+	Released int                `json:"released,omitempty"`
+	Dropped  int                `json:"dropped,omitempty"`
+}
+
+// HeldRequestEntry is a proxied request held by intercept.
+type HeldRequestEntry struct {
+	HeldID  string `json:"held_id"`
+	Method  string `json:"method"`
+	Host    string `json:"host"`
+	Path    string `json:"path"`
+	HeldFor string `json:"held_for"`
+}
+
+// =============================================================================
+// Kill Switch Types
+// =============================================================================
+
+// KillSwitchResponse is the response for kill_switch.
+type KillSwitchResponse struct {
+	Engaged       bool   `json:"engaged"`
+	Reason        string `json:"reason,omitempty"`
+	EngagedAt     string `json:"engaged_at,omitempty"`
+	StoppedCrawls int    `json:"stopped_crawls,omitempty"`
+	ProxyHalted   bool   `json:"proxy_halted,omitempty"`
+}
+
+// =============================================================================
+// Server Status Types
+// =============================================================================
+
+// ServerStatusResponse is the response for server_status.
+type ServerStatusResponse struct {
+	Version      string            `json:"version"`
+	PID          int               `json:"pid"`
+	MCPPort      int               `json:"mcp_port"`
+	ProxyPort    int               `json:"proxy_port,omitempty"` // built-in proxy only
+	Backend      string            `json:"backend"`              // "native" or "burp"
+	StartedAt    string            `json:"started_at"`
+	Uptime       string            `json:"uptime"`
+	ActiveCrawls int               `json:"active_crawls"`
+	KillSwitch   bool              `json:"kill_switch,omitempty"`
+	Stores       map[string]string `json:"stores,omitempty"` // health metric key to value, e.g. flows, replay_history
+}
+
+// ServerStopResponse is the response for server_stop.
+type ServerStopResponse struct {
+	PID            int `json:"pid"`
+	FinishedCrawls int `json:"finished_crawls"`
+	StoppedCrawls  int `json:"stopped_crawls"`
 }
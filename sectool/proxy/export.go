@@ -3,49 +3,82 @@ package proxy
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/go-appsec/toolbox/sectool/bundle"
+	"github.com/go-appsec/toolbox/sectool/cliutil"
 	"github.com/go-appsec/toolbox/sectool/mcpclient"
+	"github.com/go-appsec/toolbox/sectool/protocol"
 )
 
-func export(mcpURL string, flowID string) error {
+const (
+	exportFormatBundle = "bundle"
+	exportFormatHAR    = "har"
+)
+
+// exportedFlow is a proxy flow fetched with full, decoded bodies.
+type exportedFlow struct {
+	*protocol.ProxyGetResponse
+	reqBody, respBody []byte
+}
+
+func (f exportedFlow) harEntry() bundle.HAREntry {
+	return bundle.HAREntry{
+		URL:         f.URL,
+		Method:      f.Method,
+		ReqHeaders:  f.ReqHeaders,
+		ReqBody:     f.reqBody,
+		RespHeaders: f.RespHeaders,
+		RespBody:    f.respBody,
+	}
+}
+
+func export(mcpURL string, flowID, format, out string, redact bool, extraRedactHeaders []string) error {
 	ctx := context.Background()
 
+	redactHeaders := exportRedactHeaders(redact, extraRedactHeaders)
+
 	client, err := mcpclient.Connect(ctx, mcpURL)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = client.Close() }()
 
-	resp, err := client.ProxyGet(ctx, flowID, mcpclient.ProxyGetOpts{FullBody: true})
+	flow, err := fetchFlow(ctx, client, flowID)
 	if err != nil {
-		return fmt.Errorf("get flow: %w", err)
-	}
-
-	reqBody, err := bundle.DecodeBase64Body(resp.ReqBody)
-	if err != nil {
-		return fmt.Errorf("decode request body: %w", err)
+		return err
 	}
 
-	respBody, err := bundle.DecodeBase64Body(resp.RespBody)
-	if err != nil {
-		return fmt.Errorf("decode response body: %w", err)
+	if format == exportFormatHAR {
+		if out == "" {
+			out = filepath.Join(bundle.DefaultDir, flowID+".har")
+		}
+		if err := bundle.WriteHAR(out, []bundle.HAREntry{flow.harEntry()}, redactHeaders); err != nil {
+			return err
+		}
+		fmt.Printf("Exported flow `%s` to `%s`\n", flowID, out)
+		printRedactNote(redactHeaders, format)
+		return nil
 	}
 
 	bundleDir, err := bundle.Write(flowID,
-		resp.URL, resp.Method, resp.ReqHeaders, reqBody,
-		resp.RespHeaders, respBody)
+		flow.URL, flow.Method, flow.ReqHeaders, flow.reqBody,
+		flow.RespHeaders, flow.respBody, redactHeaders)
 	if err != nil {
 		return fmt.Errorf("write bundle: %w", err)
 	}
 
 	fmt.Printf("Exported flow `%s` to `%s/`\n", flowID, bundleDir)
+	printRedactNote(redactHeaders, format)
 	fmt.Println()
 	fmt.Println("Files:")
 	fmt.Println("- request.http - HTTP request headers")
 	fmt.Println("- body - request body (edit this)")
 	fmt.Println("- request.meta.json - metadata")
-	if resp.RespHeaders != "" {
+	if flow.RespHeaders != "" {
 		fmt.Println("- response.http - response headers")
 		fmt.Println("- response.body - response body")
 	}
@@ -54,3 +87,121 @@ func export(mcpURL string, flowID string) error {
 
 	return nil
 }
+
+// exportBulk exports every flow matching the proxy list filters, either as bundles
+// recorded in the bundle index or as a single HAR file.
+func exportBulk(mcpURL string, filters mcpclient.ProxyPollOpts, format, out string, redact bool, extraRedactHeaders []string) error {
+	ctx := context.Background()
+
+	redactHeaders := exportRedactHeaders(redact, extraRedactHeaders)
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	filters.OutputMode = "flows"
+	list, err := client.ProxyPoll(ctx, filters)
+	if err != nil {
+		return fmt.Errorf("list flows: %w", err)
+	} else if len(list.Flows) == 0 {
+		cliutil.NoResults(os.Stdout, "No matching flows to export.")
+		return nil
+	}
+
+	flows := make([]exportedFlow, 0, len(list.Flows))
+	for _, entry := range list.Flows {
+		flow, err := fetchFlow(ctx, client, entry.FlowID)
+		if err != nil {
+			return fmt.Errorf("export flow %s: %w", entry.FlowID, err)
+		}
+		flows = append(flows, flow)
+	}
+
+	if format == exportFormatHAR {
+		if out == "" {
+			out = filepath.Join(bundle.DefaultDir, "proxy-export.har")
+		}
+		entries := make([]bundle.HAREntry, 0, len(flows))
+		for _, flow := range flows {
+			entries = append(entries, flow.harEntry())
+		}
+		if err := bundle.WriteHAR(out, entries, redactHeaders); err != nil {
+			return err
+		}
+		fmt.Printf("Exported %d flows to `%s`\n", len(flows), out)
+		printRedactNote(redactHeaders, format)
+		return nil
+	}
+
+	exportedAt := time.Now().UTC().Format(time.RFC3339)
+	entries := make([]bundle.IndexEntry, 0, len(flows))
+	for _, flow := range flows {
+		if _, err := bundle.Write(flow.FlowID,
+			flow.URL, flow.Method, flow.ReqHeaders, flow.reqBody,
+			flow.RespHeaders, flow.respBody, redactHeaders); err != nil {
+			return fmt.Errorf("write bundle %s: %w", flow.FlowID, err)
+		}
+		entries = append(entries, bundle.IndexEntry{
+			FlowID:     flow.FlowID,
+			URL:        flow.URL,
+			Method:     flow.Method,
+			Status:     flow.Status,
+			ExportedAt: exportedAt,
+		})
+	}
+
+	indexPath, err := bundle.UpdateIndex(entries)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d flows to `%s/`\n", len(entries), bundle.DefaultDir)
+	printRedactNote(redactHeaders, format)
+	fmt.Println()
+	for _, e := range entries {
+		fmt.Printf("- %s %s %s (%s)\n", cliutil.ID(e.FlowID), e.Method, e.URL, cliutil.FormatStatus(e.Status))
+	}
+	fmt.Println()
+	fmt.Printf("Index: `%s`\n", indexPath)
+
+	return nil
+}
+
+// fetchFlow gets a proxy flow with full bodies and decodes them.
+func fetchFlow(ctx context.Context, client *mcpclient.Client, flowID string) (exportedFlow, error) {
+	resp, err := client.ProxyGet(ctx, flowID, mcpclient.ProxyGetOpts{FullBody: true})
+	if err != nil {
+		return exportedFlow{}, fmt.Errorf("get flow: %w", err)
+	}
+
+	reqBody, err := bundle.DecodeBase64Body(resp.ReqBody)
+	if err != nil {
+		return exportedFlow{}, fmt.Errorf("decode request body: %w", err)
+	}
+
+	respBody, err := bundle.DecodeBase64Body(resp.RespBody)
+	if err != nil {
+		return exportedFlow{}, fmt.Errorf("decode response body: %w", err)
+	}
+
+	return exportedFlow{ProxyGetResponse: resp, reqBody: reqBody, respBody: respBody}, nil
+}
+
+func exportRedactHeaders(redact bool, extra []string) []string {
+	if !redact {
+		return nil
+	}
+	return append(slices.Clone(bundle.DefaultRedactHeaders), extra...)
+}
+
+func printRedactNote(redactHeaders []string, format string) {
+	if len(redactHeaders) == 0 {
+		return
+	} else if format == exportFormatHAR {
+		fmt.Println(cliutil.Muted("Sensitive header values redacted in the HAR file"))
+		return
+	}
+	fmt.Println(cliutil.Muted("Sensitive header values redacted (recorded in request.meta.json)"))
+}
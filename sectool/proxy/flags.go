@@ -8,9 +8,10 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/go-appsec/toolbox/sectool/cliutil"
+	"github.com/go-appsec/toolbox/sectool/mcpclient"
 )
 
-var proxySubcommands = []string{"summary", "list", "get", "cookies", "export", "rule", "help"}
+var proxySubcommands = []string{"summary", "list", "get", "cookies", "export", "rule", "intercept", "help"}
 
 func Parse(args []string, mcpURL string) error {
 	if len(args) < 1 {
@@ -31,6 +32,8 @@ func Parse(args []string, mcpURL string) error {
 		return parseExport(args[1:], mcpURL)
 	case "rule":
 		return parseRule(args[1:], mcpURL)
+	case "intercept":
+		return parseIntercept(args[1:], mcpURL)
 	case "help", "--help", "-h":
 		printUsage()
 		return nil
@@ -141,7 +144,7 @@ proxy cookies [options]
 
 ---
 
-proxy export <flow_id>
+proxy export [flow_id] [options]
 
   Export a captured request to disk for editing and replay.
   Note: Prefer 'replay send --flow' with modification flags for simple changes.
@@ -155,12 +158,26 @@ proxy export <flow_id>
     body               request body (edit this for modifications)
     request.meta.json  metadata (method, URL, timestamps)
 
+  Options:
+    --redact                mask Authorization, Proxy-Authorization, Cookie, Set-Cookie
+    --redact-header <name>  additional header to mask (repeatable, implies --redact)
+    --format <fmt>          bundle (default) or har
+    --out <path>            HAR output path (with --format har)
+    --all                   export the whole history when no flow_id or filter is given
+
+  Without a flow_id, exports all flows matching the 'proxy list' filters
+  (--host, --path, --method, --status, --since, ...) and updates
+  sectool-requests/index.json, or writes one HAR file with --format har.
+  Exporting the whole history without a filter requires --all.
+
   Examples:
     sectool proxy list --host example.com     # find flow_id
     sectool proxy export f7k2x                # exports to sectool-requests/f7k2x/
+    sectool proxy export f7k2x --redact       # mask credentials for sharing
+    sectool proxy export --host example.com --format har --out example.har
     sectool replay send --bundle f7k2x        # replay the exported bundle
 
-  Output: Bundle path and files created
+  Output: Bundle path and files created, or HAR path
 
 ---
 
@@ -211,6 +228,32 @@ proxy rule delete <rule_id>
   Examples:
     sectool proxy rule delete abc123
     sectool proxy rule delete my-rule
+
+---
+
+proxy intercept <on|off|list|release|drop> [options]
+
+  Hold proxied browser requests until released. Intercept stays on across
+  invocations until turned off. Held requests are dropped after 5 minutes.
+
+  Commands:
+    on         Hold matching requests (all when no filter is given)
+    off        Stop holding and forward all held requests
+    list       Show intercept state and held requests (default)
+    release    Forward a held request, or all held requests
+    drop       Refuse a held request (browser gets 502), or all held requests
+
+  Options (on):
+    --host <pattern>        host glob pattern (*, ?)
+    --path <pattern>        path glob pattern (*, ?)
+    --method <method>       HTTP method
+
+  Examples:
+    sectool proxy intercept on --host api.example.com --method POST
+    sectool proxy intercept list
+    sectool proxy intercept release h7k2x     # forward one held request
+    sectool proxy intercept drop              # drop all held requests
+    sectool proxy intercept off
 `)
 }
 
@@ -338,9 +381,32 @@ Options:
 func parseExport(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("proxy export", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
+	var redact, all bool
+	var redactHeaders []string
+	var format, out string
+	var filters mcpclient.ProxyPollOpts
+
+	fs.BoolVar(&redact, "redact", false, "mask Authorization, Proxy-Authorization, Cookie, and Set-Cookie header values")
+	fs.StringArrayVar(&redactHeaders, "redact-header", nil, "additional header to mask (can specify multiple times, implies --redact)")
+	fs.StringVar(&format, "format", exportFormatBundle, "output format: 'bundle' or 'har'")
+	fs.StringVar(&out, "out", "", "HAR output path (default sectool-requests/<flow_id>.har or sectool-requests/proxy-export.har)")
+	fs.StringVar(&filters.Source, "source", "", "bulk: filter by source: 'proxy', 'replay', or empty for both")
+	fs.StringVar(&filters.Host, "host", "", "bulk: filter by host pattern (glob: *, ?)")
+	fs.StringVar(&filters.Path, "path", "", "bulk: filter by path pattern (glob: *, ?)")
+	fs.StringVar(&filters.Method, "method", "", "bulk: filter by HTTP method (comma-separated)")
+	fs.StringVar(&filters.Status, "status", "", "bulk: filter by status code (e.g., 200,4XX)")
+	fs.StringVar(&filters.SearchHeader, "search-header", "", "bulk: regex search in request/response headers (RE2)")
+	fs.StringVar(&filters.SearchBody, "search-body", "", "bulk: regex search in request/response body (RE2)")
+	fs.StringVar(&filters.Since, "since", "", "bulk: filter since flow_id or 'last'")
+	fs.StringVar(&filters.ExcludeHost, "exclude-host", "", "bulk: exclude hosts matching pattern")
+	fs.StringVar(&filters.ExcludePath, "exclude-path", "", "bulk: exclude paths matching pattern")
+	fs.IntVar(&filters.Limit, "limit", 0, "bulk: maximum number of flows to export")
+	fs.IntVar(&filters.Offset, "offset", 0, "bulk: skip first N matching flows")
+	fs.BoolVar(&all, "all", false, "bulk: export the whole proxy history (required without a flow_id or filter)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy export <flow_id> [options]
+       sectool proxy export [filter options] [options]
 
 Export a flow to disk for editing and replay.
 Note: Prefer 'replay send --flow' with modification flags for simple changes.
@@ -363,6 +429,17 @@ After replay, response files are added:
 
 Edit body for body modifications; Content-Length is auto-updated on replay.
 
+Without a flow_id, every flow matching the 'proxy list' filters is exported
+and recorded in sectool-requests/index.json. With --format har the flows are
+written to a single HAR 1.2 file instead of bundles. Exporting the whole
+history without a filter requires --all.
+
+Examples:
+  sectool proxy export f7k2x --format har
+  sectool proxy export --host api.example.com --method POST
+  sectool proxy export --host api.example.com --format har --out api.har --redact
+  sectool proxy export --all --format har
+
 Options:
 `)
 		fs.PrintDefaults()
@@ -370,12 +447,29 @@ Options:
 
 	if err := fs.Parse(args); err != nil {
 		return err
-	} else if len(fs.Args()) < 1 {
-		fs.Usage()
-		return errors.New("flow_id required (get from 'sectool proxy list' with filters)")
+	} else if format != exportFormatBundle && format != exportFormatHAR {
+		return fmt.Errorf("invalid --format %q: must be 'bundle' or 'har'", format)
+	} else if out != "" && format != exportFormatHAR {
+		return errors.New("--out requires --format har")
+	}
+	redact = redact || len(redactHeaders) > 0
+
+	if len(fs.Args()) > 0 {
+		if all {
+			return errors.New("--all cannot be combined with a flow_id")
+		}
+		return export(mcpURL, fs.Args()[0], format, out, redact, redactHeaders)
 	}
 
-	return export(mcpURL, fs.Args()[0])
+	if filters == (mcpclient.ProxyPollOpts{}) {
+		if !all {
+			fs.Usage()
+			return errors.New("flow_id, a filter option, or --all required")
+		}
+		filters.Limit = 1_000_000_000 // MCP refuses list with no limits or filters
+	}
+
+	return exportBulk(mcpURL, filters, format, out, redact, redactHeaders)
 }
 
 var ruleSubcommands = []string{"list", "add", "delete", "help"}
@@ -446,14 +540,17 @@ func parseRuleAdd(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("proxy rule add", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
 	var isRegex bool
-	var ruleType, label, name, match, replace string
+	var ruleType, label, name, match, replace, host, path, method string
 
 	fs.StringVar(&ruleType, "type", "request_header", "rule type")
 	fs.BoolVar(&isRegex, "regex", false, "treat match as regex pattern")
 	fs.StringVar(&label, "label", "", "optional label for easier reference")
 	fs.StringVar(&name, "name", "", "alias for --label")
 	fs.StringVar(&match, "match", "", "pattern to match")
-	fs.StringVar(&replace, "replace", "", "replacement string")
+	fs.StringVar(&replace, "replace", "", "replacement string; redirect target URL for redirect")
+	fs.StringVar(&host, "host", "", "host glob scoping block/redirect rules")
+	fs.StringVar(&path, "path", "", "path glob scoping block/redirect rules")
+	fs.StringVar(&method, "method", "", "HTTP method scoping block/redirect rules")
 	_ = fs.MarkHidden("name")
 
 	fs.Usage = func() {
@@ -463,8 +560,13 @@ Add a match/replace rule.
 For header add: only replace is needed (adds header).
 For replacements: both match and replace are needed.
 
+For block/redirect: no match; scope with --host, --path and --method
+(built-in proxy only). Matching requests are answered by the proxy and
+not recorded in history.
+
 Types:
   HTTP:      request_header (default), request_body, response_header, response_body
+  Actions:   block (403), redirect (302 to replace)
   WebSocket: ws:to-server, ws:to-client, ws:both
 
 Examples:
@@ -472,6 +574,9 @@ Examples:
   sectool proxy rule add --type response_header "X-Frame-Options: DENY" # Add response header
   sectool proxy rule add --regex "^User-Agent.*$" "User-Agent: X"       # Replace User-Agent
   sectool proxy rule add --type ws:both "old" "new"                     # WebSocket replacement
+  sectool proxy rule add --type block --host "*.tracker.com"            # Block a host
+  sectool proxy rule add --type redirect --method GET --path /login \
+    https://staging.example.com/login                                  # Redirect a path
 
 Options:
 `)
@@ -498,7 +603,16 @@ Options:
 		}
 	}
 
-	return ruleAdd(mcpURL, ruleType, match, replace, label, isRegex)
+	return ruleAdd(mcpURL, mcpclient.RuleAddOpts{
+		Type:    ruleType,
+		Match:   match,
+		Replace: replace,
+		Label:   label,
+		IsRegex: isRegex,
+		Host:    host,
+		Path:    path,
+		Method:  method,
+	})
 }
 
 func parseRuleDelete(args []string, mcpURL string) error {
@@ -554,3 +668,60 @@ Options:
 
 	return cookies(mcpURL, name, domain)
 }
+
+var interceptSubcommands = []string{"on", "off", "list", "release", "drop", "help"}
+
+func parseIntercept(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("proxy intercept", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+	var opts mcpclient.InterceptOpts
+
+	fs.StringVar(&opts.Host, "host", "", "on: hold hosts matching pattern (glob: *, ?)")
+	fs.StringVar(&opts.Path, "path", "", "on: hold paths matching pattern (glob: *, ?)")
+	fs.StringVar(&opts.Method, "method", "", "on: hold requests with HTTP method")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool proxy intercept <on|off|list|release|drop> [held_id] [options]
+
+Hold proxied browser requests until released.
+Intercept stays on across invocations until turned off.
+
+Commands:
+  on         Hold matching requests (all when no filter is given)
+  off        Stop holding and forward all held requests
+  list       Show intercept state and held requests (default)
+  release    Forward held_id, or all held requests
+  drop       Refuse held_id (browser gets 502), or all held requests
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts.Action = "list"
+	posArgs := fs.Args()
+	if len(posArgs) > 0 {
+		opts.Action = posArgs[0]
+	}
+	switch opts.Action {
+	case "on", "off", "list":
+	case "release", "drop":
+		if len(posArgs) > 1 {
+			opts.HeldID = posArgs[1]
+		}
+	case "help":
+		fs.Usage()
+		return nil
+	default:
+		return cliutil.UnknownSubcommandError("proxy intercept", opts.Action, interceptSubcommands)
+	}
+	if opts.Action != "on" && (opts.Host != "" || opts.Path != "" || opts.Method != "") {
+		return errors.New("--host, --path and --method only apply to 'intercept on'")
+	}
+
+	return intercept(mcpURL, opts)
+}
@@ -0,0 +1,70 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/go-appsec/toolbox/sectool/cliutil"
+	"github.com/go-appsec/toolbox/sectool/mcpclient"
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func intercept(mcpURL string, opts mcpclient.InterceptOpts) error {
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	action := opts.Action
+	if action == "list" {
+		opts.Action = "status"
+	}
+	resp, err := client.ProxyIntercept(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("intercept %s failed: %w", action, err)
+	}
+
+	switch {
+	case resp.Dropped > 0:
+		fmt.Printf("Dropped %d held request(s)\n", resp.Dropped)
+	case resp.Released > 0:
+		fmt.Printf("Forwarded %d held request(s)\n", resp.Released)
+	}
+	printInterceptStatus(resp)
+	return nil
+}
+
+func printInterceptStatus(resp *protocol.ProxyInterceptResponse) {
+	if !resp.Enabled {
+		fmt.Println("Intercept: off")
+	} else {
+		fmt.Println("Intercept: on")
+		for _, f := range []struct{ name, value string }{
+			{"Host", resp.Host}, {"Path", resp.Path}, {"Method", resp.Method},
+		} {
+			if f.value != "" {
+				fmt.Printf("%s: `%s`\n", f.name, f.value)
+			}
+		}
+	}
+	if len(resp.Held) == 0 {
+		fmt.Println("No requests held.")
+		return
+	}
+
+	fmt.Println()
+	t := cliutil.NewTable(os.Stdout)
+	t.AppendHeader(table.Row{"Held ID", "Method", "Host", "Path", "Held For"})
+	for _, h := range resp.Held {
+		t.AppendRow(table.Row{h.HeldID, h.Method, h.Host, h.Path, h.HeldFor})
+	}
+	t.Render()
+	cliutil.Summary(os.Stdout, len(resp.Held), "held request", "held requests")
+	cliutil.HintCommand(os.Stdout, "To forward a held request", "sectool proxy intercept release <held_id>")
+}
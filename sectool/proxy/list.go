@@ -2,11 +2,14 @@ package proxy
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"unicode/utf8"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 
+	"github.com/go-appsec/toolbox/sectool/bundle"
 	"github.com/go-appsec/toolbox/sectool/cliutil"
 	"github.com/go-appsec/toolbox/sectool/mcpclient"
 	"github.com/go-appsec/toolbox/sectool/protocol"
@@ -102,39 +105,50 @@ func get(mcpURL string, flowID, scope, pattern string) error {
 	}
 	defer func() { _ = client.Close() }()
 
+	// Full (decompressed) bodies unless matching a pattern, which returns match context
+	fullBody := pattern == ""
 	resp, err := client.ProxyGet(ctx, flowID, mcpclient.ProxyGetOpts{
-		Scope:   scope,
-		Pattern: pattern,
+		Scope:    scope,
+		Pattern:  pattern,
+		FullBody: fullBody,
 	})
 	if err != nil {
 		return fmt.Errorf("proxy get failed: %w", err)
 	}
+	reqBody, respBody := []byte(resp.ReqBody), []byte(resp.RespBody)
+	if fullBody {
+		if reqBody, err = bundle.DecodeBase64Body(resp.ReqBody); err != nil {
+			return fmt.Errorf("decode request body: %w", err)
+		} else if respBody, err = bundle.DecodeBase64Body(resp.RespBody); err != nil {
+			return fmt.Errorf("decode response body: %w", err)
+		}
+	}
 
 	fmt.Printf("%s\n\n", cliutil.Bold("Flow Details"))
 	fmt.Printf("Flow: %s\n", cliutil.ID(resp.FlowID))
 	fmt.Printf("Method: %s\n", resp.Method)
 	fmt.Printf("URL: %s\n", resp.URL)
 	fmt.Printf("Status: %s %s\n", cliutil.FormatStatus(resp.Status), resp.StatusLine)
-	fmt.Printf("Request Size: %d bytes\n", resp.ReqSize)
-	fmt.Printf("Response Size: %d bytes\n", resp.RespSize)
+	fmt.Printf("Request Size: %d bytes%s\n", resp.ReqSize, truncatedNote(resp.ReqTruncated))
+	fmt.Printf("Response Size: %d bytes%s\n", resp.RespSize, truncatedNote(resp.RespTruncated))
 
 	if resp.ReqHeaders != "" {
 		fmt.Println()
 		fmt.Println(cliutil.Bold("Request Headers"))
 		fmt.Println(resp.ReqHeaders)
 	}
-	if resp.ReqBody != "" {
+	if len(reqBody) > 0 {
 		fmt.Println(cliutil.Bold("Request Body"))
-		fmt.Println(resp.ReqBody)
+		printBody(reqBody)
 	}
 	if resp.RespHeaders != "" {
 		fmt.Println()
 		fmt.Println(cliutil.Bold("Response Headers"))
 		fmt.Println(resp.RespHeaders)
 	}
-	if resp.RespBody != "" {
+	if len(respBody) > 0 {
 		fmt.Println(cliutil.Bold("Response Body"))
-		fmt.Println(resp.RespBody)
+		printBody(respBody)
 	}
 	if resp.Note != "" {
 		fmt.Println()
@@ -144,6 +158,28 @@ func get(mcpURL string, flowID, scope, pattern string) error {
 	return nil
 }
 
+// maxHexDumpBytes caps how much of a binary body is shown as a hex dump.
+const maxHexDumpBytes = 1024
+
+// printBody prints a text body as-is and a binary body as a hex dump of its first bytes.
+func printBody(body []byte) {
+	if utf8.Valid(body) {
+		fmt.Println(string(body))
+		return
+	}
+	fmt.Print(hex.Dump(body[:min(len(body), maxHexDumpBytes)]))
+	if len(body) > maxHexDumpBytes {
+		fmt.Println(cliutil.Muted(fmt.Sprintf("... %d more bytes (use proxy export for the full body)", len(body)-maxHexDumpBytes)))
+	}
+}
+
+func truncatedNote(truncated bool) string {
+	if truncated {
+		return cliutil.Warning(" (truncated at max_body_bytes)")
+	}
+	return ""
+}
+
 func printAggregateTable(agg []protocol.SummaryEntry) {
 	t := cliutil.NewTable(os.Stdout)
 	t.AppendHeader(table.Row{"Host", "Path", "Method", "Status", "Count"})
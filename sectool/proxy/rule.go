@@ -61,7 +61,11 @@ func printRuleTable(rules []protocol.RuleEntry) {
 			if r.IsRegex {
 				regex = "yes"
 			}
-			t.AppendRow(table.Row{r.RuleID, r.Label, r.Type, regex, tr(r.Match, 30), tr(r.Replace, 30)})
+			match := r.Match
+			if scope := ruleScope(r); scope != "" { // block/redirect rules match by scope
+				match = scope
+			}
+			t.AppendRow(table.Row{r.RuleID, r.Label, r.Type, regex, tr(match, 30), tr(r.Replace, 30)})
 		}
 	} else {
 		t.AppendHeader(table.Row{"Rule ID", "Type", "Regex", "Match", "Replace"})
@@ -70,14 +74,18 @@ func printRuleTable(rules []protocol.RuleEntry) {
 			if r.IsRegex {
 				regex = "yes"
 			}
-			t.AppendRow(table.Row{r.RuleID, r.Type, regex, tr(r.Match, 30), tr(r.Replace, 30)})
+			match := r.Match
+			if scope := ruleScope(r); scope != "" { // block/redirect rules match by scope
+				match = scope
+			}
+			t.AppendRow(table.Row{r.RuleID, r.Type, regex, tr(match, 30), tr(r.Replace, 30)})
 		}
 	}
 	t.Render()
 	cliutil.Summary(os.Stdout, len(rules), "rule", "rules")
 }
 
-func ruleAdd(mcpURL string, ruleType, match, replace, label string, isRegex bool) error {
+func ruleAdd(mcpURL string, opts mcpclient.RuleAddOpts) error {
 	ctx := context.Background()
 
 	client, err := mcpclient.Connect(ctx, mcpURL)
@@ -86,13 +94,7 @@ func ruleAdd(mcpURL string, ruleType, match, replace, label string, isRegex bool
 	}
 	defer func() { _ = client.Close() }()
 
-	resp, err := client.ProxyRuleAdd(ctx, mcpclient.RuleAddOpts{
-		Label:   label,
-		Type:    ruleType,
-		IsRegex: isRegex,
-		Match:   match,
-		Replace: replace,
-	})
+	resp, err := client.ProxyRuleAdd(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("rule add failed: %w", err)
 	}
@@ -111,9 +113,30 @@ func ruleAdd(mcpURL string, ruleType, match, replace, label string, isRegex bool
 	if resp.Replace != "" {
 		fmt.Printf("Replace: `%s`\n", resp.Replace)
 	}
+	if scope := ruleScope(*resp); scope != "" {
+		fmt.Printf("Scope: `%s`\n", scope)
+	}
 	return nil
 }
 
+// ruleScope formats the method, host and path a block or redirect rule applies to.
+func ruleScope(r protocol.RuleEntry) string {
+	if r.Method == "" && r.Host == "" && r.Path == "" {
+		return ""
+	}
+	method, host, path := r.Method, r.Host, r.Path
+	if method == "" {
+		method = "*"
+	}
+	if host == "" {
+		host = "*"
+	}
+	if path == "" {
+		path = "/*"
+	}
+	return method + " " + host + path
+}
+
 func ruleDelete(mcpURL string, ruleID string) error {
 	ctx := context.Background()
 
@@ -11,6 +11,12 @@ import (
 // Parse handles the "sectool reflected" command.
 func Parse(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("reflected", pflag.ContinueOnError)
+	var locations []string
+	var limit, minLength int
+
+	fs.StringSliceVar(&locations, "in", nil, "response sections to search: body, headers (default: both)")
+	fs.IntVar(&limit, "limit", 0, "maximum reflections to show (0 = all)")
+	fs.IntVar(&minLength, "min-length", 4, "minimum parameter value length to search for")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool reflected <flow_id>
@@ -26,18 +32,28 @@ Arguments:
 Examples:
   sectool reflected f7k2x
   sectool reflected rpl_abc
+  sectool reflected f7k2x --in headers    # only header reflections (redirects, CRLF)
+  sectool reflected f7k2x --limit 10      # first 10 reflections on large echoed forms
+  sectool reflected f7k2x --min-length 2  # include short values such as 3-char IDs
+
+Options:
 `)
+		fs.PrintDefaults()
 	}
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
+	if minLength < 1 {
+		return errors.New("--min-length must be at least 1")
+	}
+
 	posArgs := fs.Args()
 	if len(posArgs) < 1 {
 		fs.Usage()
 		return errors.New("flow_id required: sectool reflected <flow_id>")
 	}
 
-	return run(mcpURL, posArgs[0])
+	return run(mcpURL, posArgs[0], locations, limit, minLength)
 }
@@ -3,13 +3,14 @@ package reflected
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/go-appsec/toolbox/sectool/cliutil"
 	"github.com/go-appsec/toolbox/sectool/mcpclient"
 )
 
-func run(mcpURL, flowID string) error {
+func run(mcpURL, flowID string, locations []string, limit, minLength int) error {
 	ctx := context.Background()
 
 	client, err := mcpclient.Connect(ctx, mcpURL)
@@ -18,11 +19,15 @@ func run(mcpURL, flowID string) error {
 	}
 	defer func() { _ = client.Close() }()
 
-	resp, err := client.FindReflected(ctx, flowID)
+	resp, err := client.FindReflected(ctx, flowID, mcpclient.FindReflectedOpts{Locations: locations, Limit: limit, MinLength: minLength})
 	if err != nil {
 		return fmt.Errorf("find_reflected failed: %w", err)
 	}
 
+	if resp.JSONPCallback != "" {
+		fmt.Printf("%s JSONP endpoint: callback parameter %s (XSS via callback name, cross-site readable)\n\n",
+			cliutil.Warning("!"), cliutil.Bold(resp.JSONPCallback))
+	}
 	if len(resp.Reflections) == 0 {
 		fmt.Println("No reflections detected.")
 		return nil
@@ -34,12 +39,35 @@ func run(mcpURL, flowID string) error {
 	for _, r := range resp.Reflections {
 		fmt.Printf("  %s %s (%s)\n", cliutil.Warning("→"), cliutil.Bold(r.Name), r.Source)
 		fmt.Printf("    Value: %s\n", r.Value)
-		fmt.Printf("    Found in: %s\n", strings.Join(r.Locations, ", "))
+		locations := make([]string, 0, len(r.Locations))
+		for _, loc := range r.Locations {
+			label := loc
+			if n := r.Occurrences[loc]; n > 1 {
+				label += fmt.Sprintf(" x%d", n)
+			}
+			switch enc := r.Encodings[loc]; enc {
+			case "":
+				locations = append(locations, label)
+			case "none":
+				locations = append(locations, label+" ("+cliutil.Error("unencoded")+")")
+			default:
+				locations = append(locations, label+" ("+enc+")")
+			}
+		}
+		fmt.Printf("    Found in: %s\n", strings.Join(locations, ", "))
+		if r.OccurrencesCapped {
+			fmt.Printf("    Occurrences: %d (locations count only the first matches of each encoding)\n", r.OccurrenceCount)
+		} else if r.OccurrenceCount > 1 {
+			fmt.Printf("    Occurrences: %d\n", r.OccurrenceCount)
+		}
 		if r.RawReflected {
 			fmt.Printf("    %s Reflected without encoding (not sanitized)\n", cliutil.Error("!"))
 		}
 		fmt.Println()
 	}
+	if resp.Truncated {
+		cliutil.Hint(os.Stdout, fmt.Sprintf("More reflections exist beyond the first %d. Raise --limit to see them.", limit))
+	}
 
 	return nil
 }
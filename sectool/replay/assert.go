@@ -0,0 +1,159 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/go-appsec/toolbox/sectool/bundle"
+	"github.com/go-appsec/toolbox/sectool/cliutil"
+	"github.com/go-appsec/toolbox/sectool/mcpclient"
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+// expectations are the response checks requested for replay assert.
+type expectations struct {
+	status       int
+	bodyContains []string
+	headers      []string // "Name" (present) or "Name: Value" (exact value)
+}
+
+func (e expectations) empty() bool {
+	return e.status == 0 && len(e.bodyContains) == 0 && len(e.headers) == 0
+}
+
+// assertionResult is the outcome of a single expectation.
+type assertionResult struct {
+	assertion string
+	expected  string
+	actual    string
+	passed    bool
+}
+
+func assertReplay(mcpURL, bundleArg string, expect expectations) error {
+	bundlePath, err := bundle.ResolvePath(bundleArg)
+	if err != nil {
+		return err
+	}
+
+	rawHeaders, body, meta, err := bundle.Read(bundlePath)
+	if err != nil {
+		return fmt.Errorf("read bundle: %w", err)
+	}
+
+	headerMap, err := parseHeaders(rawHeaders)
+	if err != nil {
+		return fmt.Errorf("parse headers: %w", err)
+	}
+	deleteHeaderCaseInsensitive(headerMap, "Content-Length")
+
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	sent, err := client.RequestSend(ctx, mcpclient.RequestSendOpts{
+		URL:     meta.URL,
+		Method:  meta.Method,
+		Headers: headerMap,
+		Body:    string(body),
+	})
+	if err != nil {
+		return fmt.Errorf("request send: %w", err)
+	}
+
+	// Fetch the full body; the send response only carries a preview
+	resp, err := client.ReplayGet(ctx, sent.ReplayID)
+	if err != nil {
+		return fmt.Errorf("replay get failed: %w", err)
+	}
+	respBody, err := base64.StdEncoding.DecodeString(resp.RespBody)
+	if err != nil {
+		return fmt.Errorf("decode response body: %w", err)
+	}
+
+	results := evaluateExpectations(expect, resp, respBody)
+
+	fmt.Printf("%s\n\n", cliutil.Bold("Replay Assertions"))
+	fmt.Printf("Replay ID: %s\n", cliutil.ID(resp.ReplayID))
+	fmt.Printf("Request: %s %s\n", meta.Method, meta.URL)
+	fmt.Printf("Status: %s %s\n\n", cliutil.FormatStatus(resp.Status), resp.StatusLine)
+
+	var failed int
+	t := cliutil.NewTable(os.Stdout)
+	t.AppendHeader(table.Row{"Assertion", "Expected", "Actual", "Result"})
+	for _, r := range results {
+		result := cliutil.Success("PASS")
+		if !r.passed {
+			result = cliutil.BoldRed("FAIL")
+			failed++
+		}
+		t.AppendRow(table.Row{r.assertion, r.expected, r.actual, result})
+	}
+	t.Render()
+	fmt.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d assertions failed", failed, len(results))
+	}
+	fmt.Println(cliutil.Success(fmt.Sprintf("All %d assertions passed", len(results))))
+	return nil
+}
+
+// evaluateExpectations checks each expectation against the replayed response.
+func evaluateExpectations(expect expectations, resp *protocol.ReplayGetResponse, body []byte) []assertionResult {
+	var results []assertionResult
+
+	if expect.status != 0 {
+		results = append(results, assertionResult{
+			assertion: "status",
+			expected:  strconv.Itoa(expect.status),
+			actual:    strconv.Itoa(resp.Status),
+			passed:    resp.Status == expect.status,
+		})
+	}
+
+	for _, needle := range expect.bodyContains {
+		found := bytes.Contains(body, []byte(needle))
+		actual := "not found"
+		if found {
+			actual = "found"
+		}
+		results = append(results, assertionResult{
+			assertion: "body contains",
+			expected:  strconv.Quote(needle),
+			actual:    actual,
+			passed:    found,
+		})
+	}
+
+	for _, h := range expect.headers {
+		name, want, hasValue := strings.Cut(h, ":")
+		name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+		want = strings.TrimSpace(want)
+		values := resp.RespHeadersParsed[name]
+
+		r := assertionResult{assertion: "header " + name, expected: "present", actual: "missing"}
+		if hasValue {
+			r.expected = strconv.Quote(want)
+		}
+		if len(values) > 0 {
+			r.actual = strconv.Quote(strings.Join(values, ", "))
+			r.passed = !hasValue || slices.Contains(values, want)
+		}
+		results = append(results, r)
+	}
+
+	return results
+}
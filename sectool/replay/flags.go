@@ -4,13 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 
 	"github.com/go-appsec/toolbox/sectool/cliutil"
+	"github.com/go-appsec/toolbox/sectool/diff"
 )
 
-var replaySubcommands = []string{"send", "get", "create", "help"}
+var replaySubcommands = []string{"send", "get", "create", "assert", "smuggle", "help"}
 
 func Parse(args []string, mcpURL string) error {
 	if len(args) < 1 {
@@ -25,6 +29,10 @@ func Parse(args []string, mcpURL string) error {
 		return parseGet(args[1:], mcpURL)
 	case "create":
 		return parseCreate(args[1:])
+	case "assert":
+		return parseAssert(args[1:], mcpURL)
+	case "smuggle":
+		return parseSmuggle(args[1:], mcpURL)
 	case "help", "--help", "-h":
 		printUsage()
 		return nil
@@ -57,6 +65,7 @@ replay send [options]
     --set-query "key=value"        add or replace query param
     --remove-query "key"           remove query param
     --target "https://other:8443"  override destination host
+    --resolve "host=ip"            pin hostname to IP (Host/SNI unchanged)
 
   JSON body modifications:
     --set-json "key=value"         set key (infers type: null/bool/number/object/string)
@@ -70,18 +79,29 @@ replay send [options]
 
   Other options:
     --follow-redirects             follow 3xx redirects
+    --request-timeout <dur>        abort the request after this duration
     --force                        send even if validation fails
+    --compare-scope <scope>        diff against the --flow source (e.g., response, response_body)
     --body <path>                  body file (with --file)
 
+  Fuzzing (--bundle or --file):
+    --fuzz <marker>                placeholder to replace, e.g. '§FUZZ§'
+    --wordlist <path>              words substituted for the marker, one per line
+    --delay <dur>                  pause between fuzz requests
+    --max <n>                      send at most n words (0 = all)
+
   Examples:
     sectool replay send --flow f7k2x
     sectool replay send --flow f7k2x --set-header "Authorization: Bearer tok"
     sectool replay send --flow f7k2x --path /api/v2/users --set-query "id=123"
     sectool replay send --flow f7k2x --set-json "user.role=admin"
+    sectool replay send --flow f7k2x --remove-header Cookie --compare-scope response_headers
     sectool replay send --bundle abc123
     sectool replay send --file request.http --body payload
+    sectool replay send --bundle abc123 --fuzz '§FUZZ§' --wordlist ids.txt --delay 200ms
 
   Output: Markdown with replay_id, status, headers, body preview
+          (fuzzing: table of word, status, length, and flow_id)
 
 ---
 
@@ -115,21 +135,67 @@ replay create <url> [options]
     sectool replay create https://api.example.com --header "Authorization: Bearer token"
 
   Output: Bundle path that can be used with 'sectool replay send --bundle'
+
+---
+
+replay assert --bundle <bundle_id> [expectations]
+
+  Send a bundle and check the response; exits non-zero if any expectation
+  fails. Suited to security regression tests in CI pipelines.
+
+  Expectations (at least one required, combine multiple):
+    --expect-status <code>          response status must equal code
+    --expect-body-contains <text>   body must contain text (repeatable)
+    --expect-header "Name"          header must be present (repeatable)
+    --expect-header "Name: Value"   header must have exact value
+
+  Examples:
+    sectool replay assert --bundle abc123 --expect-status 403 --expect-body-contains "denied"
+    sectool replay assert --bundle abc123 --expect-header "X-Frame-Options: DENY"
+
+  Output: Table of assertions with PASS/FAIL; error exit when any fail
+
+---
+
+replay smuggle --bundle <bundle_id> --confirm [options]
+
+  INTRUSIVE: probe the bundle's target for HTTP request smuggling (CL.TE / TE.CL).
+
+  Sends a baseline request, then raw HTTP/1.1 requests with conflicting
+  Content-Length and Transfer-Encoding framing. A probe that hangs until the
+  timeout while the baseline answers promptly indicates a desync; anomalies
+  are re-sent once to confirm. Probes may poison shared back-end connections
+  and affect other users. Only run against targets you are authorized to test.
+
+  Options:
+    --bundle <bundle_id>  bundle providing URL and headers (required)
+    --confirm             acknowledge the probe is intrusive (required)
+    --timeout <dur>       per-request hang threshold (default: 10s)
+    --method <method>     override probe method (default: POST)
+
+  Example:
+    sectool replay smuggle --bundle abc123 --confirm
+
+  Output: Verdict (likely/possible/unlikely/inconclusive) with per-technique timings
 `)
 }
 
 func parseSend(args []string, mcpURL string) error {
 	fs := pflag.NewFlagSet("replay send", pflag.ContinueOnError)
 	fs.SetInterspersed(true)
-	var flow, bundle, file, body, target, path, query string
+	var flow, bundle, file, body, target, path, query, compareScope string
 	var followRedirects, force bool
-	var headers, removeHeaders, setQuery, removeQuery, setJSON, removeJSON []string
+	var requestTimeout, fuzzDelay time.Duration
+	var fuzzMarker, wordlist string
+	var fuzzMax int
+	var headers, removeHeaders, setQuery, removeQuery, setJSON, removeJSON, resolve []string
 
 	fs.StringVar(&flow, "flow", "", "flow_id to replay from proxy history")
 	fs.StringVar(&bundle, "bundle", "", "bundle_id from proxy export")
 	fs.StringVar(&file, "file", "", "path to request.http file (- for stdin)")
 	fs.StringVar(&body, "body", "", "path to body file (use with --file)")
 	fs.StringVar(&target, "target", "", "override target URL (scheme://host:port)")
+	fs.StringArrayVar(&resolve, "resolve", nil, "pin hostname to IP, like curl --resolve (repeatable, e.g., api.example.com=10.0.0.5)")
 	fs.StringArrayVar(&headers, "set-header", nil, "add or replace header (repeatable)")
 	fs.StringArrayVar(&removeHeaders, "remove-header", nil, "remove header by name (repeatable)")
 	fs.StringVar(&path, "path", "", "replace URL path (e.g., /api/v2/users)")
@@ -140,6 +206,12 @@ func parseSend(args []string, mcpURL string) error {
 	fs.StringArrayVar(&removeJSON, "remove-json", nil, "remove JSON key (repeatable)")
 	fs.BoolVar(&followRedirects, "follow-redirects", false, "follow 3xx redirects")
 	fs.BoolVar(&force, "force", false, "send request even if validation fails")
+	fs.DurationVar(&requestTimeout, "request-timeout", 0, "abort the request after this duration (e.g., 5s)")
+	fs.StringVar(&compareScope, "compare-scope", "", "after sending, diff against the --flow source using this diff scope (e.g., response, response_body)")
+	fs.StringVar(&fuzzMarker, "fuzz", "", "placeholder replaced by each --wordlist entry (e.g., §FUZZ§)")
+	fs.StringVar(&wordlist, "wordlist", "", "file of words to substitute for the --fuzz marker, one per line")
+	fs.DurationVar(&fuzzDelay, "delay", 0, "pause between fuzz requests (e.g., 200ms)")
+	fs.IntVar(&fuzzMax, "max", 0, "maximum fuzz requests to send (0 = whole wordlist)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool replay send [options]
@@ -175,6 +247,7 @@ Request modifications:
 
   Target:
     --target scheme://host    Override destination host and scheme
+    --resolve host=ip         Connect to ip for host; Host header and SNI unchanged
 
   Query modification order: remove -> set
 
@@ -200,6 +273,22 @@ JSON body modifications:
 
   Note: Content-Length header is automatically updated when body changes.
 
+Comparison (--flow only):
+  --compare-scope <scope>   After sending, diff the replay against the source flow.
+                            Accepts the 'sectool diff' scopes: response for the full
+                            response, response_headers for status and headers, or
+                            response_body to focus on content.
+
+Fuzzing (--bundle or --file):
+  --fuzz <marker>     Placeholder in the URL, headers, or body, e.g. '§FUZZ§'
+  --wordlist <path>   Words substituted verbatim for the marker, one per line;
+                      one request is sent per non-empty line
+  --delay <dur>       Pause between requests
+  --max <n>           Stop after n words (0 = whole wordlist)
+
+  Each request is captured like any other send; the output is a table of word,
+  status, response length, and flow_id for spotting anomalies.
+
 Validation:
   Requests are validated before sending. If validation fails, the request
   is NOT sent and errors are displayed. Use --force to send anyway (useful
@@ -230,11 +319,36 @@ Options:
 	} else if sources > 1 {
 		return errors.New("only one of --flow, --bundle, or --file can be specified")
 	}
+	if requestTimeout < 0 {
+		return errors.New("--request-timeout must be positive")
+	}
+	if (fuzzMarker == "") != (wordlist == "") {
+		return errors.New("--fuzz and --wordlist must be used together")
+	} else if fuzzMarker != "" {
+		if flow != "" {
+			return errors.New("--fuzz requires --bundle or --file (place the marker in the request)")
+		}
+	} else if fuzzDelay != 0 || fuzzMax != 0 {
+		return errors.New("--delay and --max require --fuzz")
+	}
+	if fuzzDelay < 0 {
+		return errors.New("--delay must be positive")
+	} else if fuzzMax < 0 {
+		return errors.New("--max must be positive")
+	}
+	if compareScope != "" {
+		if flow == "" {
+			return errors.New("--compare-scope requires --flow (bundle and file sources have no flow to compare against)")
+		} else if !slices.Contains(diff.Scopes, compareScope) {
+			return fmt.Errorf("invalid --compare-scope %q: use one of %s", compareScope, strings.Join(diff.Scopes, ", "))
+		}
+	}
 
 	return send(mcpURL, flow, bundle, file, body, target, headers, removeHeaders,
 		path, query, setQuery, removeQuery,
-		setJSON, removeJSON,
-		followRedirects, force)
+		setJSON, removeJSON, resolve,
+		followRedirects, force, requestTimeout, compareScope,
+		fuzzMarker, wordlist, fuzzDelay, fuzzMax)
 }
 
 func parseGet(args []string, mcpURL string) error {
@@ -302,3 +416,81 @@ Output: Bundle path that can be used with 'sectool replay send --bundle'
 
 	return create(fs.Args()[0], method, headers, bodyPath)
 }
+
+func parseSmuggle(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("replay smuggle", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+
+	var bundleArg, method string
+	var timeout time.Duration
+	var confirm bool
+
+	fs.StringVar(&bundleArg, "bundle", "", "bundle_id providing URL and headers (required)")
+	fs.BoolVar(&confirm, "confirm", false, "acknowledge the probe sends malformed requests (required)")
+	fs.DurationVar(&timeout, "timeout", 10*time.Second, "per-request hang threshold")
+	fs.StringVar(&method, "method", "", "override probe method (default: POST)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool replay smuggle --bundle <bundle_id> --confirm [options]
+
+INTRUSIVE: probe for HTTP request smuggling (CL.TE / TE.CL desync) using timing.
+Probes may poison shared back-end connections; only test authorized targets.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if bundleArg == "" {
+		fs.Usage()
+		return errors.New("--bundle is required")
+	} else if !confirm {
+		return errors.New("replay smuggle sends malformed requests that can disrupt the target; pass --confirm to proceed")
+	} else if timeout <= 0 {
+		return errors.New("--timeout must be positive")
+	}
+
+	return smuggle(mcpURL, bundleArg, method, timeout)
+}
+
+func parseAssert(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("replay assert", pflag.ContinueOnError)
+	fs.SetInterspersed(true)
+
+	var bundleArg string
+	var expect expectations
+
+	fs.StringVar(&bundleArg, "bundle", "", "bundle_id to send (required)")
+	fs.IntVar(&expect.status, "expect-status", 0, "expected response status code")
+	fs.StringArrayVar(&expect.bodyContains, "expect-body-contains", nil, "text the response body must contain (repeatable)")
+	fs.StringArrayVar(&expect.headers, "expect-header", nil, "response header 'Name' or 'Name: Value' (repeatable)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool replay assert --bundle <bundle_id> [expectations]
+
+Send a bundle and check the response; exits non-zero if any expectation fails.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if bundleArg == "" {
+		fs.Usage()
+		return errors.New("--bundle is required")
+	} else if expect.empty() {
+		fs.Usage()
+		return errors.New("at least one --expect-status, --expect-body-contains, or --expect-header is required")
+	}
+	for _, h := range expect.headers {
+		if name, _, _ := strings.Cut(h, ":"); strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid --expect-header %q: header name required", h)
+		}
+	}
+
+	return assertReplay(mcpURL, bundleArg, expect)
+}
@@ -0,0 +1,169 @@
+package replay
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/go-appsec/toolbox/sectool/cliutil"
+	"github.com/go-appsec/toolbox/sectool/mcpclient"
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+// fuzzOptions configure replay send to fire one request per wordlist entry.
+type fuzzOptions struct {
+	marker string // placeholder replaced by each word, e.g. §FUZZ§
+	words  []string
+	delay  time.Duration // pause between requests
+}
+
+func (f fuzzOptions) enabled() bool {
+	return f.marker != ""
+}
+
+// fuzzResult is the outcome of the request sent for a single word.
+type fuzzResult struct {
+	word   string
+	status int
+	size   int
+	flowID string
+	err    error
+}
+
+// requestSender sends a request and records it as a flow.
+type requestSender interface {
+	RequestSend(ctx context.Context, opts mcpclient.RequestSendOpts) (*protocol.ReplaySendResponse, error)
+}
+
+// readWordlist returns the non-empty lines of path, up to max entries (0 for all).
+func readWordlist(path string, max int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read wordlist: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		word := strings.TrimRight(scanner.Text(), "\r")
+		if word == "" {
+			continue
+		}
+		words = append(words, word)
+		if max > 0 && len(words) >= max {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read wordlist: %w", err)
+	}
+	if len(words) == 0 {
+		return nil, errors.New("wordlist is empty")
+	}
+	return words, nil
+}
+
+// containsMarker reports whether the marker appears in the URL, headers or body of opts.
+func containsMarker(opts mcpclient.RequestSendOpts, marker string) bool {
+	if strings.Contains(opts.URL, marker) || strings.Contains(opts.Body, marker) {
+		return true
+	}
+	for k, v := range opts.Headers {
+		if strings.Contains(k, marker) || strings.Contains(v, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// substituteMarker returns a copy of opts with every marker in the URL, headers and body
+// replaced by word.
+func substituteMarker(opts mcpclient.RequestSendOpts, marker, word string) mcpclient.RequestSendOpts {
+	opts.URL = strings.ReplaceAll(opts.URL, marker, word)
+	opts.Body = strings.ReplaceAll(opts.Body, marker, word)
+	headers := make(map[string]string, len(opts.Headers))
+	for k, v := range opts.Headers {
+		headers[strings.ReplaceAll(k, marker, word)] = strings.ReplaceAll(v, marker, word)
+	}
+	opts.Headers = headers
+	return opts
+}
+
+// runFuzz sends base once per word with the marker substituted. A failed request is
+// recorded in its result and does not stop the run.
+func runFuzz(ctx context.Context, sender requestSender, base mcpclient.RequestSendOpts, fuzz fuzzOptions) ([]fuzzResult, error) {
+	if !containsMarker(base, fuzz.marker) {
+		return nil, fmt.Errorf("fuzz marker %q not found in request URL, headers, or body", fuzz.marker)
+	}
+
+	results := make([]fuzzResult, 0, len(fuzz.words))
+	for i, word := range fuzz.words {
+		if i > 0 && fuzz.delay > 0 {
+			select {
+			case <-ctx.Done():
+				return results, ctx.Err()
+			case <-time.After(fuzz.delay):
+			}
+		}
+
+		result := fuzzResult{word: word}
+		resp, err := sender.RequestSend(ctx, substituteMarker(base, fuzz.marker, word))
+		if err != nil {
+			result.err = err
+		} else {
+			result.status = resp.Status
+			result.size = resp.RespSize
+			result.flowID = resp.ReplayID
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// restoreMarker undoes the percent-encoding URL normalization applies to a marker, so a
+// marker placed in the path or query is still found and substituted.
+func restoreMarker(rawURL, marker string) string {
+	rawURL = strings.ReplaceAll(rawURL, url.PathEscape(marker), marker)
+	return strings.ReplaceAll(rawURL, url.QueryEscape(marker), marker)
+}
+
+// sendFuzz runs the fuzz iteration over opts and prints the results table.
+func sendFuzz(ctx context.Context, sender requestSender, opts mcpclient.RequestSendOpts, fuzz fuzzOptions) error {
+	opts.URL = restoreMarker(opts.URL, fuzz.marker)
+	results, err := runFuzz(ctx, sender, opts, fuzz)
+	if len(results) > 0 {
+		printFuzzResults(results)
+	}
+	return err
+}
+
+func printFuzzResults(results []fuzzResult) {
+	fmt.Printf("%s\n\n", cliutil.Bold(fmt.Sprintf("Fuzz Results (%d requests)", len(results))))
+
+	t := cliutil.NewTable(os.Stdout)
+	t.AppendHeader(table.Row{"Word", "Status", "Length", "Flow ID"})
+	for _, r := range results {
+		if r.err != nil {
+			t.AppendRow(table.Row{r.word, cliutil.BoldRed("error: " + r.err.Error()), "", ""})
+			continue
+		}
+		t.AppendRow(table.Row{r.word, cliutil.FormatStatus(r.status), r.size, cliutil.ID(r.flowID)})
+	}
+	t.Render()
+
+	for _, r := range results {
+		if r.flowID != "" {
+			fmt.Println()
+			cliutil.HintCommand(os.Stdout, "To inspect a response", "sectool replay get "+r.flowID)
+			break
+		}
+	}
+}
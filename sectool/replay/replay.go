@@ -15,8 +15,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jedib0t/go-pretty/v6/table"
+
 	"github.com/go-appsec/toolbox/sectool/bundle"
 	"github.com/go-appsec/toolbox/sectool/cliutil"
+	"github.com/go-appsec/toolbox/sectool/diff"
 	"github.com/go-appsec/toolbox/sectool/mcpclient"
 	"github.com/go-appsec/toolbox/sectool/protocol"
 	"github.com/go-appsec/toolbox/sectool/service"
@@ -24,8 +27,9 @@ import (
 
 func send(mcpURL string, flow, bundleArg, file, body, target string, headers, removeHeaders []string,
 	path, query string, setQuery, removeQuery []string,
-	setJSON, removeJSON []string,
-	followRedirects bool, force bool) error {
+	setJSON, removeJSON, resolve []string,
+	followRedirects bool, force bool, requestTimeout time.Duration, compareScope string,
+	fuzzMarker, wordlist string, fuzzDelay time.Duration, fuzzMax int) error {
 	if flow == "" && bundleArg == "" && file == "" {
 		return errors.New("one of --flow, --bundle, or --file is required")
 	}
@@ -69,12 +73,26 @@ func send(mcpURL string, flow, bundleArg, file, body, target string, headers, re
 		}
 	}
 
+	var timeout string
+	if requestTimeout > 0 {
+		timeout = requestTimeout.String()
+	}
+
+	var fuzz fuzzOptions
+	if fuzzMarker != "" {
+		words, err := readWordlist(wordlist, fuzzMax)
+		if err != nil {
+			return err
+		}
+		fuzz = fuzzOptions{marker: fuzzMarker, words: words, delay: fuzzDelay}
+	}
+
 	if bundleArg != "" {
-		return sendFromBundle(mcpURL, bundleArg, target, headers, removeHeaders, path, query, setQuery, removeQuery, setJSONMap, removeJSON, bodyOverride, hasBodyOverride, followRedirects)
+		return sendFromBundle(mcpURL, bundleArg, target, headers, removeHeaders, path, query, setQuery, removeQuery, setJSONMap, removeJSON, bodyOverride, hasBodyOverride, resolve, followRedirects, timeout, fuzz)
 	}
 
 	if file != "" {
-		return sendFromFile(mcpURL, file, target, headers, removeHeaders, path, query, setQuery, removeQuery, setJSONMap, removeJSON, bodyOverride, hasBodyOverride, followRedirects)
+		return sendFromFile(mcpURL, file, target, headers, removeHeaders, path, query, setQuery, removeQuery, setJSONMap, removeJSON, bodyOverride, hasBodyOverride, resolve, followRedirects, timeout, fuzz)
 	}
 
 	ctx := context.Background()
@@ -104,6 +122,8 @@ func send(mcpURL string, flow, bundleArg, file, body, target string, headers, re
 		RemoveJSON:      removeJSON,
 		FollowRedirects: followRedirects,
 		Force:           force,
+		Resolve:         resolve,
+		Timeout:         timeout,
 	})
 	if err != nil {
 		return fmt.Errorf("replay send failed: %w", err)
@@ -123,6 +143,19 @@ func send(mcpURL string, flow, bundleArg, file, body, target string, headers, re
 		fmt.Printf("Body Preview:\n%s\n", resp.RespPreview)
 	}
 
+	if compareScope != "" {
+		diffResp, err := client.DiffFlow(ctx, mcpclient.DiffFlowOpts{
+			FlowA: flow,
+			FlowB: resp.ReplayID,
+			Scope: compareScope,
+		})
+		if err != nil {
+			return fmt.Errorf("compare with %s failed: %w", flow, err)
+		}
+		fmt.Printf("\n%s\n\n", cliutil.Bold(fmt.Sprintf("Comparison with %s (scope: %s)", flow, compareScope)))
+		diff.PrintResult(diffResp)
+	}
+
 	return nil
 }
 
@@ -142,25 +175,141 @@ func get(mcpURL string, replayID string) error {
 
 	fmt.Printf("%s\n\n", cliutil.Bold("Replay Details"))
 	fmt.Printf("Replay ID: %s\n", cliutil.ID(resp.ReplayID))
-	fmt.Printf("Duration: %s\n", resp.Duration)
+	if resp.SourceFlowID != "" {
+		fmt.Printf("Source Flow: %s\n", cliutil.ID(resp.SourceFlowID))
+	}
+	fmt.Printf("Duration: %s\n\n", resp.Duration)
+
+	fmt.Printf("%s\n\n", cliutil.Bold("Request"))
+	fmt.Printf("Size: %d bytes\n\n", resp.ReqSize)
+	if resp.ReqHeaders != "" {
+		fmt.Printf("Headers:\n%s\n", resp.ReqHeaders)
+	}
+	printDecodedBody(resp.ReqBody)
+
+	if len(resp.Redirects) > 0 {
+		fmt.Printf("\n%s\n\n", cliutil.Bold("Redirects"))
+		for i, r := range resp.Redirects {
+			fmt.Printf("%d. %s %s -> %s\n", i+1, cliutil.FormatStatus(r.Status), r.URL, r.Location)
+		}
+	}
+
+	fmt.Printf("\n%s\n\n", cliutil.Bold("Response"))
 	fmt.Printf("Status: %s %s\n", cliutil.FormatStatus(resp.Status), resp.StatusLine)
 	fmt.Printf("Size: %d bytes\n\n", resp.RespSize)
 	if resp.RespHeaders != "" {
 		fmt.Printf("Headers:\n%s\n", resp.RespHeaders)
 	}
+	printDecodedBody(resp.RespBody)
 
-	if resp.RespBody != "" {
-		body, err := base64.StdEncoding.DecodeString(resp.RespBody)
-		if err != nil {
-			fmt.Printf("Body: %s\n", cliutil.Error(fmt.Sprintf("failed to decode: %v", err)))
-		} else {
-			fmt.Printf("Body:\n%s\n", string(body))
+	return nil
+}
+
+// printDecodedBody prints a base64-encoded full body from replay_get.
+func printDecodedBody(encoded string) {
+	if encoded == "" {
+		return
+	}
+	body, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		fmt.Printf("Body: %s\n", cliutil.Error(fmt.Sprintf("failed to decode: %v", err)))
+	} else if len(body) > 0 {
+		fmt.Printf("Body:\n%s\n", string(body))
+	}
+}
+
+func smuggle(mcpURL string, bundleArg, method string, timeout time.Duration) error {
+	bundlePath, err := bundle.ResolvePath(bundleArg)
+	if err != nil {
+		return err
+	}
+
+	rawHeaders, _, meta, err := bundle.Read(bundlePath)
+	if err != nil {
+		return fmt.Errorf("read bundle: %w", err)
+	}
+
+	headerMap, err := parseHeaders(rawHeaders)
+	if err != nil {
+		return fmt.Errorf("parse headers: %w", err)
+	}
+	// Framing headers are crafted per probe
+	deleteHeaderCaseInsensitive(headerMap, "Content-Length")
+	deleteHeaderCaseInsensitive(headerMap, "Transfer-Encoding")
+
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	fmt.Fprintf(os.Stderr, "%s\n", cliutil.Warning("Sending intrusive smuggling probes to "+meta.URL))
+
+	resp, err := client.ReplaySmuggle(ctx, mcpclient.ReplaySmuggleOpts{
+		URL:              meta.URL,
+		Method:           method,
+		Headers:          headerMap,
+		Timeout:          timeout.String(),
+		ConfirmIntrusive: true,
+	})
+	if err != nil {
+		return fmt.Errorf("replay smuggle failed: %w", err)
+	}
+
+	fmt.Printf("%s\n\n", cliutil.Bold("Smuggling Probe"))
+	fmt.Printf("Target: %s\n", meta.URL)
+	fmt.Printf("Verdict: %s\n", formatSmuggleVerdict(resp.Verdict))
+	fmt.Printf("Baseline: %s\n\n", formatSmuggleAttempt(resp.Baseline))
+
+	if len(resp.Probes) > 0 {
+		t := cliutil.NewTable(os.Stdout)
+		t.AppendHeader(table.Row{"Technique", "Verdict", "Attempts"})
+		for _, p := range resp.Probes {
+			attempts := p.Skipped
+			if attempts == "" {
+				parts := make([]string, 0, len(p.Attempts))
+				for _, a := range p.Attempts {
+					parts = append(parts, formatSmuggleAttempt(a))
+				}
+				attempts = strings.Join(parts, "; ")
+			}
+			t.AppendRow(table.Row{p.Technique, formatSmuggleVerdict(p.Verdict), attempts})
 		}
+		t.Render()
 	}
 
+	if resp.Note != "" {
+		fmt.Printf("\n%s\n", cliutil.Muted(resp.Note))
+	}
 	return nil
 }
 
+func formatSmuggleVerdict(verdict string) string {
+	switch verdict {
+	case "likely":
+		return cliutil.BoldRed(verdict)
+	case "possible":
+		return cliutil.Warning(verdict)
+	case "unlikely":
+		return cliutil.Success(verdict)
+	default:
+		return cliutil.Muted(verdict)
+	}
+}
+
+func formatSmuggleAttempt(a protocol.SmuggleAttempt) string {
+	switch {
+	case a.TimedOut:
+		return "timeout after " + a.Duration
+	case a.Error != "":
+		return "error: " + a.Error
+	default:
+		return fmt.Sprintf("%d in %s", a.Status, a.Duration)
+	}
+}
+
 func create(urlArg, method string, headers []string, bodyPath string) error {
 	// Parse and normalize URL
 	if !strings.Contains(urlArg, "://") {
@@ -217,7 +366,7 @@ func create(urlArg, method string, headers []string, bodyPath string) error {
 	// Write bundle to disk
 	bundlePath, err := bundle.Write(bundleID,
 		urlArg, method, reqBuilder.String(),
-		bodyBytes, "", nil)
+		bodyBytes, "", nil, nil)
 	if err != nil {
 		return fmt.Errorf("write bundle: %w", err)
 	}
@@ -237,8 +386,8 @@ func create(urlArg, method string, headers []string, bodyPath string) error {
 func sendFromBundle(mcpURL string, bundleArg, target string, addHeaders, removeHeaders []string,
 	path, query string, setQuery, removeQuery []string,
 	setJSON map[string]interface{}, removeJSON []string,
-	bodyOverride []byte, hasBodyOverride bool,
-	followRedirects bool) error {
+	bodyOverride []byte, hasBodyOverride bool, resolve []string,
+	followRedirects bool, timeout string, fuzz fuzzOptions) error {
 	bundlePath, err := bundle.ResolvePath(bundleArg)
 	if err != nil {
 		return err
@@ -281,13 +430,20 @@ func sendFromBundle(mcpURL string, bundleArg, target string, addHeaders, removeH
 	}
 	defer func() { _ = client.Close() }()
 
-	resp, err := client.RequestSend(ctx, mcpclient.RequestSendOpts{
+	opts := mcpclient.RequestSendOpts{
 		URL:             urlStr,
 		Method:          meta.Method,
 		Headers:         headerMap,
 		Body:            string(body),
 		FollowRedirects: followRedirects,
-	})
+		Resolve:         resolve,
+		Timeout:         timeout,
+	}
+	if fuzz.enabled() {
+		return sendFuzz(ctx, client, opts, fuzz)
+	}
+
+	resp, err := client.RequestSend(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("request send: %w", err)
 	}
@@ -299,8 +455,8 @@ func sendFromBundle(mcpURL string, bundleArg, target string, addHeaders, removeH
 func sendFromFile(mcpURL string, file, target string, addHeaders, removeHeaders []string,
 	path, query string, setQuery, removeQuery []string,
 	setJSON map[string]interface{}, removeJSON []string,
-	bodyOverride []byte, hasBodyOverride bool,
-	followRedirects bool) error {
+	bodyOverride []byte, hasBodyOverride bool, resolve []string,
+	followRedirects bool, timeout string, fuzz fuzzOptions) error {
 	data, err := readRequestData(file)
 	if err != nil {
 		return err
@@ -356,13 +512,20 @@ func sendFromFile(mcpURL string, file, target string, addHeaders, removeHeaders
 	}
 	defer func() { _ = client.Close() }()
 
-	resp, err := client.RequestSend(ctx, mcpclient.RequestSendOpts{
+	opts := mcpclient.RequestSendOpts{
 		URL:             urlStr,
 		Method:          req.Method,
 		Headers:         headerMap,
 		Body:            string(body),
 		FollowRedirects: followRedirects,
-	})
+		Resolve:         resolve,
+		Timeout:         timeout,
+	}
+	if fuzz.enabled() {
+		return sendFuzz(ctx, client, opts, fuzz)
+	}
+
+	resp, err := client.RequestSend(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("request send: %w", err)
 	}
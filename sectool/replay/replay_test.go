@@ -1,12 +1,25 @@
 package replay
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/mcpclient"
+	"github.com/go-appsec/toolbox/sectool/protocol"
 )
 
 func TestDeleteHeaderCaseInsensitive(t *testing.T) {
@@ -338,3 +351,221 @@ func mustParseURL(raw string) *url.URL {
 	}
 	return u
 }
+
+func TestParseSend_CompareScope(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requires_flow", func(t *testing.T) {
+		err := parseSend([]string{"--bundle", "abc", "--compare-scope", "response"}, "")
+		assert.ErrorContains(t, err, "requires --flow")
+	})
+
+	t.Run("invalid_scope", func(t *testing.T) {
+		err := parseSend([]string{"--flow", "f1", "--compare-scope", "status"}, "")
+		assert.ErrorContains(t, err, `invalid --compare-scope "status"`)
+	})
+}
+
+func TestParseSend_Fuzz(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requires_wordlist", func(t *testing.T) {
+		err := parseSend([]string{"--bundle", "abc", "--fuzz", "§FUZZ§"}, "")
+		assert.ErrorContains(t, err, "must be used together")
+	})
+
+	t.Run("rejects_flow", func(t *testing.T) {
+		err := parseSend([]string{"--flow", "f1", "--fuzz", "§FUZZ§", "--wordlist", "words.txt"}, "")
+		assert.ErrorContains(t, err, "requires --bundle or --file")
+	})
+
+	t.Run("delay_requires_fuzz", func(t *testing.T) {
+		err := parseSend([]string{"--bundle", "abc", "--delay", "1s"}, "")
+		assert.ErrorContains(t, err, "require --fuzz")
+	})
+}
+
+// fakeSender records each request and answers with a new flow id.
+type fakeSender struct {
+	sent []mcpclient.RequestSendOpts
+}
+
+func (f *fakeSender) RequestSend(_ context.Context, opts mcpclient.RequestSendOpts) (*protocol.ReplaySendResponse, error) {
+	f.sent = append(f.sent, opts)
+	if opts.Body == `{"id":"bad"}` {
+		return nil, errors.New("connection reset")
+	}
+	return &protocol.ReplaySendResponse{
+		ReplayID: fmt.Sprintf("flow%d", len(f.sent)),
+		ResponseDetails: protocol.ResponseDetails{
+			Status:   200,
+			RespSize: 10 * len(f.sent),
+		},
+	}, nil
+}
+
+func TestReadWordlist(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "words.txt")
+	require.NoError(t, os.WriteFile(path, []byte("admin\r\n\nroot\nguest\ntest\nuser\n"), 0644))
+
+	words, err := readWordlist(path, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin", "root", "guest", "test", "user"}, words)
+
+	words, err = readWordlist(path, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin", "root"}, words)
+
+	empty := filepath.Join(t.TempDir(), "empty.txt")
+	require.NoError(t, os.WriteFile(empty, []byte("\n\n"), 0644))
+	_, err = readWordlist(empty, 0)
+	assert.ErrorContains(t, err, "wordlist is empty")
+}
+
+func TestRunFuzz(t *testing.T) {
+	t.Parallel()
+
+	base := mcpclient.RequestSendOpts{
+		URL:     "https://example.com/users/§FUZZ§?q=1",
+		Method:  "POST",
+		Headers: map[string]string{"X-User": "§FUZZ§", "Accept": "*/*"},
+		Body:    `{"id":"§FUZZ§"}`,
+	}
+
+	t.Run("one_flow_per_word", func(t *testing.T) {
+		sender := &fakeSender{}
+		words := []string{"1", "2", "admin", "../etc", "0"}
+
+		results, err := runFuzz(t.Context(), sender, base, fuzzOptions{marker: "§FUZZ§", words: words, delay: time.Millisecond})
+		require.NoError(t, err)
+
+		require.Len(t, sender.sent, 5)
+		require.Len(t, results, 5)
+		flowIDs := make(map[string]bool)
+		for i, r := range results {
+			assert.Equal(t, words[i], r.word)
+			assert.Equal(t, 200, r.status)
+			assert.Equal(t, 10*(i+1), r.size)
+			flowIDs[r.flowID] = true
+		}
+		assert.Len(t, flowIDs, 5)
+
+		assert.Equal(t, "https://example.com/users/admin?q=1", sender.sent[2].URL)
+		assert.Equal(t, "admin", sender.sent[2].Headers["X-User"])
+		assert.Equal(t, `{"id":"admin"}`, sender.sent[2].Body)
+		assert.Equal(t, "POST", sender.sent[2].Method)
+		assert.Equal(t, "§FUZZ§", base.Headers["X-User"])
+	})
+
+	t.Run("error_recorded", func(t *testing.T) {
+		sender := &fakeSender{}
+		results, err := runFuzz(t.Context(), sender, base, fuzzOptions{marker: "§FUZZ§", words: []string{"bad", "ok"}})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.ErrorContains(t, results[0].err, "connection reset")
+		assert.Equal(t, "flow2", results[1].flowID)
+	})
+
+	t.Run("marker_missing", func(t *testing.T) {
+		sender := &fakeSender{}
+		_, err := runFuzz(t.Context(), sender, base, fuzzOptions{marker: "§USER§", words: []string{"a"}})
+		assert.ErrorContains(t, err, "not found")
+		assert.Empty(t, sender.sent)
+	})
+}
+
+func TestSendFromBundle_FuzzPath(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var sentURLs []string
+	mcpServer := server.NewMCPServer("test", "1.0")
+	mcpServer.AddTool(mcp.NewTool("request_send", mcp.WithString("url")),
+		func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			sentURLs = append(sentURLs, req.GetString("url", ""))
+			return mcp.NewToolResultText(fmt.Sprintf(`{"replay_id":"flow%d","status":200}`, len(sentURLs))), nil
+		})
+	srv := httptest.NewServer(server.NewStreamableHTTPServer(mcpServer, server.WithStateLess(true)))
+	t.Cleanup(srv.Close)
+
+	bundleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "request.meta.json"),
+		[]byte(`{"flow_id":"f1","url":"https://example.com/users/§FUZZ§/profile","method":"GET"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "request.http"),
+		[]byte("GET /users/§FUZZ§/profile HTTP/1.1\r\nHost: example.com\r\n\r\n"), 0644))
+
+	err := sendFromBundle(srv.URL, bundleDir, "", nil, nil, "", "", []string{"role=§FUZZ§"}, nil, nil, nil,
+		nil, false, nil, false, "", fuzzOptions{marker: "§FUZZ§", words: []string{"1", "admin"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"https://example.com/users/1/profile?role=1",
+		"https://example.com/users/admin/profile?role=admin",
+	}, sentURLs)
+}
+
+func TestParseSmuggle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requires_bundle", func(t *testing.T) {
+		err := parseSmuggle([]string{"--confirm"}, "")
+		assert.ErrorContains(t, err, "--bundle is required")
+	})
+
+	t.Run("requires_confirm", func(t *testing.T) {
+		err := parseSmuggle([]string{"--bundle", "abc"}, "")
+		assert.ErrorContains(t, err, "pass --confirm")
+	})
+}
+
+func TestParseAssert(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requires_bundle", func(t *testing.T) {
+		err := parseAssert([]string{"--expect-status", "403"}, "")
+		assert.ErrorContains(t, err, "--bundle is required")
+	})
+
+	t.Run("requires_expectation", func(t *testing.T) {
+		err := parseAssert([]string{"--bundle", "abc"}, "")
+		assert.ErrorContains(t, err, "at least one")
+	})
+
+	t.Run("invalid_header", func(t *testing.T) {
+		err := parseAssert([]string{"--bundle", "abc", "--expect-header", ": DENY"}, "")
+		assert.ErrorContains(t, err, "header name required")
+	})
+}
+
+func TestEvaluateExpectations(t *testing.T) {
+	t.Parallel()
+
+	resp := &protocol.ReplayGetResponse{
+		Status: 403,
+		RespHeadersParsed: map[string][]string{
+			"X-Frame-Options": {"DENY"},
+			"Set-Cookie":      {"a=1", "b=2"},
+		},
+	}
+	body := []byte("Access denied for user")
+
+	results := evaluateExpectations(expectations{
+		status:       403,
+		bodyContains: []string{"denied", "stack trace"},
+		headers:      []string{"x-frame-options", "X-Frame-Options: SAMEORIGIN", "Set-Cookie: b=2", "Content-Security-Policy"},
+	}, resp, body)
+
+	require.Len(t, results, 7)
+	var passed []bool
+	for _, r := range results {
+		passed = append(passed, r.passed)
+	}
+	assert.Equal(t, []bool{true, true, false, true, false, true, false}, passed)
+	assert.Equal(t, "header X-Frame-Options", results[3].assertion)
+	assert.Equal(t, `"DENY"`, results[4].actual)
+	assert.Equal(t, "missing", results[6].actual)
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"regexp"
 	"strings"
 	"time"
@@ -17,6 +19,9 @@ var ErrLabelExists = errors.New("label already exists")
 // ErrNotFound is returned when a requested resource (rule, session, etc.) doesn't exist.
 var ErrNotFound = errors.New("not found")
 
+// ErrEventNotFound is returned when an OAST session exists but holds no event with the given ID.
+var ErrEventNotFound = fmt.Errorf("event %w", ErrNotFound)
+
 // Rule type constants for match/replace rules.
 const (
 	RuleTypeRequestHeader  = "request_header"
@@ -24,6 +29,10 @@ const (
 	RuleTypeResponseHeader = "response_header"
 	RuleTypeResponseBody   = "response_body"
 
+	// Block and redirect answer matching requests in the proxy instead of forwarding them
+	RuleTypeBlock    = "block"
+	RuleTypeRedirect = "redirect"
+
 	RuleTypeWSToServer = "ws:to-server"
 	RuleTypeWSToClient = "ws:to-client"
 	RuleTypeWSBoth     = "ws:both"
@@ -86,7 +95,12 @@ type ProxyRuleInput struct {
 	Type    string // Required on add
 	IsRegex *bool
 	Match   string
-	Replace string
+	Replace string // redirect target for redirect rules
+
+	// Scope of block and redirect rules; empty matches any
+	Host   string // host glob, case-insensitive
+	Path   string // path glob, query excluded
+	Method string
 }
 
 // ProxyEntry represents a single proxy history entry in HttpBackend-agnostic form.
@@ -111,13 +125,17 @@ type SendRequestInput struct {
 	// Protocol from the original history entry ("http/1.1" or "h2")
 	// Empty defaults to HTTP/1.1
 	Protocol string
+
+	// HostResolution pins hostnames (lowercase) to IPs, like curl --resolve
+	HostResolution map[string]string
 }
 
 // SendRequestResult contains the response from a sent request.
 type SendRequestResult struct {
-	Headers  []byte
-	Body     []byte
-	Duration time.Duration
+	Headers   []byte
+	Body      []byte
+	Duration  time.Duration
+	Redirects []proxy.RedirectHop // Redirects followed before the response, with FollowRedirects
 }
 
 // MaxOastEventsPerSession is the maximum number of events stored per session.
@@ -178,6 +196,7 @@ type OastEventInfo struct {
 type OastPollResultInfo struct {
 	Events       []OastEventInfo // Events matching the filter
 	DroppedCount int             // Number of events dropped due to buffer limit
+	FailedCount  int             // Number of interactions that could not be decrypted or parsed
 }
 
 // CrawlerBackend defines the interface for web crawling operations.
@@ -194,6 +213,16 @@ type CrawlerBackend interface {
 	// sessionID can be the ID or label. Returns ErrNotFound if session doesn't exist.
 	GetStatus(ctx context.Context, sessionID string) (*CrawlStatus, error)
 
+	// StreamStatus emits a status snapshot now and again whenever session activity changes,
+	// at most about once a second. The channel is closed after the snapshot showing the
+	// session no longer running, or when ctx is done.
+	// sessionID can be the ID or label. Returns ErrNotFound if session doesn't exist.
+	StreamStatus(ctx context.Context, sessionID string) (<-chan CrawlStatus, error)
+
+	// GetStats returns status code and content type distributions across all flows.
+	// sessionID can be the ID or label. Returns ErrNotFound if session doesn't exist.
+	GetStats(ctx context.Context, sessionID string) (*CrawlStats, error)
+
 	// ListFlows returns flows matching filters.
 	// sessionID can be the ID or label.
 	ListFlows(ctx context.Context, sessionID string, opts CrawlListOptions) ([]CrawlFlow, error)
@@ -206,13 +235,59 @@ type CrawlerBackend interface {
 	// sessionID can be the ID or label.
 	ListErrors(ctx context.Context, sessionID string, limit int) ([]CrawlError, error)
 
+	// ListExternalLinks returns out-of-scope links referenced by crawled pages (never fetched).
+	// sessionID can be the ID or label.
+	ListExternalLinks(ctx context.Context, sessionID string, limit int) ([]ExternalLink, error)
+
+	// ListJSEndpoints returns request URLs found in crawled JavaScript, in discovery order.
+	// sessionID can be the ID or label.
+	ListJSEndpoints(ctx context.Context, sessionID string, limit int) ([]JSEndpoint, error)
+
+	// ListClientRoutes returns client-side routes found in crawled scripts (never visited).
+	// sessionID can be the ID or label.
+	ListClientRoutes(ctx context.Context, sessionID string, limit int) ([]ClientRoute, error)
+
+	// ListSimilarClusters returns groups of near-identical responses (SimilarityThreshold),
+	// largest first. sessionID can be the ID or label.
+	ListSimilarClusters(ctx context.Context, sessionID string, limit int) ([]SimilarCluster, error)
+
+	// ListFindings returns the findings of the session's flows in discovery order, one per
+	// sensitive data match. sessionID can be the ID or label.
+	ListFindings(ctx context.Context, sessionID string, limit int) ([]CrawlFinding, error)
+
 	// GetFlow returns a flow by ID. Returns ErrNotFound if flow doesn't exist.
 	GetFlow(ctx context.Context, flowID string) (*CrawlFlow, error)
 
+	// ExportSession writes the metadata of every flow in a session to w, without raw
+	// request or response bytes, and returns how many flows were written. format is
+	// CrawlExportNDJSON (one flow object per line) or CrawlExportCSV. Flows are written as
+	// they are read rather than buffered. sessionID can be the ID or label.
+	ExportSession(ctx context.Context, sessionID, format string, w io.Writer) (int, error)
+
+	// DeleteFlow removes a flow by ID. Returns ErrNotFound if flow doesn't exist.
+	DeleteFlow(ctx context.Context, flowID string) error
+
+	// DeleteFlows removes flows matching the filters and searches (honoring Invert) and
+	// returns how many were removed. Since, Cursor, Limit, Offset and Wait are ignored.
+	// sessionID can be the ID or label.
+	DeleteFlows(ctx context.Context, sessionID string, opts CrawlListOptions) (int, error)
+
+	// DeleteSession removes a session with its flows, forms and errors, freeing their memory,
+	// and returns how many flows were dropped. A running session is refused unless force,
+	// which stops it first. Returns ErrNotFound for an unknown session. sessionID can be the
+	// ID or label.
+	DeleteSession(ctx context.Context, sessionID string, force bool) (int, error)
+
 	// StopSession immediately stops a running crawl. In-flight requests are abandoned.
 	// sessionID can be the ID or label.
 	StopSession(ctx context.Context, sessionID string) error
 
+	// ResumeSession restarts a stopped or completed session with its original options,
+	// requesting the discovered URLs that were never requested (including those abandoned
+	// by the stop) and returning how many were queued. Flows, forms and counters are kept.
+	// sessionID can be the ID or label.
+	ResumeSession(ctx context.Context, sessionID string) (int, error)
+
 	// ListSessions returns all sessions (active and completed), most recent first.
 	// limit=0 means no limit.
 	ListSessions(ctx context.Context, limit int) ([]CrawlSessionInfo, error)
@@ -221,22 +296,63 @@ type CrawlerBackend interface {
 	Close() error
 }
 
+// CrawlHostLimit overrides the crawl rate for hosts matching a glob.
+type CrawlHostLimit struct {
+	HostGlob    string        // Matched against the URL host, including an explicit port, e.g. "*.cdn.example.com"
+	Delay       time.Duration // Delay between requests to matching hosts
+	RandomDelay time.Duration // Additional random jitter
+	Parallelism int           // Concurrent requests to matching hosts; 0 = session parallelism
+}
+
 // CrawlOptions contains parameters for creating a crawl session.
 type CrawlOptions struct {
-	Label           string            // Optional unique label for the session
-	Seeds           []CrawlSeed       // Initial seeds (URLs and/or flow IDs)
-	ExplicitDomains []string          // User-specified via --domain
-	AllowedPaths    []string          // Glob patterns (default: all)
-	DisallowedPaths []string          // Glob patterns (default from config)
-	MaxDepth        int               // 0 = unlimited
-	MaxRequests     int               // 0 = unlimited
-	Delay           time.Duration     // Default: 200ms
-	RandomDelay     time.Duration     // Additional random jitter
-	Parallelism     int               // Default: 2
-	IgnoreRobotsTxt bool              // Default: false
-	SubmitForms     bool              // Default: false
-	ExtractForms    *bool             // Default: true (from config)
-	Headers         map[string]string // Custom headers
+	Label                string                       // Optional unique label for the session
+	Seeds                []CrawlSeed                  // Initial seeds (URLs and/or flow IDs)
+	ExplicitDomains      []string                     // User-specified via --domain
+	AllowedPaths         []string                     // Glob patterns (default: all)
+	DisallowedPaths      []string                     // Glob patterns (default from config)
+	AllowedPathsRegex    []string                     // Regular expressions matched against the URL path, alongside AllowedPaths
+	DisallowedPathsRegex []string                     // Regular expressions matched against the URL path, alongside DisallowedPaths
+	AllowedContentTypes  []string                     // Content-Type prefixes recorded as flows, replacing the text/JSON/XML/JavaScript defaults
+	ExtraContentTypes    []string                     // Content-Type prefixes recorded in addition to the defaults or AllowedContentTypes
+	IgnoreQueryParams    []string                     // Query parameter name globs stripped from discovered links before dedup and visiting
+	MaxDepth             int                          // 0 = unlimited
+	MaxRequests          int                          // 0 = unlimited
+	MaxHosts             int                          // Distinct hosts to request; new hosts beyond this are skipped. 0 = unlimited
+	MaxPagesPerHost      int                          // Requests per host; further requests to that host are skipped. 0 = unlimited
+	BlockThreshold       int                          // Consecutive identical 403/429/CAPTCHA responses from a host that stop the crawl. 0 = disabled
+	VaryVariants         bool                         // Re-request responses with a Vary header, changing each listed header
+	MergeTrailingSlash   bool                         // Crawl only the first-discovered of "/path" and "/path/" unless it fails
+	MaxInFlightBytes     int64                        // Response bytes buffered at once across requests; reads block above this. 0 = unlimited
+	MaxResponseBodyBytes int                          // Captured response body size, overriding config max_body_bytes. 0 = config default, negative = unlimited
+	NoCookieJar          bool                         // Send seed cookies as a static header and ignore cookies set by responses
+	Delay                time.Duration                // Default: 200ms
+	RandomDelay          time.Duration                // Additional random jitter
+	Parallelism          int                          // Default: 2
+	HostLimits           []CrawlHostLimit             // Rate limits for matching hosts, first match wins; other hosts use Delay/RandomDelay/Parallelism
+	Deterministic        bool                         // Visit one URL at a time, sorted by URL within a depth, for reproducible crawls; ignores Parallelism
+	Strategy             string                       // Link visit order: "bfs" (default) or "dfs", which visits one URL at a time and ignores Parallelism
+	IgnoreRobotsTxt      bool                         // Default: false
+	SubmitForms          bool                         // Default: false
+	SafeMode             bool                         // Refuse DELETE/PUT/PATCH and destructive form submissions regardless of path filters
+	CheckFormMethods     bool                         // Send each form as both GET and POST and compare responses
+	FollowLinksOnError   bool                         // Record 4xx/5xx responses as flows and follow their links
+	ExtractForms         *bool                        // Default: true (from config)
+	Headers              map[string]string            // Custom headers
+	DomainHeaders        map[string]map[string]string // Host glob -> headers applied only to matching hosts
+	ExtractJSONURLs      bool                         // Follow URL string values found in JSON responses
+	ExtractCSSURLs       bool                         // Follow url() and @import references in stylesheets
+	ExtractCommentURLs   bool                         // Follow URLs and paths in HTML comments, recording notable comments as findings
+	ExtractJSRoutes      bool                         // Fetch scripts and record client-side routes declared in them; routes are not visited
+	ExtractJSEndpoints   bool                         // Fetch scripts, record fetch/axios/XHR URLs and quoted paths in them, and follow same-origin GET ones
+	SimilarityThreshold  float64                      // Cluster text responses whose SimHash similarity is at least this (0-1); 0 disables
+	DetectDirListing     bool                         // Flag directory-listing pages as findings
+	HostResolution       map[string]string            // Lowercase host -> IP dial override; Host header and SNI unchanged
+	CompletionWebhook    string                       // URL POSTed a JSON summary when the session completes or stops
+	PreflightSeed        bool                         // Fetch the first seed before starting; fail creation if unreachable or behind an auth wall
+	TokenRefresh         *TokenRefresh                // Fetch a new bearer token on 401 and retry the request; nil = disabled
+	DoHResolver          string                       // DNS-over-HTTPS endpoint for name resolution; system resolver when empty
+	Referer              string                       // Referer for discovered links: "" = parent URL under its referrer policy, "none" = omit, else sent as-is
 }
 
 // CrawlSeed represents a seed for starting a crawl.
@@ -254,9 +370,14 @@ type CrawlListOptions struct {
 	Methods     []string          // Filter by HTTP methods
 	ExcludeHost string            // Exclude hosts matching glob
 	ExcludePath string            // Exclude paths matching glob
+	ContentType string            // Glob pattern for response media type, e.g. "image/*"
+	Invert      bool              // Return flows that do not match the filters and searches
+	Fingerprint string            // Only flows with this endpointFingerprint (duplicate_of)
 	Since       string            // Only flows after this flow_id, or "last" for new flows
+	Cursor      string            // Named cursor for "last"; implies Since="last" when Since is empty
 	Limit       int               // Max results (0 = no limit)
 	Offset      int               // Skip first N results
+	Wait        time.Duration     // Block up to this long for a matching flow while the session runs (0 = return immediately)
 
 	// Search regexes for header/body content matching.
 	// Applied during filtering so the since=last cursor only advances
@@ -275,45 +396,103 @@ type CrawlSessionInfo struct {
 
 // CrawlStatus contains progress metrics for a crawl session.
 type CrawlStatus struct {
-	State           string        // "running", "stopped", "completed", "error"
-	URLsQueued      int           // URLs waiting to be visited
-	URLsVisited     int           // URLs successfully visited
-	URLsErrored     int           // URLs that resulted in errors
-	FormsDiscovered int           // Forms found during crawl
-	Duration        time.Duration // Time since session started
-	LastActivity    time.Time     // When last request was made
-	ErrorMessage    string        // Error details if State is "error"
+	State               string              // "running", "stopped", "completed", "error"
+	URLsQueued          int                 // URLs waiting to be visited
+	URLsVisited         int                 // URLs successfully visited
+	URLsErrored         int                 // URLs that resulted in errors
+	FormsDiscovered     int                 // Forms found during crawl
+	Duration            time.Duration       // Time since session started
+	LastActivity        time.Time           // When last request was made
+	ErrorMessage        string              // Error details if State is "error"
+	Hosts               []string            // Distinct hosts requested, sorted
+	SkippedHosts        []string            // New hosts not requested due to MaxHosts, sorted
+	HostQuotaSkips      map[string]int      // Host -> requests skipped after reaching MaxPagesPerHost
+	MergedSlashVariants int                 // Trailing-slash URL variants skipped by MergeTrailingSlash
+	BlockedNote         string              // Set when BlockThreshold stopped the session
+	TokenRefreshes      []TokenRefreshEvent // Calls made to the TokenRefresh endpoint, oldest first
+	DiscoveredPaths     []DiscoveredPath    // Paths found by recon but not crawled, e.g. robots.txt Disallow rules
+}
+
+// CrawlStats contains status code and content type distributions for a crawl session.
+type CrawlStats struct {
+	TotalFlows   int            // Number of captured flows
+	StatusCodes  map[int]int    // status code -> flow count
+	ContentTypes map[string]int // media type (lowercase, parameters stripped) -> flow count
+	Search       CrawlSearchStats
+}
+
+// CrawlSearchStats reports flow search activity for a crawl session.
+type CrawlSearchStats struct {
+	IndexEnabled    bool          // Full-text index is maintained for this session
+	IndexedTokens   int           // Distinct tokens in the index
+	Searches        int           // Flow queries with search_header/search_body
+	IndexedSearches int           // Narrowed to index candidates (literal patterns)
+	ScanSearches    int           // Scanned every flow (regex patterns or index disabled)
+	TotalLatency    time.Duration // Summed search time
+	MaxLatency      time.Duration
 }
 
 // CrawlFlow represents a single captured request/response from crawling.
 type CrawlFlow struct {
-	ID             string        // Short sectool ID
-	SessionID      string        // Parent session ID
-	URL            string        // Full URL visited
-	Host           string        // Hostname (extracted from URL)
-	Path           string        // Path with query string (extracted from URL)
-	Method         string        // HTTP method
-	FoundOn        string        // Parent URL where discovered
-	Depth          int           // Crawl depth from seed
-	StatusCode     int           // HTTP response status
-	ContentType    string        // Response content type
-	ResponseLength int           // Response body length in bytes
-	Request        []byte        // Wire-format bytes from httputil.DumpRequestOut
-	Response       []byte        // Wire-format bytes from httputil.DumpResponse
-	Truncated      bool          // True if response exceeded max_response_body_bytes
-	Duration       time.Duration // Request/response round-trip time
-	DiscoveredAt   time.Time     // When this flow was captured
+	ID             string           // Short sectool ID
+	SessionID      string           // Parent session ID
+	URL            string           // Full URL visited
+	Host           string           // Hostname (extracted from URL)
+	Path           string           // Path with query string (extracted from URL)
+	Method         string           // HTTP method
+	FoundOn        string           // Parent URL where discovered
+	Depth          int              // Crawl depth from seed
+	StatusCode     int              // HTTP response status
+	ContentType    string           // Response content type
+	ResponseLength int              // Response body length in bytes
+	Request        []byte           // Wire-format bytes from httputil.DumpRequestOut
+	Response       []byte           // Wire-format bytes from httputil.DumpResponse
+	Truncated      bool             // True if response exceeded max_response_body_bytes
+	Duration       time.Duration    // Request/response round-trip time
+	DiscoveredAt   time.Time        // When this flow was captured
+	Findings       []string         // Passive detections, e.g. "directory-listing"
+	VariantOf      string           // Flow ID this flow re-requested with VariedHeader changed (VaryVariants)
+	VariedHeader   string           // Request header changed from the VariantOf flow
+	ReferrerPolicy string           // Policy declared by Referrer-Policy header or <meta name="referrer">
+	OriginalURL    string           // URL as discovered, when IgnoreQueryParams stripped parameters from URL
+	JSONPCallback  string           // Request parameter naming the JSONP callback, set with the "jsonp" finding
+	HTMLComments   []string         // Comments with URLs or sensitive content, set with the "html-comment" finding
+	CookieBefore   string           // Replaced session cookie name, set with the "session-cookie-rotated" finding
+	CookieAfter    string           // Session cookie name set in its place (same name for a new value)
+	SimHash        uint64           // Body fingerprint for SimilarityThreshold clustering; 0 when not computed
+	RedirectTo     string           // Resolved Location of a 3xx response
+	RedirectedFrom string           // Flow ID of the redirect hop that led to this flow
+	RedirectChain  []string         // URLs of the redirect hops followed to reach this flow, in order
+	SensitiveData  []SensitiveMatch // SecretPatterns matches in the response body, set with the "sensitive-data" finding
+
+	// HeadersTruncated is set when request or response headers exceeded max_header_bytes
+	// and were cut at a line boundary
+	HeadersTruncated bool
 }
 
 // DiscoveredForm represents a form found during crawling.
 type DiscoveredForm struct {
-	ID        string      // Short sectool ID
-	SessionID string      // Parent session ID
-	URL       string      // Page containing the form
-	Action    string      // Form action URL (resolved to absolute)
-	Method    string      // GET/POST
-	Inputs    []FormInput // Form fields
-	HasCSRF   bool        // Detected CSRF token field
+	ID                string           // Short sectool ID
+	SessionID         string           // Parent session ID
+	URL               string           // Page containing the form
+	Action            string           // Form action URL (resolved to absolute)
+	Method            string           // GET/POST
+	Inputs            []FormInput      // Form fields
+	HasCSRF           bool             // Detected CSRF token field
+	HasJSHandlers     bool             // onsubmit, onclick on submit controls, or javascript: action
+	AutoSubmit        bool             // Hidden-only form submitted by script on load (SSO/CSRF pattern)
+	MethodCheck       *FormMethodCheck // GET vs POST comparison; nil unless CheckFormMethods
+	SubmissionFlowIDs []string         // Captured flows that requested Action with Method, linked at list time
+}
+
+// FormMethodCheck compares a form's responses when submitted as GET and as POST.
+type FormMethodCheck struct {
+	GetFlowID           string // Empty when the response was recorded as a crawl error
+	PostFlowID          string
+	GetStatus           int
+	PostStatus          int
+	Verdict             string // pending, same, different_status, different_body
+	WrongMethodAccepted bool   // Undeclared method got the declared method's non-error status
 }
 
 // FormInput represents a single form field.
@@ -332,6 +511,65 @@ type CrawlError struct {
 	Status int    // HTTP status if available
 }
 
+// DiscoveredPath is a URL surfaced by recon that the crawler does not visit.
+type DiscoveredPath struct {
+	URL    string // Absolute URL
+	Source string // Where it was found, e.g. "robots-disallow"
+}
+
+// ExternalLink is an out-of-scope link target seen during crawling, deduped by URL.
+type ExternalLink struct {
+	URL     string // Absolute link target
+	Host    string // Target hostname
+	FoundOn string // First in-scope page that referenced it
+}
+
+// ClientRoute is a client-side (SPA) route declared in JavaScript. Routes are
+// recorded for review and never visited.
+type ClientRoute struct {
+	Route   string // e.g. "/admin/users/:id" or "#!/settings"
+	Kind    string // "route-table", "hashbang", or "history"
+	FoundOn string // Script or page the route was declared in
+}
+
+// SensitiveMatch is a SecretPatterns match in a crawled response body.
+type SensitiveMatch struct {
+	Pattern string // Name of the matching SecretPattern, e.g. "aws-access-key"
+	Offset  int    // Byte offset of the secret in the response body
+	Snippet string // Secret with its middle masked, in surrounding context
+}
+
+// CrawlFinding is a passive detection on a crawled flow. A sensitive-data finding is
+// listed once per match.
+type CrawlFinding struct {
+	FlowID  string
+	URL     string
+	Finding string // e.g. "sensitive-data", "directory-listing"
+	Pattern string // SensitiveMatch fields, for sensitive-data findings
+	Offset  int
+	Snippet string
+}
+
+// JSEndpoint is a request URL found in a crawled script, such as a fetch() target.
+type JSEndpoint struct {
+	URL     string // Resolved against the page that loaded the script
+	Method  string // Declared HTTP method (axios.<method>, XHR open), empty when unknown
+	Kind    string // "fetch", "axios", "xhr", or "string"
+	FoundOn string // Script the endpoint was declared in
+}
+
+// SimilarCluster groups crawled responses that are near-identical, such as pages rendered
+// from one template that differ only in a timestamp or token.
+type SimilarCluster struct {
+	RepresentativeID string   // Earliest flow of the cluster, compared against the others
+	Host             string   // Representative's host
+	Path             string   // Representative's path
+	StatusCode       int      // Shared by all members
+	ContentType      string   // Media type shared by all members
+	Size             int      // Number of flows in the cluster
+	FlowIDs          []string // Member flow IDs in capture order, representative first (capped)
+}
+
 // ExportResult contains information about an exported flow bundle.
 // BundleID equals FlowID for simpler mental model - one ID per request.
 // Re-exporting the same flow overwrites the bundle, restoring original state.
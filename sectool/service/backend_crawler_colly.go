@@ -3,18 +3,25 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"maps"
+	"net"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httputil"
 	"net/url"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-analyze/bulk"
 	"github.com/go-appsec/scout"
@@ -22,6 +29,7 @@ import (
 
 	"github.com/go-appsec/toolbox/sectool/config"
 	"github.com/go-appsec/toolbox/sectool/service/ids"
+	"github.com/go-appsec/toolbox/sectool/service/proxy"
 	"github.com/go-appsec/toolbox/sectool/service/store"
 )
 
@@ -32,6 +40,16 @@ const (
 	crawlStateRunning   = "running"
 	crawlStateStopped   = "stopped"
 	crawlStateCompleted = "completed"
+
+	// methodCheckFormKey and methodCheckMethodKey tag requests sent for a form's GET/POST comparison
+	methodCheckFormKey   = "method_check_form"
+	methodCheckMethodKey = "method_check_method"
+
+	formMethodPending         = "pending"
+	formMethodIncomplete      = "incomplete"
+	formMethodSame            = "same"
+	formMethodDifferentStatus = "different_status"
+	formMethodDifferentBody   = "different_body"
 )
 
 // Compile-time check that CollyBackend implements CrawlerBackend.
@@ -45,6 +63,17 @@ type CollyBackend struct {
 	config       config.Config
 	maxBodyBytes int
 	closed       bool
+	sweepStop    chan struct{} // closed by Close to end the expired session sweeper
+	sweepDone    chan struct{} // closed when the sweeper exits
+	flowLog      *crawlFlowLog // nil unless PersistFlows
+	secretRules  []secretRule  // compiled SecretPatterns
+
+	// Completion webhook deliveries run after their session's run is done; Close waits
+	// crawlWebhookCloseGrace for them, then cancels webhookCtx
+	webhooks       sync.WaitGroup
+	webhooksClosed bool // set by Close once it waits on webhooks; later deliveries are skipped
+	webhookCtx     context.Context
+	webhookCancel  context.CancelFunc
 
 	// For resolving seed flows from proxy history
 	proxyIndex  *store.ProxyIndex
@@ -62,40 +91,128 @@ type crawlSession struct {
 	reconWg         sync.WaitGroup        // Tracks background recon goroutines
 	flowsByID       map[string]*CrawlFlow // by flow ID for lookup
 	flowsOrdered    []*CrawlFlow          // ordered by discovery time
+	searchIndex     *flowSearchIndex      // token -> flowsOrdered positions; nil when disabled
+	searchStats     flowSearchStats
 	forms           []DiscoveredForm
+	methodChecks    map[string]*methodCheckState // form ID -> GET/POST comparison
+	methodCheckKeys map[string]bool              // action + encoded params already compared
 	errors          []CrawlError
+	externalLinks   []ExternalLink
+	externalSeen    map[string]bool // external link URLs already recorded
+	clientRoutes    []ClientRoute
+	routesSeen      map[string]bool // client-side routes already recorded
+	jsEndpoints     []JSEndpoint
+	endpointsSeen   map[string]bool // resolved JavaScript endpoint URLs already recorded
+	discoveredPaths []DiscoveredPath
+	discoveredSeen  map[string]bool // discovered path URLs already recorded
 	urlsSeen        map[string]bool
+	urlsRequested   map[string]bool          // URLs sent past the request limits; the rest of urlsSeen is requeued on resume
+	hosts           map[string]bool          // distinct hosts requested
+	skippedHosts    map[string]bool          // hosts rejected by MaxHosts
+	hostRequests    map[string]int           // requests issued per host, for MaxPagesPerHost
+	hostQuotaSkips  map[string]int           // host -> requests skipped by MaxPagesPerHost
+	slashVariants   map[string]*slashVariant // trailing-slash-insensitive URL -> first discovered variant
+	slashMerged     int                      // variants currently merged into their first-discovered form
+	blockStreaks    map[string]blockStreak   // host -> consecutive identical block responses
+	blockedNote     string                   // why block detection stopped the session
+	varyChecked     map[string]bool          // URL + header already re-requested as a Vary variant
 	urlsQueued      int
-	requestCount    int // for MaxRequests enforcement
+	requestCount    int            // for MaxRequests enforcement
+	frontier        *crawlFrontier // visit queue for Deterministic and depth-first sessions; nil otherwise
 	lastActivity    time.Time
-	lastReturnedIdx int // for --since last feature
+	lastReturnedIdx int            // for --since last feature
+	namedCursors    map[string]int // cursor name -> next index, independent of lastReturnedIdx
+	flowNotify      chan struct{}  // closed when flows are added or the session ends, then replaced
+	runDone         chan struct{}  // closed when the current run (create or resume) has finished
+	restored        bool           // rebuilt from a crawl flow log without resume state; flows only, cannot resume
+	flowLog         *crawlFlowLog  // the backend's, persisting flows as captured; nil unless PersistFlows
+	deleted         bool           // removed by DeleteSession or expiry; no longer persisted
+
+	// Resolved seed URLs, persisted so a restored session can re-seed its cookie jar
+	seedURLs []string
 
 	// seedHeaders from resolved seed flows (auth cookies, tokens, etc.)
 	// Applied to all requests; can be extended via AddSeeds
 	seedHeaders map[string]string
 
+	// Cookie jar shared by the session's collectors across resumes, seeded with the seed
+	// flow cookies; nil with NoCookieJar, leaving the seed Cookie header static
+	cookieJar http.CookieJar
+
+	// Bearer token from the last TokenRefresh, sent as Authorization to the hosts whose 401
+	// it answered, unless their DomainHeaders set their own Authorization
+	authToken        string
+	authTokenHosts   map[string]bool // host:port that rejected the replaced Authorization
+	lastTokenRefresh time.Time
+	tokenRefreshes   []TokenRefreshEvent
+	refreshMu        sync.Mutex        // serializes calls to the token endpoint
+	refreshTransport http.RoundTripper // transport for token endpoint calls
+	tokenRetried     sync.Map          // method + URL -> retried once after a 401
+
+	// Session cookies as last set per host, for session-cookie-rotated detection
+	sessionCookies map[string]map[string]string // host -> cookie name -> value
+
 	// reconnedDomains tracks domains already expanded via scout (to avoid duplicate recon)
 	reconnedDomains map[string]bool
 
 	// allowedDomains for domain validation of discovered URLs
 	allowedDomains []string
 
+	// Depth and page found on of each discovered link, so one requeued on resume keeps them
+	linkOrigins map[string]crawlLinkOrigin
+
 	// Parent URL tracking for FoundOn field
 	parentURLs sync.Map // url -> parent_url
 
+	// URLs as discovered, keyed by the URL visited after IgnoreQueryParams were stripped
+	originalURLs sync.Map // url -> original url
+
+	// Referrer policies declared by crawled pages, applied to the Referer of links found on them
+	referrerPolicies sync.Map // url -> policy
+
 	// Capture store for correlating RoundTrip with OnResponse
 	captureStore sync.Map // captureID -> *capturedData
 
+	// Capture IDs by colly request ID; request contexts are shared with child requests
+	requestCaptures sync.Map // request ID -> captureID
+
+	// Found-on pages by colly request ID, for the same reason: concurrent siblings would
+	// overwrite a parent_url kept in their shared context
+	requestParents sync.Map // request ID -> parent URL
+
 	// Precompiled regexes for path filtering
-	disallowedRegexes []*regexp.Regexp
+	disallowedRegexes []*regexp.Regexp // from DisallowedPaths globs, matched against the full URL
+	deniedPathRegexes []*regexp.Regexp // from DisallowedPathsRegex, matched against the path
 	allowedRegexes    []*regexp.Regexp
 
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
+// methodCheckState accumulates both halves of a FormMethodCheck until they complete.
+type methodCheckState struct {
+	result            FormMethodCheck
+	getDone, postDone bool
+	getBody, postBody [sha256.Size]byte
+}
+
+// slashVariant tracks the first-discovered form of a URL that differs from others only by a
+// trailing slash. Later variants are merged until that URL's outcome shows it failed.
+type slashVariant struct {
+	link     string
+	resolved bool                     // response or error seen for link
+	failed   bool                     // link returned 4xx/5xx or errored; variants are crawled
+	merged   map[string]mergedVariant // variant URL -> discovery to replay if link fails
+}
+
+type mergedVariant struct {
+	from    *colly.Request
+	foundOn string
+}
+
 // capturedData holds request/response bytes captured in RoundTrip.
 type capturedData struct {
+	URL          string // URL of the captured hop; the final one when redirects were followed
 	Request      []byte
 	RespHeaders  []byte
 	RespBody     []byte // Response body (possibly truncated)
@@ -103,13 +220,19 @@ type capturedData struct {
 	Duration     time.Duration
 	Truncated    bool
 	Error        error
+
+	HeadersTruncated bool // request or response headers cut at maxHeaderBytes
+
+	Redirects []*capturedData // Redirect hops followed before this one, in order
 }
 
 // capturingTransport wraps http.RoundTripper to capture raw request/response bytes.
 type capturingTransport struct {
-	base         http.RoundTripper
-	session      *crawlSession
-	maxBodyBytes int // 0 or negative = unlimited
+	base           http.RoundTripper
+	session        *crawlSession
+	maxBodyBytes   int              // 0 or negative = unlimited
+	maxHeaderBytes int              // 0 or negative = unlimited
+	inFlight       *inFlightLimiter // nil = unlimited
 }
 
 func (t *capturingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -117,6 +240,11 @@ func (t *capturingTransport) RoundTrip(req *http.Request) (*http.Response, error
 	req.Header.Del(captureIDHeader) // Remove before sending
 
 	reqBytes, _ := httputil.DumpRequestOut(req, true)
+	reqBytes, headersTruncated := t.limitHeaders(reqBytes)
+	var redirects []*capturedData
+	if captureID != "" {
+		redirects = t.redirectHops(captureID, req.URL)
+	}
 
 	start := time.Now()
 	resp, err := t.base.RoundTrip(req)
@@ -125,9 +253,12 @@ func (t *capturingTransport) RoundTrip(req *http.Request) (*http.Response, error
 	if err != nil {
 		if captureID != "" {
 			t.session.captureStore.Store(captureID, &capturedData{
-				Request:  reqBytes,
-				Error:    err,
-				Duration: duration,
+				URL:              req.URL.String(),
+				Request:          reqBytes,
+				Error:            err,
+				Duration:         duration,
+				HeadersTruncated: headersTruncated,
+				Redirects:        redirects,
 			})
 		}
 		return nil, err
@@ -135,14 +266,18 @@ func (t *capturingTransport) RoundTrip(req *http.Request) (*http.Response, error
 
 	if captureID != "" {
 		respHeaders, respBody, bodySize, truncated := t.captureResponse(resp)
+		respHeaders, respHeadersTruncated := truncateHeaders(respHeaders, t.maxHeaderBytes)
 
 		t.session.captureStore.Store(captureID, &capturedData{
-			Request:      reqBytes,
-			RespHeaders:  respHeaders,
-			RespBody:     respBody,
-			RespBodySize: bodySize,
-			Duration:     duration,
-			Truncated:    truncated,
+			URL:              req.URL.String(),
+			Request:          reqBytes,
+			RespHeaders:      respHeaders,
+			RespBody:         respBody,
+			RespBodySize:     bodySize,
+			Duration:         duration,
+			Truncated:        truncated,
+			HeadersTruncated: headersTruncated || respHeadersTruncated,
+			Redirects:        redirects,
 		})
 	}
 
@@ -159,21 +294,63 @@ func (t *capturingTransport) captureResponse(resp *http.Response) (headers, body
 		return headers, nil, 0, false
 	}
 
+	var src io.Reader = resp.Body
+	var budget *budgetReader
+	if t.inFlight != nil {
+		budget = &budgetReader{r: resp.Body, ctx: resp.Request.Context(), limiter: t.inFlight, maxHold: int64(t.maxBodyBytes)}
+		src = budget
+	}
+
 	if t.maxBodyBytes <= 0 { // Unlimited: read entire body
-		body, _ = io.ReadAll(resp.Body)
+		body, _ = io.ReadAll(src)
 		_ = resp.Body.Close()
 		bodySize = len(body)
 	} else { // Limited: read up to limit, count total
-		body, bodySize, truncated = readBodyLimited(resp.Body, t.maxBodyBytes)
+		body, bodySize, truncated = readBodyLimited(src, t.maxBodyBytes)
 		_ = resp.Body.Close()
+		if truncated {
+			body = trimTruncatedBody(body, resp.Header.Get("Content-Type"), resp.Header.Get("Content-Encoding"))
+		}
 	}
 
-	// Replace body so Colly can read it
-	resp.Body = io.NopCloser(bytes.NewReader(body))
+	// Replace body so Colly can read it; buffered bytes stay reserved until Colly closes it
+	if budget != nil {
+		resp.Body = &releasingBody{Reader: bytes.NewReader(body), release: budget.release}
+	} else {
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
 
 	return headers, body, bodySize, truncated
 }
 
+// limitHeaders applies maxHeaderBytes to the header block of a dumped message, keeping its body.
+func (t *capturingTransport) limitHeaders(msg []byte) ([]byte, bool) {
+	headers, body := splitHeadersBody(msg)
+	headers, truncated := truncateHeaders(headers, t.maxHeaderBytes)
+	if !truncated {
+		return msg, false
+	}
+	return append(headers, body...), true
+}
+
+// truncateHeaders cuts a raw header block (ending in a blank line) to at most limit bytes.
+// It ends at a line boundary and is re-terminated so it still parses; the start line is
+// always kept. limit <= 0 means unlimited.
+func truncateHeaders(headers []byte, limit int) ([]byte, bool) {
+	if limit <= 0 || len(headers) <= limit {
+		return headers, false
+	}
+
+	// Room for the terminating CRLF after the last kept line's CRLF
+	cut := bytes.LastIndex(headers[:max(limit-2, 0)], []byte("\r\n"))
+	if cut < 0 {
+		if cut = bytes.Index(headers, []byte("\r\n")); cut < 0 {
+			return headers, false
+		}
+	}
+	return append(slices.Clip(headers[:cut+2]), "\r\n"...), true
+}
+
 // readBodyLimited reads up to limit bytes but counts total size.
 // Returns the limited body, actual total size, and whether truncation occurred.
 func readBodyLimited(r io.Reader, limit int) ([]byte, int, bool) {
@@ -192,16 +369,165 @@ func readBodyLimited(r io.Reader, limit int) ([]byte, int, bool) {
 	return buf.Bytes(), totalSize, truncated
 }
 
+// truncationBoundaryWindow is how far before the cut a truncated JSON or HTML body
+// may be shortened to end on a structural boundary.
+const truncationBoundaryWindow = 512
+
+// trimTruncatedBody shortens a truncated text body so it does not end mid-rune and, for
+// JSON and HTML, ends at the last value or tag boundary within truncationBoundaryWindow.
+// Binary and still content-encoded bodies are returned unchanged.
+func trimTruncatedBody(body []byte, contentType, contentEncoding string) []byte {
+	if (contentEncoding != "" && !strings.EqualFold(contentEncoding, "identity")) || !isTextContentType(contentType) {
+		return body
+	}
+
+	// Drop a trailing partial rune; bytes that were never valid UTF-8 are left alone
+	for i := len(body) - 1; i >= 0 && i >= len(body)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(body[i]) {
+			if !utf8.FullRune(body[i:]) {
+				body = body[:i]
+			}
+			break
+		}
+	}
+
+	mediaType := contentMediaType(contentType)
+	isJSON := isJSONMediaType(mediaType)
+	isHTML := mediaType == "text/html" || mediaType == "application/xhtml+xml"
+	if !isJSON && !isHTML {
+		return body
+	}
+	for i := len(body) - 1; i >= 0 && i >= len(body)-truncationBoundaryWindow; i-- {
+		switch c := body[i]; {
+		case isJSON && c == ',': // end before the separator, after the complete value
+			return body[:i]
+		case isJSON && (c == '}' || c == ']'), isHTML && c == '>':
+			return body[:i+1]
+		}
+	}
+	return body
+}
+
+// inFlightLimiter bounds the response bytes buffered across concurrent requests.
+type inFlightLimiter struct {
+	mu    sync.Mutex
+	limit int64
+	used  int64
+	freed chan struct{} // closed and replaced whenever bytes are released
+}
+
+func newInFlightLimiter(limit int64) *inFlightLimiter {
+	return &inFlightLimiter{limit: limit, freed: make(chan struct{})}
+}
+
+// acquire reserves n bytes for a caller already holding held bytes, blocking until they
+// fit. A caller that is the only holder always proceeds so an oversized response cannot
+// wait on itself.
+func (l *inFlightLimiter) acquire(ctx context.Context, n, held int64) error {
+	for {
+		l.mu.Lock()
+		if l.used+n <= l.limit || l.used == held {
+			l.used += n
+			l.mu.Unlock()
+			return nil
+		}
+		freed := l.freed
+		l.mu.Unlock()
+
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (l *inFlightLimiter) release(n int64) {
+	if n <= 0 {
+		return
+	}
+	l.mu.Lock()
+	l.used -= n
+	close(l.freed)
+	l.freed = make(chan struct{})
+	l.mu.Unlock()
+}
+
+// budgetReader reserves limiter bytes before each read. Once maxHold bytes are held
+// (the retained body size when positive), further reads are discarded by the caller
+// and pass through unreserved.
+type budgetReader struct {
+	r       io.Reader
+	ctx     context.Context
+	limiter *inFlightLimiter
+	maxHold int64 // 0 or negative = unlimited
+	held    int64
+	once    sync.Once
+}
+
+func (b *budgetReader) Read(p []byte) (int, error) {
+	want := int64(len(p))
+	if b.maxHold > 0 {
+		want = min(want, b.maxHold-b.held)
+	}
+	if want <= 0 {
+		return b.r.Read(p)
+	}
+
+	if err := b.limiter.acquire(b.ctx, want, b.held); err != nil {
+		return 0, err
+	}
+	n, err := b.r.Read(p[:want])
+	b.held += int64(n)
+	b.limiter.release(want - int64(n))
+	return n, err
+}
+
+// release returns all bytes held by this reader to the limiter; safe to call repeatedly.
+func (b *budgetReader) release() {
+	b.once.Do(func() { b.limiter.release(b.held) })
+}
+
+// releasingBody is a response body that releases its in-flight reservation on Close.
+type releasingBody struct {
+	*bytes.Reader
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	b.release()
+	return nil
+}
+
 // NewCollyBackend creates a new Colly-backed CrawlerBackend.
 func NewCollyBackend(cfg *config.Config, proxyIndex *store.ProxyIndex, httpBackend HttpBackend) *CollyBackend {
-	return &CollyBackend{
+	b := &CollyBackend{
 		sessions:     make(map[string]*crawlSession),
 		byLabel:      make(map[string]string),
 		config:       *cfg,
 		maxBodyBytes: cfg.MaxBodyBytes,
+		sweepStop:    make(chan struct{}),
+		sweepDone:    make(chan struct{}),
 		proxyIndex:   proxyIndex,
 		httpBackend:  httpBackend,
+		secretRules:  compileSecretPatterns(cfg.Crawler.SecretPatterns),
 	}
+	b.webhookCtx, b.webhookCancel = context.WithCancel(context.Background())
+	if cfg.Crawler.PersistFlows != nil && *cfg.Crawler.PersistFlows {
+		dir := cfg.Crawler.PersistDir
+		if dir == "" {
+			dir = filepath.Join(filepath.Dir(config.DefaultPath()), "crawl")
+		}
+		if flowLog, sessions, err := openCrawlFlowLog(dir); err != nil {
+			log.Printf("crawler: warning: crawl flows will not be persisted: %v", err)
+		} else {
+			b.flowLog = flowLog
+			b.restoreSessions(sessions)
+		}
+	}
+	go b.sweepSessions(time.Duration(cfg.Crawler.SessionTTLSecs)*time.Second,
+		time.Duration(cfg.Crawler.SessionSweepSecs)*time.Second)
+	return b
 }
 
 func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*CrawlSessionInfo, error) {
@@ -219,6 +545,48 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 	}
 	b.mu.Unlock()
 
+	if opts.CompletionWebhook != "" {
+		if err := validateWebhookURL(opts.CompletionWebhook); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateCrawlStrategy(opts.Strategy); err != nil {
+		return nil, err
+	}
+	if err := validateHostLimits(opts.HostLimits); err != nil {
+		return nil, err
+	}
+
+	// Compile path filters up front so a mistyped pattern fails creation instead of
+	// silently filtering nothing; config defaults are applied leniently below
+	allowedRegexes, err := compileGlobs(opts.AllowedPaths)
+	if err != nil {
+		return nil, err
+	}
+	allowedPathRegexes, err := compilePathRegexes(opts.AllowedPathsRegex)
+	if err != nil {
+		return nil, err
+	}
+	allowedRegexes = append(allowedRegexes, allowedPathRegexes...)
+	disallowedRegexes, err := compileGlobs(opts.DisallowedPaths)
+	if err != nil {
+		return nil, err
+	}
+	deniedPathRegexes, err := compilePathRegexes(opts.DisallowedPathsRegex)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DoHResolver != "" {
+		if err := validateDoHURL(opts.DoHResolver); err != nil {
+			return nil, err
+		}
+	}
+	if opts.TokenRefresh != nil {
+		if err := validateTokenRefresh(opts.TokenRefresh); err != nil {
+			return nil, err
+		}
+	}
+
 	// Compute allowed domains from seeds
 	allowedDomains, seedURLs, seedHeaders, err := b.resolveSeeds(ctx, opts.Seeds, opts.ExplicitDomains)
 	if err != nil {
@@ -229,19 +597,24 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 		return nil, errors.New("no valid domains: provide seed URLs, seed flows, or explicit domains")
 	}
 
+	baseTransport := crawlBaseTransport(opts, b.config.MaxHeaderBytes)
+	if opts.PreflightSeed && len(seedURLs) > 0 {
+		headers := preflightHeaders(seedURLs[0], seedHeaders, opts)
+		if err := preflightSeed(ctx, baseTransport, seedURLs[0], headers, opts.TokenRefresh != nil); err != nil {
+			return nil, err
+		}
+	}
+
 	// Apply defaults from config
 	if len(opts.DisallowedPaths) == 0 {
 		opts.DisallowedPaths = b.config.Crawler.DisallowedPaths
+		disallowedRegexes = globsToRegexes(opts.DisallowedPaths)
 	}
 
 	sessionCtx, cancel := context.WithCancel(context.Background())
 
 	sessionID := ids.Generate(ids.DefaultLength)
 
-	// Precompile path filter regexes
-	disallowedRegexes := globsToRegexes(opts.DisallowedPaths)
-	allowedRegexes := globsToRegexes(opts.AllowedPaths)
-
 	sess := &crawlSession{
 		info: CrawlSessionInfo{
 			ID:        sessionID,
@@ -253,18 +626,111 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 		startedAt:         time.Now(),
 		flowsByID:         make(map[string]*CrawlFlow),
 		urlsSeen:          make(map[string]bool),
+		urlsRequested:     make(map[string]bool),
+		linkOrigins:       make(map[string]crawlLinkOrigin),
+		externalSeen:      make(map[string]bool),
+		routesSeen:        make(map[string]bool),
+		endpointsSeen:     make(map[string]bool),
+		discoveredSeen:    make(map[string]bool),
+		methodChecks:      make(map[string]*methodCheckState),
+		methodCheckKeys:   make(map[string]bool),
+		hosts:             make(map[string]bool),
+		skippedHosts:      make(map[string]bool),
+		hostRequests:      make(map[string]int),
+		hostQuotaSkips:    make(map[string]int),
+		slashVariants:     make(map[string]*slashVariant),
+		blockStreaks:      make(map[string]blockStreak),
+		sessionCookies:    make(map[string]map[string]string),
+		varyChecked:       make(map[string]bool),
+		flowNotify:        make(chan struct{}),
+		runDone:           make(chan struct{}),
 		lastActivity:      time.Now(),
+		seedURLs:          seedURLs,
 		seedHeaders:       seedHeaders,
+		refreshTransport:  baseTransport,
 		reconnedDomains:   make(map[string]bool),
 		allowedDomains:    allowedDomains,
 		disallowedRegexes: disallowedRegexes,
+		deniedPathRegexes: deniedPathRegexes,
 		allowedRegexes:    allowedRegexes,
+		flowLog:           b.flowLog,
 		ctx:               sessionCtx,
 		cancel:            cancel,
 	}
+	if b.config.Crawler.SearchIndex == nil || *b.config.Crawler.SearchIndex {
+		sess.searchIndex = newFlowSearchIndex()
+	}
+	if !opts.NoCookieJar {
+		sess.cookieJar, _ = cookiejar.New(nil)
+		seedCookieJar(sess.cookieJar, seedURLs, seedHeaders)
+	}
+	sess.seedSessionCookies(seedURLs, seedHeaders)
+	sess.seedSessionCookies(seedURLs, opts.Headers)
 
+	c, varyCollector := b.newSessionCollectors(sess, baseTransport)
+	sess.collector = c
+
+	// Register session
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		cancel()
+		return nil, errors.New("backend is closed")
+	}
+
+	// Ensure ID uniqueness
+	for b.sessions[sessionID] != nil {
+		sessionID = ids.Generate(ids.DefaultLength)
+		sess.info.ID = sessionID
+	}
+
+	b.sessions[sessionID] = sess
+	if opts.Label != "" {
+		b.byLabel[opts.Label] = sessionID
+	}
+	b.mu.Unlock()
+	sess.flowLog.persistSession(sess)
+
+	log.Printf("crawler: created session %s (label=%q) with %d domains", sessionID, opts.Label, len(allowedDomains))
+
+	// Start recon in background if enabled
+	var recon bool
+	if b.config.Crawler.Recon != nil {
+		recon = *b.config.Crawler.Recon
+	}
+	if recon && len(allowedDomains) > 0 {
+		sess.reconWg.Add(2)
+		go func() {
+			defer sess.reconWg.Done()
+			b.runReconForSession(sessionCtx, sess, allowedDomains)
+		}()
+		go func() {
+			defer sess.reconWg.Done()
+			b.runSeedHintsForSession(sessionCtx, sess, seedURLs)
+		}()
+	}
+
+	// Start crawling seeds in background
+	go b.runSession(sess, c, varyCollector, seedURLs)
+
+	return &sess.info, nil
+}
+
+// newSessionCollectors builds the session's collector, with every crawl callback installed,
+// and the Vary variant collector when VaryVariants is set (nil otherwise). Deterministic
+// and depth-first sessions also get a fresh frontier. Called on creation and again on resume.
+func (b *CollyBackend) newSessionCollectors(sess *crawlSession, baseTransport http.RoundTripper) (*colly.Collector, *colly.Collector) {
+	opts := sess.opts
+	sessionCtx := sess.ctx
+	allowedDomains := sess.allowedDomains
+
+	// Deterministic and depth-first sessions visit one URL at a time from a frontier instead,
+	// as the order is lost once concurrent requests race
+	if opts.Deterministic || opts.Strategy == crawlStrategyDFS {
+		sess.frontier = &crawlFrontier{depthFirst: opts.Strategy == crawlStrategyDFS, sorted: opts.Deterministic}
+	}
 	c := colly.NewCollector(
-		colly.Async(true),
+		colly.Async(sess.frontier == nil),
 		colly.StdlibContext(sessionCtx),
 	)
 
@@ -287,7 +753,14 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 	if opts.IgnoreRobotsTxt {
 		c.IgnoreRobotsTxt = true
 	}
+	// Error statuses reach OnResponse/OnHTML instead of OnError
+	c.ParseHTTPErrorResponse = opts.FollowLinksOnError
 	c.UserAgent = config.UserAgent()
+	if sess.cookieJar != nil {
+		c.SetCookieJar(sess.cookieJar)
+	} else {
+		c.DisableCookies()
+	}
 
 	// Rate limiting
 	delay := opts.Delay
@@ -298,6 +771,22 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 	if parallelism == 0 {
 		parallelism = b.config.Crawler.Parallelism
 	}
+	if sess.frontier != nil {
+		parallelism = 1
+	}
+	// Host rules go first as colly applies the first rule matching the host
+	for _, l := range opts.HostLimits {
+		hostParallelism := parallelism
+		if l.Parallelism > 0 && sess.frontier == nil {
+			hostParallelism = l.Parallelism
+		}
+		_ = c.Limit(&colly.LimitRule{
+			DomainGlob:  l.HostGlob,
+			Delay:       l.Delay,
+			RandomDelay: l.RandomDelay,
+			Parallelism: hostParallelism,
+		})
+	}
 	_ = c.Limit(&colly.LimitRule{
 		DomainGlob:  "*",
 		Delay:       delay,
@@ -305,19 +794,292 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 		Parallelism: parallelism,
 	})
 
-	// Install capturing transport with body size limit
+	// Install capturing transport with body size limit; colly parses what was captured
+	maxBodyBytes := b.maxBodyBytes
+	if opts.MaxResponseBodyBytes != 0 {
+		maxBodyBytes = opts.MaxResponseBodyBytes
+	}
+	c.MaxBodySize = max(maxBodyBytes, 0)
 	transport := &capturingTransport{
-		base:         http.DefaultTransport,
-		session:      sess,
-		maxBodyBytes: b.maxBodyBytes,
+		base:           baseTransport,
+		session:        sess,
+		maxBodyBytes:   maxBodyBytes,
+		maxHeaderBytes: b.config.MaxHeaderBytes,
+	}
+	if opts.MaxInFlightBytes > 0 {
+		transport.inFlight = newInFlightLimiter(opts.MaxInFlightBytes)
 	}
 	c.WithTransport(transport)
 
+	// Vary variants go through a clone that may revisit URLs, sharing transport and limits
+	var varyCollector *colly.Collector
+	if opts.VaryVariants {
+		varyCollector = sess.newVaryCollector(c)
+	}
+
+	includeSubdomains := *b.config.IncludeSubdomains
+	// mergeSlashVariant reports whether link is a trailing-slash variant of an already
+	// discovered URL that should be skipped, recording it for replay should that URL fail.
+	mergeSlashVariant := func(from *colly.Request, foundOn, link string) bool {
+		key, ok := trailingSlashKey(link)
+		if !ok {
+			return false
+		}
+
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		v := sess.slashVariants[key]
+		if v == nil {
+			sess.slashVariants[key] = &slashVariant{link: link, merged: make(map[string]mergedVariant)}
+			return false
+		} else if v.link == link || v.failed {
+			return false
+		}
+		if _, exists := v.merged[link]; !exists {
+			v.merged[link] = mergedVariant{from: from, foundOn: foundOn}
+			sess.slashMerged++
+		}
+		return true
+	}
+
+	var visitDiscoveredFrom func(from *colly.Request, foundOn, link string)
+
+	// checkBlocked labels a response that looks like a block or rate limit and counts it toward
+	// BlockThreshold, stopping the session once a host reaches it. Disabled when the threshold is 0.
+	checkBlocked := func(r *colly.Response) string {
+		if opts.BlockThreshold <= 0 {
+			return ""
+		}
+		kind := blockKind(r.StatusCode, r.Body)
+		host := r.Request.URL.Host
+		sess.mu.Lock()
+		tripped := sess.observeBlock(host, kind, r.StatusCode, r.Body)
+		sess.mu.Unlock()
+		if tripped {
+			sess.haltBlocked(host, kind, r.StatusCode)
+		}
+		return kind
+	}
+	// retryUnauthorized handles a 401 when TokenRefresh is set: it refreshes the token (or
+	// picks up one refreshed since the request was sent) and retries the request once. The
+	// failed attempt is discarded; reports whether a retry was queued.
+	retryUnauthorized := func(r *colly.Response) bool {
+		if opts.TokenRefresh == nil || r.StatusCode != http.StatusUnauthorized {
+			return false
+		}
+		link := r.Request.URL.String()
+		if _, retried := sess.tokenRetried.LoadOrStore(r.Request.Method+" "+link, true); retried {
+			return false
+		} else if !sess.refreshToken(r.Request.Headers.Get("Authorization"), link) {
+			return false
+		}
+
+		// Keep FoundOn for the retried flow
+		if parent, ok := sess.requestParents.LoadAndDelete(r.Request.ID); ok && parent != "seed" {
+			sess.parentURLs.Store(link, parent)
+		}
+		if err := r.Request.Retry(); err != nil {
+			sess.parentURLs.Delete(link)
+			return false
+		}
+		if captureID, ok := sess.requestCaptures.LoadAndDelete(r.Request.ID); ok {
+			sess.captureStore.Delete(captureID)
+		}
+		sess.mu.Lock()
+		sess.urlsQueued--
+		sess.mu.Unlock()
+		return true
+	}
+	// resolveSlashVariant records the outcome of a requested URL; if it failed, variants
+	// merged into it are crawled after all since the site may serve them differently.
+	resolveSlashVariant := func(requested *url.URL, failed bool) {
+		if !opts.MergeTrailingSlash {
+			return
+		}
+		key, ok := trailingSlashKey(requested.String())
+		if !ok {
+			return
+		}
+
+		sess.mu.Lock()
+		v := sess.slashVariants[key]
+		if v == nil || v.resolved {
+			sess.mu.Unlock()
+			return
+		}
+		v.resolved = true
+		v.failed = failed
+		var replay map[string]mergedVariant
+		if failed {
+			replay = v.merged
+			sess.slashMerged -= len(replay)
+			v.merged = nil
+		}
+		sess.mu.Unlock()
+
+		for link, m := range replay {
+			visitDiscoveredFrom(m.from, m.foundOn, link)
+		}
+	}
+
+	// visitDiscoveredFrom queues a newly discovered link, recording the page it was found on.
+	// Scope is enforced by the collector's domain and path filters; out-of-scope
+	// http(s) links are recorded as external references instead of being visited.
+	visitDiscoveredFrom = func(from *colly.Request, foundOn, link string) {
+		if link == "" {
+			return
+		} else if host, ok := externalLinkHost(link, allowedDomains, includeSubdomains); ok {
+			sess.mu.Lock()
+			if !sess.externalSeen[link] {
+				sess.externalSeen[link] = true
+				sess.externalLinks = append(sess.externalLinks, ExternalLink{
+					URL:     link,
+					Host:    host,
+					FoundOn: foundOn,
+				})
+			}
+			sess.mu.Unlock()
+			return
+		}
+
+		// Dedup and visit without ignored parameters, remembering the link as discovered
+		original := link
+		link, stripped := stripQueryParams(link, opts.IgnoreQueryParams)
+		if opts.MergeTrailingSlash && mergeSlashVariant(from, foundOn, link) {
+			return
+		}
+
+		sess.mu.Lock()
+		seen := sess.urlsSeen[link]
+		if !seen {
+			sess.urlsSeen[link] = true
+			sess.linkOrigins[link] = crawlLinkOrigin{Depth: from.Depth + 1, FoundOn: foundOn}
+		}
+		sess.mu.Unlock()
+
+		if !seen {
+			// Store parent URL for this link (will be retrieved in OnRequest)
+			sess.parentURLs.Store(link, foundOn)
+			if stripped {
+				sess.originalURLs.Store(link, original)
+			}
+			if sess.frontier != nil {
+				sess.frontier.push(from, link)
+			} else {
+				_ = from.Visit(link)
+			}
+		}
+	}
+	// visitDiscovered queues a link found on the page being processed.
+	visitDiscovered := func(from *colly.Request, link string) {
+		visitDiscoveredFrom(from, from.URL.String(), link)
+	}
+
+	// discoverRedirect queues the Location of a captured 3xx response as a link found on the
+	// redirecting URL. Followed redirects are captured at their final hop, so this picks up
+	// redirects the client refused to follow, such as out-of-scope targets (recorded as external).
+	discoverRedirect := func(from *colly.Request, data *capturedData) {
+		if target := data.redirectLocation(); target != "" {
+			visitDiscoveredFrom(from, data.URL, target)
+		}
+	}
+
+	// recordMethodCheck stores one half of a form GET/POST comparison and
+	// computes the verdict once both responses (or errors) are in.
+	recordMethodCheck := func(ctx *colly.Context, flowID string, status int, body []byte) {
+		formID := ctx.Get(methodCheckFormKey)
+		if formID == "" {
+			return
+		}
+		hash := sha256.Sum256(body)
+
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		st := sess.methodChecks[formID]
+		if st == nil {
+			return
+		}
+		// Links followed from a check response inherit its context; only the first record counts
+		isGet := ctx.Get(methodCheckMethodKey) == http.MethodGet
+		if (isGet && st.getDone) || (!isGet && st.postDone) {
+			return
+		} else if isGet {
+			st.getDone, st.getBody = true, hash
+			st.result.GetFlowID, st.result.GetStatus = flowID, status
+		} else {
+			st.postDone, st.postBody = true, hash
+			st.result.PostFlowID, st.result.PostStatus = flowID, status
+		}
+		if st.getDone && st.postDone {
+			st.result.Verdict, st.result.WrongMethodAccepted = formMethodVerdict(
+				st.result.GetStatus, st.result.PostStatus, st.getBody == st.postBody)
+		}
+	}
+
+	// checkFormMethods submits a form's default values as both GET (query) and POST (body)
+	// to the form action, deduplicated by action and parameters across pages.
+	checkFormMethods := func(from *colly.Request, form DiscoveredForm, data map[string]string) {
+		if form.Method != http.MethodGet && form.Method != http.MethodPost {
+			return
+		}
+		getURL, err := url.Parse(form.Action)
+		if err != nil || (getURL.Scheme != "http" && getURL.Scheme != "https") {
+			return
+		}
+		getURL.Fragment = ""
+		postURL := getURL.String()
+		query := getURL.Query()
+		values := url.Values{}
+		for k, v := range data {
+			query.Set(k, v)
+			values.Set(k, v)
+		}
+		getURL.RawQuery = query.Encode()
+		encoded := values.Encode()
+
+		sess.mu.Lock()
+		key := postURL + " " + encoded
+		if sess.methodCheckKeys[key] {
+			sess.mu.Unlock()
+			return
+		}
+		sess.methodCheckKeys[key] = true
+		sess.methodChecks[form.ID] = &methodCheckState{
+			result: FormMethodCheck{Verdict: formMethodPending},
+		}
+		sess.mu.Unlock()
+
+		for _, method := range []string{http.MethodGet, http.MethodPost} {
+			target, body := getURL.String(), io.Reader(nil)
+			if method == http.MethodPost {
+				target, body = postURL, strings.NewReader(encoded)
+			}
+			// Fresh context so the pair is not confused with the parent page's request
+			reqCtx := colly.NewContext()
+			reqCtx.Put(methodCheckFormKey, form.ID)
+			reqCtx.Put(methodCheckMethodKey, method)
+			sess.parentURLs.Store(target, from.URL.String())
+			if err := c.Request(method, target, body, reqCtx, nil); err != nil {
+				// Not sent (already visited, filtered); mark the half done without a status
+				sess.parentURLs.Delete(target)
+				recordMethodCheck(reqCtx, "", 0, nil)
+			}
+		}
+	}
+
 	// Set up request callback for headers and capture ID
 	c.OnRequest(func(r *colly.Request) {
-		// Check AllowedPaths filter first (before counting)
+		if opts.SafeMode {
+			if reason := safeModeRequestBlock(r.Method); reason != "" {
+				log.Printf("crawler: safe mode blocked %s %s in session %s (%s)", r.Method, r.URL, sess.info.ID, reason)
+				r.Abort()
+				return
+			}
+		}
+
+		// Check AllowedPaths and DisallowedPathsRegex filters first (before counting)
+		path := r.URL.Path
 		if len(sess.allowedRegexes) > 0 {
-			path := r.URL.Path
 			allowed := slices.ContainsFunc(sess.allowedRegexes, func(re *regexp.Regexp) bool {
 				return re.MatchString(path)
 			})
@@ -326,22 +1088,52 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 				return
 			}
 		}
+		if slices.ContainsFunc(sess.deniedPathRegexes, func(re *regexp.Regexp) bool {
+			return re.MatchString(path)
+		}) {
+			r.Abort()
+			return
+		}
 
-		// Check MaxRequests limit and increment counters atomically
+		// Check MaxRequests, MaxHosts and MaxPagesPerHost limits and increment counters atomically
+		host := strings.ToLower(r.URL.Hostname())
 		sess.mu.Lock()
-		if opts.MaxRequests > 0 && sess.requestCount >= opts.MaxRequests {
+		if origin, ok := sess.linkOrigins[r.URL.String()]; ok {
+			r.Depth = origin.Depth // a link requeued on resume is visited at the depth it was found
+		}
+		if opts.MaxDepth > 0 && r.Depth > opts.MaxDepth {
+			sess.mu.Unlock()
+			r.Abort()
+			return
+		} else if opts.MaxRequests > 0 && sess.requestCount >= opts.MaxRequests {
+			sess.mu.Unlock()
+			r.Abort()
+			return
+		} else if !sess.hosts[host] {
+			if opts.MaxHosts > 0 && len(sess.hosts) >= opts.MaxHosts {
+				sess.skippedHosts[host] = true
+				sess.mu.Unlock()
+				r.Abort()
+				return
+			}
+			sess.hosts[host] = true
+		}
+		if opts.MaxPagesPerHost > 0 && sess.hostRequests[host] >= opts.MaxPagesPerHost {
+			sess.hostQuotaSkips[host]++
 			sess.mu.Unlock()
 			r.Abort()
 			return
 		}
+		sess.hostRequests[host]++
 		sess.requestCount++
 		sess.urlsQueued++
+		sess.urlsRequested[r.URL.String()] = true
 		sess.lastActivity = time.Now()
 		sess.mu.Unlock()
 
 		// Generate capture ID for correlation
 		captureID := ids.Generate(ids.DefaultLength)
-		r.Ctx.Put("capture_id", captureID)
+		sess.requestCaptures.Store(r.ID, captureID)
 		r.Headers.Set(captureIDHeader, captureID)
 
 		// Get parent URL from stored map, or use "seed" for initial seeds
@@ -349,35 +1141,88 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 		if p, ok := sess.parentURLs.LoadAndDelete(r.URL.String()); ok {
 			parentURL = p.(string)
 		}
-		r.Ctx.Put("parent_url", parentURL)
+		sess.requestParents.Store(r.ID, parentURL)
 
 		// Apply seed headers first (auth context from resolved flows)
 		// These are set before custom headers so user headers can override if needed
+		// The seed Cookie header is left to the jar, which carries cookies set since
 		sess.mu.RLock()
 		for k, v := range sess.seedHeaders {
+			if sess.cookieJar != nil && strings.EqualFold(k, "Cookie") {
+				continue
+			}
 			r.Headers.Set(k, v)
 		}
 		sess.mu.RUnlock()
 
+		// Send the parent page as Referer, as a browser following the link would
+		if parentURL != "seed" {
+			referer := opts.Referer
+			if referer == "" {
+				if parent, err := url.Parse(parentURL); err == nil {
+					stored, _ := sess.referrerPolicies.Load(parentURL)
+					policy, _ := stored.(string)
+					referer = refererFor(parent, r.URL, policy)
+				}
+			}
+			if referer != "" && referer != refererNone {
+				r.Headers.Set("Referer", referer)
+			} else {
+				r.Headers.Del("Referer")
+			}
+		}
+
 		// Apply custom headers from options (override seed headers if specified)
 		for k, v := range opts.Headers {
 			r.Headers.Set(k, v)
 		}
+
+		// Apply per-domain headers last so host-specific values win
+		applyDomainHeaders(opts.DomainHeaders, r.URL.Hostname(), r.Headers.Set)
+
+		// A refreshed token replaces the Authorization only of hosts that rejected it
+		sess.mu.RLock()
+		if sess.authToken != "" && sess.authTokenHosts[strings.ToLower(r.URL.Host)] {
+			r.Headers.Set("Authorization", "Bearer "+sess.authToken)
+		}
+		sess.mu.RUnlock()
 	})
 
 	// Response callback for capturing flows
+	contentTypes := crawlContentTypes(opts)
+	recordAllJSON := len(opts.AllowedContentTypes) == 0 // the defaults include +json types like application/hal+json
 	c.OnResponse(func(r *colly.Response) {
+		if retryUnauthorized(r) { // only reachable with FollowLinksOnError
+			return
+		}
+		parent, _ := sess.requestParents.LoadAndDelete(r.Request.ID)
+		foundOn, _ := parent.(string)
 		ct := r.Headers.Get("Content-Type")
 		// Filter by content-type (empty is allowed for HTML pages without explicit type)
-		if ct != "" && !isTextContentType(ct) {
+		if ct != "" && !matchesContentType(ct, contentTypes) && !(recordAllJSON && isJSONMediaType(contentMediaType(ct))) {
+			checkBlocked(r)
+			recordMethodCheck(r.Ctx, "", r.StatusCode, r.Body)
+			if captureID, ok := sess.requestCaptures.LoadAndDelete(r.Request.ID); ok {
+				if captured, ok := sess.captureStore.LoadAndDelete(captureID); ok {
+					discoverRedirect(r.Request, captured.(*capturedData))
+				}
+			}
+			resolveSlashVariant(r.Request.URL, r.StatusCode >= 400)
 			sess.mu.Lock()
+			if r.StatusCode >= 400 { // only reachable with FollowLinksOnError
+				sess.errors = append(sess.errors, CrawlError{
+					URL:    r.Request.URL.String(),
+					Error:  http.StatusText(r.StatusCode),
+					Status: r.StatusCode,
+				})
+			}
 			sess.urlsQueued--
 			sess.mu.Unlock()
 			return
 		}
 
-		captureID := r.Ctx.Get("capture_id")
-		if captureID == "" {
+		captureID, ok := sess.requestCaptures.LoadAndDelete(r.Request.ID)
+		if !ok {
 			sess.mu.Lock()
 			sess.urlsQueued--
 			sess.mu.Unlock()
@@ -393,6 +1238,7 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 			return
 		}
 		data := captured.(*capturedData)
+		discoverRedirect(r.Request, data)
 
 		// Reassemble response from pre-split headers and body
 		respBytes := append(data.RespHeaders, data.RespBody...)
@@ -404,6 +1250,36 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 			flowPath += "?" + r.Request.URL.RawQuery
 		}
 
+		var findings []string
+		if opts.DetectDirListing && isDirectoryListing(r.Body) {
+			findings = append(findings, findingDirectoryListing)
+		}
+		if kind := checkBlocked(r); kind != "" {
+			findings = append(findings, kind)
+		}
+		var commentURLs, comments []string
+		if opts.ExtractCommentURLs && contentMediaType(ct) == "text/html" {
+			if commentURLs, comments = extractHTMLComments(r.Body); len(comments) > 0 {
+				findings = append(findings, findingHTMLComment)
+			}
+		}
+		var cookieBefore, cookieAfter string
+		if before, after, rotated := sess.observeSessionCookies(r.Request.URL.Hostname(), *r.Headers); rotated {
+			findings = append(findings, findingSessionCookieRotated)
+			cookieBefore, cookieAfter = before, after
+			log.Printf("crawler: session %s: %s rotated session cookie %s -> %s", sess.info.ID, r.Request.URL, before, after)
+		}
+		var jsonpCallback string
+		if isJavaScriptContentType(ct) {
+			if jsonpCallback = detectJSONP(extractParams(data.Request), ct, r.Body); jsonpCallback != "" {
+				findings = append(findings, findingJSONP)
+			}
+		}
+		sensitive := scanSensitiveData(b.secretRules, r.Body)
+		if len(sensitive) > 0 {
+			findings = append(findings, findingSensitiveData)
+		}
+
 		flowID := ids.Generate(ids.DefaultLength)
 		flow := &CrawlFlow{
 			ID:             flowID,
@@ -412,7 +1288,7 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 			Host:           flowHost,
 			Path:           flowPath,
 			Method:         r.Request.Method,
-			FoundOn:        r.Ctx.Get("parent_url"),
+			FoundOn:        foundOn,
 			Depth:          r.Request.Depth,
 			StatusCode:     r.StatusCode,
 			ContentType:    ct,
@@ -422,36 +1298,149 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 			Truncated:      data.Truncated,
 			Duration:       data.Duration,
 			DiscoveredAt:   time.Now(),
+			Findings:       findings,
+			ReferrerPolicy: pageReferrerPolicy(*r.Headers, r.Body),
+			JSONPCallback:  jsonpCallback,
+			HTMLComments:   comments,
+			CookieBefore:   cookieBefore,
+			CookieAfter:    cookieAfter,
+			RedirectTo:     data.redirectLocation(),
+			SensitiveData:  sensitive,
+		}
+		flow.HeadersTruncated = data.HeadersTruncated
+		hops := sess.redirectHopFlows(data, flow.FoundOn, flow.Depth)
+		if len(hops) > 0 {
+			flow.RedirectedFrom = hops[len(hops)-1].ID
+			for _, hop := range hops {
+				flow.RedirectChain = append(flow.RedirectChain, hop.URL)
+			}
+		}
+		if original, ok := sess.originalURLs.LoadAndDelete(flow.URL); ok {
+			flow.OriginalURL = original.(string)
+		}
+		if flow.ReferrerPolicy != "" {
+			sess.referrerPolicies.Store(flow.URL, flow.ReferrerPolicy)
+		}
+
+		if opts.SimilarityThreshold > 0 && isTextContentType(ct) {
+			flow.SimHash, _ = bodySimHash(r.Body)
+		}
+
+		// Tokenize outside the lock; the index itself is updated with the append
+		// Redirect hops are recorded ahead of the flow they led to
+		flows := append(hops, flow)
+		var searchTokens [][]string
+		if sess.searchIndex != nil {
+			for _, f := range flows {
+				searchTokens = append(searchTokens, flowSearchTokens(f.Request, f.Response))
+			}
 		}
 
 		sess.mu.Lock()
-		sess.flowsByID[flowID] = flow
-		sess.flowsOrdered = append(sess.flowsOrdered, flow)
+		for i, f := range flows {
+			sess.flowsByID[f.ID] = f
+			sess.flowsOrdered = append(sess.flowsOrdered, f)
+			if sess.searchIndex != nil {
+				sess.searchIndex.add(len(sess.flowsOrdered)-1, searchTokens[i])
+			}
+		}
+		sess.notifyFlows()
+		if r.StatusCode >= 400 { // only reachable with FollowLinksOnError
+			sess.errors = append(sess.errors, CrawlError{
+				FlowID: flowID,
+				URL:    flow.URL,
+				Error:  http.StatusText(r.StatusCode),
+				Status: r.StatusCode,
+			})
+		}
 		sess.urlsQueued--
 		sess.lastActivity = time.Now()
+		sess.persistFlows(flows...)
 		sess.mu.Unlock()
+
+		recordMethodCheck(r.Ctx, flowID, r.StatusCode, r.Body)
+		resolveSlashVariant(r.Request.URL, r.StatusCode >= 400)
+		if varyCollector != nil && r.Request.Method == http.MethodGet {
+			sess.queueVaryVariants(varyCollector, r.Request, flowID, r.Headers.Values("Vary"))
+		}
+
+		// URL discovery from JSON API responses (HATEOAS links, pagination, etc.)
+		if opts.ExtractJSONURLs && isJSONMediaType(contentMediaType(ct)) {
+			for _, candidate := range extractJSONURLs(r.Body) {
+				visitDiscovered(r.Request, r.Request.AbsoluteURL(candidate))
+			}
+		}
+		// Stylesheet references resolve relative to the stylesheet itself
+		if opts.ExtractCSSURLs && contentMediaType(ct) == "text/css" {
+			for _, candidate := range extractCSSURLs(r.Body) {
+				visitDiscovered(r.Request, r.Request.AbsoluteURL(candidate))
+			}
+		}
+		// URLs and paths left in HTML comments
+		for _, candidate := range commentURLs {
+			visitDiscovered(r.Request, r.Request.AbsoluteURL(candidate))
+		}
+		// Client-side routes declared in script bundles
+		if opts.ExtractJSRoutes && isJavaScriptContentType(ct) {
+			sess.recordClientRoutes(extractClientRoutes(r.Body), flow.URL)
+		}
+		// Request URLs in script bundles resolve against the page that loaded the script
+		if opts.ExtractJSEndpoints && isJavaScriptContentType(ct) {
+			base := r.Request.URL
+			if page, err := url.Parse(flow.FoundOn); err == nil && page.Host != "" {
+				base = page
+			}
+			for _, e := range sess.recordJSEndpoints(extractJSEndpoints(r.Body), base, flow.URL) {
+				if jsEndpointCrawlable(e, base) {
+					visitDiscovered(r.Request, e.URL)
+				}
+			}
+		}
 	})
 
 	// URL discovery from links
 	c.OnHTML("a[href]", func(e *colly.HTMLElement) {
-		link := e.Request.AbsoluteURL(e.Attr("href"))
-		if link == "" {
+		href := e.Attr("href")
+		if opts.DetectDirListing && autoindexSortRe.MatchString(href) {
 			return
 		}
+		visitDiscovered(e.Request, e.Request.AbsoluteURL(href))
+	})
 
-		sess.mu.Lock()
-		seen := sess.urlsSeen[link]
-		if !seen {
-			sess.urlsSeen[link] = true
-		}
-		sess.mu.Unlock()
+	// Stylesheets and inline CSS, so url() and @import references become reachable
+	if opts.ExtractCSSURLs {
+		c.OnHTML(`link[rel~="stylesheet"][href]`, func(e *colly.HTMLElement) {
+			visitDiscovered(e.Request, e.Request.AbsoluteURL(e.Attr("href")))
+		})
+		c.OnHTML("style", func(e *colly.HTMLElement) {
+			for _, candidate := range extractCSSURLs([]byte(e.Text)) {
+				visitDiscovered(e.Request, e.Request.AbsoluteURL(candidate))
+			}
+		})
+		c.OnHTML("[style]", func(e *colly.HTMLElement) {
+			for _, candidate := range extractCSSURLs([]byte(e.Attr("style"))) {
+				visitDiscovered(e.Request, e.Request.AbsoluteURL(candidate))
+			}
+		})
+	}
 
-		if !seen {
-			// Store parent URL for this link (will be retrieved in OnRequest)
-			sess.parentURLs.Store(link, e.Request.URL.String())
-			_ = e.Request.Visit(link)
-		}
-	})
+	// Scripts, so bundles are scanned for routes or endpoints
+	if opts.ExtractJSRoutes || opts.ExtractJSEndpoints {
+		c.OnHTML("script[src]", func(e *colly.HTMLElement) {
+			visitDiscovered(e.Request, e.Request.AbsoluteURL(e.Attr("src")))
+		})
+	}
+	// Inline scripts and hash-bang links declare routes directly
+	if opts.ExtractJSRoutes {
+		c.OnHTML("script:not([src])", func(e *colly.HTMLElement) {
+			sess.recordClientRoutes(extractClientRoutes([]byte(e.Text)), e.Request.URL.String())
+		})
+		c.OnHTML(`a[href^="#/"], a[href^="#!/"]`, func(e *colly.HTMLElement) {
+			if href := strings.TrimSpace(e.Attr("href")); routeHasWordRe.MatchString(href) {
+				sess.recordClientRoutes([]ClientRoute{{Route: href, Kind: routeKindHashbang}}, e.Request.URL.String())
+			}
+		})
+	}
 
 	// Form extraction - config default, then explicit option override
 	extractForms := true
@@ -469,8 +1458,8 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 			sess.forms = append(sess.forms, form)
 			sess.mu.Unlock()
 
-			// Optionally submit form
-			if opts.SubmitForms {
+			// Optionally submit form, or compare GET and POST submissions
+			if opts.SubmitForms || opts.CheckFormMethods {
 				allowed := true
 				for _, re := range sess.disallowedRegexes {
 					if re.MatchString(form.Action) {
@@ -478,18 +1467,40 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 						break
 					}
 				}
+				formData := extractFormData(e)
+				if allowed && opts.SafeMode {
+					if reason := safeModeFormBlock(form, formData); reason != "" {
+						log.Printf("crawler: safe mode blocked form %s %s in session %s (%s)", form.Method, form.Action, sess.info.ID, reason)
+						allowed = false
+					}
+				}
 				if allowed {
-					formData := extractFormData(e)
-					_ = e.Request.Post(form.Action, formData)
+					if opts.CheckFormMethods {
+						// The POST half doubles as the SubmitForms submission
+						checkFormMethods(e.Request, form, formData)
+					} else {
+						_ = e.Request.Post(form.Action, formData)
+					}
 				}
 			}
 		})
 	}
 
 	c.OnError(func(r *colly.Response, err error) {
-		// Clean up capture store to prevent memory leak
-		if captureID := r.Ctx.Get("capture_id"); captureID != "" {
-			sess.captureStore.LoadAndDelete(captureID)
+		if retryUnauthorized(r) {
+			return
+		}
+		sess.requestParents.Delete(r.Request.ID)
+		sess.originalURLs.Delete(r.Request.URL.String())
+		// Clean up capture store to prevent memory leak; unfollowed redirects still yield their target
+		if captureID, ok := sess.requestCaptures.LoadAndDelete(r.Request.ID); ok {
+			if captured, ok := sess.captureStore.LoadAndDelete(captureID); ok {
+				discoverRedirect(r.Request, captured.(*capturedData))
+			}
+		}
+		resolveSlashVariant(r.Request.URL, true)
+		if r.StatusCode > 0 { // transport errors say nothing about blocking
+			checkBlocked(r)
 		}
 
 		crawlErr := CrawlError{
@@ -501,72 +1512,73 @@ func (b *CollyBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*C
 		sess.mu.Lock()
 		sess.errors = append(sess.errors, crawlErr)
 		sess.urlsQueued--
+		if sessionCtx.Err() != nil { // abandoned by a stop; requested again on resume
+			delete(sess.urlsRequested, r.Request.URL.String())
+		}
 		sess.lastActivity = time.Now()
 		sess.mu.Unlock()
-	})
 
-	sess.collector = c
-
-	// Register session
-	b.mu.Lock()
-	if b.closed {
-		b.mu.Unlock()
-		cancel()
-		return nil, errors.New("backend is closed")
-	}
-
-	// Ensure ID uniqueness
-	for b.sessions[sessionID] != nil {
-		sessionID = ids.Generate(ids.DefaultLength)
-		sess.info.ID = sessionID
-	}
-
-	b.sessions[sessionID] = sess
-	if opts.Label != "" {
-		b.byLabel[opts.Label] = sessionID
-	}
-	b.mu.Unlock()
-
-	log.Printf("crawler: created session %s (label=%q) with %d domains", sessionID, opts.Label, len(allowedDomains))
+		recordMethodCheck(r.Ctx, "", r.StatusCode, r.Body)
+	})
 
-	// Start recon in background if enabled
-	var recon bool
-	if b.config.Crawler.Recon != nil {
-		recon = *b.config.Crawler.Recon
-	}
-	if recon && len(allowedDomains) > 0 {
-		sess.reconWg.Add(1)
-		go func() {
-			defer sess.reconWg.Done()
-			b.runReconForSession(sessionCtx, sess, allowedDomains)
-		}()
-	}
+	return c, varyCollector
+}
 
-	// Start crawling seeds in background
-	go func() {
-		for _, seedURL := range seedURLs {
-			sess.mu.Lock()
-			sess.urlsSeen[seedURL] = true
-			sess.mu.Unlock()
+// runSession visits the seed URLs, waits for the crawl to finish, marks the session
+// completed unless it was stopped, and fires the completion webhook.
+func (b *CollyBackend) runSession(sess *crawlSession, c, varyCollector *colly.Collector, seedURLs []string) {
+	opts := sess.opts
+	sessionCtx := sess.ctx
+	for _, seedURL := range seedURLs {
+		sess.mu.Lock()
+		sess.urlsSeen[seedURL] = true
+		if key, ok := trailingSlashKey(seedURL); ok && opts.MergeTrailingSlash && sess.slashVariants[key] == nil {
+			sess.slashVariants[key] = &slashVariant{link: seedURL, merged: make(map[string]mergedVariant)}
+		}
+		depth := max(sess.linkOrigins[seedURL].Depth, 1) // resumed links keep their depth
+		sess.mu.Unlock()
+		if sess.frontier != nil {
+			sess.frontier.pushAt(seedURL, depth)
+		} else {
 			_ = c.Visit(seedURL)
 		}
+	}
 
+	for {
 		// Wait for recon to finish discovering URLs
 		sess.reconWg.Wait()
+		if sess.frontier != nil {
+			// Recon results are queued before the first visit so they sort into the order
+			sess.frontier.drain(c, sessionCtx.Done())
+		}
 
-		// Wait for all URLs to be crawled
+		// Wait for all URLs to be crawled, then the variants their responses queued
 		c.Wait()
+		if varyCollector != nil {
+			varyCollector.Wait()
+		}
 
-		sess.mu.Lock()
-		if sess.info.State == crawlStateRunning {
-			sess.info.State = crawlStateCompleted
+		// Seeds and recon results pushed meanwhile need another drain
+		if sess.frontier == nil || sessionCtx.Err() != nil || sess.frontier.closeIfEmpty() {
+			break
 		}
-		sess.mu.Unlock()
+	}
 
-		log.Printf("crawler: session %s completed", sessionID)
-	}()
+	sess.mu.Lock()
+	if sess.info.State == crawlStateRunning {
+		sess.info.State = crawlStateCompleted
+		sess.notifyFlows()
+	}
+	sess.mu.Unlock()
+	sess.flowLog.persistSession(sess)
 
-	return &sess.info, nil
+	log.Printf("crawler: session %s completed", sess.info.ID)
+	close(sess.runDone)
+
+	// Fires for stopped sessions too, once in-flight requests have been abandoned
+	if opts.CompletionWebhook != "" {
+		b.deliverCompletionWebhook(opts.CompletionWebhook, sess.completionSummary())
+	}
 }
 
 func (b *CollyBackend) AddSeeds(ctx context.Context, sessionID string, seeds []CrawlSeed) error {
@@ -582,6 +1594,13 @@ func (b *CollyBackend) AddSeeds(ctx context.Context, sessionID string, seeds []C
 	if state != crawlStateRunning {
 		return fmt.Errorf("session %s is not running (state: %s); create a new session instead", sessionID, state)
 	}
+	// Keeps the run draining until these seeds and their recon results are visited
+	if sess.frontier != nil {
+		if !sess.frontier.hold() {
+			return fmt.Errorf("session %s is finishing; create a new session instead", sessionID)
+		}
+		defer sess.frontier.release()
+	}
 
 	newDomains, seedURLs, newHeaders, err := b.resolveSeeds(ctx, seeds, nil)
 	if err != nil {
@@ -601,6 +1620,11 @@ func (b *CollyBackend) AddSeeds(ctx context.Context, sessionID string, seeds []C
 		}
 		sess.mu.Unlock()
 	}
+	if sess.cookieJar != nil {
+		seedCookieJar(sess.cookieJar, seedURLs, newHeaders)
+	}
+	sess.seedSessionCookies(seedURLs, newHeaders)
+	sess.seedSessionCookies(seedURLs, sess.opts.Headers)
 
 	// Start recon for new domains if enabled
 	var recon bool
@@ -608,10 +1632,24 @@ func (b *CollyBackend) AddSeeds(ctx context.Context, sessionID string, seeds []C
 		recon = *b.config.Crawler.Recon
 	}
 	if recon && len(newDomains) > 0 {
-		sess.reconWg.Add(1)
+		sess.reconWg.Add(2)
+		if sess.frontier != nil { // released by each recon run; cannot fail while AddSeeds holds it
+			sess.frontier.hold()
+			sess.frontier.hold()
+		}
 		go func() {
 			defer sess.reconWg.Done()
 			b.runReconForSession(sess.ctx, sess, newDomains)
+			if sess.frontier != nil {
+				sess.frontier.release()
+			}
+		}()
+		go func() {
+			defer sess.reconWg.Done()
+			b.runSeedHintsForSession(sess.ctx, sess, seedURLs)
+			if sess.frontier != nil {
+				sess.frontier.release()
+			}
 		}()
 	}
 
@@ -623,16 +1661,111 @@ func (b *CollyBackend) AddSeeds(ctx context.Context, sessionID string, seeds []C
 		}
 		sess.mu.Unlock()
 
-		if !seen {
-			_ = sess.collector.Visit(seedURL)
-		}
-	}
+		if !seen {
+			if sess.frontier != nil {
+				sess.frontier.push(nil, seedURL)
+			} else {
+				_ = sess.collector.Visit(seedURL)
+			}
+		}
+	}
+
+	log.Printf("crawler: added %d seeds to session %s", len(seedURLs), sessionID)
+	return nil
+}
+
+func (b *CollyBackend) GetStatus(ctx context.Context, sessionID string) (*CrawlStatus, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sess.mu.RLock()
+	defer sess.mu.RUnlock()
+
+	hosts := bulk.MapKeysSlice(sess.hosts)
+	slices.Sort(hosts)
+	skippedHosts := bulk.MapKeysSlice(sess.skippedHosts)
+	slices.Sort(skippedHosts)
+
+	return &CrawlStatus{
+		State:               sess.info.State,
+		URLsQueued:          sess.urlsQueued,
+		URLsVisited:         len(sess.flowsOrdered),
+		URLsErrored:         len(sess.errors),
+		FormsDiscovered:     len(sess.forms),
+		Duration:            time.Since(sess.startedAt),
+		LastActivity:        sess.lastActivity,
+		Hosts:               hosts,
+		SkippedHosts:        skippedHosts,
+		HostQuotaSkips:      maps.Clone(sess.hostQuotaSkips),
+		MergedSlashVariants: sess.slashMerged,
+		BlockedNote:         sess.blockedNote,
+		TokenRefreshes:      slices.Clone(sess.tokenRefreshes),
+		DiscoveredPaths:     slices.Clone(sess.discoveredPaths),
+	}, nil
+}
+
+// statusStreamInterval debounces StreamStatus snapshots of a running session.
+const statusStreamInterval = time.Second
+
+func (b *CollyBackend) StreamStatus(ctx context.Context, sessionID string) (<-chan CrawlStatus, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	sessionID = sess.info.ID
+
+	// Capture the current run; stopping cancels its context and completion closes runDone
+	sess.mu.RLock()
+	sessionCtx, runDone := sess.ctx, sess.runDone
+	sess.mu.RUnlock()
+
+	ch := make(chan CrawlStatus, 1)
+	go func() {
+		defer close(ch)
+
+		var lastActivity time.Time
+		ticker := time.NewTicker(statusStreamInterval)
+		defer ticker.Stop()
+		for first := true; ; first = false {
+			var ended bool
+			if !first {
+				select {
+				case <-ctx.Done():
+					return
+				case <-sessionCtx.Done():
+					ended = true
+				case <-runDone:
+					ended = true
+				case <-ticker.C:
+				}
+			}
+
+			status, err := b.GetStatus(ctx, sessionID)
+			if err != nil {
+				return
+			}
+			ended = ended || status.State != crawlStateRunning
+			if !first && !ended && status.LastActivity.Equal(lastActivity) {
+				continue
+			}
+			lastActivity = status.LastActivity
 
-	log.Printf("crawler: added %d seeds to session %s", len(seedURLs), sessionID)
-	return nil
+			select {
+			case ch <- *status:
+			case <-ctx.Done():
+				return
+			}
+			if ended {
+				return
+			}
+		}
+	}()
+	return ch, nil
 }
 
-func (b *CollyBackend) GetStatus(ctx context.Context, sessionID string) (*CrawlStatus, error) {
+func (b *CollyBackend) GetStats(ctx context.Context, sessionID string) (*CrawlStats, error) {
 	sess, err := b.resolveSession(sessionID)
 	if err != nil {
 		return nil, err
@@ -641,15 +1774,27 @@ func (b *CollyBackend) GetStatus(ctx context.Context, sessionID string) (*CrawlS
 	sess.mu.RLock()
 	defer sess.mu.RUnlock()
 
-	return &CrawlStatus{
-		State:           sess.info.State,
-		URLsQueued:      sess.urlsQueued,
-		URLsVisited:     len(sess.flowsOrdered),
-		URLsErrored:     len(sess.errors),
-		FormsDiscovered: len(sess.forms),
-		Duration:        time.Since(sess.startedAt),
-		LastActivity:    sess.lastActivity,
-	}, nil
+	stats := &CrawlStats{
+		TotalFlows:   len(sess.flowsOrdered),
+		StatusCodes:  make(map[int]int),
+		ContentTypes: make(map[string]int),
+	}
+	for _, flow := range sess.flowsOrdered {
+		stats.StatusCodes[flow.StatusCode]++
+		stats.ContentTypes[contentMediaType(flow.ContentType)]++
+	}
+	stats.Search = CrawlSearchStats{
+		IndexEnabled:    sess.searchIndex != nil,
+		Searches:        sess.searchStats.searches,
+		IndexedSearches: sess.searchStats.indexedSearches,
+		ScanSearches:    sess.searchStats.scanSearches,
+		TotalLatency:    sess.searchStats.totalLatency,
+		MaxLatency:      sess.searchStats.maxLatency,
+	}
+	if sess.searchIndex != nil {
+		stats.Search.IndexedTokens = sess.searchIndex.tokenCount()
+	}
+	return stats, nil
 }
 
 func (b *CollyBackend) ListFlows(ctx context.Context, sessionID string, opts CrawlListOptions) ([]CrawlFlow, error) {
@@ -658,8 +1803,46 @@ func (b *CollyBackend) ListFlows(ctx context.Context, sessionID string, opts Cra
 		return nil, err
 	}
 
-	sess.mu.Lock()
-	defer sess.mu.Unlock()
+	deadline := time.Now().Add(opts.Wait)
+	for {
+		sess.mu.Lock()
+		flows, advanced := sess.listFlows(opts)
+		if len(flows) > 0 || opts.Wait <= 0 || sess.info.State != crawlStateRunning ||
+			time.Now().After(deadline) || ctx.Err() != nil {
+			sess.mu.Unlock()
+			if advanced {
+				sess.persistCursors()
+			}
+			return flows, nil
+		}
+		notify := sess.flowNotify // capture before unlocking
+		sess.mu.Unlock()
+
+		select {
+		case <-notify: // channel closed = new flows or session ended
+		case <-ctx.Done():
+		case <-time.After(time.Until(deadline)):
+		}
+	}
+}
+
+// notifyFlows wakes ListFlows callers waiting for new flows. Caller must hold sess.mu.
+func (sess *crawlSession) notifyFlows() {
+	close(sess.flowNotify)
+	sess.flowNotify = make(chan struct{})
+}
+
+// listFlows applies opts to the session's flows and advances the cursor past those returned,
+// reporting whether it moved. Caller must hold sess.mu.
+func (sess *crawlSession) listFlows(opts CrawlListOptions) ([]CrawlFlow, bool) {
+	// Resolve the cursor position: a named cursor if given, otherwise the shared session cursor
+	cursorIdx := sess.lastReturnedIdx
+	if opts.Cursor != "" {
+		cursorIdx = sess.namedCursors[opts.Cursor]
+		if opts.Since == "" {
+			opts.Since = sinceLast
+		}
+	}
 
 	// Determine start index and/or timestamp filter based on "since" value
 	var startIdx int
@@ -669,7 +1852,7 @@ func (b *CollyBackend) ListFlows(ctx context.Context, sessionID string, opts Cra
 	if opts.Since != "" {
 		if opts.Since == sinceLast {
 			// Use the last returned index (exclusive - start after it)
-			startIdx = sess.lastReturnedIdx
+			startIdx = cursorIdx
 		} else if t, ok := parseSinceTimestamp(opts.Since); ok {
 			// Timestamp filter - will filter by DiscoveredAt
 			sinceTime = t
@@ -697,26 +1880,51 @@ func (b *CollyBackend) ListFlows(ctx context.Context, sessionID string, opts Cra
 		idx  int // original index in flowsOrdered
 	}
 	var filtered []indexedFlow
-	for i := startIdx; i < len(sess.flowsOrdered); i++ {
+	// collect applies filters to the flow at position i and reports whether enough were collected
+	collect := func(i int) bool {
 		flow := sess.flowsOrdered[i]
 		// Apply timestamp filter if specified (exclusive - only flows after sinceTime)
 		if useSinceTime && !flow.DiscoveredAt.After(sinceTime) {
-			continue
-		} else if !matchesFlowFilters(flow, opts) {
-			continue
-		} else if hasSearch && !matchesFlowSearch(flow.Request, flow.Response, opts.SearchHeaderRe, opts.SearchBodyRe) {
-			continue
+			return false
+		}
+		matched := matchesFlowFilters(flow, opts) &&
+			(!hasSearch || matchesFlowSearch(flow.Request, flow.Response, opts.SearchHeaderRe, opts.SearchBodyRe))
+		if matched == opts.Invert {
+			return false
 		}
 		filtered = append(filtered, indexedFlow{flow: flow, idx: i})
-		if maxCollect > 0 && len(filtered) >= maxCollect {
-			break
+		return maxCollect > 0 && len(filtered) >= maxCollect
+	}
+
+	// Literal searches only visit flows the index reports as candidates; regexes scan everything.
+	// Inverted listings want the non-candidates, so they always scan.
+	searchStart := time.Now()
+	var candidates []int
+	var indexed bool
+	if hasSearch && !opts.Invert && sess.searchIndex != nil {
+		candidates, indexed = sess.searchIndex.flowCandidates(opts.SearchHeaderRe, opts.SearchBodyRe)
+	}
+	if indexed {
+		for _, i := range candidates {
+			if i >= startIdx && collect(i) {
+				break
+			}
 		}
+	} else {
+		for i := startIdx; i < len(sess.flowsOrdered); i++ {
+			if collect(i) {
+				break
+			}
+		}
+	}
+	if hasSearch {
+		sess.searchStats.record(indexed, time.Since(searchStart))
 	}
 
 	// Apply offset (after filtering)
 	if opts.Offset > 0 {
 		if opts.Offset >= len(filtered) {
-			return []CrawlFlow{}, nil
+			return []CrawlFlow{}, false
 		}
 		filtered = filtered[opts.Offset:]
 	}
@@ -726,12 +1934,21 @@ func (b *CollyBackend) ListFlows(ctx context.Context, sessionID string, opts Cra
 		filtered = filtered[:opts.Limit]
 	}
 
-	// Update lastReturnedIdx based on flows actually returned
+	// Update the cursor based on flows actually returned
+	var advanced bool
 	if len(filtered) > 0 {
 		// Use the highest original index from flows being returned (+1 for next iteration)
 		maxIdx := filtered[len(filtered)-1].idx + 1
-		if maxIdx > sess.lastReturnedIdx {
-			sess.lastReturnedIdx = maxIdx
+		if maxIdx > cursorIdx {
+			advanced = true
+			if opts.Cursor != "" {
+				if sess.namedCursors == nil {
+					sess.namedCursors = make(map[string]int)
+				}
+				sess.namedCursors[opts.Cursor] = maxIdx
+			} else {
+				sess.lastReturnedIdx = maxIdx
+			}
 		}
 	}
 
@@ -739,7 +1956,7 @@ func (b *CollyBackend) ListFlows(ctx context.Context, sessionID string, opts Cra
 	for i, f := range filtered {
 		result[i] = *f.flow
 	}
-	return result, nil
+	return result, advanced
 }
 
 func (b *CollyBackend) ListForms(ctx context.Context, sessionID string, limit int) ([]DiscoveredForm, error) {
@@ -755,7 +1972,15 @@ func (b *CollyBackend) ListForms(ctx context.Context, sessionID string, limit in
 	if limit > 0 && limit < len(forms) {
 		forms = forms[:limit]
 	}
-	return slices.Clone(forms), nil
+	forms = slices.Clone(forms)
+	for i := range forms {
+		if st := sess.methodChecks[forms[i].ID]; st != nil {
+			check := st.result
+			forms[i].MethodCheck = &check
+		}
+	}
+	linkFormSubmissions(forms, sess.flowsOrdered)
+	return forms, nil
 }
 
 func (b *CollyBackend) ListErrors(ctx context.Context, sessionID string, limit int) ([]CrawlError, error) {
@@ -774,6 +1999,107 @@ func (b *CollyBackend) ListErrors(ctx context.Context, sessionID string, limit i
 	return slices.Clone(errs), nil
 }
 
+func (b *CollyBackend) ListExternalLinks(ctx context.Context, sessionID string, limit int) ([]ExternalLink, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sess.mu.RLock()
+	defer sess.mu.RUnlock()
+
+	links := sess.externalLinks
+	if limit > 0 && limit < len(links) {
+		links = links[:limit]
+	}
+	return slices.Clone(links), nil
+}
+
+func (b *CollyBackend) ListJSEndpoints(ctx context.Context, sessionID string, limit int) ([]JSEndpoint, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sess.mu.RLock()
+	defer sess.mu.RUnlock()
+
+	endpoints := sess.jsEndpoints
+	if limit > 0 && limit < len(endpoints) {
+		endpoints = endpoints[:limit]
+	}
+	return slices.Clone(endpoints), nil
+}
+
+func (b *CollyBackend) ListFindings(ctx context.Context, sessionID string, limit int) ([]CrawlFinding, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sess.mu.RLock()
+	defer sess.mu.RUnlock()
+
+	var findings []CrawlFinding
+	for _, flow := range sess.flowsOrdered {
+		for _, f := range flow.Findings {
+			if f != findingSensitiveData {
+				findings = append(findings, CrawlFinding{FlowID: flow.ID, URL: flow.URL, Finding: f})
+				continue
+			}
+			for _, m := range flow.SensitiveData {
+				findings = append(findings, CrawlFinding{
+					FlowID:  flow.ID,
+					URL:     flow.URL,
+					Finding: f,
+					Pattern: m.Pattern,
+					Offset:  m.Offset,
+					Snippet: m.Snippet,
+				})
+			}
+		}
+		if limit > 0 && len(findings) >= limit {
+			return findings[:limit], nil
+		}
+	}
+	return findings, nil
+}
+
+func (b *CollyBackend) ListClientRoutes(ctx context.Context, sessionID string, limit int) ([]ClientRoute, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sess.mu.RLock()
+	defer sess.mu.RUnlock()
+
+	routes := sess.clientRoutes
+	if limit > 0 && limit < len(routes) {
+		routes = routes[:limit]
+	}
+	return slices.Clone(routes), nil
+}
+
+func (b *CollyBackend) ListSimilarClusters(ctx context.Context, sessionID string, limit int) ([]SimilarCluster, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sess.mu.RLock()
+	defer sess.mu.RUnlock()
+
+	if sess.opts.SimilarityThreshold <= 0 {
+		return nil, nil
+	}
+	clusters := clusterSimilarFlows(sess.flowsOrdered, sess.opts.SimilarityThreshold)
+	if limit > 0 && limit < len(clusters) {
+		clusters = clusters[:limit]
+	}
+	return clusters, nil
+}
+
 func (b *CollyBackend) GetFlow(ctx context.Context, flowID string) (*CrawlFlow, error) {
 	b.mu.RLock()
 	sessions := bulk.MapValuesSlice(b.sessions)
@@ -804,13 +2130,87 @@ func (b *CollyBackend) StopSession(ctx context.Context, sessionID string) error
 		return nil // Already stopped
 	}
 	sess.info.State = crawlStateStopped
+	sess.notifyFlows()
+	cancel := sess.cancel
 	sess.mu.Unlock()
 
-	sess.cancel()
+	cancel()
+	sess.flowLog.persistSession(sess)
 	log.Printf("crawler: stopped session %s", sessionID)
 	return nil
 }
 
+func (b *CollyBackend) ResumeSession(ctx context.Context, sessionID string) (int, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	sess.mu.RLock()
+	state, runDone := sess.info.State, sess.runDone
+	sess.mu.RUnlock()
+	if state == crawlStateRunning {
+		return 0, fmt.Errorf("session %s is already running", sessionID)
+	} else if sess.restored {
+		return 0, fmt.Errorf("session %s was restored from a crawl flow log without resume state and cannot be resumed; create a new session instead", sessionID)
+	}
+
+	// The previous run marks the session completed once its collector drains; wait for it
+	select {
+	case <-runDone:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	// Swap in a fresh context while holding b.mu so Close cannot miss the new cancel func
+	sessionCtx, cancel := context.WithCancel(context.Background())
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		cancel()
+		return 0, errors.New("backend is closed")
+	}
+	sess.mu.Lock()
+	if sess.info.State == crawlStateRunning {
+		sess.mu.Unlock()
+		b.mu.RUnlock()
+		cancel()
+		return 0, fmt.Errorf("session %s is already running", sessionID)
+	}
+	sess.ctx, sess.cancel = sessionCtx, cancel
+	sess.runDone = make(chan struct{})
+	sess.mu.Unlock()
+	b.mu.RUnlock()
+
+	c, varyCollector := b.newSessionCollectors(sess, sess.refreshTransport) // the session's base transport
+
+	sess.mu.Lock()
+	var pending []string
+	for link := range sess.urlsSeen {
+		if !sess.urlsRequested[link] {
+			pending = append(pending, link)
+			if origin := sess.linkOrigins[link]; origin.FoundOn != "" {
+				sess.parentURLs.Store(link, origin.FoundOn)
+			}
+		}
+	}
+	slices.Sort(pending)
+	sess.collector = c
+	sess.urlsQueued = 0
+	sess.blockStreaks = make(map[string]blockStreak)
+	sess.blockedNote = ""
+	sess.lastActivity = time.Now()
+	sess.info.State = crawlStateRunning
+	sess.notifyFlows()
+	sess.mu.Unlock()
+	sess.flowLog.persistSession(sess)
+
+	log.Printf("crawler: resumed session %s with %d pending URLs", sess.info.ID, len(pending))
+
+	go b.runSession(sess, c, varyCollector, pending)
+	return len(pending), nil
+}
+
 func (b *CollyBackend) ListSessions(ctx context.Context, limit int) ([]CrawlSessionInfo, error) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -843,8 +2243,22 @@ func (b *CollyBackend) Close() error {
 	sessions := bulk.MapValuesSlice(b.sessions)
 	b.mu.Unlock()
 
+	close(b.sweepStop)
+	<-b.sweepDone
 	for _, sess := range sessions {
+		sess.mu.Lock()
+		if sess.info.State == crawlStateRunning {
+			sess.info.State = crawlStateStopped
+			sess.notifyFlows()
+		}
+		runDone := sess.runDone
+		sess.mu.Unlock()
 		sess.cancel()
+		<-runDone // the run persists the final frontier, for resuming after a restart
+	}
+	b.closeWebhooks()
+	if b.flowLog != nil {
+		return b.flowLog.Close()
 	}
 	return nil
 }
@@ -865,6 +2279,31 @@ func (b *CollyBackend) resolveSession(identifier string) (*crawlSession, error)
 	return nil, fmt.Errorf("%w: session %s", ErrNotFound, identifier)
 }
 
+// crawlBaseTransport returns the transport crawl requests are sent through. It pins hostnames
+// to fixed IPs when requested (Host header and SNI keep the original name), and resolves the
+// remaining names through a DoH resolver when one is given. Response headers past
+// maxHeaderBytes fail the request rather than being read into memory before capture.
+func crawlBaseTransport(opts CrawlOptions, maxHeaderBytes int) http.RoundTripper {
+	if len(opts.HostResolution) == 0 && opts.DoHResolver == "" && maxHeaderBytes <= 0 {
+		return http.DefaultTransport
+	}
+	custom := http.DefaultTransport.(*http.Transport).Clone()
+	if maxHeaderBytes > 0 {
+		custom.MaxResponseHeaderBytes = int64(maxHeaderBytes)
+	}
+	if len(opts.HostResolution) == 0 && opts.DoHResolver == "" {
+		return custom
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if opts.DoHResolver != "" {
+		dialer.Resolver = newDoHResolver(opts.DoHResolver)
+	}
+	custom.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, proxy.OverrideDialAddr(addr, opts.HostResolution))
+	}
+	return custom
+}
+
 // resolveSeeds processes seed options and returns allowed domains, seed URLs, and headers.
 func (b *CollyBackend) resolveSeeds(ctx context.Context, seeds []CrawlSeed, explicitDomains []string) ([]string, []string, map[string]string, error) {
 	domainSet := make(map[string]bool)
@@ -955,7 +2394,9 @@ func (b *CollyBackend) runReconForSession(ctx context.Context, sess *crawlSessio
 	toRecon := make([]string, 0, len(domains))
 	sess.mu.Lock()
 	for _, d := range domains {
-		if !sess.reconnedDomains[d] {
+		if net.ParseIP(d) != nil {
+			continue // passive sources index hostnames, not addresses
+		} else if !sess.reconnedDomains[d] {
 			toRecon = append(toRecon, d)
 			sess.reconnedDomains[d] = true
 		}
@@ -996,16 +2437,7 @@ func (b *CollyBackend) runReconForSession(ctx context.Context, sess *crawlSessio
 				continue // out of scope
 			}
 
-			// Add to crawler dynamically (same pattern as AddSeeds)
-			sess.mu.Lock()
-			seen := sess.urlsSeen[url]
-			if !seen {
-				sess.urlsSeen[url] = true
-			}
-			sess.mu.Unlock()
-
-			if !seen {
-				_ = sess.collector.Visit(url)
+			if sess.queueDiscovered(url) {
 				urlsAdded++
 				domainHadResults = true
 			}
@@ -1053,39 +2485,230 @@ func matchesFlowFilters(flow *CrawlFlow, opts CrawlListOptions) bool {
 		return false
 	}
 
+	if opts.ContentType != "" && !matchesGlob(contentMediaType(flow.ContentType), strings.ToLower(opts.ContentType)) {
+		return false
+	}
+
+	if opts.Fingerprint != "" && endpointFingerprint(flow) != opts.Fingerprint {
+		return false
+	}
+
 	return true
 }
 
 func isTextContentType(ct string) bool {
+	return matchesContentType(ct, defaultCrawlContentTypes) || isJSONMediaType(contentMediaType(ct))
+}
+
+// defaultCrawlContentTypes are the Content-Type prefixes of responses recorded as flows
+// unless a session sets AllowedContentTypes.
+var defaultCrawlContentTypes = []string{
+	"text/",
+	"application/json",
+	"application/xml",
+	"application/javascript",
+	"application/x-javascript",
+}
+
+// crawlContentTypes returns the Content-Type prefixes a session records: AllowedContentTypes
+// in place of the defaults when set, plus ExtraContentTypes, lowercased for matching.
+func crawlContentTypes(opts CrawlOptions) []string {
+	base := defaultCrawlContentTypes
+	if len(opts.AllowedContentTypes) > 0 {
+		base = opts.AllowedContentTypes
+	}
+	types := make([]string, 0, len(base)+len(opts.ExtraContentTypes))
+	for _, t := range slices.Concat(base, opts.ExtraContentTypes) {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// matchesContentType reports whether ct starts with one of the lowercase prefixes.
+func matchesContentType(ct string, prefixes []string) bool {
 	if ct == "" {
 		return true // Allow empty content type (will be filtered later if needed)
 	}
 	ct = strings.ToLower(ct)
-	return slices.ContainsFunc([]string{
-		"text/",
-		"application/json",
-		"application/xml",
-		"application/javascript",
-		"application/x-javascript",
-	}, func(allowed string) bool {
+	return slices.ContainsFunc(prefixes, func(allowed string) bool {
 		return strings.HasPrefix(ct, allowed)
 	})
 }
 
-// globsToRegexes converts glob patterns to compiled regexes.
+// extractJSONURLs walks a JSON document and returns string values that look like URLs.
+// Absolute http(s) URLs and root-relative paths are returned; other strings are ignored.
+func extractJSONURLs(body []byte) []string {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil
+	}
+
+	var urls []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			for _, child := range val {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range val {
+				walk(child)
+			}
+		case string:
+			lower := strings.ToLower(val)
+			if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") ||
+				(strings.HasPrefix(val, "/") && !strings.HasPrefix(val, "//") && !strings.ContainsAny(val, " \t\n")) {
+				urls = append(urls, val)
+			}
+		}
+	}
+	walk(doc)
+	return urls
+}
+
+var (
+	cssCommentRe = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cssURLRe     = regexp.MustCompile(`(?i)url\(\s*(?:"([^"]*)"|'([^']*)'|([^)"'\s]+))\s*\)`)
+	cssImportRe  = regexp.MustCompile(`(?i)@import\s+(?:"([^"]*)"|'([^']*)')`)
+)
+
+// extractCSSURLs returns url() and quoted @import references from a stylesheet.
+// Inline data, fragment-only (SVG filter) and script references are ignored.
+func extractCSSURLs(body []byte) []string {
+	css := cssCommentRe.ReplaceAll(body, nil)
+
+	var urls []string
+	for _, re := range []*regexp.Regexp{cssURLRe, cssImportRe} {
+		for _, m := range re.FindAllSubmatch(css, -1) {
+			var ref string
+			for _, group := range m[1:] {
+				if len(group) > 0 {
+					ref = strings.TrimSpace(string(group))
+					break
+				}
+			}
+			lower := strings.ToLower(ref)
+			if ref == "" || strings.HasPrefix(ref, "#") || strings.HasPrefix(lower, "data:") ||
+				strings.HasPrefix(lower, "javascript:") || strings.HasPrefix(lower, "about:") {
+				continue
+			}
+			urls = append(urls, ref)
+		}
+	}
+	return urls
+}
+
+// findingDirectoryListing marks a flow whose response is a server-generated directory index.
+const findingDirectoryListing = "directory-listing"
+
+var (
+	// Apache/nginx autoindex, Python http.server, IIS and lighttpd listing signatures
+	dirListingRe = regexp.MustCompile(`(?i)<title>\s*(?:Index of /|Directory listing for /)|<h1>\s*Index of /|\[To Parent Directory\]|<a href="\?C=[NMSD];O=[AD]">`)
+	// Apache autoindex column sort links; re-requesting them only reorders the same listing
+	autoindexSortRe = regexp.MustCompile(`^\?C=[NMSD];O=[AD]$`)
+)
+
+// isDirectoryListing reports whether an HTML response body looks like a directory listing.
+func isDirectoryListing(body []byte) bool {
+	return dirListingRe.Match(body)
+}
+
+// trailingSlashKey returns a URL with any fragment and single trailing path slash removed,
+// so "/path" and "/path/" share a key. The root path is kept as "/".
+func trailingSlashKey(link string) (string, bool) {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path = strings.TrimSuffix(u.Path, "/"); u.Path == "" {
+		u.Path = "/"
+	}
+	u.RawPath = ""
+	return u.String(), true
+}
+
+// contentMediaType returns the lowercase media type from a Content-Type value, without parameters.
+func contentMediaType(ct string) string {
+	mt, _, _ := strings.Cut(ct, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
+
+// applyDomainHeaders calls set for each header of the DomainHeaders globs matching host.
+// Globs are applied in sorted order so overlapping globs resolve the same way everywhere.
+func applyDomainHeaders(domainHeaders map[string]map[string]string, host string, set func(name, value string)) {
+	host = strings.ToLower(host)
+	globs := bulk.MapKeysSlice(domainHeaders)
+	slices.Sort(globs)
+	for _, pattern := range globs {
+		if matchesGlob(host, strings.ToLower(pattern)) {
+			for k, v := range domainHeaders[pattern] {
+				set(k, v)
+			}
+		}
+	}
+}
+
+// isJSONMediaType reports whether a media type from contentMediaType is application/json
+// or a structured +json type such as application/hal+json or application/problem+json.
+func isJSONMediaType(mt string) bool {
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// compileGlob compiles a path filter glob, unanchored. Empty patterns are rejected since
+// they would match every URL.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, errors.New("pattern is empty")
+	}
+	return regexp.Compile(globToRegex(pattern))
+}
+
+// globsToRegexes converts glob patterns to compiled regexes, logging and skipping any
+// that are invalid. Used for config defaults; user patterns go through compileGlobs.
 func globsToRegexes(patterns []string) []*regexp.Regexp {
 	result := make([]*regexp.Regexp, 0, len(patterns))
 	for _, p := range patterns {
-		escaped := regexp.QuoteMeta(p)
-		escaped = strings.ReplaceAll(escaped, `\*`, ".*")
-		escaped = strings.ReplaceAll(escaped, `\?`, ".")
-		if re, err := regexp.Compile(escaped); err == nil {
+		if re, err := compileGlob(p); err != nil {
+			log.Printf("crawler: warning: ignoring path pattern %q: %v", p, err)
+		} else {
 			result = append(result, re)
 		}
 	}
 	return result
 }
 
+// compileGlobs converts glob patterns to compiled regexes, failing on the first invalid one.
+func compileGlobs(patterns []string) ([]*regexp.Regexp, error) {
+	result := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := compileGlob(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path pattern %q: %w", p, err)
+		}
+		result = append(result, re)
+	}
+	return result, nil
+}
+
+// compilePathRegexes compiles path filter regular expressions, failing on the first
+// invalid one.
+func compilePathRegexes(patterns []string) ([]*regexp.Regexp, error) {
+	result := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path regex %q: %w", p, err)
+		}
+		result = append(result, re)
+	}
+	return result, nil
+}
+
 // buildDomainFilters creates URL filters that match a domain and any subdomains.
 // For example, "example.com" matches example.com, sub.example.com, a.b.example.com.
 func buildDomainFilters(domains []string) []*regexp.Regexp {
@@ -1120,6 +2743,18 @@ func isDomainAllowed(urlStr string, allowedDomains []string, includeSubdomains b
 	return false
 }
 
+// externalLinkHost returns the hostname of an http(s) link outside the allowed domains.
+// ok is false for in-scope links and non-web schemes (mailto:, javascript:, etc).
+func externalLinkHost(link string, allowedDomains []string, includeSubdomains bool) (host string, ok bool) {
+	parsed, err := url.Parse(link)
+	if err != nil || (parsed.Scheme != schemeHTTP && parsed.Scheme != schemeHTTPS) || parsed.Hostname() == "" {
+		return "", false
+	} else if isDomainAllowed(link, allowedDomains, includeSubdomains) {
+		return "", false
+	}
+	return parsed.Hostname(), true
+}
+
 func extractForm(e *colly.HTMLElement, sessionID string) DiscoveredForm {
 	action := e.Request.AbsoluteURL(e.Attr("action"))
 	if action == "" {
@@ -1169,9 +2804,61 @@ func extractForm(e *colly.HTMLElement, sessionID string) DiscoveredForm {
 		form.Inputs = append(form.Inputs, input)
 	})
 
+	form.HasJSHandlers = formHasJSHandlers(e)
+	form.AutoSubmit = isAutoSubmitForm(e, form.Inputs)
+
 	return form
 }
 
+// formHasJSHandlers reports whether submission is driven or intercepted by script.
+func formHasJSHandlers(e *colly.HTMLElement) bool {
+	if e.Attr("onsubmit") != "" ||
+		strings.HasPrefix(strings.ToLower(strings.TrimSpace(e.Attr("action"))), "javascript:") {
+		return true
+	}
+	var found bool
+	e.ForEach(`button, input[type="submit"], input[type="image"]`, func(_ int, el *colly.HTMLElement) {
+		if el.Attr("onclick") != "" {
+			found = true
+		}
+	})
+	return found
+}
+
+// isAutoSubmitForm detects hidden-only forms submitted by script when the page loads,
+// as used by SAML/OIDC post bindings and CSRF PoCs.
+func isAutoSubmitForm(e *colly.HTMLElement, inputs []FormInput) bool {
+	if len(inputs) == 0 {
+		return false
+	}
+	for _, input := range inputs {
+		switch strings.ToLower(input.Type) {
+		case "hidden", "submit", "button", "image":
+		default:
+			return false
+		}
+	}
+
+	doc := e.DOM.Parents().Last()
+	return strings.Contains(doc.Find("body").AttrOr("onload", ""), "submit") ||
+		strings.Contains(doc.Find("script").Text(), ".submit()")
+}
+
+// formMethodVerdict compares the GET and POST halves of a form method check. The
+// undeclared method counts as accepted when both return the same non-error response.
+func formMethodVerdict(getStatus, postStatus int, sameBody bool) (verdict string, wrongMethodAccepted bool) {
+	switch {
+	case getStatus == 0 || postStatus == 0:
+		return formMethodIncomplete, false
+	case getStatus != postStatus:
+		return formMethodDifferentStatus, false
+	case !sameBody:
+		return formMethodDifferentBody, false
+	default:
+		return formMethodSame, getStatus < 400
+	}
+}
+
 func extractFormData(e *colly.HTMLElement) map[string]string {
 	data := make(map[string]string)
 	e.ForEach("input, select, textarea", func(_ int, el *colly.HTMLElement) {
@@ -4,7 +4,13 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,6 +33,8 @@ func TestIsTextContentType(t *testing.T) {
 		{"text_plain", "text/plain", true},
 		{"text_html_charset", "text/html; charset=utf-8", true},
 		{"application_json", "application/json", true},
+		{"application_hal_json", "application/hal+json", true},
+		{"application_problem_json", "application/problem+json; charset=utf-8", true},
 		{"application_xml", "application/xml", true},
 		{"application_javascript", "application/javascript", true},
 		{"application_x_javascript", "application/x-javascript", true},
@@ -47,6 +55,65 @@ func TestIsTextContentType(t *testing.T) {
 	}
 }
 
+func TestCollyBackend_ContentTypes(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<a href="/app.js.map">map</a><a href="/report.pdf">pdf</a>`))
+		case "/app.js.map":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version":3,"sources":["app.ts"]}`))
+		case "/report.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte("%PDF-1.4\n"))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	tests := []struct {
+		name    string
+		allowed []string
+		extra   []string
+		want    []string
+	}{
+		{name: "default", want: []string{"/", "/app.js.map"}},
+		{name: "allowed_replaces", allowed: []string{"text/html", "Application/PDF"}, want: []string{"/", "/report.pdf"}},
+		{name: "extra_appends", extra: []string{"application/pdf"}, want: []string{"/", "/app.js.map", "/report.pdf"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess, err := b.CreateSession(t.Context(), CrawlOptions{
+				Seeds:               []CrawlSeed{{URL: server.URL + "/"}},
+				AllowedContentTypes: tt.allowed,
+				ExtraContentTypes:   tt.extra,
+				Delay:               time.Millisecond,
+				IgnoreRobotsTxt:     true,
+			})
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				status, err := b.GetStatus(t.Context(), sess.ID)
+				return err == nil && status.State == crawlStateCompleted
+			}, 10*time.Second, 10*time.Millisecond)
+
+			flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+			require.NoError(t, err)
+			paths := make([]string, 0, len(flows))
+			for _, f := range flows {
+				paths = append(paths, f.Path)
+			}
+			assert.ElementsMatch(t, tt.want, paths)
+		})
+	}
+}
+
 func TestMatchesFlowFilters(t *testing.T) {
 	t.Parallel()
 
@@ -133,6 +200,136 @@ func TestGlobsToRegexes(t *testing.T) {
 		regexes = globsToRegexes([]string{})
 		assert.Empty(t, regexes)
 	})
+
+	t.Run("invalid_skipped", func(t *testing.T) {
+		regexes := globsToRegexes([]string{"*logout*", "", "*\xff*"})
+		require.Len(t, regexes, 1)
+		assert.True(t, regexes[0].MatchString("/logout"))
+	})
+}
+
+func TestCompileGlobs(t *testing.T) {
+	t.Parallel()
+
+	regexes, err := compileGlobs([]string{"*logout*", "/api/v?/*"})
+	require.NoError(t, err)
+	assert.Len(t, regexes, 2)
+
+	_, err = compileGlobs([]string{"*logout*", ""})
+	require.ErrorContains(t, err, `invalid path pattern "": pattern is empty`)
+
+	_, err = compileGlobs([]string{"*log\xffout*"})
+	require.ErrorContains(t, err, "invalid path pattern")
+	require.ErrorContains(t, err, "invalid UTF-8")
+}
+
+func TestCollyBackend_InvalidPathPatterns(t *testing.T) {
+	t.Parallel()
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	tests := []struct {
+		name    string
+		opts    CrawlOptions
+		wantErr string
+	}{
+		{
+			name:    "disallowed_glob",
+			opts:    CrawlOptions{DisallowedPaths: []string{"*logout*", "*sign\xffout*"}},
+			wantErr: `invalid path pattern "*sign\xffout*"`,
+		},
+		{
+			name:    "disallowed_empty_glob",
+			opts:    CrawlOptions{DisallowedPaths: []string{"*logout*", ""}},
+			wantErr: `invalid path pattern "": pattern is empty`,
+		},
+		{
+			name:    "allowed_glob",
+			opts:    CrawlOptions{AllowedPaths: []string{"/app/\xff"}},
+			wantErr: "invalid path pattern",
+		},
+		{
+			name:    "disallowed_regex",
+			opts:    CrawlOptions{DisallowedPathsRegex: []string{`/logout(`}},
+			wantErr: "invalid path regex",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Seeds = []CrawlSeed{{URL: "https://example.com/"}}
+			_, err := b.CreateSession(t.Context(), tt.opts)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCompilePathRegexes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		regexes, err := compilePathRegexes([]string{`^/users/\d+$`})
+		require.NoError(t, err)
+		require.Len(t, regexes, 1)
+		assert.True(t, regexes[0].MatchString("/users/42"))
+		assert.False(t, regexes[0].MatchString("/users/me"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := compilePathRegexes([]string{`^/ok$`, `/users/(\d+`})
+		require.ErrorContains(t, err, `invalid path regex "/users/(\\d+"`)
+	})
+}
+
+func TestCollyBackend_PathRegexFilters(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`<a href="/users/1">1</a><a href="/users/22">22</a><a href="/users/me">me</a>` +
+				`<a href="/users/1/edit">edit</a><a href="/users/13/posts">posts</a><a href="/users/22/posts">posts</a>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	t.Run("filters_paths", func(t *testing.T) {
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds: []CrawlSeed{{URL: server.URL + "/"}},
+			// Numeric IDs only, and no ID with an odd last digit followed by a subpath
+			AllowedPathsRegex:    []string{`^/$`, `^/users/\d+(/|$)`},
+			DisallowedPathsRegex: []string{`^/users/\d*[13579]/`},
+			Delay:                time.Millisecond,
+			IgnoreRobotsTxt:      true,
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, map[string]bool{"/": true, "/users/1": true, "/users/22": true, "/users/22/posts": true}, requested)
+	})
+
+	t.Run("invalid_regex", func(t *testing.T) {
+		_, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:                []CrawlSeed{{URL: server.URL + "/"}},
+			DisallowedPathsRegex: []string{`[`},
+		})
+		require.ErrorContains(t, err, "invalid path regex")
+	})
 }
 
 func TestBuildDomainFilters(t *testing.T) {
@@ -296,6 +493,251 @@ func TestReadBodyLimited(t *testing.T) {
 	}
 }
 
+func TestTrimTruncatedBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		encoding    string
+		want        string
+	}{
+		{"ascii_text", "plain text", "text/plain", "", "plain text"},
+		{"split_two_byte_rune", "caf\xc3", "text/plain; charset=utf-8", "", "caf"},
+		{"split_four_byte_rune", "ok \xf0\x9f\x98", "text/plain", "", "ok "},
+		{"complete_rune_kept", "café", "text/plain", "", "café"},
+		{"invalid_bytes_kept", "ab\xff", "text/plain", "", "ab\xff"},
+		{"binary_untouched", "caf\xc3", "image/png", "", "caf\xc3"},
+		{"encoded_untouched", `{"a":1,"b":"x`, "application/json", "gzip", `{"a":1,"b":"x`},
+		{"json_ends_before_separator", `{"a":1,"b":"partial val`, "application/json", "", `{"a":1`},
+		{"json_ends_after_close", `[{"a":1}]  {"b`, "application/json", "", `[{"a":1}]`},
+		{"json_rune_then_boundary", `{"a":"é","b":"\xc3`, "application/json", "", `{"a":"é"`},
+		{"html_ends_after_tag", "<p>one</p><p>tw", "text/html", "", "<p>one</p><p>"},
+		{"no_boundary_in_window", "<p>" + strings.Repeat("x", truncationBoundaryWindow+10), "text/html",
+			"", "<p>" + strings.Repeat("x", truncationBoundaryWindow+10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, string(trimTruncatedBody([]byte(tt.body), tt.contentType, tt.encoding)))
+		})
+	}
+}
+
+func TestTruncateHeaders(t *testing.T) {
+	t.Parallel()
+
+	const headers = "HTTP/1.1 200 OK\r\nA: 1\r\nB: 22\r\n\r\n" // 32 bytes
+
+	tests := []struct {
+		name          string
+		limit         int
+		want          string
+		wantTruncated bool
+	}{
+		{"unlimited", 0, headers, false},
+		{"within_limit", 100, headers, false},
+		{"exactly_at_limit", len(headers), headers, false},
+		{"drops_last_line", len(headers) - 1, "HTTP/1.1 200 OK\r\nA: 1\r\n\r\n", true},
+		{"cut_mid_line", 27, "HTTP/1.1 200 OK\r\nA: 1\r\n\r\n", true},
+		{"keeps_start_line", 5, "HTTP/1.1 200 OK\r\n\r\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := truncateHeaders([]byte(headers), tt.limit)
+			assert.Equal(t, tt.want, string(got))
+			assert.Equal(t, tt.wantTruncated, truncated)
+		})
+	}
+
+	t.Run("does_not_alias_input", func(t *testing.T) {
+		in := []byte(headers)
+		got, _ := truncateHeaders(in, 30)
+		got[len(got)-1] = 'x'
+		assert.Equal(t, headers, string(in))
+	})
+}
+
+func TestCollyBackend_MaxHeaderBytes(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/large" {
+			for i := 0; i < 100; i++ {
+				w.Header().Add(fmt.Sprintf("X-Filler-%d", i), strings.Repeat("a", 100))
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("body"))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := config.DefaultConfig()
+	cfg.MaxHeaderBytes = 1024
+	b := NewCollyBackend(cfg, nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	crawl := func(t *testing.T, path string, headers map[string]string) string {
+		t.Helper()
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + path}},
+			Headers:         headers,
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+		})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+		return sess.ID
+	}
+
+	t.Run("request_headers_truncated", func(t *testing.T) {
+		sessionID := crawl(t, "/", map[string]string{"X-Filler": strings.Repeat("a", 2000)})
+
+		flows, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{})
+		require.NoError(t, err)
+		require.Len(t, flows, 1)
+		flow := flows[0]
+		assert.True(t, flow.HeadersTruncated)
+		assert.False(t, flow.Truncated)
+
+		headers, _ := splitHeadersBody(flow.Request)
+		assert.LessOrEqual(t, len(headers), cfg.MaxHeaderBytes)
+		assert.True(t, bytes.HasSuffix(headers, []byte("\r\n\r\n")))
+		_, body := splitHeadersBody(flow.Response)
+		assert.Equal(t, "body", string(body))
+	})
+
+	t.Run("response_headers_refused", func(t *testing.T) {
+		sessionID := crawl(t, "/large", nil)
+
+		flows, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, flows)
+		errs, err := b.ListErrors(t.Context(), sessionID, 0)
+		require.NoError(t, err)
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error, "exceeded 1024 bytes")
+	})
+}
+
+func TestCollyBackend_MaxResponseBodyBytes(t *testing.T) {
+	t.Parallel()
+
+	const bodySize = 4096
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write(bytes.Repeat([]byte("a"), bodySize))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := config.DefaultConfig()
+	cfg.MaxBodyBytes = 1024
+	b := NewCollyBackend(cfg, nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	tests := []struct {
+		name          string
+		override      int
+		wantCaptured  int
+		wantTruncated bool
+	}{
+		{name: "config_default", override: 0, wantCaptured: 1024, wantTruncated: true},
+		{name: "larger_override", override: 8192, wantCaptured: bodySize},
+		{name: "smaller_override", override: 100, wantCaptured: 100, wantTruncated: true},
+		{name: "unlimited", override: -1, wantCaptured: bodySize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess, err := b.CreateSession(t.Context(), CrawlOptions{
+				Seeds:                []CrawlSeed{{URL: server.URL + "/"}},
+				Delay:                time.Millisecond,
+				IgnoreRobotsTxt:      true,
+				MaxResponseBodyBytes: tt.override,
+			})
+			require.NoError(t, err)
+			require.Eventually(t, func() bool {
+				status, err := b.GetStatus(t.Context(), sess.ID)
+				return err == nil && status.State == crawlStateCompleted
+			}, 10*time.Second, 10*time.Millisecond)
+
+			flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+			require.NoError(t, err)
+			require.Len(t, flows, 1)
+			flow := flows[0]
+			assert.Equal(t, tt.wantTruncated, flow.Truncated)
+			assert.Equal(t, bodySize, flow.ResponseLength)
+
+			_, body := splitHeadersBody(flow.Response)
+			assert.Len(t, body, tt.wantCaptured)
+		})
+	}
+}
+
+func TestInFlightLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("blocks_until_released", func(t *testing.T) {
+		l := newInFlightLimiter(10)
+		require.NoError(t, l.acquire(t.Context(), 8, 0))
+
+		acquired := make(chan struct{})
+		go func() {
+			_ = l.acquire(context.Background(), 5, 0)
+			close(acquired)
+		}()
+		select {
+		case <-acquired:
+			t.Fatal("acquire should block while over the limit")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		l.release(8)
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("acquire not unblocked by release")
+		}
+	})
+
+	t.Run("sole_holder_proceeds", func(t *testing.T) {
+		l := newInFlightLimiter(10)
+		require.NoError(t, l.acquire(t.Context(), 8, 0))
+		require.NoError(t, l.acquire(t.Context(), 8, 8))
+	})
+
+	t.Run("context_cancelled", func(t *testing.T) {
+		l := newInFlightLimiter(10)
+		require.NoError(t, l.acquire(t.Context(), 10, 0))
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		assert.ErrorIs(t, l.acquire(ctx, 1, 0), context.Canceled)
+	})
+}
+
+func TestBudgetReader(t *testing.T) {
+	t.Parallel()
+
+	l := newInFlightLimiter(1 << 20)
+	r := &budgetReader{r: strings.NewReader(strings.Repeat("x", 100)), ctx: t.Context(), limiter: l, maxHold: 40}
+
+	body, size, truncated := readBodyLimited(r, 40)
+	assert.Len(t, body, 40)
+	assert.Equal(t, 100, size)
+	assert.True(t, truncated)
+	assert.Equal(t, int64(40), l.used) // discarded bytes beyond maxHold are not reserved
+
+	r.release()
+	r.release()
+	assert.Zero(t, l.used)
+}
+
 // newTestCollySession creates a CollyBackend with a pre-populated session for unit testing.
 // Returns the backend and session ID.
 func newTestCollySession(t *testing.T, flows []*CrawlFlow) (*CollyBackend, string) {
@@ -306,19 +748,25 @@ func newTestCollySession(t *testing.T, flows []*CrawlFlow) (*CollyBackend, strin
 	t.Cleanup(func() { _ = b.Close() })
 
 	ctx, cancel := context.WithCancel(t.Context())
+	runDone := make(chan struct{})
+	close(runDone) // no run to wait for on Close
 	sessionID := "test-session"
 	sess := &crawlSession{
-		info:      CrawlSessionInfo{ID: sessionID, State: crawlStateRunning, CreatedAt: time.Now()},
-		startedAt: time.Now(),
-		flowsByID: make(map[string]*CrawlFlow),
-		urlsSeen:  make(map[string]bool),
-		ctx:       ctx,
-		cancel:    cancel,
+		info:        CrawlSessionInfo{ID: sessionID, State: crawlStateRunning, CreatedAt: time.Now()},
+		startedAt:   time.Now(),
+		flowsByID:   make(map[string]*CrawlFlow),
+		urlsSeen:    make(map[string]bool),
+		ctx:         ctx,
+		cancel:      cancel,
+		searchIndex: newFlowSearchIndex(),
+		flowNotify:  make(chan struct{}),
+		runDone:     runDone,
 	}
 	for _, f := range flows {
 		f.SessionID = sessionID
 		sess.flowsByID[f.ID] = f
 		sess.flowsOrdered = append(sess.flowsOrdered, f)
+		sess.searchIndex.add(len(sess.flowsOrdered)-1, flowSearchTokens(f.Request, f.Response))
 	}
 
 	b.sessions[sessionID] = sess
@@ -380,6 +828,180 @@ func TestCollyBackend_ListFlows_since_last_with_search(t *testing.T) {
 	assert.Equal(t, "flow-4", got[0].ID)
 }
 
+func TestCollyBackend_ListFlows_invert(t *testing.T) {
+	t.Parallel()
+
+	flows := []*CrawlFlow{
+		{ID: "flow-0", Host: "a.com", Path: "/0", Method: "GET", StatusCode: 200,
+			Request: []byte("GET /0 HTTP/1.1\r\nHost: a.com\r\n\r\n"), Response: []byte("HTTP/1.1 200 OK\r\n\r\nok")},
+		{ID: "flow-1", Host: "a.com", Path: "/1", Method: "GET", StatusCode: 404,
+			Request: []byte("GET /1 HTTP/1.1\r\nHost: a.com\r\n\r\n"), Response: []byte("HTTP/1.1 404 Not Found\r\n\r\nmissing")},
+		{ID: "flow-2", Host: "a.com", Path: "/2", Method: "GET", StatusCode: 200,
+			Request: []byte("GET /2 HTTP/1.1\r\nHost: a.com\r\n\r\n"), Response: []byte("HTTP/1.1 200 OK\r\n\r\nsecret token")},
+		{ID: "flow-3", Host: "a.com", Path: "/3", Method: "GET", StatusCode: 500,
+			Request: []byte("GET /3 HTTP/1.1\r\nHost: a.com\r\n\r\n"), Response: []byte("HTTP/1.1 500 Internal Server Error\r\n\r\nerror")},
+	}
+	b, sessionID := newTestCollySession(t, flows)
+
+	ids := func(flows []CrawlFlow) []string {
+		var out []string
+		for _, f := range flows {
+			out = append(out, f.ID)
+		}
+		return out
+	}
+
+	t.Run("status", func(t *testing.T) {
+		got, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{
+			StatusCodes: parseStatusFilter("2XX"),
+			Invert:      true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"flow-1", "flow-3"}, ids(got))
+	})
+
+	t.Run("search", func(t *testing.T) {
+		got, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{
+			SearchBodyRe: regexp.MustCompile(`secret`),
+			Invert:       true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"flow-0", "flow-1", "flow-3"}, ids(got))
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		got, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{
+			StatusCodes:  parseStatusFilter("200"),
+			SearchBodyRe: regexp.MustCompile(`secret`),
+			Invert:       true,
+			Limit:        2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"flow-0", "flow-1"}, ids(got))
+	})
+}
+
+func TestCollyBackend_ListFlows_wait(t *testing.T) {
+	t.Parallel()
+
+	newSession := func(t *testing.T) (*CollyBackend, *crawlSession) {
+		t.Helper()
+		b, sessionID := newTestCollySession(t, []*CrawlFlow{{ID: "flow-0", Host: "a.com", Path: "/0", Method: "GET", StatusCode: 200}})
+		_, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{Since: sinceLast})
+		require.NoError(t, err)
+		return b, b.sessions[sessionID]
+	}
+	addFlowAfter := func(sess *crawlSession, d time.Duration, f *CrawlFlow) {
+		time.AfterFunc(d, func() {
+			sess.mu.Lock()
+			defer sess.mu.Unlock()
+			sess.flowsByID[f.ID] = f
+			sess.flowsOrdered = append(sess.flowsOrdered, f)
+			sess.notifyFlows()
+		})
+	}
+
+	t.Run("returns_when_flow_arrives", func(t *testing.T) {
+		b, sess := newSession(t)
+		addFlowAfter(sess, 50*time.Millisecond, &CrawlFlow{ID: "flow-1", Host: "a.com", Path: "/1", Method: "GET", StatusCode: 200})
+
+		start := time.Now()
+		got, err := b.ListFlows(t.Context(), sess.info.ID, CrawlListOptions{Since: sinceLast, Wait: 5 * time.Second})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, "flow-1", got[0].ID)
+		assert.Less(t, time.Since(start), 2*time.Second)
+	})
+
+	t.Run("non_matching_flow_keeps_waiting", func(t *testing.T) {
+		b, sess := newSession(t)
+		addFlowAfter(sess, 20*time.Millisecond, &CrawlFlow{ID: "flow-1", Host: "a.com", Path: "/1", Method: "GET", StatusCode: 404})
+		addFlowAfter(sess, 80*time.Millisecond, &CrawlFlow{ID: "flow-2", Host: "a.com", Path: "/2", Method: "GET", StatusCode: 200})
+
+		got, err := b.ListFlows(t.Context(), sess.info.ID, CrawlListOptions{
+			Since: sinceLast, StatusCodes: parseStatusFilter("200"), Wait: 5 * time.Second,
+		})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, "flow-2", got[0].ID)
+	})
+
+	t.Run("stop_ends_wait", func(t *testing.T) {
+		b, sess := newSession(t)
+		time.AfterFunc(50*time.Millisecond, func() { _ = b.StopSession(t.Context(), sess.info.ID) })
+
+		start := time.Now()
+		got, err := b.ListFlows(t.Context(), sess.info.ID, CrawlListOptions{Since: sinceLast, Wait: 5 * time.Second})
+		require.NoError(t, err)
+		assert.Empty(t, got)
+		assert.Less(t, time.Since(start), 2*time.Second)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		b, sess := newSession(t)
+
+		start := time.Now()
+		got, err := b.ListFlows(t.Context(), sess.info.ID, CrawlListOptions{Since: sinceLast, Wait: 50 * time.Millisecond})
+		require.NoError(t, err)
+		assert.Empty(t, got)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+}
+
+func TestCollyBackend_ListFlows_named_cursor(t *testing.T) {
+	t.Parallel()
+
+	flows := make([]*CrawlFlow, 3)
+	for i := range flows {
+		flows[i] = &CrawlFlow{ID: fmt.Sprintf("flow-%d", i), Host: "a.com", Path: fmt.Sprintf("/%d", i),
+			Method: "GET", StatusCode: 200}
+	}
+	b, sessionID := newTestCollySession(t, flows)
+	ctx := t.Context()
+
+	flowIDs := func(got []CrawlFlow) []string {
+		ids := make([]string, len(got))
+		for i, f := range got {
+			ids[i] = f.ID
+		}
+		return ids
+	}
+
+	// Cursor implies since=last and starts at the beginning
+	got, err := b.ListFlows(ctx, sessionID, CrawlListOptions{Cursor: "agent"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"flow-0", "flow-1", "flow-2"}, flowIDs(got))
+
+	got, err = b.ListFlows(ctx, sessionID, CrawlListOptions{Cursor: "agent"})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	// Other cursors and the default cursor are unaffected
+	got, err = b.ListFlows(ctx, sessionID, CrawlListOptions{Cursor: "dashboard", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"flow-0", "flow-1"}, flowIDs(got))
+
+	got, err = b.ListFlows(ctx, sessionID, CrawlListOptions{Since: sinceLast})
+	require.NoError(t, err)
+	assert.Len(t, got, 3)
+
+	// New flows are seen once per cursor
+	sess := b.sessions[sessionID]
+	sess.mu.Lock()
+	newFlow := &CrawlFlow{ID: "flow-3", SessionID: sessionID, Host: "a.com", Path: "/3", Method: "GET", StatusCode: 200}
+	sess.flowsByID[newFlow.ID] = newFlow
+	sess.flowsOrdered = append(sess.flowsOrdered, newFlow)
+	sess.mu.Unlock()
+
+	got, err = b.ListFlows(ctx, sessionID, CrawlListOptions{Cursor: "agent"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"flow-3"}, flowIDs(got))
+
+	got, err = b.ListFlows(ctx, sessionID, CrawlListOptions{Cursor: "dashboard"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"flow-2", "flow-3"}, flowIDs(got))
+}
+
 func TestCollyBackend_ListFlows_search_cursor_not_past_results(t *testing.T) {
 	t.Parallel()
 
@@ -473,3 +1095,1570 @@ func TestCollyBackend_ListFlows_search_with_limit(t *testing.T) {
 	require.Len(t, got, 1)
 	assert.Equal(t, "flow-5", got[0].ID)
 }
+
+func TestCollyBackend_GetStats(t *testing.T) {
+	t.Parallel()
+
+	flows := []*CrawlFlow{
+		{ID: "flow-0", StatusCode: 200, ContentType: "text/html; charset=utf-8"},
+		{ID: "flow-1", StatusCode: 200, ContentType: "Text/HTML"},
+		{ID: "flow-2", StatusCode: 404, ContentType: "text/plain"},
+		{ID: "flow-3", StatusCode: 500, ContentType: ""},
+	}
+	b, sessionID := newTestCollySession(t, flows)
+
+	t.Run("counts", func(t *testing.T) {
+		stats, err := b.GetStats(t.Context(), sessionID)
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, stats.TotalFlows)
+		assert.Equal(t, map[int]int{200: 2, 404: 1, 500: 1}, stats.StatusCodes)
+		assert.Equal(t, map[string]int{"text/html": 2, "text/plain": 1, "": 1}, stats.ContentTypes)
+	})
+
+	t.Run("unknown_session", func(t *testing.T) {
+		_, err := b.GetStats(t.Context(), "missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestCollyBackend_DomainHeaders(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	tokensByHost := make(map[string]string)
+	var port string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := strings.Cut(r.Host, ":")
+		mu.Lock()
+		tokensByHost[host] = r.Header.Get("X-Token") + "|" + r.Header.Get("X-Other")
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprintf(w, `<a href="http://localhost:%s/other">other</a>`, port)
+	}))
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port = serverURL.Port()
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		ExplicitDomains: []string{"localhost"},
+		Delay:           time.Millisecond,
+		IgnoreRobotsTxt: true,
+		DomainHeaders: map[string]map[string]string{
+			"127.0.0.*":     {"X-Token": "ip-token"},
+			"LOCALHOST":     {"X-Token": "named-token"},
+			"*.example.com": {"X-Other": "leak"},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "ip-token|", tokensByHost["127.0.0.1"])
+	assert.Equal(t, "named-token|", tokensByHost["localhost"])
+}
+
+func TestCollyBackend_HostResolution(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var seenHosts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seenHosts = append(seenHosts, r.Host)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	pinnedHost := "pinned.invalid:" + serverURL.Port()
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: "http://" + pinnedHost + "/"}},
+		Delay:           time.Millisecond,
+		IgnoreRobotsTxt: true,
+		HostResolution:  map[string]string{"pinned.invalid": "127.0.0.1"},
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{pinnedHost}, seenHosts)
+}
+
+func TestCollyBackend_ExternalLinks(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.Host+r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<a href="https://cdn.external.test/lib.js">a</a>
+<a href="https://cdn.external.test/lib.js">dup</a>
+<a href="mailto:admin@example.com">mail</a>
+<a href="/page">in scope</a>`))
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		Delay:           time.Millisecond,
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	links, err := b.ListExternalLinks(t.Context(), sess.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "https://cdn.external.test/lib.js", links[0].URL)
+	assert.Equal(t, "cdn.external.test", links[0].Host)
+	assert.Equal(t, server.URL+"/", links[0].FoundOn)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(t, requested, strings.TrimPrefix(server.URL, "http://")+"/page")
+}
+
+func TestCollyBackend_FormScriptDetection(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/sso":
+			_, _ = w.Write([]byte(`<html><body onload="document.forms[0].submit()">
+<form method="POST" action="/acs"><input type="hidden" name="SAMLResponse" value="abc"><noscript><input type="submit" value="Go"></noscript></form>
+</body></html>`))
+		case "/js":
+			_, _ = w.Write([]byte(`<html><body>
+<form action="/search" onsubmit="return validate()"><input type="text" name="q"></form>
+<form action="/save"><input type="text" name="name"><button onclick="send()">Save</button></form>
+<form action="/plain"><input type="hidden" name="id" value="1"><input type="text" name="comment"></form>
+<script>function send() { document.forms[1].submit(); }</script>
+</body></html>`))
+		default:
+			_, _ = w.Write([]byte(`<a href="/sso">sso</a><a href="/js">js</a>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		Delay:           time.Millisecond,
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	forms, err := b.ListForms(t.Context(), sess.ID, 0)
+	require.NoError(t, err)
+	byAction := make(map[string]DiscoveredForm)
+	for _, f := range forms {
+		byAction[strings.TrimPrefix(f.Action, server.URL)] = f
+	}
+	require.Len(t, byAction, 4)
+
+	assert.True(t, byAction["/acs"].AutoSubmit)
+	assert.False(t, byAction["/acs"].HasJSHandlers)
+	assert.True(t, byAction["/search"].HasJSHandlers)
+	assert.True(t, byAction["/save"].HasJSHandlers)
+	assert.False(t, byAction["/save"].AutoSubmit)
+	assert.False(t, byAction["/plain"].HasJSHandlers)
+	assert.False(t, byAction["/plain"].AutoSubmit)
+}
+
+func TestCollyBackend_CheckFormMethods(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/archive":
+			_, _ = w.Write([]byte("archived " + r.FormValue("id")))
+		case "/update":
+			if r.Method != http.MethodPost {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			_, _ = w.Write([]byte("updated"))
+		default:
+			_, _ = w.Write([]byte(`<html><body>
+<form method="POST" action="/archive"><input type="hidden" name="id" value="7"></form>
+<form method="POST" action="/update"><input type="text" name="name" value="x"></form>
+</body></html>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:            []CrawlSeed{{URL: server.URL + "/"}},
+		Delay:            time.Millisecond,
+		IgnoreRobotsTxt:  true,
+		CheckFormMethods: true,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	forms, err := b.ListForms(t.Context(), sess.ID, 0)
+	require.NoError(t, err)
+	byAction := make(map[string]DiscoveredForm)
+	for _, f := range forms {
+		byAction[strings.TrimPrefix(f.Action, server.URL)] = f
+	}
+	require.Len(t, byAction, 2)
+
+	archiveCheck := byAction["/archive"].MethodCheck
+	require.NotNil(t, archiveCheck)
+	assert.Equal(t, formMethodSame, archiveCheck.Verdict)
+	assert.True(t, archiveCheck.WrongMethodAccepted)
+	require.NotEmpty(t, archiveCheck.GetFlowID)
+	require.NotEmpty(t, archiveCheck.PostFlowID)
+	getFlow, err := b.GetFlow(t.Context(), archiveCheck.GetFlowID)
+	require.NoError(t, err)
+	assert.Equal(t, "GET", getFlow.Method)
+	assert.Equal(t, "/archive?id=7", getFlow.Path)
+	postFlow, err := b.GetFlow(t.Context(), archiveCheck.PostFlowID)
+	require.NoError(t, err)
+	assert.Equal(t, "POST", postFlow.Method)
+
+	updateCheck := byAction["/update"].MethodCheck
+	require.NotNil(t, updateCheck)
+	assert.Equal(t, formMethodDifferentStatus, updateCheck.Verdict)
+	assert.False(t, updateCheck.WrongMethodAccepted)
+	assert.Equal(t, http.StatusMethodNotAllowed, updateCheck.GetStatus)
+	assert.Equal(t, http.StatusOK, updateCheck.PostStatus)
+	assert.Empty(t, updateCheck.GetFlowID)
+}
+
+func TestCollyBackend_FormSubmissionFlows(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/login":
+			_, _ = w.Write([]byte("welcome " + r.FormValue("user")))
+		default:
+			_, _ = w.Write([]byte(`<html><body>
+<form method="POST" action="/login"><input type="text" name="user" value="alice"></form>
+<form method="POST" action="/never"><input type="text" name="x" value="1"></form>
+</body></html>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		Delay:           time.Millisecond,
+		IgnoreRobotsTxt: true,
+		SubmitForms:     true,
+		DisallowedPaths: []string{"*/never*"},
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	forms, err := b.ListForms(t.Context(), sess.ID, 0)
+	require.NoError(t, err)
+	byAction := make(map[string]DiscoveredForm)
+	for _, f := range forms {
+		byAction[strings.TrimPrefix(f.Action, server.URL)] = f
+	}
+	require.Len(t, byAction, 2)
+
+	loginFlows := byAction["/login"].SubmissionFlowIDs
+	require.Len(t, loginFlows, 1)
+	flow, err := b.GetFlow(t.Context(), loginFlows[0])
+	require.NoError(t, err)
+	assert.Equal(t, "POST", flow.Method)
+	assert.Equal(t, "/login", flow.Path)
+	assert.Empty(t, byAction["/never"].SubmissionFlowIDs)
+}
+
+func TestCollyBackend_SimilarClusters(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch {
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`<a href="/item/1">1</a><a href="/item/2">2</a><a href="/item/3">3</a><a href="/about">about</a>`))
+		case strings.HasPrefix(r.URL.Path, "/item/"):
+			_, _ = w.Write([]byte(templatedPage(r.URL.Path, strconv.FormatInt(time.Now().UnixNano(), 16))))
+		default:
+			_, _ = w.Write([]byte(`<html><body><h1>About us</h1><p>We have sold catalog goods since 1999. Contact support for help.</p></body></html>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	newSession := func(t *testing.T, threshold float64) string {
+		t.Helper()
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:               []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:               time.Millisecond,
+			IgnoreRobotsTxt:     true,
+			SimilarityThreshold: threshold,
+		})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+		return sess.ID
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		sessionID := newSession(t, 0.9)
+		clusters, err := b.ListSimilarClusters(t.Context(), sessionID, 0)
+		require.NoError(t, err)
+		require.Len(t, clusters, 1)
+		assert.Equal(t, 3, clusters[0].Size)
+		assert.Equal(t, clusters[0].RepresentativeID, clusters[0].FlowIDs[0])
+		for _, id := range clusters[0].FlowIDs {
+			flow, err := b.GetFlow(t.Context(), id)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(flow.Path, "/item/"), flow.Path)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		sessionID := newSession(t, 0)
+		clusters, err := b.ListSimilarClusters(t.Context(), sessionID, 0)
+		require.NoError(t, err)
+		assert.Empty(t, clusters)
+	})
+}
+
+func TestCollyBackend_SeedHints(t *testing.T) {
+	t.Parallel()
+
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /admin\nDisallow: /backup/\nSitemap: " + serverURL + "/sitemap_index.xml\n"))
+		case "/sitemap_index.xml":
+			_, _ = w.Write([]byte(`<sitemapindex><sitemap><loc>` + serverURL + `/sitemap-pages.xml</loc></sitemap></sitemapindex>`))
+		case "/sitemap-pages.xml":
+			_, _ = w.Write([]byte(`<urlset><url><loc>` + serverURL + `/hidden</loc></url><url><loc>https://other.example/page</loc></url></urlset>`))
+		case "/sitemap.xml":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><body>` + r.URL.Path + `</body></html>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	serverURL = server.URL
+
+	crawl := func(t *testing.T, recon bool) (string, []CrawlFlow) {
+		t.Helper()
+		cfg := config.DefaultConfig()
+		cfg.Crawler.Recon = &recon
+		b := NewCollyBackend(cfg, nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+		})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		require.NoError(t, err)
+		var discovered []string
+		for _, p := range status.DiscoveredPaths {
+			assert.Equal(t, discoveredPathRobot, p.Source)
+			discovered = append(discovered, p.URL)
+		}
+		flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+		require.NoError(t, err)
+		return strings.Join(discovered, " "), flows
+	}
+
+	t.Run("recon_enabled", func(t *testing.T) {
+		discovered, flows := crawl(t, true)
+		assert.Equal(t, server.URL+"/admin "+server.URL+"/backup/", discovered)
+
+		var paths []string
+		for _, f := range flows {
+			paths = append(paths, f.Path)
+		}
+		assert.ElementsMatch(t, []string{"/", "/hidden"}, paths)
+	})
+
+	t.Run("recon_disabled", func(t *testing.T) {
+		discovered, flows := crawl(t, false)
+		assert.Empty(t, discovered)
+		require.Len(t, flows, 1)
+		assert.Equal(t, "/", flows[0].Path)
+	})
+}
+
+func TestCollyBackend_StreamStatus(t *testing.T) {
+	t.Parallel()
+
+	drain := func(t *testing.T, ch <-chan CrawlStatus) []CrawlStatus {
+		t.Helper()
+
+		var statuses []CrawlStatus
+		timeout := time.After(10 * time.Second)
+		for {
+			select {
+			case s, ok := <-ch:
+				if !ok {
+					return statuses
+				}
+				statuses = append(statuses, s)
+			case <-timeout:
+				t.Fatal("status stream was not closed")
+			}
+		}
+	}
+
+	t.Run("through_completion", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+			w.Header().Set("Content-Type", "text/html")
+			if r.URL.Path == "/" {
+				_, _ = w.Write([]byte(`<a href="/a">a</a><a href="/b">b</a><a href="/c">c</a>`))
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		info, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Label:           "streamed",
+			Delay:           time.Millisecond,
+			Parallelism:     1,
+			IgnoreRobotsTxt: true,
+		})
+		require.NoError(t, err)
+
+		ch, err := b.StreamStatus(t.Context(), "streamed")
+		require.NoError(t, err)
+		statuses := drain(t, ch)
+
+		require.NotEmpty(t, statuses)
+		last := statuses[len(statuses)-1]
+		assert.Equal(t, crawlStateCompleted, last.State)
+		assert.Equal(t, 4, last.URLsVisited)
+		for _, s := range statuses[:len(statuses)-1] {
+			assert.Equal(t, crawlStateRunning, s.State)
+		}
+
+		// A finished session yields its final status once
+		ch, err = b.StreamStatus(t.Context(), info.ID)
+		require.NoError(t, err)
+		statuses = drain(t, ch)
+		require.Len(t, statuses, 1)
+		assert.Equal(t, crawlStateCompleted, statuses[0].State)
+	})
+
+	t.Run("closed_on_stop", func(t *testing.T) {
+		t.Parallel()
+
+		requested := make(chan struct{}, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case requested <- struct{}{}:
+			default:
+			}
+			<-r.Context().Done() // hold every request open until the stop abandons it
+		}))
+		t.Cleanup(server.Close)
+
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		info, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+		})
+		require.NoError(t, err)
+
+		ch, err := b.StreamStatus(t.Context(), info.ID)
+		require.NoError(t, err)
+		select {
+		case <-requested:
+		case <-time.After(10 * time.Second):
+			t.Fatal("seed was never requested")
+		}
+		require.NoError(t, b.StopSession(t.Context(), info.ID))
+
+		statuses := drain(t, ch)
+		require.NotEmpty(t, statuses)
+		assert.Equal(t, crawlStateStopped, statuses[len(statuses)-1].State)
+	})
+
+	t.Run("closed_on_cancel", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		t.Cleanup(server.Close)
+
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		info, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+		})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		ch, err := b.StreamStatus(ctx, info.ID)
+		require.NoError(t, err)
+		cancel()
+		drain(t, ch)
+	})
+
+	t.Run("unknown_session", func(t *testing.T) {
+		t.Parallel()
+
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		_, err := b.StreamStatus(t.Context(), "missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestCollyBackend_ResumeSession(t *testing.T) {
+	t.Parallel()
+
+	var blockMu sync.Mutex
+	block := true
+	blocked := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`<a href="/slow">slow</a><a href="/b">b</a><a href="/c">c</a>`))
+		case "/slow":
+			blockMu.Lock()
+			wait := block
+			blockMu.Unlock()
+			if wait { // hold the request open until the stop abandons it
+				blocked <- struct{}{}
+				<-r.Context().Done()
+				return
+			}
+			_, _ = w.Write([]byte(`<a href="/d">d</a>`))
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	info, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		Label:           "resumable",
+		Delay:           time.Millisecond,
+		Parallelism:     1,
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+
+	t.Run("running_rejected", func(t *testing.T) {
+		_, err := b.ResumeSession(t.Context(), info.ID)
+		assert.ErrorContains(t, err, "already running")
+	})
+
+	select {
+	case <-blocked:
+	case <-time.After(10 * time.Second):
+		t.Fatal("slow page was never requested")
+	}
+	require.NoError(t, b.StopSession(t.Context(), info.ID))
+	blockMu.Lock()
+	block = false
+	blockMu.Unlock()
+
+	requeued, err := b.ResumeSession(t.Context(), "resumable")
+	require.NoError(t, err)
+	assert.Positive(t, requeued)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), info.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	flows, err := b.ListFlows(t.Context(), info.ID, CrawlListOptions{})
+	require.NoError(t, err)
+	paths := make(map[string]int)
+	for _, f := range flows {
+		paths[f.Path]++
+	}
+	assert.Equal(t, map[string]int{"/": 1, "/slow": 1, "/b": 1, "/c": 1, "/d": 1}, paths)
+
+	t.Run("completed_resumes_with_nothing_pending", func(t *testing.T) {
+		requeued, err := b.ResumeSession(t.Context(), info.ID)
+		require.NoError(t, err)
+		assert.Zero(t, requeued)
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), info.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("unknown_session", func(t *testing.T) {
+		_, err := b.ResumeSession(t.Context(), "nonexistent")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("closed_backend", func(t *testing.T) {
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		info, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/b"}},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+		})
+		require.NoError(t, err)
+		require.NoError(t, b.StopSession(t.Context(), info.ID))
+		require.NoError(t, b.Close())
+
+		_, err = b.ResumeSession(t.Context(), info.ID)
+		assert.ErrorContains(t, err, "backend is closed")
+	})
+}
+
+func TestCollyBackend_ResumeSessionKeepsDepth(t *testing.T) {
+	t.Parallel()
+
+	var blockOnce sync.Once
+	blocked := make(chan struct{}, 1)
+	var refererMu sync.Mutex
+	var bReferer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`<a href="/slow">slow</a><a href="/b">b</a>`))
+		case "/slow":
+			first := false
+			blockOnce.Do(func() { first = true })
+			if first { // hold the request open until the stop abandons it
+				blocked <- struct{}{}
+				<-r.Context().Done()
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		case "/b":
+			refererMu.Lock()
+			bReferer = r.Header.Get("Referer")
+			refererMu.Unlock()
+			_, _ = w.Write([]byte(`<a href="/deep">deep</a>`))
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	info, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		MaxDepth:        2,
+		Delay:           time.Millisecond,
+		Parallelism:     1,
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+
+	select {
+	case <-blocked:
+	case <-time.After(10 * time.Second):
+		t.Fatal("slow page was never requested")
+	}
+	require.NoError(t, b.StopSession(t.Context(), info.ID))
+
+	_, err = b.ResumeSession(t.Context(), info.ID)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), info.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	flows, err := b.ListFlows(t.Context(), info.ID, CrawlListOptions{})
+	require.NoError(t, err)
+	foundOn := make(map[string]string)
+	for _, f := range flows {
+		foundOn[f.Path] = f.FoundOn
+	}
+	// /b resumes at depth 2, so /deep found on it is past MaxDepth
+	assert.Equal(t, map[string]string{"/": "seed", "/slow": server.URL + "/", "/b": server.URL + "/"}, foundOn)
+	refererMu.Lock()
+	defer refererMu.Unlock()
+	assert.Equal(t, server.URL+"/", bReferer)
+}
+func TestCollyBackend_FollowLinksOnError(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T) *httptest.Server {
+		t.Helper()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			switch r.URL.Path {
+			case "/":
+				_, _ = w.Write([]byte(`<a href="/private">private</a>`))
+			case "/private":
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`<nav><a href="/help">help</a></nav>Forbidden`))
+			default:
+				_, _ = w.Write([]byte("ok"))
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	crawl := func(t *testing.T, server *httptest.Server, follow bool) (*CollyBackend, string) {
+		t.Helper()
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:              []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:              time.Millisecond,
+			IgnoreRobotsTxt:    true,
+			FollowLinksOnError: follow,
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+		return b, sess.ID
+	}
+
+	paths := func(t *testing.T, b *CollyBackend, sessionID string) map[string]int {
+		t.Helper()
+		flows, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{})
+		require.NoError(t, err)
+		result := make(map[string]int)
+		for _, f := range flows {
+			result[f.Path] = f.StatusCode
+		}
+		return result
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		b, sessionID := crawl(t, newServer(t), false)
+
+		got := paths(t, b, sessionID)
+		assert.NotContains(t, got, "/private")
+		assert.NotContains(t, got, "/help")
+
+		errs, err := b.ListErrors(t.Context(), sessionID, 0)
+		require.NoError(t, err)
+		require.Len(t, errs, 1)
+		assert.Equal(t, http.StatusForbidden, errs[0].Status)
+		assert.Empty(t, errs[0].FlowID)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+		b, sessionID := crawl(t, newServer(t), true)
+
+		got := paths(t, b, sessionID)
+		assert.Equal(t, http.StatusForbidden, got["/private"])
+		assert.Equal(t, http.StatusOK, got["/help"])
+
+		errs, err := b.ListErrors(t.Context(), sessionID, 0)
+		require.NoError(t, err)
+		require.Len(t, errs, 1)
+		assert.Equal(t, http.StatusForbidden, errs[0].Status)
+		assert.NotEmpty(t, errs[0].FlowID)
+	})
+}
+
+func TestCollyBackend_MaxHosts(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var seenHosts []string
+	var port string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seenHosts = append(seenHosts, r.Host)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprintf(w, `<a href="/same">same</a><a href="http://localhost:%s/other">other</a>`, port)
+	}))
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port = serverURL.Port()
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		ExplicitDomains: []string{"localhost"},
+		MaxHosts:        1,
+		Delay:           time.Millisecond,
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+
+	var status *CrawlStatus
+	require.Eventually(t, func() bool {
+		status, err = b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, []string{"127.0.0.1"}, status.Hosts)
+	assert.Equal(t, []string{"localhost"}, status.SkippedHosts)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, seenHosts, 2) // seed and /same only
+	for _, h := range seenHosts {
+		assert.Equal(t, serverURL.Host, h)
+	}
+}
+
+func TestCollyBackend_MaxPagesPerHost(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	hostRequests := make(map[string]int)
+	var port string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hostRequests[strings.Split(r.Host, ":")[0]]++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		for i := 1; i <= 5; i++ {
+			_, _ = fmt.Fprintf(w, `<a href="/p%d">p</a><a href="http://localhost:%s/q%d">q</a>`, i, port, i)
+		}
+	}))
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port = serverURL.Port()
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		ExplicitDomains: []string{"localhost"},
+		MaxPagesPerHost: 3,
+		Delay:           time.Millisecond,
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+
+	var status *CrawlStatus
+	require.Eventually(t, func() bool {
+		status, err = b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, []string{"127.0.0.1", "localhost"}, status.Hosts)
+	// 127.0.0.1: seed + /p1-/p5 (6 URLs); localhost: /q1-/q5 plus its own relative /p1-/p5 (10 URLs)
+	assert.Equal(t, map[string]int{"127.0.0.1": 3, "localhost": 7}, status.HostQuotaSkips)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]int{"127.0.0.1": 3, "localhost": 3}, hostRequests)
+}
+
+func TestCollyBackend_MergeTrailingSlash(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	requested := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = fmt.Fprint(w, `<a href="/docs">d</a><a href="/docs/#top">d</a><a href="/gone">g</a><a href="/gone/">g</a>`)
+		case "/gone":
+			http.NotFound(w, r)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = fmt.Fprint(w, `<a href="/">home</a>`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:              []CrawlSeed{{URL: server.URL + "/"}},
+		MergeTrailingSlash: true,
+		Delay:              time.Millisecond,
+		IgnoreRobotsTxt:    true,
+	})
+	require.NoError(t, err)
+
+	var status *CrawlStatus
+	require.Eventually(t, func() bool {
+		status, err = b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// /docs/ merges into /docs; /gone/ is crawled after all since /gone returned 404
+	assert.Equal(t, 1, status.MergedSlashVariants)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]int{"/": 1, "/docs": 1, "/gone": 1, "/gone/": 1}, requested)
+}
+
+func TestCollyBackend_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"/":  `<a href="/c">c</a><a href="/a">a</a><a href="/b">b</a>`,
+		"/a": `<a href="/z">z</a><a href="/a1">a1</a>`,
+		"/b": `<a href="/b2">b2</a><a href="/a">a</a>`,
+	}
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, pages[r.URL.Path])
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		Deterministic:   true,
+		Parallelism:     4,
+		Delay:           time.Millisecond,
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// Breadth-first, sorted within each depth, regardless of discovery order
+	wantOrder := []string{"/", "/a", "/b", "/c", "/a1", "/b2", "/z"}
+	mu.Lock()
+	assert.Equal(t, wantOrder, requested)
+	mu.Unlock()
+
+	flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+	require.NoError(t, err)
+	paths := make([]string, 0, len(flows))
+	for _, f := range flows {
+		paths = append(paths, f.Path)
+	}
+	assert.Equal(t, wantOrder, paths)
+}
+
+func TestCollyBackend_DeterministicLateSeeds(t *testing.T) {
+	t.Parallel()
+
+	rootStarted := make(chan struct{})
+	releaseRoot := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		close(rootStarted)
+		<-releaseRoot
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, "<html></html>")
+	}))
+	t.Cleanup(server.Close)
+
+	// Seed hints for an added seed's origin run alongside the crawl; its sitemap answers
+	// only after the run's first drain
+	releaseSitemap := make(chan struct{})
+	late := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.WriteHeader(http.StatusNotFound)
+		case "/sitemap.xml":
+			<-releaseSitemap
+			_, _ = fmt.Fprint(w, `<urlset><url><loc>http://`+r.Host+`/late</loc></url></urlset>`)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = fmt.Fprint(w, "<html></html>")
+		}
+	}))
+	t.Cleanup(late.Close)
+
+	recon := true
+	cfg := config.DefaultConfig()
+	cfg.Crawler.Recon = &recon
+	b := NewCollyBackend(cfg, nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		Deterministic:   true,
+		Delay:           time.Millisecond,
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+
+	<-rootStarted
+	require.NoError(t, b.AddSeeds(t.Context(), sess.ID, []CrawlSeed{{URL: late.URL + "/"}}))
+	close(releaseRoot)
+	require.Eventually(t, func() bool {
+		flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{Host: late.Listener.Addr().String()})
+		return err == nil && len(flows) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	close(releaseSitemap)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{PathPattern: "/late"})
+	require.NoError(t, err)
+	assert.Len(t, flows, 1)
+}
+
+func TestCollyBackend_DepthFirst(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"/":   `<a href="/b">b</a><a href="/a">a</a>`,
+		"/a":  `<a href="/a1">a1</a><a href="/a2">a2</a>`,
+		"/a1": `<a href="/a1x">a1x</a>`,
+		"/b":  `<a href="/b1">b1</a>`,
+	}
+
+	tests := []struct {
+		name          string
+		deterministic bool
+		maxDepth      int
+		want          []string
+	}{
+		{
+			name: "discovery_order",
+			want: []string{"/", "/b", "/b1", "/a", "/a1", "/a1x", "/a2"},
+		},
+		{
+			name:          "deterministic",
+			deterministic: true,
+			want:          []string{"/", "/a", "/a1", "/a1x", "/a2", "/b", "/b1"},
+		},
+		{
+			name:     "max_depth",
+			maxDepth: 3,
+			want:     []string{"/", "/b", "/b1", "/a", "/a1", "/a2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var requested []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requested = append(requested, r.URL.Path)
+				mu.Unlock()
+				w.Header().Set("Content-Type", "text/html")
+				_, _ = fmt.Fprint(w, pages[r.URL.Path])
+			}))
+			t.Cleanup(server.Close)
+
+			b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+			t.Cleanup(func() { _ = b.Close() })
+
+			sess, err := b.CreateSession(t.Context(), CrawlOptions{
+				Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+				Strategy:        crawlStrategyDFS,
+				Deterministic:   tt.deterministic,
+				MaxDepth:        tt.maxDepth,
+				Parallelism:     4,
+				Delay:           time.Millisecond,
+				IgnoreRobotsTxt: true,
+			})
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				status, err := b.GetStatus(t.Context(), sess.ID)
+				return err == nil && status.State == crawlStateCompleted
+			}, 10*time.Second, 10*time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, tt.want, requested)
+		})
+	}
+
+	t.Run("invalid_strategy", func(t *testing.T) {
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		_, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:    []CrawlSeed{{URL: "https://example.com/"}},
+			Strategy: "random",
+		})
+		require.ErrorContains(t, err, "invalid strategy")
+	})
+}
+
+func TestCollyBackend_MaxInFlightBytes(t *testing.T) {
+	t.Parallel()
+
+	page := strings.Repeat("a", 64*1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			for i := 0; i < 6; i++ {
+				_, _ = fmt.Fprintf(w, `<a href="/p%d">p</a>`, i)
+			}
+			return
+		}
+		_, _ = w.Write([]byte(page))
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:            []CrawlSeed{{URL: server.URL + "/"}},
+		MaxInFlightBytes: 16 * 1024, // smaller than one page
+		Parallelism:      4,
+		Delay:            time.Millisecond,
+		IgnoreRobotsTxt:  true,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{PathPattern: "/p*"})
+	require.NoError(t, err)
+	require.Len(t, flows, 6)
+	for _, f := range flows {
+		full, err := b.GetFlow(t.Context(), f.ID)
+		require.NoError(t, err)
+		_, body := splitHeadersBody(full.Response)
+		assert.Len(t, body, len(page))
+	}
+}
+
+func TestTrailingSlashKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{"trailing_slash", "https://example.com/a/b/", "https://example.com/a/b"},
+		{"no_trailing_slash", "https://example.com/a/b", "https://example.com/a/b"},
+		{"root", "https://example.com", "https://example.com/"},
+		{"keeps_query", "https://example.com/a/?x=1", "https://example.com/a?x=1"},
+		{"drops_fragment", "https://example.com/a/#top", "https://example.com/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := trailingSlashKey(tt.link)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExtractJSONURLs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"absolute_url", `{"next":"https://example.com/page/2"}`, []string{"https://example.com/page/2"}},
+		{"root_relative", `{"href":"/api/users/1"}`, []string{"/api/users/1"}},
+		{"nested_links", `{"_links":{"self":{"href":"/a"},"items":[{"url":"http://example.com/b"}]}}`,
+			[]string{"/a", "http://example.com/b"}},
+		{"top_level_array", `["/x", "not a url"]`, []string{"/x"}},
+		{"ignores_non_urls", `{"name":"alice","id":5,"path":"relative/path","ok":true}`, nil},
+		{"ignores_protocol_relative", `{"cdn":"//cdn.example.com/x"}`, nil},
+		{"ignores_other_schemes", `{"mail":"mailto:a@example.com","js":"javascript:void(0)"}`, nil},
+		{"invalid_json", `{not json`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.want, extractJSONURLs([]byte(tt.body)))
+		})
+	}
+}
+
+func TestCollyBackend_ExtractJSONURLs(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[],"_links":{"next":{"href":"/api/page2"}}}`))
+		case "/hal":
+			w.Header().Set("Content-Type", "application/hal+json")
+			_, _ = w.Write([]byte(`{"_links":{"self":{"href":"/hal"},"orders":{"href":"/hal/orders"}}}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	runCrawl := func(t *testing.T, seedPath string, extract bool) []CrawlFlow {
+		t.Helper()
+
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + seedPath}},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+			ExtractJSONURLs: extract,
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+
+		flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+		require.NoError(t, err)
+		return flows
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		flows := runCrawl(t, "/api", true)
+		require.Len(t, flows, 2)
+
+		var page2 *CrawlFlow
+		for i := range flows {
+			if flows[i].Path == "/api/page2" {
+				page2 = &flows[i]
+			}
+		}
+		require.NotNil(t, page2)
+		assert.Equal(t, server.URL+"/api", page2.FoundOn)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		flows := runCrawl(t, "/api", false)
+		assert.Len(t, flows, 1)
+	})
+
+	t.Run("hal_json", func(t *testing.T) {
+		flows := runCrawl(t, "/hal", true)
+
+		paths := make([]string, 0, len(flows))
+		for _, f := range flows {
+			paths = append(paths, f.Path)
+		}
+		assert.ElementsMatch(t, []string{"/hal", "/hal/orders"}, paths)
+	})
+}
+
+func TestExtractCSSURLs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"unquoted_url", `body{background:url(/img/bg.png)}`, []string{"/img/bg.png"}},
+		{"quoted_urls", `@font-face{src:url("fonts/a.woff2") format("woff2"), url( 'fonts/a.woff' )}`,
+			[]string{"fonts/a.woff2", "fonts/a.woff"}},
+		{"import_forms", `@import "base.css"; @import url(theme.css) screen; @import 'print.css' print;`,
+			[]string{"base.css", "theme.css", "print.css"}},
+		{"ignores_inline_and_fragments", `a{background:url(data:image/png;base64,AAAA)} b{filter:url(#blur)}`, nil},
+		{"ignores_comments", `/* url(/hidden.png) */ c{background:URL(/shown.png)}`, []string{"/shown.png"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.want, extractCSSURLs([]byte(tt.body)))
+		})
+	}
+}
+
+func TestCollyBackend_ExtractCSSURLs(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><head><link rel="stylesheet" href="/static/site.css">
+<style>.hero{background:url(/static/hero.css)}</style></head><body></body></html>`))
+		case "/static/site.css":
+			w.Header().Set("Content-Type", "text/css")
+			_, _ = w.Write([]byte(`@import "theme.css"; .x{background:url(../admin/logo.txt)}`))
+		default:
+			w.Header().Set("Content-Type", "text/css")
+			_, _ = w.Write([]byte(`.y{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	runCrawl := func(t *testing.T, extract bool) map[string]string {
+		t.Helper()
+
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+			ExtractCSSURLs:  extract,
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+
+		flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+		require.NoError(t, err)
+		foundOn := make(map[string]string)
+		for _, f := range flows {
+			foundOn[f.Path] = f.FoundOn
+		}
+		return foundOn
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		foundOn := runCrawl(t, true)
+		assert.Len(t, foundOn, 5)
+		assert.Equal(t, server.URL+"/", foundOn["/static/site.css"])
+		assert.Equal(t, server.URL+"/", foundOn["/static/hero.css"])
+		assert.Equal(t, server.URL+"/static/site.css", foundOn["/static/theme.css"])
+		assert.Equal(t, server.URL+"/static/site.css", foundOn["/admin/logo.txt"])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		foundOn := runCrawl(t, false)
+		assert.Len(t, foundOn, 1)
+	})
+}
+
+func TestIsDirectoryListing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{name: "apache", body: `<html><head><title>Index of /files</title></head><body><h1>Index of /files</h1>`, want: true},
+		{name: "nginx", body: `<html><head><title>Index of /</title></head><body><h1>Index of /</h1><hr><pre><a href="../">../</a>`, want: true},
+		{name: "python_http_server", body: `<title>Directory listing for /static/</title>`, want: true},
+		{name: "iis", body: `<pre><A HREF="/">[To Parent Directory]</A><br><br>`, want: true},
+		{name: "apache_sort_links", body: `<th><a href="?C=N;O=D">Name</a></th>`, want: true},
+		{name: "regular_page", body: `<html><head><title>Home</title></head><body><a href="/docs">Index of topics</a></body></html>`, want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, isDirectoryListing([]byte(tc.body)))
+		})
+	}
+}
+
+func TestCollyBackend_DetectDirListing(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`<html><body><a href="/files/">files</a></body></html>`))
+		case "/files/":
+			_, _ = w.Write([]byte(`<html><head><title>Index of /files</title></head><body><h1>Index of /files</h1>
+<table><tr><th><a href="?C=N;O=D">Name</a></th><th><a href="?C=M;O=A">Last modified</a></th></tr>
+<tr><td><a href="/">Parent Directory</a></td></tr>
+<tr><td><a href="backup.sql">backup.sql</a></td></tr></table></body></html>`))
+		default:
+			_, _ = w.Write([]byte(`<html><body>ok</body></html>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:            []CrawlSeed{{URL: server.URL + "/"}},
+		Delay:            time.Millisecond,
+		IgnoreRobotsTxt:  true,
+		DetectDirListing: true,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+	require.NoError(t, err)
+	findings := make(map[string][]string)
+	for _, f := range flows {
+		findings[f.Path] = f.Findings
+	}
+
+	assert.Len(t, findings, 3) // sort links are not followed
+	assert.Equal(t, []string{findingDirectoryListing}, findings["/files/"])
+	assert.Empty(t, findings["/"])
+	assert.Contains(t, findings, "/files/backup.sql")
+	assert.Empty(t, findings["/files/backup.sql"])
+}
+
+func TestCollyBackend_RedirectTargets(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<a href="/go">go</a><a href="/out">out</a>`))
+		case "/go":
+			http.Redirect(w, r, "/target", http.StatusFound)
+		case "/out":
+			http.Redirect(w, r, "https://other.example/landing", http.StatusFound)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`ok`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		Delay:           time.Millisecond,
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	t.Run("followed_redirect_captured_once", func(t *testing.T) {
+		flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{PathPattern: "/target"})
+		require.NoError(t, err)
+		assert.Len(t, flows, 1)
+	})
+
+	t.Run("unfollowed_redirect_recorded_external", func(t *testing.T) {
+		links, err := b.ListExternalLinks(t.Context(), sess.ID, 0)
+		require.NoError(t, err)
+		require.Len(t, links, 1)
+		assert.Equal(t, "https://other.example/landing", links[0].URL)
+		assert.Equal(t, server.URL+"/out", links[0].FoundOn)
+	})
+}
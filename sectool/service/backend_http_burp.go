@@ -9,6 +9,7 @@ import (
 	"net"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-appsec/toolbox/sectool/protocol"
@@ -18,11 +19,13 @@ import (
 
 // BurpBackend implements HttpBackend using Burp Suite via MCP.
 type BurpBackend struct {
-	client *mcp.BurpClient
+	client       *mcp.BurpClient
+	intercepting atomic.Bool // last state set through SetIntercept
 }
 
 // Compile-time check that BurpBackend implements HttpBackend
 var _ HttpBackend = (*BurpBackend)(nil)
+var _ proxyInterceptor = (*BurpBackend)(nil)
 
 // ConnectBurpBackend creates a new Burp HttpBackend with the given MCP URL.
 func ConnectBurpBackend(ctx context.Context, url string, opts ...mcp.Option) (*BurpBackend, error) {
@@ -100,6 +103,11 @@ func (b *BurpBackend) SendRequest(ctx context.Context, name string, req SendRequ
 	log.Printf("burp: sending request %s to %s://%s:%d (follow_redirects=%v)",
 		name, scheme, req.Target.Hostname, req.Target.Port, req.FollowRedirects)
 
+	if len(req.HostResolution) > 0 {
+		// Burp performs its own DNS resolution; configure hostname overrides in Burp instead
+		return nil, errors.New("host resolution overrides are not supported by the Burp backend")
+	}
+
 	return b.doSendRequest(ctx, name, req)
 }
 
@@ -296,6 +304,28 @@ func (b *BurpBackend) SetInterceptState(ctx context.Context, intercepting bool)
 	return b.client.SetInterceptState(ctx, intercepting)
 }
 
+// SetIntercept toggles Burp's proxy intercept. Burp holds requests in its own Intercept
+// tab and applies its own intercept rules, so filters are not supported.
+func (b *BurpBackend) SetIntercept(ctx context.Context, enabled bool, filter InterceptFilter) error {
+	if filter != (InterceptFilter{}) {
+		return errors.New("intercept filters are not supported with Burp; configure Burp's intercept rules instead")
+	} else if err := b.SetInterceptState(ctx, enabled); err != nil {
+		return err
+	}
+	b.intercepting.Store(enabled)
+	return nil
+}
+
+// InterceptStatus reports the last intercept state set; held requests are listed in Burp.
+func (b *BurpBackend) InterceptStatus() InterceptStatus {
+	return InterceptStatus{Enabled: b.intercepting.Load()}
+}
+
+// ReleaseHeld is not supported: requests held by Burp are forwarded from its Intercept tab.
+func (b *BurpBackend) ReleaseHeld(string, bool) (int, error) {
+	return 0, errors.New("requests held by Burp are forwarded or dropped in Burp's Proxy > Intercept tab")
+}
+
 // sectool comment prefix identifies rules managed by sectool
 const sectoolRulePrefix = "sectool:"
 
@@ -334,6 +364,12 @@ func (b *BurpBackend) ListRules(ctx context.Context, websocket bool) ([]protocol
 }
 
 func (b *BurpBackend) AddRule(ctx context.Context, input ProxyRuleInput) (*protocol.RuleEntry, error) {
+	if input.Type == RuleTypeBlock || input.Type == RuleTypeRedirect {
+		return nil, fmt.Errorf("%s rules are only supported by the built-in proxy", input.Type)
+	} else if err := validateRuleAction(input); err != nil {
+		return nil, err
+	}
+
 	httpRules, err := b.getAllRules(ctx, false)
 	if err != nil {
 		return nil, fmt.Errorf("add rule: %w", err)
@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -37,6 +38,8 @@ type NativeProxyBackend struct {
 	wsRules     []nativeStoredRule
 	ruleStorage store.Storage
 
+	intercept interceptQueue
+
 	closed atomic.Bool
 }
 
@@ -48,14 +51,61 @@ type nativeStoredRule struct {
 	IsRegex bool   `json:"is_regex" msgpack:"ir"`
 	Match   string `json:"match" msgpack:"m"`
 	Replace string `json:"replace" msgpack:"r"`
+	Host    string `json:"host,omitempty" msgpack:"h,omitempty"`
+	Path    string `json:"path,omitempty" msgpack:"p,omitempty"`
+	Method  string `json:"method,omitempty" msgpack:"mt,omitempty"`
 
 	// compiled is the pre-compiled regex (nil if not a regex rule)
 	compiled *regexp.Regexp `msgpack:"-"`
+	// hostRe and pathRe are the compiled Host and Path globs (nil when empty)
+	hostRe, pathRe *regexp.Regexp `msgpack:"-"`
+}
+
+// entry returns the rule as reported by ListRules and AddRule.
+func (r nativeStoredRule) entry() protocol.RuleEntry {
+	return protocol.RuleEntry{
+		RuleID:  r.ID,
+		Label:   r.Label,
+		Type:    r.Type,
+		IsRegex: r.IsRegex,
+		Match:   r.Match,
+		Replace: r.Replace,
+		Host:    r.Host,
+		Path:    r.Path,
+		Method:  r.Method,
+	}
+}
+
+// compileScope compiles the Host and Path globs of a block or redirect rule.
+func (r *nativeStoredRule) compileScope() error {
+	var err error
+	if r.hostRe, err = compileScopeGlob(strings.ToLower(r.Host)); err != nil {
+		return fmt.Errorf("invalid host pattern %q: %w", r.Host, err)
+	} else if r.pathRe, err = compileScopeGlob(r.Path); err != nil {
+		return fmt.Errorf("invalid path pattern %q: %w", r.Path, err)
+	}
+	return nil
+}
+
+// compileScopeGlob compiles a glob matched against the whole string; nil for an empty glob.
+func compileScopeGlob(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile("^" + globToRegex(pattern) + "$")
+}
+
+// matchesRequest reports whether a request to host falls in the rule's scope.
+func (r *nativeStoredRule) matchesRequest(host string, req *proxy.RawHTTP1Request) bool {
+	return (r.Method == "" || strings.EqualFold(r.Method, req.Method)) &&
+		(r.hostRe == nil || r.hostRe.MatchString(strings.ToLower(host))) &&
+		(r.pathRe == nil || r.pathRe.MatchString(req.Path))
 }
 
 // Compile-time checks that NativeProxyBackend implements interfaces.
 var _ HttpBackend = (*NativeProxyBackend)(nil)
 var _ proxy.RuleApplier = (*NativeProxyBackend)(nil)
+var _ proxyInterceptor = (*NativeProxyBackend)(nil)
 
 // NewNativeProxyBackend creates a new native proxy backend.
 // Does NOT start serving - call Serve() separately (typically in a goroutine).
@@ -81,6 +131,7 @@ func NewNativeProxyBackend(port int, configDir string, maxBodyBytes int, history
 	}
 
 	server.SetRuleApplier(b) // Wire backend as rule applier for the proxy handlers
+	server.SetRequestHolder(&b.intercept)
 
 	return b, nil
 }
@@ -95,6 +146,11 @@ func (b *NativeProxyBackend) Addr() string {
 	return b.server.Addr()
 }
 
+// SetHalted makes the proxy drop client connections while halted (kill switch).
+func (b *NativeProxyBackend) SetHalted(halted bool) {
+	b.server.SetHalted(halted)
+}
+
 // WaitReady blocks until Serve() has entered its accept loop.
 func (b *NativeProxyBackend) WaitReady(ctx context.Context) error {
 	return b.server.WaitReady(ctx)
@@ -118,6 +174,9 @@ func (b *NativeProxyBackend) loadRuleList(key string) ([]nativeStoredRule, error
 				return nil, fmt.Errorf("invalid stored regex in rule %s (match=%q): %w", rules[i].ID, rules[i].Match, err)
 			}
 		}
+		if err := rules[i].compileScope(); err != nil {
+			return nil, fmt.Errorf("invalid stored scope in rule %s: %w", rules[i].ID, err)
+		}
 	}
 	return rules, nil
 }
@@ -139,12 +198,35 @@ func (b *NativeProxyBackend) Close() error {
 		return nil
 	}
 
+	// Held requests are dropped, not forwarded, so shutdown does not wait on them
+	b.intercept.set(false, InterceptFilter{})
+	_, _ = b.intercept.release("", true)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	return b.server.Shutdown(ctx)
 }
 
+// SetIntercept turns request holding on with filter, or off, forwarding any held requests.
+func (b *NativeProxyBackend) SetIntercept(_ context.Context, enabled bool, filter InterceptFilter) error {
+	b.intercept.set(enabled, filter)
+	if !enabled {
+		_, _ = b.intercept.release("", false)
+	}
+	return nil
+}
+
+// InterceptStatus reports the intercept state and held requests.
+func (b *NativeProxyBackend) InterceptStatus() InterceptStatus {
+	return b.intercept.status()
+}
+
+// ReleaseHeld forwards or drops a held request, or all of them when id is empty.
+func (b *NativeProxyBackend) ReleaseHeld(id string, drop bool) (int, error) {
+	return b.intercept.release(id, drop)
+}
+
 // CACert returns the CA certificate used for MITM TLS interception.
 func (b *NativeProxyBackend) CACert() *x509.Certificate {
 	return b.server.CertManager().CACert()
@@ -211,8 +293,9 @@ func (b *NativeProxyBackend) SendRequest(ctx context.Context, name string, req S
 	}
 
 	sender := &proxy.Sender{
-		JSONModifier: ModifyJSONBodyMap,
-		Timeouts:     b.timeouts,
+		JSONModifier:   ModifyJSONBodyMap,
+		Timeouts:       b.timeouts,
+		HostResolution: req.HostResolution,
 	}
 
 	var result *proxy.SendResult
@@ -228,9 +311,10 @@ func (b *NativeProxyBackend) SendRequest(ctx context.Context, name string, req S
 
 	var buf bytes.Buffer
 	return &SendRequestResult{
-		Headers:  result.Response.SerializeHeaders(&buf),
-		Body:     result.Response.Body,
-		Duration: result.Duration,
+		Headers:   result.Response.SerializeHeaders(&buf),
+		Body:      result.Response.Body,
+		Duration:  result.Duration,
+		Redirects: result.Redirects,
 	}, nil
 }
 
@@ -245,14 +329,7 @@ func (b *NativeProxyBackend) ListRules(ctx context.Context, websocket bool) ([]p
 
 	result := make([]protocol.RuleEntry, 0, len(rules))
 	for _, r := range rules {
-		result = append(result, protocol.RuleEntry{
-			RuleID:  r.ID,
-			Label:   r.Label,
-			Type:    r.Type,
-			IsRegex: r.IsRegex,
-			Match:   r.Match,
-			Replace: r.Replace,
-		})
+		result = append(result, r.entry())
 	}
 	return result, nil
 }
@@ -263,6 +340,10 @@ func (b *NativeProxyBackend) AddRule(ctx context.Context, input ProxyRuleInput)
 		return nil, fmt.Errorf("invalid rule type: %q", input.Type)
 	}
 
+	if err := validateRuleAction(input); err != nil {
+		return nil, err
+	}
+
 	isRegex := input.IsRegex != nil && *input.IsRegex
 
 	var compiled *regexp.Regexp
@@ -291,8 +372,14 @@ func (b *NativeProxyBackend) AddRule(ctx context.Context, input ProxyRuleInput)
 		IsRegex:  isRegex,
 		Match:    input.Match,
 		Replace:  input.Replace,
+		Host:     input.Host,
+		Path:     input.Path,
+		Method:   strings.ToUpper(input.Method),
 		compiled: compiled,
 	}
+	if err := rule.compileScope(); err != nil {
+		return nil, err
+	}
 
 	// Save to storage (source of truth), then update cache
 	target := &b.httpRules
@@ -307,14 +394,8 @@ func (b *NativeProxyBackend) AddRule(ctx context.Context, input ProxyRuleInput)
 	}
 	*target = updated
 
-	return &protocol.RuleEntry{
-		RuleID:  rule.ID,
-		Label:   rule.Label,
-		Type:    rule.Type,
-		IsRegex: rule.IsRegex,
-		Match:   rule.Match,
-		Replace: rule.Replace,
-	}, nil
+	entry := rule.entry()
+	return &entry, nil
 }
 
 func (b *NativeProxyBackend) DeleteRule(ctx context.Context, idOrLabel string) error {
@@ -435,6 +516,32 @@ func (b *NativeProxyBackend) ApplyWSRules(payload []byte, direction string) []by
 	return payload
 }
 
+// MatchRequestAction returns the first block or redirect rule in scope of the request.
+func (b *NativeProxyBackend) MatchRequestAction(host string, req *proxy.RawHTTP1Request) *proxy.RequestAction {
+	b.rulesMu.RLock()
+	defer b.rulesMu.RUnlock()
+
+	for _, rule := range b.httpRules {
+		if rule.Type != RuleTypeBlock && rule.Type != RuleTypeRedirect {
+			continue
+		} else if rule.matchesRequest(host, req) {
+			log.Printf("proxy: %s rule %s matched %s %s%s", rule.Type, rule.ID, req.Method, host, req.Path)
+			return &proxy.RequestAction{RuleID: rule.ID, Location: rule.Replace}
+		}
+	}
+	return nil
+}
+
+// HasRequestActions returns true if there are block or redirect rules.
+func (b *NativeProxyBackend) HasRequestActions() bool {
+	b.rulesMu.RLock()
+	defer b.rulesMu.RUnlock()
+
+	return slices.ContainsFunc(b.httpRules, func(r nativeStoredRule) bool {
+		return r.Type == RuleTypeBlock || r.Type == RuleTypeRedirect
+	})
+}
+
 // HasBodyRules returns true if there are body rules for request or response.
 // Used by HTTP/2 handler to decide whether to buffer full bodies.
 func (b *NativeProxyBackend) HasBodyRules(isRequest bool) bool {
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"regexp"
 	"strconv"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -316,6 +317,106 @@ func TestNativeProxyBackend_Rules_Regex(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid regex")
 }
 
+func TestNativeProxyBackend_Rules_RequestActions(t *testing.T) {
+	t.Parallel()
+
+	var upstreamHits atomic.Int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamHits.Add(1)
+		w.Header().Set("X-Seen", r.Header.Get("X-Added"))
+		_, _ = w.Write([]byte("OK"))
+	}))
+	t.Cleanup(testServer.Close)
+
+	backend, err := NewNativeProxyBackend(0, t.TempDir(), 10*1024*1024, store.NewMemStorage(), store.NewMemStorage(), proxy.TimeoutConfig{})
+	require.NoError(t, err)
+	go func() { _ = backend.Serve() }()
+	t.Cleanup(func() { _ = backend.Close() })
+
+	block, err := backend.AddRule(t.Context(), ProxyRuleInput{Type: RuleTypeBlock, Path: "/blocked/*"})
+	require.NoError(t, err)
+	_, err = backend.AddRule(t.Context(), ProxyRuleInput{
+		Type:    RuleTypeRedirect,
+		Replace: "https://example.com/new",
+		Host:    "127.0.0.*",
+		Path:    "/old",
+		Method:  "get",
+	})
+	require.NoError(t, err)
+	_, err = backend.AddRule(t.Context(), ProxyRuleInput{Type: RuleTypeRequestHeader, Replace: "X-Added: yes"})
+	require.NoError(t, err)
+	assert.True(t, backend.HasRequestActions())
+
+	proxyURL, _ := url.Parse("http://" + backend.Addr())
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL), DisableKeepAlives: true},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	send := func(method, path string) *http.Response {
+		req, err := http.NewRequestWithContext(t.Context(), method, testServer.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	t.Run("block", func(t *testing.T) {
+		resp := send("GET", "/blocked/page")
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Contains(t, resp.Status, block.RuleID)
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		resp := send("GET", "/old")
+		assert.Equal(t, 302, resp.StatusCode)
+		assert.Equal(t, "https://example.com/new", resp.Header.Get("Location"))
+	})
+
+	t.Run("out_of_scope", func(t *testing.T) {
+		resp := send("POST", "/old") // redirect is GET only
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "yes", resp.Header.Get("X-Seen"))
+	})
+
+	testutil.WaitForCount(t, func() int { return backend.server.History().Count() }, 1)
+	entries, err := backend.GetProxyHistory(t.Context(), 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Contains(t, entries[0].Request, "POST /old")
+	assert.Equal(t, int32(1), upstreamHits.Load())
+}
+
+func TestNativeProxyBackend_Rules_RequestActionValidation(t *testing.T) {
+	t.Parallel()
+
+	backend, err := NewNativeProxyBackend(0, t.TempDir(), 10*1024*1024, store.NewMemStorage(), store.NewMemStorage(), proxy.TimeoutConfig{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	tests := []struct {
+		name  string
+		input ProxyRuleInput
+		err   string
+	}{
+		{name: "block_with_match", input: ProxyRuleInput{Type: RuleTypeBlock, Match: "x"}, err: "match is not used"},
+		{name: "block_with_replace", input: ProxyRuleInput{Type: RuleTypeBlock, Replace: "x"}, err: "replace is not used"},
+		{name: "redirect_relative", input: ProxyRuleInput{Type: RuleTypeRedirect, Replace: "/new"}, err: "absolute http(s) URL"},
+		{name: "redirect_other_scheme", input: ProxyRuleInput{Type: RuleTypeRedirect, Replace: "ftp://example.com/"}, err: "absolute http(s) URL"},
+		{name: "scope_on_header_rule", input: ProxyRuleInput{Type: RuleTypeRequestHeader, Replace: "X: y", Host: "example.com"}, err: "only scope block and redirect"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := backend.AddRule(t.Context(), tt.input)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+	assert.False(t, backend.HasRequestActions())
+}
+
 func TestNativeProxyBackend_SendRequest(t *testing.T) {
 	t.Parallel()
 
@@ -358,6 +459,50 @@ func TestNativeProxyBackend_SendRequest(t *testing.T) {
 	assert.True(t, bytes.HasSuffix(result.Headers, []byte("\r\n\r\n")))
 }
 
+func TestNativeProxyBackend_SendRequestFollowRedirects(t *testing.T) {
+	t.Parallel()
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start":
+			http.Redirect(w, r, "/middle", http.StatusFound)
+		case "/middle":
+			http.Redirect(w, r, "/final", http.StatusMovedPermanently)
+		default:
+			_, _ = w.Write([]byte("final"))
+		}
+	}))
+	t.Cleanup(testServer.Close)
+
+	serverURL, err := url.Parse(testServer.URL)
+	require.NoError(t, err)
+
+	backend, err := NewNativeProxyBackend(0, t.TempDir(), 10*1024*1024, store.NewMemStorage(), store.NewMemStorage(), proxy.TimeoutConfig{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	rawReq := []byte("GET /start HTTP/1.1\r\nHost: " + serverURL.Host + "\r\n\r\n")
+	result, err := backend.SendRequest(t.Context(), "test", SendRequestInput{
+		RawRequest: rawReq,
+		Target: Target{
+			Hostname:  serverURL.Hostname(),
+			Port:      mustParsePort(t, serverURL.Port()),
+			UsesHTTPS: false,
+		},
+		FollowRedirects: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "final", string(result.Body))
+	require.Len(t, result.Redirects, 2)
+	assert.Equal(t, http.StatusFound, result.Redirects[0].Status)
+	assert.Equal(t, testServer.URL+"/start", result.Redirects[0].URL)
+	assert.Equal(t, testServer.URL+"/middle", result.Redirects[0].Location)
+	assert.Equal(t, http.StatusMovedPermanently, result.Redirects[1].Status)
+	assert.Equal(t, testServer.URL+"/middle", result.Redirects[1].URL)
+	assert.Equal(t, testServer.URL+"/final", result.Redirects[1].Location)
+}
+
 func TestNativeProxyBackend_Close(t *testing.T) {
 	t.Parallel()
 
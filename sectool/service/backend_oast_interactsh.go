@@ -24,12 +24,13 @@ const (
 
 // InteractshBackend implements OastBackend using Interactsh.
 type InteractshBackend struct {
-	serverURL string // custom server URL, empty = use defaults
-	mu        sync.RWMutex
-	sessions  map[string]*oastSession // by domain (canonical key)
-	byID      map[string]string       // short ID -> domain
-	byLabel   map[string]string       // label -> domain (only non-empty labels)
-	closed    bool
+	serverURL    string        // custom server URL, empty = use defaults
+	pollInterval time.Duration // how often each session polls the server
+	mu           sync.RWMutex
+	sessions     map[string]*oastSession // by domain (canonical key)
+	byID         map[string]string       // short ID -> domain
+	byLabel      map[string]string       // label -> domain (only non-empty labels)
+	closed       bool
 }
 
 // Compile-time check that InteractshBackend implements OastBackend
@@ -37,8 +38,9 @@ var _ OastBackend = (*InteractshBackend)(nil)
 
 // oastSession holds the state for a single OAST session.
 type oastSession struct {
-	info   OastSessionInfo
-	client *oobclient.Client
+	info    OastSessionInfo
+	client  *oobclient.Client
+	counter interactionCounter // interactions returned by the server vs. delivered
 
 	mu           sync.Mutex
 	notify       chan struct{} // closed when new events arrive, then replaced
@@ -53,10 +55,11 @@ type oastSession struct {
 // NewInteractshBackend creates a new Interactsh-backed OastBackend.
 func NewInteractshBackend(serverURL string) *InteractshBackend {
 	return &InteractshBackend{
-		serverURL: serverURL,
-		sessions:  make(map[string]*oastSession),
-		byID:      make(map[string]string),
-		byLabel:   make(map[string]string),
+		serverURL:    serverURL,
+		pollInterval: interactshPollInterval,
+		sessions:     make(map[string]*oastSession),
+		byID:         make(map[string]string),
+		byLabel:      make(map[string]string),
 	}
 }
 
@@ -77,7 +80,12 @@ func (b *InteractshBackend) CreateSession(ctx context.Context, label string) (*O
 	}
 	b.mu.Unlock()
 
-	var opts oobclient.Options
+	sess := &oastSession{
+		notify:      make(chan struct{}),
+		stopPolling: make(chan struct{}),
+	}
+
+	opts := oobclient.Options{HTTPClient: newInteractshHTTPClient(&sess.counter)}
 	if b.serverURL != "" {
 		opts.ServerURLs = []string{b.serverURL}
 	}
@@ -89,16 +97,12 @@ func (b *InteractshBackend) CreateSession(ctx context.Context, label string) (*O
 	sessionID := ids.Generate(ids.DefaultLength)
 	domain := c.URL()
 
-	sess := &oastSession{
-		info: OastSessionInfo{
-			ID:        sessionID,
-			Domain:    domain,
-			Label:     label,
-			CreatedAt: time.Now(),
-		},
-		client:      c,
-		notify:      make(chan struct{}),
-		stopPolling: make(chan struct{}),
+	sess.client = c
+	sess.info = OastSessionInfo{
+		ID:        sessionID,
+		Domain:    domain,
+		Label:     label,
+		CreatedAt: time.Now(),
 	}
 
 	b.mu.Lock()
@@ -142,6 +146,8 @@ func (b *InteractshBackend) CreateSession(ctx context.Context, label string) (*O
 // pollLoop runs background polling for a session.
 func (b *InteractshBackend) pollLoop(sess *oastSession) {
 	callback := func(interaction *oobclient.Interaction) {
+		sess.counter.delivered.Add(1)
+
 		sess.mu.Lock()
 		defer sess.mu.Unlock()
 
@@ -156,20 +162,6 @@ func (b *InteractshBackend) pollLoop(sess *oastSession) {
 			eventTime = time.Now()
 		}
 
-		details := make(map[string]interface{}, 4)
-		if interaction.RawRequest != "" {
-			details["raw_request"] = interaction.RawRequest
-		}
-		if interaction.RawResponse != "" {
-			details["raw_response"] = interaction.RawResponse
-		}
-		if interaction.QType != "" {
-			details["query_type"] = interaction.QType
-		}
-		if interaction.SMTPFrom != "" {
-			details["smtp_from"] = interaction.SMTPFrom
-		}
-
 		if len(sess.events) >= MaxOastEventsPerSession {
 			sess.events = sess.events[1:]
 			sess.droppedCount++
@@ -183,7 +175,7 @@ func (b *InteractshBackend) pollLoop(sess *oastSession) {
 			Type:      strings.ToLower(interaction.Protocol),
 			SourceIP:  interaction.RemoteAddress,
 			Subdomain: interaction.FullId,
-			Details:   details,
+			Details:   interactionDetails(interaction),
 		}
 		sess.events = append(sess.events, event)
 
@@ -196,7 +188,7 @@ func (b *InteractshBackend) pollLoop(sess *oastSession) {
 
 	sess.mu.Lock()
 	if !sess.stopped {
-		if err := sess.client.StartPolling(interactshPollInterval, callback); err != nil {
+		if err := sess.client.StartPolling(b.pollInterval, callback); err != nil {
 			log.Printf("oast: polling error for session %s: %v", sess.info.ID, err)
 		}
 	}
@@ -205,6 +197,55 @@ func (b *InteractshBackend) pollLoop(sess *oastSession) {
 	<-sess.stopPolling
 }
 
+// interactionDetails returns the protocol-specific details stored for an interaction,
+// keeping the raw request and response untruncated.
+func interactionDetails(interaction *oobclient.Interaction) map[string]interface{} {
+	details := make(map[string]interface{}, 6)
+	if interaction.RawRequest != "" {
+		details["raw_request"] = interaction.RawRequest
+	}
+	if interaction.RawResponse != "" {
+		details["raw_response"] = interaction.RawResponse
+	}
+	if interaction.QType != "" {
+		details["query_type"] = interaction.QType
+	}
+	if interaction.SMTPFrom != "" {
+		details["smtp_from"] = interaction.SMTPFrom
+	}
+	if strings.EqualFold(interaction.Protocol, "dns") {
+		if name := dnsQueryName(interaction.RawRequest); name != "" {
+			details["query_name"] = name
+		} else if interaction.FullId != "" {
+			details["query_name"] = interaction.FullId
+		}
+	}
+	return details
+}
+
+// dnsQueryName extracts the first question name from a DNS message dump as captured by
+// the interactsh server (";; QUESTION SECTION:" followed by ";name.\tIN\tTYPE").
+func dnsQueryName(raw string) string {
+	_, question, ok := strings.Cut(raw, ";; QUESTION SECTION:")
+	if !ok {
+		return ""
+	}
+	for _, line := range strings.Split(question, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, ";") || strings.HasPrefix(line, ";;") {
+			return ""
+		}
+		if fields := strings.Fields(strings.TrimPrefix(line, ";")); len(fields) > 0 {
+			return strings.TrimSuffix(fields[0], ".")
+		}
+		return ""
+	}
+	return ""
+}
+
 func (b *InteractshBackend) PollSession(ctx context.Context, idOrDomain string, since string, eventType string, wait time.Duration, limit int) (*OastPollResultInfo, error) {
 	sess, err := b.resolveSession(idOrDomain)
 	if err != nil {
@@ -229,6 +270,7 @@ func (b *InteractshBackend) PollSession(ctx context.Context, idOrDomain string,
 			result := &OastPollResultInfo{
 				Events:       events,
 				DroppedCount: sess.droppedCount,
+				FailedCount:  int(sess.counter.failed.Load()),
 			}
 			sess.mu.Unlock()
 			return result, nil
@@ -332,7 +374,7 @@ func (b *InteractshBackend) GetEvent(ctx context.Context, idOrDomain string, eve
 		}
 	}
 
-	return nil, fmt.Errorf("%w: event %s", ErrNotFound, eventID)
+	return nil, fmt.Errorf("%w: %s", ErrEventNotFound, eventID)
 }
 
 func (b *InteractshBackend) ListSessions(ctx context.Context) ([]OastSessionInfo, error) {
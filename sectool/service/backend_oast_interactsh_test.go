@@ -2,6 +2,19 @@ package service
 
 import (
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -44,6 +57,303 @@ func TestInteractshBackend_CreateAndClose(t *testing.T) {
 	assert.Empty(t, sessions)
 }
 
+func TestInteractshBackend_CreateSession_mockServer(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var registered, deregistered map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/register":
+			registered = body
+			_, _ = w.Write([]byte(`{"message":"registration successful"}`))
+		case "/deregister":
+			deregistered = body
+			_, _ = w.Write([]byte(`{"message":"deregistration successful"}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":[],"extra":[]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	backend := NewInteractshBackend(server.URL)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	sess, err := backend.CreateSession(t.Context(), "mock")
+	require.NoError(t, err)
+	assert.Equal(t, "mock", sess.Label)
+
+	mu.Lock()
+	reg := registered
+	mu.Unlock()
+	require.NotNil(t, reg)
+
+	// Domain is <correlation-id><nonce>.<server host>, so tagged subdomains correlate
+	host := strings.TrimPrefix(server.URL, "http://")
+	correlationID := reg["correlation-id"]
+	require.NotEmpty(t, correlationID)
+	assert.True(t, strings.HasPrefix(sess.Domain, correlationID), sess.Domain)
+	assert.True(t, strings.HasSuffix(sess.Domain, "."+host), sess.Domain)
+	assert.NotEmpty(t, reg["secret-key"])
+
+	// Registered public key is the RSA key interactions are encrypted to
+	pemBytes, err := base64.StdEncoding.DecodeString(reg["public-key"])
+	require.NoError(t, err)
+	block, _ := pem.Decode(pemBytes)
+	require.NotNil(t, block)
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	require.NoError(t, err)
+	assert.IsType(t, &rsa.PublicKey{}, pub)
+
+	require.NoError(t, backend.DeleteSession(t.Context(), "mock"))
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotNil(t, deregistered)
+	assert.Equal(t, correlationID, deregistered["correlation-id"])
+	assert.Equal(t, reg["secret-key"], deregistered["secret-key"])
+}
+
+// encryptInteraction encrypts an interaction the way an interactsh server does: AES-CFB with
+// the IV prefixed to the ciphertext, and the AES key RSA-OAEP encrypted to the client's key.
+func encryptInteraction(pub *rsa.PublicKey, aesKey []byte, interaction string) (string, string, error) {
+	encKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, aesKey, nil)
+	if err != nil {
+		return "", "", err
+	}
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return "", "", err
+	}
+	ciphertext := make([]byte, aes.BlockSize+len(interaction))
+	_, _ = rand.Read(ciphertext[:aes.BlockSize])
+	//nolint:staticcheck // CFB required for interactsh protocol compatibility
+	cipher.NewCFBEncrypter(block, ciphertext[:aes.BlockSize]).XORKeyStream(ciphertext[aes.BlockSize:], []byte(interaction))
+
+	return base64.StdEncoding.EncodeToString(encKey), base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func TestInteractshBackend_PollSession_mockServer(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var pub *rsa.PublicKey
+	var served bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/register":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			pemBytes, _ := base64.StdEncoding.DecodeString(body["public-key"])
+			if block, _ := pem.Decode(pemBytes); block != nil {
+				key, _ := x509.ParsePKIXPublicKey(block.Bytes)
+				pub, _ = key.(*rsa.PublicKey)
+			}
+			_, _ = w.Write([]byte(`{"message":"registration successful"}`))
+		case "/poll":
+			if served || pub == nil {
+				_, _ = w.Write([]byte(`{"data":[],"extra":[]}`))
+				return
+			}
+
+			aesKey := make([]byte, 32)
+			_, _ = rand.Read(aesKey)
+			encKey, httpData, err := encryptInteraction(pub, aesKey,
+				`{"protocol":"http","full-id":"sqli.abc","remote-address":"203.0.113.7","raw-request":"GET / HTTP/1.1"}`)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			_, dnsData, err := encryptInteraction(pub, aesKey,
+				`{"protocol":"dns","full-id":"ssrf.abc","remote-address":"198.51.100.2","q-type":"A"}`)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			served = true
+			resp, _ := json.Marshal(map[string]interface{}{
+				"aes_key": encKey,
+				"data":    []string{httpData, "bm90IGNpcGhlcnRleHQgYXQgYWxsIQ==", dnsData},
+				"extra":   []string{},
+			})
+			_, _ = w.Write(resp)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	backend := NewInteractshBackend(server.URL)
+	backend.pollInterval = 20 * time.Millisecond
+	t.Cleanup(func() { _ = backend.Close() })
+
+	sess, err := backend.CreateSession(t.Context(), "")
+	require.NoError(t, err)
+
+	var events []OastEventInfo
+	require.Eventually(t, func() bool {
+		result, err := backend.PollSession(t.Context(), sess.ID, "", "", time.Second, 0)
+		require.NoError(t, err)
+		events = result.Events
+		return len(events) == 2 && result.FailedCount == 1
+	}, 10*time.Second, 50*time.Millisecond)
+
+	assert.Equal(t, "http", events[0].Type)
+	assert.Equal(t, "203.0.113.7", events[0].SourceIP)
+	assert.Equal(t, "sqli.abc", events[0].Subdomain)
+	assert.Equal(t, "GET / HTTP/1.1", events[0].Details["raw_request"])
+	assert.Equal(t, "dns", events[1].Type)
+	assert.Equal(t, "A", events[1].Details["query_type"])
+
+	// since=last returns nothing new, but the failure stays reported
+	result, err := backend.PollSession(t.Context(), sess.ID, "last", "", 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, result.Events)
+	assert.Equal(t, 1, result.FailedCount)
+}
+
+func TestInteractshBackend_GetEvent_mockServer(t *testing.T) {
+	t.Parallel()
+
+	const dnsRaw = ";; opcode: QUERY, status: NOERROR, id: 4242\n" +
+		";; flags: rd; QUERY: 1, ANSWER: 0, AUTHORITY: 0, ADDITIONAL: 0\n\n" +
+		";; QUESTION SECTION:\n;ssrf.c0ffee.oast.test.\tIN\t A\n"
+	const httpRaw = "POST /callback?x=1 HTTP/1.1\r\nHost: c0ffee.oast.test\r\n" +
+		"Content-Type: application/json\r\nContent-Length: 27\r\n\r\n" +
+		`{"secret":"exfil-data-123"}`
+	interactions := []map[string]string{
+		{"protocol": "dns", "full-id": "ssrf.c0ffee", "remote-address": "198.51.100.2", "q-type": "A", "raw-request": dnsRaw},
+		{"protocol": "http", "full-id": "c0ffee", "remote-address": "203.0.113.7", "raw-request": httpRaw, "raw-response": "HTTP/1.1 200 OK\r\n\r\n"},
+	}
+
+	var mu sync.Mutex
+	var pub *rsa.PublicKey
+	var firstID string
+	var served bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/register":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if firstID == "" {
+				firstID = body["correlation-id"]
+				pemBytes, _ := base64.StdEncoding.DecodeString(body["public-key"])
+				if block, _ := pem.Decode(pemBytes); block != nil {
+					key, _ := x509.ParsePKIXPublicKey(block.Bytes)
+					pub, _ = key.(*rsa.PublicKey)
+				}
+			}
+			_, _ = w.Write([]byte(`{"message":"registration successful"}`))
+		case "/poll":
+			if served || pub == nil || r.URL.Query().Get("id") != firstID {
+				_, _ = w.Write([]byte(`{"data":[],"extra":[]}`))
+				return
+			}
+
+			aesKey := make([]byte, 32)
+			_, _ = rand.Read(aesKey)
+			var encKey string
+			var data []string
+			for _, interaction := range interactions {
+				plain, _ := json.Marshal(interaction)
+				key, ciphertext, err := encryptInteraction(pub, aesKey, string(plain))
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				encKey = key
+				data = append(data, ciphertext)
+			}
+			served = true
+			resp, _ := json.Marshal(map[string]interface{}{"aes_key": encKey, "data": data, "extra": []string{}})
+			_, _ = w.Write(resp)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	backend := NewInteractshBackend(server.URL)
+	backend.pollInterval = 20 * time.Millisecond
+	t.Cleanup(func() { _ = backend.Close() })
+
+	sess, err := backend.CreateSession(t.Context(), "target")
+	require.NoError(t, err)
+	other, err := backend.CreateSession(t.Context(), "other")
+	require.NoError(t, err)
+
+	var events []OastEventInfo
+	require.Eventually(t, func() bool {
+		result, err := backend.PollSession(t.Context(), sess.ID, "", "", time.Second, 0)
+		require.NoError(t, err)
+		events = result.Events
+		return len(events) == 2
+	}, 10*time.Second, 50*time.Millisecond)
+
+	t.Run("dns_a_record", func(t *testing.T) {
+		event, err := backend.GetEvent(t.Context(), sess.ID, events[0].ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, "dns", event.Type)
+		assert.Equal(t, "198.51.100.2", event.SourceIP)
+		assert.Equal(t, "ssrf.c0ffee.oast.test", event.Details["query_name"])
+		assert.Equal(t, "A", event.Details["query_type"])
+		assert.Equal(t, dnsRaw, event.Details["raw_request"])
+		assert.NotContains(t, event.Details, "raw_packet_hex")
+	})
+
+	t.Run("http_post_with_body", func(t *testing.T) {
+		event, err := backend.GetEvent(t.Context(), "target", events[1].ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, "http", event.Type)
+		assert.Equal(t, httpRaw, event.Details["raw_request"])
+		assert.Equal(t, "HTTP/1.1 200 OK\r\n\r\n", event.Details["raw_response"])
+		assert.NotContains(t, event.Details, "query_name")
+	})
+
+	t.Run("unknown_event", func(t *testing.T) {
+		_, err := backend.GetEvent(t.Context(), sess.ID, "nope")
+		assert.ErrorIs(t, err, ErrEventNotFound)
+	})
+
+	t.Run("event_of_other_session", func(t *testing.T) {
+		_, err := backend.GetEvent(t.Context(), other.ID, events[0].ID)
+		assert.ErrorIs(t, err, ErrEventNotFound)
+	})
+}
+
+func TestDNSQueryName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "question_section",
+			raw:  ";; opcode: QUERY\n\n;; QUESTION SECTION:\n;Abc.Oast.Test.\tIN\t AAAA\n",
+			want: "Abc.Oast.Test",
+		},
+		{name: "no_question", raw: ";; opcode: QUERY\n", want: ""},
+		{name: "empty_question", raw: ";; QUESTION SECTION:\n\n;; ANSWER SECTION:\n", want: ""},
+		{name: "empty", raw: "", want: ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, dnsQueryName(tc.raw))
+		})
+	}
+}
+
 func TestInteractshBackend_PollSession(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+)
+
+// findingBlocked and findingRateLimited label flows whose responses look like a WAF
+// block page (403, or a 503 CAPTCHA challenge) or a rate limit (429).
+const (
+	findingBlocked     = "blocked"
+	findingRateLimited = "rate-limited"
+)
+
+// captchaRe matches common CAPTCHA and bot-challenge page markers.
+var captchaRe = regexp.MustCompile(`(?i)captcha|cf-challenge|challenge-platform|cf-chl-|_incapsula_|px-captcha|are you a (?:human|robot)`)
+
+// blockKind classifies a response as findingRateLimited, findingBlocked, or "" when it
+// does not look like the target pushing back. CAPTCHA markers only count on a 503, the
+// status challenge pages use; ordinary pages embed reCAPTCHA/hCaptcha in their forms.
+func blockKind(status int, body []byte) string {
+	if status == http.StatusTooManyRequests {
+		return findingRateLimited
+	} else if status == http.StatusForbidden || (status == http.StatusServiceUnavailable && captchaRe.Match(body)) {
+		return findingBlocked
+	}
+	return ""
+}
+
+// blockStreak counts consecutive responses from one host sharing a block signature.
+type blockStreak struct {
+	signature [sha256.Size]byte
+	count     int
+}
+
+// observeBlock records a response for host and reports whether CrawlOptions.BlockThreshold
+// consecutive identical block responses have now been seen. Rate limits match on status
+// alone; block pages must also share a body. Callers hold sess.mu.
+func (sess *crawlSession) observeBlock(host, kind string, status int, body []byte) bool {
+	if kind == "" {
+		delete(sess.blockStreaks, host)
+		return false
+	}
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s %d\n", kind, status)
+	if kind == findingBlocked {
+		_, _ = h.Write(body)
+	}
+	var signature [sha256.Size]byte
+	h.Sum(signature[:0])
+
+	streak := sess.blockStreaks[host]
+	if streak.signature != signature {
+		streak = blockStreak{signature: signature}
+	}
+	streak.count++
+	sess.blockStreaks[host] = streak
+	return streak.count >= sess.opts.BlockThreshold
+}
+
+// haltBlocked stops a running session after block detection, recording why in the status.
+func (sess *crawlSession) haltBlocked(host, kind string, status int) {
+	note := fmt.Sprintf("%s: %d consecutive identical %d responses from %s; crawl stopped, lower parallelism or raise delay before crawling again",
+		kind, sess.opts.BlockThreshold, status, host)
+
+	sess.mu.Lock()
+	if sess.info.State != crawlStateRunning {
+		sess.mu.Unlock()
+		return
+	}
+	sess.info.State = crawlStateStopped
+	sess.blockedNote = note
+	sess.notifyFlows()
+	sess.mu.Unlock()
+
+	sess.cancel()
+	log.Printf("crawler: session %s %s", sess.info.ID, note)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestBlockKind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"ok", http.StatusOK, "<html>welcome</html>", ""},
+		{"not_found", http.StatusNotFound, "not found", ""},
+		{"too_many_requests", http.StatusTooManyRequests, "slow down", findingRateLimited},
+		{"forbidden", http.StatusForbidden, "Forbidden", findingBlocked},
+		{"captcha_form_on_ok_page", http.StatusOK, `<form><div class="g-recaptcha"></div></form>`, ""},
+		{"captcha_challenge", http.StatusServiceUnavailable, `<div class="g-recaptcha"></div>`, findingBlocked},
+		{"unavailable_without_captcha", http.StatusServiceUnavailable, "maintenance", ""},
+		{"cloudflare_challenge", http.StatusServiceUnavailable, `<script src="/cdn-cgi/challenge-platform/x.js">`, findingBlocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, blockKind(tt.status, []byte(tt.body)))
+		})
+	}
+}
+
+func TestCrawlSession_ObserveBlock(t *testing.T) {
+	t.Parallel()
+
+	newSession := func() *crawlSession {
+		return &crawlSession{opts: CrawlOptions{BlockThreshold: 3}, blockStreaks: make(map[string]blockStreak)}
+	}
+
+	t.Run("identical_block_pages_trip", func(t *testing.T) {
+		sess := newSession()
+		assert.False(t, sess.observeBlock("a.test", findingBlocked, 403, []byte("denied")))
+		assert.False(t, sess.observeBlock("a.test", findingBlocked, 403, []byte("denied")))
+		assert.True(t, sess.observeBlock("a.test", findingBlocked, 403, []byte("denied")))
+	})
+
+	t.Run("different_bodies_restart", func(t *testing.T) {
+		sess := newSession()
+		assert.False(t, sess.observeBlock("a.test", findingBlocked, 403, []byte("one")))
+		assert.False(t, sess.observeBlock("a.test", findingBlocked, 403, []byte("two")))
+		assert.False(t, sess.observeBlock("a.test", findingBlocked, 403, []byte("two")))
+	})
+
+	t.Run("rate_limit_ignores_body", func(t *testing.T) {
+		sess := newSession()
+		assert.False(t, sess.observeBlock("a.test", findingRateLimited, 429, []byte("retry in 1s")))
+		assert.False(t, sess.observeBlock("a.test", findingRateLimited, 429, []byte("retry in 2s")))
+		assert.True(t, sess.observeBlock("a.test", findingRateLimited, 429, []byte("retry in 3s")))
+	})
+
+	t.Run("normal_response_resets", func(t *testing.T) {
+		sess := newSession()
+		assert.False(t, sess.observeBlock("a.test", findingRateLimited, 429, nil))
+		assert.False(t, sess.observeBlock("a.test", findingRateLimited, 429, nil))
+		assert.False(t, sess.observeBlock("a.test", "", 200, nil))
+		assert.False(t, sess.observeBlock("a.test", findingRateLimited, 429, nil))
+	})
+
+	t.Run("hosts_tracked_separately", func(t *testing.T) {
+		sess := newSession()
+		assert.False(t, sess.observeBlock("a.test", findingRateLimited, 429, nil))
+		assert.False(t, sess.observeBlock("b.test", findingRateLimited, 429, nil))
+		assert.False(t, sess.observeBlock("a.test", findingRateLimited, 429, nil))
+		assert.False(t, sess.observeBlock("b.test", findingRateLimited, 429, nil))
+	})
+}
+
+func TestCollyBackend_BlockThreshold(t *testing.T) {
+	t.Parallel()
+
+	const pages = 20
+	var links strings.Builder
+	for i := range pages {
+		_, _ = fmt.Fprintf(&links, `<a href="/page%d">p</a>`, i)
+	}
+	newServer := func(blockStatus int) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			if r.URL.Path == "/" {
+				_, _ = w.Write([]byte(links.String()))
+				return
+			}
+			w.WriteHeader(blockStatus)
+			_, _ = w.Write([]byte("<html>Access denied</html>"))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	waitDone := func(t *testing.T, sessionID string) *CrawlStatus {
+		t.Helper()
+		var status *CrawlStatus
+		require.Eventually(t, func() bool {
+			var err error
+			status, err = b.GetStatus(t.Context(), sessionID)
+			return err == nil && status.State != crawlStateRunning
+		}, 10*time.Second, 10*time.Millisecond)
+		return status
+	}
+
+	t.Run("rate_limited_stops", func(t *testing.T) {
+		server := newServer(http.StatusTooManyRequests)
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:           time.Millisecond,
+			Parallelism:     1,
+			IgnoreRobotsTxt: true,
+			BlockThreshold:  3,
+		})
+		require.NoError(t, err)
+
+		status := waitDone(t, sess.ID)
+		assert.Equal(t, crawlStateStopped, status.State)
+		assert.Contains(t, status.BlockedNote, findingRateLimited)
+		assert.Less(t, status.URLsErrored, pages)
+	})
+
+	t.Run("blocked_flows_labeled", func(t *testing.T) {
+		server := newServer(http.StatusForbidden)
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:              []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:              time.Millisecond,
+			Parallelism:        1,
+			IgnoreRobotsTxt:    true,
+			FollowLinksOnError: true,
+			BlockThreshold:     3,
+		})
+		require.NoError(t, err)
+
+		status := waitDone(t, sess.ID)
+		assert.Equal(t, crawlStateStopped, status.State)
+		assert.Contains(t, status.BlockedNote, findingBlocked)
+
+		flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{StatusCodes: parseStatusFilter("403")})
+		require.NoError(t, err)
+		require.NotEmpty(t, flows)
+		for _, f := range flows {
+			assert.Equal(t, []string{findingBlocked}, f.Findings)
+		}
+	})
+
+	t.Run("disabled_by_default", func(t *testing.T) {
+		server := newServer(http.StatusTooManyRequests)
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+		})
+		require.NoError(t, err)
+
+		status := waitDone(t, sess.ID)
+		assert.Equal(t, crawlStateCompleted, status.State)
+		assert.Empty(t, status.BlockedNote)
+		assert.Equal(t, pages, status.URLsErrored)
+	})
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+)
+
+// findingHTMLComment marks a page with HTML comments that reference URLs or look sensitive.
+const findingHTMLComment = "html-comment"
+
+var (
+	htmlCommentRe = regexp.MustCompile(`(?s)<!--(.*?)-->`)
+	// absolute http(s) URLs, or root-relative paths delimited by whitespace, quotes, '=' or '('
+	commentURLRe  = regexp.MustCompile(`(?i)https?://[^\s"'<>()]+`)
+	commentPathRe = regexp.MustCompile(`(?:^|[\s"'=(])(/[A-Za-z0-9_\-.~%/]*[A-Za-z0-9_\-~%/](?:\?[^\s"'<>()]*)?)`)
+	// credentials, developer notes, private addresses and internal host names
+	commentSensitiveRe = regexp.MustCompile(`(?i)\b(?:passw(?:or)?d|pwd|secret|api[_-]?key|token|credential|todo|fixme|hack)s?\b|` +
+		`\b(?:10\.\d{1,3}|127\.\d{1,3}|192\.168|172\.(?:1[6-9]|2\d|3[01]))\.\d{1,3}\.\d{1,3}\b|` +
+		`\b[a-z0-9-]+(?:\.[a-z0-9-]+)*\.(?:internal|local|corp|lan|intranet)\b`)
+)
+
+// extractHTMLComments scans the comments of an HTML document for absolute URLs and
+// root-relative paths. It also returns the full text of each comment that referenced
+// a URL or looks sensitive, so it can be recorded as a finding.
+func extractHTMLComments(body []byte) (urls []string, notable []string) {
+	for _, m := range htmlCommentRe.FindAllSubmatch(body, -1) {
+		comment := string(m[1])
+		var found bool
+		for _, u := range commentURLRe.FindAllString(comment, -1) {
+			urls = append(urls, strings.TrimRight(u, ".,;:!"))
+			found = true
+		}
+		withoutURLs := commentURLRe.ReplaceAllString(comment, " ")
+		for _, pm := range commentPathRe.FindAllStringSubmatch(withoutURLs, -1) {
+			if strings.HasPrefix(pm[1], "//") || strings.Trim(pm[1], "/") == "" {
+				continue
+			}
+			urls = append(urls, pm[1])
+			found = true
+		}
+		if text := strings.TrimSpace(comment); text != "" && (found || commentSensitiveRe.MatchString(text)) {
+			notable = append(notable, text)
+		}
+	}
+	return urls, notable
+}
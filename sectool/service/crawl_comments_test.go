@@ -0,0 +1,124 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestExtractHTMLComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		body        string
+		wantURLs    []string
+		wantNotable []string
+	}{
+		{
+			name:        "absolute_url",
+			body:        `<p>x</p><!-- old API: https://api.internal.test/v1/users. -->`,
+			wantURLs:    []string{"https://api.internal.test/v1/users"},
+			wantNotable: []string{"old API: https://api.internal.test/v1/users."},
+		},
+		{
+			name:        "paths",
+			body:        "<!-- TODO remove /debug/vars and href=\"/admin/panel?x=1\" -->",
+			wantURLs:    []string{"/debug/vars", "/admin/panel?x=1"},
+			wantNotable: []string{`TODO remove /debug/vars and href="/admin/panel?x=1"`},
+		},
+		{
+			name:        "multiline",
+			body:        "<!--\n  <a href=\"/beta\">beta</a>\n-->",
+			wantURLs:    []string{"/beta"},
+			wantNotable: []string{`<a href="/beta">beta</a>`},
+		},
+		{
+			name:        "sensitive_without_url",
+			body:        `<!-- db password: hunter2 --><!-- build 42 --><!-- host db01.corp -->`,
+			wantNotable: []string{"db password: hunter2", "host db01.corp"},
+		},
+		{
+			name:        "private_ip",
+			body:        `<!-- upstream 10.1.2.3:8080 -->`,
+			wantNotable: []string{"upstream 10.1.2.3:8080"},
+		},
+		{
+			name: "ignores_plain_and_protocol_relative",
+			body: `<!-- end of header --><!-- a/b and //cdn.test/x and / -->`,
+		},
+		{
+			name: "outside_comment",
+			body: `<p>/not/comment https://a.test/</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls, notable := extractHTMLComments([]byte(tt.body))
+			assert.Equal(t, tt.wantURLs, urls)
+			assert.Equal(t, tt.wantNotable, notable)
+		})
+	}
+}
+
+func TestCollyBackend_ExtractCommentURLs(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`<html><body><!-- TODO: remove /legacy/api before launch --><p>hi</p></body></html>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	runCrawl := func(t *testing.T, extract bool) map[string]CrawlFlow {
+		t.Helper()
+
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:              []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:              time.Millisecond,
+			IgnoreRobotsTxt:    true,
+			ExtractCommentURLs: extract,
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+
+		flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+		require.NoError(t, err)
+		byPath := make(map[string]CrawlFlow)
+		for _, f := range flows {
+			byPath[f.Path] = f
+		}
+		return byPath
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		flows := runCrawl(t, true)
+		require.Len(t, flows, 2)
+		assert.Equal(t, server.URL+"/", flows["/legacy/api"].FoundOn)
+		assert.Contains(t, flows["/"].Findings, findingHTMLComment)
+		assert.Equal(t, []string{"TODO: remove /legacy/api before launch"}, flows["/"].HTMLComments)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		flows := runCrawl(t, false)
+		require.Len(t, flows, 1)
+		assert.Empty(t, flows["/"].Findings)
+		assert.Empty(t, flows["/"].HTMLComments)
+	})
+}
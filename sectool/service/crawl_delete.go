@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/go-analyze/bulk"
+)
+
+func (b *CollyBackend) DeleteFlow(ctx context.Context, flowID string) error {
+	b.mu.RLock()
+	sessions := bulk.MapValuesSlice(b.sessions)
+	b.mu.RUnlock()
+
+	for _, sess := range sessions {
+		sess.mu.Lock()
+		_, ok := sess.flowsByID[flowID]
+		if ok {
+			sess.removeFlows(func(flow *CrawlFlow) bool { return flow.ID == flowID })
+		}
+		sess.mu.Unlock()
+		if ok {
+			sess.flowLog.persistDeletedFlows(sess.info.ID, []string{flowID})
+			log.Printf("crawler: deleted flow %s from session %s", flowID, sess.info.ID)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: flow %s", ErrNotFound, flowID)
+}
+
+func (b *CollyBackend) DeleteFlows(ctx context.Context, sessionID string, opts CrawlListOptions) (int, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	hasSearch := opts.SearchHeaderRe != nil || opts.SearchBodyRe != nil
+	var removedIDs []string
+	sess.mu.Lock()
+	removed := sess.removeFlows(func(flow *CrawlFlow) bool {
+		matched := matchesFlowFilters(flow, opts) &&
+			(!hasSearch || matchesFlowSearch(flow.Request, flow.Response, opts.SearchHeaderRe, opts.SearchBodyRe))
+		if matched != opts.Invert {
+			removedIDs = append(removedIDs, flow.ID)
+			return true
+		}
+		return false
+	})
+	sess.mu.Unlock()
+	sess.flowLog.persistDeletedFlows(sess.info.ID, removedIDs)
+
+	log.Printf("crawler: deleted %d flows from session %s", removed, sess.info.ID)
+	return removed, nil
+}
+
+func (b *CollyBackend) DeleteSession(ctx context.Context, sessionID string, force bool) (int, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Hold b.mu like ResumeSession so a resume cannot restart the session mid-delete
+	b.mu.Lock()
+	sess.mu.Lock()
+	if sess.info.State == crawlStateRunning && !force {
+		sess.mu.Unlock()
+		b.mu.Unlock()
+		return 0, fmt.Errorf("session %s is running (stop it first or force the delete)", sessionID)
+	}
+	sess.info.State = crawlStateStopped
+	sess.deleted = true // a run still draining must not persist the session again
+	sess.notifyFlows()
+	flows, cancel := len(sess.flowsOrdered), sess.cancel
+	sess.mu.Unlock()
+	delete(b.sessions, sess.info.ID)
+	if b.byLabel[sess.info.Label] == sess.info.ID {
+		delete(b.byLabel, sess.info.Label)
+	}
+	b.mu.Unlock()
+
+	cancel()
+	b.flowLog.persistDeletedSession(sess.info.ID)
+	log.Printf("crawler: deleted session %s with %d flows", sess.info.ID, flows)
+	return flows, nil
+}
+
+// sweepSessions deletes sessions that ended and have been idle longer than ttl, checking
+// every interval until Close. A non-positive ttl keeps sessions forever.
+func (b *CollyBackend) sweepSessions(ttl, interval time.Duration) {
+	defer close(b.sweepDone)
+	if ttl <= 0 || interval <= 0 {
+		<-b.sweepStop
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.sweepStop:
+			return
+		case now := <-ticker.C:
+			b.expireSessions(ttl, now)
+		}
+	}
+}
+
+// expireSessions deletes completed or stopped sessions whose last activity is more than
+// ttl before now, returning their IDs.
+func (b *CollyBackend) expireSessions(ttl time.Duration, now time.Time) []string {
+	var expired []*crawlSession
+	b.mu.Lock()
+	for id, sess := range b.sessions {
+		sess.mu.Lock()
+		ended := sess.info.State == crawlStateCompleted || sess.info.State == crawlStateStopped
+		expire := ended && now.Sub(sess.lastActivity) > ttl
+		if expire {
+			sess.deleted = true
+		}
+		sess.mu.Unlock()
+		if expire {
+			expired = append(expired, sess)
+			delete(b.sessions, id)
+			if b.byLabel[sess.info.Label] == id {
+				delete(b.byLabel, sess.info.Label)
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	ids := make([]string, 0, len(expired))
+	for _, sess := range expired {
+		sess.cancel()
+		b.flowLog.persistDeletedSession(sess.info.ID)
+		ids = append(ids, sess.info.ID)
+		log.Printf("crawler: expired session %s after %s idle", sess.info.ID, ttl)
+	}
+	return ids
+}
+
+// removeFlows deletes the flows for which remove returns true, shifting the since=last
+// cursors and search index positions to match the remaining flows. Caller must hold sess.mu.
+func (sess *crawlSession) removeFlows(remove func(*CrawlFlow) bool) int {
+	var removed []int // ascending positions in flowsOrdered
+	kept := sess.flowsOrdered[:0]
+	for i, flow := range sess.flowsOrdered {
+		if remove(flow) {
+			removed = append(removed, i)
+			delete(sess.flowsByID, flow.ID)
+		} else {
+			kept = append(kept, flow)
+		}
+	}
+	if len(removed) == 0 {
+		return 0
+	}
+	clear(sess.flowsOrdered[len(kept):])
+	sess.flowsOrdered = kept
+
+	sess.lastReturnedIdx = shiftCursor(removed, sess.lastReturnedIdx)
+	for name, idx := range sess.namedCursors {
+		sess.namedCursors[name] = shiftCursor(removed, idx)
+	}
+	if sess.searchIndex != nil {
+		sess.searchIndex.remove(removed)
+	}
+	return len(removed)
+}
+
+// shiftCursor moves a cursor, the position of the next unread flow, back past the removed
+// positions (ascending) before it, which no longer count.
+func shiftCursor(removed []int, pos int) int {
+	below, _ := slices.BinarySearch(removed, pos)
+	return pos - below
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-analyze/bulk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func newDeleteTestFlows() []*CrawlFlow {
+	var flows []*CrawlFlow
+	for i, ct := range []string{"text/html", "image/png", "text/html", "image/gif; charset=binary", "application/json"} {
+		flows = append(flows, &CrawlFlow{
+			ID: fmt.Sprintf("flow-%d", i), Host: "a.com", Path: fmt.Sprintf("/%d", i), Method: "GET", StatusCode: 200,
+			ContentType: ct,
+			Request:     []byte(fmt.Sprintf("GET /%d HTTP/1.1\r\nHost: a.com\r\n\r\n", i)),
+			Response:    []byte(fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Type: %s\r\n\r\nbody%d shared", ct, i)),
+		})
+	}
+	return flows
+}
+
+func flowIDs(flows []CrawlFlow) []string {
+	ids := make([]string, 0, len(flows))
+	for _, f := range flows {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
+
+func TestCollyBackend_DeleteFlows(t *testing.T) {
+	t.Parallel()
+
+	t.Run("content_type", func(t *testing.T) {
+		b, sessionID := newTestCollySession(t, newDeleteTestFlows())
+
+		deleted, err := b.DeleteFlows(t.Context(), sessionID, CrawlListOptions{ContentType: "image/*"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+
+		got, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"flow-0", "flow-2", "flow-4"}, flowIDs(got))
+
+		_, err = b.GetFlow(t.Context(), "flow-1")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		stats, err := b.GetStats(t.Context(), sessionID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, stats.TotalFlows)
+	})
+
+	t.Run("invert", func(t *testing.T) {
+		b, sessionID := newTestCollySession(t, newDeleteTestFlows())
+
+		deleted, err := b.DeleteFlows(t.Context(), sessionID, CrawlListOptions{ContentType: "text/html", Invert: true})
+		require.NoError(t, err)
+		assert.Equal(t, 3, deleted)
+
+		got, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"flow-0", "flow-2"}, flowIDs(got))
+	})
+
+	t.Run("search_index_realigned", func(t *testing.T) {
+		b, sessionID := newTestCollySession(t, newDeleteTestFlows())
+
+		_, err := b.DeleteFlows(t.Context(), sessionID, CrawlListOptions{SearchBodyRe: regexp.MustCompile(`body1`)})
+		require.NoError(t, err)
+
+		for i, want := range map[string][]string{"body3": {"flow-3"}, "shared": {"flow-0", "flow-2", "flow-3", "flow-4"}, "body1": {}} {
+			got, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{SearchBodyRe: regexp.MustCompile(i)})
+			require.NoError(t, err)
+			assert.Equal(t, want, flowIDs(got), i)
+		}
+	})
+
+	t.Run("cursors_shift", func(t *testing.T) {
+		b, sessionID := newTestCollySession(t, newDeleteTestFlows())
+
+		// Default cursor after flow-2, named cursor after flow-3
+		_, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{Limit: 3})
+		require.NoError(t, err)
+		_, err = b.ListFlows(t.Context(), sessionID, CrawlListOptions{Cursor: "c", Limit: 4})
+		require.NoError(t, err)
+
+		_, err = b.DeleteFlows(t.Context(), sessionID, CrawlListOptions{ContentType: "image/*"})
+		require.NoError(t, err)
+
+		got, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{Since: sinceLast})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"flow-4"}, flowIDs(got))
+		got, err = b.ListFlows(t.Context(), sessionID, CrawlListOptions{Cursor: "c"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"flow-4"}, flowIDs(got))
+	})
+
+	t.Run("no_match", func(t *testing.T) {
+		b, sessionID := newTestCollySession(t, newDeleteTestFlows())
+
+		deleted, err := b.DeleteFlows(t.Context(), sessionID, CrawlListOptions{ContentType: "video/*"})
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+
+	t.Run("unknown_session", func(t *testing.T) {
+		b, _ := newTestCollySession(t, nil)
+
+		_, err := b.DeleteFlows(t.Context(), "missing", CrawlListOptions{ContentType: "image/*"})
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestCollyBackend_DeleteFlow(t *testing.T) {
+	t.Parallel()
+
+	b, sessionID := newTestCollySession(t, newDeleteTestFlows())
+
+	require.NoError(t, b.DeleteFlow(t.Context(), "flow-0"))
+	require.ErrorIs(t, b.DeleteFlow(t.Context(), "flow-0"), ErrNotFound)
+
+	got, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{SearchBodyRe: regexp.MustCompile(`body2`)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"flow-2"}, flowIDs(got))
+}
+
+func TestCollyBackend_DeleteSession(t *testing.T) {
+	t.Parallel()
+
+	t.Run("running_refused", func(t *testing.T) {
+		b, sessionID := newTestCollySession(t, newDeleteTestFlows())
+
+		_, err := b.DeleteSession(t.Context(), sessionID, false)
+		require.ErrorContains(t, err, "is running")
+
+		_, err = b.GetFlow(t.Context(), "flow-0")
+		require.NoError(t, err)
+	})
+
+	t.Run("force", func(t *testing.T) {
+		b, sessionID := newTestCollySession(t, newDeleteTestFlows())
+		sess := b.sessions[sessionID]
+		sess.info.Label = "target"
+		b.byLabel["target"] = sessionID
+
+		deleted, err := b.DeleteSession(t.Context(), "target", true)
+		require.NoError(t, err)
+		assert.Equal(t, 5, deleted)
+		require.ErrorIs(t, sess.ctx.Err(), context.Canceled)
+
+		sessions, err := b.ListSessions(t.Context(), 0)
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+		assert.Empty(t, b.byLabel)
+		for _, f := range newDeleteTestFlows() {
+			_, err = b.GetFlow(t.Context(), f.ID)
+			require.ErrorIs(t, err, ErrNotFound)
+		}
+		_, err = b.GetStatus(t.Context(), sessionID)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		b, sessionID := newTestCollySession(t, newDeleteTestFlows())
+		require.NoError(t, b.StopSession(t.Context(), sessionID))
+
+		deleted, err := b.DeleteSession(t.Context(), sessionID, false)
+		require.NoError(t, err)
+		assert.Equal(t, 5, deleted)
+		assert.Empty(t, b.sessions)
+	})
+
+	t.Run("unknown_session", func(t *testing.T) {
+		b, _ := newTestCollySession(t, nil)
+
+		_, err := b.DeleteSession(t.Context(), "missing", true)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestCollyBackend_ExpireSessions(t *testing.T) {
+	t.Parallel()
+
+	b, oldID := newTestCollySession(t, newDeleteTestFlows())
+	now := time.Now()
+	addSession := func(id, state string, lastActivity time.Time) {
+		ctx, cancel := context.WithCancel(t.Context())
+		runDone := make(chan struct{})
+		close(runDone)
+		b.sessions[id] = &crawlSession{
+			info:         CrawlSessionInfo{ID: id, State: state},
+			flowsByID:    make(map[string]*CrawlFlow),
+			flowNotify:   make(chan struct{}),
+			runDone:      runDone,
+			ctx:          ctx,
+			cancel:       cancel,
+			lastActivity: lastActivity,
+		}
+	}
+	old := b.sessions[oldID]
+	old.info.State, old.info.Label, old.lastActivity = crawlStateCompleted, "old", now.Add(-2*time.Hour)
+	b.byLabel["old"] = oldID
+	addSession("recent", crawlStateStopped, now.Add(-time.Minute))
+	addSession("running", crawlStateRunning, now.Add(-2*time.Hour))
+
+	assert.Equal(t, []string{oldID}, b.expireSessions(time.Hour, now))
+
+	assert.ElementsMatch(t, []string{"recent", "running"}, bulk.MapKeysSlice(b.sessions))
+	assert.Empty(t, b.byLabel)
+	_, err := b.GetFlow(t.Context(), "flow-0")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = b.GetStatus(t.Context(), "old")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	assert.Empty(t, b.expireSessions(time.Hour, now))
+}
+
+func TestCollyBackend_SessionSweeper(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.DefaultConfig()
+	cfg.Crawler.SessionTTLSecs = 1
+	cfg.Crawler.SessionSweepSecs = 1
+	b := NewCollyBackend(cfg, nil, nil)
+	ctx, cancel := context.WithCancel(t.Context())
+	runDone := make(chan struct{})
+	close(runDone)
+	b.mu.Lock()
+	b.sessions["done"] = &crawlSession{
+		info:         CrawlSessionInfo{ID: "done", State: crawlStateCompleted},
+		runDone:      runDone,
+		ctx:          ctx,
+		cancel:       cancel,
+		lastActivity: time.Now().Add(-time.Minute),
+	}
+	b.mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		_, err := b.GetStatus(t.Context(), "done")
+		return errors.Is(err, ErrNotFound)
+	}, 5*time.Second, 50*time.Millisecond)
+
+	require.NoError(t, b.Close())
+	select {
+	case <-b.sweepDone:
+	default:
+		t.Fatal("sweeper still running after Close")
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	dohTimeout     = 10 * time.Second
+	dohMessageType = "application/dns-message"
+	dohMaxResponse = 64 * 1024 // DNS messages are length-prefixed with a uint16
+)
+
+// validateDoHURL checks that a DNS-over-HTTPS resolver is an absolute http(s) URL.
+func validateDoHURL(resolver string) error {
+	u, err := url.Parse(resolver)
+	if err != nil {
+		return fmt.Errorf("invalid doh resolver: %w", err)
+	} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("invalid doh resolver: must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// newDoHResolver returns a net.Resolver that sends every query to endpoint as an
+// RFC 8484 POST. The resolver's own HTTP client uses the system resolver, so the
+// endpoint should be an IP or a name the system can already resolve.
+func newDoHResolver(endpoint string) *net.Resolver {
+	client := &http.Client{Timeout: dohTimeout}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return &dohConn{ctx: ctx, endpoint: endpoint, client: client}, nil
+		},
+	}
+}
+
+// dohConn adapts DoH to the stream framing the Go resolver uses for a net.Conn that
+// is not a net.PacketConn: each Write is a length-prefixed query, answered by a
+// length-prefixed response on Read.
+type dohConn struct {
+	ctx      context.Context
+	endpoint string
+	client   *http.Client
+	deadline time.Time
+	resp     bytes.Reader
+}
+
+func (c *dohConn) Write(b []byte) (int, error) {
+	if len(b) < 2 || int(binary.BigEndian.Uint16(b)) != len(b)-2 {
+		return 0, errors.New("doh: malformed dns query")
+	}
+
+	ctx := c.ctx
+	if !c.deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, c.deadline)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(b[2:]))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", dohMessageType)
+	req.Header.Set("Accept", dohMessageType)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("doh: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("doh: resolver returned status %d", resp.StatusCode)
+	}
+	msg, err := io.ReadAll(io.LimitReader(resp.Body, dohMaxResponse))
+	if err != nil {
+		return 0, fmt.Errorf("doh: read response: %w", err)
+	} else if len(msg) >= dohMaxResponse {
+		return 0, errors.New("doh: response too large")
+	}
+
+	framed := binary.BigEndian.AppendUint16(make([]byte, 0, len(msg)+2), uint16(len(msg)))
+	c.resp.Reset(append(framed, msg...))
+	return len(b), nil
+}
+
+func (c *dohConn) Read(b []byte) (int, error) {
+	return c.resp.Read(b)
+}
+
+func (c *dohConn) Close() error                       { return nil }
+func (c *dohConn) LocalAddr() net.Addr                { return dohAddr(c.endpoint) }
+func (c *dohConn) RemoteAddr() net.Addr               { return dohAddr(c.endpoint) }
+func (c *dohConn) SetDeadline(t time.Time) error      { c.deadline = t; return nil }
+func (c *dohConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *dohConn) SetWriteDeadline(t time.Time) error { c.deadline = t; return nil }
+
+// dohAddr identifies the DoH endpoint in net.Addr form.
+type dohAddr string
+
+func (a dohAddr) Network() string { return "doh" }
+func (a dohAddr) String() string  { return string(a) }
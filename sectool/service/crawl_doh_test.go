@@ -0,0 +1,175 @@
+package service
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+// newTestDoHServer answers A queries for the given names with 127.0.0.1 and
+// returns NXDOMAIN for everything else.
+func newTestDoHServer(t *testing.T, names ...string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != dohMessageType {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var query dnsmessage.Message
+		if err := query.Unpack(body); err != nil || len(query.Questions) != 1 {
+			http.Error(w, "bad query", http.StatusBadRequest)
+			return
+		}
+
+		q := query.Questions[0]
+		name := strings.TrimSuffix(strings.ToLower(q.Name.String()), ".")
+		resp := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: query.ID, Response: true, RecursionAvailable: true, RCode: dnsmessage.RCodeNameError},
+			Questions: query.Questions,
+		}
+		for _, n := range names {
+			if n != name {
+				continue
+			}
+			resp.RCode = dnsmessage.RCodeSuccess
+			if q.Type == dnsmessage.TypeA {
+				resp.Answers = []dnsmessage.Resource{{
+					Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 60},
+					Body:   &dnsmessage.AResource{A: [4]byte{127, 0, 0, 1}},
+				}}
+			}
+		}
+
+		packed, err := resp.Pack()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", dohMessageType)
+		_, _ = w.Write(packed)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestValidateDoHURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		resolver string
+		wantErr  bool
+	}{
+		{"https", "https://dns.example.com/dns-query", false},
+		{"http_ip", "http://10.0.0.53/dns-query", false},
+		{"relative", "/dns-query", true},
+		{"other_scheme", "udp://10.0.0.53", true},
+		{"missing_host", "https:///dns-query", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDoHURL(tt.resolver)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDoHResolver(t *testing.T) {
+	t.Parallel()
+
+	server := newTestDoHServer(t, "app.internal.invalid")
+	resolver := newDoHResolver(server.URL + "/dns-query")
+
+	t.Run("known_name", func(t *testing.T) {
+		addrs, err := resolver.LookupHost(t.Context(), "app.internal.invalid")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"127.0.0.1"}, addrs)
+	})
+
+	t.Run("unknown_name", func(t *testing.T) {
+		_, err := resolver.LookupHost(t.Context(), "other.internal.invalid")
+		var dnsErr *net.DNSError
+		require.ErrorAs(t, err, &dnsErr)
+		assert.True(t, dnsErr.IsNotFound)
+	})
+
+	t.Run("resolver_error", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(failing.Close)
+
+		_, err := newDoHResolver(failing.URL).LookupHost(t.Context(), "app.internal.invalid")
+		assert.Error(t, err)
+	})
+}
+
+func TestCollyBackend_DoHResolver(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var seenHosts []string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seenHosts = append(seenHosts, r.Host)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	t.Cleanup(target.Close)
+	targetURL, err := url.Parse(target.URL)
+	require.NoError(t, err)
+
+	doh := newTestDoHServer(t, "app.internal.invalid")
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	t.Run("invalid_url", func(t *testing.T) {
+		_, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:       []CrawlSeed{{URL: target.URL + "/"}},
+			DoHResolver: "dns.example.com",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid doh resolver")
+	})
+
+	t.Run("resolves_internal_name", func(t *testing.T) {
+		internalHost := "app.internal.invalid:" + targetURL.Port()
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: "http://" + internalHost + "/"}},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+			DoHResolver:     doh.URL + "/dns-query",
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []string{internalHost}, seenHosts)
+	})
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	CrawlExportNDJSON = "ndjson"
+	CrawlExportCSV    = "csv"
+
+	// crawlExportBatch is how many flows are copied per session lock while exporting.
+	crawlExportBatch = 256
+)
+
+// crawlExportCSVHeader names the columns written by a CSV session export.
+var crawlExportCSVHeader = []string{"flow_id", "method", "host", "path", "status", "length", "content_type", "discovered_at"}
+
+// crawlExportFlow is the metadata of a flow in an NDJSON session export; raw request and
+// response bytes are left out.
+type crawlExportFlow struct {
+	FlowID           string   `json:"flow_id"`
+	SessionID        string   `json:"session_id"`
+	URL              string   `json:"url"`
+	Host             string   `json:"host"`
+	Path             string   `json:"path"`
+	Method           string   `json:"method"`
+	FoundOn          string   `json:"found_on,omitempty"`
+	Depth            int      `json:"depth"`
+	Status           int      `json:"status"`
+	ContentType      string   `json:"content_type,omitempty"`
+	ResponseLength   int      `json:"response_length"`
+	Truncated        bool     `json:"truncated,omitempty"`
+	HeadersTruncated bool     `json:"headers_truncated,omitempty"`
+	Duration         string   `json:"duration"`
+	DiscoveredAt     string   `json:"discovered_at"`
+	Findings         []string `json:"findings,omitempty"`
+	VariantOf        string   `json:"variant_of,omitempty"`
+	VariedHeader     string   `json:"varied_header,omitempty"`
+	ReferrerPolicy   string   `json:"referrer_policy,omitempty"`
+	OriginalURL      string   `json:"original_url,omitempty"`
+	RedirectTo       string   `json:"redirect_to,omitempty"`
+	RedirectedFrom   string   `json:"redirected_from,omitempty"`
+	RedirectChain    []string `json:"redirect_chain,omitempty"`
+}
+
+func newCrawlExportFlow(flow *CrawlFlow) crawlExportFlow {
+	return crawlExportFlow{
+		FlowID:           flow.ID,
+		SessionID:        flow.SessionID,
+		URL:              flow.URL,
+		Host:             flow.Host,
+		Path:             flow.Path,
+		Method:           flow.Method,
+		FoundOn:          flow.FoundOn,
+		Depth:            flow.Depth,
+		Status:           flow.StatusCode,
+		ContentType:      flow.ContentType,
+		ResponseLength:   flow.ResponseLength,
+		Truncated:        flow.Truncated,
+		HeadersTruncated: flow.HeadersTruncated,
+		Duration:         flow.Duration.Round(time.Millisecond).String(),
+		DiscoveredAt:     flow.DiscoveredAt.UTC().Format(time.RFC3339Nano),
+		Findings:         flow.Findings,
+		VariantOf:        flow.VariantOf,
+		VariedHeader:     flow.VariedHeader,
+		ReferrerPolicy:   flow.ReferrerPolicy,
+		OriginalURL:      flow.OriginalURL,
+		RedirectTo:       flow.RedirectTo,
+		RedirectedFrom:   flow.RedirectedFrom,
+		RedirectChain:    flow.RedirectChain,
+	}
+}
+
+func (f crawlExportFlow) csvRecord() []string {
+	return []string{f.FlowID, f.Method, f.Host, f.Path, strconv.Itoa(f.Status),
+		strconv.Itoa(f.ResponseLength), f.ContentType, f.DiscoveredAt}
+}
+
+func (b *CollyBackend) ExportSession(ctx context.Context, sessionID, format string, w io.Writer) (int, error) {
+	if format != CrawlExportNDJSON && format != CrawlExportCSV {
+		return 0, fmt.Errorf("unsupported export format %q: use %s or %s", format, CrawlExportNDJSON, CrawlExportCSV)
+	}
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	var write func(crawlExportFlow) error
+	var flush func() error
+	if format == CrawlExportNDJSON {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		write = func(f crawlExportFlow) error { return enc.Encode(f) }
+		flush = func() error { return nil }
+	} else {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(crawlExportCSVHeader); err != nil {
+			return 0, err
+		}
+		write = func(f crawlExportFlow) error { return cw.Write(f.csvRecord()) }
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	}
+
+	// Copy flow metadata a batch at a time so the session stays unlocked while writing and a
+	// large session is never held in memory as a whole. Flows are only appended or removed,
+	// so deletions meanwhile can only move the last exported flow back from next.
+	var count, next int
+	var lastID string
+	batch := make([]crawlExportFlow, 0, crawlExportBatch)
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+
+		batch = batch[:0]
+		sess.mu.RLock()
+		start := min(next, len(sess.flowsOrdered))
+		for i := start - 1; i >= 0; i-- {
+			if sess.flowsOrdered[i].ID == lastID {
+				start = i + 1
+				break
+			}
+		}
+		for _, flow := range sess.flowsOrdered[start:min(start+crawlExportBatch, len(sess.flowsOrdered))] {
+			batch = append(batch, newCrawlExportFlow(flow))
+		}
+		sess.mu.RUnlock()
+
+		if len(batch) == 0 {
+			return count, flush()
+		}
+		for _, f := range batch {
+			if err := write(f); err != nil {
+				return count, err
+			}
+		}
+		count += len(batch)
+		next = start + len(batch)
+		lastID = batch[len(batch)-1].FlowID
+	}
+}
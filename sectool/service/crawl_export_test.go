@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTestFlows() []*CrawlFlow {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return []*CrawlFlow{
+		{ID: "flow-0", URL: "https://a.com/", Host: "a.com", Path: "/", Method: "GET", StatusCode: 200,
+			ContentType: "text/html", ResponseLength: 120, DiscoveredAt: at, Duration: 15 * time.Millisecond,
+			Request: []byte("GET / HTTP/1.1\r\nHost: a.com\r\n\r\n"), Response: []byte("HTTP/1.1 200 OK\r\n\r\n<html>secret-body</html>")},
+		{ID: "flow-1", URL: "https://a.com/login", Host: "a.com", Path: "/login", Method: "POST", StatusCode: 302,
+			ResponseLength: 0, DiscoveredAt: at.Add(time.Second), RedirectTo: "https://a.com/home",
+			Findings: []string{"session-cookie-rotated"}},
+		{ID: "flow-2", URL: "https://b.com/api?q=1,2", Host: "b.com", Path: "/api?q=1,2", Method: "GET", StatusCode: 404,
+			ContentType: "application/json; charset=utf-8", ResponseLength: 9, DiscoveredAt: at.Add(2 * time.Second)},
+	}
+}
+
+func TestCollyBackend_ExportSession(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ndjson", func(t *testing.T) {
+		t.Parallel()
+		b, sessionID := newTestCollySession(t, exportTestFlows())
+
+		var buf bytes.Buffer
+		count, err := b.ExportSession(t.Context(), sessionID, CrawlExportNDJSON, &buf)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.NotContains(t, buf.String(), "secret-body")
+
+		var got []crawlExportFlow
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			var f crawlExportFlow
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &f))
+			got = append(got, f)
+		}
+		require.Len(t, got, 3)
+		assert.Equal(t, "flow-0", got[0].FlowID)
+		assert.Equal(t, sessionID, got[0].SessionID)
+		assert.Equal(t, "text/html", got[0].ContentType)
+		assert.Equal(t, 120, got[0].ResponseLength)
+		assert.Equal(t, "15ms", got[0].Duration)
+		assert.Equal(t, "2024-05-01T12:00:00Z", got[0].DiscoveredAt)
+		assert.Equal(t, "POST", got[1].Method)
+		assert.Equal(t, "https://a.com/home", got[1].RedirectTo)
+		assert.Equal(t, []string{"session-cookie-rotated"}, got[1].Findings)
+		assert.Equal(t, 404, got[2].Status)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		t.Parallel()
+		b, sessionID := newTestCollySession(t, exportTestFlows())
+
+		var buf bytes.Buffer
+		count, err := b.ExportSession(t.Context(), sessionID, CrawlExportCSV, &buf)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			crawlExportCSVHeader,
+			{"flow-0", "GET", "a.com", "/", "200", "120", "text/html", "2024-05-01T12:00:00Z"},
+			{"flow-1", "POST", "a.com", "/login", "302", "0", "", "2024-05-01T12:00:01Z"},
+			{"flow-2", "GET", "b.com", "/api?q=1,2", "404", "9", "application/json; charset=utf-8", "2024-05-01T12:00:02Z"},
+		}, records)
+	})
+
+	t.Run("delete_between_batches", func(t *testing.T) {
+		t.Parallel()
+		flows := make([]*CrawlFlow, crawlExportBatch+10)
+		for i := range flows {
+			flows[i] = &CrawlFlow{ID: fmt.Sprintf("flow-%d", i), Method: "GET"}
+		}
+		b, sessionID := newTestCollySession(t, flows)
+
+		// Deleting an already exported flow between batches must not skip or repeat others
+		w := &deleteAfterWriter{t: t, b: b, flowID: flows[3].ID, after: crawlExportBatch}
+		count, err := b.ExportSession(t.Context(), sessionID, CrawlExportNDJSON, w)
+		require.NoError(t, err)
+		assert.Equal(t, len(flows), count)
+
+		var ids []string
+		scanner := bufio.NewScanner(&w.buf)
+		for scanner.Scan() {
+			var f crawlExportFlow
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &f))
+			ids = append(ids, f.FlowID)
+		}
+		want := make([]string, len(flows))
+		for i, f := range flows {
+			want[i] = f.ID
+		}
+		assert.Equal(t, want, ids)
+	})
+
+	t.Run("unknown_format", func(t *testing.T) {
+		t.Parallel()
+		b, sessionID := newTestCollySession(t, exportTestFlows())
+
+		var buf bytes.Buffer
+		_, err := b.ExportSession(t.Context(), sessionID, "xml", &buf)
+		require.Error(t, err)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("unknown_session", func(t *testing.T) {
+		t.Parallel()
+		b, _ := newTestCollySession(t, nil)
+
+		var buf bytes.Buffer
+		_, err := b.ExportSession(t.Context(), "missing", CrawlExportCSV, &buf)
+		require.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, buf.String())
+	})
+}
+
+// deleteAfterWriter buffers an NDJSON export and deletes flowID once after lines were written.
+type deleteAfterWriter struct {
+	t      *testing.T
+	b      *CollyBackend
+	flowID string
+	after  int
+	buf    bytes.Buffer
+}
+
+func (w *deleteAfterWriter) Write(p []byte) (int, error) {
+	n, _ := w.buf.Write(p)
+	if bytes.Count(w.buf.Bytes(), []byte("\n")) == w.after && w.flowID != "" {
+		require.NoError(w.t, w.b.DeleteFlow(w.t.Context(), w.flowID))
+		w.flowID = ""
+	}
+	return n, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Sources of links returned by extractLinks.
+const (
+	linkSourceAnchor  = "anchor"  // <a>/<area> href
+	linkSourceForm    = "form"    // <form> action
+	linkSourceScript  = "script"  // <script src>, or a route declared in JavaScript
+	linkSourceJSON    = "json"    // URL-like string values in a JSON document
+	linkSourceCSS     = "css"     // stylesheet links, url() and @import references
+	linkSourceComment = "comment" // URLs and paths inside HTML comments
+)
+
+// ExtractedLink is a URL found in a single response by extractLinks.
+type ExtractedLink struct {
+	URL    string // Absolute when the reference could be resolved against the response URL
+	Source string // anchor, form, script, json, css or comment
+}
+
+// extractLinks runs the crawler's URL extractors over one response body, picking them
+// by media type: HTML pages get anchors, forms, scripts, stylesheets and comments;
+// JavaScript gets declared client-side routes; JSON and CSS their own extractors.
+// References are resolved against baseURL and deduplicated per source.
+func extractLinks(baseURL, contentType string, body []byte) []ExtractedLink {
+	base, _ := url.Parse(baseURL)
+
+	var links []ExtractedLink
+	seen := make(map[string]bool)
+	add := func(source, ref string) {
+		ref = strings.TrimSpace(ref)
+		lower := strings.ToLower(ref)
+		if ref == "" || ref == "#" || strings.HasPrefix(lower, "javascript:") || strings.HasPrefix(lower, "mailto:") ||
+			strings.HasPrefix(lower, "tel:") || strings.HasPrefix(lower, "data:") || strings.HasPrefix(lower, "about:") {
+			return
+		}
+		if base != nil {
+			if u, err := base.Parse(ref); err == nil {
+				ref = u.String()
+			}
+		}
+		if key := source + " " + ref; !seen[key] {
+			seen[key] = true
+			links = append(links, ExtractedLink{URL: ref, Source: source})
+		}
+	}
+
+	switch mediaType := contentMediaType(contentType); {
+	case isJavaScriptContentType(mediaType):
+		for _, r := range extractClientRoutes(body) {
+			add(linkSourceScript, r.Route)
+		}
+	case isJSONMediaType(mediaType):
+		for _, u := range extractJSONURLs(body) {
+			add(linkSourceJSON, u)
+		}
+	case mediaType == "text/css":
+		for _, u := range extractCSSURLs(body) {
+			add(linkSourceCSS, u)
+		}
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml" || mediaType == "":
+		extractHTMLLinks(body, add)
+	}
+	return links
+}
+
+// extractHTMLLinks reports the link-bearing elements of an HTML document to add.
+func extractHTMLLinks(body []byte, add func(source, ref string)) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	doc.Find("a[href], area[href]").Each(func(_ int, s *goquery.Selection) {
+		add(linkSourceAnchor, s.AttrOr("href", ""))
+	})
+	doc.Find("form").Each(func(_ int, s *goquery.Selection) {
+		add(linkSourceForm, s.AttrOr("action", ""))
+	})
+	doc.Find("script[src]").Each(func(_ int, s *goquery.Selection) {
+		add(linkSourceScript, s.AttrOr("src", ""))
+	})
+	doc.Find("script:not([src])").Each(func(_ int, s *goquery.Selection) {
+		for _, r := range extractClientRoutes([]byte(s.Text())) {
+			add(linkSourceScript, r.Route)
+		}
+	})
+	doc.Find(`link[rel~="stylesheet"][href]`).Each(func(_ int, s *goquery.Selection) {
+		add(linkSourceCSS, s.AttrOr("href", ""))
+	})
+	doc.Find("style").Each(func(_ int, s *goquery.Selection) {
+		for _, u := range extractCSSURLs([]byte(s.Text())) {
+			add(linkSourceCSS, u)
+		}
+	})
+	doc.Find("[style]").Each(func(_ int, s *goquery.Selection) {
+		for _, u := range extractCSSURLs([]byte(s.AttrOr("style", ""))) {
+			add(linkSourceCSS, u)
+		}
+	})
+
+	urls, _ := extractHTMLComments(body)
+	for _, u := range urls {
+		add(linkSourceComment, u)
+	}
+}
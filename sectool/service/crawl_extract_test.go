@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLinks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        []ExtractedLink
+	}{
+		{
+			name:        "html",
+			contentType: "text/html; charset=utf-8",
+			body: `<html><head>
+<link rel="stylesheet" href="/static/site.css">
+<style>body { background: url('img/bg.png') }</style>
+<script src="https://cdn.example.net/lib.js"></script>
+<script>router.push('/account/settings')</script>
+</head><body>
+<a href="next.html">Next</a>
+<a href="next.html">Next again</a>
+<a href="mailto:admin@example.com">Mail</a>
+<a href="javascript:void(0)">Noop</a>
+<map><area href="/map/region"></map>
+<form action="/search" method="get"></form>
+<div style="background-image: url(/img/hero.jpg)"></div>
+<!-- staging: https://staging.example.com/debug -->
+</body></html>`,
+			want: []ExtractedLink{
+				{URL: "https://example.com/app/next.html", Source: linkSourceAnchor},
+				{URL: "https://example.com/map/region", Source: linkSourceAnchor},
+				{URL: "https://example.com/search", Source: linkSourceForm},
+				{URL: "https://cdn.example.net/lib.js", Source: linkSourceScript},
+				{URL: "https://example.com/account/settings", Source: linkSourceScript},
+				{URL: "https://example.com/static/site.css", Source: linkSourceCSS},
+				{URL: "https://example.com/app/img/bg.png", Source: linkSourceCSS},
+				{URL: "https://example.com/img/hero.jpg", Source: linkSourceCSS},
+				{URL: "https://staging.example.com/debug", Source: linkSourceComment},
+			},
+		},
+		{
+			name:        "javascript",
+			contentType: "application/javascript",
+			body:        `const routes = [{ path: '/admin/users' }]; history.pushState({}, '', '/reports');`,
+			want: []ExtractedLink{
+				{URL: "https://example.com/admin/users", Source: linkSourceScript},
+				{URL: "https://example.com/reports", Source: linkSourceScript},
+			},
+		},
+		{
+			name:        "json",
+			contentType: "application/vnd.api+json",
+			body:        `[{"self":"/api/v2/items?page=1"},{"docs":"https://docs.example.com/"},{"name":"not a url"}]`,
+			want: []ExtractedLink{
+				{URL: "https://example.com/api/v2/items?page=1", Source: linkSourceJSON},
+				{URL: "https://docs.example.com/", Source: linkSourceJSON},
+			},
+		},
+		{
+			name:        "css",
+			contentType: "text/css",
+			body:        `@import "theme.css"; .logo { background: url(../img/logo.svg) }`,
+			want: []ExtractedLink{
+				{URL: "https://example.com/img/logo.svg", Source: linkSourceCSS},
+				{URL: "https://example.com/app/theme.css", Source: linkSourceCSS},
+			},
+		},
+		{
+			name:        "binary",
+			contentType: "image/png",
+			body:        `<a href="/not-html">`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractLinks("https://example.com/app/index.html", tt.contentType, []byte(tt.body))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// endpointFingerprint identifies the endpoint a flow hit, independent of the concrete IDs
+// and parameter values in it: host, method, path with dynamic segments replaced (see
+// normalizePath), and the sorted names of its query and body parameters. Flows sharing a
+// fingerprint are instances of the same endpoint.
+func endpointFingerprint(flow *CrawlFlow) string {
+	path, query, _ := strings.Cut(flow.Path, "?")
+
+	var params []string
+	if values, err := url.ParseQuery(query); err == nil {
+		for name := range values {
+			params = append(params, name)
+		}
+	}
+	params = append(params, bodyParamNames(flow.Request)...)
+	slices.Sort(params)
+	params = slices.Compact(params)
+
+	return strings.ToLower(flow.Host) + " " + strings.ToUpper(flow.Method) + " " +
+		normalizePath(path) + "?" + strings.Join(params, "&")
+}
+
+// bodyParamNames returns the form field names or top-level JSON object keys of a
+// wire-format request body; nil for other bodies.
+func bodyParamNames(rawRequest []byte) []string {
+	headers, body := splitHeadersBody(rawRequest)
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil
+	}
+
+	var contentType string
+	for _, line := range strings.Split(string(headers), "\r\n") {
+		if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(name), "Content-Type") {
+			contentType, _, _ = mime.ParseMediaType(strings.TrimSpace(value))
+			break
+		}
+	}
+
+	var names []string
+	switch {
+	case contentType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil
+		}
+		for name := range values {
+			names = append(names, name)
+		}
+	case strings.HasSuffix(contentType, "json") || (contentType == "" && body[0] == '{'):
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(body, &obj) != nil {
+			return nil
+		}
+		for name := range obj {
+			names = append(names, name)
+		}
+	}
+	return names
+}
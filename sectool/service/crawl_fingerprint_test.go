@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointFingerprint(t *testing.T) {
+	t.Parallel()
+
+	formRequest := func(body string) []byte {
+		return []byte("POST /login HTTP/1.1\r\nHost: example.com\r\nContent-Type: application/x-www-form-urlencoded\r\n\r\n" + body)
+	}
+	jsonRequest := func(body string) []byte {
+		return []byte("POST /api/items HTTP/1.1\r\nHost: example.com\r\nContent-Type: application/json; charset=utf-8\r\n\r\n" + body)
+	}
+
+	tests := []struct {
+		name  string
+		a, b  CrawlFlow
+		equal bool
+	}{
+		{
+			name:  "ids_and_values_ignored",
+			a:     CrawlFlow{Method: "GET", Host: "example.com", Path: "/api/users/17/orders/550e8400-e29b-41d4-a716-446655440000?sort=asc&page=1"},
+			b:     CrawlFlow{Method: "get", Host: "Example.com", Path: "/api/users/42/orders/6ba7b810-9dad-11d1-80b4-00c04fd430c8?page=9&sort=desc"},
+			equal: true,
+		},
+		{
+			name: "different_param_names",
+			a:    CrawlFlow{Method: "GET", Host: "example.com", Path: "/api/users/1?fields=name"},
+			b:    CrawlFlow{Method: "GET", Host: "example.com", Path: "/api/users/1?expand=orders"},
+		},
+		{
+			name: "different_method",
+			a:    CrawlFlow{Method: "GET", Host: "example.com", Path: "/api/users/1"},
+			b:    CrawlFlow{Method: "DELETE", Host: "example.com", Path: "/api/users/1"},
+		},
+		{
+			name: "different_host",
+			a:    CrawlFlow{Method: "GET", Host: "a.example.com", Path: "/api/users/1"},
+			b:    CrawlFlow{Method: "GET", Host: "b.example.com", Path: "/api/users/1"},
+		},
+		{
+			name: "different_static_segment",
+			a:    CrawlFlow{Method: "GET", Host: "example.com", Path: "/api/users/1"},
+			b:    CrawlFlow{Method: "GET", Host: "example.com", Path: "/api/groups/1"},
+		},
+		{
+			name:  "form_body_values_ignored",
+			a:     CrawlFlow{Method: "POST", Host: "example.com", Path: "/login", Request: formRequest("user=alice&pass=x")},
+			b:     CrawlFlow{Method: "POST", Host: "example.com", Path: "/login", Request: formRequest("pass=y&user=bob")},
+			equal: true,
+		},
+		{
+			name: "form_body_names_differ",
+			a:    CrawlFlow{Method: "POST", Host: "example.com", Path: "/login", Request: formRequest("user=alice&pass=x")},
+			b:    CrawlFlow{Method: "POST", Host: "example.com", Path: "/login", Request: formRequest("user=alice&pass=x&otp=1")},
+		},
+		{
+			name:  "json_body_keys",
+			a:     CrawlFlow{Method: "POST", Host: "example.com", Path: "/api/items", Request: jsonRequest(`{"id": 1, "name": "a"}`)},
+			b:     CrawlFlow{Method: "POST", Host: "example.com", Path: "/api/items", Request: jsonRequest(`{"name": "b", "id": 2}`)},
+			equal: true,
+		},
+		{
+			name: "json_body_keys_differ",
+			a:    CrawlFlow{Method: "POST", Host: "example.com", Path: "/api/items", Request: jsonRequest(`{"id": 1}`)},
+			b:    CrawlFlow{Method: "POST", Host: "example.com", Path: "/api/items", Request: jsonRequest(`{"id": 1, "role": "admin"}`)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := endpointFingerprint(&tt.a), endpointFingerprint(&tt.b)
+			if tt.equal {
+				assert.Equal(t, a, b)
+			} else {
+				assert.NotEqual(t, a, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+)
+
+// maxFormSubmissionFlows caps the flows linked to a single form; GET forms that
+// target a commonly crawled page would otherwise collect every visit to it.
+const maxFormSubmissionFlows = 10
+
+// formEndpointKey reduces a request to its method and URL without query or
+// fragment, so a form matches its submissions regardless of the values sent.
+func formEndpointKey(method, rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		u.RawQuery = ""
+		u.ForceQuery = false
+		u.Fragment = ""
+		rawURL = u.String()
+	} else if i := strings.IndexAny(rawURL, "?#"); i >= 0 {
+		rawURL = rawURL[:i]
+	}
+	return strings.ToUpper(method) + " " + rawURL
+}
+
+// linkFormSubmissions records on each form the captured flows that requested its
+// action with its method. Flows are matched in discovery order. For GET forms the
+// load of the page containing the form is skipped when the action targets that
+// same page, since it is not a submission.
+func linkFormSubmissions(forms []DiscoveredForm, flows []*CrawlFlow) {
+	if len(forms) == 0 || len(flows) == 0 {
+		return
+	}
+
+	byEndpoint := make(map[string][]*CrawlFlow)
+	for _, flow := range flows {
+		key := formEndpointKey(flow.Method, flow.URL)
+		byEndpoint[key] = append(byEndpoint[key], flow)
+	}
+
+	for i := range forms {
+		form := &forms[i]
+		form.SubmissionFlowIDs = nil
+		for _, flow := range byEndpoint[formEndpointKey(form.Method, form.Action)] {
+			if form.Method == "GET" && flow.URL == form.URL {
+				continue
+			}
+			form.SubmissionFlowIDs = append(form.SubmissionFlowIDs, flow.ID)
+			if len(form.SubmissionFlowIDs) >= maxFormSubmissionFlows {
+				break
+			}
+		}
+	}
+}
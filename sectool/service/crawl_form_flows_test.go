@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormEndpointKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		want   string
+	}{
+		{name: "strips_query", method: "GET", url: "https://example.com/search?q=x", want: "GET https://example.com/search"},
+		{name: "strips_fragment", method: "post", url: "https://example.com/login#top", want: "POST https://example.com/login"},
+		{name: "empty_query", method: "GET", url: "https://example.com/search?", want: "GET https://example.com/search"},
+		{name: "plain", method: "POST", url: "https://example.com/api/v1/items", want: "POST https://example.com/api/v1/items"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, formEndpointKey(tc.method, tc.url))
+		})
+	}
+}
+
+func TestLinkFormSubmissions(t *testing.T) {
+	t.Parallel()
+
+	flows := []*CrawlFlow{
+		{ID: "page", Method: "GET", URL: "https://example.com/search"},
+		{ID: "login", Method: "POST", URL: "https://example.com/login"},
+		{ID: "login_get", Method: "GET", URL: "https://example.com/login?user=a"},
+		{ID: "search1", Method: "GET", URL: "https://example.com/search?q=a"},
+		{ID: "search2", Method: "GET", URL: "https://example.com/search?q=b"},
+	}
+
+	t.Run("post_form", func(t *testing.T) {
+		forms := []DiscoveredForm{{URL: "https://example.com/", Action: "https://example.com/login", Method: "POST"}}
+		linkFormSubmissions(forms, flows)
+		assert.Equal(t, []string{"login"}, forms[0].SubmissionFlowIDs)
+	})
+
+	t.Run("get_form_skips_own_page", func(t *testing.T) {
+		forms := []DiscoveredForm{{URL: "https://example.com/search", Action: "https://example.com/search", Method: "GET"}}
+		linkFormSubmissions(forms, flows)
+		assert.Equal(t, []string{"search1", "search2"}, forms[0].SubmissionFlowIDs)
+	})
+
+	t.Run("no_match", func(t *testing.T) {
+		forms := []DiscoveredForm{{URL: "https://example.com/", Action: "https://example.com/logout", Method: "POST"}}
+		linkFormSubmissions(forms, flows)
+		assert.Empty(t, forms[0].SubmissionFlowIDs)
+	})
+
+	t.Run("capped", func(t *testing.T) {
+		many := make([]*CrawlFlow, 0, maxFormSubmissionFlows+5)
+		for range maxFormSubmissionFlows + 5 {
+			many = append(many, &CrawlFlow{ID: "f", Method: "POST", URL: "https://example.com/api"})
+		}
+		forms := []DiscoveredForm{{URL: "https://example.com/", Action: "https://example.com/api", Method: "POST"}}
+		linkFormSubmissions(forms, many)
+		assert.Len(t, forms[0].SubmissionFlowIDs, maxFormSubmissionFlows)
+	})
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// Crawl strategies, ordering how discovered links are visited.
+const (
+	crawlStrategyBFS = "bfs" // breadth-first: shallower pages first (default)
+	crawlStrategyDFS = "dfs" // depth-first: each branch is exhausted before its siblings
+)
+
+func validateCrawlStrategy(strategy string) error {
+	switch strategy {
+	case "", crawlStrategyBFS, crawlStrategyDFS:
+		return nil
+	default:
+		return fmt.Errorf("invalid strategy %q: must be %s or %s", strategy, crawlStrategyBFS, crawlStrategyDFS)
+	}
+}
+
+// crawlFrontier queues URLs for a crawl visiting one URL at a time. Breadth-first frontiers
+// take the shallowest entry next, depth-first ones the deepest, so the links of the page
+// just visited are followed before its siblings'. Within a depth, entries are ordered by
+// URL when sorted (Deterministic), so the same site is visited in the same order, and by
+// discovery order otherwise.
+type crawlFrontier struct {
+	depthFirst bool
+	sorted     bool
+
+	mu      sync.Mutex
+	entries []frontierEntry // in visit order
+	pushed  int             // entries ever pushed, for discovery order
+	holds   int             // producers (AddSeeds and its recon) that may still push
+	closed  bool            // set once the run has finished draining; later pushes are refused
+}
+
+type frontierEntry struct {
+	from  *colly.Request // page the link was found on; nil for seeds
+	link  string
+	depth int
+	seq   int // discovery order
+}
+
+// push queues link as found on from (nil for a seed), reporting false when the frontier
+// is closed and the link will not be visited.
+func (f *crawlFrontier) push(from *colly.Request, link string) bool {
+	e := frontierEntry{from: from, link: link, depth: 1}
+	if from != nil {
+		e.depth = from.Depth + 1
+	}
+	return f.insert(e)
+}
+
+// pushAt queues link without a page it was found on, ordered at depth; a link requeued on
+// resume keeps the depth it was discovered at.
+func (f *crawlFrontier) pushAt(link string, depth int) bool {
+	return f.insert(frontierEntry{link: link, depth: depth})
+}
+
+func (f *crawlFrontier) insert(e frontierEntry) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return false
+	}
+	e.seq = f.pushed
+	f.pushed++
+	i, _ := slices.BinarySearchFunc(f.entries, e, f.compare)
+	f.entries = slices.Insert(f.entries, i, e)
+	return true
+}
+
+// hold registers a producer that may push after the run's current drain, keeping the
+// frontier open until release. It reports false when the frontier is already closed.
+func (f *crawlFrontier) hold() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return false
+	}
+	f.holds++
+	return true
+}
+
+func (f *crawlFrontier) release() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.holds--
+}
+
+// closeIfEmpty closes the frontier when nothing is queued or held, reporting whether it
+// did. A run drains again when it returns false, so entries pushed during the last drain
+// (late seeds, recon results) are still visited.
+func (f *crawlFrontier) closeIfEmpty() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.entries) == 0 && f.holds == 0 {
+		f.closed = true
+	}
+	return f.closed
+}
+
+// pop removes and returns the next entry to visit; false when the frontier is empty.
+func (f *crawlFrontier) pop() (frontierEntry, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.entries) == 0 {
+		return frontierEntry{}, false
+	}
+	e := f.entries[0]
+	f.entries = f.entries[1:]
+	return e, true
+}
+
+func (f *crawlFrontier) compare(a, b frontierEntry) int {
+	byDepth := cmp.Compare(a.depth, b.depth)
+	if f.depthFirst {
+		byDepth = -byDepth
+	}
+	if f.sorted {
+		return cmp.Or(byDepth, cmp.Compare(a.link, b.link))
+	}
+	return cmp.Or(byDepth, cmp.Compare(a.seq, b.seq))
+}
+
+// drain visits queued entries one at a time until the frontier is empty or done is closed.
+// The collector must be synchronous so each visit, and everything it queues, completes
+// before the next entry is taken.
+func (f *crawlFrontier) drain(c *colly.Collector, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		e, ok := f.pop()
+		if !ok {
+			return
+		} else if e.from == nil {
+			_ = c.Visit(e.link)
+		} else {
+			_ = e.from.Visit(e.link)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// JavaScript endpoint kinds, by how the URL appeared in the script.
+const (
+	jsEndpointKindFetch  = "fetch"  // fetch("...") call
+	jsEndpointKindAxios  = "axios"  // axios("...") or axios.<method>("...")
+	jsEndpointKindXHR    = "xhr"    // XMLHttpRequest .open("METHOD", "...")
+	jsEndpointKindString = "string" // any other quoted path-like literal
+)
+
+// endpoint characters: URL path and query, with :param and {param} placeholders used by API clients
+const endpointChars = `[A-Za-z0-9_\-.~%:{}/@!$&*+,;=?]`
+
+var (
+	jsFetchRe = regexp.MustCompile(`\bfetch\s*\(\s*["'` + "`" + `]([^"'` + "`" + `\s]+)["'` + "`" + `]`)
+	jsAxiosRe = regexp.MustCompile(`\baxios(?:\.(get|post|put|patch|delete|head|options))?\s*\(\s*["'` + "`" + `]([^"'` + "`" + `\s]+)["'` + "`" + `]`)
+	jsXHRRe   = regexp.MustCompile(`\.open\s*\(\s*["'` + "`" + `]([A-Za-z]+)["'` + "`" + `]\s*,\s*["'` + "`" + `]([^"'` + "`" + `\s]+)["'` + "`" + `]`)
+	jsPathRe  = regexp.MustCompile(`["'` + "`" + `](/[A-Za-z0-9_\-.~%]` + endpointChars + `+)["'` + "`" + `]`)
+	jsAssetRe = regexp.MustCompile(`(?i)\.(?:png|jpe?g|gif|svg|ico|webp|woff2?|ttf|eot|css|map)(?:\?|$)`)
+)
+
+// extractJSEndpoints scans a JavaScript body for request URL literals: fetch, axios and
+// XHR calls, plus quoted root-relative path strings. Endpoints are returned in discovery
+// order, deduplicated by URL; call sites take precedence over bare strings so the HTTP
+// method is kept when known. Template literals with ${...} and static assets are skipped.
+func extractJSEndpoints(body []byte) []JSEndpoint {
+	var endpoints []JSEndpoint
+	seen := make(map[string]bool)
+	add := func(link, method, kind string) {
+		if link == "" || seen[link] || strings.Contains(link, "${") || strings.HasPrefix(link, "//") ||
+			!routeHasWordRe.MatchString(link) || jsAssetRe.MatchString(link) {
+			return
+		}
+		if u, err := url.Parse(link); err != nil || (u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https") {
+			return
+		}
+		seen[link] = true
+		endpoints = append(endpoints, JSEndpoint{URL: link, Method: method, Kind: kind})
+	}
+
+	for _, m := range jsFetchRe.FindAllSubmatch(body, -1) {
+		add(string(m[1]), "", jsEndpointKindFetch)
+	}
+	for _, m := range jsAxiosRe.FindAllSubmatch(body, -1) {
+		add(string(m[2]), strings.ToUpper(string(m[1])), jsEndpointKindAxios)
+	}
+	for _, m := range jsXHRRe.FindAllSubmatch(body, -1) {
+		add(string(m[2]), strings.ToUpper(string(m[1])), jsEndpointKindXHR)
+	}
+	for _, m := range jsPathRe.FindAllSubmatch(body, -1) {
+		add(string(m[1]), "", jsEndpointKindString)
+	}
+	return endpoints
+}
+
+// recordJSEndpoints resolves endpoints against base (the page that loaded the script, as
+// a browser would) and adds those not yet seen in the session, attributed to foundOn.
+// Returns the newly recorded endpoints.
+func (sess *crawlSession) recordJSEndpoints(endpoints []JSEndpoint, base *url.URL, foundOn string) []JSEndpoint {
+	if len(endpoints) == 0 {
+		return nil
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	var added []JSEndpoint
+	for _, e := range endpoints {
+		if u, err := base.Parse(e.URL); err == nil {
+			u.Fragment = ""
+			e.URL = u.String()
+		}
+		if sess.endpointsSeen[e.URL] {
+			continue
+		}
+		sess.endpointsSeen[e.URL] = true
+		e.FoundOn = foundOn
+		sess.jsEndpoints = append(sess.jsEndpoints, e)
+		added = append(added, e)
+	}
+	return added
+}
+
+// jsEndpointCrawlable reports whether an endpoint may be requested by the crawler: same
+// origin as base, and not declared with a method other than GET.
+func jsEndpointCrawlable(e JSEndpoint, base *url.URL) bool {
+	if e.Method != "" && e.Method != http.MethodGet {
+		return false
+	}
+	u, err := url.Parse(e.URL)
+	return err == nil && strings.EqualFold(u.Scheme, base.Scheme) && strings.EqualFold(u.Host, base.Host)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+const sampleJSBundle = `!function(){
+var API="/api/v1";
+function load(id){return fetch("/api/v1/users/"+id).then(function(r){return r.json()})}
+fetch('/api/v1/session',{credentials:"include"});
+axios.post("/api/v1/orders",{sku:1});
+axios("/api/v1/cart");
+var x=new XMLHttpRequest();x.open("GET","/legacy/report.jsp?format=csv");
+fetch(` + "`/api/v1/items/${id}`" + `);
+var logo="/static/img/logo.png",docs="https://docs.example.com/api",mail="mailto:ops@example.com";
+var re=/ab+c/g,sep="/";
+fetch("/api/v1/session");
+}();`
+
+func TestExtractJSEndpoints(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []JSEndpoint{
+		{URL: "/api/v1/users/", Kind: jsEndpointKindFetch},
+		{URL: "/api/v1/session", Kind: jsEndpointKindFetch},
+		{URL: "/api/v1/orders", Method: "POST", Kind: jsEndpointKindAxios},
+		{URL: "/api/v1/cart", Kind: jsEndpointKindAxios},
+		{URL: "/legacy/report.jsp?format=csv", Method: "GET", Kind: jsEndpointKindXHR},
+		{URL: "/api/v1", Kind: jsEndpointKindString},
+	}, extractJSEndpoints([]byte(sampleJSBundle)))
+
+	assert.Empty(t, extractJSEndpoints([]byte(`var a = 1 / 2; console.log("done")`)))
+}
+
+func TestJSEndpointCrawlable(t *testing.T) {
+	t.Parallel()
+
+	base, err := url.Parse("https://app.example.com/dashboard")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		endpoint JSEndpoint
+		want     bool
+	}{
+		{name: "same_origin", endpoint: JSEndpoint{URL: "https://app.example.com/api/users"}, want: true},
+		{name: "declared_get", endpoint: JSEndpoint{URL: "https://app.example.com/api/users", Method: "GET"}, want: true},
+		{name: "declared_post", endpoint: JSEndpoint{URL: "https://app.example.com/api/users", Method: "POST"}},
+		{name: "other_host", endpoint: JSEndpoint{URL: "https://api.example.com/users"}},
+		{name: "other_scheme", endpoint: JSEndpoint{URL: "http://app.example.com/api/users"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, jsEndpointCrawlable(tt.endpoint, base))
+		})
+	}
+}
+
+func TestCollyBackend_ExtractJSEndpoints(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><head><script src="/static/app.js"></script></head><body><a href="/api/v1/cart">Cart</a></body></html>`))
+		case "/static/app.js":
+			w.Header().Set("Content-Type", "application/javascript")
+			_, _ = w.Write([]byte(sampleJSBundle))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	runCrawl := func(t *testing.T, opts CrawlOptions) ([]JSEndpoint, map[string]int) {
+		t.Helper()
+
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		opts.Seeds = []CrawlSeed{{URL: server.URL + "/"}}
+		opts.Delay = time.Millisecond
+		opts.IgnoreRobotsTxt = true
+		sess, err := b.CreateSession(t.Context(), opts)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+
+		endpoints, err := b.ListJSEndpoints(t.Context(), sess.ID, 0)
+		require.NoError(t, err)
+		flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+		require.NoError(t, err)
+		visits := make(map[string]int)
+		for _, f := range flows {
+			visits[f.Path]++
+		}
+		return endpoints, visits
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		endpoints, visits := runCrawl(t, CrawlOptions{
+			ExtractJSEndpoints: true,
+			DisallowedPaths:    []string{"*/legacy/*"},
+		})
+
+		script := server.URL + "/static/app.js"
+		assert.Contains(t, endpoints, JSEndpoint{URL: server.URL + "/api/v1/orders", Method: "POST", Kind: jsEndpointKindAxios, FoundOn: script})
+		assert.Contains(t, endpoints, JSEndpoint{URL: server.URL + "/legacy/report.jsp?format=csv", Method: "GET", Kind: jsEndpointKindXHR, FoundOn: script})
+		assert.Len(t, endpoints, 6)
+
+		assert.Equal(t, map[string]int{
+			"/":               1,
+			"/static/app.js":  1,
+			"/api/v1/users/":  1,
+			"/api/v1/session": 1,
+			"/api/v1/cart":    1, // also linked from the page, requested once
+			"/api/v1":         1,
+		}, visits) // POST-only endpoint and disallowed path are recorded but not requested
+	})
+
+	t.Run("max_depth", func(t *testing.T) {
+		endpoints, visits := runCrawl(t, CrawlOptions{ExtractJSEndpoints: true, MaxDepth: 2})
+		assert.Len(t, endpoints, 6)
+		assert.NotContains(t, visits, "/api/v1/session")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		endpoints, visits := runCrawl(t, CrawlOptions{})
+		assert.Empty(t, endpoints)
+		assert.NotContains(t, visits, "/static/app.js")
+	})
+}
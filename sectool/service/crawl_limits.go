@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// parseHostLimitsArg parses the host_limits argument of crawl_create: an array of
+// "host_glob=delay[,parallelism[,random_delay]]" entries, e.g. "cdn.example.com=0s,8".
+func parseHostLimitsArg(raw interface{}) ([]CrawlHostLimit, error) {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.New("host_limits must be an array of \"host=delay,parallelism\" strings")
+	}
+	limits := make([]CrawlHostLimit, 0, len(items))
+	for _, item := range items {
+		entry, _ := item.(string)
+		limit, err := parseHostLimit(entry)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
+
+// parseHostLimit parses one "host_glob=delay[,parallelism[,random_delay]]" entry.
+func parseHostLimit(entry string) (CrawlHostLimit, error) {
+	glob, spec, ok := strings.Cut(entry, "=")
+	glob = strings.ToLower(strings.TrimSpace(glob))
+	if !ok || glob == "" {
+		return CrawlHostLimit{}, fmt.Errorf("invalid host limit %q: expected host=delay,parallelism", entry)
+	}
+
+	limit := CrawlHostLimit{HostGlob: glob}
+	parts := strings.Split(spec, ",")
+	if len(parts) > 3 {
+		return CrawlHostLimit{}, fmt.Errorf("invalid host limit %q: expected host=delay[,parallelism[,random_delay]]", entry)
+	}
+	var err error
+	if limit.Delay, err = time.ParseDuration(strings.TrimSpace(parts[0])); err != nil {
+		return CrawlHostLimit{}, fmt.Errorf("invalid host limit %q delay: %w", entry, err)
+	}
+	if len(parts) > 1 {
+		if limit.Parallelism, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil {
+			return CrawlHostLimit{}, fmt.Errorf("invalid host limit %q parallelism: %w", entry, err)
+		}
+	}
+	if len(parts) > 2 {
+		if limit.RandomDelay, err = time.ParseDuration(strings.TrimSpace(parts[2])); err != nil {
+			return CrawlHostLimit{}, fmt.Errorf("invalid host limit %q random delay: %w", entry, err)
+		}
+	}
+	return limit, nil
+}
+
+// validateHostLimits rejects host limits with an empty or malformed glob or negative values.
+func validateHostLimits(limits []CrawlHostLimit) error {
+	for _, l := range limits {
+		if l.HostGlob == "" {
+			return errors.New("host limit requires a host glob")
+		} else if l.Delay < 0 || l.RandomDelay < 0 || l.Parallelism < 0 {
+			return fmt.Errorf("host limit %s: delay and parallelism must not be negative", l.HostGlob)
+		} else if err := (&colly.LimitRule{DomainGlob: l.HostGlob}).Init(); err != nil {
+			return fmt.Errorf("host limit %s: invalid glob: %w", l.HostGlob, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestParseHostLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		entry   string
+		want    CrawlHostLimit
+		wantErr string
+	}{
+		{
+			name:  "delay_only",
+			entry: "Origin.example.com=2s",
+			want:  CrawlHostLimit{HostGlob: "origin.example.com", Delay: 2 * time.Second},
+		},
+		{
+			name:  "delay_parallelism",
+			entry: "*.cdn.example.com=0s,8",
+			want:  CrawlHostLimit{HostGlob: "*.cdn.example.com", Parallelism: 8},
+		},
+		{
+			name:  "random_delay",
+			entry: "api.example.com = 500ms, 2, 250ms",
+			want:  CrawlHostLimit{HostGlob: "api.example.com", Delay: 500 * time.Millisecond, Parallelism: 2, RandomDelay: 250 * time.Millisecond},
+		},
+		{name: "missing_equals", entry: "example.com", wantErr: "expected host=delay"},
+		{name: "empty_host", entry: "=1s", wantErr: "expected host=delay"},
+		{name: "bad_delay", entry: "example.com=fast", wantErr: "delay"},
+		{name: "bad_parallelism", entry: "example.com=1s,many", wantErr: "parallelism"},
+		{name: "too_many_parts", entry: "example.com=1s,1,1s,1", wantErr: "random_delay"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseHostLimit(tt.entry)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateHostLimits(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateHostLimits([]CrawlHostLimit{{HostGlob: "*.example.com", Delay: time.Second}}))
+	require.ErrorContains(t, validateHostLimits([]CrawlHostLimit{{Delay: time.Second}}), "requires a host glob")
+	require.ErrorContains(t, validateHostLimits([]CrawlHostLimit{{HostGlob: "a.com", Parallelism: -1}}), "must not be negative")
+	require.ErrorContains(t, validateHostLimits([]CrawlHostLimit{{HostGlob: "[a.com"}}), "invalid glob")
+}
+
+func TestCollyBackend_HostLimits(t *testing.T) {
+	t.Parallel()
+
+	const slowDelay = 200 * time.Millisecond
+	var mu sync.Mutex
+	requestTimes := make(map[string][]time.Time) // host -> request times
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestTimes[r.Host] = append(requestTimes[r.Host], time.Now())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			for i := range 3 {
+				_, _ = fmt.Fprintf(w, `<a href="/p%d">p</a>`, i)
+			}
+		}
+	})
+	slow := httptest.NewServer(handler)
+	t.Cleanup(slow.Close)
+	fast := httptest.NewServer(handler)
+	t.Cleanup(fast.Close)
+	slowURL, err := url.Parse(slow.URL)
+	require.NoError(t, err)
+	fastURL, err := url.Parse(fast.URL)
+	require.NoError(t, err)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: slow.URL + "/"}, {URL: fast.URL + "/"}},
+		Delay:           time.Millisecond,
+		HostLimits:      []CrawlHostLimit{{HostGlob: slowURL.Host, Delay: slowDelay, Parallelism: 1}},
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	minGap := func(times []time.Time) time.Duration {
+		slices.SortFunc(times, time.Time.Compare)
+		gap := time.Duration(1<<63 - 1)
+		for i := 1; i < len(times); i++ {
+			gap = min(gap, times[i].Sub(times[i-1]))
+		}
+		return gap
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, requestTimes[slowURL.Host], 4)
+	require.Len(t, requestTimes[fastURL.Host], 4)
+	assert.GreaterOrEqual(t, minGap(requestTimes[slowURL.Host]), slowDelay-20*time.Millisecond)
+	assert.Less(t, minGap(requestTimes[fastURL.Host]), slowDelay/2)
+}
@@ -0,0 +1,588 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http/cookiejar"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-appsec/toolbox/sectool/service/store"
+)
+
+const (
+	crawlLogFile    = "crawl_flows.log"
+	crawlLogCompact = "crawl_flows.compact"
+	crawlLogKeyFile = "crawl_flows.key"
+
+	// Records a compaction would drop before the log is compacted while open, once they are
+	// also at least half of it
+	crawlLogCompactMin = 1024
+
+	crawlLogSession       = "session"        // session created or changed state
+	crawlLogFlow          = "flow"           // flow captured
+	crawlLogCursors       = "cursors"        // flow cursors advanced by a poll
+	crawlLogDeleteFlows   = "delete_flows"   // flows removed from a session
+	crawlLogDeleteSession = "delete_session" // session and its flows removed
+)
+
+// crawlLogRecord is one entry of the crawl flow log.
+type crawlLogRecord struct {
+	Kind      string            `msgpack:"k"`
+	Session   *CrawlSessionInfo `msgpack:"s,omitempty"`
+	Resume    *crawlResumeState `msgpack:"r,omitempty"` // with Session
+	Cursors   *crawlCursors     `msgpack:"c,omitempty"` // with Session, or alone with SessionID
+	Flow      *CrawlFlow        `msgpack:"f,omitempty"`
+	SessionID string            `msgpack:"sid,omitempty"`
+	FlowIDs   []string          `msgpack:"fids,omitempty"`
+}
+
+// crawlResumeState is what a restored session needs to be resumed: its options and scope,
+// and its frontier as of the last session record.
+type crawlResumeState struct {
+	Opts           CrawlOptions      `msgpack:"o"`
+	AllowedDomains []string          `msgpack:"d"`
+	SeedURLs       []string          `msgpack:"u,omitempty"`
+	SeedHeaders    map[string]string `msgpack:"h,omitempty"`
+	URLsSeen       []string          `msgpack:"seen,omitempty"`
+	URLsRequested  []string          `msgpack:"req,omitempty"`
+	HostRequests   map[string]int    `msgpack:"hr,omitempty"` // for MaxRequests, MaxHosts and MaxPagesPerHost
+	// Depth and found-on page of the discovered URLs not yet requested
+	LinkOrigins map[string]crawlLinkOrigin `msgpack:"lo,omitempty"`
+}
+
+// crawlLinkOrigin is where a discovered link was found, so a link requeued on resume keeps
+// its depth for MaxDepth and its page for FoundOn and the Referer.
+type crawlLinkOrigin struct {
+	Depth   int    `msgpack:"d"`
+	FoundOn string `msgpack:"f,omitempty"`
+}
+
+// crawlCursors are a session's since=last positions: the next unread index in its flows.
+type crawlCursors struct {
+	Last  int            `msgpack:"l,omitempty"`
+	Named map[string]int `msgpack:"n,omitempty"`
+}
+
+// persistedSession is a session rebuilt from the crawl flow log.
+type persistedSession struct {
+	info    CrawlSessionInfo
+	resume  *crawlResumeState // nil for records written before resume state was persisted
+	cursors crawlCursors
+	flows   []*CrawlFlow
+}
+
+// crawlFlowLog persists crawl sessions and their flows to an append-only file so they
+// survive a service restart. Records are length-prefixed msgpack sealed with AES-GCM, since
+// they hold seed credentials and full request and response bodies; deletions and updated
+// session state are appended as records too, and dropped when the file is compacted on the
+// next open or once enough of it is dead.
+type crawlFlowLog struct {
+	path string
+	gcm  cipher.AEAD
+
+	mu     sync.Mutex
+	file   *os.File
+	closed bool
+
+	// Record counts since the last compaction, to compact once dead records pile up
+	records     int
+	dead        int             // records the next compaction drops
+	stateLogged map[string]bool // session ID -> a session or cursors record is live
+	flowsLogged map[string]int  // session ID -> live flow records
+}
+
+// openCrawlFlowLog replays the log in dir, rewrites it without deleted entries, and opens
+// it for appending. A record cut short by a crash ends the replay.
+func openCrawlFlowLog(dir string) (*crawlFlowLog, []*persistedSession, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, nil, err
+	}
+	gcm, err := loadCrawlLogKey(filepath.Join(dir, crawlLogKeyFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("crawl flow log key: %w", err)
+	}
+	l := &crawlFlowLog{path: filepath.Join(dir, crawlLogFile), gcm: gcm}
+	sessions, err := l.compact()
+	if err != nil {
+		return nil, nil, err
+	}
+	return l, sessions, nil
+}
+
+// compact replays the log and rewrites it with only the live sessions and flows, switching
+// appends to the rewritten file. Caller must hold l.mu once the log is open.
+func (l *crawlFlowLog) compact() ([]*persistedSession, error) {
+	sessions, err := replayCrawlFlowLog(l.path, l.gcm)
+	if err != nil {
+		return nil, err
+	}
+
+	compactPath := filepath.Join(filepath.Dir(l.path), crawlLogCompact)
+	file, err := os.OpenFile(compactPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	l.records, l.dead = 0, 0
+	l.stateLogged, l.flowsLogged = make(map[string]bool), make(map[string]int)
+	for _, ps := range sessions {
+		err = l.writeTo(file, crawlLogRecord{Kind: crawlLogSession, Session: &ps.info, Resume: ps.resume, Cursors: &ps.cursors})
+		for _, flow := range ps.flows {
+			if err != nil {
+				break
+			}
+			err = l.writeTo(file, crawlLogRecord{Kind: crawlLogFlow, Flow: flow})
+		}
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("compact crawl flow log: %w", err)
+		}
+	}
+	if err := os.Rename(compactPath, l.path); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if l.file != nil {
+		_ = l.file.Close()
+	}
+	l.file = file
+	return sessions, nil
+}
+
+// loadCrawlLogKey reads the log's AES-256 key from path, creating it (0600) on first use.
+// The key sits beside the log, so the encryption only keeps the log's secrets out of
+// backups and copies of the log alone, not from anyone who can read the persist directory.
+func loadCrawlLogKey(path string) (cipher.AEAD, error) {
+	key, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		} else if err := os.WriteFile(path, key, 0600); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else if len(key) != 32 {
+		return nil, fmt.Errorf("%s holds %d bytes, want 32", path, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// replayCrawlFlowLog reads the sessions and flows left after applying every record in path.
+func replayCrawlFlowLog(path string, gcm cipher.AEAD) ([]*persistedSession, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var ordered []*persistedSession
+	byID := make(map[string]*persistedSession)
+	r := bufio.NewReader(f)
+	for {
+		var size uint32
+		if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Printf("crawler: warning: crawl flow log %s ends with a partial record", path)
+			}
+			break
+		}
+		blob := make([]byte, size)
+		var rec crawlLogRecord
+		if _, err := io.ReadFull(r, blob); err != nil {
+			log.Printf("crawler: warning: crawl flow log %s ends with a partial record", path)
+			break
+		} else if len(blob) < gcm.NonceSize() {
+			log.Printf("crawler: warning: stopping crawl flow log replay at truncated record")
+			break
+		} else if blob, err = gcm.Open(nil, blob[:gcm.NonceSize()], blob[gcm.NonceSize():], nil); err != nil {
+			log.Printf("crawler: warning: stopping crawl flow log replay at undecryptable record: %v", err)
+			break
+		} else if err := store.Deserialize(blob, &rec); err != nil {
+			log.Printf("crawler: warning: stopping crawl flow log replay at corrupt record: %v", err)
+			break
+		}
+
+		switch rec.Kind {
+		case crawlLogSession:
+			if rec.Session == nil {
+				continue
+			}
+			ps := byID[rec.Session.ID]
+			if ps != nil {
+				ps.info, ps.resume = *rec.Session, rec.Resume
+			} else {
+				ps = &persistedSession{info: *rec.Session, resume: rec.Resume}
+				byID[ps.info.ID] = ps
+				ordered = append(ordered, ps)
+			}
+			if rec.Cursors != nil {
+				ps.cursors = *rec.Cursors
+			}
+		case crawlLogFlow:
+			if rec.Flow == nil {
+				continue
+			} else if ps := byID[rec.Flow.SessionID]; ps != nil {
+				ps.flows = append(ps.flows, rec.Flow)
+			}
+		case crawlLogCursors:
+			if ps := byID[rec.SessionID]; ps != nil && rec.Cursors != nil {
+				ps.cursors = *rec.Cursors
+			}
+		case crawlLogDeleteFlows:
+			if ps := byID[rec.SessionID]; ps != nil {
+				remove := make(map[string]bool, len(rec.FlowIDs))
+				for _, id := range rec.FlowIDs {
+					remove[id] = true
+				}
+				var removed []int
+				kept := ps.flows[:0]
+				for i, flow := range ps.flows {
+					if remove[flow.ID] {
+						removed = append(removed, i)
+					} else {
+						kept = append(kept, flow)
+					}
+				}
+				clear(ps.flows[len(kept):])
+				ps.flows = kept
+				ps.cursors.Last = shiftCursor(removed, ps.cursors.Last)
+				for name, idx := range ps.cursors.Named {
+					ps.cursors.Named[name] = shiftCursor(removed, idx)
+				}
+			}
+		case crawlLogDeleteSession:
+			if ps := byID[rec.SessionID]; ps != nil {
+				delete(byID, rec.SessionID)
+				ps.flows = nil
+			}
+		}
+	}
+
+	sessions := make([]*persistedSession, 0, len(byID))
+	for _, ps := range ordered {
+		if byID[ps.info.ID] == ps {
+			sessions = append(sessions, ps)
+		}
+	}
+	return sessions, nil
+}
+
+// write appends rec to the log, compacting it once enough records are dead.
+func (l *crawlFlowLog) write(rec crawlLogRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return store.ErrClosed
+	} else if err := l.writeTo(l.file, rec); err != nil {
+		return err
+	}
+
+	if l.dead >= crawlLogCompactMin && 2*l.dead >= l.records {
+		if _, err := l.compact(); err != nil {
+			log.Printf("crawler: warning: compacting crawl flow log: %v", err)
+		}
+	}
+	return nil
+}
+
+// writeTo seals rec onto file and counts it. Caller must hold l.mu once the log is open.
+func (l *crawlFlowLog) writeTo(file *os.File, rec crawlLogRecord) error {
+	blob, err := store.Serialize(rec)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, l.gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	sealed := l.gcm.Seal(nonce, nonce, blob, nil)
+	buf := make([]byte, 4, 4+len(sealed))
+	binary.LittleEndian.PutUint32(buf, uint32(len(sealed)))
+	buf = append(buf, sealed...)
+	if _, err := file.Write(buf); err != nil {
+		return err
+	}
+
+	// Track which records compaction would drop: all but the last session state, deleted
+	// flows, and everything of a deleted session, deletion records included
+	l.records++
+	switch rec.Kind {
+	case crawlLogSession, crawlLogCursors:
+		id := rec.SessionID
+		if rec.Session != nil {
+			id = rec.Session.ID
+		}
+		if l.stateLogged[id] {
+			l.dead++
+		}
+		l.stateLogged[id] = true
+	case crawlLogFlow:
+		if rec.Flow != nil {
+			l.flowsLogged[rec.Flow.SessionID]++
+		}
+	case crawlLogDeleteFlows:
+		l.dead += 1 + len(rec.FlowIDs)
+		l.flowsLogged[rec.SessionID] -= len(rec.FlowIDs)
+	case crawlLogDeleteSession:
+		l.dead += 1 + l.flowsLogged[rec.SessionID]
+		if l.stateLogged[rec.SessionID] {
+			l.dead++
+		}
+		delete(l.stateLogged, rec.SessionID)
+		delete(l.flowsLogged, rec.SessionID)
+	}
+	return nil
+}
+
+func (l *crawlFlowLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	return l.file.Close()
+}
+
+// persist appends records to the log, doing nothing on a nil log (PersistFlows disabled).
+// Failures are logged; the in-memory sessions stay authoritative.
+func (l *crawlFlowLog) persist(recs ...crawlLogRecord) {
+	if l == nil {
+		return
+	}
+	for _, rec := range recs {
+		if err := l.write(rec); err != nil && !errors.Is(err, store.ErrClosed) {
+			log.Printf("crawler: warning: persisting %s record: %v", rec.Kind, err)
+			return
+		}
+	}
+}
+
+// persistSession records the session's current info and resume state, unless it was
+// deleted. Holds sess.mu.RLock through the write so no record follows the delete record.
+func (l *crawlFlowLog) persistSession(sess *crawlSession) {
+	if l == nil {
+		return
+	}
+	sess.mu.RLock()
+	defer sess.mu.RUnlock()
+	if sess.deleted {
+		return
+	}
+	info := sess.info
+	var resume *crawlResumeState
+	if !sess.restored {
+		resume = &crawlResumeState{
+			Opts:           sess.opts,
+			AllowedDomains: slices.Clone(sess.allowedDomains),
+			SeedURLs:       slices.Clone(sess.seedURLs),
+			SeedHeaders:    maps.Clone(sess.seedHeaders),
+			URLsSeen:       make([]string, 0, len(sess.urlsSeen)),
+			URLsRequested:  make([]string, 0, len(sess.urlsRequested)),
+			HostRequests:   maps.Clone(sess.hostRequests),
+		}
+		for link := range sess.urlsSeen {
+			resume.URLsSeen = append(resume.URLsSeen, link)
+			if origin, ok := sess.linkOrigins[link]; ok && !sess.urlsRequested[link] {
+				if resume.LinkOrigins == nil {
+					resume.LinkOrigins = make(map[string]crawlLinkOrigin)
+				}
+				resume.LinkOrigins[link] = origin
+			}
+		}
+		for link := range sess.urlsRequested {
+			resume.URLsRequested = append(resume.URLsRequested, link)
+		}
+		slices.Sort(resume.URLsSeen)
+		slices.Sort(resume.URLsRequested)
+	}
+	l.persist(crawlLogRecord{Kind: crawlLogSession, Session: &info, Resume: resume, Cursors: sess.cursors()})
+}
+
+// persistFlows records newly captured flows.
+func (l *crawlFlowLog) persistFlows(flows ...*CrawlFlow) {
+	if l == nil {
+		return
+	}
+	recs := make([]crawlLogRecord, len(flows))
+	for i, flow := range flows {
+		recs[i] = crawlLogRecord{Kind: crawlLogFlow, Flow: flow}
+	}
+	l.persist(recs...)
+}
+
+// persistFlows records flows newly captured by the session, unless it was deleted. Caller
+// must hold sess.mu, which keeps the records in flowsOrdered order for the cursors.
+func (sess *crawlSession) persistFlows(flows ...*CrawlFlow) {
+	if !sess.deleted {
+		sess.flowLog.persistFlows(flows...)
+	}
+}
+
+// persistCursors records the session's flow cursors after a poll advanced them, unless it
+// was deleted. Locks sess.mu.
+func (sess *crawlSession) persistCursors() {
+	if sess.flowLog == nil {
+		return
+	}
+	sess.mu.RLock()
+	defer sess.mu.RUnlock()
+	if !sess.deleted {
+		sess.flowLog.persist(crawlLogRecord{Kind: crawlLogCursors, SessionID: sess.info.ID, Cursors: sess.cursors()})
+	}
+}
+
+// cursors snapshots the session's flow cursors. Caller must hold sess.mu.
+func (sess *crawlSession) cursors() *crawlCursors {
+	return &crawlCursors{Last: sess.lastReturnedIdx, Named: maps.Clone(sess.namedCursors)}
+}
+
+// persistDeletedFlows records flows removed from a session.
+func (l *crawlFlowLog) persistDeletedFlows(sessionID string, flowIDs []string) {
+	if len(flowIDs) > 0 {
+		l.persist(crawlLogRecord{Kind: crawlLogDeleteFlows, SessionID: sessionID, FlowIDs: flowIDs})
+	}
+}
+
+// persistDeletedSession records a session removed with its flows.
+func (l *crawlFlowLog) persistDeletedSession(sessionID string) {
+	l.persist(crawlLogRecord{Kind: crawlLogDeleteSession, SessionID: sessionID})
+}
+
+// restoreSessions registers the sessions read from the flow log. Ones that were running
+// when the service stopped are marked stopped; ResumeSession continues them from their
+// persisted frontier. Sessions logged without resume state hold their flows only. The
+// session TTL counts from the restore, so a long downtime does not expire them all at once.
+func (b *CollyBackend) restoreSessions(sessions []*persistedSession) {
+	now := time.Now()
+	for _, ps := range sessions {
+		ctx, cancel := context.WithCancel(context.Background())
+		runDone := make(chan struct{})
+		close(runDone)
+		sess := &crawlSession{
+			info:            ps.info,
+			startedAt:       ps.info.CreatedAt,
+			flowsByID:       make(map[string]*CrawlFlow, len(ps.flows)),
+			urlsSeen:        make(map[string]bool),
+			urlsRequested:   make(map[string]bool),
+			linkOrigins:     make(map[string]crawlLinkOrigin),
+			externalSeen:    make(map[string]bool),
+			routesSeen:      make(map[string]bool),
+			endpointsSeen:   make(map[string]bool),
+			discoveredSeen:  make(map[string]bool),
+			methodChecks:    make(map[string]*methodCheckState),
+			methodCheckKeys: make(map[string]bool),
+			hosts:           make(map[string]bool),
+			skippedHosts:    make(map[string]bool),
+			hostRequests:    make(map[string]int),
+			hostQuotaSkips:  make(map[string]int),
+			slashVariants:   make(map[string]*slashVariant),
+			blockStreaks:    make(map[string]blockStreak),
+			sessionCookies:  make(map[string]map[string]string),
+			varyChecked:     make(map[string]bool),
+			reconnedDomains: make(map[string]bool),
+			flowNotify:      make(chan struct{}),
+			runDone:         runDone,
+			lastActivity:    now, // idle time counts from the restart, not from the last flow
+			lastReturnedIdx: min(ps.cursors.Last, len(ps.flows)),
+			restored:        ps.resume == nil,
+			flowLog:         b.flowLog,
+			ctx:             ctx,
+			cancel:          cancel,
+		}
+		if sess.info.State == crawlStateRunning {
+			sess.info.State = crawlStateStopped
+		}
+		if b.config.Crawler.SearchIndex == nil || *b.config.Crawler.SearchIndex {
+			sess.searchIndex = newFlowSearchIndex()
+		}
+		if ps.resume != nil {
+			b.restoreResumeState(sess, ps.resume)
+		}
+		for name, idx := range ps.cursors.Named {
+			if sess.namedCursors == nil {
+				sess.namedCursors = make(map[string]int, len(ps.cursors.Named))
+			}
+			sess.namedCursors[name] = min(idx, len(ps.flows))
+		}
+		for _, flow := range ps.flows {
+			sess.flowsByID[flow.ID] = flow
+			sess.flowsOrdered = append(sess.flowsOrdered, flow)
+			if sess.searchIndex != nil {
+				sess.searchIndex.add(len(sess.flowsOrdered)-1, flowSearchTokens(flow.Request, flow.Response))
+			}
+		}
+
+		b.sessions[sess.info.ID] = sess
+		if sess.info.Label != "" {
+			b.byLabel[sess.info.Label] = sess.info.ID
+		}
+	}
+	if len(sessions) > 0 {
+		log.Printf("crawler: restored %d sessions from the crawl flow log", len(sessions))
+	}
+}
+
+// restoreResumeState applies persisted options, scope and frontier to a restored session.
+// Path filters were validated when the session was created, so they compile leniently here.
+func (b *CollyBackend) restoreResumeState(sess *crawlSession, rs *crawlResumeState) {
+	sess.opts = rs.Opts
+	sess.allowedDomains = rs.AllowedDomains
+	sess.seedURLs = rs.SeedURLs
+	sess.seedHeaders = rs.SeedHeaders
+	for _, link := range rs.URLsSeen {
+		sess.urlsSeen[link] = true
+	}
+	for _, link := range rs.URLsRequested {
+		sess.urlsRequested[link] = true
+	}
+	maps.Copy(sess.linkOrigins, rs.LinkOrigins)
+	for host, n := range rs.HostRequests {
+		sess.hosts[host] = true
+		sess.hostRequests[host] = n
+		sess.requestCount += n
+	}
+
+	sess.allowedRegexes = globsToRegexes(rs.Opts.AllowedPaths)
+	sess.disallowedRegexes = globsToRegexes(rs.Opts.DisallowedPaths)
+	for _, p := range rs.Opts.AllowedPathsRegex {
+		if re, err := regexp.Compile(p); err == nil {
+			sess.allowedRegexes = append(sess.allowedRegexes, re)
+		}
+	}
+	for _, p := range rs.Opts.DisallowedPathsRegex {
+		if re, err := regexp.Compile(p); err == nil {
+			sess.deniedPathRegexes = append(sess.deniedPathRegexes, re)
+		}
+	}
+
+	sess.refreshTransport = crawlBaseTransport(rs.Opts, b.config.MaxHeaderBytes)
+	if !rs.Opts.NoCookieJar {
+		sess.cookieJar, _ = cookiejar.New(nil)
+		seedCookieJar(sess.cookieJar, rs.SeedURLs, rs.SeedHeaders)
+	}
+	sess.seedSessionCookies(rs.SeedURLs, rs.SeedHeaders)
+	sess.seedSessionCookies(rs.SeedURLs, rs.Opts.Headers)
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func newPersistentCollyBackend(t *testing.T, dir string) *CollyBackend {
+	t.Helper()
+
+	persist := true
+	cfg := config.DefaultConfig()
+	cfg.Crawler.PersistFlows = &persist
+	cfg.Crawler.PersistDir = dir
+	b := NewCollyBackend(cfg, nil, nil)
+	require.NotNil(t, b.flowLog)
+	t.Cleanup(func() { _ = b.Close() })
+	return b
+}
+
+func TestCollyBackend_PersistFlows(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`<a href="/a">a</a><a href="/b">b</a>`))
+		} else {
+			_, _ = w.Write([]byte("page " + r.URL.Path))
+		}
+	}))
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+
+	b := newPersistentCollyBackend(t, dir)
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Label:           "persisted",
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+	require.NoError(t, err)
+	require.Len(t, flows, 3)
+	deleted := flows[2]
+	polled, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{Cursor: "agent", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, polled, 1)
+	require.NoError(t, b.DeleteFlow(t.Context(), deleted.ID))
+	require.NoError(t, b.Close())
+
+	t.Run("restored", func(t *testing.T) {
+		restored := newPersistentCollyBackend(t, dir)
+
+		sessions, err := restored.ListSessions(t.Context(), 0)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, sess.ID, sessions[0].ID)
+		assert.Equal(t, "persisted", sessions[0].Label)
+		assert.Equal(t, crawlStateCompleted, sessions[0].State)
+
+		got, err := restored.ListFlows(t.Context(), "persisted", CrawlListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, flowIDs(flows[:2]), flowIDs(got))
+
+		flow, err := restored.GetFlow(t.Context(), flows[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, flows[1].URL, flow.URL)
+		assert.Equal(t, flows[1].Request, flow.Request)
+		assert.Equal(t, flows[1].Response, flow.Response)
+		_, err = restored.GetFlow(t.Context(), deleted.ID)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		// Cursors survive the restart, shifted past the deleted flow
+		unread, err := restored.ListFlows(t.Context(), sess.ID, CrawlListOptions{Since: sinceLast})
+		require.NoError(t, err)
+		assert.Empty(t, unread)
+		unread, err = restored.ListFlows(t.Context(), sess.ID, CrawlListOptions{Cursor: "agent"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{flows[1].ID}, flowIDs(unread))
+
+		requeued, err := restored.ResumeSession(t.Context(), sess.ID)
+		require.NoError(t, err)
+		assert.Zero(t, requeued)
+	})
+
+	t.Run("without_resume_state", func(t *testing.T) {
+		legacyDir := t.TempDir()
+		l, _, err := openCrawlFlowLog(legacyDir)
+		require.NoError(t, err)
+		l.persist(crawlLogRecord{Kind: crawlLogSession, Session: &CrawlSessionInfo{ID: "legacy", State: crawlStateStopped}})
+		require.NoError(t, l.Close())
+
+		legacy := newPersistentCollyBackend(t, legacyDir)
+		_, err = legacy.ResumeSession(t.Context(), "legacy")
+		require.ErrorContains(t, err, "without resume state")
+	})
+
+	t.Run("older_than_ttl", func(t *testing.T) {
+		oldDir := t.TempDir()
+		l, _, err := openCrawlFlowLog(oldDir)
+		require.NoError(t, err)
+		created := time.Now().Add(-48 * time.Hour)
+		l.persist(crawlLogRecord{Kind: crawlLogSession, Session: &CrawlSessionInfo{ID: "old", State: crawlStateCompleted, CreatedAt: created}})
+		l.persistFlows(&CrawlFlow{ID: "f1", SessionID: "old", DiscoveredAt: created})
+		require.NoError(t, l.Close())
+
+		restored := newPersistentCollyBackend(t, oldDir)
+		assert.Empty(t, restored.expireSessions(time.Hour, time.Now()))
+		_, err = restored.GetStatus(t.Context(), "old")
+		require.NoError(t, err)
+
+		// Untouched restored sessions still expire once idle for the TTL after the restore
+		assert.Equal(t, []string{"old"}, restored.expireSessions(time.Hour, time.Now().Add(2*time.Hour)))
+	})
+
+	t.Run("deleted_session", func(t *testing.T) {
+		sessionDir := t.TempDir()
+		for _, name := range []string{crawlLogFile, crawlLogKeyFile} {
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(sessionDir, name), data, 0600))
+		}
+
+		first := newPersistentCollyBackend(t, sessionDir)
+		_, err = first.DeleteSession(t.Context(), sess.ID, false)
+		require.NoError(t, err)
+		require.NoError(t, first.Close())
+
+		second := newPersistentCollyBackend(t, sessionDir)
+		assert.Empty(t, second.sessions)
+	})
+}
+
+func TestCollyBackend_PersistForceDeletedSession(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	blocked := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`<a href="/slow">slow</a>`))
+			return
+		}
+		blocked <- struct{}{}
+		<-release // answer after the delete so the run persists a flow and its final state
+		_, _ = w.Write([]byte(`<a href="/late">late</a>`))
+	}))
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+
+	b := newPersistentCollyBackend(t, dir)
+	info, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+	select {
+	case <-blocked:
+	case <-time.After(10 * time.Second):
+		t.Fatal("slow page was never requested")
+	}
+	sess, err := b.resolveSession(info.ID)
+	require.NoError(t, err)
+
+	_, err = b.DeleteSession(t.Context(), info.ID, true)
+	require.NoError(t, err)
+	close(release)
+	select {
+	case <-sess.runDone:
+	case <-time.After(10 * time.Second):
+		t.Fatal("deleted session run did not finish")
+	}
+	require.NoError(t, b.Close())
+
+	restored := newPersistentCollyBackend(t, dir)
+	assert.Empty(t, restored.sessions)
+}
+
+func TestCollyBackend_ResumeRestoredSession(t *testing.T) {
+	t.Parallel()
+
+	var blockMu sync.Mutex
+	block := true
+	blocked := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`<a href="/slow">slow</a><a href="/b">b</a><a href="/c">c</a>`))
+		case "/slow":
+			blockMu.Lock()
+			wait := block
+			blockMu.Unlock()
+			if wait { // hold the request open until the close abandons it
+				blocked <- struct{}{}
+				<-r.Context().Done()
+				return
+			}
+			_, _ = w.Write([]byte(`<a href="/d">d</a>`))
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+
+	b := newPersistentCollyBackend(t, dir)
+	info, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		Label:           "restart",
+		Delay:           time.Millisecond,
+		Parallelism:     1,
+		DisallowedPaths: []string{"*/c"},
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+	select {
+	case <-blocked:
+	case <-time.After(10 * time.Second):
+		t.Fatal("slow page was never requested")
+	}
+	require.NoError(t, b.Close())
+	blockMu.Lock()
+	block = false
+	blockMu.Unlock()
+
+	restored := newPersistentCollyBackend(t, dir)
+	status, err := restored.GetStatus(t.Context(), "restart")
+	require.NoError(t, err)
+	assert.Equal(t, crawlStateStopped, status.State)
+
+	requeued, err := restored.ResumeSession(t.Context(), "restart")
+	require.NoError(t, err)
+	assert.Positive(t, requeued)
+	require.Eventually(t, func() bool {
+		status, err := restored.GetStatus(t.Context(), info.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	flows, err := restored.ListFlows(t.Context(), info.ID, CrawlListOptions{})
+	require.NoError(t, err)
+	paths := make(map[string]int)
+	for _, f := range flows {
+		paths[f.Path]++
+		if f.Path == "/slow" { // requeued with the page it was found on
+			assert.Equal(t, server.URL+"/", f.FoundOn)
+		}
+	}
+	// Options survive the restart: /c stays filtered out
+	assert.Equal(t, map[string]int{"/": 1, "/slow": 1, "/b": 1, "/d": 1}, paths)
+}
+
+func TestOpenCrawlFlowLog(t *testing.T) {
+	t.Parallel()
+
+	t.Run("partial_record", func(t *testing.T) {
+		dir := t.TempDir()
+		l, sessions, err := openCrawlFlowLog(dir)
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+		l.persist(crawlLogRecord{Kind: crawlLogSession, Session: &CrawlSessionInfo{ID: "s1", State: crawlStateRunning}})
+		l.persistFlows(&CrawlFlow{ID: "f1", SessionID: "s1", URL: "https://example.com/"})
+		require.NoError(t, l.Close())
+
+		f, err := os.OpenFile(filepath.Join(dir, crawlLogFile), os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		_, err = f.Write([]byte{0xff, 0x00, 0x00, 0x00, 0x01}) // length prefix without its record
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		l, sessions, err = openCrawlFlowLog(dir)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = l.Close() })
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "s1", sessions[0].info.ID)
+		require.Len(t, sessions[0].flows, 1)
+		assert.Equal(t, "f1", sessions[0].flows[0].ID)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		dir := t.TempDir()
+		l, _, err := openCrawlFlowLog(dir)
+		require.NoError(t, err)
+		l.persist(crawlLogRecord{
+			Kind:    crawlLogSession,
+			Session: &CrawlSessionInfo{ID: "s1"},
+			Resume:  &crawlResumeState{SeedHeaders: map[string]string{"Authorization": "Bearer seed-secret"}},
+		})
+		l.persistFlows(&CrawlFlow{ID: "f1", SessionID: "s1", Request: []byte("Cookie: session=flow-secret")})
+		require.NoError(t, l.Close())
+
+		data, err := os.ReadFile(filepath.Join(dir, crawlLogFile))
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "seed-secret")
+		assert.NotContains(t, string(data), "flow-secret")
+		key, err := os.Stat(filepath.Join(dir, crawlLogKeyFile))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), key.Mode().Perm())
+
+		l, sessions, err := openCrawlFlowLog(dir)
+		require.NoError(t, err)
+		require.NoError(t, l.Close())
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "Bearer seed-secret", sessions[0].resume.SeedHeaders["Authorization"])
+		require.Len(t, sessions[0].flows, 1)
+		assert.Equal(t, "Cookie: session=flow-secret", string(sessions[0].flows[0].Request))
+
+		require.NoError(t, os.Remove(filepath.Join(dir, crawlLogKeyFile)))
+		l, sessions, err = openCrawlFlowLog(dir)
+		require.NoError(t, err)
+		require.NoError(t, l.Close())
+		assert.Empty(t, sessions)
+	})
+
+	t.Run("compacted", func(t *testing.T) {
+		dir := t.TempDir()
+		l, _, err := openCrawlFlowLog(dir)
+		require.NoError(t, err)
+		l.persist(crawlLogRecord{Kind: crawlLogSession, Session: &CrawlSessionInfo{ID: "s1"}})
+		l.persist(crawlLogRecord{Kind: crawlLogSession, Session: &CrawlSessionInfo{ID: "s2"}})
+		l.persistFlows(&CrawlFlow{ID: "f1", SessionID: "s1"}, &CrawlFlow{ID: "f2", SessionID: "s1"}, &CrawlFlow{ID: "f3", SessionID: "s2"})
+		l.persistDeletedFlows("s1", []string{"f1"})
+		l.persistDeletedSession("s2")
+		require.NoError(t, l.Close())
+		before, err := os.Stat(filepath.Join(dir, crawlLogFile))
+		require.NoError(t, err)
+
+		l, sessions, err := openCrawlFlowLog(dir)
+		require.NoError(t, err)
+		require.NoError(t, l.Close())
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "s1", sessions[0].info.ID)
+		require.Len(t, sessions[0].flows, 1)
+		assert.Equal(t, "f2", sessions[0].flows[0].ID)
+
+		after, err := os.Stat(filepath.Join(dir, crawlLogFile))
+		require.NoError(t, err)
+		assert.Less(t, after.Size(), before.Size())
+	})
+
+	t.Run("compacted_while_open", func(t *testing.T) {
+		dir := t.TempDir()
+		l, _, err := openCrawlFlowLog(dir)
+		require.NoError(t, err)
+		l.persist(crawlLogRecord{Kind: crawlLogSession, Session: &CrawlSessionInfo{ID: "s1"}})
+		l.persistFlows(&CrawlFlow{ID: "f1", SessionID: "s1"})
+		for i := 1; i <= crawlLogCompactMin+10; i++ {
+			l.persist(crawlLogRecord{Kind: crawlLogCursors, SessionID: "s1", Cursors: &crawlCursors{Last: i}})
+		}
+		assert.Less(t, l.records, 20) // superseded cursor records dropped
+		require.NoError(t, l.Close())
+
+		l, sessions, err := openCrawlFlowLog(dir)
+		require.NoError(t, err)
+		require.NoError(t, l.Close())
+		require.Len(t, sessions, 1)
+		assert.Equal(t, crawlLogCompactMin+10, sessions[0].cursors.Last)
+		require.Len(t, sessions[0].flows, 1)
+		assert.Equal(t, "f1", sessions[0].flows[0].ID)
+	})
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+// preflightTimeout bounds the seed fetch made before a crawl session is created.
+const preflightTimeout = 15 * time.Second
+
+// loginPathRe matches paths of typical login pages and SSO entry points.
+var loginPathRe = regexp.MustCompile(`(?i)/(?:log-?in|sign-?in|sso|auth|authorize|oauth2?|saml2?|cas)(?:[/?.;]|$)`)
+
+// preflightHeaders returns the headers the crawler would send to seedURL: seed flow
+// headers, then custom headers, then per-domain headers matching the seed host.
+func preflightHeaders(seedURL string, seedHeaders map[string]string, opts CrawlOptions) map[string]string {
+	headers := make(map[string]string, len(seedHeaders)+len(opts.Headers))
+	for k, v := range seedHeaders {
+		headers[k] = v
+	}
+	for k, v := range opts.Headers {
+		headers[k] = v
+	}
+	if u, err := url.Parse(seedURL); err == nil {
+		applyDomainHeaders(opts.DomainHeaders, u.Hostname(), func(k, v string) {
+			headers[k] = v
+		})
+	}
+	return headers
+}
+
+// preflightSeed fetches seedURL, following redirects, and returns an error when it cannot
+// be reached or answers with an authentication wall, so a crawl that could only come back
+// empty fails at creation instead. With refreshesToken a 401 is accepted, since the crawl
+// fetches a new token when the seed rejects an expired one.
+func preflightSeed(ctx context.Context, transport http.RoundTripper, seedURL string, headers map[string]string, refreshesToken bool) error {
+	ctx, cancel := context.WithTimeout(ctx, preflightTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, seedURL, nil)
+	if err != nil {
+		return fmt.Errorf("seed preflight: %w", err)
+	}
+	req.Header.Set("User-Agent", config.UserAgent())
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return fmt.Errorf("seed %s is unreachable: %w (disable the seed preflight to crawl anyway)", seedURL, err)
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+
+	if refreshesToken && resp.StatusCode == http.StatusUnauthorized {
+		return nil
+	} else if reason := authWallReason(req.URL, resp); reason != "" {
+		return fmt.Errorf("seed %s is behind an auth wall: %s; supply credentials with headers or a seed flow, or disable the seed preflight", seedURL, reason)
+	}
+	return nil
+}
+
+// authWallReason describes why resp looks like an authentication wall, or returns "" if not.
+func authWallReason(seed *url.URL, resp *http.Response) string {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		if challenge := resp.Header.Get("WWW-Authenticate"); challenge != "" {
+			scheme, _, _ := strings.Cut(challenge, " ")
+			return "HTTP 401 requesting " + scheme + " authentication"
+		}
+		return "HTTP 401 Unauthorized"
+	case http.StatusProxyAuthRequired:
+		return "HTTP 407 Proxy Authentication Required"
+	}
+
+	// A seed that redirects to a login page, unless the seed is itself a login page
+	final := resp.Request.URL
+	if final.String() != seed.String() && loginPathRe.MatchString(final.Path) && !loginPathRe.MatchString(seed.Path) {
+		return "redirected to login page " + final.String()
+	}
+	return ""
+}
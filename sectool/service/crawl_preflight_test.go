@@ -0,0 +1,146 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestPreflightSeed(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/basic":
+			w.Header().Set("WWW-Authenticate", `Basic realm="admin"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/bearer":
+			if r.Header.Get("Authorization") != "Bearer ok" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		case "/app":
+			http.Redirect(w, r, "/account/login?next=/app", http.StatusFound)
+		case "/moved":
+			http.Redirect(w, r, "/new-home", http.StatusMovedPermanently)
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		name         string
+		url          string
+		headers      map[string]string
+		tokenRefresh bool
+		wantErr      string
+	}{
+		{name: "ok", url: server.URL + "/"},
+		{name: "unreachable", url: closedURL + "/", wantErr: "unreachable"},
+		{name: "basic_auth", url: server.URL + "/basic", wantErr: "HTTP 401 requesting Basic authentication"},
+		{name: "unauthorized", url: server.URL + "/bearer", wantErr: "HTTP 401 Unauthorized"},
+		{name: "authorized_with_headers", url: server.URL + "/bearer", headers: map[string]string{"Authorization": "Bearer ok"}},
+		{name: "unauthorized_with_token_refresh", url: server.URL + "/bearer", headers: map[string]string{"Authorization": "Bearer expired"}, tokenRefresh: true},
+		{name: "login_redirect_with_token_refresh", url: server.URL + "/app", tokenRefresh: true, wantErr: "redirected to login page"},
+		{name: "login_redirect", url: server.URL + "/app", wantErr: "redirected to login page"},
+		{name: "login_seed", url: server.URL + "/login"},
+		{name: "other_redirect", url: server.URL + "/moved"},
+		{name: "server_error_is_reachable", url: server.URL + "/error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := preflightSeed(t.Context(), http.DefaultTransport, tt.url, tt.headers, tt.tokenRefresh)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPreflightHeaders(t *testing.T) {
+	t.Parallel()
+
+	headers := preflightHeaders("https://API.example.com/", map[string]string{"Cookie": "seed", "X-A": "seed"}, CrawlOptions{
+		Headers: map[string]string{"X-A": "custom"},
+		DomainHeaders: map[string]map[string]string{
+			"*.example.com": {"Authorization": "Bearer t"},
+			"other.test":    {"X-Other": "1"},
+		},
+	})
+	assert.Equal(t, map[string]string{"Cookie": "seed", "X-A": "custom", "Authorization": "Bearer t"}, headers)
+
+	// Overlapping globs apply in sorted order, as on crawl requests, so the later glob wins
+	overlapping := CrawlOptions{DomainHeaders: map[string]map[string]string{
+		"api.example.com": {"Authorization": "Bearer api"},
+		"*.example.com":   {"Authorization": "Bearer wildcard"},
+		"*":               {"Authorization": "Bearer any"},
+	}}
+	for range 20 {
+		headers := preflightHeaders("https://api.example.com/", nil, overlapping)
+		assert.Equal(t, "Bearer api", headers["Authorization"])
+	}
+}
+
+func TestCollyBackend_PreflightSeed(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	t.Run("fails_creation", func(t *testing.T) {
+		_, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+			PreflightSeed:   true,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "auth wall")
+
+		sessions, err := b.ListSessions(t.Context(), 0)
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, sess.ID)
+	})
+
+	t.Run("token_refresh", func(t *testing.T) {
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Headers:         map[string]string{"Authorization": "Bearer expired"},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+			PreflightSeed:   true,
+			TokenRefresh:    &TokenRefresh{URL: server.URL + "/token", TokenPath: "access_token"},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, sess.ID)
+	})
+}
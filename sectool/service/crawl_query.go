@@ -0,0 +1,41 @@
+package service
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// stripQueryParams removes query parameters whose names match any of the case-insensitive
+// globs, keeping the order and encoding of the rest. It returns link unchanged, and
+// false, when nothing was removed or link does not parse.
+func stripQueryParams(link string, patterns []string) (string, bool) {
+	if len(patterns) == 0 || !strings.Contains(link, "?") {
+		return link, false
+	}
+	u, err := url.Parse(link)
+	if err != nil || u.RawQuery == "" {
+		return link, false
+	}
+
+	pairs := strings.Split(u.RawQuery, "&")
+	kept := pairs[:0:0]
+	for _, pair := range pairs {
+		rawName, _, _ := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(rawName)
+		if err != nil {
+			name = rawName
+		}
+		name = strings.ToLower(name)
+		if !slices.ContainsFunc(patterns, func(p string) bool { return matchesGlob(name, strings.ToLower(p)) }) {
+			kept = append(kept, pair)
+		}
+	}
+	if len(kept) == len(pairs) {
+		return link, false
+	}
+
+	u.RawQuery = strings.Join(kept, "&")
+	u.ForceQuery = false
+	return u.String(), true
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestStripQueryParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		link     string
+		patterns []string
+		want     string
+		stripped bool
+	}{
+		{"no_patterns", "https://a.test/p?utm_source=x", nil, "https://a.test/p?utm_source=x", false},
+		{"no_query", "https://a.test/p", []string{"utm_*"}, "https://a.test/p", false},
+		{"glob", "https://a.test/p?id=1&utm_source=x&utm_medium=y", []string{"utm_*"}, "https://a.test/p?id=1", true},
+		{"case_insensitive", "https://a.test/p?FBCLID=1&id=2", []string{"fbclid"}, "https://a.test/p?id=2", true},
+		{"all_removed", "https://a.test/p?sessionid=abc", []string{"sessionid"}, "https://a.test/p", true},
+		{"order_and_encoding_kept", "https://a.test/p?b=%2F&ref=1&a=x+y", []string{"ref"}, "https://a.test/p?b=%2F&a=x+y", true},
+		{"escaped_name", "https://a.test/p?utm%5Fsource=x&id=1", []string{"utm_*"}, "https://a.test/p?id=1", true},
+		{"fragment_kept", "https://a.test/p?ref=1&id=2#top", []string{"ref"}, "https://a.test/p?id=2#top", true},
+		{"no_match", "https://a.test/p?id=1", []string{"utm_*"}, "https://a.test/p?id=1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, stripped := stripQueryParams(tt.link, tt.patterns)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.stripped, stripped)
+		})
+	}
+}
+
+func TestCollyBackend_IgnoreQueryParams(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`<a href="/p?id=1&utm_source=a">a</a><a href="/p?UTM_Source=b&id=1">b</a><a href="/q">q</a>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:             []CrawlSeed{{URL: server.URL + "/"}},
+		Delay:             time.Millisecond,
+		IgnoreRobotsTxt:   true,
+		IgnoreQueryParams: []string{"utm_*"},
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+	require.NoError(t, err)
+
+	originals := make(map[string]string)
+	for _, f := range flows {
+		originals[f.Path] = f.OriginalURL
+	}
+	assert.Equal(t, map[string]string{
+		"/":       "",
+		"/p?id=1": server.URL + "/p?id=1&utm_source=a",
+		"/q":      "",
+	}, originals)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/go-appsec/toolbox/sectool/service/ids"
+)
+
+// maxCrawlRedirectHops caps the redirect hops kept for one crawl request. The client gives
+// up after 10 redirects as well, so this only bounds memory should that ever change.
+const maxCrawlRedirectHops = 10
+
+// redirectLocation returns the resolved Location of a captured 3xx response, or "" when
+// the response is not a redirect.
+func (d *capturedData) redirectLocation() string {
+	if status, _ := parseResponseStatus(d.RespHeaders); status < 300 || status >= 400 {
+		return ""
+	}
+	location := extractHeader(string(d.RespHeaders), "Location")
+	base, err := url.Parse(d.URL)
+	if location == "" || err != nil {
+		return ""
+	}
+	target, err := base.Parse(location)
+	if err != nil {
+		return ""
+	}
+	return target.String()
+}
+
+// redirectHops returns the redirect hops that led to target under captureID. The client
+// re-sends the capture header on each redirect, so the hop stored for captureID so far is
+// the redirect being followed now.
+func (t *capturingTransport) redirectHops(captureID string, target *url.URL) []*capturedData {
+	stored, ok := t.session.captureStore.Load(captureID)
+	if !ok {
+		return nil
+	}
+	prev := stored.(*capturedData)
+	if prev.redirectLocation() != target.String() {
+		return nil
+	} else if len(prev.Redirects) >= maxCrawlRedirectHops {
+		return prev.Redirects
+	}
+	hop := *prev
+	hop.Redirects = nil
+	return append(prev.Redirects[:len(prev.Redirects):len(prev.Redirects)], &hop)
+}
+
+// redirectHopFlows builds a flow for each redirect hop followed before data's response,
+// linked through RedirectedFrom. The final flow is linked to the last hop by the caller.
+func (sess *crawlSession) redirectHopFlows(data *capturedData, foundOn string, depth int) []*CrawlFlow {
+	flows := make([]*CrawlFlow, 0, len(data.Redirects))
+	for _, hop := range data.Redirects {
+		u, err := url.Parse(hop.URL)
+		if err != nil {
+			continue
+		}
+		flowPath := u.Path
+		if u.RawQuery != "" {
+			flowPath += "?" + u.RawQuery
+		}
+		status, _ := parseResponseStatus(hop.RespHeaders)
+		flow := &CrawlFlow{
+			ID:             ids.Generate(ids.DefaultLength),
+			SessionID:      sess.info.ID,
+			URL:            hop.URL,
+			Host:           u.Host,
+			Path:           flowPath,
+			Method:         extractMethod(hop.Request),
+			FoundOn:        foundOn,
+			Depth:          depth,
+			StatusCode:     status,
+			ContentType:    extractHeader(string(hop.RespHeaders), "Content-Type"),
+			ResponseLength: hop.RespBodySize,
+			Request:        hop.Request,
+			Response:       append(hop.RespHeaders, hop.RespBody...),
+			Truncated:      hop.Truncated,
+			Duration:       hop.Duration,
+			DiscoveredAt:   time.Now(),
+			RedirectTo:     hop.redirectLocation(),
+		}
+		flow.HeadersTruncated = hop.HeadersTruncated
+		if len(flows) > 0 {
+			flow.RedirectedFrom = flows[len(flows)-1].ID
+		}
+		flows = append(flows, flow)
+	}
+	return flows
+}
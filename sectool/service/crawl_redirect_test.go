@@ -0,0 +1,99 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestCapturedData_RedirectLocation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		headers string
+		want    string
+	}{
+		{name: "relative", headers: "HTTP/1.1 302 Found\r\nLocation: /next?x=1\r\n\r\n", want: "http://example.com/next?x=1"},
+		{name: "absolute", headers: "HTTP/1.1 301 Moved Permanently\r\nLocation: https://other.example.com/\r\n\r\n", want: "https://other.example.com/"},
+		{name: "no_location", headers: "HTTP/1.1 302 Found\r\n\r\n"},
+		{name: "not_redirect", headers: "HTTP/1.1 200 OK\r\nLocation: /next\r\n\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &capturedData{URL: "http://example.com/dir/page", RespHeaders: []byte(tt.headers)}
+			assert.Equal(t, tt.want, data.redirectLocation())
+		})
+	}
+}
+
+func TestCollyBackend_RedirectChain(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<a href="/start">start</a>`))
+		case "/start":
+			http.Redirect(w, r, "/middle", http.StatusFound)
+		case "/middle":
+			http.Redirect(w, r, "/final", http.StatusMovedPermanently)
+		case "/final":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<p>done</p>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+	require.NoError(t, err)
+	byPath := make(map[string]CrawlFlow)
+	for _, f := range flows {
+		byPath[f.Path] = f
+	}
+	require.Len(t, byPath, 4)
+
+	start, middle, final := byPath["/start"], byPath["/middle"], byPath["/final"]
+	assert.Equal(t, http.StatusFound, start.StatusCode)
+	assert.Equal(t, server.URL+"/middle", start.RedirectTo)
+	assert.Empty(t, start.RedirectedFrom)
+	assert.Equal(t, server.URL+"/", start.FoundOn)
+
+	assert.Equal(t, http.StatusMovedPermanently, middle.StatusCode)
+	assert.Equal(t, server.URL+"/final", middle.RedirectTo)
+	assert.Equal(t, start.ID, middle.RedirectedFrom)
+
+	assert.Equal(t, http.StatusOK, final.StatusCode)
+	assert.Empty(t, final.RedirectTo)
+	assert.Equal(t, middle.ID, final.RedirectedFrom)
+	assert.Equal(t, []string{server.URL + "/start", server.URL + "/middle"}, final.RedirectChain)
+	assert.Contains(t, string(middle.Request), "GET /middle ")
+
+	redirects, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{StatusCodes: parseStatusFilter("3XX")})
+	require.NoError(t, err)
+	assert.Len(t, redirects, 2)
+}
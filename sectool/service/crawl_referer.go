@@ -0,0 +1,108 @@
+package service
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// refererNone disables the Referer header on crawl navigations.
+const refererNone = "none"
+
+var knownReferrerPolicies = map[string]bool{
+	"no-referrer":                     true,
+	"no-referrer-when-downgrade":      true,
+	"origin":                          true,
+	"origin-when-cross-origin":        true,
+	"same-origin":                     true,
+	"strict-origin":                   true,
+	"strict-origin-when-cross-origin": true,
+	"unsafe-url":                      true,
+}
+
+var (
+	metaReferrerRe = regexp.MustCompile(`(?is)<meta\s[^>]*\bname\s*=\s*["']?referrer\b[^>]*>`)
+	metaContentRe  = regexp.MustCompile(`(?is)\bcontent\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+// pageReferrerPolicy returns the referrer policy a page declares, "" when none.
+// As in browsers, a <meta name="referrer"> tag overrides the Referrer-Policy header,
+// and the last recognized token of a comma-separated list wins.
+func pageReferrerPolicy(header http.Header, body []byte) string {
+	var policy string
+	for _, v := range header.Values("Referrer-Policy") {
+		if p := lastKnownPolicy(v); p != "" {
+			policy = p
+		}
+	}
+	for _, tag := range metaReferrerRe.FindAll(body, -1) {
+		if m := metaContentRe.FindSubmatch(tag); m != nil {
+			if p := lastKnownPolicy(string(m[1]) + string(m[2]) + string(m[3])); p != "" {
+				policy = p
+			}
+		}
+	}
+	return policy
+}
+
+func lastKnownPolicy(value string) string {
+	var policy string
+	for _, token := range strings.Split(value, ",") {
+		if token = strings.ToLower(strings.TrimSpace(token)); knownReferrerPolicies[token] {
+			policy = token
+		}
+	}
+	return policy
+}
+
+// refererFor returns the Referer a browser sends navigating from parent to target under
+// policy (the browser default when empty), or "" when it sends none.
+func refererFor(parent, target *url.URL, policy string) string {
+	if parent.Scheme != "http" && parent.Scheme != "https" {
+		return ""
+	}
+	full := *parent
+	full.User = nil
+	full.Fragment = ""
+	full.RawFragment = ""
+	origin := parent.Scheme + "://" + parent.Host + "/"
+	sameOrigin := parent.Scheme == target.Scheme && strings.EqualFold(parent.Host, target.Host)
+	downgrade := parent.Scheme == "https" && target.Scheme != "https"
+
+	switch policy {
+	case "no-referrer":
+		return ""
+	case "unsafe-url":
+		return full.String()
+	case "origin":
+		return origin
+	case "same-origin":
+		if sameOrigin {
+			return full.String()
+		}
+		return ""
+	case "origin-when-cross-origin":
+		if sameOrigin {
+			return full.String()
+		}
+		return origin
+	case "no-referrer-when-downgrade":
+		if downgrade {
+			return ""
+		}
+		return full.String()
+	case "strict-origin":
+		if downgrade {
+			return ""
+		}
+		return origin
+	default: // strict-origin-when-cross-origin
+		if sameOrigin {
+			return full.String()
+		} else if downgrade {
+			return ""
+		}
+		return origin
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestPageReferrerPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+		body   string
+		want   string
+	}{
+		{"none", "", "<html></html>", ""},
+		{"header", "same-origin", "", "same-origin"},
+		{"header_fallback_list", "no-referrer, bogus-policy", "", "no-referrer"},
+		{"header_last_wins", "no-referrer, Unsafe-URL", "", "unsafe-url"},
+		{"meta_overrides_header", "unsafe-url", `<meta name="referrer" content="origin">`, "origin"},
+		{"meta_attribute_order", "", `<META content='no-referrer' NAME=referrer>`, "no-referrer"},
+		{"meta_unknown_ignored", "origin", `<meta name="referrer" content="always">`, "origin"},
+		{"other_meta_ignored", "", `<meta name="description" content="origin">`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			if tt.header != "" {
+				h.Set("Referrer-Policy", tt.header)
+			}
+			assert.Equal(t, tt.want, pageReferrerPolicy(h, []byte(tt.body)))
+		})
+	}
+}
+
+func TestRefererFor(t *testing.T) {
+	t.Parallel()
+
+	const parent = "https://user:pw@a.test/page?q=1#frag"
+	tests := []struct {
+		name   string
+		target string
+		policy string
+		want   string
+	}{
+		{"default_same_origin", "https://a.test/next", "", "https://a.test/page?q=1"},
+		{"default_cross_origin", "https://b.test/", "", "https://a.test/"},
+		{"default_downgrade", "http://a.test/", "", ""},
+		{"no_referrer", "https://a.test/next", "no-referrer", ""},
+		{"origin", "https://a.test/next", "origin", "https://a.test/"},
+		{"same_origin_cross", "https://b.test/", "same-origin", ""},
+		{"origin_when_cross_origin", "https://b.test/", "origin-when-cross-origin", "https://a.test/"},
+		{"no_referrer_when_downgrade", "https://b.test/", "no-referrer-when-downgrade", "https://a.test/page?q=1"},
+		{"strict_origin_downgrade", "http://b.test/", "strict-origin", ""},
+		{"unsafe_url_downgrade", "http://b.test/", "unsafe-url", "https://a.test/page?q=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := url.Parse(parent)
+			require.NoError(t, err)
+			target, err := url.Parse(tt.target)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, refererFor(p, target, tt.policy))
+		})
+	}
+}
+
+func TestCollyBackend_Referer(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	referers := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		referers[r.URL.Path] = r.Referer()
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`<a href="/docs?page=1">docs</a>`))
+		case "/docs":
+			w.Header().Set("Referrer-Policy", "origin")
+			_, _ = w.Write([]byte(`<a href="/private">private</a>`))
+		case "/private":
+			_, _ = w.Write([]byte(`<meta name="referrer" content="no-referrer"><a href="/last">last</a>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	crawl := func(t *testing.T, referer string) (map[string]string, []CrawlFlow) {
+		t.Helper()
+		mu.Lock()
+		clear(referers)
+		mu.Unlock()
+
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+			Referer:         referer,
+		})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+
+		flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+		require.NoError(t, err)
+
+		mu.Lock()
+		defer mu.Unlock()
+		got := make(map[string]string, len(referers))
+		for k, v := range referers {
+			got[k] = v
+		}
+		return got, flows
+	}
+
+	// Subtests share the server's record of Referers, so run them sequentially
+	t.Run("policy_applied", func(t *testing.T) {
+		got, flows := crawl(t, "")
+		assert.Empty(t, got["/"])
+		assert.Equal(t, server.URL+"/", got["/docs"])
+		assert.Equal(t, server.URL+"/", got["/private"]) // origin policy drops the path and query
+		assert.Empty(t, got["/last"])
+
+		policies := make(map[string]string)
+		for _, f := range flows {
+			policies[f.Path] = f.ReferrerPolicy
+		}
+		assert.Equal(t, map[string]string{
+			"/": "", "/docs?page=1": "origin", "/private": "no-referrer", "/last": "",
+		}, policies)
+	})
+
+	t.Run("suppressed", func(t *testing.T) {
+		got, _ := crawl(t, refererNone)
+		assert.Len(t, got, 4)
+		for path, ref := range got {
+			assert.Empty(t, ref, path)
+		}
+	})
+
+	t.Run("fixed", func(t *testing.T) {
+		got, _ := crawl(t, "https://portal.example/")
+		assert.Empty(t, got["/"])
+		assert.Equal(t, "https://portal.example/", got["/docs"])
+		assert.Equal(t, "https://portal.example/", got["/last"])
+	})
+}
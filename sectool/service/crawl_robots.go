@@ -0,0 +1,245 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+const (
+	hintFetchTimeout    = 10 * time.Second
+	maxRobotsBytes      = 512 << 10
+	maxSitemapBytes     = 10 << 20
+	maxSitemapFetches   = 10   // sitemap files fetched per origin, nested indexes included
+	maxSitemapURLs      = 1000 // URLs queued from sitemaps per origin
+	maxHintRedirects    = 10   // matches net/http's default redirect limit
+	discoveredPathRobot = "robots-disallow"
+)
+
+// parseRobotsHints returns the Sitemap URLs and Disallow paths declared in a robots.txt,
+// across all user-agent groups. Empty and root-only Disallow rules are skipped.
+func parseRobotsHints(body []byte) (sitemaps, disallowed []string) {
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "sitemap":
+			if value != "" {
+				sitemaps = append(sitemaps, value)
+			}
+		case "disallow":
+			if value != "" && value != "/" && !seen[value] {
+				seen[value] = true
+				disallowed = append(disallowed, value)
+			}
+		}
+	}
+	return sitemaps, disallowed
+}
+
+// parseSitemap returns the page URLs of a sitemap and the child sitemaps of a sitemap index.
+func parseSitemap(body []byte) (pages, children []string, err error) {
+	var doc struct {
+		URLs []struct {
+			Loc string `xml:"loc"`
+		} `xml:"url"`
+		Sitemaps []struct {
+			Loc string `xml:"loc"`
+		} `xml:"sitemap"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, nil, err
+	}
+	for _, u := range doc.URLs {
+		if loc := strings.TrimSpace(u.Loc); loc != "" {
+			pages = append(pages, loc)
+		}
+	}
+	for _, s := range doc.Sitemaps {
+		if loc := strings.TrimSpace(s.Loc); loc != "" {
+			children = append(children, loc)
+		}
+	}
+	return pages, children, nil
+}
+
+// fetchHint GETs a robots.txt or sitemap, returning the body of a 200 response read up to
+// limit bytes. Gzipped sitemaps are decompressed.
+func fetchHint(ctx context.Context, client *http.Client, target string, limit int64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, hintFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", config.UserAgent())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned HTTP %d", target, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > 2 && body[0] == 0x1f && body[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = zr.Close() }()
+		return io.ReadAll(io.LimitReader(zr, limit))
+	}
+	return body, nil
+}
+
+// hintClient returns the client for robots.txt and sitemap fetches. It follows redirects
+// only to hosts inside the crawl scope, so a hint cannot pull in an off-scope URL.
+func hintClient(transport http.RoundTripper, allowedDomains []string, includeSubdomains bool) *http.Client {
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxHintRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxHintRedirects)
+			} else if !isDomainAllowed(req.URL.String(), allowedDomains, includeSubdomains) {
+				return fmt.Errorf("redirect to out-of-scope host %s", req.URL.Hostname())
+			}
+			return nil
+		},
+	}
+}
+
+// runSeedHintsForSession reads robots.txt and sitemaps for each seed origin, queueing
+// in-scope sitemap URLs and recording robots.txt Disallow paths as discovered paths.
+// Disallowed paths are not visited.
+func (b *CollyBackend) runSeedHintsForSession(ctx context.Context, sess *crawlSession, seedURLs []string) {
+	sess.mu.RLock()
+	includeSubdomains := *b.config.IncludeSubdomains
+	allowedDomains := sess.allowedDomains
+	client := hintClient(sess.refreshTransport, allowedDomains, includeSubdomains)
+	sess.mu.RUnlock()
+
+	origins := make(map[string]bool)
+	var queued, recorded int
+	for _, seed := range seedURLs {
+		u, err := url.Parse(seed)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		origin := u.Scheme + "://" + u.Host
+		if origins[origin] {
+			continue
+		}
+		origins[origin] = true
+
+		sitemaps := []string{origin + "/sitemap.xml"}
+		if body, err := fetchHint(ctx, client, origin+"/robots.txt", maxRobotsBytes); err == nil {
+			declared, disallowed := parseRobotsHints(body)
+			sitemaps = append(declared, sitemaps...)
+			recorded += sess.recordDiscoveredPaths(origin, disallowed)
+		}
+
+		var fetched, originQueued int
+		seen := make(map[string]bool)
+		for len(sitemaps) > 0 && fetched < maxSitemapFetches && originQueued < maxSitemapURLs {
+			sitemapURL := sitemaps[0]
+			sitemaps = sitemaps[1:]
+			if seen[sitemapURL] || !isDomainAllowed(sitemapURL, allowedDomains, includeSubdomains) {
+				continue
+			}
+			seen[sitemapURL] = true
+			fetched++
+
+			body, err := fetchHint(ctx, client, sitemapURL, maxSitemapBytes)
+			if err != nil {
+				continue
+			}
+			pages, children, err := parseSitemap(body)
+			if err != nil {
+				continue
+			}
+			sitemaps = append(sitemaps, children...)
+			for _, page := range pages {
+				if originQueued >= maxSitemapURLs {
+					break
+				} else if ctx.Err() != nil {
+					return
+				} else if isDomainAllowed(page, allowedDomains, includeSubdomains) && sess.queueDiscovered(page) {
+					originQueued++
+				}
+			}
+		}
+		queued += originQueued
+	}
+
+	if queued > 0 || recorded > 0 {
+		log.Printf("crawler: session %s: robots.txt/sitemap queued %d URLs, recorded %d disallowed paths",
+			sess.info.ID, queued, recorded)
+	}
+}
+
+// recordDiscoveredPaths adds robots.txt Disallow paths under origin, skipping ones already
+// recorded, and returns how many were new.
+func (sess *crawlSession) recordDiscoveredPaths(origin string, paths []string) int {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	var added int
+	for _, p := range paths {
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		full := origin + p
+		if sess.discoveredSeen[full] {
+			continue
+		}
+		sess.discoveredSeen[full] = true
+		sess.discoveredPaths = append(sess.discoveredPaths, DiscoveredPath{URL: full, Source: discoveredPathRobot})
+		added++
+	}
+	return added
+}
+
+// queueDiscovered queues a URL found outside of page parsing (recon, sitemaps) unless it
+// was already seen, reporting whether it was queued.
+func (sess *crawlSession) queueDiscovered(link string) bool {
+	sess.mu.Lock()
+	state := sess.info.State
+	seen := sess.urlsSeen[link]
+	if !seen && state == crawlStateRunning {
+		sess.urlsSeen[link] = true
+	}
+	sess.mu.Unlock()
+
+	if seen || state != crawlStateRunning {
+		return false
+	}
+	if sess.frontier != nil {
+		return sess.frontier.push(nil, link)
+	}
+	_ = sess.collector.Visit(link)
+	return true
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRobotsHints(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`# comment line
+User-agent: *
+Disallow: /admin
+disallow: /private/   # trailing comment
+Disallow:
+Disallow: /
+Allow: /public
+SITEMAP: https://example.com/sitemap_index.xml
+
+User-agent: Googlebot
+Disallow: /admin
+Disallow: /search?q=
+`)
+
+	sitemaps, disallowed := parseRobotsHints(body)
+	assert.Equal(t, []string{"https://example.com/sitemap_index.xml"}, sitemaps)
+	assert.Equal(t, []string{"/admin", "/private/", "/search?q="}, disallowed)
+
+	sitemaps, disallowed = parseRobotsHints(nil)
+	assert.Empty(t, sitemaps)
+	assert.Empty(t, disallowed)
+}
+
+func TestParseSitemap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("urlset", func(t *testing.T) {
+		pages, children, err := parseSitemap([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc> https://example.com/a </loc><lastmod>2024-01-01</lastmod></url>
+  <url><loc>https://example.com/b</loc></url>
+  <url><loc></loc></url>
+</urlset>`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/a", "https://example.com/b"}, pages)
+		assert.Empty(t, children)
+	})
+
+	t.Run("index", func(t *testing.T) {
+		pages, children, err := parseSitemap([]byte(`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/sitemap-pages.xml</loc></sitemap>
+</sitemapindex>`))
+		require.NoError(t, err)
+		assert.Empty(t, pages)
+		assert.Equal(t, []string{"https://example.com/sitemap-pages.xml"}, children)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := parseSitemap([]byte("<html><body>not found"))
+		assert.Error(t, err)
+	})
+}
+
+func TestFetchHint(t *testing.T) {
+	t.Parallel()
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte("<urlset></urlset>"))
+	require.NoError(t, zw.Close())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			_, _ = w.Write([]byte("Disallow: /admin\n"))
+		case "/sitemap.xml.gz":
+			w.Header().Set("Content-Type", "application/gzip")
+			_, _ = w.Write(gz.Bytes())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := server.Client()
+
+	body, err := fetchHint(t.Context(), client, server.URL+"/robots.txt", maxRobotsBytes)
+	require.NoError(t, err)
+	assert.Equal(t, "Disallow: /admin\n", string(body))
+
+	body, err = fetchHint(t.Context(), client, server.URL+"/sitemap.xml.gz", maxSitemapBytes)
+	require.NoError(t, err)
+	assert.Equal(t, "<urlset></urlset>", string(body))
+
+	body, err = fetchHint(t.Context(), client, server.URL+"/robots.txt", 8)
+	require.NoError(t, err)
+	assert.Equal(t, "Disallow", string(body))
+
+	_, err = fetchHint(t.Context(), client, server.URL+"/missing", maxRobotsBytes)
+	assert.ErrorContains(t, err, "HTTP 404")
+}
+
+func TestHintClient(t *testing.T) {
+	t.Parallel()
+
+	var offScopeHits atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Host, "localhost:") {
+			offScopeHits.Add(1)
+		}
+		switch r.URL.Path {
+		case "/robots.txt":
+			_, _ = w.Write([]byte("Disallow: /admin\n"))
+		case "/moved":
+			http.Redirect(w, r, "/robots.txt", http.StatusFound)
+		case "/offsite":
+			http.Redirect(w, r, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)+"/robots.txt", http.StatusFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := hintClient(http.DefaultTransport, []string{"127.0.0.1"}, false)
+
+	body, err := fetchHint(t.Context(), client, server.URL+"/moved", maxRobotsBytes)
+	require.NoError(t, err)
+	assert.Equal(t, "Disallow: /admin\n", string(body))
+
+	_, err = fetchHint(t.Context(), client, server.URL+"/offsite", maxRobotsBytes)
+	assert.ErrorContains(t, err, "out-of-scope host localhost")
+	assert.Zero(t, offScopeHits.Load())
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Client-side route kinds, by how the route was declared.
+const (
+	routeKindTable    = "route-table" // path entries in a router config or <Route path=...>
+	routeKindHashbang = "hashbang"    // "#!/view" or "#/view" links
+	routeKindHistory  = "history"     // History API or router navigation calls
+)
+
+// route characters: path segments plus :param and * wildcards used by router tables
+const routeChars = `[A-Za-z0-9_\-.~%:*/]*`
+
+var (
+	routeTableRe    = regexp.MustCompile(`\bpath\s*[:=]\s*\{?\s*["'` + "`" + `](/?` + routeChars + `)["'` + "`" + `]`)
+	routeHashbangRe = regexp.MustCompile(`["'` + "`" + `=]\s*(#!?/` + routeChars + `)`)
+	routeHistoryRe  = regexp.MustCompile(`\b(?:pushState|replaceState)\s*\([^;)]*?,\s*["'` + "`" + `](/` + routeChars + `)["'` + "`" + `]\s*\)|` +
+		`\b(?:navigate|navigateByUrl|(?:router|history)\.(?:push|replace))\s*\(\s*["'` + "`" + `](/` + routeChars + `)["'` + "`" + `]`)
+	routeHasWordRe = regexp.MustCompile(`[A-Za-z0-9]`)
+)
+
+// extractClientRoutes scans JavaScript (or an HTML page with inline scripts) for
+// client-side route declarations: router tables, hash-bang links, and History API
+// navigation. Routes are returned in discovery order, deduplicated.
+func extractClientRoutes(body []byte) []ClientRoute {
+	var routes []ClientRoute
+	seen := make(map[string]bool)
+	add := func(route, kind string) {
+		if !routeHasWordRe.MatchString(route) || strings.Contains(route, "//") || seen[route] {
+			return
+		}
+		seen[route] = true
+		routes = append(routes, ClientRoute{Route: route, Kind: kind})
+	}
+
+	for _, m := range routeTableRe.FindAllSubmatch(body, -1) {
+		route := string(m[1])
+		if !strings.HasPrefix(route, "/") {
+			route = "/" + route // relative child routes (Angular, Vue) are listed from the root
+		}
+		add(route, routeKindTable)
+	}
+	for _, m := range routeHashbangRe.FindAllSubmatch(body, -1) {
+		add(string(m[1]), routeKindHashbang)
+	}
+	for _, m := range routeHistoryRe.FindAllSubmatch(body, -1) {
+		route := string(m[1])
+		if route == "" {
+			route = string(m[2])
+		}
+		add(route, routeKindHistory)
+	}
+	return routes
+}
+
+// recordClientRoutes adds routes not yet seen in the session, attributed to foundOn.
+func (sess *crawlSession) recordClientRoutes(routes []ClientRoute, foundOn string) {
+	if len(routes) == 0 {
+		return
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	for _, r := range routes {
+		if sess.routesSeen[r.Route] {
+			continue
+		}
+		sess.routesSeen[r.Route] = true
+		r.FoundOn = foundOn
+		sess.clientRoutes = append(sess.clientRoutes, r)
+	}
+}
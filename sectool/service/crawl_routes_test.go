@@ -0,0 +1,135 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestExtractClientRoutes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want []ClientRoute
+	}{
+		{
+			name: "route_table",
+			body: `const routes=[{path:"/",component:Home},{path:"/admin",component:Admin},{ path: 'users/:id', component: User },{path:"**",redirectTo:""}];`,
+			want: []ClientRoute{
+				{Route: "/admin", Kind: routeKindTable},
+				{Route: "/users/:id", Kind: routeKindTable},
+			},
+		},
+		{
+			name: "jsx_route",
+			body: `e(Route,{path:"/reports"}),<Route path="/billing/*" element={x}/><Route path={"/internal"} />`,
+			want: []ClientRoute{
+				{Route: "/reports", Kind: routeKindTable},
+				{Route: "/billing/*", Kind: routeKindTable},
+				{Route: "/internal", Kind: routeKindTable},
+			},
+		},
+		{
+			name: "hashbang",
+			body: `location.hash="#!/dashboard";a.href='#/debug/panel';var c="#fff";var d="#/"`,
+			want: []ClientRoute{
+				{Route: "#!/dashboard", Kind: routeKindHashbang},
+				{Route: "#/debug/panel", Kind: routeKindHashbang},
+			},
+		},
+		{
+			name: "history",
+			body: "history.pushState({}, \"\", \"/checkout/confirm\");this.router.navigate('/staff');router.push(`/beta`);navigateByUrl(\"/ops/health\")",
+			want: []ClientRoute{
+				{Route: "/checkout/confirm", Kind: routeKindHistory},
+				{Route: "/staff", Kind: routeKindHistory},
+				{Route: "/beta", Kind: routeKindHistory},
+				{Route: "/ops/health", Kind: routeKindHistory},
+			},
+		},
+		{
+			name: "dedup_first_kind_wins",
+			body: `{path:"/admin"};navigate("/admin")`,
+			want: []ClientRoute{{Route: "/admin", Kind: routeKindTable}},
+		},
+		{
+			name: "ignores_urls_and_plain_strings",
+			body: `fetch("/api/users");x={path:"//cdn.test/a"};y="/admin"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, extractClientRoutes([]byte(tt.body)))
+		})
+	}
+}
+
+func TestCollyBackend_ExtractJSRoutes(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><head><script src="/static/app.js"></script></head><body>
+<a href="#!/profile">Profile</a><script>history.replaceState(null, "", "/welcome")</script></body></html>`))
+		case "/static/app.js":
+			w.Header().Set("Content-Type", "application/javascript")
+			_, _ = w.Write([]byte(`var r=[{path:"/admin/users",component:A},{path:"/"}];`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	runCrawl := func(t *testing.T, extract bool) ([]ClientRoute, int) {
+		t.Helper()
+
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+			ExtractJSRoutes: extract,
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+
+		routes, err := b.ListClientRoutes(t.Context(), sess.ID, 0)
+		require.NoError(t, err)
+		flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+		require.NoError(t, err)
+		return routes, len(flows)
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		routes, flowCount := runCrawl(t, true)
+		assert.Equal(t, 2, flowCount) // page and script; routes are not visited
+		assert.ElementsMatch(t, []ClientRoute{
+			{Route: "#!/profile", Kind: routeKindHashbang, FoundOn: server.URL + "/"},
+			{Route: "/welcome", Kind: routeKindHistory, FoundOn: server.URL + "/"},
+			{Route: "/admin/users", Kind: routeKindTable, FoundOn: server.URL + "/static/app.js"},
+		}, routes)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		routes, flowCount := runCrawl(t, false)
+		assert.Equal(t, 1, flowCount)
+		assert.Empty(t, routes)
+	})
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// safeModeMethods are request methods SafeMode refuses to send.
+var safeModeMethods = map[string]bool{
+	http.MethodDelete: true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+}
+
+// destructiveActionRe matches form actions SafeMode refuses to submit, independent of DisallowedPaths.
+var destructiveActionRe = regexp.MustCompile(`(?i)(delete|remove|destroy|purge|erase|revoke|deactivate|unsubscribe|cancel|reset|log-?out|sign-?out)`)
+
+// methodOverrideFields are form fields frameworks read to replace the submitted method.
+var methodOverrideFields = []string{"_method", "X-HTTP-Method-Override"}
+
+// safeModeRequestBlock returns why SafeMode refuses a request with method, or "" when it may be sent.
+func safeModeRequestBlock(method string) string {
+	if safeModeMethods[strings.ToUpper(method)] {
+		return "method " + strings.ToUpper(method)
+	}
+	return ""
+}
+
+// safeModeFormBlock returns why SafeMode refuses to submit form with data, or "" when it may be submitted.
+func safeModeFormBlock(form DiscoveredForm, data map[string]string) string {
+	if reason := safeModeRequestBlock(form.Method); reason != "" {
+		return reason
+	}
+	for _, field := range methodOverrideFields {
+		if reason := safeModeRequestBlock(data[field]); reason != "" {
+			return reason + " via " + field
+		}
+	}
+	action := form.Action
+	if u, err := url.Parse(form.Action); err == nil {
+		action = u.Path + "?" + u.RawQuery
+	}
+	if m := destructiveActionRe.FindString(action); m != "" {
+		return "destructive action " + strings.ToLower(m)
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestSafeModeFormBlock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		method string
+		action string
+		data   map[string]string
+		want   string
+	}{
+		{"plain_post", "POST", "https://example.com/search", nil, ""},
+		{"get_form", "GET", "/profile?tab=settings", nil, ""},
+		{"put_method", "PUT", "/profile", nil, "method PUT"},
+		{"method_override", "POST", "/items/7", map[string]string{"_method": "delete"}, "method DELETE via _method"},
+		{"destructive_path", "POST", "https://example.com/account/Delete", nil, "destructive action delete"},
+		{"destructive_query", "GET", "/items?action=remove&id=7", nil, "destructive action remove"},
+		{"logout", "POST", "/log-out", nil, "destructive action log-out"},
+		{"host_not_matched", "POST", "https://remove.example.com/search", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := DiscoveredForm{Method: tt.method, Action: tt.action}
+			assert.Equal(t, tt.want, safeModeFormBlock(form, tt.data))
+		})
+	}
+}
+
+func TestCollyBackend_SafeMode(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var posted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.Method == http.MethodPost {
+			mu.Lock()
+			posted = append(posted, r.URL.Path)
+			mu.Unlock()
+			return
+		}
+		_, _ = w.Write([]byte(`<html><body>
+<form method="POST" action="/comment"><input type="text" name="body" value="hi"></form>
+<form method="POST" action="/account/destroy"><input type="hidden" name="id" value="7"></form>
+<form method="POST" action="/items/7"><input type="hidden" name="_method" value="PATCH"></form>
+</body></html>`))
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	crawl := func(t *testing.T, safeMode bool) []string {
+		t.Helper()
+		mu.Lock()
+		posted = nil
+		mu.Unlock()
+
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			DisallowedPaths: []string{"*nothing-matches*"},
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+			SubmitForms:     true,
+			SafeMode:        safeMode,
+		})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), posted...)
+	}
+
+	// Subtests share the server's record of POSTs, so run them sequentially
+	t.Run("enabled", func(t *testing.T) {
+		assert.Equal(t, []string{"/comment"}, crawl(t, true))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"/comment", "/account/destroy", "/items/7"}, crawl(t, false))
+	})
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"log"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+// findingSensitiveData marks a response body matching a SecretPatterns rule, such as a
+// leaked AWS key or private key; the matches are recorded in CrawlFlow.SensitiveData.
+const findingSensitiveData = "sensitive-data"
+
+const (
+	maxSensitiveMatches = 20 // per flow, so a data dump cannot grow a flow without bound
+	sensitiveContextLen = 24 // bytes of context kept on each side of a match in its snippet
+)
+
+// secretRule is a compiled config.SecretPattern.
+type secretRule struct {
+	name       string
+	re         *regexp.Regexp
+	minEntropy float64
+	luhn       bool
+}
+
+// compileSecretPatterns compiles the configured patterns, logging and skipping invalid ones.
+func compileSecretPatterns(patterns []config.SecretPattern) []secretRule {
+	rules := make([]secretRule, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p.Regex)
+		if err != nil || p.Name == "" {
+			log.Printf("crawler: warning: skipping secret pattern %q: invalid regex or missing name: %v", p.Name, err)
+			continue
+		}
+		rules = append(rules, secretRule{name: p.Name, re: re, minEntropy: p.MinEntropy, luhn: p.Luhn})
+	}
+	return rules
+}
+
+// scanSensitiveData returns the rule matches in body, in rule order and at most
+// maxSensitiveMatches. A value matched more than once by a rule is reported once.
+func scanSensitiveData(rules []secretRule, body []byte) []SensitiveMatch {
+	var matches []SensitiveMatch
+	for _, rule := range rules {
+		seen := make(map[string]bool)
+		for _, loc := range rule.re.FindAllSubmatchIndex(body, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 { // the first group is the secret
+				start, end = loc[2], loc[3]
+			}
+			secret := string(body[start:end])
+			if seen[secret] ||
+				(rule.minEntropy > 0 && shannonEntropy(secret) < rule.minEntropy) ||
+				(rule.luhn && !luhnValid(secret)) {
+				continue
+			}
+			seen[secret] = true
+			matches = append(matches, SensitiveMatch{
+				Pattern: rule.name,
+				Offset:  start,
+				Snippet: sensitiveSnippet(body, start, end),
+			})
+			if len(matches) >= maxSensitiveMatches {
+				return matches
+			}
+		}
+	}
+	return matches
+}
+
+// shannonEntropy returns the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	var total int
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+	var entropy float64
+	for _, c := range counts {
+		p := float64(c) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum, ignoring separators.
+func luhnValid(s string) bool {
+	var sum, digits int
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		} else if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits >= 12 && sum%10 == 0
+}
+
+// redactSecret masks the middle of a secret, keeping a few characters on each end to
+// tell matches apart.
+func redactSecret(secret string) string {
+	keep := min(4, len(secret)/4)
+	return secret[:keep] + strings.Repeat("*", len(secret)-2*keep) + secret[len(secret)-keep:]
+}
+
+// sensitiveSnippet returns the match at body[start:end] redacted, with surrounding context
+// on one line.
+func sensitiveSnippet(body []byte, start, end int) string {
+	before := body[max(0, start-sensitiveContextLen):start]
+	after := body[end:min(len(body), end+sensitiveContextLen)]
+	snippet := string(before) + redactSecret(string(body[start:end])) + string(after)
+	return strings.Join(strings.Fields(strings.ToValidUTF8(snippet, "")), " ")
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// findingSessionCookieRotated marks a response that replaced a session cookie sent with
+// the seed or issued earlier in the crawl, either with a new value or under a new name. This may be
+// session fixation protection, or a server invalidating the session the crawl relies on.
+const findingSessionCookieRotated = "session-cookie-rotated"
+
+var (
+	// sessionCookieNameRe matches cookie names that typically carry a session identifier,
+	// e.g. PHPSESSID, JSESSIONID, connect.sid, laravel_session, auth_token
+	sessionCookieNameRe = regexp.MustCompile(`(?i)sess|(?:^|[_.-])sid$|auth|token`)
+	// antiCSRFCookieNameRe excludes CSRF tokens, which rotate by design
+	antiCSRFCookieNameRe = regexp.MustCompile(`(?i)csrf|xsrf`)
+)
+
+func isSessionCookieName(name string) bool {
+	return sessionCookieNameRe.MatchString(name) && !antiCSRFCookieNameRe.MatchString(name)
+}
+
+// observeSessionCookies records the session cookies set by a response from host. When one
+// replaces a session cookie seen earlier for that host, it returns the replaced and new
+// cookie names: the same name for a new value, or a cookie deleted by the same response
+// and the one set in its place. Locks sess.mu.
+func (sess *crawlSession) observeSessionCookies(host string, header http.Header) (before, after string, rotated bool) {
+	cookies := (&http.Response{Header: header}).Cookies()
+	if len(cookies) == 0 {
+		return "", "", false
+	}
+	host = strings.ToLower(host)
+	now := time.Now()
+	expired := func(c *http.Cookie) bool {
+		return c.Value == "" || c.MaxAge < 0 || (!c.Expires.IsZero() && c.Expires.Before(now))
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	known := sess.sessionCookies[host]
+	if known == nil {
+		known = make(map[string]string)
+		sess.sessionCookies[host] = known
+	}
+
+	// Deletions first, so a cookie replaced under a new name is matched to the one it replaces
+	var deleted string
+	for _, c := range cookies {
+		if _, ok := known[c.Name]; ok && expired(c) {
+			delete(known, c.Name)
+			deleted = c.Name
+		}
+	}
+	for _, c := range cookies {
+		if expired(c) || !isSessionCookieName(c.Name) {
+			continue
+		}
+		previous, seen := known[c.Name]
+		known[c.Name] = c.Value
+		if rotated {
+			continue
+		} else if seen && previous != c.Value {
+			before, after, rotated = c.Name, c.Name, true
+		} else if !seen && deleted != "" && deleted != c.Name {
+			before, after, rotated = deleted, c.Name, true
+		}
+	}
+	return before, after, rotated
+}
+
+// seedSessionCookies records the session cookies of the Cookie header in headers, if any,
+// as already issued by each seed host, so a response replacing the seeded session is
+// flagged like one replacing a cookie set during the crawl. Values observed since are
+// kept. Locks sess.mu.
+func (sess *crawlSession) seedSessionCookies(seedURLs []string, headers map[string]string) {
+	cookies := headerCookies(headers)
+	if len(cookies) == 0 {
+		return
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	for _, seedURL := range seedURLs {
+		u, err := url.Parse(seedURL)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+		known := sess.sessionCookies[host]
+		if known == nil {
+			known = make(map[string]string)
+			sess.sessionCookies[host] = known
+		}
+		for _, c := range cookies {
+			if _, ok := known[c.Name]; !ok && isSessionCookieName(c.Name) {
+				known[c.Name] = c.Value
+			}
+		}
+	}
+}
+
+// seedCookieJar stores the cookies of the Cookie header in headers, if any, in jar for
+// each seed URL. They are scoped to the seed host and path "/" so every page on the host
+// receives them, and a response setting the same cookie replaces the seeded value.
+func seedCookieJar(jar http.CookieJar, seedURLs []string, headers map[string]string) {
+	cookies := headerCookies(headers)
+	if len(cookies) == 0 {
+		return
+	}
+	for _, c := range cookies {
+		c.Path = "/"
+	}
+	for _, seedURL := range seedURLs {
+		if u, err := url.Parse(seedURL); err == nil {
+			jar.SetCookies(u, cookies)
+		}
+	}
+}
+
+// headerCookies parses the Cookie header in headers, matched case-insensitively.
+func headerCookies(headers map[string]string) []*http.Cookie {
+	for k, v := range headers {
+		if strings.EqualFold(k, "Cookie") {
+			cookies, _ := http.ParseCookie(v)
+			return cookies
+		}
+	}
+	return nil
+}
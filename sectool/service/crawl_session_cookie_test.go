@@ -0,0 +1,291 @@
+package service
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestIsSessionCookieName(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]bool{
+		"PHPSESSID":         true,
+		"JSESSIONID":        true,
+		"connect.sid":       true,
+		"laravel_session":   true,
+		"auth_token":        true,
+		"sid":               true,
+		"csrftoken":         false,
+		"XSRF-TOKEN":        false,
+		"theme":             false,
+		"_ga":               false,
+		"consid":            false,
+		"remember_web_sess": true,
+	} {
+		assert.Equal(t, want, isSessionCookieName(name), name)
+	}
+}
+
+func TestObserveSessionCookies(t *testing.T) {
+	t.Parallel()
+
+	headers := func(setCookies ...string) http.Header {
+		h := http.Header{}
+		for _, c := range setCookies {
+			h.Add("Set-Cookie", c)
+		}
+		return h
+	}
+
+	tests := []struct {
+		name       string
+		responses  []http.Header
+		wantBefore string
+		wantAfter  string
+	}{
+		{
+			name:      "first_issue_not_rotation",
+			responses: []http.Header{headers("PHPSESSID=a; Path=/")},
+		},
+		{
+			name:      "same_value_not_rotation",
+			responses: []http.Header{headers("PHPSESSID=a"), headers("PHPSESSID=a")},
+		},
+		{
+			name:       "new_value",
+			responses:  []http.Header{headers("PHPSESSID=a"), headers("PHPSESSID=b")},
+			wantBefore: "PHPSESSID",
+			wantAfter:  "PHPSESSID",
+		},
+		{
+			name:       "renamed",
+			responses:  []http.Header{headers("sid=a"), headers("sid=; Max-Age=0", "session=b")},
+			wantBefore: "sid",
+			wantAfter:  "session",
+		},
+		{
+			name:      "deleted_then_reissued",
+			responses: []http.Header{headers("sid=a"), headers("sid=; Expires=Thu, 01 Jan 1970 00:00:00 GMT"), headers("sid=b")},
+		},
+		{
+			name:      "csrf_ignored",
+			responses: []http.Header{headers("csrftoken=a"), headers("csrftoken=b")},
+		},
+		{
+			name:      "non_session_ignored",
+			responses: []http.Header{headers("theme=dark"), headers("theme=light")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess := &crawlSession{sessionCookies: make(map[string]map[string]string)}
+
+			var before, after string
+			var rotated bool
+			for _, h := range tt.responses {
+				before, after, rotated = sess.observeSessionCookies("Example.com", h)
+			}
+			assert.Equal(t, tt.wantAfter != "", rotated)
+			assert.Equal(t, tt.wantBefore, before)
+			assert.Equal(t, tt.wantAfter, after)
+		})
+	}
+
+	t.Run("per_host", func(t *testing.T) {
+		sess := &crawlSession{sessionCookies: make(map[string]map[string]string)}
+
+		_, _, rotated := sess.observeSessionCookies("a.example.com", headers("sid=a"))
+		assert.False(t, rotated)
+		_, _, rotated = sess.observeSessionCookies("b.example.com", headers("sid=b"))
+		assert.False(t, rotated)
+	})
+
+	t.Run("seeded", func(t *testing.T) {
+		sess := &crawlSession{sessionCookies: make(map[string]map[string]string)}
+		sess.seedSessionCookies([]string{"https://Example.com/login"}, map[string]string{
+			"cookie": "PHPSESSID=seed; theme=dark",
+		})
+
+		_, _, rotated := sess.observeSessionCookies("example.com", headers("theme=light"))
+		assert.False(t, rotated)
+		before, after, rotated := sess.observeSessionCookies("example.com", headers("PHPSESSID=fresh"))
+		assert.True(t, rotated)
+		assert.Equal(t, "PHPSESSID", before)
+		assert.Equal(t, "PHPSESSID", after)
+	})
+
+	t.Run("seeded_keeps_observed", func(t *testing.T) {
+		sess := &crawlSession{sessionCookies: make(map[string]map[string]string)}
+		sess.observeSessionCookies("example.com", headers("sid=issued"))
+		sess.seedSessionCookies([]string{"https://example.com/"}, map[string]string{"Cookie": "sid=seed"})
+
+		_, _, rotated := sess.observeSessionCookies("example.com", headers("sid=issued"))
+		assert.False(t, rotated)
+	})
+}
+
+func TestCollyBackend_SessionCookieRotation(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Header().Add("Set-Cookie", "PHPSESSID=first; Path=/")
+			_, _ = w.Write([]byte(`<a href="/stable">s</a><a href="/rotate">r</a>`))
+		case "/rotate":
+			w.Header().Add("Set-Cookie", "PHPSESSID=second; Path=/")
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		rotated []string // paths flagged
+	}{
+		{name: "issued_during_crawl", rotated: []string{"/rotate"}},
+		{name: "seeded_cookie_replaced", headers: map[string]string{"Cookie": "PHPSESSID=seed"}, rotated: []string{"/", "/rotate"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+			t.Cleanup(func() { _ = b.Close() })
+
+			sess, err := b.CreateSession(t.Context(), CrawlOptions{
+				Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+				Headers:         tt.headers,
+				Delay:           time.Millisecond,
+				IgnoreRobotsTxt: true,
+			})
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				status, err := b.GetStatus(t.Context(), sess.ID)
+				return err == nil && status.State == crawlStateCompleted
+			}, 10*time.Second, 10*time.Millisecond)
+
+			flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+			require.NoError(t, err)
+			require.Len(t, flows, 3)
+			for _, f := range flows {
+				if slices.Contains(tt.rotated, f.Path) {
+					assert.Equal(t, []string{findingSessionCookieRotated}, f.Findings, f.Path)
+					assert.Equal(t, "PHPSESSID", f.CookieBefore)
+					assert.Equal(t, "PHPSESSID", f.CookieAfter)
+				} else {
+					assert.Empty(t, f.Findings, f.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestSeedCookieJar(t *testing.T) {
+	t.Parallel()
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	seedCookieJar(jar, []string{"https://example.com/app/login"}, map[string]string{
+		"cookie": "sid=seed; theme=dark",
+	})
+
+	u, err := url.Parse("https://example.com/other")
+	require.NoError(t, err)
+	assert.Equal(t, "sid=seed; theme=dark", cookieHeader(jar.Cookies(u)))
+
+	jar.SetCookies(u, []*http.Cookie{{Name: "sid", Value: "rotated", Path: "/"}})
+	assert.Equal(t, "sid=rotated; theme=dark", cookieHeader(jar.Cookies(u)))
+
+	other, err := url.Parse("https://other.example.com/")
+	require.NoError(t, err)
+	assert.Empty(t, jar.Cookies(other))
+}
+
+func cookieHeader(cookies []*http.Cookie) string {
+	req := &http.Request{Header: http.Header{}}
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	return req.Header.Get("Cookie")
+}
+
+func TestCollyBackend_CookieJar(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T, received *sync.Map) *httptest.Server {
+		t.Helper()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received.Store(r.URL.Path, r.Header.Get("Cookie"))
+			w.Header().Set("Content-Type", "text/html")
+			switch r.URL.Path {
+			case "/":
+				w.Header().Add("Set-Cookie", "sid=first; Path=/")
+				_, _ = w.Write([]byte(`<a href="/a">a</a>`))
+			case "/a":
+				w.Header().Add("Set-Cookie", "sid=second; Path=/")
+				_, _ = w.Write([]byte(`<a href="/b">b</a>`))
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	tests := []struct {
+		name        string
+		noCookieJar bool
+		wantA       string
+		wantB       string
+	}{
+		{name: "carries_rotated_cookie", wantA: "sid=first", wantB: "sid=second"},
+		{name: "no_cookie_jar", noCookieJar: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var received sync.Map
+			server := newServer(t, &received)
+			b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+			t.Cleanup(func() { _ = b.Close() })
+
+			sess, err := b.CreateSession(t.Context(), CrawlOptions{
+				Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+				Delay:           time.Millisecond,
+				IgnoreRobotsTxt: true,
+				Deterministic:   true,
+				NoCookieJar:     tt.noCookieJar,
+			})
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				status, err := b.GetStatus(t.Context(), sess.ID)
+				return err == nil && status.State == crawlStateCompleted
+			}, 10*time.Second, 10*time.Millisecond)
+
+			a, ok := received.Load("/a")
+			require.True(t, ok)
+			assert.Equal(t, tt.wantA, a)
+			bCookie, ok := received.Load("/b")
+			require.True(t, ok)
+			assert.Equal(t, tt.wantB, bCookie)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"cmp"
+	"hash/fnv"
+	"math/bits"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// simHashShingle is the number of consecutive tokens hashed together, so word order
+// (page structure) matters and not just the vocabulary.
+const simHashShingle = 3
+
+// maxSimilarClusterFlows caps the member flow IDs listed per cluster; Size keeps the full count.
+const maxSimilarClusterFlows = 20
+
+var simHashTokenRe = regexp.MustCompile(`[A-Za-z0-9_]+`)
+
+// bodySimHash returns a 64-bit SimHash of body over shingles of word tokens (tag and
+// attribute names included), so bodies that differ only in a few tokens such as a
+// timestamp or CSRF value get hashes a few bits apart. ok is false when the body has
+// too few tokens to fingerprint.
+func bodySimHash(body []byte) (hash uint64, ok bool) {
+	tokens := simHashTokenRe.FindAll(body, -1)
+	if len(tokens) < simHashShingle {
+		return 0, false
+	}
+
+	var weights [64]int
+	h := fnv.New64a()
+	for i := 0; i+simHashShingle <= len(tokens); i++ {
+		h.Reset()
+		for _, tok := range tokens[i : i+simHashShingle] {
+			_, _ = h.Write(tok)
+			_, _ = h.Write([]byte{0})
+		}
+		sum := h.Sum64()
+		for bit := range weights {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	for bit, w := range weights {
+		if w > 0 {
+			hash |= 1 << bit
+		}
+	}
+	return hash, true
+}
+
+// simHashSimilarity returns the fraction of bits two SimHashes share, from 0 to 1.
+func simHashSimilarity(a, b uint64) float64 {
+	return 1 - float64(bits.OnesCount64(a^b))/64
+}
+
+// clusterSimilarFlows groups fingerprinted flows of the same host, status and media type,
+// adding each flow to the first cluster whose representative (its earliest flow) is at
+// least threshold similar. Only clusters of two or more flows are returned, largest first.
+func clusterSimilarFlows(flows []*CrawlFlow, threshold float64) []SimilarCluster {
+	type cluster struct {
+		rep     *CrawlFlow
+		members []string
+	}
+	byKey := make(map[string][]*cluster)
+	var ordered []*cluster
+	for _, flow := range flows {
+		if flow.SimHash == 0 {
+			continue
+		}
+		key := strings.ToLower(flow.Host) + " " + contentMediaType(flow.ContentType) + " " + strconv.Itoa(flow.StatusCode)
+
+		var match *cluster
+		for _, c := range byKey[key] {
+			if simHashSimilarity(c.rep.SimHash, flow.SimHash) >= threshold {
+				match = c
+				break
+			}
+		}
+		if match == nil {
+			match = &cluster{rep: flow}
+			byKey[key] = append(byKey[key], match)
+			ordered = append(ordered, match)
+		}
+		match.members = append(match.members, flow.ID)
+	}
+
+	var result []SimilarCluster
+	for _, c := range ordered {
+		if len(c.members) < 2 {
+			continue
+		}
+		result = append(result, SimilarCluster{
+			RepresentativeID: c.rep.ID,
+			Host:             c.rep.Host,
+			Path:             c.rep.Path,
+			StatusCode:       c.rep.StatusCode,
+			ContentType:      contentMediaType(c.rep.ContentType),
+			Size:             len(c.members),
+			FlowIDs:          c.members[:min(len(c.members), maxSimilarClusterFlows)],
+		})
+	}
+	slices.SortStableFunc(result, func(a, b SimilarCluster) int {
+		return cmp.Compare(b.Size, a.Size)
+	})
+	return result
+}
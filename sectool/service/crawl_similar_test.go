@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func templatedPage(title, token string) string {
+	var sb strings.Builder
+	sb.WriteString(`<html><head><title>` + title + `</title></head><body><nav><a href="/">Home</a><a href="/about">About</a></nav>`)
+	sb.WriteString(`<form action="/search"><input type="hidden" name="csrf" value="` + token + `"><input name="q"></form>`)
+	for i := range 30 {
+		fmt.Fprintf(&sb, `<div class="row"><span class="label">Item %d</span><p>Standard catalog description text for this listing.</p></div>`, i)
+	}
+	sb.WriteString(`<footer>Rendered at 2026-10-15T12:00:00Z</footer></body></html>`)
+	return sb.String()
+}
+
+func TestBodySimHash(t *testing.T) {
+	t.Parallel()
+
+	t.Run("near_identical", func(t *testing.T) {
+		a, ok := bodySimHash([]byte(templatedPage("Product 1", "a8f3c2e1d4b5")))
+		require.True(t, ok)
+		b, ok := bodySimHash([]byte(templatedPage("Product 2", "99e0b7a6c5d4")))
+		require.True(t, ok)
+		assert.GreaterOrEqual(t, simHashSimilarity(a, b), 0.9)
+	})
+
+	t.Run("different", func(t *testing.T) {
+		a, _ := bodySimHash([]byte(templatedPage("Product 1", "a8f3c2e1d4b5")))
+		b, ok := bodySimHash([]byte(`{"users":[{"id":1,"name":"alice","email":"alice@example.com"},{"id":2,"name":"bob","email":"bob@example.com"}],"total":2,"page":1}`))
+		require.True(t, ok)
+		assert.Less(t, simHashSimilarity(a, b), 0.9)
+	})
+
+	t.Run("too_short", func(t *testing.T) {
+		_, ok := bodySimHash([]byte("ok"))
+		assert.False(t, ok)
+	})
+}
+
+func TestSimHashSimilarity(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 1.0, simHashSimilarity(0xabcd, 0xabcd), 0.0001)
+	assert.InDelta(t, 0.0, simHashSimilarity(0, ^uint64(0)), 0.0001)
+	assert.InDelta(t, 62.0/64, simHashSimilarity(0b1100, 0b0000), 0.0001)
+}
+
+func TestClusterSimilarFlows(t *testing.T) {
+	t.Parallel()
+
+	flow := func(id, host string, status int, hash uint64) *CrawlFlow {
+		return &CrawlFlow{ID: id, Host: host, Path: "/" + id, StatusCode: status, ContentType: "text/html; charset=utf-8", SimHash: hash}
+	}
+	const base = uint64(0xF0F0F0F0F0F0F0F0)
+	flows := []*CrawlFlow{
+		flow("p1", "a.com", 200, base),
+		flow("p2", "a.com", 200, base^0b1),   // 1 bit off
+		flow("p3", "a.com", 200, base^0b11),  // 2 bits off
+		flow("other", "a.com", 200, ^base),   // unrelated
+		flow("err", "a.com", 404, base),      // different status
+		flow("b1", "b.com", 200, base),       // different host
+		flow("b2", "b.com", 200, base^0b100), // same cluster as b1
+		flow("unhashed", "a.com", 200, 0),    // not fingerprinted
+		{ID: "json", Host: "a.com", StatusCode: 200, ContentType: "application/json", SimHash: base},
+	}
+
+	clusters := clusterSimilarFlows(flows, 0.95)
+	require.Len(t, clusters, 2)
+	assert.Equal(t, "p1", clusters[0].RepresentativeID)
+	assert.Equal(t, []string{"p1", "p2", "p3"}, clusters[0].FlowIDs)
+	assert.Equal(t, 3, clusters[0].Size)
+	assert.Equal(t, "text/html", clusters[0].ContentType)
+	assert.Equal(t, []string{"b1", "b2"}, clusters[1].FlowIDs)
+
+	t.Run("strict_threshold", func(t *testing.T) {
+		assert.Empty(t, clusterSimilarFlows(flows, 1))
+	})
+
+	t.Run("member_ids_capped", func(t *testing.T) {
+		many := make([]*CrawlFlow, 0, maxSimilarClusterFlows+5)
+		for i := range maxSimilarClusterFlows + 5 {
+			many = append(many, flow(fmt.Sprintf("f%d", i), "a.com", 200, base))
+		}
+		clusters := clusterSimilarFlows(many, 0.9)
+		require.Len(t, clusters, 1)
+		assert.Equal(t, maxSimilarClusterFlows+5, clusters[0].Size)
+		assert.Len(t, clusters[0].FlowIDs, maxSimilarClusterFlows)
+	})
+}
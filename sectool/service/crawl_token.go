@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+const (
+	// tokenRefreshTimeout bounds a single call to the token endpoint.
+	tokenRefreshTimeout = 15 * time.Second
+	// tokenRefreshMinInterval stops a rejected fresh token from triggering refresh after refresh.
+	tokenRefreshMinInterval = 10 * time.Second
+	// tokenResponseMaxBytes caps the token endpoint response read.
+	tokenResponseMaxBytes = 1 << 20
+)
+
+// TokenRefresh describes the request a crawl sends to obtain a new bearer token when
+// responses start returning 401.
+type TokenRefresh struct {
+	URL       string
+	Method    string            // Default: POST with a body, else GET
+	Body      string            // Sent as JSON when valid JSON, else form-encoded, unless Headers set Content-Type
+	Headers   map[string]string // Extra headers for the token request
+	TokenPath string            // Dot-notation JSON path to the token in the response, e.g. "access_token"
+}
+
+// TokenRefreshEvent records one call to the token endpoint.
+type TokenRefreshEvent struct {
+	Time       time.Time
+	TriggerURL string // URL whose 401 triggered the refresh
+	Error      string // Empty on success
+}
+
+// validateTokenRefresh checks a token refresh configuration before a session is created.
+func validateTokenRefresh(tr *TokenRefresh) error {
+	u, err := url.Parse(tr.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid token refresh URL %q: must be an absolute http(s) URL", tr.URL)
+	} else if tr.TokenPath == "" {
+		return errors.New("token refresh requires a JSON path to the token in the response")
+	} else if _, err := parseJSONPath(tr.TokenPath); err != nil {
+		return fmt.Errorf("invalid token refresh path: %w", err)
+	}
+	return nil
+}
+
+// fetchToken calls the token endpoint and extracts the token at tr.TokenPath.
+func fetchToken(ctx context.Context, transport http.RoundTripper, tr *TokenRefresh) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, tokenRefreshTimeout)
+	defer cancel()
+
+	method := tr.Method
+	if method == "" {
+		method = http.MethodGet
+		if tr.Body != "" {
+			method = http.MethodPost
+		}
+	}
+	var body io.Reader
+	if tr.Body != "" {
+		body = strings.NewReader(tr.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), tr.URL, body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", config.UserAgent())
+	req.Header.Set("Accept", "application/json")
+	if tr.Body != "" {
+		if json.Valid([]byte(tr.Body)) {
+			req.Header.Set("Content-Type", "application/json")
+		} else {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	for k, v := range tr.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, tokenResponseMaxBytes))
+	if err != nil {
+		return "", fmt.Errorf("read token response: %w", err)
+	} else if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("token endpoint returned HTTP %d", resp.StatusCode)
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return "", fmt.Errorf("token response is not JSON: %w", err)
+	}
+	segments, err := parseJSONPath(tr.TokenPath)
+	if err != nil {
+		return "", err
+	}
+	value, ok := getValueAtPath(parsed, segments)
+	if !ok {
+		return "", fmt.Errorf("token response has no %q", tr.TokenPath)
+	}
+	token, ok := value.(string)
+	if !ok || token == "" {
+		return "", fmt.Errorf("token response %q is not a non-empty string", tr.TokenPath)
+	}
+	return token, nil
+}
+
+// refreshToken obtains a new token after a 401 for triggerURL, whose request carried
+// sentAuth as its Authorization header. It reports whether the request should be retried:
+// true when a token newer than sentAuth is now available. Concurrent 401s share one refresh.
+// The token is then sent to triggerURL's host; hosts given their own Authorization by
+// DomainHeaders never trigger a refresh nor receive the token.
+func (sess *crawlSession) refreshToken(sentAuth, triggerURL string) bool {
+	u, err := url.Parse(triggerURL)
+	if err != nil || domainSetsHeader(sess.opts.DomainHeaders, u.Hostname(), "Authorization") {
+		return false
+	}
+	host := strings.ToLower(u.Host)
+
+	sess.refreshMu.Lock()
+	defer sess.refreshMu.Unlock()
+
+	sess.mu.Lock()
+	token, lastRefresh := sess.authToken, sess.lastTokenRefresh
+	if token != "" && sentAuth != "Bearer "+token { // refreshed since the request was sent
+		sess.addAuthTokenHost(host)
+		sess.mu.Unlock()
+		return true
+	}
+	sess.mu.Unlock()
+	if !lastRefresh.IsZero() && time.Since(lastRefresh) < tokenRefreshMinInterval {
+		return false
+	}
+
+	token, err = fetchToken(sess.ctx, sess.refreshTransport, sess.opts.TokenRefresh)
+	event := TokenRefreshEvent{Time: time.Now(), TriggerURL: triggerURL}
+	if err != nil {
+		event.Error = err.Error()
+		log.Printf("crawler: session %s token refresh failed: %v", sess.info.ID, err)
+	} else {
+		log.Printf("crawler: session %s refreshed token after 401 from %s", sess.info.ID, triggerURL)
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.lastTokenRefresh = event.Time
+	sess.tokenRefreshes = append(sess.tokenRefreshes, event)
+	if err != nil {
+		return false
+	}
+	sess.authToken = token
+	sess.addAuthTokenHost(host)
+	return true
+}
+
+// addAuthTokenHost sends the refreshed token to host from now on. Caller must hold sess.mu.
+func (sess *crawlSession) addAuthTokenHost(host string) {
+	if sess.authTokenHosts == nil {
+		sess.authTokenHosts = make(map[string]bool)
+	}
+	sess.authTokenHosts[host] = true
+}
+
+// domainSetsHeader reports whether a DomainHeaders entry matching host sets header name.
+func domainSetsHeader(domainHeaders map[string]map[string]string, host, name string) bool {
+	var found bool
+	applyDomainHeaders(domainHeaders, host, func(k, _ string) {
+		found = found || strings.EqualFold(k, name)
+	})
+	return found
+}
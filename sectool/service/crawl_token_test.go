@@ -0,0 +1,230 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestValidateTokenRefresh(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		tr      TokenRefresh
+		wantErr string
+	}{
+		{name: "valid", tr: TokenRefresh{URL: "https://auth.test/token", TokenPath: "data.access_token"}},
+		{name: "relative_url", tr: TokenRefresh{URL: "/token", TokenPath: "access_token"}, wantErr: "absolute http(s) URL"},
+		{name: "missing_path", tr: TokenRefresh{URL: "https://auth.test/token"}, wantErr: "JSON path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTokenRefresh(&tt.tr)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetchToken(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/echo":
+			// Reports how the request was sent so the test can check method and content type
+			_, _ = fmt.Fprintf(w, `{"token": %q}`, r.Method+" "+r.Header.Get("Content-Type")+" "+string(body))
+		case "/nested":
+			_, _ = w.Write([]byte(`{"data": {"tokens": [{"value": "t-nested"}]}}`))
+		case "/number":
+			_, _ = w.Write([]byte(`{"token": 42}`))
+		case "/error":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_grant"}`))
+		case "/html":
+			_, _ = w.Write([]byte(`<html></html>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name      string
+		tr        TokenRefresh
+		wantToken string
+		wantErr   string
+	}{
+		{name: "get_without_body", tr: TokenRefresh{URL: server.URL + "/echo", TokenPath: "token"}, wantToken: "GET  "},
+		{name: "json_body", tr: TokenRefresh{URL: server.URL + "/echo", Body: `{"a":1}`, TokenPath: "token"}, wantToken: `POST application/json {"a":1}`},
+		{name: "form_body", tr: TokenRefresh{URL: server.URL + "/echo", Body: "grant_type=refresh_token", TokenPath: "token"}, wantToken: "POST application/x-www-form-urlencoded grant_type=refresh_token"},
+		{name: "header_override", tr: TokenRefresh{URL: server.URL + "/echo", Method: "put", Body: "x", Headers: map[string]string{"Content-Type": "text/plain"}, TokenPath: "token"}, wantToken: "PUT text/plain x"},
+		{name: "nested_path", tr: TokenRefresh{URL: server.URL + "/nested", TokenPath: "data.tokens[0].value"}, wantToken: "t-nested"},
+		{name: "missing_path", tr: TokenRefresh{URL: server.URL + "/nested", TokenPath: "access_token"}, wantErr: `no "access_token"`},
+		{name: "not_string", tr: TokenRefresh{URL: server.URL + "/number", TokenPath: "token"}, wantErr: "not a non-empty string"},
+		{name: "error_status", tr: TokenRefresh{URL: server.URL + "/error", TokenPath: "token"}, wantErr: "HTTP 400"},
+		{name: "not_json", tr: TokenRefresh{URL: server.URL + "/html", TokenPath: "token"}, wantErr: "not JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := fetchToken(t.Context(), http.DefaultTransport, &tt.tr)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantToken, token)
+		})
+	}
+}
+
+func TestCollyBackend_TokenRefresh(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T, tokenStatus int) (*httptest.Server, *atomic.Int32) {
+		t.Helper()
+
+		var issued atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/token" {
+				issued.Add(1)
+				w.WriteHeader(tokenStatus)
+				_, _ = w.Write([]byte(`{"access_token": "fresh"}`))
+				return
+			} else if r.Header.Get("Authorization") != "Bearer fresh" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html")
+			if r.URL.Path == "/" {
+				_, _ = w.Write([]byte(`<a href="/a">a</a><a href="/b">b</a>`))
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server, &issued
+	}
+
+	runCrawl := func(t *testing.T, server *httptest.Server, domainHeaders map[string]map[string]string) (*CollyBackend, string) {
+		t.Helper()
+
+		b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		t.Cleanup(func() { _ = b.Close() })
+
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+			Headers:         map[string]string{"Authorization": "Bearer expired"},
+			DomainHeaders:   domainHeaders,
+			Delay:           time.Millisecond,
+			IgnoreRobotsTxt: true,
+			TokenRefresh: &TokenRefresh{
+				URL:       server.URL + "/token",
+				Body:      `{"refresh_token": "r"}`,
+				TokenPath: "access_token",
+			},
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			status, err := b.GetStatus(t.Context(), sess.ID)
+			return err == nil && status.State == crawlStateCompleted
+		}, 10*time.Second, 10*time.Millisecond)
+		return b, sess.ID
+	}
+
+	t.Run("refreshes_and_retries", func(t *testing.T) {
+		server, issued := newServer(t, http.StatusOK)
+		b, sessionID := runCrawl(t, server, nil)
+
+		flows, err := b.ListFlows(t.Context(), sessionID, CrawlListOptions{})
+		require.NoError(t, err)
+		byPath := make(map[string]CrawlFlow)
+		for _, f := range flows {
+			byPath[f.Path] = f
+		}
+		require.Len(t, byPath, 3)
+		assert.Equal(t, 200, byPath["/"].StatusCode)
+		assert.Equal(t, server.URL+"/", byPath["/a"].FoundOn)
+
+		status, err := b.GetStatus(t.Context(), sessionID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, status.URLsErrored)
+		require.Len(t, status.TokenRefreshes, 1)
+		assert.Equal(t, server.URL+"/", status.TokenRefreshes[0].TriggerURL)
+		assert.Empty(t, status.TokenRefreshes[0].Error)
+		assert.Equal(t, int32(1), issued.Load())
+	})
+
+	t.Run("refresh_fails", func(t *testing.T) {
+		server, issued := newServer(t, http.StatusBadRequest)
+		b, sessionID := runCrawl(t, server, nil)
+
+		status, err := b.GetStatus(t.Context(), sessionID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.URLsErrored)
+		require.Len(t, status.TokenRefreshes, 1)
+		assert.Contains(t, status.TokenRefreshes[0].Error, "HTTP 400")
+		assert.Equal(t, int32(1), issued.Load())
+
+		crawlErrors, err := b.ListErrors(t.Context(), sessionID, 0)
+		require.NoError(t, err)
+		require.Len(t, crawlErrors, 1)
+		assert.Equal(t, http.StatusUnauthorized, crawlErrors[0].Status)
+	})
+	t.Run("other_host_keeps_its_authorization", func(t *testing.T) {
+		var otherAuth sync.Map
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			otherAuth.Store(r.URL.Path, r.Header.Get("Authorization"))
+			w.Header().Set("Content-Type", "text/html")
+		}))
+		t.Cleanup(other.Close)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/token" {
+				_, _ = w.Write([]byte(`{"access_token": "fresh"}`))
+				return
+			} else if r.Header.Get("Authorization") != "Bearer fresh" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html")
+			if r.URL.Path == "/" {
+				_, _ = w.Write([]byte(`<a href="` + other.URL + `/other">other</a>`))
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		b, sessionID := runCrawl(t, server, nil)
+		status, err := b.GetStatus(t.Context(), sessionID)
+		require.NoError(t, err)
+		require.Len(t, status.TokenRefreshes, 1)
+		sent, ok := otherAuth.Load("/other")
+		require.True(t, ok)
+		assert.Equal(t, "Bearer expired", sent)
+	})
+
+	t.Run("domain_authorization_not_replaced", func(t *testing.T) {
+		server, issued := newServer(t, http.StatusOK)
+		b, sessionID := runCrawl(t, server, map[string]map[string]string{
+			"127.0.0.1": {"Authorization": "Bearer domain"},
+		})
+
+		status, err := b.GetStatus(t.Context(), sessionID)
+		require.NoError(t, err)
+		assert.Empty(t, status.TokenRefreshes)
+		assert.Equal(t, int32(0), issued.Load())
+	})
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/go-appsec/toolbox/sectool/service/ids"
+)
+
+// findingVaryVariantDiffers marks a Vary variant whose response differs significantly
+// (status, content type, or more than varyLengthTolerance in size) from the original.
+const findingVaryVariantDiffers = "vary-variant-differs"
+
+const (
+	varyLengthTolerance = 0.1
+
+	// varyCaptureKey, varyOfKey and varyHeaderKey carry variant request state in its colly context
+	varyCaptureKey = "vary_capture_id"
+	varyOfKey      = "vary_of"
+	varyHeaderKey  = "vary_header"
+
+	varyMobileUserAgent = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+)
+
+// varyAlternates returns, per lowercase request header, a value different from the
+// original to request a variant with. Headers without an entry are not varied.
+var varyAlternates = map[string]func(original string) string{
+	"user-agent": func(string) string { return varyMobileUserAgent },
+	"accept": func(original string) string {
+		if strings.Contains(original, "json") {
+			return "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8"
+		}
+		return "application/json"
+	},
+	"accept-language": func(original string) string {
+		if strings.HasPrefix(strings.ToLower(original), "fr") {
+			return "de-DE,de;q=0.9"
+		}
+		return "fr-FR,fr;q=0.9"
+	},
+	"x-requested-with": func(original string) string {
+		if original != "" {
+			return ""
+		}
+		return "XMLHttpRequest"
+	},
+}
+
+// varyHeaders parses Vary header values into the distinct canonical header names that
+// have an alternate value, in listed order.
+func varyHeaders(values []string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		for _, name := range strings.Split(v, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if _, ok := varyAlternates[strings.ToLower(name)]; ok && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// varyVariantDiffers reports whether a variant response differs significantly from the original.
+func varyVariantDiffers(original, variant *CrawlFlow) bool {
+	if original.StatusCode != variant.StatusCode ||
+		contentMediaType(original.ContentType) != contentMediaType(variant.ContentType) {
+		return true
+	}
+	longer := max(original.ResponseLength, variant.ResponseLength)
+	diff := original.ResponseLength - variant.ResponseLength
+	if diff < 0 {
+		diff = -diff
+	}
+	return longer > 0 && float64(diff) > float64(longer)*varyLengthTolerance
+}
+
+// newVaryCollector clones c for variant requests. The clone may revisit URLs the crawl
+// already fetched, and records each response as a flow linked to the original; variant
+// responses are not searched for further links.
+func (sess *crawlSession) newVaryCollector(c *colly.Collector) *colly.Collector {
+	vc := c.Clone()
+	vc.AllowURLRevisit = true
+
+	vc.OnRequest(func(r *colly.Request) {
+		sess.mu.Lock()
+		if sess.opts.MaxRequests > 0 && sess.requestCount >= sess.opts.MaxRequests {
+			sess.mu.Unlock()
+			r.Abort()
+			return
+		}
+		sess.requestCount++
+		sess.urlsQueued++
+		sess.lastActivity = time.Now()
+		sess.mu.Unlock()
+
+		// Request IDs are per collector, so the capture ID travels in the request context
+		captureID := ids.Generate(ids.DefaultLength)
+		r.Ctx.Put(varyCaptureKey, captureID)
+		r.Headers.Set(captureIDHeader, captureID)
+	})
+
+	vc.OnResponse(func(r *colly.Response) {
+		captured, ok := sess.captureStore.LoadAndDelete(r.Ctx.Get(varyCaptureKey))
+		if !ok {
+			sess.mu.Lock()
+			sess.urlsQueued--
+			sess.mu.Unlock()
+			return
+		}
+		data := captured.(*capturedData)
+
+		flowPath := r.Request.URL.Path
+		if r.Request.URL.RawQuery != "" {
+			flowPath += "?" + r.Request.URL.RawQuery
+		}
+		flow := &CrawlFlow{
+			ID:             ids.Generate(ids.DefaultLength),
+			SessionID:      sess.info.ID,
+			URL:            r.Request.URL.String(),
+			Host:           r.Request.URL.Host,
+			Path:           flowPath,
+			Method:         r.Request.Method,
+			FoundOn:        r.Request.URL.String(),
+			Depth:          r.Request.Depth,
+			StatusCode:     r.StatusCode,
+			ContentType:    r.Headers.Get("Content-Type"),
+			ResponseLength: data.RespBodySize,
+			Request:        data.Request,
+			Response:       append(data.RespHeaders, data.RespBody...),
+			Truncated:      data.Truncated,
+			Duration:       data.Duration,
+			DiscoveredAt:   time.Now(),
+			VariantOf:      r.Ctx.Get(varyOfKey),
+			VariedHeader:   r.Ctx.Get(varyHeaderKey),
+		}
+		flow.HeadersTruncated = data.HeadersTruncated
+
+		var searchTokens []string
+		if sess.searchIndex != nil {
+			searchTokens = flowSearchTokens(flow.Request, flow.Response)
+		}
+
+		sess.mu.Lock()
+		if original := sess.flowsByID[flow.VariantOf]; original != nil && varyVariantDiffers(original, flow) {
+			flow.Findings = append(flow.Findings, findingVaryVariantDiffers)
+		}
+		sess.flowsByID[flow.ID] = flow
+		sess.flowsOrdered = append(sess.flowsOrdered, flow)
+		if sess.searchIndex != nil {
+			sess.searchIndex.add(len(sess.flowsOrdered)-1, searchTokens)
+		}
+		sess.notifyFlows()
+		sess.urlsQueued--
+		sess.lastActivity = time.Now()
+		sess.persistFlows(flow)
+		sess.mu.Unlock()
+	})
+
+	vc.OnError(func(r *colly.Response, err error) {
+		sess.captureStore.Delete(r.Ctx.Get(varyCaptureKey))
+
+		sess.mu.Lock()
+		sess.errors = append(sess.errors, CrawlError{
+			URL:    r.Request.URL.String(),
+			Error:  "vary variant (" + r.Ctx.Get(varyHeaderKey) + "): " + err.Error(),
+			Status: r.StatusCode,
+		})
+		sess.urlsQueued--
+		sess.lastActivity = time.Now()
+		sess.mu.Unlock()
+	})
+
+	return vc
+}
+
+// queueVaryVariants re-requests a GET response's URL once per header named in its Vary
+// header, changing only that header from the original request.
+func (sess *crawlSession) queueVaryVariants(vc *colly.Collector, req *colly.Request, flowID string, vary []string) {
+	for _, name := range varyHeaders(vary) {
+		key := req.URL.String() + " " + name
+		sess.mu.Lock()
+		checked := sess.varyChecked[key]
+		sess.varyChecked[key] = true
+		sess.mu.Unlock()
+		if checked {
+			continue
+		}
+
+		hdr := req.Headers.Clone()
+		hdr.Del(captureIDHeader)
+		if value := varyAlternates[strings.ToLower(name)](hdr.Get(name)); value != "" {
+			hdr.Set(name, value)
+		} else {
+			hdr.Del(name)
+		}
+
+		ctx := colly.NewContext()
+		ctx.Put(varyOfKey, flowID)
+		ctx.Put(varyHeaderKey, name)
+		_ = vc.Request(http.MethodGet, req.URL.String(), nil, ctx, hdr)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestVaryHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		values []string
+		want   []string
+	}{
+		{"none", nil, nil},
+		{"single", []string{"User-Agent"}, []string{"User-Agent"}},
+		{"list_and_case", []string{"accept, ACCEPT-LANGUAGE"}, []string{"Accept", "Accept-Language"}},
+		{"deduped_across_values", []string{"Accept", "accept, User-Agent"}, []string{"Accept", "User-Agent"}},
+		{"unsupported_skipped", []string{"*", "Accept-Encoding, Cookie, Origin"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, varyHeaders(tt.values))
+		})
+	}
+}
+
+func TestVaryVariantDiffers(t *testing.T) {
+	t.Parallel()
+
+	base := CrawlFlow{StatusCode: 200, ContentType: "text/html; charset=utf-8", ResponseLength: 1000}
+
+	tests := []struct {
+		name   string
+		modify func(f *CrawlFlow)
+		want   bool
+	}{
+		{"identical", func(f *CrawlFlow) {}, false},
+		{"small_size_change", func(f *CrawlFlow) { f.ResponseLength = 950 }, false},
+		{"large_size_change", func(f *CrawlFlow) { f.ResponseLength = 500 }, true},
+		{"status_change", func(f *CrawlFlow) { f.StatusCode = 302 }, true},
+		{"content_type_change", func(f *CrawlFlow) { f.ContentType = "application/json" }, true},
+		{"charset_only", func(f *CrawlFlow) { f.ContentType = "text/html" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variant := base
+			tt.modify(&variant)
+			assert.Equal(t, tt.want, varyVariantDiffers(&base, &variant))
+		})
+	}
+}
+
+func TestCollyBackend_VaryVariants(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Vary", "Accept-Language")
+			_, _ = w.Write([]byte(`<a href="/app">app</a>`))
+		case "/app":
+			w.Header().Set("Vary", "User-Agent, Accept-Encoding")
+			if strings.Contains(r.UserAgent(), "Mobile") {
+				_, _ = w.Write([]byte(`<h1>Mobile app</h1><a href="/m/internal-api">internal api docs for the mobile client</a>`))
+				return
+			}
+			_, _ = w.Write([]byte(`<h1>Desktop</h1>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		Delay:           time.Millisecond,
+		IgnoreRobotsTxt: true,
+		VaryVariants:    true,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+	require.NoError(t, err)
+
+	byID := make(map[string]CrawlFlow)
+	variants := make(map[string]CrawlFlow) // path -> variant
+	for _, f := range flows {
+		byID[f.ID] = f
+		if f.VariantOf != "" {
+			variants[f.Path] = f
+		}
+	}
+	require.Len(t, variants, 2)
+	assert.Len(t, flows, 4)
+
+	root := variants["/"]
+	assert.Equal(t, "Accept-Language", root.VariedHeader)
+	assert.Equal(t, "/", byID[root.VariantOf].Path)
+	assert.Empty(t, root.Findings)
+	assert.Contains(t, string(root.Request), "Accept-Language: fr-FR")
+
+	app := variants["/app"]
+	assert.Equal(t, "User-Agent", app.VariedHeader)
+	assert.Equal(t, "/app", byID[app.VariantOf].Path)
+	assert.Equal(t, []string{findingVaryVariantDiffers}, app.Findings)
+}
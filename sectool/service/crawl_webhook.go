@@ -0,0 +1,130 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	crawlWebhookTimeout = 10 * time.Second
+	// crawlWebhookCloseGrace is how long Close waits for pending deliveries before cancelling them.
+	crawlWebhookCloseGrace = 2 * time.Second
+)
+
+// crawlCompletionSummary is the JSON body POSTed to CrawlOptions.CompletionWebhook.
+type crawlCompletionSummary struct {
+	SessionID       string      `json:"session_id"`
+	Label           string      `json:"label,omitempty"`
+	State           string      `json:"state"`
+	URLsVisited     int         `json:"urls_visited"`
+	URLsErrored     int         `json:"urls_errored"`
+	FormsDiscovered int         `json:"forms_discovered"`
+	StatusCodes     map[int]int `json:"status_codes"` // status code -> flow count
+	Findings        int         `json:"findings"`
+	Duration        string      `json:"duration"`
+}
+
+// validateWebhookURL checks that a completion webhook is an absolute http(s) URL.
+func validateWebhookURL(webhook string) error {
+	u, err := url.Parse(webhook)
+	if err != nil {
+		return fmt.Errorf("invalid completion webhook: %w", err)
+	} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("invalid completion webhook: must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// completionSummary snapshots the session totals reported to the completion webhook.
+func (sess *crawlSession) completionSummary() crawlCompletionSummary {
+	sess.mu.RLock()
+	defer sess.mu.RUnlock()
+
+	summary := crawlCompletionSummary{
+		SessionID:       sess.info.ID,
+		Label:           sess.info.Label,
+		State:           sess.info.State,
+		URLsVisited:     len(sess.flowsOrdered),
+		URLsErrored:     len(sess.errors),
+		FormsDiscovered: len(sess.forms),
+		StatusCodes:     make(map[int]int),
+		Duration:        time.Since(sess.startedAt).Round(time.Millisecond).String(),
+	}
+	for _, flow := range sess.flowsOrdered {
+		summary.StatusCodes[flow.StatusCode]++
+		summary.Findings += len(flow.Findings)
+	}
+	return summary
+}
+
+// deliverCompletionWebhook posts the session summary in the background, so a slow or
+// unreachable webhook holds up neither the run's runDone nor Close beyond its grace period.
+func (b *CollyBackend) deliverCompletionWebhook(webhook string, summary crawlCompletionSummary) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.webhooksClosed {
+		log.Printf("crawler: session %s webhook skipped: backend is closed", summary.SessionID)
+		return
+	}
+	b.webhooks.Add(1)
+	go func() {
+		defer b.webhooks.Done()
+		postCompletionWebhook(b.webhookCtx, webhook, summary)
+	}()
+}
+
+// closeWebhooks waits up to crawlWebhookCloseGrace for pending deliveries, then cancels the rest.
+func (b *CollyBackend) closeWebhooks() {
+	b.mu.Lock()
+	b.webhooksClosed = true
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.webhooks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(crawlWebhookCloseGrace):
+		log.Printf("crawler: cancelling completion webhooks still pending after %s", crawlWebhookCloseGrace)
+	}
+	b.webhookCancel()
+	<-done
+}
+
+// postCompletionWebhook delivers the session summary. Failures are logged only;
+// the crawl has already finished and nothing waits on delivery.
+func postCompletionWebhook(ctx context.Context, webhook string, summary crawlCompletionSummary) {
+	body, err := json.Marshal(summary)
+	if err != nil {
+		log.Printf("crawler: session %s webhook: marshal summary: %v", summary.SessionID, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, crawlWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("crawler: session %s webhook: %v", summary.SessionID, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Printf("crawler: session %s webhook delivery failed: %v", summary.SessionID, err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("crawler: session %s webhook returned status %d", summary.SessionID, resp.StatusCode)
+	}
+}
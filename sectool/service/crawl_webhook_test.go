@@ -0,0 +1,147 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		webhook string
+		wantErr bool
+	}{
+		{"https", "https://hooks.example.com/crawl", false},
+		{"http_with_port", "http://127.0.0.1:8080/done", false},
+		{"relative", "/done", true},
+		{"other_scheme", "ftp://example.com/done", true},
+		{"missing_host", "http:///done", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWebhookURL(tt.webhook)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCollyBackend_CompletionWebhook(t *testing.T) {
+	t.Parallel()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<a href="/about">about</a><a href="/missing">missing</a>`))
+		case "/about":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`about`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(target.Close)
+
+	received := make(chan crawlCompletionSummary, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var summary crawlCompletionSummary
+		if r.Method == http.MethodPost && r.Header.Get("Content-Type") == "application/json" &&
+			json.NewDecoder(r.Body).Decode(&summary) == nil {
+			received <- summary
+		}
+	}))
+	t.Cleanup(hook.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	t.Run("invalid_url", func(t *testing.T) {
+		_, err := b.CreateSession(t.Context(), CrawlOptions{
+			Seeds:             []CrawlSeed{{URL: target.URL + "/"}},
+			CompletionWebhook: "not a url",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid completion webhook")
+	})
+
+	t.Run("posts_summary", func(t *testing.T) {
+		sess, err := b.CreateSession(t.Context(), CrawlOptions{
+			Label:             "hooked",
+			Seeds:             []CrawlSeed{{URL: target.URL + "/"}},
+			Delay:             time.Millisecond,
+			IgnoreRobotsTxt:   true,
+			CompletionWebhook: hook.URL + "/crawl-done",
+		})
+		require.NoError(t, err)
+
+		var summary crawlCompletionSummary
+		select {
+		case summary = <-received:
+		case <-time.After(10 * time.Second):
+			t.Fatal("webhook not delivered")
+		}
+
+		assert.Equal(t, sess.ID, summary.SessionID)
+		assert.Equal(t, "hooked", summary.Label)
+		assert.Equal(t, crawlStateCompleted, summary.State)
+		assert.Equal(t, 2, summary.URLsVisited)
+		assert.Equal(t, 1, summary.URLsErrored)
+		assert.Equal(t, map[int]int{200: 2}, summary.StatusCodes)
+		assert.NotEmpty(t, summary.Duration)
+	})
+
+	t.Run("slow_webhook", func(t *testing.T) {
+		hanging := make(chan struct{}, 1)
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.Copy(io.Discard, r.Body) // the server notices the client going away once the body is read
+			select {
+			case hanging <- struct{}{}:
+			default:
+			}
+			<-r.Context().Done()
+		}))
+		t.Cleanup(slow.Close)
+
+		slowBackend := NewCollyBackend(config.DefaultConfig(), nil, nil)
+		sess, err := slowBackend.CreateSession(t.Context(), CrawlOptions{
+			Seeds:             []CrawlSeed{{URL: target.URL + "/"}},
+			Delay:             time.Millisecond,
+			IgnoreRobotsTxt:   true,
+			CompletionWebhook: slow.URL,
+		})
+		require.NoError(t, err)
+		select {
+		case <-hanging:
+		case <-time.After(10 * time.Second):
+			t.Fatal("webhook not called")
+		}
+
+		// The run is done while the delivery hangs, and Close only waits out its grace period
+		crawl, err := slowBackend.resolveSession(sess.ID)
+		require.NoError(t, err)
+		select {
+		case <-crawl.runDone:
+		default:
+			t.Fatal("run waits on the webhook")
+		}
+		start := time.Now()
+		require.NoError(t, slowBackend.Close())
+		assert.Less(t, time.Since(start), crawlWebhookTimeout)
+	})
+}
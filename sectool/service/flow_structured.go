@@ -0,0 +1,67 @@
+package service
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+// Structured returns a decoded view of the flow for direct JSON serialization: the request
+// line and headers parsed, bodies decompressed, and query, form and JSON parameters extracted.
+// Text bodies are capped like crawl_get previews; JSON bodies are parsed in full.
+func (f *CrawlFlow) Structured() protocol.StructuredFlow {
+	reqHeaders, reqBody := splitHeadersBody(f.Request)
+	respHeaders, respBody := splitHeadersBody(f.Response)
+
+	var method, path, version string
+	firstLine, _, _ := strings.Cut(string(reqHeaders), "\r\n")
+	if parts := strings.SplitN(firstLine, " ", 3); len(parts) == 3 {
+		method, path, version = parts[0], parts[1], parts[2]
+	}
+
+	req := protocol.StructuredRequest{
+		Method:  method,
+		Path:    path,
+		Version: version,
+		Headers: parseHeadersToMap(string(reqHeaders)),
+	}
+	if u, err := url.Parse(f.URL); err == nil && u.RawQuery != "" {
+		req.Query = u.Query()
+	}
+	var decodedReqBody []byte
+	req.ContentType, decodedReqBody, req.JSON, req.Body = structuredBody(reqHeaders, reqBody)
+	if contentMediaType(req.ContentType) == "application/x-www-form-urlencoded" && len(decodedReqBody) > 0 {
+		req.Form, _ = url.ParseQuery(string(decodedReqBody)) // keep pairs parsed before any malformed one
+	}
+
+	resp := protocol.StructuredResponse{Headers: parseHeadersToMap(string(respHeaders))}
+	resp.Status, resp.StatusLine = parseResponseStatus(respHeaders)
+	resp.ContentType, _, resp.JSON, resp.Body = structuredBody(respHeaders, respBody)
+
+	return protocol.StructuredFlow{
+		FlowID:    f.ID,
+		URL:       f.URL,
+		FoundOn:   f.FoundOn,
+		Findings:  f.Findings,
+		Truncated: f.Truncated,
+		Duration:  f.Duration.Round(time.Millisecond).String(),
+		Request:   req,
+		Response:  resp,
+	}
+}
+
+// structuredBody decompresses a message body and parses it when the content type is JSON.
+// text is only set when the body was not parsed as JSON.
+func structuredBody(headers, body []byte) (contentType string, decoded []byte, jsonValue interface{}, text string) {
+	contentType = extractHeader(string(headers), "Content-Type")
+	decoded, _ = decompressForDisplay(body, string(headers))
+	if mt := contentMediaType(contentType); mt == "application/json" || strings.HasSuffix(mt, "+json") {
+		if err := json.Unmarshal(decoded, &jsonValue); err == nil {
+			return contentType, decoded, jsonValue, ""
+		}
+	}
+	return contentType, decoded, nil, previewBody(decoded, fullBodyMaxSize)
+}
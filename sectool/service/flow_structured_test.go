@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/service/proxy"
+)
+
+func TestCrawlFlow_Structured(t *testing.T) {
+	t.Parallel()
+
+	t.Run("form_request_gzip_json_response", func(t *testing.T) {
+		gzipped, err := proxy.Compress([]byte(`{"ok":true,"items":[1,2]}`), "gzip")
+		require.NoError(t, err)
+
+		flow := &CrawlFlow{
+			ID:  "f1",
+			URL: "https://example.com/login?next=%2Fhome&x=1",
+			Request: []byte("POST /login?next=%2Fhome&x=1 HTTP/1.1\r\nHost: example.com\r\n" +
+				"Content-Type: application/x-www-form-urlencoded\r\n\r\nuser=bob&pass=a%26b"),
+			Response: append([]byte("HTTP/1.1 200 OK\r\nContent-Type: application/json; charset=utf-8\r\n"+
+				"Content-Encoding: gzip\r\nSet-Cookie: a=1\r\nSet-Cookie: b=2\r\n\r\n"), gzipped...),
+		}
+
+		s := flow.Structured()
+		assert.Equal(t, "f1", s.FlowID)
+		assert.Equal(t, "POST", s.Request.Method)
+		assert.Equal(t, "/login?next=%2Fhome&x=1", s.Request.Path)
+		assert.Equal(t, "HTTP/1.1", s.Request.Version)
+		assert.Equal(t, []string{"example.com"}, s.Request.Headers["Host"])
+		assert.Equal(t, map[string][]string{"next": {"/home"}, "x": {"1"}}, s.Request.Query)
+		assert.Equal(t, map[string][]string{"user": {"bob"}, "pass": {"a&b"}}, s.Request.Form)
+		assert.Equal(t, "user=bob&pass=a%26b", s.Request.Body)
+
+		assert.Equal(t, 200, s.Response.Status)
+		assert.Equal(t, []string{"a=1", "b=2"}, s.Response.Headers["Set-Cookie"])
+		assert.Equal(t, map[string]interface{}{"ok": true, "items": []interface{}{1.0, 2.0}}, s.Response.JSON)
+		assert.Empty(t, s.Response.Body)
+	})
+
+	t.Run("json_request_text_response", func(t *testing.T) {
+		flow := &CrawlFlow{
+			ID:       "f2",
+			URL:      "https://example.com/api",
+			Request:  []byte("PUT /api HTTP/1.1\r\nHost: example.com\r\nContent-Type: application/vnd.api+json\r\n\r\n{\"id\":7}"),
+			Response: []byte("HTTP/1.1 404 Not Found\r\nContent-Type: text/html\r\n\r\n<h1>missing</h1>"),
+		}
+
+		s := flow.Structured()
+		assert.Nil(t, s.Request.Query)
+		assert.Nil(t, s.Request.Form)
+		assert.Equal(t, map[string]interface{}{"id": 7.0}, s.Request.JSON)
+		assert.Empty(t, s.Request.Body)
+		assert.Equal(t, 404, s.Response.Status)
+		assert.Nil(t, s.Response.JSON)
+		assert.Equal(t, "<h1>missing</h1>", s.Response.Body)
+	})
+
+	t.Run("invalid_json_falls_back_to_text", func(t *testing.T) {
+		flow := &CrawlFlow{
+			ID:       "f3",
+			URL:      "https://example.com/",
+			Request:  []byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"),
+			Response: []byte("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n{broken"),
+		}
+
+		s := flow.Structured()
+		assert.Nil(t, s.Response.JSON)
+		assert.Equal(t, "{broken", s.Response.Body)
+	})
+}
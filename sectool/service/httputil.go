@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"path"
@@ -351,6 +352,59 @@ func parseHeaderArg(raw interface{}) []string {
 	}
 }
 
+// headerArgToMap parses a header argument (see parseHeaderArg) into a name to value map.
+// Returns nil if no valid headers are present.
+func headerArgToMap(raw interface{}) map[string]string {
+	headerSlice := parseHeaderArg(raw)
+	if len(headerSlice) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(headerSlice))
+	for _, h := range headerSlice {
+		if name, value, ok := strings.Cut(h, ":"); ok {
+			headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	return headers
+}
+
+// parseHostResolutionArg parses a host pinning argument into a lowercase host to IP map.
+// Accepts an object {"host": "ip"} or an array ["host=ip"].
+func parseHostResolutionArg(raw interface{}) (map[string]string, error) {
+	entries := make(map[string]string)
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		for host, val := range v {
+			ip, _ := val.(string)
+			entries[host] = ip
+		}
+	case []interface{}:
+		for _, item := range v {
+			entry, _ := item.(string)
+			host, ip, ok := strings.Cut(entry, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid resolve entry %q: expected host=ip", entry)
+			}
+			entries[host] = ip
+		}
+	default:
+		return nil, errors.New("resolve must be an object {\"host\": \"ip\"} or array [\"host=ip\"]")
+	}
+
+	result := make(map[string]string, len(entries))
+	for host, ip := range entries {
+		host = strings.ToLower(strings.TrimSpace(host))
+		ip = strings.TrimSpace(ip)
+		if host == "" {
+			return nil, errors.New("invalid resolve entry: empty host")
+		} else if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("invalid resolve IP %q for host %s", ip, host)
+		}
+		result[host] = ip
+	}
+	return result, nil
+}
+
 // extractHeaderLines extracts header lines from raw HTTP request.
 // Skips the request line and returns each header as "Name: Value".
 func extractHeaderLines(raw string) []string {
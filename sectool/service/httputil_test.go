@@ -63,6 +63,42 @@ func TestAggregateByTuple(t *testing.T) {
 	})
 }
 
+func TestParseHostResolutionArg(t *testing.T) {
+	t.Parallel()
+
+	t.Run("object_format", func(t *testing.T) {
+		got, err := parseHostResolutionArg(map[string]interface{}{"API.example.com": "10.0.0.5"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"api.example.com": "10.0.0.5"}, got)
+	})
+
+	t.Run("array_format", func(t *testing.T) {
+		got, err := parseHostResolutionArg([]interface{}{"a.example.com=10.0.0.1", "b.example.com = ::1"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"a.example.com": "10.0.0.1", "b.example.com": "::1"}, got)
+	})
+
+	t.Run("invalid_ip", func(t *testing.T) {
+		_, err := parseHostResolutionArg(map[string]interface{}{"example.com": "not-an-ip"})
+		assert.ErrorContains(t, err, "invalid resolve IP")
+	})
+
+	t.Run("missing_separator", func(t *testing.T) {
+		_, err := parseHostResolutionArg([]interface{}{"example.com"})
+		assert.ErrorContains(t, err, "expected host=ip")
+	})
+
+	t.Run("empty_host", func(t *testing.T) {
+		_, err := parseHostResolutionArg([]interface{}{"=10.0.0.1"})
+		assert.ErrorContains(t, err, "empty host")
+	})
+
+	t.Run("wrong_type", func(t *testing.T) {
+		_, err := parseHostResolutionArg("example.com=10.0.0.1")
+		assert.Error(t, err)
+	})
+}
+
 func TestParseHeaderArg(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-appsec/toolbox/sectool/service/ids"
+	"github.com/go-appsec/toolbox/sectool/service/proxy"
+)
+
+// interceptMaxHold bounds how long a request is held; it is dropped, not forwarded, after.
+const interceptMaxHold = 5 * time.Minute
+
+// InterceptFilter selects the requests held while intercept is on. Empty fields match all.
+type InterceptFilter struct {
+	Host   string // Host glob, e.g. "*.example.com"
+	Path   string // Path glob, e.g. "/api/*"
+	Method string // Exact method, case-insensitive
+}
+
+func (f InterceptFilter) matches(host, method, path string) bool {
+	return matchesGlob(strings.ToLower(host), strings.ToLower(f.Host)) &&
+		matchesGlob(path, f.Path) &&
+		(f.Method == "" || strings.EqualFold(method, f.Method))
+}
+
+// HeldRequest is a proxied request waiting to be released or dropped.
+type HeldRequest struct {
+	ID     string
+	Method string
+	Host   string
+	Path   string // Path with query string
+	HeldAt time.Time
+}
+
+// InterceptStatus describes the intercept state and the requests currently held.
+type InterceptStatus struct {
+	Enabled bool
+	Filter  InterceptFilter
+	Held    []HeldRequest // oldest first
+}
+
+// proxyInterceptor is implemented by HTTP backends that can hold proxied requests until released.
+type proxyInterceptor interface {
+	// SetIntercept turns intercept on with filter, or off; turning it off releases held requests.
+	SetIntercept(ctx context.Context, enabled bool, filter InterceptFilter) error
+	InterceptStatus() InterceptStatus
+	// ReleaseHeld forwards (or drops) the held request id, or every held request when id
+	// is empty, returning how many were affected.
+	ReleaseHeld(id string, drop bool) (int, error)
+}
+
+// interceptQueue holds proxied requests matching the intercept filter until released.
+// Implements proxy.RequestHolder for the native proxy.
+type interceptQueue struct {
+	mu      sync.Mutex
+	enabled bool
+	filter  InterceptFilter
+	held    []*heldRequest
+}
+
+type heldRequest struct {
+	HeldRequest
+	decision chan bool // receives true to forward, false to drop
+}
+
+var _ proxy.RequestHolder = (*interceptQueue)(nil)
+
+// set changes the intercept state; requests already held stay held until released.
+func (q *interceptQueue) set(enabled bool, filter InterceptFilter) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.enabled = enabled
+	q.filter = filter
+}
+
+func (q *interceptQueue) status() InterceptStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	status := InterceptStatus{Enabled: q.enabled, Filter: q.filter}
+	for _, h := range q.held {
+		status.Held = append(status.Held, h.HeldRequest)
+	}
+	return status
+}
+
+// release decides the held request id, or all held requests when id is empty.
+func (q *interceptQueue) release(id string, drop bool) (int, error) {
+	q.mu.Lock()
+	var decided []*heldRequest
+	if id == "" {
+		decided, q.held = q.held, nil
+	} else {
+		for i, h := range q.held {
+			if h.ID == id {
+				decided = append(decided, h)
+				q.held = append(q.held[:i:i], q.held[i+1:]...)
+				break
+			}
+		}
+	}
+	q.mu.Unlock()
+
+	if id != "" && len(decided) == 0 {
+		return 0, fmt.Errorf("held request %s not found (it may have been released or timed out)", id)
+	}
+	for _, h := range decided {
+		h.decision <- !drop
+	}
+	return len(decided), nil
+}
+
+// Intercepting reports whether intercept is on.
+func (q *interceptQueue) Intercepting() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.enabled
+}
+
+// HoldRequest blocks while a matching request is held, dropping it if not released within
+// interceptMaxHold or when ctx ends.
+func (q *interceptQueue) HoldRequest(ctx context.Context, host string, req *proxy.RawHTTP1Request) bool {
+	path := req.Path
+	if req.Query != "" {
+		path += "?" + req.Query
+	}
+
+	q.mu.Lock()
+	if !q.enabled || !q.filter.matches(host, req.Method, req.Path) {
+		q.mu.Unlock()
+		return true
+	}
+	h := &heldRequest{
+		HeldRequest: HeldRequest{
+			ID:     ids.Generate(ids.DefaultLength),
+			Method: req.Method,
+			Host:   host,
+			Path:   path,
+			HeldAt: time.Now(),
+		},
+		decision: make(chan bool, 1),
+	}
+	q.held = append(q.held, h)
+	q.mu.Unlock()
+
+	log.Printf("proxy: intercept holding %s %s %s%s", h.ID, h.Method, h.Host, h.Path)
+
+	timer := time.NewTimer(interceptMaxHold)
+	defer timer.Stop()
+	select {
+	case forward := <-h.decision:
+		return forward
+	case <-ctx.Done():
+	case <-timer.C:
+		log.Printf("proxy: intercept dropped %s after holding it %s", h.ID, interceptMaxHold)
+	}
+
+	// Not decided; remove it unless a release is racing us, then honor that decision
+	if _, err := q.release(h.ID, true); err != nil {
+		return <-h.decision
+	}
+	return false
+}
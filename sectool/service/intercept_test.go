@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/service/proxy"
+	"github.com/go-appsec/toolbox/sectool/service/store"
+)
+
+func TestInterceptFilter_Matches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		filter InterceptFilter
+		want   bool
+	}{
+		{"empty_matches_all", InterceptFilter{}, true},
+		{"host_glob", InterceptFilter{Host: "*.example.com"}, true},
+		{"host_case_insensitive", InterceptFilter{Host: "API.EXAMPLE.COM"}, true},
+		{"host_mismatch", InterceptFilter{Host: "other.com"}, false},
+		{"path_glob", InterceptFilter{Path: "/api/*"}, true},
+		{"path_mismatch", InterceptFilter{Path: "/admin/*"}, false},
+		{"method_case_insensitive", InterceptFilter{Method: "post"}, true},
+		{"method_mismatch", InterceptFilter{Method: "GET"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.matches("api.example.com", "POST", "/api/users"))
+		})
+	}
+}
+
+// holdAsync runs HoldRequest in the background, returning a channel with its decision.
+func holdAsync(ctx context.Context, q *interceptQueue, method, path string) <-chan bool {
+	result := make(chan bool, 1)
+	go func() {
+		result <- q.HoldRequest(ctx, "example.com", &proxy.RawHTTP1Request{Method: method, Path: path})
+	}()
+	return result
+}
+
+func waitHeld(t *testing.T, q *interceptQueue, n int) []HeldRequest {
+	t.Helper()
+
+	require.Eventually(t, func() bool { return len(q.status().Held) == n }, 5*time.Second, time.Millisecond)
+	return q.status().Held
+}
+
+func TestInterceptQueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("off_passes_through", func(t *testing.T) {
+		var q interceptQueue
+		assert.False(t, q.Intercepting())
+		assert.True(t, <-holdAsync(t.Context(), &q, "GET", "/"))
+	})
+
+	t.Run("on_off_transitions", func(t *testing.T) {
+		var q interceptQueue
+		q.set(true, InterceptFilter{Host: "*.example.com"})
+		assert.True(t, q.Intercepting())
+		assert.Equal(t, InterceptStatus{Enabled: true, Filter: InterceptFilter{Host: "*.example.com"}}, q.status())
+
+		q.set(false, InterceptFilter{})
+		assert.False(t, q.Intercepting())
+		assert.Equal(t, InterceptStatus{}, q.status())
+	})
+
+	t.Run("filter_mismatch_passes_through", func(t *testing.T) {
+		var q interceptQueue
+		q.set(true, InterceptFilter{Method: "POST"})
+		assert.True(t, <-holdAsync(t.Context(), &q, "GET", "/"))
+		assert.Empty(t, q.status().Held)
+	})
+
+	t.Run("release_one", func(t *testing.T) {
+		var q interceptQueue
+		q.set(true, InterceptFilter{})
+		first := holdAsync(t.Context(), &q, "GET", "/first")
+		held := waitHeld(t, &q, 1)
+		second := holdAsync(t.Context(), &q, "GET", "/second")
+		waitHeld(t, &q, 2)
+		assert.Equal(t, "/first", held[0].Path)
+
+		n, err := q.release(held[0].ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.True(t, <-first)
+
+		remaining := q.status().Held
+		require.Len(t, remaining, 1)
+		assert.Equal(t, "/second", remaining[0].Path)
+
+		n, err = q.release(remaining[0].ID, true)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.False(t, <-second)
+	})
+
+	t.Run("release_all", func(t *testing.T) {
+		var q interceptQueue
+		q.set(true, InterceptFilter{})
+		results := []<-chan bool{holdAsync(t.Context(), &q, "GET", "/a"), holdAsync(t.Context(), &q, "GET", "/b")}
+		waitHeld(t, &q, 2)
+
+		n, err := q.release("", false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		for _, r := range results {
+			assert.True(t, <-r)
+		}
+	})
+
+	t.Run("release_unknown", func(t *testing.T) {
+		var q interceptQueue
+		_, err := q.release("missing", false)
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("context_done_drops", func(t *testing.T) {
+		var q interceptQueue
+		q.set(true, InterceptFilter{})
+		ctx, cancel := context.WithCancel(t.Context())
+		result := holdAsync(ctx, &q, "GET", "/")
+		waitHeld(t, &q, 1)
+
+		cancel()
+		assert.False(t, <-result)
+		assert.Empty(t, q.status().Held)
+	})
+
+	t.Run("query_in_held_path", func(t *testing.T) {
+		var q interceptQueue
+		q.set(true, InterceptFilter{Path: "/search"})
+		result := make(chan bool, 1)
+		go func() {
+			result <- q.HoldRequest(t.Context(), "example.com", &proxy.RawHTTP1Request{Method: "GET", Path: "/search", Query: "q=1"})
+		}()
+		held := waitHeld(t, &q, 1)
+		assert.Equal(t, "/search?q=1", held[0].Path)
+
+		_, err := q.release("", true)
+		require.NoError(t, err)
+		assert.False(t, <-result)
+	})
+}
+
+func TestNativeProxyBackend_Intercept(t *testing.T) {
+	t.Parallel()
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK"))
+	}))
+	t.Cleanup(testServer.Close)
+
+	backend, err := NewNativeProxyBackend(0, t.TempDir(), 10*1024*1024, store.NewMemStorage(), store.NewMemStorage(), proxy.TimeoutConfig{})
+	require.NoError(t, err)
+	go func() { _ = backend.Serve() }()
+	t.Cleanup(func() { _ = backend.Close() })
+
+	proxyURL, _ := url.Parse("http://" + backend.Addr())
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+	get := func(path string) <-chan int {
+		status := make(chan int, 1)
+		go func() {
+			resp, err := client.Get(testServer.URL + path)
+			if err != nil {
+				status <- 0
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+			status <- resp.StatusCode
+		}()
+		return status
+	}
+
+	require.NoError(t, backend.SetIntercept(t.Context(), true, InterceptFilter{Path: "/held*"}))
+	assert.True(t, backend.InterceptStatus().Enabled)
+
+	assert.Equal(t, http.StatusOK, <-get("/free"))
+
+	released := get("/held/1")
+	require.Eventually(t, func() bool { return len(backend.InterceptStatus().Held) == 1 }, 5*time.Second, time.Millisecond)
+	held := backend.InterceptStatus().Held[0]
+	assert.Equal(t, "GET", held.Method)
+	assert.Equal(t, "/held/1", held.Path)
+
+	n, err := backend.ReleaseHeld(held.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, http.StatusOK, <-released)
+
+	dropped := get("/held/2")
+	require.Eventually(t, func() bool { return len(backend.InterceptStatus().Held) == 1 }, 5*time.Second, time.Millisecond)
+	_, err = backend.ReleaseHeld("", true)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadGateway, <-dropped)
+
+	// Turning intercept off forwards anything still held
+	pending := get("/held/3")
+	require.Eventually(t, func() bool { return len(backend.InterceptStatus().Held) == 1 }, 5*time.Second, time.Millisecond)
+	require.NoError(t, backend.SetIntercept(t.Context(), false, InterceptFilter{}))
+	assert.Equal(t, http.StatusOK, <-pending)
+	assert.Equal(t, InterceptStatus{}, backend.InterceptStatus())
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"mime"
+	"regexp"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+// findingJSONP marks a JavaScript response wrapping its payload in a callback named by a request parameter.
+const findingJSONP = "jsonp"
+
+// jsonpCallRe matches a JSONP body: an optional /**/ guard and typeof check, then the callback invocation.
+var jsonpCallRe = regexp.MustCompile(`^\s*(?:/\*\*/\s*)?(?:typeof\s+[\w$.]+\s*===?\s*['"]function['"]\s*&&\s*)?([A-Za-z_$][\w$]*(?:\.[A-Za-z_$][\w$]*)*)\s*\(`)
+
+// isJavaScriptContentType reports whether a Content-Type value names a JavaScript media type.
+func isJavaScriptContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/javascript", "text/javascript", "application/x-javascript",
+		"application/ecmascript", "text/ecmascript":
+		return true
+	}
+	return false
+}
+
+// detectJSONP returns the name of the request parameter whose value is the function a
+// JavaScript response body calls, "" when the response is not JSONP. Only query, form
+// and JSON body parameters are considered, since those are what a cross-site <script>
+// include controls.
+func detectJSONP(params []protocol.Reflection, contentType string, body []byte) string {
+	if !isJavaScriptContentType(contentType) {
+		return ""
+	}
+	m := jsonpCallRe.FindSubmatch(body)
+	if m == nil {
+		return ""
+	}
+	callback := string(m[1])
+	for _, p := range params {
+		switch p.Source {
+		case "query", "body", "json":
+			if p.Value == callback {
+				return p.Name
+			}
+		}
+	}
+	return ""
+}
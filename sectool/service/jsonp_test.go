@@ -0,0 +1,95 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func TestDetectJSONP(t *testing.T) {
+	t.Parallel()
+
+	params := []protocol.Reflection{
+		{Name: "callback", Source: "query", Value: "handleData"},
+		{Name: "ns", Source: "body", Value: "app.cb.run"},
+		{Name: "X-Callback", Source: "header", Value: "fromHeader"},
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"plain_call", "application/javascript", `handleData({"a":1});`, "callback"},
+		{"guarded", "text/javascript; charset=utf-8", "/**/ typeof handleData === 'function' && handleData({});", "callback"},
+		{"leading_whitespace", "application/x-javascript", "\n  handleData ([1,2])", "callback"},
+		{"dotted_name", "application/javascript", `app.cb.run({"a":1})`, "ns"},
+		{"json_content_type", "application/json", `handleData({"a":1})`, ""},
+		{"callback_not_param", "application/javascript", `otherFn({"a":1})`, ""},
+		{"header_source_ignored", "application/javascript", `fromHeader({})`, ""},
+		{"not_a_call", "application/javascript", `var handleData = 1;`, ""},
+		{"prefix_mismatch", "application/javascript", `handleDataX({})`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectJSONP(params, tt.contentType, []byte(tt.body)))
+		})
+	}
+}
+
+func TestCollyBackend_JSONP(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<a href="/api?callback=render">api</a><a href="/static">static</a>`))
+		case "/api":
+			w.Header().Set("Content-Type", "application/javascript")
+			_, _ = w.Write([]byte(r.URL.Query().Get("callback") + `({"user":"alice"});`))
+		case "/static":
+			w.Header().Set("Content-Type", "application/javascript")
+			_, _ = w.Write([]byte(`render({"user":"alice"});`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b := NewCollyBackend(config.DefaultConfig(), nil, nil)
+	t.Cleanup(func() { _ = b.Close() })
+
+	sess, err := b.CreateSession(t.Context(), CrawlOptions{
+		Seeds:           []CrawlSeed{{URL: server.URL + "/"}},
+		Delay:           time.Millisecond,
+		IgnoreRobotsTxt: true,
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		status, err := b.GetStatus(t.Context(), sess.ID)
+		return err == nil && status.State == crawlStateCompleted
+	}, 10*time.Second, 10*time.Millisecond)
+
+	flows, err := b.ListFlows(t.Context(), sess.ID, CrawlListOptions{})
+	require.NoError(t, err)
+
+	byPath := make(map[string]CrawlFlow)
+	for _, f := range flows {
+		byPath[f.Path] = f
+	}
+	require.Contains(t, byPath, "/api?callback=render")
+	api := byPath["/api?callback=render"]
+	assert.Equal(t, []string{findingJSONP}, api.Findings)
+	assert.Equal(t, "callback", api.JSONPCallback)
+
+	require.Contains(t, byPath, "/static")
+	assert.Empty(t, byPath["/static"].Findings)
+}
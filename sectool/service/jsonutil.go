@@ -185,6 +185,28 @@ func parseJSONPath(path string) ([]pathSegment, error) {
 	return segments, nil
 }
 
+// getValueAtPath returns the value at the path, or false if any segment is missing.
+func getValueAtPath(data interface{}, segments []pathSegment) (interface{}, bool) {
+	for _, seg := range segments {
+		if seg.Index >= 0 {
+			arr, ok := data.([]interface{})
+			if !ok || seg.Index >= len(arr) {
+				return nil, false
+			}
+			data = arr[seg.Index]
+		} else {
+			obj, ok := data.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if data, ok = obj[seg.Key]; !ok {
+				return nil, false
+			}
+		}
+	}
+	return data, true
+}
+
 // setValueAtPath recursively sets a value at the path.
 func setValueAtPath(data interface{}, segments []pathSegment, value interface{}) (interface{}, error) {
 	if len(segments) == 0 {
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrKillSwitchEngaged is returned for traffic-generating operations while the kill switch is engaged.
+var ErrKillSwitchEngaged = errors.New("kill switch engaged")
+
+// proxyHalter is implemented by HTTP backends whose proxy listener can refuse client traffic.
+type proxyHalter interface {
+	SetHalted(halted bool)
+}
+
+// killSwitch is the service-wide emergency stop. While engaged, every operation that sends
+// traffic to a target is refused, and engaging it cancels those already in flight.
+type killSwitch struct {
+	mu        sync.Mutex
+	engaged   bool
+	reason    string
+	engagedAt time.Time
+	ctx       context.Context // cancelled when the switch engages, replaced when cleared
+	cancel    context.CancelFunc
+}
+
+// KillSwitchStatus describes the kill switch state and what engaging it stopped.
+type KillSwitchStatus struct {
+	Engaged       bool
+	Reason        string
+	EngagedAt     time.Time
+	StoppedCrawls int  // crawl sessions stopped when engaged
+	ProxyHalted   bool // built-in proxy dropped client connections
+}
+
+// activity returns the context in-flight operations derive from, or an error when engaged.
+func (k *killSwitch) activity() (context.Context, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.engaged {
+		return nil, k.errLocked()
+	}
+	if k.ctx == nil {
+		k.ctx, k.cancel = context.WithCancel(context.Background())
+	}
+	return k.ctx, nil
+}
+
+func (k *killSwitch) errLocked() error {
+	msg := "engaged at " + k.engagedAt.UTC().Format(time.RFC3339)
+	if k.reason != "" {
+		msg += " (" + k.reason + ")"
+	}
+	return fmt.Errorf("%w %s; clear it with 'sectool panic clear'", ErrKillSwitchEngaged, msg)
+}
+
+// EngageKillSwitch cancels all in-flight replay and request traffic, stops every running crawl,
+// halts the built-in proxy, and refuses new traffic until ClearKillSwitch is called.
+func (s *Server) EngageKillSwitch(ctx context.Context, reason string) KillSwitchStatus {
+	k := &s.killSwitch
+	k.mu.Lock()
+	if !k.engaged {
+		k.engaged = true
+		k.reason = reason
+		k.engagedAt = time.Now()
+		if k.cancel != nil {
+			k.cancel()
+		}
+		k.ctx, k.cancel = nil, nil
+	}
+	status := KillSwitchStatus{Engaged: true, Reason: k.reason, EngagedAt: k.engagedAt}
+	k.mu.Unlock()
+
+	banner := strings.Repeat("!", 80)
+	log.Print(banner)
+	log.Printf("!!! KILL SWITCH ENGAGED: all target traffic is halted (reason=%q)", status.Reason)
+	log.Print(banner)
+
+	if halter, ok := s.httpBackend.(proxyHalter); ok {
+		halter.SetHalted(true)
+		status.ProxyHalted = true
+		log.Printf("!!! kill switch: proxy is refusing client connections")
+	}
+	if interceptor, ok := s.httpBackend.(proxyInterceptor); ok {
+		if dropped, _ := interceptor.ReleaseHeld("", true); dropped > 0 {
+			log.Printf("!!! kill switch: dropped %d intercepted requests", dropped)
+		}
+	}
+
+	if s.crawlerBackend != nil {
+		sessions, err := s.crawlerBackend.ListSessions(ctx, 0)
+		if err != nil {
+			log.Printf("!!! kill switch: failed to list crawl sessions: %v", err)
+		}
+		for _, sess := range sessions {
+			if sess.State != crawlStateRunning {
+				continue
+			}
+			if err := s.crawlerBackend.StopSession(ctx, sess.ID); err != nil {
+				log.Printf("!!! kill switch: failed to stop crawl session %s: %v", sess.ID, err)
+				continue
+			}
+			status.StoppedCrawls++
+		}
+		log.Printf("!!! kill switch: stopped %d crawl sessions", status.StoppedCrawls)
+	}
+
+	return status
+}
+
+// ClearKillSwitch re-enables traffic and reports whether the switch was engaged.
+// Crawls stopped by the kill switch are not resumed.
+func (s *Server) ClearKillSwitch() bool {
+	k := &s.killSwitch
+	k.mu.Lock()
+	wasEngaged := k.engaged
+	k.engaged = false
+	k.reason = ""
+	k.engagedAt = time.Time{}
+	k.mu.Unlock()
+
+	if halter, ok := s.httpBackend.(proxyHalter); ok {
+		halter.SetHalted(false)
+	}
+	if wasEngaged {
+		log.Printf("kill switch cleared: target traffic re-enabled")
+	}
+	return wasEngaged
+}
+
+// KillSwitchState reports whether the kill switch is engaged.
+func (s *Server) KillSwitchState() KillSwitchStatus {
+	k := &s.killSwitch
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return KillSwitchStatus{Engaged: k.engaged, Reason: k.reason, EngagedAt: k.engagedAt}
+}
+
+// killSwitchErr returns an error wrapping ErrKillSwitchEngaged while the kill switch is engaged.
+func (s *Server) killSwitchErr() error {
+	s.killSwitch.mu.Lock()
+	defer s.killSwitch.mu.Unlock()
+	if s.killSwitch.engaged {
+		return s.killSwitch.errLocked()
+	}
+	return nil
+}
+
+// activityContext returns a context derived from ctx that is also cancelled when the kill
+// switch engages. It returns an error wrapping ErrKillSwitchEngaged if it already is.
+func (s *Server) activityContext(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	killCtx, err := s.killSwitch.activity()
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(killCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_KillSwitch(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{}
+
+	ctx, done, err := s.activityContext(t.Context())
+	require.NoError(t, err)
+	t.Cleanup(done)
+
+	status := s.EngageKillSwitch(t.Context(), "owner asked to stop")
+	assert.True(t, status.Engaged)
+	assert.Equal(t, "owner asked to stop", status.Reason)
+
+	// In-flight activity is cancelled and new activity refused
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("activity context not cancelled by kill switch")
+	}
+	_, _, err = s.activityContext(t.Context())
+	require.ErrorIs(t, err, ErrKillSwitchEngaged)
+	assert.Contains(t, err.Error(), "owner asked to stop")
+
+	// Engaging again keeps the original reason
+	assert.Equal(t, "owner asked to stop", s.EngageKillSwitch(t.Context(), "again").Reason)
+
+	assert.True(t, s.ClearKillSwitch())
+	assert.False(t, s.KillSwitchState().Engaged)
+	assert.NoError(t, s.killSwitchErr())
+
+	ctx, done, err = s.activityContext(t.Context())
+	require.NoError(t, err)
+	t.Cleanup(done)
+	assert.NoError(t, ctx.Err())
+	assert.False(t, s.ClearKillSwitch())
+}
@@ -1,11 +1,14 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"log"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -35,17 +38,52 @@ The crawler automatically:
 		mcp.WithString("seed_flows", mcp.Description("Comma-separated list of proxy flow_ids to use as seeds")),
 		mcp.WithString("domains", mcp.Description("Comma-separated list of additional domains to allow")),
 		mcp.WithObject("headers", mcp.Description("Custom headers as object: {\"Name\": \"Value\"}")),
+		mcp.WithObject("domain_headers", mcp.Description("Per-host headers keyed by host glob: {\"api.example.com\": {\"Authorization\": \"Bearer x\"}, \"*.example.com\": {...}}. Only sent to matching hosts")),
+		mcp.WithObject("resolve", mcp.Description("Pin hostnames to IPs like curl --resolve: {\"host\": \"ip\"} or [\"host=ip\"]. Host header and SNI keep the original name")),
 		mcp.WithNumber("max_depth", mcp.Description("Maximum crawl depth (0 = unlimited)")),
 		mcp.WithNumber("max_requests", mcp.Description("Maximum total requests (0 = unlimited)")),
+		mcp.WithNumber("max_hosts", mcp.Description("Maximum distinct hosts to request; links to further new hosts are skipped and reported (0 = unlimited)")),
+		mcp.WithNumber("max_pages_per_host", mcp.Description("Maximum requests per host so one large host cannot exhaust max_requests; skips are reported per host (0 = unlimited)")),
+		mcp.WithNumber("block_threshold", mcp.Description("Stop the crawl after this many consecutive identical 403/429 or 503 CAPTCHA-challenge responses from one host, labeling them 'blocked' or 'rate-limited' in findings; crawl_status reports blocked_note (0 = disabled)")),
+		mcp.WithNumber("max_body_bytes", mcp.Description("Maximum response body bytes captured per flow for this crawl, overriding the server's max_body_bytes; larger bodies are truncated and flagged (0 = server default, negative = unlimited)")),
+		mcp.WithNumber("max_in_flight_bytes", mcp.Description("Maximum response bytes buffered at once across concurrent requests; reads wait while over the limit, smoothing memory use with large responses (0 = unlimited)")),
 		mcp.WithString("delay", mcp.Description("Delay between requests (e.g., '200ms', '1s')")),
 		mcp.WithNumber("parallelism", mcp.Description("Number of concurrent requests (default: 2)")),
+		mcp.WithArray("host_limits", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Per-host rate limits as \"host_glob=delay[,parallelism[,random_delay]]\" entries, e.g. [\"cdn.example.com=0s,8\", \"origin.example.com=2s,1\"]. The glob matches the URL host (with port when explicit); the first matching entry applies, other hosts use delay/parallelism. Omitted parallelism uses the session's")),
+		mcp.WithBoolean("deterministic", mcp.Description("Visit one URL at a time, breadth-first and in sorted URL order within each depth, so the same site yields the same crawl order and flows; slower, overrides parallelism. Use to compare crawls or debug coverage (default: false)")),
+		mcp.WithString("strategy", mcp.Description("Order discovered links are visited in: 'bfs' (default) crawls shallower pages first; 'dfs' follows the links of each page before its siblings', exhausting a branch down to max_depth before backtracking. dfs visits one URL at a time, overriding parallelism; with deterministic, siblings are visited in sorted URL order")),
 		mcp.WithBoolean("ignore_robots", mcp.Description("Ignore robots.txt restrictions (default: false)")),
+		mcp.WithBoolean("extract_json_urls", mcp.Description("Follow URLs found in JSON response values, e.g. next, href, _links (default: false)")),
+		mcp.WithBoolean("extract_css_urls", mcp.Description("Fetch stylesheets and follow url() and @import references in CSS (default: false)")),
+		mcp.WithBoolean("extract_comment_urls", mcp.Description("Follow URLs and paths found in HTML comments; comments with URLs, credentials, TODOs or internal hosts are recorded as 'html-comment' findings (default: false)")),
+		mcp.WithNumber("similarity_threshold", mcp.Description("Cluster near-identical text responses (same host, status and media type) whose body SimHash similarity is at least this, from 0 to 1 (e.g. 0.9), so templated pages differing only in timestamps or tokens collapse into one representative; listed by crawl_poll output_mode=similar (0 = disabled)")),
+		mcp.WithBoolean("extract_js_endpoints", mcp.Description("Fetch <script src> bundles and record request URLs in JavaScript (fetch/axios/XHR call literals and quoted paths), listed by crawl_poll output_mode=endpoints. Same-origin endpoints not declared with a non-GET method are crawled, subject to depth and path filters (default: false)")),
+		mcp.WithBoolean("extract_js_routes", mcp.Description("Fetch <script src> bundles and record client-side SPA routes declared in scripts (router path tables, #!/ hash-bang links, History API navigation), listed by crawl_poll output_mode=routes. Routes are not visited (default: false)")),
+		mcp.WithBoolean("detect_dir_listing", mcp.Description("Flag directory-listing (Index of /) pages with a 'directory-listing' finding; listed entries are crawled as links (default: false)")),
+		mcp.WithBoolean("vary_variants", mcp.Description("For GET responses with a Vary header, re-request the URL once per listed header (User-Agent, Accept, Accept-Language, X-Requested-With) with a different value; variants are flows with variant_of/varied_header and a 'vary-variant-differs' finding when status, content type or size differ significantly (default: false)")),
+		mcp.WithBoolean("merge_trailing_slash", mcp.Description("Treat /path and /path/ as one URL: only the first-discovered variant is crawled unless it fails, then the other is crawled too. Merged counts are reported in crawl_status (default: false)")),
+		mcp.WithArray("allow_path_regex", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Regular expressions (Go syntax, unanchored) matched against URL paths; when set, only matching paths are requested, e.g. '^/users/\\d+$'")),
+		mcp.WithArray("deny_path_regex", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Regular expressions (Go syntax, unanchored) matched against URL paths; matching paths are not requested, in addition to the configured disallowed_paths globs")),
+		mcp.WithString("allowed_content_types", mcp.Description("Comma-separated Content-Type prefixes of responses recorded as flows, replacing the default text/, application/json (and +json types such as application/hal+json), application/xml and JavaScript types (e.g. 'text/html,application/pdf'); responses without a Content-Type are always recorded")),
+		mcp.WithString("extra_content_types", mcp.Description("Comma-separated Content-Type prefixes recorded in addition to the defaults or allowed_content_types, e.g. 'application/pdf,application/zip' to capture exposed documents and archives")),
+		mcp.WithString("ignore_query_params", mcp.Description("Comma-separated query parameter names to strip from discovered links before dedup and visiting; globs match case-insensitively (e.g. 'utm_*,fbclid,sessionid'). Flows whose URL was changed report original_url in crawl_get")),
+		mcp.WithString("doh_resolver", mcp.Description("DNS-over-HTTPS resolver URL (RFC 8484, e.g. 'https://10.0.0.53/dns-query') used to resolve crawl hostnames instead of the system resolver; 'resolve' pins still take precedence")),
+		mcp.WithString("referer", mcp.Description("Referer sent when following discovered links. Default sends the parent page URL reduced per its Referrer-Policy header or <meta name=referrer> (browser default strict-origin-when-cross-origin); 'none' omits it; any other value is sent as-is. Each flow's declared policy is shown in crawl_get")),
+		mcp.WithString("completion_webhook", mcp.Description("http(s) URL POSTed a JSON summary (session_id, label, state, counts, status_codes, findings) when the crawl completes or is stopped; delivery failures are only logged")),
+		mcp.WithBoolean("no_cookie_jar", mcp.Description("Disable the session cookie jar: seed flow cookies are sent unchanged on every request and cookies set by responses are ignored. By default the jar is seeded with the seed flow cookies and carries cookies set or rotated by responses to later requests (default: false)")),
+		mcp.WithBoolean("follow_links_on_error", mcp.Description("Record 4xx/5xx responses as flows and follow links in their HTML; they are also listed as errors with a flow_id (default: false)")),
+		mcp.WithObject("token_refresh", mcp.Description("Refresh a short-lived bearer token when responses return 401: {\"url\": \"https://api.example.com/oauth/token\", \"method\": \"POST\", \"body\": \"grant_type=refresh_token&refresh_token=...\", \"headers\": {...}, \"token_path\": \"access_token\"}. token_path is a dot-notation JSON path into the response. The new token is sent as 'Authorization: Bearer <token>' on later requests to the hosts that returned 401, never over a matching domain_headers Authorization, and the rejected request is retried once; each refresh is listed in crawl_status token_refreshes. Body is sent as JSON when valid JSON, otherwise form-encoded; method defaults to POST with a body")),
+		mcp.WithBoolean("preflight_seed", mcp.Description("Fetch the first seed URL before creating the session and fail with an error if it is unreachable or behind an auth wall (401, or a redirect to a login page; a 401 is accepted with token_refresh) (default: true)")),
+		mcp.WithBoolean("safe_mode", mcp.Description("Refuse to send DELETE/PUT/PATCH requests and to submit forms whose action or method override looks destructive (delete, remove, logout, ...), regardless of path filters; each refusal is logged. Set false only when mutating the target is acceptable (default: true)")),
+		mcp.WithBoolean("check_form_methods", mcp.Description("Submit each GET/POST form with both methods and compare responses; forms answering the undeclared method identically are flagged (method tampering). Sends requests to form actions (default: false)")),
 	)
 }
 
 func (m *mcpServer) handleCrawlCreate(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if err := m.requireWorkflow(); err != nil {
 		return err, nil
+	} else if err := m.service.killSwitchErr(); err != nil {
+		return errorResult(err.Error()), nil
 	}
 
 	// Parse seed URLs and flows
@@ -67,6 +105,16 @@ func (m *mcpServer) handleCrawlCreate(ctx context.Context, req mcp.CallToolReque
 		domains = parseCommaSeparated(domainsStr)
 	}
 
+	var ignoreQueryParams []string
+	if paramsStr := req.GetString("ignore_query_params", ""); paramsStr != "" {
+		ignoreQueryParams = parseCommaSeparated(paramsStr)
+	}
+
+	similarityThreshold := req.GetFloat("similarity_threshold", 0)
+	if similarityThreshold < 0 || similarityThreshold > 1 {
+		return errorResult("similarity_threshold must be between 0 and 1"), nil
+	}
+
 	// Parse delay
 	var delay time.Duration
 	if delayStr := req.GetString("delay", ""); delayStr != "" {
@@ -77,15 +125,91 @@ func (m *mcpServer) handleCrawlCreate(ctx context.Context, req mcp.CallToolReque
 		delay = parsed
 	}
 
+	// Parse headers from object {"Name":"Value"} or array ["Name: Value"]
+	var headers map[string]string
+	var domainHeaders map[string]map[string]string
+	var hostResolution map[string]string
+	var hostLimits []CrawlHostLimit
+	var tokenRefresh *TokenRefresh
+	if args := req.GetArguments(); args != nil {
+		if raw, ok := args["headers"]; ok && raw != nil {
+			headers = headerArgToMap(raw)
+		}
+		if raw, ok := args["domain_headers"]; ok && raw != nil {
+			byHost, ok := raw.(map[string]interface{})
+			if !ok {
+				return errorResult("domain_headers must be an object keyed by host glob"), nil
+			}
+			domainHeaders = make(map[string]map[string]string, len(byHost))
+			for host, hostHeaders := range byHost {
+				if h := headerArgToMap(hostHeaders); len(h) > 0 {
+					domainHeaders[host] = h
+				}
+			}
+		}
+		if raw, ok := args["resolve"]; ok && raw != nil {
+			var err error
+			if hostResolution, err = parseHostResolutionArg(raw); err != nil {
+				return errorResult(err.Error()), nil
+			}
+		}
+		if raw, ok := args["host_limits"]; ok && raw != nil {
+			var err error
+			if hostLimits, err = parseHostLimitsArg(raw); err != nil {
+				return errorResult(err.Error()), nil
+			}
+		}
+		if raw, ok := args["token_refresh"]; ok && raw != nil {
+			var err error
+			if tokenRefresh, err = parseTokenRefreshArg(raw); err != nil {
+				return errorResult(err.Error()), nil
+			}
+		}
+	}
+
 	opts := CrawlOptions{
-		Label:           req.GetString("label", ""),
-		Seeds:           seeds,
-		ExplicitDomains: domains,
-		MaxDepth:        req.GetInt("max_depth", 0),
-		MaxRequests:     req.GetInt("max_requests", 0),
-		Delay:           delay,
-		Parallelism:     req.GetInt("parallelism", 0),
-		IgnoreRobotsTxt: req.GetBool("ignore_robots", false),
+		Label:                req.GetString("label", ""),
+		Seeds:                seeds,
+		ExplicitDomains:      domains,
+		MaxDepth:             req.GetInt("max_depth", 0),
+		MaxRequests:          req.GetInt("max_requests", 0),
+		MaxHosts:             req.GetInt("max_hosts", 0),
+		MaxPagesPerHost:      req.GetInt("max_pages_per_host", 0),
+		BlockThreshold:       req.GetInt("block_threshold", 0),
+		MaxInFlightBytes:     int64(req.GetInt("max_in_flight_bytes", 0)),
+		MaxResponseBodyBytes: req.GetInt("max_body_bytes", 0),
+		Delay:                delay,
+		Parallelism:          req.GetInt("parallelism", 0),
+		HostLimits:           hostLimits,
+		Deterministic:        req.GetBool("deterministic", false),
+		Strategy:             req.GetString("strategy", ""),
+		IgnoreRobotsTxt:      req.GetBool("ignore_robots", false),
+		ExtractJSONURLs:      req.GetBool("extract_json_urls", false),
+		ExtractCSSURLs:       req.GetBool("extract_css_urls", false),
+		ExtractCommentURLs:   req.GetBool("extract_comment_urls", false),
+		ExtractJSRoutes:      req.GetBool("extract_js_routes", false),
+		ExtractJSEndpoints:   req.GetBool("extract_js_endpoints", false),
+		SimilarityThreshold:  similarityThreshold,
+		DetectDirListing:     req.GetBool("detect_dir_listing", false),
+		MergeTrailingSlash:   req.GetBool("merge_trailing_slash", false),
+		VaryVariants:         req.GetBool("vary_variants", false),
+		CheckFormMethods:     req.GetBool("check_form_methods", false),
+		SafeMode:             req.GetBool("safe_mode", true),
+		FollowLinksOnError:   req.GetBool("follow_links_on_error", false),
+		Headers:              headers,
+		DomainHeaders:        domainHeaders,
+		HostResolution:       hostResolution,
+		CompletionWebhook:    req.GetString("completion_webhook", ""),
+		PreflightSeed:        req.GetBool("preflight_seed", true),
+		TokenRefresh:         tokenRefresh,
+		DoHResolver:          req.GetString("doh_resolver", ""),
+		Referer:              req.GetString("referer", ""),
+		IgnoreQueryParams:    ignoreQueryParams,
+		AllowedPathsRegex:    req.GetStringSlice("allow_path_regex", nil),
+		AllowedContentTypes:  parseCommaSeparated(req.GetString("allowed_content_types", "")),
+		ExtraContentTypes:    parseCommaSeparated(req.GetString("extra_content_types", "")),
+		DisallowedPathsRegex: req.GetStringSlice("deny_path_regex", nil),
+		NoCookieJar:          req.GetBool("no_cookie_jar", false),
 		// SubmitForms and ExtractForms left unset to use config defaults
 	}
 
@@ -95,6 +219,10 @@ func (m *mcpServer) handleCrawlCreate(ctx context.Context, req mcp.CallToolReque
 			return errorResult("label already exists: " + err.Error()), nil
 		}
 		return errorResultFromErr("failed to create crawl session: ", err), nil
+	} else if err := m.service.killSwitchErr(); err != nil {
+		// Kill switch engaged while the session was being created
+		_ = m.service.crawlerBackend.StopSession(context.Background(), sess.ID)
+		return errorResult(err.Error()), nil
 	}
 
 	return jsonResult(protocol.CrawlCreateResponse{
@@ -119,6 +247,8 @@ Can only add seeds while session is running.`),
 func (m *mcpServer) handleCrawlSeed(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if err := m.requireWorkflow(); err != nil {
 		return err, nil
+	} else if err := m.service.killSwitchErr(); err != nil {
+		return errorResult(err.Error()), nil
 	}
 
 	sessionID := req.GetString("session_id", "")
@@ -160,6 +290,7 @@ func (m *mcpServer) crawlStatusTool() mcp.Tool {
 
 Returns progress metrics including URLs visited, queued, errors, and forms discovered.`),
 		mcp.WithString("session_id", mcp.Required(), mcp.Description("Session ID or label")),
+		mcp.WithString("wait", mcp.Description("Long-poll duration (e.g. '30s', max 120s, default '0s'): return the status once crawl activity changes or the session ends, or the current status when the wait expires. Changes are reported at most once a second")),
 	)
 }
 
@@ -173,9 +304,18 @@ func (m *mcpServer) handleCrawlStatus(ctx context.Context, req mcp.CallToolReque
 		return errorResult("session_id is required"), nil
 	}
 
-	log.Printf("mcp/crawl_status: getting status for session %s", sessionID)
+	var wait time.Duration
+	if waitStr := req.GetString("wait", ""); waitStr != "" {
+		parsed, err := time.ParseDuration(waitStr)
+		if err != nil {
+			return errorResult("invalid wait duration: " + err.Error()), nil
+		}
+		wait = min(parsed, 120*time.Second)
+	}
 
-	status, err := m.service.crawlerBackend.GetStatus(ctx, sessionID)
+	log.Printf("mcp/crawl_status: getting status for session %s (wait=%s)", sessionID, wait)
+
+	status, err := m.crawlStatus(ctx, sessionID, wait)
 	if err != nil {
 		if errors.Is(err, ErrNotFound) {
 			return errorResult("session not found"), nil
@@ -184,32 +324,193 @@ func (m *mcpServer) handleCrawlStatus(ctx context.Context, req mcp.CallToolReque
 	}
 
 	return jsonResult(protocol.CrawlStatusResponse{
-		State:           status.State,
-		URLsQueued:      status.URLsQueued,
-		URLsVisited:     status.URLsVisited,
-		URLsErrored:     status.URLsErrored,
-		FormsDiscovered: status.FormsDiscovered,
-		Duration:        status.Duration.Round(time.Millisecond).String(),
-		LastActivity:    status.LastActivity.UTC().Format(time.RFC3339),
-		ErrorMessage:    status.ErrorMessage,
+		State:               status.State,
+		URLsQueued:          status.URLsQueued,
+		URLsVisited:         status.URLsVisited,
+		URLsErrored:         status.URLsErrored,
+		FormsDiscovered:     status.FormsDiscovered,
+		Duration:            status.Duration.Round(time.Millisecond).String(),
+		LastActivity:        status.LastActivity.UTC().Format(time.RFC3339),
+		ErrorMessage:        status.ErrorMessage,
+		Hosts:               status.Hosts,
+		SkippedHosts:        status.SkippedHosts,
+		HostQuotaSkips:      status.HostQuotaSkips,
+		MergedSlashVariants: status.MergedSlashVariants,
+		BlockedNote:         status.BlockedNote,
+		TokenRefreshes:      tokenRefreshEvents(status.TokenRefreshes),
+		DiscoveredPaths:     discoveredPathsToAPI(status.DiscoveredPaths),
+	})
+}
+
+// crawlStatus returns the session status, or with a positive wait, the first status
+// streamed after the current one, falling back to the latest when wait expires first.
+func (m *mcpServer) crawlStatus(ctx context.Context, sessionID string, wait time.Duration) (*CrawlStatus, error) {
+	if wait <= 0 {
+		return m.service.crawlerBackend.GetStatus(ctx, sessionID)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	updates, err := m.service.crawlerBackend.StreamStatus(waitCtx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	var status *CrawlStatus
+	var received int
+	for s := range updates {
+		status = &s
+		if received++; received == 2 {
+			break
+		}
+	}
+	if status == nil {
+		return m.service.crawlerBackend.GetStatus(ctx, sessionID)
+	}
+	return status, nil
+}
+
+// parseTokenRefreshArg parses the token_refresh object of crawl_create.
+func parseTokenRefreshArg(raw interface{}) (*TokenRefresh, error) {
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("token_refresh must be an object with url and token_path")
+	}
+	str := func(key string) string {
+		v, _ := obj[key].(string)
+		return v
+	}
+	tr := &TokenRefresh{
+		URL:       str("url"),
+		Method:    str("method"),
+		Body:      str("body"),
+		TokenPath: str("token_path"),
+	}
+	if raw, ok := obj["headers"]; ok && raw != nil {
+		tr.Headers = headerArgToMap(raw)
+	}
+	return tr, nil
+}
+
+func discoveredPathsToAPI(paths []DiscoveredPath) []protocol.DiscoveredPath {
+	if len(paths) == 0 {
+		return nil
+	}
+	out := make([]protocol.DiscoveredPath, len(paths))
+	for i, p := range paths {
+		out[i] = protocol.DiscoveredPath{URL: p.URL, Source: p.Source}
+	}
+	return out
+}
+
+func tokenRefreshEvents(events []TokenRefreshEvent) []protocol.CrawlTokenRefresh {
+	if len(events) == 0 {
+		return nil
+	}
+	out := make([]protocol.CrawlTokenRefresh, 0, len(events))
+	for _, e := range events {
+		out = append(out, protocol.CrawlTokenRefresh{
+			Time:       e.Time.UTC().Format(time.RFC3339),
+			TriggerURL: e.TriggerURL,
+			Error:      e.Error,
+		})
+	}
+	return out
+}
+
+func (m *mcpServer) crawlStatsTool() mcp.Tool {
+	return mcp.NewTool("crawl_stats",
+		mcp.WithDescription(`Get status code and content type distributions for a crawl session.
+
+Counts all captured flows by HTTP status and response media type. Useful for gauging crawl health and spotting anomalies (e.g. many 5xx responses).
+Also reports search activity: literal search_header/search_body queries use the session's full-text index; regex queries scan every flow.`),
+		mcp.WithString("session_id", mcp.Required(), mcp.Description("Session ID or label")),
+	)
+}
+
+func (m *mcpServer) handleCrawlStats(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	sessionID := req.GetString("session_id", "")
+	if sessionID == "" {
+		return errorResult("session_id is required"), nil
+	}
+
+	log.Printf("mcp/crawl_stats: getting stats for session %s", sessionID)
+
+	stats, err := m.service.crawlerBackend.GetStats(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return errorResult("session not found"), nil
+		}
+		return errorResultFromErr("failed to get stats: ", err), nil
+	}
+
+	statusCodes := make([]protocol.StatusCodeCount, 0, len(stats.StatusCodes))
+	for code, count := range stats.StatusCodes {
+		statusCodes = append(statusCodes, protocol.StatusCodeCount{Status: code, Count: count})
+	}
+	slices.SortFunc(statusCodes, func(a, b protocol.StatusCodeCount) int {
+		return cmp.Compare(a.Status, b.Status)
+	})
+
+	contentTypes := make([]protocol.ContentTypeCount, 0, len(stats.ContentTypes))
+	for ct, count := range stats.ContentTypes {
+		contentTypes = append(contentTypes, protocol.ContentTypeCount{ContentType: ct, Count: count})
+	}
+	slices.SortFunc(contentTypes, func(a, b protocol.ContentTypeCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ContentType, b.ContentType)
+	})
+
+	search := protocol.CrawlSearchStats{
+		IndexEnabled:    stats.Search.IndexEnabled,
+		IndexedTokens:   stats.Search.IndexedTokens,
+		Searches:        stats.Search.Searches,
+		IndexedSearches: stats.Search.IndexedSearches,
+		ScanSearches:    stats.Search.ScanSearches,
+	}
+	if stats.Search.Searches > 0 {
+		avg := stats.Search.TotalLatency / time.Duration(stats.Search.Searches)
+		search.AvgLatency = avg.Round(time.Microsecond).String()
+		search.MaxLatency = stats.Search.MaxLatency.Round(time.Microsecond).String()
+	}
+
+	return jsonResult(protocol.CrawlStatsResponse{
+		SessionID:    sessionID,
+		TotalFlows:   stats.TotalFlows,
+		StatusCodes:  statusCodes,
+		ContentTypes: contentTypes,
+		Search:       search,
 	})
 }
 
 func (m *mcpServer) crawlPollTool() mcp.Tool {
 	return mcp.NewTool("crawl_poll",
-		mcp.WithDescription(`Query crawl session results: summary (default), flows, forms, or errors.
+		mcp.WithDescription(`Query crawl session results: summary (default), flows, forms, errors, external, routes, endpoints, similar, or findings.
 
 Output modes:
 - "summary" (default): Returns traffic grouped by (host, path, method, status). Path patterns replace numeric IDs and UUIDs with * for grouping.
 - "flows": Returns crawled flows with flow_id for use with crawl_get.
 - "forms": Returns discovered forms with field information.
 - "errors": Returns errors encountered during crawling.
+- "external": Returns out-of-scope link and redirect targets (deduped, with referring page or redirecting URL). Never fetched.
+- "routes": Returns client-side SPA routes found in scripts (requires extract_js_routes on crawl_create), with the declaring script or page. Never fetched.
+- "endpoints": Returns request URLs found in JavaScript (requires extract_js_endpoints on crawl_create): kind fetch/axios/xhr/string, declared method when known, and the declaring script.
+- "similar": Returns clusters of near-identical responses (requires similarity_threshold on crawl_create), largest first, each with a representative flow and member flow_ids.
+- "findings": Returns passive findings per flow in discovery order. Sensitive data found in response bodies by the configured secret_patterns (AWS/Google API keys, JWTs, private keys, emails, card numbers, high-entropy tokens) is listed once per match with the pattern name, body offset and a redacted snippet.
 
-Filters apply to summary and flows modes: host/path/exclude_host/exclude_path use glob (*, ?). method/status are comma-separated (status supports ranges like 2XX).
+Filters apply to summary and flows modes: host/path/exclude_host/exclude_path/content_type use glob (*, ?). method/status are comma-separated (status supports ranges like 2XX).
 Search: search_header/search_body use regex; literal if invalid.
-Incremental (summary/flows): since accepts flow_id or "last" (cursor). Flows mode only: pagination with limit/offset.`),
+duplicate_of=<flow_id> keeps flows sharing that flow's endpoint fingerprint (host, method, ID-normalized path, parameter names).
+invert=true returns flows matching none of the combined filters and searches (e.g. status=2XX with invert for all non-2xx flows).
+Incremental (summary/flows): since accepts flow_id or "last" (cursor). Pass cursor=<name> for an independent "last" position per consumer. Flows mode only: pagination with limit/offset.
+Long-poll (flows mode): wait blocks up to that duration until a flow matching the filters is available, returning early when the crawl ends; since=last with wait gives incremental results during an active crawl without busy-polling.`),
 		mcp.WithString("session_id", mcp.Required(), mcp.Description("Session ID or label")),
-		mcp.WithString("output_mode", mcp.Description("Output mode: 'summary' (default), 'flows', 'forms', or 'errors'")),
+		mcp.WithString("output_mode", mcp.Description("Output mode: 'summary' (default), 'flows', 'forms', 'errors', 'external', 'routes', 'endpoints', 'similar', or 'findings'")),
 		mcp.WithString("host", mcp.Description("Filter by host glob pattern (e.g., '*.example.com')")),
 		mcp.WithString("path", mcp.Description("Filter by path+query glob pattern (e.g., '/api/*')")),
 		mcp.WithString("method", mcp.Description("Filter by HTTP method (comma-separated)")),
@@ -218,9 +519,14 @@ Incremental (summary/flows): since accepts flow_id or "last" (cursor). Flows mod
 		mcp.WithString("search_body", mcp.Description("Search request/response body by regex (RE2, use (?i) for case-insensitive); literal if invalid")),
 		mcp.WithString("exclude_host", mcp.Description("Exclude hosts matching glob pattern")),
 		mcp.WithString("exclude_path", mcp.Description("Exclude paths matching glob pattern")),
+		mcp.WithString("content_type", mcp.Description("Filter by response media type glob (e.g., 'image/*')")),
+		mcp.WithBoolean("invert", mcp.Description("Return flows that do not match the filters (summary and flows modes)")),
+		mcp.WithString("duplicate_of", mcp.Description("Only flows of the same endpoint as this crawl flow_id: same host, method, path with numeric/UUID/hex ID segments ignored, and query/body parameter names (values ignored). Gathers all instances of an endpoint for IDOR/access-control comparison")),
 		mcp.WithString("since", mcp.Description("flow_id or 'last' (cursor)")),
-		mcp.WithNumber("limit", mcp.Description("Maximum number of results (default: 100 for flows/forms/errors)")),
+		mcp.WithString("cursor", mcp.Description("Named cursor for since='last' (implied when since is omitted); tracked separately from the default cursor")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of results (default: 100 for flows/forms/errors/external/routes/similar)")),
 		mcp.WithNumber("offset", mcp.Description("Skip first N results for pagination (flows mode)")),
+		mcp.WithString("wait", mcp.Description("Flows mode: long-poll duration when no matching flows are available yet (e.g. '30s', max 120s, default '0s')")),
 	)
 }
 
@@ -237,6 +543,18 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 	outputMode := req.GetString("output_mode", "summary")
 	limit := req.GetInt("limit", 100)
 
+	var fingerprint string
+	if duplicateOf := req.GetString("duplicate_of", ""); duplicateOf != "" {
+		flow, err := m.service.crawlerBackend.GetFlow(ctx, duplicateOf)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return errorResult("duplicate_of flow " + duplicateOf + " not found (use a crawl flow_id)"), nil
+			}
+			return errorResultFromErr("failed to get duplicate_of flow: ", err), nil
+		}
+		fingerprint = endpointFingerprint(flow)
+	}
+
 	log.Printf("mcp/crawl_poll: mode=%s session=%s (limit=%d)", outputMode, sessionID, limit)
 
 	switch outputMode {
@@ -263,6 +581,7 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 		var apiErrors []protocol.CrawlError
 		for _, e := range errs {
 			apiErrors = append(apiErrors, protocol.CrawlError{
+				FlowID: e.FlowID,
 				URL:    e.URL,
 				Status: e.Status,
 				Error:  e.Error,
@@ -270,11 +589,123 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 		}
 		return jsonResult(protocol.CrawlPollResponse{SessionID: sessionID, Errors: apiErrors})
 
+	case OutputModeExternal:
+		links, err := m.service.crawlerBackend.ListExternalLinks(ctx, sessionID, limit)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return errorResult("session not found"), nil
+			}
+			return errorResultFromErr("failed to list external links: ", err), nil
+		}
+
+		var apiLinks []protocol.ExternalLink
+		for _, l := range links {
+			apiLinks = append(apiLinks, protocol.ExternalLink{
+				URL:     l.URL,
+				Host:    l.Host,
+				FoundOn: l.FoundOn,
+			})
+		}
+		return jsonResult(protocol.CrawlPollResponse{SessionID: sessionID, External: apiLinks})
+
+	case OutputModeRoutes:
+		routes, err := m.service.crawlerBackend.ListClientRoutes(ctx, sessionID, limit)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return errorResult("session not found"), nil
+			}
+			return errorResultFromErr("failed to list client routes: ", err), nil
+		}
+
+		var apiRoutes []protocol.ClientRoute
+		for _, r := range routes {
+			apiRoutes = append(apiRoutes, protocol.ClientRoute{
+				Route:   r.Route,
+				Kind:    r.Kind,
+				FoundOn: r.FoundOn,
+			})
+		}
+		return jsonResult(protocol.CrawlPollResponse{SessionID: sessionID, Routes: apiRoutes})
+
+	case OutputModeEndpoints:
+		endpoints, err := m.service.crawlerBackend.ListJSEndpoints(ctx, sessionID, limit)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return errorResult("session not found"), nil
+			}
+			return errorResultFromErr("failed to list JavaScript endpoints: ", err), nil
+		}
+
+		var apiEndpoints []protocol.JSEndpoint
+		for _, e := range endpoints {
+			apiEndpoints = append(apiEndpoints, protocol.JSEndpoint{
+				URL:     e.URL,
+				Method:  e.Method,
+				Kind:    e.Kind,
+				FoundOn: e.FoundOn,
+			})
+		}
+		return jsonResult(protocol.CrawlPollResponse{SessionID: sessionID, Endpoints: apiEndpoints})
+
+	case OutputModeSimilar:
+		clusters, err := m.service.crawlerBackend.ListSimilarClusters(ctx, sessionID, limit)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return errorResult("session not found"), nil
+			}
+			return errorResultFromErr("failed to list similar clusters: ", err), nil
+		}
+
+		var apiClusters []protocol.SimilarCluster
+		for _, c := range clusters {
+			apiClusters = append(apiClusters, protocol.SimilarCluster{
+				RepresentativeID: c.RepresentativeID,
+				Host:             c.Host,
+				Path:             c.Path,
+				Status:           c.StatusCode,
+				ContentType:      c.ContentType,
+				Size:             c.Size,
+				FlowIDs:          c.FlowIDs,
+			})
+		}
+		return jsonResult(protocol.CrawlPollResponse{SessionID: sessionID, Similar: apiClusters})
+
+	case OutputModeFindings:
+		findings, err := m.service.crawlerBackend.ListFindings(ctx, sessionID, limit)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return errorResult("session not found"), nil
+			}
+			return errorResultFromErr("failed to list findings: ", err), nil
+		}
+
+		var apiFindings []protocol.CrawlFinding
+		for _, f := range findings {
+			apiFindings = append(apiFindings, protocol.CrawlFinding{
+				FlowID:  f.FlowID,
+				URL:     f.URL,
+				Finding: f.Finding,
+				Pattern: f.Pattern,
+				Offset:  f.Offset,
+				Snippet: f.Snippet,
+			})
+		}
+		return jsonResult(protocol.CrawlPollResponse{SessionID: sessionID, Findings: apiFindings})
+
 	case OutputModeFlows:
 		searchHeader := req.GetString("search_header", "")
 		searchBody := req.GetString("search_body", "")
 		offset := req.GetInt("offset", 0)
 
+		var wait time.Duration
+		if waitStr := req.GetString("wait", ""); waitStr != "" {
+			parsed, err := time.ParseDuration(waitStr)
+			if err != nil {
+				return errorResult("invalid wait duration: " + err.Error()), nil
+			}
+			wait = min(parsed, 120*time.Second)
+		}
+
 		var notes []string
 		opts := CrawlListOptions{
 			Host:        req.GetString("host", ""),
@@ -283,9 +714,14 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 			Methods:     parseCommaSeparated(req.GetString("method", "")),
 			ExcludeHost: req.GetString("exclude_host", ""),
 			ExcludePath: req.GetString("exclude_path", ""),
+			ContentType: req.GetString("content_type", ""),
+			Invert:      req.GetBool("invert", false),
+			Fingerprint: fingerprint,
 			Since:       req.GetString("since", ""),
+			Cursor:      req.GetString("cursor", ""),
 			Limit:       limit,
 			Offset:      offset,
+			Wait:        wait,
 		}
 
 		// Pass compiled search regexes to backend for integrated filtering
@@ -323,6 +759,11 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 				ResponseLength: f.ResponseLength,
 				Duration:       f.Duration.Round(time.Millisecond).String(),
 				FoundOn:        f.FoundOn,
+				VariantOf:      f.VariantOf,
+				VariedHeader:   f.VariedHeader,
+				RedirectTo:     f.RedirectTo,
+				RedirectedFrom: f.RedirectedFrom,
+				Findings:       f.Findings,
 			})
 		}
 		noteStr := strings.Join(notes, "; ")
@@ -350,7 +791,11 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 			Methods:     parseCommaSeparated(req.GetString("method", "")),
 			ExcludeHost: req.GetString("exclude_host", ""),
 			ExcludePath: req.GetString("exclude_path", ""),
+			ContentType: req.GetString("content_type", ""),
+			Invert:      req.GetBool("invert", false),
+			Fingerprint: fingerprint,
 			Since:       req.GetString("since", ""),
+			Cursor:      req.GetString("cursor", ""),
 			Limit:       0, // no limit for summary
 		}
 
@@ -381,11 +826,14 @@ func (m *mcpServer) handleCrawlPoll(ctx context.Context, req mcp.CallToolRequest
 
 		noteStr := strings.Join(notes, "; ")
 		return jsonResult(protocol.CrawlPollResponse{
-			SessionID:  sessionID,
-			State:      status.State,
-			Duration:   status.Duration.Round(time.Millisecond).String(),
-			Aggregates: aggregates,
-			Note:       noteStr,
+			SessionID:      sessionID,
+			State:          status.State,
+			Duration:       status.Duration.Round(time.Millisecond).String(),
+			Hosts:          status.Hosts,
+			SkippedHosts:   status.SkippedHosts,
+			HostQuotaSkips: status.HostQuotaSkips,
+			Aggregates:     aggregates,
+			Note:           noteStr,
 		})
 	}
 }
@@ -457,6 +905,192 @@ func (m *mcpServer) handleCrawlStop(ctx context.Context, req mcp.CallToolRequest
 	return jsonResult(CrawlStopResponse{Stopped: true})
 }
 
+func (m *mcpServer) crawlResumeTool() mcp.Tool {
+	return mcp.NewTool("crawl_resume",
+		mcp.WithDescription(`Resume a stopped or completed crawl session with its original options.
+
+Discovered URLs that were never requested, including those abandoned by crawl_stop, are queued again at the depth they were found, with the page they were found on as found_on and Referer, so max_depth still applies. Captured flows, forms and request counts are kept, so max_requests still counts earlier requests. Sessions restored from persist_flows after a restart resume the same way.`),
+		mcp.WithString("session_id", mcp.Required(), mcp.Description("Session ID or label")),
+	)
+}
+
+func (m *mcpServer) handleCrawlResume(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	} else if err := m.service.killSwitchErr(); err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	sessionID := req.GetString("session_id", "")
+	if sessionID == "" {
+		return errorResult("session_id is required"), nil
+	}
+
+	log.Printf("mcp/crawl_resume: resuming session %s", sessionID)
+
+	requeued, err := m.service.crawlerBackend.ResumeSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return errorResult("session not found"), nil
+		}
+		return errorResultFromErr("failed to resume session: ", err), nil
+	}
+
+	status, err := m.service.crawlerBackend.GetStatus(ctx, sessionID)
+	if err != nil {
+		return errorResultFromErr("failed to get status: ", err), nil
+	}
+
+	return jsonResult(protocol.CrawlResumeResponse{
+		State:        status.State,
+		URLsRequeued: requeued,
+	})
+}
+
+func (m *mcpServer) crawlDeleteTool() mcp.Tool {
+	return mcp.NewTool("crawl_delete",
+		mcp.WithDescription(`Delete crawled flows to drop noise (tracking pixels, static assets) before export or reporting.
+
+Pass flow_id to delete one flow, or session_id with filters to delete every matching flow in that session (same filters as crawl_poll, e.g. content_type='image/*').
+At least one of flow_id or a filter is required. Deletion is permanent; deleted URLs are not crawled again.
+delete_session=true instead removes the whole session_id session with its flows, forms and errors to free memory; a running session is refused unless force=true, which stops it first.`),
+		mcp.WithString("session_id", mcp.Description("Session ID or label (required with filters)")),
+		mcp.WithString("flow_id", mcp.Description("Delete only this flow")),
+		mcp.WithString("host", mcp.Description("Delete flows with host matching glob pattern")),
+		mcp.WithString("path", mcp.Description("Delete flows with path+query matching glob pattern")),
+		mcp.WithString("method", mcp.Description("Delete flows with HTTP method (comma-separated)")),
+		mcp.WithString("status", mcp.Description("Delete flows with status codes or ranges (e.g., '404' or '3XX')")),
+		mcp.WithString("content_type", mcp.Description("Delete flows with response media type matching glob (e.g., 'image/*')")),
+		mcp.WithString("search_header", mcp.Description("Delete flows with headers matching regex (RE2); literal if invalid")),
+		mcp.WithString("search_body", mcp.Description("Delete flows with body matching regex (RE2); literal if invalid")),
+		mcp.WithString("exclude_host", mcp.Description("Keep hosts matching glob pattern")),
+		mcp.WithString("exclude_path", mcp.Description("Keep paths matching glob pattern")),
+		mcp.WithString("duplicate_of", mcp.Description("Delete flows of the same endpoint as this crawl flow_id (including itself)")),
+		mcp.WithBoolean("invert", mcp.Description("Delete flows that do not match the filters")),
+		mcp.WithBoolean("delete_session", mcp.Description("Delete the whole session instead of matching flows (no filters)")),
+		mcp.WithBoolean("force", mcp.Description("With delete_session, stop a running session before deleting it")),
+	)
+}
+
+func (m *mcpServer) handleCrawlDelete(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	if flowID := req.GetString("flow_id", ""); flowID != "" {
+		log.Printf("mcp/crawl_delete: deleting flow %s", flowID)
+		if err := m.service.crawlerBackend.DeleteFlow(ctx, flowID); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return errorResult("flow " + flowID + " not found (use a crawl flow_id)"), nil
+			}
+			return errorResultFromErr("failed to delete flow: ", err), nil
+		}
+		return jsonResult(protocol.CrawlDeleteResponse{Deleted: 1})
+	}
+
+	sessionID := req.GetString("session_id", "")
+	if req.GetBool("delete_session", false) {
+		if sessionID == "" {
+			return errorResult("session_id is required with delete_session"), nil
+		}
+		log.Printf("mcp/crawl_delete: deleting session %s", sessionID)
+		deleted, err := m.service.crawlerBackend.DeleteSession(ctx, sessionID, req.GetBool("force", false))
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return errorResult("session not found"), nil
+			}
+			return errorResultFromErr("failed to delete session: ", err), nil
+		}
+		return jsonResult(protocol.CrawlDeleteResponse{Deleted: deleted, SessionDeleted: true})
+	} else if sessionID == "" {
+		return errorResult("session_id is required when deleting by filter"), nil
+	}
+
+	opts := CrawlListOptions{
+		Host:        req.GetString("host", ""),
+		PathPattern: req.GetString("path", ""),
+		StatusCodes: parseStatusFilter(req.GetString("status", "")),
+		Methods:     parseCommaSeparated(req.GetString("method", "")),
+		ExcludeHost: req.GetString("exclude_host", ""),
+		ExcludePath: req.GetString("exclude_path", ""),
+		ContentType: req.GetString("content_type", ""),
+		Invert:      req.GetBool("invert", false),
+	}
+	if duplicateOf := req.GetString("duplicate_of", ""); duplicateOf != "" {
+		flow, err := m.service.crawlerBackend.GetFlow(ctx, duplicateOf)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return errorResult("duplicate_of flow " + duplicateOf + " not found (use a crawl flow_id)"), nil
+			}
+			return errorResultFromErr("failed to get duplicate_of flow: ", err), nil
+		}
+		opts.Fingerprint = endpointFingerprint(flow)
+	}
+	if searchHeader := req.GetString("search_header", ""); searchHeader != "" {
+		opts.SearchHeaderRe, _ = compileSearchPattern(searchHeader, true)
+	}
+	if searchBody := req.GetString("search_body", ""); searchBody != "" {
+		opts.SearchBodyRe, _ = compileSearchPattern(searchBody, false)
+	}
+	if opts.Host == "" && opts.PathPattern == "" && opts.StatusCodes.Empty() && len(opts.Methods) == 0 &&
+		opts.ExcludeHost == "" && opts.ExcludePath == "" && opts.ContentType == "" && opts.Fingerprint == "" &&
+		opts.SearchHeaderRe == nil && opts.SearchBodyRe == nil {
+		return errorResult("flow_id or at least one filter is required"), nil
+	}
+
+	log.Printf("mcp/crawl_delete: deleting matching flows from session %s", sessionID)
+
+	deleted, err := m.service.crawlerBackend.DeleteFlows(ctx, sessionID, opts)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return errorResult("session not found"), nil
+		}
+		return errorResultFromErr("failed to delete flows: ", err), nil
+	}
+	return jsonResult(protocol.CrawlDeleteResponse{Deleted: deleted})
+}
+
+func (m *mcpServer) crawlExportSessionTool() mcp.Tool {
+	return mcp.NewTool("crawl_export_session",
+		mcp.WithDescription(`Export the metadata of every flow in a crawl session for bulk triage.
+
+format=ndjson (default) returns one JSON object per line with the flow fields from crawl_poll and crawl_get, without raw request or response bytes.
+format=csv returns a header row and one row per flow: flow_id, method, host, path, status, length, content_type, discovered_at.
+Use crawl_get for a flow's full request and response.`),
+		mcp.WithString("session_id", mcp.Required(), mcp.Description("Session ID or label")),
+		mcp.WithString("format", mcp.Description("Export format: ndjson (default) or csv")),
+	)
+}
+
+func (m *mcpServer) handleCrawlExportSession(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	sessionID := req.GetString("session_id", "")
+	if sessionID == "" {
+		return errorResult("session_id is required"), nil
+	}
+	format := req.GetString("format", CrawlExportNDJSON)
+	if format != CrawlExportNDJSON && format != CrawlExportCSV {
+		return errorResult("format must be ndjson or csv"), nil
+	}
+
+	log.Printf("mcp/crawl_export_session: exporting session %s as %s", sessionID, format)
+
+	var sb strings.Builder
+	count, err := m.service.crawlerBackend.ExportSession(ctx, sessionID, format, &sb)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return errorResult("session not found"), nil
+		}
+		return errorResultFromErr("failed to export session: ", err), nil
+	}
+	log.Printf("mcp/crawl_export_session: exported %d flows from session %s", count, sessionID)
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
 func (m *mcpServer) crawlGetTool() mcp.Tool {
 	return mcp.NewTool("crawl_get",
 		mcp.WithDescription(`Get full details of a crawl flow.
@@ -464,10 +1098,12 @@ func (m *mcpServer) crawlGetTool() mcp.Tool {
 Returns the complete request and response for a flow captured during crawling.
 
 Scope: Sections to return (comma-separated): request_headers, request_body, response_headers, response_body, all (default).
-Pattern: Regex search within scoped sections; returns match context instead of full content. Sections without matches are omitted.`),
+Pattern: Regex search within scoped sections; returns match context instead of full content. Sections without matches are omitted.
+Format: "structured" returns a decoded view instead: parsed request line, header maps, decompressed bodies (JSON bodies parsed), and query/form params. Scope and pattern are ignored.`),
 		mcp.WithString("flow_id", mcp.Required(), mcp.Description("The flow_id from crawl_poll (output_mode=flows)")),
 		mcp.WithString("scope", mcp.Description("Sections to return (comma-separated): request_headers, request_body, response_headers, response_body, all (default)")),
 		mcp.WithString("pattern", mcp.Description("Regex (RE2) search within scoped sections; returns match context instead of full content")),
+		mcp.WithString("format", mcp.Description("Output format: raw (default) or structured")),
 	)
 }
 
@@ -485,6 +1121,10 @@ func (m *mcpServer) handleCrawlGet(ctx context.Context, req mcp.CallToolRequest)
 	fullBody := req.GetBool("full_body", false)
 	scopeStr := req.GetString("scope", "")
 	patternStr := req.GetString("pattern", "")
+	format := req.GetString("format", "raw")
+	if format != "raw" && format != "structured" {
+		return errorResult("invalid format: must be 'raw' or 'structured'"), nil
+	}
 
 	// pattern takes precedence over full_body
 	if patternStr != "" {
@@ -513,6 +1153,8 @@ func (m *mcpServer) handleCrawlGet(ctx context.Context, req mcp.CallToolRequest)
 
 	if flow == nil {
 		return errorResult("flow not found: run crawl_poll to see available flows"), nil
+	} else if format == "structured" {
+		return jsonResult(flow.Structured())
 	}
 
 	reqHeaders, reqBody := splitHeadersBody(flow.Request)
@@ -544,12 +1186,52 @@ func (m *mcpServer) handleCrawlGet(ctx context.Context, req mcp.CallToolRequest)
 	if flow.FoundOn != "" {
 		result["found_on"] = flow.FoundOn
 	}
+	if flow.VariantOf != "" {
+		result["variant_of"] = flow.VariantOf
+		result["varied_header"] = flow.VariedHeader
+	}
+	if flow.ReferrerPolicy != "" {
+		result["referrer_policy"] = flow.ReferrerPolicy
+	}
+	if flow.OriginalURL != "" {
+		result["original_url"] = flow.OriginalURL
+	}
+	if flow.JSONPCallback != "" {
+		result["jsonp_callback"] = flow.JSONPCallback
+	}
+	if len(flow.HTMLComments) > 0 {
+		result["html_comments"] = flow.HTMLComments
+	}
+	if flow.CookieAfter != "" {
+		result["cookie_before"] = flow.CookieBefore
+		result["cookie_after"] = flow.CookieAfter
+	}
+	if flow.RedirectTo != "" {
+		result["redirect_to"] = flow.RedirectTo
+	}
+	if flow.RedirectedFrom != "" {
+		result["redirected_from"] = flow.RedirectedFrom
+		result["redirect_chain"] = flow.RedirectChain
+	}
+	if len(flow.SensitiveData) > 0 {
+		sensitive := make([]protocol.SensitiveMatch, 0, len(flow.SensitiveData))
+		for _, m := range flow.SensitiveData {
+			sensitive = append(sensitive, protocol.SensitiveMatch{Pattern: m.Pattern, Offset: m.Offset, Snippet: m.Snippet})
+		}
+		result["sensitive_data"] = sensitive
+	}
 	if flow.Depth > 0 {
 		result["depth"] = flow.Depth
 	}
 	if flow.Truncated {
 		result["truncated"] = true
 	}
+	if flow.HeadersTruncated {
+		result["headers_truncated"] = true
+	}
+	if len(flow.Findings) > 0 {
+		result["findings"] = flow.Findings
+	}
 
 	if patternRe != nil {
 		// Pattern mode: grep-like context output
@@ -605,3 +1287,74 @@ func (m *mcpServer) handleCrawlGet(ctx context.Context, req mcp.CallToolRequest)
 
 	return jsonResult(result)
 }
+
+func (m *mcpServer) extractLinksTool() mcp.Tool {
+	return mcp.NewTool("extract_links",
+		mcp.WithDescription(`Extract links and endpoints from a single flow's response without crawling.
+
+Runs the crawler's URL extractors over the stored response, chosen by its Content-Type:
+- HTML: anchor (<a>/<area> href), form (action), script (<script src> and routes declared in inline scripts), css (stylesheet links, url() and @import in <style> and style attributes), comment (URLs and paths in HTML comments)
+- JavaScript: script (client-side routes from router tables, hash-bang links and History API calls)
+- JSON: json (string values that are absolute URLs or root-relative paths)
+- CSS: css (url() and @import references)
+
+Relative references are resolved against the flow's request URL. Results are deduplicated per source, in document order.`),
+		mcp.WithString("flow_id", mcp.Required(), mcp.Description("Flow ID (from proxy_poll, replay_send, or crawl_poll)")),
+		mcp.WithString("source", mcp.Description("Only return links from these sources (comma-separated): anchor, form, script, json, css, comment")),
+	)
+}
+
+func (m *mcpServer) handleExtractLinks(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	flowID := req.GetString("flow_id", "")
+	if flowID == "" {
+		return errorResult("flow_id is required"), nil
+	}
+	var sources map[string]bool
+	if raw := req.GetString("source", ""); raw != "" {
+		sources = make(map[string]bool)
+		for _, s := range strings.Split(raw, ",") {
+			switch s = strings.ToLower(strings.TrimSpace(s)); s {
+			case linkSourceAnchor, linkSourceForm, linkSourceScript, linkSourceJSON, linkSourceCSS, linkSourceComment:
+				sources[s] = true
+			default:
+				return errorResult(fmt.Sprintf("invalid source %q: use anchor, form, script, json, css or comment", s)), nil
+			}
+		}
+	}
+
+	flow, errResult := m.resolveFlow(ctx, flowID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	log.Printf("mcp/extract_links: extracting from %s", flowID)
+
+	baseURL := flow.URL
+	if baseURL == "" {
+		_, host, path := extractRequestMeta(string(flow.RawRequest))
+		if host != "" {
+			scheme, _, _ := inferSchemeAndPort(host)
+			baseURL = scheme + "://" + host + path
+		}
+	}
+	respHeaders, respBody := splitHeadersBody(flow.RawResponse)
+	respBody, _ = decompressForDisplay(respBody, string(respHeaders))
+	contentType := extractHeader(string(respHeaders), "Content-Type")
+
+	resp := protocol.ExtractLinksResponse{
+		FlowID:      flowID,
+		BaseURL:     baseURL,
+		ContentType: contentType,
+		Links:       []protocol.ExtractedLink{},
+	}
+	for _, l := range extractLinks(baseURL, contentType, respBody) {
+		if sources == nil || sources[l.Source] {
+			resp.Links = append(resp.Links, protocol.ExtractedLink{URL: l.URL, Source: l.Source})
+		}
+	}
+	return jsonResult(resp)
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"encoding/base64"
 	"encoding/json"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -239,6 +241,278 @@ func TestMCP_CrawlSeedWithMock(t *testing.T) {
 	assert.Equal(t, queuedBefore+2, statusAfter.URLsQueued)
 }
 
+func TestMCP_CrawlStats(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+	createResp := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls": "https://example.com",
+	})
+
+	for i, f := range []struct {
+		status int
+		ct     string
+	}{
+		{200, "text/html; charset=utf-8"},
+		{200, "text/html"},
+		{404, "text/html"},
+		{500, "application/json"},
+	} {
+		require.NoError(t, mockCrawler.AddFlow(createResp.SessionID, CrawlFlow{
+			ID:          "stats-flow-" + strconv.Itoa(i),
+			StatusCode:  f.status,
+			ContentType: f.ct,
+		}))
+	}
+
+	t.Run("distributions", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.CrawlStatsResponse](t, mcpClient, "crawl_stats", map[string]interface{}{
+			"session_id": createResp.SessionID,
+		})
+
+		assert.Equal(t, 4, resp.TotalFlows)
+		assert.Equal(t, []protocol.StatusCodeCount{
+			{Status: 200, Count: 2},
+			{Status: 404, Count: 1},
+			{Status: 500, Count: 1},
+		}, resp.StatusCodes)
+		assert.Equal(t, []protocol.ContentTypeCount{
+			{ContentType: "text/html", Count: 3},
+			{ContentType: "application/json", Count: 1},
+		}, resp.ContentTypes)
+	})
+
+	t.Run("unknown_session", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_stats", map[string]interface{}{
+			"session_id": "missing",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "session not found")
+	})
+}
+
+func TestMCP_CrawlPollDuplicateOf(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+	createResp := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls": "https://example.com",
+	})
+	for _, f := range []CrawlFlow{
+		{ID: "user-1", Method: "GET", Host: "example.com", Path: "/api/users/1?fields=name"},
+		{ID: "user-2", Method: "GET", Host: "example.com", Path: "/api/users/2?fields=email"},
+		{ID: "user-3", Method: "GET", Host: "example.com", Path: "/api/users/3"},
+		{ID: "order-1", Method: "GET", Host: "example.com", Path: "/api/orders/1?fields=name"},
+		{ID: "user-post", Method: "POST", Host: "example.com", Path: "/api/users/1?fields=name"},
+	} {
+		require.NoError(t, mockCrawler.AddFlow(createResp.SessionID, f))
+	}
+
+	t.Run("flows", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.CrawlPollResponse](t, mcpClient, "crawl_poll", map[string]interface{}{
+			"session_id":   createResp.SessionID,
+			"output_mode":  "flows",
+			"duplicate_of": "user-1",
+		})
+
+		var ids []string
+		for _, f := range resp.Flows {
+			ids = append(ids, f.FlowID)
+		}
+		assert.ElementsMatch(t, []string{"user-1", "user-2"}, ids)
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.CrawlPollResponse](t, mcpClient, "crawl_poll", map[string]interface{}{
+			"session_id":   createResp.SessionID,
+			"duplicate_of": "user-3",
+		})
+		require.Len(t, resp.Aggregates, 1)
+		assert.Equal(t, 1, resp.Aggregates[0].Count)
+	})
+
+	t.Run("unknown_flow", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_poll", map[string]interface{}{
+			"session_id":   createResp.SessionID,
+			"output_mode":  "flows",
+			"duplicate_of": "missing",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "duplicate_of flow missing not found")
+	})
+}
+
+func TestMCP_CrawlDelete(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+	createResp := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls": "https://example.com",
+	})
+	for _, f := range []CrawlFlow{
+		{ID: "page", Method: "GET", Host: "example.com", Path: "/", ContentType: "text/html"},
+		{ID: "pixel", Method: "GET", Host: "example.com", Path: "/t.gif", ContentType: "image/gif"},
+		{ID: "logo", Method: "GET", Host: "example.com", Path: "/logo.png", ContentType: "image/png"},
+		{ID: "api", Method: "GET", Host: "example.com", Path: "/api", ContentType: "application/json"},
+	} {
+		require.NoError(t, mockCrawler.AddFlow(createResp.SessionID, f))
+	}
+
+	t.Run("by_filter", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.CrawlDeleteResponse](t, mcpClient, "crawl_delete", map[string]interface{}{
+			"session_id":   createResp.SessionID,
+			"content_type": "image/*",
+		})
+		assert.Equal(t, 2, resp.Deleted)
+		assert.NotContains(t, mockCrawler.flows, "pixel")
+		assert.NotContains(t, mockCrawler.flows, "logo")
+		assert.Contains(t, mockCrawler.flows, "page")
+	})
+
+	t.Run("by_flow_id", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.CrawlDeleteResponse](t, mcpClient, "crawl_delete", map[string]interface{}{
+			"flow_id": "api",
+		})
+		assert.Equal(t, 1, resp.Deleted)
+		assert.NotContains(t, mockCrawler.flows, "api")
+	})
+
+	t.Run("unknown_flow", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_delete", map[string]interface{}{"flow_id": "missing"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "not found")
+	})
+
+	t.Run("requires_filter", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_delete", map[string]interface{}{
+			"session_id": createResp.SessionID,
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "at least one filter")
+		assert.Contains(t, mockCrawler.flows, "page")
+	})
+
+	t.Run("requires_session", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_delete", map[string]interface{}{"content_type": "text/*"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "session_id is required")
+	})
+
+	t.Run("delete_session", func(t *testing.T) {
+		_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+		createResp := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls": "https://example.com",
+		})
+		require.NoError(t, mockCrawler.AddFlow(createResp.SessionID, CrawlFlow{ID: "page", Method: "GET", Host: "example.com", Path: "/"}))
+
+		result := CallMCPTool(t, mcpClient, "crawl_delete", map[string]interface{}{
+			"session_id":     createResp.SessionID,
+			"delete_session": true,
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "is running")
+
+		resp := CallMCPToolJSONOK[protocol.CrawlDeleteResponse](t, mcpClient, "crawl_delete", map[string]interface{}{
+			"session_id":     createResp.SessionID,
+			"delete_session": true,
+			"force":          true,
+		})
+		assert.True(t, resp.SessionDeleted)
+		assert.Equal(t, 1, resp.Deleted)
+		assert.Empty(t, mockCrawler.flows)
+		assert.NotContains(t, mockCrawler.sessions, createResp.SessionID)
+
+		result = CallMCPTool(t, mcpClient, "crawl_delete", map[string]interface{}{
+			"session_id":     createResp.SessionID,
+			"delete_session": true,
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "session not found")
+	})
+}
+
+func TestMCP_CrawlPollFindings(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+	createResp := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls": "https://example.com",
+	})
+	require.NoError(t, mockCrawler.AddFlow(createResp.SessionID, CrawlFlow{
+		ID: "js", Method: "GET", URL: "https://example.com/app.js", Host: "example.com", Path: "/app.js",
+		Findings: []string{findingSensitiveData},
+		SensitiveData: []SensitiveMatch{
+			{Pattern: "aws-access-key", Offset: 12, Snippet: `key: "AKIA************MPLE"`},
+			{Pattern: "email", Offset: 80, Snippet: "to ops@e***********com"},
+		},
+	}))
+
+	resp := CallMCPToolJSONOK[protocol.CrawlPollResponse](t, mcpClient, "crawl_poll", map[string]interface{}{
+		"session_id":  createResp.SessionID,
+		"output_mode": "findings",
+	})
+	require.Len(t, resp.Findings, 2)
+	assert.Equal(t, protocol.CrawlFinding{
+		FlowID:  "js",
+		URL:     "https://example.com/app.js",
+		Finding: "sensitive-data",
+		Pattern: "aws-access-key",
+		Offset:  12,
+		Snippet: `key: "AKIA************MPLE"`,
+	}, resp.Findings[0])
+
+	get := CallMCPToolJSONOK[protocol.CrawlGetResponse](t, mcpClient, "crawl_get", map[string]interface{}{"flow_id": "js"})
+	require.Len(t, get.SensitiveData, 2)
+	assert.Equal(t, "email", get.SensitiveData[1].Pattern)
+}
+
+func TestMCP_CrawlExportSession(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+	createResp := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls": "https://example.com",
+	})
+	for _, f := range []CrawlFlow{
+		{ID: "a", Method: "GET", Host: "example.com", Path: "/", StatusCode: 200},
+		{ID: "b", Method: "POST", Host: "example.com", Path: "/login", StatusCode: 302},
+	} {
+		require.NoError(t, mockCrawler.AddFlow(createResp.SessionID, f))
+	}
+
+	t.Run("ndjson", func(t *testing.T) {
+		text := CallMCPToolTextOK(t, mcpClient, "crawl_export_session", map[string]interface{}{
+			"session_id": createResp.SessionID,
+		})
+		lines := strings.Split(strings.TrimSpace(text), "\n")
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[1], `"flow_id":"b"`)
+		assert.Contains(t, lines[1], `"method":"POST"`)
+	})
+
+	t.Run("invalid_format", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_export_session", map[string]interface{}{
+			"session_id": createResp.SessionID,
+			"format":     "xml",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "format must be ndjson or csv")
+	})
+
+	t.Run("unknown_session", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_export_session", map[string]interface{}{
+			"session_id": "missing",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "session not found")
+	})
+}
+
 func TestMCP_CrawlGetDecompressesGzipBody(t *testing.T) {
 	t.Parallel()
 
@@ -286,6 +560,223 @@ func TestMCP_CrawlGetDecompressesGzipBody(t *testing.T) {
 	assert.Equal(t, originalBody, string(decodedBody))
 }
 
+func TestMCP_CrawlCreateHeaders(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+	t.Run("domain_headers", func(t *testing.T) {
+		CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls": "https://example.com",
+			"headers":   map[string]interface{}{"X-Global": "1"},
+			"domain_headers": map[string]interface{}{
+				"api.example.com": map[string]interface{}{"Authorization": "Bearer abc"},
+				"example.com":     []interface{}{"Cookie: sid=xyz"},
+			},
+		})
+
+		opts := mockCrawler.lastCreateOpts
+		assert.Equal(t, map[string]string{"X-Global": "1"}, opts.Headers)
+		assert.Equal(t, map[string]map[string]string{
+			"api.example.com": {"Authorization": "Bearer abc"},
+			"example.com":     {"Cookie": "sid=xyz"},
+		}, opts.DomainHeaders)
+	})
+
+	t.Run("invalid_domain_headers", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls":      "https://example.com",
+			"domain_headers": []interface{}{"Authorization: x"},
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "domain_headers")
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls": "https://example.com",
+			"resolve":   []interface{}{"Example.com=127.0.0.1"},
+		})
+
+		assert.Equal(t, map[string]string{"example.com": "127.0.0.1"}, mockCrawler.lastCreateOpts.HostResolution)
+	})
+
+	t.Run("invalid_resolve", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls": "https://example.com",
+			"resolve":   map[string]interface{}{"example.com": "nope"},
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid resolve IP")
+	})
+
+	t.Run("token_refresh", func(t *testing.T) {
+		CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls": "https://example.com",
+			"token_refresh": map[string]interface{}{
+				"url":        "https://example.com/oauth/token",
+				"body":       "grant_type=refresh_token&refresh_token=r",
+				"headers":    map[string]interface{}{"X-Client": "crawler"},
+				"token_path": "access_token",
+			},
+		})
+
+		assert.Equal(t, &TokenRefresh{
+			URL:       "https://example.com/oauth/token",
+			Body:      "grant_type=refresh_token&refresh_token=r",
+			Headers:   map[string]string{"X-Client": "crawler"},
+			TokenPath: "access_token",
+		}, mockCrawler.lastCreateOpts.TokenRefresh)
+	})
+
+	t.Run("invalid_token_refresh", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls":     "https://example.com",
+			"token_refresh": "https://example.com/oauth/token",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "token_refresh must be an object")
+	})
+}
+
+func TestMCP_CrawlPollExternal(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+	created := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls": "https://example.com",
+	})
+	mockCrawler.external[created.SessionID] = []ExternalLink{
+		{URL: "https://cdn.other.net/app.js", Host: "cdn.other.net", FoundOn: "https://example.com/"},
+		{URL: "https://analytics.test/t", Host: "analytics.test", FoundOn: "https://example.com/about"},
+	}
+
+	resp := CallMCPToolJSONOK[protocol.CrawlPollResponse](t, mcpClient, "crawl_poll", map[string]interface{}{
+		"session_id":  created.SessionID,
+		"output_mode": "external",
+		"limit":       1,
+	})
+	require.Len(t, resp.External, 1)
+	assert.Equal(t, protocol.ExternalLink{
+		URL:     "https://cdn.other.net/app.js",
+		Host:    "cdn.other.net",
+		FoundOn: "https://example.com/",
+	}, resp.External[0])
+}
+
+func TestMCP_CrawlPollRoutes(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+	created := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls":         "https://example.com",
+		"extract_js_routes": true,
+	})
+	assert.True(t, mockCrawler.lastCreateOpts.ExtractJSRoutes)
+	mockCrawler.routes[created.SessionID] = []ClientRoute{
+		{Route: "/admin/users/:id", Kind: routeKindTable, FoundOn: "https://example.com/main.js"},
+		{Route: "#!/settings", Kind: routeKindHashbang, FoundOn: "https://example.com/"},
+	}
+
+	resp := CallMCPToolJSONOK[protocol.CrawlPollResponse](t, mcpClient, "crawl_poll", map[string]interface{}{
+		"session_id":  created.SessionID,
+		"output_mode": "routes",
+	})
+	assert.Equal(t, []protocol.ClientRoute{
+		{Route: "/admin/users/:id", Kind: "route-table", FoundOn: "https://example.com/main.js"},
+		{Route: "#!/settings", Kind: "hashbang", FoundOn: "https://example.com/"},
+	}, resp.Routes)
+
+	t.Run("unknown_session", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_poll", map[string]interface{}{
+			"session_id":  "missing",
+			"output_mode": "routes",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "session not found")
+	})
+}
+
+func TestMCP_CrawlPollEndpoints(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+	created := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls":            "https://example.com",
+		"extract_js_endpoints": true,
+	})
+	assert.True(t, mockCrawler.lastCreateOpts.ExtractJSEndpoints)
+	mockCrawler.endpoints[created.SessionID] = []JSEndpoint{
+		{URL: "https://example.com/api/orders", Method: "POST", Kind: jsEndpointKindAxios, FoundOn: "https://example.com/main.js"},
+		{URL: "https://example.com/api/session", Kind: jsEndpointKindFetch, FoundOn: "https://example.com/main.js"},
+	}
+
+	resp := CallMCPToolJSONOK[protocol.CrawlPollResponse](t, mcpClient, "crawl_poll", map[string]interface{}{
+		"session_id":  created.SessionID,
+		"output_mode": "endpoints",
+	})
+	assert.Equal(t, []protocol.JSEndpoint{
+		{URL: "https://example.com/api/orders", Method: "POST", Kind: "axios", FoundOn: "https://example.com/main.js"},
+		{URL: "https://example.com/api/session", Kind: "fetch", FoundOn: "https://example.com/main.js"},
+	}, resp.Endpoints)
+
+	t.Run("unknown_session", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_poll", map[string]interface{}{
+			"session_id":  "missing",
+			"output_mode": "endpoints",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "session not found")
+	})
+}
+
+func TestMCP_CrawlPollSimilar(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+	created := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls":            "https://example.com",
+		"similarity_threshold": 0.9,
+	})
+	assert.InDelta(t, 0.9, mockCrawler.lastCreateOpts.SimilarityThreshold, 0.0001)
+	mockCrawler.similar[created.SessionID] = []SimilarCluster{{
+		RepresentativeID: "f1",
+		Host:             "example.com",
+		Path:             "/item/1",
+		StatusCode:       200,
+		ContentType:      "text/html",
+		Size:             3,
+		FlowIDs:          []string{"f1", "f2", "f3"},
+	}}
+
+	resp := CallMCPToolJSONOK[protocol.CrawlPollResponse](t, mcpClient, "crawl_poll", map[string]interface{}{
+		"session_id":  created.SessionID,
+		"output_mode": "similar",
+	})
+	assert.Equal(t, []protocol.SimilarCluster{{
+		RepresentativeID: "f1",
+		Host:             "example.com",
+		Path:             "/item/1",
+		Status:           200,
+		ContentType:      "text/html",
+		Size:             3,
+		FlowIDs:          []string{"f1", "f2", "f3"},
+	}}, resp.Similar)
+
+	t.Run("unknown_session", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_poll", map[string]interface{}{
+			"session_id":  "missing",
+			"output_mode": "similar",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "session not found")
+	})
+}
+
 func TestMCP_CrawlValidation(t *testing.T) {
 	t.Parallel()
 
@@ -327,6 +818,27 @@ func TestMCP_CrawlValidation(t *testing.T) {
 		assert.Contains(t, ExtractMCPText(t, result), "not found")
 	})
 
+	t.Run("status_invalid_wait", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_status", map[string]interface{}{
+			"session_id": "nonexistent",
+			"wait":       "soon",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid wait duration")
+	})
+
+	t.Run("status_wait", func(t *testing.T) {
+		create := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls": "https://example.com",
+		})
+
+		resp := CallMCPToolJSONOK[protocol.CrawlStatusResponse](t, mcpClient, "crawl_status", map[string]interface{}{
+			"session_id": create.SessionID,
+			"wait":       "5s",
+		})
+		assert.Equal(t, "running", resp.State)
+	})
+
 	t.Run("summary_missing_session_id", func(t *testing.T) {
 		result := CallMCPTool(t, mcpClient, "crawl_poll", map[string]interface{}{})
 		assert.True(t, result.IsError)
@@ -438,6 +950,47 @@ func TestMCP_CrawlValidation(t *testing.T) {
 		assert.True(t, result.IsError)
 		assert.Contains(t, ExtractMCPText(t, result), "not running")
 	})
+
+	t.Run("invalid_similarity_threshold", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls":            "https://example.com",
+			"similarity_threshold": 1.5,
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "similarity_threshold")
+	})
+
+	t.Run("resume_missing_session_id", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_resume", map[string]interface{}{})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "session_id is required")
+	})
+
+	t.Run("resume_invalid_session_id", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_resume", map[string]interface{}{
+			"session_id": "nonexistent",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "not found")
+	})
+
+	t.Run("resume_stopped_session", func(t *testing.T) {
+		createResp := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls": "https://example.com",
+		})
+		require.NoError(t, mockCrawler.StopSession(t.Context(), createResp.SessionID))
+
+		resp := CallMCPToolJSONOK[protocol.CrawlResumeResponse](t, mcpClient, "crawl_resume", map[string]interface{}{
+			"session_id": createResp.SessionID,
+		})
+		assert.Equal(t, "running", resp.State)
+
+		result := CallMCPTool(t, mcpClient, "crawl_resume", map[string]interface{}{
+			"session_id": createResp.SessionID,
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "already running")
+	})
 }
 
 func TestMCP_CrawlPollSearch(t *testing.T) {
@@ -560,4 +1113,85 @@ func TestMCP_CrawlGetWithScope(t *testing.T) {
 		require.NoError(t, json.Unmarshal([]byte(text), &raw))
 		assert.NotContains(t, raw, "response_body")
 	})
+
+	t.Run("structured_format", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.StructuredFlow](t, mcpClient, "crawl_get", map[string]interface{}{
+			"flow_id": "scope-flow",
+			"format":  "structured",
+		})
+		assert.Equal(t, "scope-flow", resp.FlowID)
+		assert.Equal(t, "GET", resp.Request.Method)
+		assert.Equal(t, "/scoped", resp.Request.Path)
+		assert.Equal(t, []string{"example.com"}, resp.Request.Headers["Host"])
+		assert.Equal(t, 200, resp.Response.Status)
+		assert.Equal(t, "resp body content", resp.Response.Body)
+	})
+
+	t.Run("invalid_format", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "crawl_get", map[string]interface{}{
+			"flow_id": "scope-flow",
+			"format":  "xml",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid format")
+	})
+}
+
+func TestMCP_ExtractLinks(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+	createResp := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls": "https://example.com",
+	})
+
+	const page = `<html><body><a href="/about">About</a><script src="app.js"></script><!-- old: /v1/admin --></body></html>`
+	err := mockCrawler.AddFlow(createResp.SessionID, CrawlFlow{
+		ID:         "extract-flow",
+		SessionID:  createResp.SessionID,
+		URL:        "http://example.com:8080/docs/index.html",
+		Host:       "example.com",
+		Path:       "/docs/index.html",
+		Method:     "GET",
+		StatusCode: 200,
+		Request:    []byte("GET /docs/index.html HTTP/1.1\r\nHost: example.com:8080\r\n\r\n"),
+		Response:   []byte("HTTP/1.1 200 OK\r\nContent-Type: text/html; charset=utf-8\r\n\r\n" + page),
+	})
+	require.NoError(t, err)
+
+	t.Run("all_sources", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ExtractLinksResponse](t, mcpClient, "extract_links", map[string]interface{}{
+			"flow_id": "extract-flow",
+		})
+		assert.Equal(t, "http://example.com:8080/docs/index.html", resp.BaseURL)
+		assert.Equal(t, []protocol.ExtractedLink{
+			{URL: "http://example.com:8080/about", Source: "anchor"},
+			{URL: "http://example.com:8080/docs/app.js", Source: "script"},
+			{URL: "http://example.com:8080/v1/admin", Source: "comment"},
+		}, resp.Links)
+	})
+
+	t.Run("source_filter", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ExtractLinksResponse](t, mcpClient, "extract_links", map[string]interface{}{
+			"flow_id": "extract-flow",
+			"source":  "comment",
+		})
+		require.Len(t, resp.Links, 1)
+		assert.Equal(t, "comment", resp.Links[0].Source)
+	})
+
+	t.Run("invalid_source", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "extract_links", map[string]interface{}{
+			"flow_id": "extract-flow",
+			"source":  "img",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid source")
+	})
+
+	t.Run("unknown_flow", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "extract_links", map[string]interface{}{"flow_id": "missing"})
+		assert.True(t, result.IsError)
+	})
 }
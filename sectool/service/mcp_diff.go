@@ -36,15 +36,20 @@ Scope controls what is compared:
 - "response_headers" — status, response headers only (no body diff)
 - "request_body" — request body only
 - "response_body" — response body only
+- "request_raw" — unified text diff of the complete raw request bytes (no header normalization or decompression)
+- "response_raw" — unified text diff of the complete raw response bytes (no header normalization or decompression)
 
 Flows can come from any source (proxy, replay, crawl) and can be mixed.
+Use ignore_json_paths to drop volatile fields (timestamps, request IDs) from JSON bodies on both sides before comparing.
 Sections where everything is identical are omitted. Returns {"same": true} when scoped sections are entirely identical.`),
 		mcp.WithString("flow_a", mcp.Required(), mcp.Description("Flow ID (from proxy_poll, replay_send, or crawl_poll)")),
 		mcp.WithString("flow_b", mcp.Required(), mcp.Description("Flow ID (from any source)")),
 		mcp.WithString("scope", mcp.Required(),
-			mcp.Enum("request", "response", "request_headers", "response_headers", "request_body", "response_body"),
+			mcp.Enum("request", "response", "request_headers", "response_headers", "request_body", "response_body", "request_raw", "response_raw"),
 			mcp.Description("What to compare")),
 		mcp.WithNumber("max_diff_lines", mcp.Description("Cap body diff output (default: 50 for text, 20 for JSON paths)")),
+		mcp.WithArray("ignore_json_paths", mcp.Items(map[string]interface{}{"type": "string"}),
+			mcp.Description("JSON body paths to drop from both flows before comparing, same notation as diff paths (e.g. 'data.timestamp', 'items[0].id'). Not applied to raw scopes")),
 	)
 }
 
@@ -66,6 +71,14 @@ func (m *mcpServer) handleDiffFlow(ctx context.Context, req mcp.CallToolRequest)
 	}
 
 	maxDiffLines := req.GetInt("max_diff_lines", 0)
+	var ignorePaths [][]pathSegment
+	for _, p := range req.GetStringSlice("ignore_json_paths", nil) {
+		segments, err := parseJSONPath(p)
+		if err != nil {
+			return errorResult(fmt.Sprintf("invalid ignore_json_paths %q: %v", p, err)), nil
+		}
+		ignorePaths = append(ignorePaths, segments)
+	}
 
 	flowA, errResult := m.resolveFlow(ctx, flowAID)
 	if errResult != nil {
@@ -80,6 +93,22 @@ func (m *mcpServer) handleDiffFlow(ctx context.Context, req mcp.CallToolRequest)
 
 	resp := &protocol.DiffFlowResponse{}
 
+	// Raw scopes bypass parsing to compare the exact stored bytes
+	switch scope {
+	case "request_raw":
+		if !bytes.Equal(flowA.RawRequest, flowB.RawRequest) {
+			resp.Request = &protocol.RequestDiff{Raw: diffTextBodies(flowA.RawRequest, flowB.RawRequest, maxDiffLines)}
+		}
+		resp.Same = resp.Request == nil
+		return jsonResult(resp)
+	case "response_raw":
+		if !bytes.Equal(flowA.RawResponse, flowB.RawResponse) {
+			resp.Response = &protocol.ResponseDiff{Raw: diffTextBodies(flowA.RawResponse, flowB.RawResponse, maxDiffLines)}
+		}
+		resp.Same = resp.Response == nil
+		return jsonResult(resp)
+	}
+
 	includeReqHeaders := scope == "request" || scope == "request_headers"
 	includeReqBody := scope == "request" || scope == "request_body"
 	includeRespHeaders := scope == "response" || scope == "response_headers"
@@ -94,10 +123,12 @@ func (m *mcpServer) handleDiffFlow(ctx context.Context, req mcp.CallToolRequest)
 	if includeReqBody {
 		reqBodyA, _ = decompressForDisplay(reqBodyA, string(reqHeadersA))
 		reqBodyB, _ = decompressForDisplay(reqBodyB, string(reqHeadersB))
+		reqBodyA, reqBodyB = dropJSONPaths(reqBodyA, reqBodyB, ignorePaths)
 	}
 	if includeRespBody {
 		respBodyA, _ = decompressForDisplay(respBodyA, string(respHeadersA))
 		respBodyB, _ = decompressForDisplay(respBodyB, string(respHeadersB))
+		respBodyA, respBodyB = dropJSONPaths(respBodyA, respBodyB, ignorePaths)
 	}
 
 	if includeReqHeaders || includeReqBody {
@@ -123,6 +154,34 @@ func (m *mcpServer) handleDiffFlow(ctx context.Context, req mcp.CallToolRequest)
 	return jsonResult(resp)
 }
 
+// dropJSONPaths removes the given paths from both bodies when both parse as JSON, so
+// ignored fields never show as changes. Bodies are returned unchanged otherwise.
+func dropJSONPaths(bodyA, bodyB []byte, paths [][]pathSegment) ([]byte, []byte) {
+	if len(paths) == 0 {
+		return bodyA, bodyB
+	}
+
+	var dataA, dataB interface{}
+	if json.Unmarshal(bodyA, &dataA) != nil || json.Unmarshal(bodyB, &dataB) != nil {
+		return bodyA, bodyB
+	}
+	for _, segments := range paths {
+		var errA, errB error
+		dataA, errA = removeKeyAtPath(dataA, segments)
+		dataB, errB = removeKeyAtPath(dataB, segments)
+		if errA != nil || errB != nil {
+			return bodyA, bodyB
+		}
+	}
+
+	outA, errA := json.Marshal(dataA)
+	outB, errB := json.Marshal(dataB)
+	if errA != nil || errB != nil {
+		return bodyA, bodyB
+	}
+	return outA, outB
+}
+
 // diffRequest compares request components and returns nil if identical.
 func diffRequest(headersA, headersB, bodyA, bodyB []byte, includeHeaders, includeBody bool, maxLines int) *protocol.RequestDiff {
 	var diff protocol.RequestDiff
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+const defaultMaxMultiDiffFields = 50
+
+func (m *mcpServer) diffMultiTool() mcp.Tool {
+	return mcp.NewTool("diff_multi",
+		mcp.WithDescription(`Compare three or more captured flows side by side, one row per differing field.
+
+Each row names a field and lists its value in every flow, in flow_ids order (null when a flow lacks the field):
+- "method", "path", "status"
+- "query.<name>", "header.<Name>"
+- "body.<json path>" for JSON bodies (values are JSON-encoded), or "body" (size and sha256 prefix) for other bodies
+
+Scope selects the side compared, as in diff_flow: request, response, request_headers, response_headers, request_body, response_body.
+Use ignore_json_paths to drop volatile JSON body fields before comparing. Returns {"same": true} when every field matches across all flows.`),
+		mcp.WithArray("flow_ids", mcp.Required(), mcp.Items(map[string]interface{}{"type": "string"}),
+			mcp.Description("Two or more flow IDs from any source (proxy, replay, crawl)")),
+		mcp.WithString("scope", mcp.Required(),
+			mcp.Enum("request", "response", "request_headers", "response_headers", "request_body", "response_body"),
+			mcp.Description("What to compare")),
+		mcp.WithNumber("max_fields", mcp.Description("Cap differing fields returned (default: 50)")),
+		mcp.WithArray("ignore_json_paths", mcp.Items(map[string]interface{}{"type": "string"}),
+			mcp.Description("JSON body paths to drop from every flow before comparing (e.g. 'data.timestamp', 'items[0].id')")),
+	)
+}
+
+func (m *mcpServer) handleDiffMulti(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	flowIDs := req.GetStringSlice("flow_ids", nil)
+	scope := req.GetString("scope", "")
+	if len(flowIDs) < 2 {
+		return errorResult("flow_ids requires at least two flow IDs"), nil
+	}
+
+	var request, includeHeaders, includeBody bool
+	switch scope {
+	case "request":
+		request, includeHeaders, includeBody = true, true, true
+	case "request_headers":
+		request, includeHeaders = true, true
+	case "request_body":
+		request, includeBody = true, true
+	case "response":
+		includeHeaders, includeBody = true, true
+	case "response_headers":
+		includeHeaders = true
+	case "response_body":
+		includeBody = true
+	case "":
+		return errorResult("scope is required"), nil
+	default:
+		return errorResult("invalid scope: use request, response, request_headers, response_headers, request_body, or response_body"), nil
+	}
+
+	maxFields := req.GetInt("max_fields", 0)
+	if maxFields <= 0 {
+		maxFields = defaultMaxMultiDiffFields
+	}
+	var ignorePaths [][]pathSegment
+	for _, p := range req.GetStringSlice("ignore_json_paths", nil) {
+		segments, err := parseJSONPath(p)
+		if err != nil {
+			return errorResult(fmt.Sprintf("invalid ignore_json_paths %q: %v", p, err)), nil
+		}
+		ignorePaths = append(ignorePaths, segments)
+	}
+
+	fieldSets := make([]map[string]string, len(flowIDs))
+	for i, id := range flowIDs {
+		flow, errResult := m.resolveFlow(ctx, id)
+		if errResult != nil {
+			return errResult, nil
+		}
+		raw := flow.RawResponse
+		if request {
+			raw = flow.RawRequest
+		}
+		fieldSets[i] = flowDiffFields(raw, request, includeHeaders, includeBody, ignorePaths)
+	}
+
+	log.Printf("mcp/diff_multi: comparing %d flows scope=%s", len(flowIDs), scope)
+
+	resp := diffFieldSets(fieldSets, maxFields)
+	resp.FlowIDs = flowIDs
+	return jsonResult(resp)
+}
+
+// flowDiffFields flattens one side of a flow into named fields for N-way comparison.
+func flowDiffFields(raw []byte, request, includeHeaders, includeBody bool, ignorePaths [][]pathSegment) map[string]string {
+	fields := make(map[string]string)
+	headers, body := splitHeadersBody(raw)
+
+	if includeHeaders {
+		if request {
+			method, _, fullPath := extractRequestMeta(string(headers))
+			path, query := splitPathQuery(fullPath)
+			fields["method"] = method
+			fields["path"] = path
+			values, _ := url.ParseQuery(query)
+			for name, v := range values {
+				fields["query."+name] = strings.Join(v, ", ")
+			}
+		} else {
+			status, _ := parseResponseStatus(headers)
+			fields["status"] = strconv.Itoa(status)
+		}
+		for name, v := range parseHeadersToMap(string(headers)) {
+			fields["header."+name] = strings.Join(v, ", ")
+		}
+	}
+
+	if includeBody {
+		body, _ = decompressForDisplay(body, string(headers))
+		if len(body) > 0 && !addJSONBodyFields(fields, body, string(headers), ignorePaths) {
+			sum := sha256.Sum256(body)
+			fields["body"] = fmt.Sprintf("%d bytes sha256:%s", len(body), hex.EncodeToString(sum[:6]))
+		}
+	}
+
+	return fields
+}
+
+// addJSONBodyFields adds a "body.<path>" field per JSON leaf, returning false when the body is not JSON.
+func addJSONBodyFields(fields map[string]string, body []byte, headers string, ignorePaths [][]pathSegment) bool {
+	if !isDiffJSONContentType(extractHeader(headers, "Content-Type")) && !looksLikeJSON(body) {
+		return false
+	}
+	var data interface{}
+	if json.Unmarshal(body, &data) != nil {
+		return false
+	}
+	for _, segments := range ignorePaths {
+		if trimmed, err := removeKeyAtPath(data, segments); err == nil {
+			data = trimmed
+		}
+	}
+
+	for p, v := range flattenJSON("", data) {
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			encoded = []byte(fmt.Sprintf("%v", v))
+		}
+		if p == "" {
+			fields["body"] = string(encoded)
+		} else {
+			fields["body."+p] = string(encoded)
+		}
+	}
+	return true
+}
+
+// diffFieldSets builds rows for fields whose value is not identical across every set.
+func diffFieldSets(sets []map[string]string, maxFields int) *protocol.DiffMultiResponse {
+	names := make(map[string]bool)
+	for _, set := range sets {
+		for name := range set {
+			names[name] = true
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		ri, rj := diffFieldRank(sorted[i]), diffFieldRank(sorted[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	resp := &protocol.DiffMultiResponse{}
+	for _, name := range sorted {
+		values := make([]*string, len(sets))
+		differs := false
+		for i, set := range sets {
+			if v, ok := set[name]; ok {
+				values[i] = &v
+			}
+			if i > 0 && !sameFieldValue(values[0], values[i]) {
+				differs = true
+			}
+		}
+
+		if !differs {
+			resp.UnchangedCount++
+		} else if len(resp.Fields) >= maxFields {
+			resp.Truncated = true
+		} else {
+			resp.Fields = append(resp.Fields, protocol.MultiDiffField{Field: name, Values: values})
+		}
+	}
+	resp.Same = len(resp.Fields) == 0 && !resp.Truncated
+	return resp
+}
+
+// diffFieldRank orders rows by section: request/status line, query, headers, then body.
+func diffFieldRank(name string) int {
+	switch {
+	case name == "method":
+		return 0
+	case name == "path":
+		return 1
+	case name == "status":
+		return 2
+	case strings.HasPrefix(name, "query."):
+		return 3
+	case strings.HasPrefix(name, "header."):
+		return 4
+	default:
+		return 5
+	}
+}
+
+func sameFieldValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
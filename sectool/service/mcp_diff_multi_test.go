@@ -0,0 +1,135 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func TestHandleDiffMulti(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMockMCPServer(t)
+
+	mockMCP.AddProxyEntry(
+		"GET /api/users?id=1 HTTP/1.1\r\nHost: example.com\r\nAuthorization: Bearer admin\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n"+`{"id":1,"role":"admin","ts":100}`,
+		"",
+	)
+	mockMCP.AddProxyEntry(
+		"GET /api/users?id=1 HTTP/1.1\r\nHost: example.com\r\nAuthorization: Bearer user\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n"+`{"id":1,"role":"user","ts":200}`,
+		"",
+	)
+	mockMCP.AddProxyEntry(
+		"GET /api/users?id=1 HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		"HTTP/1.1 401 Unauthorized\r\nContent-Type: text/plain\r\nWWW-Authenticate: Bearer\r\n\r\nlogin required",
+		"",
+	)
+
+	listResp := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"host":        "example.com",
+	})
+	require.Len(t, listResp.Flows, 3)
+	flowIDs := []string{listResp.Flows[0].FlowID, listResp.Flows[1].FlowID, listResp.Flows[2].FlowID}
+
+	fieldsByName := func(resp protocol.DiffMultiResponse) map[string][]*string {
+		m := make(map[string][]*string)
+		for _, f := range resp.Fields {
+			m[f.Field] = f.Values
+		}
+		return m
+	}
+	strPtr := func(s string) *string { return &s }
+
+	t.Run("request_scope", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DiffMultiResponse](t, mcpClient, "diff_multi", map[string]interface{}{
+			"flow_ids": flowIDs,
+			"scope":    "request",
+		})
+
+		assert.False(t, resp.Same)
+		assert.Equal(t, flowIDs, resp.FlowIDs)
+		require.Len(t, resp.Fields, 1)
+		assert.Equal(t, "header.Authorization", resp.Fields[0].Field)
+		assert.Equal(t, []*string{strPtr("Bearer admin"), strPtr("Bearer user"), nil}, resp.Fields[0].Values)
+		assert.Positive(t, resp.UnchangedCount) // method, path, query.id, header.Host
+	})
+
+	t.Run("response_scope", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DiffMultiResponse](t, mcpClient, "diff_multi", map[string]interface{}{
+			"flow_ids": flowIDs,
+			"scope":    "response",
+		})
+
+		fields := fieldsByName(resp)
+		assert.Equal(t, []*string{strPtr("200"), strPtr("200"), strPtr("401")}, fields["status"])
+		assert.Equal(t, []*string{strPtr(`"admin"`), strPtr(`"user"`), nil}, fields["body.role"])
+		assert.Equal(t, []*string{strPtr("1"), strPtr("1"), nil}, fields["body.id"])
+		require.Contains(t, fields, "body")
+		assert.Nil(t, fields["body"][0])
+		assert.Contains(t, *fields["body"][2], "14 bytes sha256:")
+		assert.Equal(t, "status", resp.Fields[0].Field)
+	})
+
+	t.Run("ignore_json_paths", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DiffMultiResponse](t, mcpClient, "diff_multi", map[string]interface{}{
+			"flow_ids":          flowIDs[:2],
+			"scope":             "response_body",
+			"ignore_json_paths": []string{"ts"},
+		})
+
+		fields := fieldsByName(resp)
+		assert.NotContains(t, fields, "body.ts")
+		assert.Contains(t, fields, "body.role")
+	})
+
+	t.Run("same", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DiffMultiResponse](t, mcpClient, "diff_multi", map[string]interface{}{
+			"flow_ids": []string{flowIDs[0], flowIDs[0], flowIDs[0]},
+			"scope":    "response",
+		})
+		assert.True(t, resp.Same)
+		assert.Empty(t, resp.Fields)
+	})
+
+	t.Run("max_fields", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DiffMultiResponse](t, mcpClient, "diff_multi", map[string]interface{}{
+			"flow_ids":   flowIDs,
+			"scope":      "response",
+			"max_fields": 1,
+		})
+		assert.Len(t, resp.Fields, 1)
+		assert.True(t, resp.Truncated)
+		assert.False(t, resp.Same)
+	})
+
+	t.Run("too_few_flows", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "diff_multi", map[string]interface{}{
+			"flow_ids": flowIDs[:1],
+			"scope":    "response",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "at least two")
+	})
+
+	t.Run("raw_scope_rejected", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "diff_multi", map[string]interface{}{
+			"flow_ids": flowIDs,
+			"scope":    "response_raw",
+		})
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("unknown_flow", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "diff_multi", map[string]interface{}{
+			"flow_ids": []string{flowIDs[0], "nonexistent"},
+			"scope":    "response",
+		})
+		assert.True(t, result.IsError)
+	})
+}
@@ -138,6 +138,87 @@ func TestHandleDiffFlow(t *testing.T) {
 		assert.Equal(t, "json", resp.Response.Body.Format)
 	})
 
+	t.Run("ignore_json_paths", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DiffFlowResponse](t, mcpClient, "diff_flow", map[string]interface{}{
+			"flow_a":            flowA,
+			"flow_b":            flowB,
+			"scope":             "response_body",
+			"ignore_json_paths": []string{"user.role", "user.active"},
+		})
+
+		require.NotNil(t, resp.Response)
+		require.NotNil(t, resp.Response.Body)
+		assert.Empty(t, resp.Response.Body.Changed)
+		assert.Empty(t, resp.Response.Body.Removed)
+		require.Len(t, resp.Response.Body.Added, 1)
+		assert.Equal(t, "user.mfa", resp.Response.Body.Added[0].Path)
+	})
+
+	t.Run("ignore_json_paths_same", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DiffFlowResponse](t, mcpClient, "diff_flow", map[string]interface{}{
+			"flow_a":            flowA,
+			"flow_b":            flowB,
+			"scope":             "response_body",
+			"ignore_json_paths": []string{"user.role", "user.active", "user.mfa"},
+		})
+
+		assert.True(t, resp.Same)
+	})
+
+	t.Run("ignore_json_paths_invalid", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "diff_flow", map[string]interface{}{
+			"flow_a":            flowA,
+			"flow_b":            flowB,
+			"scope":             "response_body",
+			"ignore_json_paths": []string{""},
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid ignore_json_paths")
+	})
+
+	t.Run("request_raw_scope", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DiffFlowResponse](t, mcpClient, "diff_flow", map[string]interface{}{
+			"flow_a": flowA,
+			"flow_b": flowB,
+			"scope":  "request_raw",
+		})
+
+		assert.False(t, resp.Same)
+		require.NotNil(t, resp.Request)
+		assert.Nil(t, resp.Response)
+		assert.Nil(t, resp.Request.Method) // no structured fields for raw scope
+		require.NotNil(t, resp.Request.Raw)
+		assert.Equal(t, "text", resp.Request.Raw.Format)
+		assert.Contains(t, resp.Request.Raw.Diff, "-GET /api/v1/users?page=1 HTTP/1.1")
+		assert.Contains(t, resp.Request.Raw.Diff, "+POST /api/v2/users?page=2&debug=true HTTP/1.1")
+		assert.Contains(t, resp.Request.Raw.Diff, "+X-Custom: test")
+	})
+
+	t.Run("response_raw_scope", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DiffFlowResponse](t, mcpClient, "diff_flow", map[string]interface{}{
+			"flow_a": flowA,
+			"flow_b": flowB,
+			"scope":  "response_raw",
+		})
+
+		assert.False(t, resp.Same)
+		assert.Nil(t, resp.Request)
+		require.NotNil(t, resp.Response)
+		require.NotNil(t, resp.Response.Raw)
+		assert.Nil(t, resp.Response.Status)
+		assert.Contains(t, resp.Response.Raw.Diff, "+HTTP/1.1 403 Forbidden")
+	})
+
+	t.Run("raw_scope_same_flow", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.DiffFlowResponse](t, mcpClient, "diff_flow", map[string]interface{}{
+			"flow_a": flowA,
+			"flow_b": flowA,
+			"scope":  "request_raw",
+		})
+
+		assert.True(t, resp.Same)
+	})
+
 	t.Run("missing_flow_a", func(t *testing.T) {
 		result := CallMCPTool(t, mcpClient, "diff_flow", map[string]interface{}{
 			"flow_b": flowB,
@@ -176,6 +257,48 @@ func TestHandleDiffFlow(t *testing.T) {
 	})
 }
 
+func TestHandleDiffFlow_RawHeaderCasing(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMockMCPServer(t)
+
+	mockMCP.AddProxyEntry(
+		"GET / HTTP/1.1\r\nHost: casing.example.com\r\nTransfer-Encoding: chunked\r\n\r\n",
+		"HTTP/1.1 200 OK\r\n\r\nok",
+		"",
+	)
+	mockMCP.AddProxyEntry(
+		"GET / HTTP/1.1\r\nHost: casing.example.com\r\ntransfer-encoding: chunked\r\n\r\n",
+		"HTTP/1.1 200 OK\r\n\r\nok",
+		"",
+	)
+
+	listResp := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"host":        "casing.example.com",
+	})
+	require.Len(t, listResp.Flows, 2)
+
+	args := map[string]interface{}{
+		"flow_a": listResp.Flows[0].FlowID,
+		"flow_b": listResp.Flows[1].FlowID,
+	}
+
+	// Structured diff normalizes header names
+	args["scope"] = "request_headers"
+	resp := CallMCPToolJSONOK[protocol.DiffFlowResponse](t, mcpClient, "diff_flow", args)
+	assert.True(t, resp.Same)
+
+	// Raw diff surfaces the casing difference
+	args["scope"] = "request_raw"
+	resp = CallMCPToolJSONOK[protocol.DiffFlowResponse](t, mcpClient, "diff_flow", args)
+	assert.False(t, resp.Same)
+	require.NotNil(t, resp.Request)
+	require.NotNil(t, resp.Request.Raw)
+	assert.Contains(t, resp.Request.Raw.Diff, "-Transfer-Encoding: chunked")
+	assert.Contains(t, resp.Request.Raw.Diff, "+transfer-encoding: chunked")
+}
+
 func TestHandleDiffFlow_IdenticalFlows(t *testing.T) {
 	t.Parallel()
 
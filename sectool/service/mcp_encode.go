@@ -10,17 +10,19 @@ import (
 
 func (m *mcpServer) encodeTool() mcp.Tool {
 	return mcp.NewTool("encode",
-		mcp.WithDescription("Encode a string. Supported types: url (percent-encoding), base64, html (entity encoding)."),
+		mcp.WithDescription("Encode a string. Supported types: url (percent-encoding), base64, html (entity encoding), hex (lowercase digits), octal (\\101 escapes)."),
 		mcp.WithString("input", mcp.Required(), mcp.Description("String to encode")),
-		mcp.WithString("type", mcp.Required(), mcp.Enum("url", "base64", "html"), mcp.Description("Encoding type")),
+		mcp.WithString("type", mcp.Required(), mcp.Enum("url", "base64", "html", "hex", "octal"), mcp.Description("Encoding type")),
+		mcp.WithString("variant", mcp.Enum("std", "url", "rawstd", "rawurl"), mcp.Description("base64 only: alphabet and padding (default: std)")),
 	)
 }
 
 func (m *mcpServer) decodeTool() mcp.Tool {
 	return mcp.NewTool("decode",
-		mcp.WithDescription("Decode a string. Supported types: url (percent-encoding), base64, html (entity decoding)."),
+		mcp.WithDescription("Decode a string. Supported types: url (percent-encoding), base64, html (entity decoding), hex (4142, \\x41\\x42 or 0x41 0x42), octal (\\101\\102 or 101 102)."),
 		mcp.WithString("input", mcp.Required(), mcp.Description("String to decode")),
-		mcp.WithString("type", mcp.Required(), mcp.Enum("url", "base64", "html"), mcp.Description("Encoding type")),
+		mcp.WithString("type", mcp.Required(), mcp.Enum("url", "base64", "html", "hex", "octal"), mcp.Description("Encoding type")),
+		mcp.WithString("variant", mcp.Enum("std", "url", "rawstd", "rawurl"), mcp.Description("base64 only: alphabet and padding (default: try std, then url and unpadded forms)")),
 	)
 }
 
@@ -30,7 +32,13 @@ func (m *mcpServer) handleEncode(ctx context.Context, req mcp.CallToolRequest) (
 		return errorResult("input is required"), nil
 	}
 
-	result, err := encoding.Encode(input, req.GetString("type", ""))
+	var result string
+	var err error
+	if typ := req.GetString("type", ""); typ == "base64" {
+		result, err = encoding.EncodeBase64(input, req.GetString("variant", ""))
+	} else {
+		result, err = encoding.Encode(input, typ)
+	}
 	if err != nil {
 		return errorResult(err.Error()), nil
 	}
@@ -44,7 +52,13 @@ func (m *mcpServer) handleDecode(ctx context.Context, req mcp.CallToolRequest) (
 		return errorResult("input is required"), nil
 	}
 
-	result, err := encoding.Decode(input, req.GetString("type", ""))
+	var result string
+	var err error
+	if typ := req.GetString("type", ""); typ == "base64" {
+		result, err = encoding.DecodeBase64(input, req.GetString("variant", ""))
+	} else {
+		result, err = encoding.Decode(input, typ)
+	}
 	if err != nil {
 		return errorResult(err.Error()), nil
 	}
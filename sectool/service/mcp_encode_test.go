@@ -27,6 +27,15 @@ func TestMCP_Encode(t *testing.T) {
 		assert.Equal(t, "aGVsbG8gd29ybGQ=", text)
 	})
 
+	t.Run("base64_variant", func(t *testing.T) {
+		text := CallMCPToolTextOK(t, mcpClient, "encode", map[string]interface{}{
+			"input":   "<<??",
+			"type":    "base64",
+			"variant": "rawurl",
+		})
+		assert.Equal(t, "PDw_Pw", text)
+	})
+
 	t.Run("html", func(t *testing.T) {
 		text := CallMCPToolTextOK(t, mcpClient, "encode", map[string]interface{}{
 			"input": "<script>alert('xss')</script>",
@@ -74,6 +83,14 @@ func TestMCP_Decode(t *testing.T) {
 		assert.Equal(t, "hello world", text)
 	})
 
+	t.Run("base64_url_unpadded", func(t *testing.T) {
+		text := CallMCPToolTextOK(t, mcpClient, "decode", map[string]interface{}{
+			"input": "PDw_Pw",
+			"type":  "base64",
+		})
+		assert.Equal(t, "<<??", text)
+	})
+
 	t.Run("html", func(t *testing.T) {
 		text := CallMCPToolTextOK(t, mcpClient, "decode", map[string]interface{}{
 			"input": "&lt;script&gt;alert(&#39;xss&#39;)&lt;/script&gt;",
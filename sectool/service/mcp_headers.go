@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+const (
+	headerCheckPass = "pass"
+	headerCheckWarn = "warn"
+	headerCheckFail = "fail"
+)
+
+// minHSTSMaxAge is the HSTS max-age below which the policy is reported as a warning (180 days).
+const minHSTSMaxAge = 15552000
+
+func (m *mcpServer) addHeaderTools() {
+	m.server.AddTool(m.securityHeadersTool(), m.handleSecurityHeaders)
+}
+
+func (m *mcpServer) securityHeadersTool() mcp.Tool {
+	return mcp.NewTool("security_headers",
+		mcp.WithDescription(`Check the security headers and cookie flags of a captured response.
+
+Reports presence and value of Content-Security-Policy, Strict-Transport-Security, X-Frame-Options, X-Content-Type-Options and Referrer-Policy, plus the Secure, HttpOnly and SameSite flags of every Set-Cookie. Each check has status pass, warn or fail with a detail explaining the verdict.
+
+HSTS is only required for https flows; frame-ancestors in the CSP satisfies X-Frame-Options.`),
+		mcp.WithString("flow_id", mcp.Required(), mcp.Description("Flow ID (from proxy_poll, replay_send, or crawl_poll)")),
+	)
+}
+
+func (m *mcpServer) handleSecurityHeaders(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	flowID := req.GetString("flow_id", "")
+	if flowID == "" {
+		return errorResult("flow_id is required"), nil
+	}
+
+	flow, errResult := m.resolveFlow(ctx, flowID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	log.Printf("mcp/security_headers: analyzing %s", flowID)
+
+	respHeaders, _ := splitHeadersBody(flow.RawResponse)
+	return jsonResult(&protocol.SecurityHeadersResponse{
+		FlowID: flowID,
+		Checks: analyzeSecurityHeaders(respHeaders, flowIsHTTPS(flow)),
+	})
+}
+
+// flowIsHTTPS reports whether the flow was sent over TLS, preferring the crawler URL when known
+// and otherwise inferring the scheme from the request line and Host header.
+func flowIsHTTPS(flow *resolvedFlow) bool {
+	if flow.URL != "" {
+		if u, err := url.Parse(flow.URL); err == nil {
+			return u.Scheme == schemeHTTPS
+		}
+	}
+	reqStr := string(flow.RawRequest)
+	firstLine, _, _ := strings.Cut(reqStr, "\r\n")
+	if _, target, ok := strings.Cut(firstLine, " "); ok && strings.HasPrefix(target, "http://") {
+		return false
+	}
+	_, host, _ := extractRequestMeta(reqStr)
+	scheme, _, _ := inferSchemeAndPort(host)
+	return scheme == schemeHTTPS
+}
+
+// analyzeSecurityHeaders evaluates the security headers and Set-Cookie flags of raw response headers.
+// Checks are returned in a fixed header order followed by one check per cookie.
+func analyzeSecurityHeaders(respHeaders []byte, https bool) []protocol.SecurityHeaderCheck {
+	headers := parseHeadersToMap(string(respHeaders))
+	header := func(name string) (string, bool) {
+		if vals := headers[name]; len(vals) > 0 {
+			return strings.Join(vals, ", "), true
+		}
+		return "", false
+	}
+
+	csp, hasCSP := header("Content-Security-Policy")
+	checks := []protocol.SecurityHeaderCheck{checkCSP(csp, hasCSP, headers)}
+
+	hsts, hasHSTS := header("Strict-Transport-Security")
+	checks = append(checks, checkHSTS(hsts, hasHSTS, https))
+
+	xfo, hasXFO := header("X-Frame-Options")
+	checks = append(checks, checkFrameOptions(xfo, hasXFO, cspDirective(csp, "frame-ancestors")))
+
+	xcto, hasXCTO := header("X-Content-Type-Options")
+	check := protocol.SecurityHeaderCheck{Name: "X-Content-Type-Options", Value: xcto}
+	switch {
+	case !hasXCTO:
+		check.Status, check.Detail = headerCheckFail, "missing: browsers may MIME-sniff responses"
+	case strings.EqualFold(strings.TrimSpace(xcto), "nosniff"):
+		check.Status = headerCheckPass
+	default:
+		check.Status, check.Detail = headerCheckWarn, "value is not nosniff"
+	}
+	checks = append(checks, check)
+
+	referrer, hasReferrer := header("Referrer-Policy")
+	check = protocol.SecurityHeaderCheck{Name: "Referrer-Policy", Value: referrer}
+	// The last recognized policy in a comma-separated list wins
+	policies := strings.Split(referrer, ",")
+	policy := strings.ToLower(strings.TrimSpace(policies[len(policies)-1]))
+	switch {
+	case !hasReferrer:
+		check.Status, check.Detail = headerCheckWarn, "missing: browser default policy applies"
+	case policy == "unsafe-url" || policy == "no-referrer-when-downgrade":
+		check.Status, check.Detail = headerCheckWarn, policy+" leaks full URLs to other origins"
+	default:
+		check.Status = headerCheckPass
+	}
+	checks = append(checks, check)
+
+	for _, raw := range headers["Set-Cookie"] {
+		checks = append(checks, checkCookie(raw, https))
+	}
+	return checks
+}
+
+func checkCSP(csp string, present bool, headers map[string][]string) protocol.SecurityHeaderCheck {
+	check := protocol.SecurityHeaderCheck{Name: "Content-Security-Policy", Value: csp}
+	if !present {
+		check.Status = headerCheckFail
+		check.Detail = "missing"
+		if ro := headers["Content-Security-Policy-Report-Only"]; len(ro) > 0 {
+			check.Detail = "missing: only Content-Security-Policy-Report-Only is set, which is not enforced"
+		}
+		return check
+	}
+
+	var weak []string
+	for _, directive := range []string{"script-src", "default-src"} {
+		sources := cspDirective(csp, directive)
+		if sources == nil {
+			continue
+		}
+		for _, src := range sources {
+			switch strings.ToLower(src) {
+			case "'unsafe-inline'", "'unsafe-eval'", "*", "data:", "http:", "https:":
+				weak = append(weak, directive+" "+src)
+			}
+		}
+		break // script-src overrides default-src for scripts
+	}
+	if len(weak) > 0 {
+		check.Status, check.Detail = headerCheckWarn, "weak sources: "+strings.Join(weak, ", ")
+	} else {
+		check.Status = headerCheckPass
+	}
+	return check
+}
+
+// cspDirective returns the source list of a CSP directive, or nil when the directive is absent.
+func cspDirective(csp, name string) []string {
+	for _, directive := range strings.Split(csp, ";") {
+		fields := strings.Fields(directive)
+		if len(fields) > 0 && strings.EqualFold(fields[0], name) {
+			return append([]string{}, fields[1:]...)
+		}
+	}
+	return nil
+}
+
+func checkHSTS(hsts string, present, https bool) protocol.SecurityHeaderCheck {
+	check := protocol.SecurityHeaderCheck{Name: "Strict-Transport-Security", Value: hsts}
+	if !https {
+		check.Status, check.Detail = headerCheckWarn, "flow is plain http: HSTS cannot apply"
+		if present {
+			check.Detail = "set over plain http, where browsers ignore it"
+		}
+		return check
+	} else if !present {
+		check.Status, check.Detail = headerCheckFail, "missing"
+		return check
+	}
+
+	maxAge := -1
+	for _, part := range strings.Split(hsts, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if strings.EqualFold(strings.TrimSpace(name), "max-age") {
+			if n, err := strconv.Atoi(strings.Trim(strings.TrimSpace(value), `"`)); err == nil {
+				maxAge = n
+			}
+		}
+	}
+	switch {
+	case maxAge < 0:
+		check.Status, check.Detail = headerCheckFail, "missing or invalid max-age"
+	case maxAge == 0:
+		check.Status, check.Detail = headerCheckFail, "max-age=0 disables HSTS"
+	case maxAge < minHSTSMaxAge:
+		check.Status, check.Detail = headerCheckWarn, "max-age below 180 days"
+	default:
+		check.Status = headerCheckPass
+	}
+	return check
+}
+
+func checkFrameOptions(xfo string, present bool, frameAncestors []string) protocol.SecurityHeaderCheck {
+	check := protocol.SecurityHeaderCheck{Name: "X-Frame-Options", Value: xfo}
+	switch value := strings.ToUpper(strings.TrimSpace(xfo)); {
+	case present && (value == "DENY" || value == "SAMEORIGIN"):
+		check.Status = headerCheckPass
+	case frameAncestors != nil:
+		check.Status, check.Detail = headerCheckPass, "framing restricted by CSP frame-ancestors"
+	case !present:
+		check.Status, check.Detail = headerCheckFail, "missing: page can be framed (clickjacking)"
+	default:
+		check.Status, check.Detail = headerCheckWarn, "unsupported value: browsers ignore it"
+	}
+	return check
+}
+
+func checkCookie(raw string, https bool) protocol.SecurityHeaderCheck {
+	name, _, _ := strings.Cut(raw, "=")
+	check := protocol.SecurityHeaderCheck{Name: "Set-Cookie: " + strings.TrimSpace(name), Value: raw}
+
+	cookie, err := http.ParseSetCookie(raw)
+	if err != nil {
+		check.Status, check.Detail = headerCheckWarn, "unparseable cookie: "+err.Error()
+		return check
+	}
+	check.Name = "Set-Cookie: " + cookie.Name
+
+	var fails, warns []string
+	if !cookie.Secure {
+		if https {
+			fails = append(fails, "missing Secure")
+		} else {
+			warns = append(warns, "missing Secure")
+		}
+	}
+	if !cookie.HttpOnly {
+		fails = append(fails, "missing HttpOnly")
+	}
+	switch cookie.SameSite {
+	case 0, http.SameSiteDefaultMode: // absent, or present without a value
+		warns = append(warns, "missing SameSite")
+	case http.SameSiteNoneMode:
+		if !cookie.Secure {
+			fails = append(fails, "SameSite=None without Secure")
+		}
+	}
+
+	switch {
+	case len(fails) > 0:
+		check.Status = headerCheckFail
+	case len(warns) > 0:
+		check.Status = headerCheckWarn
+	default:
+		check.Status = headerCheckPass
+	}
+	check.Detail = strings.Join(append(fails, warns...), ", ")
+	return check
+}
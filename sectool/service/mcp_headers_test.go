@@ -0,0 +1,174 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func findHeaderCheck(checks []protocol.SecurityHeaderCheck, name string) *protocol.SecurityHeaderCheck {
+	for i := range checks {
+		if checks[i].Name == name {
+			return &checks[i]
+		}
+	}
+	return nil
+}
+
+func TestAnalyzeSecurityHeaders(t *testing.T) {
+	t.Parallel()
+
+	const hardened = "HTTP/1.1 200 OK\r\n" +
+		"Content-Security-Policy: default-src 'self'; frame-ancestors 'none'\r\n" +
+		"Strict-Transport-Security: max-age=31536000; includeSubDomains\r\n" +
+		"X-Frame-Options: DENY\r\n" +
+		"X-Content-Type-Options: nosniff\r\n" +
+		"Referrer-Policy: strict-origin-when-cross-origin\r\n" +
+		"Set-Cookie: session=abc; Path=/; Secure; HttpOnly; SameSite=Lax\r\n\r\n"
+
+	t.Run("hardened", func(t *testing.T) {
+		checks := analyzeSecurityHeaders([]byte(hardened), true)
+		require.Len(t, checks, 6)
+		for _, c := range checks {
+			assert.Equal(t, headerCheckPass, c.Status, c.Name)
+		}
+		assert.Equal(t, "Set-Cookie: session", checks[5].Name)
+	})
+
+	t.Run("missing_csp", func(t *testing.T) {
+		checks := analyzeSecurityHeaders([]byte("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n"), true)
+		require.Len(t, checks, 5)
+
+		csp := findHeaderCheck(checks, "Content-Security-Policy")
+		require.NotNil(t, csp)
+		assert.Equal(t, headerCheckFail, csp.Status)
+		assert.Equal(t, "missing", csp.Detail)
+		assert.Empty(t, csp.Value)
+
+		assert.Equal(t, headerCheckFail, findHeaderCheck(checks, "Strict-Transport-Security").Status)
+		assert.Equal(t, headerCheckFail, findHeaderCheck(checks, "X-Frame-Options").Status)
+		assert.Equal(t, headerCheckFail, findHeaderCheck(checks, "X-Content-Type-Options").Status)
+		assert.Equal(t, headerCheckWarn, findHeaderCheck(checks, "Referrer-Policy").Status)
+	})
+
+	t.Run("cookie_without_httponly", func(t *testing.T) {
+		checks := analyzeSecurityHeaders([]byte("HTTP/1.1 200 OK\r\n"+
+			"Set-Cookie: sid=xyz; Path=/; Secure; SameSite=Strict\r\n"+
+			"Set-Cookie: theme=dark; Path=/\r\n\r\n"), true)
+
+		sid := findHeaderCheck(checks, "Set-Cookie: sid")
+		require.NotNil(t, sid)
+		assert.Equal(t, headerCheckFail, sid.Status)
+		assert.Equal(t, "missing HttpOnly", sid.Detail)
+		assert.Equal(t, "sid=xyz; Path=/; Secure; SameSite=Strict", sid.Value)
+
+		theme := findHeaderCheck(checks, "Set-Cookie: theme")
+		require.NotNil(t, theme)
+		assert.Equal(t, headerCheckFail, theme.Status)
+		assert.Equal(t, "missing Secure, missing HttpOnly, missing SameSite", theme.Detail)
+	})
+
+	t.Run("samesite_none_without_secure", func(t *testing.T) {
+		checks := analyzeSecurityHeaders([]byte("HTTP/1.1 200 OK\r\nSet-Cookie: t=1; HttpOnly; SameSite=None\r\n\r\n"), false)
+		c := findHeaderCheck(checks, "Set-Cookie: t")
+		require.NotNil(t, c)
+		assert.Equal(t, headerCheckFail, c.Status)
+		assert.Equal(t, "SameSite=None without Secure, missing Secure", c.Detail)
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		https  bool
+		check  string
+		status string
+	}{
+		{name: "csp_unsafe_inline", header: "Content-Security-Policy: script-src 'self' 'unsafe-inline'", https: true, check: "Content-Security-Policy", status: headerCheckWarn},
+		{name: "csp_script_src_overrides_default", header: "Content-Security-Policy: default-src *; script-src 'self'", https: true, check: "Content-Security-Policy", status: headerCheckPass},
+		{name: "csp_report_only", header: "Content-Security-Policy-Report-Only: default-src 'self'", https: true, check: "Content-Security-Policy", status: headerCheckFail},
+		{name: "hsts_short_max_age", header: "Strict-Transport-Security: max-age=3600", https: true, check: "Strict-Transport-Security", status: headerCheckWarn},
+		{name: "hsts_zero_max_age", header: "Strict-Transport-Security: max-age=0", https: true, check: "Strict-Transport-Security", status: headerCheckFail},
+		{name: "hsts_plain_http", header: "Strict-Transport-Security: max-age=31536000", check: "Strict-Transport-Security", status: headerCheckWarn},
+		{name: "xfo_allow_from", header: "X-Frame-Options: ALLOW-FROM https://a.com", https: true, check: "X-Frame-Options", status: headerCheckWarn},
+		{name: "xfo_via_frame_ancestors", header: "Content-Security-Policy: frame-ancestors 'self'", https: true, check: "X-Frame-Options", status: headerCheckPass},
+		{name: "xcto_other_value", header: "X-Content-Type-Options: sniff", https: true, check: "X-Content-Type-Options", status: headerCheckWarn},
+		{name: "referrer_unsafe_url", header: "Referrer-Policy: unsafe-url", https: true, check: "Referrer-Policy", status: headerCheckWarn},
+		{name: "referrer_fallback_list", header: "Referrer-Policy: no-referrer, strict-origin-when-cross-origin", https: true, check: "Referrer-Policy", status: headerCheckPass},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := analyzeSecurityHeaders([]byte("HTTP/1.1 200 OK\r\n"+tt.header+"\r\n\r\n"), tt.https)
+			c := findHeaderCheck(checks, tt.check)
+			require.NotNil(t, c)
+			assert.Equal(t, tt.status, c.Status, c.Detail)
+		})
+	}
+}
+
+func TestHandleSecurityHeaders(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, mockCrawler := setupMockMCPServer(t)
+
+	mockMCP.AddProxyEntry(
+		"GET / HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		"HTTP/1.1 200 OK\r\n"+
+			"Content-Type: text/html\r\n"+
+			"Set-Cookie: session=abc; Secure; SameSite=Lax\r\n\r\n"+
+			"<html></html>",
+		"",
+	)
+	listResp := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"limit":       10,
+	})
+	require.Len(t, listResp.Flows, 1)
+
+	t.Run("proxy_flow", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.SecurityHeadersResponse](t, mcpClient, "security_headers", map[string]interface{}{
+			"flow_id": listResp.Flows[0].FlowID,
+		})
+		assert.Equal(t, listResp.Flows[0].FlowID, resp.FlowID)
+		assert.Equal(t, headerCheckFail, findHeaderCheck(resp.Checks, "Content-Security-Policy").Status)
+		assert.Equal(t, headerCheckFail, findHeaderCheck(resp.Checks, "Strict-Transport-Security").Status)
+		cookie := findHeaderCheck(resp.Checks, "Set-Cookie: session")
+		require.NotNil(t, cookie)
+		assert.Equal(t, headerCheckFail, cookie.Status)
+		assert.Equal(t, "missing HttpOnly", cookie.Detail)
+	})
+
+	t.Run("crawl_flow_http", func(t *testing.T) {
+		createResp := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls": "http://example.com",
+		})
+		require.NoError(t, mockCrawler.AddFlow(createResp.SessionID, CrawlFlow{
+			ID:       "crawl-hdr",
+			URL:      "http://example.com/",
+			Request:  []byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"),
+			Response: []byte("HTTP/1.1 200 OK\r\nX-Content-Type-Options: nosniff\r\n\r\n"),
+		}))
+
+		resp := CallMCPToolJSONOK[protocol.SecurityHeadersResponse](t, mcpClient, "security_headers", map[string]interface{}{
+			"flow_id": "crawl-hdr",
+		})
+		hsts := findHeaderCheck(resp.Checks, "Strict-Transport-Security")
+		require.NotNil(t, hsts)
+		assert.Equal(t, headerCheckWarn, hsts.Status)
+		assert.Equal(t, headerCheckPass, findHeaderCheck(resp.Checks, "X-Content-Type-Options").Status)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "security_headers", map[string]interface{}{
+			"flow_id": "missing",
+		})
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("missing_flow_id", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "security_headers", map[string]interface{}{})
+		assert.True(t, result.IsError)
+	})
+}
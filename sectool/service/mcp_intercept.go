@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func (m *mcpServer) proxyInterceptTool() mcp.Tool {
+	return mcp.NewTool("proxy_intercept",
+		mcp.WithDescription(`Hold proxied browser requests until released.
+
+status (default): intercept state and the queue of held requests.
+on: hold requests matching host/path/method (all when omitted). While on, new HTTPS connections use HTTP/1.1 so each request can be held; connections opened earlier are not held.
+off: stop holding and forward every held request.
+release: forward the held request held_id, or all held requests when omitted.
+drop: refuse the held request held_id (the browser gets a 502), or all when omitted.
+Held requests are dropped after 5 minutes. Intercept stays on across CLI invocations until turned off.`),
+		mcp.WithString("action", mcp.Description("status (default), on, off, release, or drop")),
+		mcp.WithString("host", mcp.Description("on: host glob to hold, e.g. '*.example.com'")),
+		mcp.WithString("path", mcp.Description("on: path glob to hold, e.g. '/api/*'")),
+		mcp.WithString("method", mcp.Description("on: HTTP method to hold")),
+		mcp.WithString("held_id", mcp.Description("release/drop: held request ID from status; all held requests when omitted")),
+	)
+}
+
+func (m *mcpServer) handleProxyIntercept(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	interceptor, ok := m.service.httpBackend.(proxyInterceptor)
+	if !ok {
+		return errorResult("proxy intercept is not supported by this proxy backend"), nil
+	}
+
+	var resp protocol.ProxyInterceptResponse
+	switch action := req.GetString("action", "status"); action {
+	case "status", "":
+	case "on":
+		filter := InterceptFilter{
+			Host:   req.GetString("host", ""),
+			Path:   req.GetString("path", ""),
+			Method: req.GetString("method", ""),
+		}
+		if err := interceptor.SetIntercept(ctx, true, filter); err != nil {
+			return errorResultFromErr("failed to enable intercept: ", err), nil
+		}
+	case "off":
+		held := len(interceptor.InterceptStatus().Held)
+		if err := interceptor.SetIntercept(ctx, false, InterceptFilter{}); err != nil {
+			return errorResultFromErr("failed to disable intercept: ", err), nil
+		}
+		resp.Released = held
+	case "release", "drop":
+		n, err := interceptor.ReleaseHeld(req.GetString("held_id", ""), action == "drop")
+		if err != nil {
+			return errorResult(err.Error()), nil
+		} else if action == "drop" {
+			resp.Dropped = n
+		} else {
+			resp.Released = n
+		}
+	default:
+		return errorResult("invalid action: use status, on, off, release, or drop"), nil
+	}
+
+	status := interceptor.InterceptStatus()
+	log.Printf("mcp/proxy_intercept: enabled=%v held=%d", status.Enabled, len(status.Held))
+
+	resp.Enabled = status.Enabled
+	resp.Host = status.Filter.Host
+	resp.Path = status.Filter.Path
+	resp.Method = status.Filter.Method
+	resp.Held = make([]protocol.HeldRequestEntry, 0, len(status.Held))
+	for _, h := range status.Held {
+		resp.Held = append(resp.Held, protocol.HeldRequestEntry{
+			HeldID:  h.ID,
+			Method:  h.Method,
+			Host:    h.Host,
+			Path:    h.Path,
+			HeldFor: time.Since(h.HeldAt).Round(time.Second).String(),
+		})
+	}
+	return jsonResult(resp)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func TestMCP_ProxyIntercept(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, _ := setupMockMCPServer(t)
+
+	status := CallMCPToolJSONOK[protocol.ProxyInterceptResponse](t, mcpClient, "proxy_intercept", map[string]interface{}{})
+	assert.False(t, status.Enabled)
+	assert.Empty(t, status.Held)
+
+	on := CallMCPToolJSONOK[protocol.ProxyInterceptResponse](t, mcpClient, "proxy_intercept", map[string]interface{}{
+		"action": "on",
+	})
+	assert.True(t, on.Enabled)
+
+	// State persists across calls
+	status = CallMCPToolJSONOK[protocol.ProxyInterceptResponse](t, mcpClient, "proxy_intercept", map[string]interface{}{
+		"action": "status",
+	})
+	assert.True(t, status.Enabled)
+
+	off := CallMCPToolJSONOK[protocol.ProxyInterceptResponse](t, mcpClient, "proxy_intercept", map[string]interface{}{
+		"action": "off",
+	})
+	assert.False(t, off.Enabled)
+
+	t.Run("burp_rejects_filter", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "proxy_intercept", map[string]interface{}{
+			"action": "on",
+			"host":   "example.com",
+		})
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("burp_rejects_release", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "proxy_intercept", map[string]interface{}{"action": "release"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "Intercept tab")
+	})
+
+	t.Run("invalid_action", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "proxy_intercept", map[string]interface{}{"action": "pause"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid action")
+	})
+}
@@ -10,7 +10,7 @@ import (
 
 func (m *mcpServer) jwtDecodeTool() mcp.Tool {
 	return mcp.NewTool("jwt_decode",
-		mcp.WithDescription("Decode a JWT. Returns header, payload, signature, and security issues."),
+		mcp.WithDescription("Decode a JWT without verifying it. Returns header, payload, signature (raw and signature_hex), and security issues including alg=none and missing or extra segments."),
 		mcp.WithString("token", mcp.Required(), mcp.Description("JWT string (Bearer prefix auto-stripped)")),
 	)
 }
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func (m *mcpServer) addKillSwitchTools() {
+	m.server.AddTool(m.killSwitchTool(), m.handleKillSwitch)
+}
+
+func (m *mcpServer) killSwitchTool() mcp.Tool {
+	return mcp.NewTool("kill_switch",
+		mcp.WithDescription(`Emergency stop for all target traffic.
+
+engage (default): cancels in-flight replay_send/request_send/replay_smuggle requests, stops every running crawl, makes the built-in proxy drop browser connections, and refuses new traffic-generating calls until cleared. Use immediately when the user or target owner asks to stop testing.
+clear: re-enables traffic; stopped crawls are not resumed. Only clear when the user explicitly asks.
+status: report whether the kill switch is engaged.`),
+		mcp.WithString("action", mcp.Description("engage (default), clear, or status")),
+		mcp.WithString("reason", mcp.Description("Why traffic is being stopped; logged and reported to later refused calls")),
+	)
+}
+
+func (m *mcpServer) handleKillSwitch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Not gated on the workflow call: stopping must always be possible
+	var status KillSwitchStatus
+	switch action := req.GetString("action", "engage"); action {
+	case "engage", "":
+		status = m.service.EngageKillSwitch(ctx, req.GetString("reason", ""))
+	case "clear":
+		m.service.ClearKillSwitch()
+		status = m.service.KillSwitchState()
+	case "status":
+		status = m.service.KillSwitchState()
+	default:
+		return errorResult("invalid action: use engage, clear, or status"), nil
+	}
+	log.Printf("mcp/kill_switch: engaged=%v", status.Engaged)
+
+	resp := protocol.KillSwitchResponse{
+		Engaged:       status.Engaged,
+		Reason:        status.Reason,
+		StoppedCrawls: status.StoppedCrawls,
+		ProxyHalted:   status.ProxyHalted,
+	}
+	if status.Engaged {
+		resp.EngagedAt = status.EngagedAt.UTC().Format(time.RFC3339)
+	}
+	return jsonResult(resp)
+}
+
+// requestErrorResult reports a failed target request, naming the kill switch when it aborted the request.
+func (m *mcpServer) requestErrorResult(err error) *mcp.CallToolResult {
+	if errors.Is(err, context.Canceled) {
+		if ksErr := m.service.killSwitchErr(); ksErr != nil {
+			return errorResult("request aborted: " + ksErr.Error())
+		}
+	}
+	return errorResultFromErr("request failed: ", err)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func TestMCP_KillSwitch(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, mockCrawler := setupMockMCPServer(t)
+
+	mockMCP.SetSendResponse(
+		"HttpRequestResponse{httpRequest=GET /test HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\n\r\nok}",
+	)
+	sendArgs := map[string]interface{}{"url": "https://example.com/test"}
+
+	crawl := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls": "https://example.com/",
+	})
+
+	engaged := CallMCPToolJSONOK[protocol.KillSwitchResponse](t, mcpClient, "kill_switch", map[string]interface{}{
+		"reason": "target owner called",
+	})
+	assert.True(t, engaged.Engaged)
+	assert.Equal(t, "target owner called", engaged.Reason)
+	assert.NotEmpty(t, engaged.EngagedAt)
+	assert.Equal(t, 1, engaged.StoppedCrawls)
+	assert.Equal(t, "stopped", mockCrawler.sessions[crawl.SessionID].State)
+
+	t.Run("refuses_traffic", func(t *testing.T) {
+		for _, tc := range []struct {
+			tool string
+			args map[string]interface{}
+		}{
+			{"request_send", sendArgs},
+			{"crawl_create", map[string]interface{}{"seed_urls": "https://example.com/"}},
+			{"crawl_seed", map[string]interface{}{"session_id": crawl.SessionID, "seed_urls": "https://example.com/a"}},
+		} {
+			result := CallMCPTool(t, mcpClient, tc.tool, tc.args)
+			assert.True(t, result.IsError, tc.tool)
+			assert.Contains(t, ExtractMCPText(t, result), "kill switch engaged", tc.tool)
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		status := CallMCPToolJSONOK[protocol.KillSwitchResponse](t, mcpClient, "kill_switch", map[string]interface{}{
+			"action": "status",
+		})
+		assert.True(t, status.Engaged)
+		assert.Equal(t, "target owner called", status.Reason)
+	})
+
+	t.Run("invalid_action", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "kill_switch", map[string]interface{}{"action": "pause"})
+		assert.True(t, result.IsError)
+	})
+
+	cleared := CallMCPToolJSONOK[protocol.KillSwitchResponse](t, mcpClient, "kill_switch", map[string]interface{}{
+		"action": "clear",
+	})
+	assert.False(t, cleared.Engaged)
+
+	resp := CallMCPToolJSONOK[protocol.ReplaySendResponse](t, mcpClient, "request_send", sendArgs)
+	require.Equal(t, 200, resp.Status)
+}
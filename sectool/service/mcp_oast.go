@@ -41,7 +41,7 @@ Options:
 - Incremental: use since parameter, accepts event_id or "last"
 - Filter by type: dns, http, smtp, ftp, ldap, smb, responder
 
-Response includes events/aggregates and optional dropped_count; use oast_get for full event details.`),
+Response includes events/aggregates and optional dropped_count (events evicted by the buffer limit) and failed_count (interactions the server returned that could not be decrypted or parsed); use oast_get for full event details.`),
 		mcp.WithString("oast_id", mcp.Required(), mcp.Description("OAST session ID, label, or domain")),
 		mcp.WithString("output_mode", mcp.Description("Output mode: 'summary' (default) or 'events'")),
 		mcp.WithString("since", mcp.Description("event_id or 'last' (per-session cursor)")),
@@ -53,7 +53,14 @@ Response includes events/aggregates and optional dropped_count; use oast_get for
 
 func (m *mcpServer) oastGetTool() mcp.Tool {
 	return mcp.NewTool("oast_get",
-		mcp.WithDescription("Get full OAST event data: HTTP request/response, DNS query type/answer, SMTP headers/body."),
+		mcp.WithDescription(`Get full, untruncated OAST event data for an event_id from oast_poll.
+
+Details by type:
+- http: raw_request (request line, headers, body) and raw_response
+- dns: query_name, query_type, raw_request (the interactsh server's text dump of the message; the wire packet is not available)
+- smtp: smtp_from and raw_request (headers/body)
+
+Errors with "event not found" when the event_id is unknown or belongs to a different oast_id.`),
 		mcp.WithString("oast_id", mcp.Required(), mcp.Description("OAST session ID, label, or domain")),
 		mcp.WithString("event_id", mcp.Required(), mcp.Description("Event ID from oast_poll")),
 	)
@@ -145,6 +152,7 @@ func (m *mcpServer) handleOastPoll(ctx context.Context, req mcp.CallToolRequest)
 		return jsonResult(protocol.OastPollResponse{
 			Events:       events,
 			DroppedCount: result.DroppedCount,
+			FailedCount:  result.FailedCount,
 		})
 
 	default: // summary
@@ -153,6 +161,7 @@ func (m *mcpServer) handleOastPoll(ctx context.Context, req mcp.CallToolRequest)
 		return jsonResult(protocol.OastPollResponse{
 			Aggregates:   agg,
 			DroppedCount: result.DroppedCount,
+			FailedCount:  result.FailedCount,
 		})
 	}
 }
@@ -207,8 +216,10 @@ func (m *mcpServer) handleOastGet(ctx context.Context, req mcp.CallToolRequest)
 
 	event, err := m.service.oastBackend.GetEvent(ctx, oastID, eventID)
 	if err != nil {
-		if errors.Is(err, ErrNotFound) {
-			return errorResult("session or event not found"), nil
+		if errors.Is(err, ErrEventNotFound) {
+			return errorResult("event not found: " + eventID + " is not an event of OAST session " + oastID + " (use oast_poll to list event IDs)"), nil
+		} else if errors.Is(err, ErrNotFound) {
+			return errorResult("OAST session not found: " + oastID), nil
 		}
 		return errorResultFromErr("failed to get event: ", err), nil
 	}
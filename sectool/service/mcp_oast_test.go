@@ -188,6 +188,26 @@ func TestMCP_OastValidation(t *testing.T) {
 		assert.Contains(t, ExtractMCPText(t, result), "event_id is required")
 	})
 
+	t.Run("get_unknown_event", func(t *testing.T) {
+		createResp := CallMCPToolJSONOK[protocol.OastCreateResponse](t, mcpClient, "oast_create", nil)
+
+		result := CallMCPTool(t, mcpClient, "oast_get", map[string]interface{}{
+			"oast_id":  createResp.OastID,
+			"event_id": "missing",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "event not found")
+	})
+
+	t.Run("get_unknown_session", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "oast_get", map[string]interface{}{
+			"oast_id":  "nonexistent",
+			"event_id": "missing",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "OAST session not found")
+	})
+
 	t.Run("delete_missing_id", func(t *testing.T) {
 		result := CallMCPTool(t, mcpClient, "oast_delete", map[string]interface{}{})
 		assert.True(t, result.IsError)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"regexp"
 	"slices"
 	"sort"
@@ -55,7 +56,7 @@ func (m *mcpServer) proxyGetTool() mcp.Tool {
 	return mcp.NewTool("proxy_get",
 		mcp.WithDescription(`Get full request and response data for a proxy history entry.
 
-Returns headers and body for both request and response. Binary bodies are returned as "<BINARY:N Bytes>" placeholder.
+Returns headers and body for both request and response. Binary bodies are returned as "<BINARY:N Bytes>" placeholder. Compressed bodies are decoded. request_truncated/response_truncated are set when a body hit max_body_bytes during capture.
 Use flow_id from proxy_poll (output_mode=flows) to identify the entry.
 
 Scope: Sections to return (comma-separated): request_headers, request_body, response_headers, response_body, all (default).
@@ -80,13 +81,19 @@ func (m *mcpServer) proxyRuleAddTool() mcp.Tool {
 
 Types:
   HTTP:      request_header (default), request_body, response_header, response_body
+  Actions:   block (answer 403), redirect (answer 302 to replace, an absolute URL)
   WebSocket: ws:to-server, ws:to-client, ws:both
 
+block and redirect take no match; they apply to requests matching host, path (globs, * and ?) and method, all optional. Matching requests are not forwarded or recorded in history. Built-in proxy only; while they exist, TLS connections use HTTP/1.1.
+
 Regex: is_regex=true (RE2 regex). Labels must be unique.
 To modify a rule, delete it with proxy_rule_delete and recreate.`),
-		mcp.WithString("type", mcp.Required(), mcp.Description("Rule type: request_header, request_body, response_header, response_body, ws:to-server, ws:to-client, ws:both")),
+		mcp.WithString("type", mcp.Required(), mcp.Description("Rule type: request_header, request_body, response_header, response_body, block, redirect, ws:to-server, ws:to-client, ws:both")),
 		mcp.WithString("match", mcp.Description("Pattern to find")),
-		mcp.WithString("replace", mcp.Description("Replacement text")),
+		mcp.WithString("replace", mcp.Description("Replacement text; redirect target URL for redirect")),
+		mcp.WithString("host", mcp.Description("Host glob for block/redirect, e.g. *.tracker.com")),
+		mcp.WithString("path", mcp.Description("Path glob for block/redirect, query excluded, e.g. /admin/*")),
+		mcp.WithString("method", mcp.Description("HTTP method for block/redirect")),
 		mcp.WithString("label", mcp.Description("Optional unique label (usable as rule_id)")),
 		mcp.WithBoolean("is_regex", mcp.Description("Treat match as regex pattern (RE2)")),
 	)
@@ -464,6 +471,15 @@ func (m *mcpServer) handleProxyGet(ctx context.Context, req mcp.CallToolRequest)
 		"request_size":  len(reqBody),
 		"response_size": len(respBody),
 	}
+	// Captures are cut at max_body_bytes; a body of that length hit the limit
+	if limit := m.service.cfg.MaxBodyBytes; limit > 0 {
+		if len(reqBody) >= limit {
+			result["request_truncated"] = true
+		}
+		if len(respBody) >= limit {
+			result["response_truncated"] = true
+		}
+	}
 
 	if patternRe != nil {
 		// Pattern mode: grep-like context output
@@ -580,7 +596,7 @@ func (m *mcpServer) handleProxyRuleAdd(ctx context.Context, req mcp.CallToolRequ
 
 	match := req.GetString("match", "")
 	replace := req.GetString("replace", "")
-	if match == "" && replace == "" {
+	if match == "" && replace == "" && ruleType != RuleTypeBlock {
 		return errorResult("match or replace is required"), nil
 	}
 	label := req.GetString("label", "")
@@ -597,6 +613,9 @@ func (m *mcpServer) handleProxyRuleAdd(ctx context.Context, req mcp.CallToolRequ
 		IsRegex: &isRegex,
 		Match:   match,
 		Replace: replace,
+		Host:    req.GetString("host", ""),
+		Path:    req.GetString("path", ""),
+		Method:  req.GetString("method", ""),
 	})
 	if err != nil {
 		if errors.Is(err, ErrLabelExists) {
@@ -910,6 +929,8 @@ var validRuleTypes = map[string]bool{
 	RuleTypeRequestBody:    true,
 	RuleTypeResponseHeader: true,
 	RuleTypeResponseBody:   true,
+	RuleTypeBlock:          true,
+	RuleTypeRedirect:       true,
 	// WebSocket types
 	RuleTypeWSToServer: true,
 	RuleTypeWSToClient: true,
@@ -923,6 +944,29 @@ func validateRuleTypeAny(t string) error {
 	return nil
 }
 
+// validateRuleAction checks the fields block and redirect rules use differently from
+// match/replace rules: only they are scoped by host, path and method, and a redirect
+// takes its absolute target URL in Replace.
+func validateRuleAction(input ProxyRuleInput) error {
+	switch input.Type {
+	case RuleTypeBlock, RuleTypeRedirect:
+		if input.Match != "" {
+			return errors.New("match is not used by block and redirect rules: scope them with host, path and method")
+		} else if input.Type == RuleTypeBlock && input.Replace != "" {
+			return errors.New("replace is not used by block rules")
+		} else if input.Type == RuleTypeRedirect {
+			if u, err := url.Parse(input.Replace); err != nil || (u.Scheme != schemeHTTP && u.Scheme != schemeHTTPS) || u.Host == "" {
+				return fmt.Errorf("redirect rules need an absolute http(s) URL as replace, got %q", input.Replace)
+			}
+		}
+	default:
+		if input.Host != "" || input.Path != "" || input.Method != "" {
+			return errors.New("host, path and method only scope block and redirect rules")
+		}
+	}
+	return nil
+}
+
 // unDoubleEscapeRegex collapses double-escaped regex metacharacters (\\X → \X).
 // LLM agents sometimes produce double-escaped patterns (e.g. \\* instead of \*)
 // due to extra JSON encoding of backslashes in tool call arguments.
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/base64"
 	"encoding/json"
 	"testing"
@@ -470,6 +472,70 @@ func TestMCP_ProxyGetFullBodyReturnsBase64(t *testing.T) {
 	assert.Equal(t, "plain text response body", string(decodedBody))
 }
 
+func TestMCP_ProxyGetDecodesGzip(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+	// Seeded as a crawl flow: binary bodies do not survive the string-based proxy mock
+	createResp := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls": "https://test.com",
+	})
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte(`{"user":"alice"}`))
+	require.NoError(t, zw.Close())
+	require.NoError(t, mockCrawler.AddFlow(createResp.SessionID, CrawlFlow{
+		ID:        "gz-flow",
+		SessionID: createResp.SessionID,
+		Request:   []byte("GET /gz HTTP/1.1\r\nHost: test.com\r\n\r\n"),
+		Response:  append([]byte("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Encoding: gzip\r\n\r\n"), gz.Bytes()...),
+	}))
+
+	t.Run("preview", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ProxyGetResponse](t, mcpClient, "proxy_get", map[string]interface{}{
+			"flow_id": "gz-flow",
+		})
+		assert.JSONEq(t, `{"user":"alice"}`, resp.RespBody)
+		assert.Equal(t, gz.Len(), resp.RespSize)
+		assert.False(t, resp.RespTruncated)
+	})
+
+	t.Run("full_body", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.ProxyGetResponse](t, mcpClient, "proxy_get", map[string]interface{}{
+			"flow_id":   "gz-flow",
+			"full_body": true,
+		})
+		body, err := base64.StdEncoding.DecodeString(resp.RespBody)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"user":"alice"}`, string(body))
+	})
+}
+
+func TestMCP_ProxyGetTruncated(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMockMCPServerWithConfig(t, &config.Config{MaxBodyBytes: 16})
+
+	mockMCP.AddProxyEntry(
+		"POST /upload HTTP/1.1\r\nHost: test.com\r\n\r\nshort",
+		"HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\n0123456789abcdef",
+		"",
+	)
+
+	listResp := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
+		"output_mode": "flows",
+		"host":        "test.com",
+	})
+	require.Len(t, listResp.Flows, 1)
+
+	resp := CallMCPToolJSONOK[protocol.ProxyGetResponse](t, mcpClient, "proxy_get", map[string]interface{}{
+		"flow_id": listResp.Flows[0].FlowID,
+	})
+	assert.False(t, resp.ReqTruncated)
+	assert.True(t, resp.RespTruncated)
+}
+
 func TestMCP_ProxyPollSearchFallbackNote(t *testing.T) {
 	t.Parallel()
 
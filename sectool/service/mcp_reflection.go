@@ -11,7 +11,6 @@ import (
 	"mime"
 	"mime/multipart"
 	"net/url"
-	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,7 +20,14 @@ import (
 	"github.com/go-appsec/toolbox/sectool/protocol"
 )
 
-const minReflectionValueLen = 4
+// defaultMinReflectionLen is the shortest parameter value searched for unless min_length is set;
+// shorter values match too often by coincidence.
+const defaultMinReflectionLen = 4
+
+// maxClassifiedOccurrences caps how many occurrences of each encoded variant are located and
+// context-classified in the body; further occurrences only add to the total count and set
+// OccurrencesCapped.
+const maxClassifiedOccurrences = 100
 
 // Standard headers unlikely to represent user-controlled reflection vectors.
 // Uses lowercase keys for case-insensitive lookup (matches H2 lowercase headers directly).
@@ -67,10 +73,17 @@ func (m *mcpServer) findReflectedTool() mcp.Tool {
 
 Extracts parameters from the request (query string, form body, JSON body, multipart, cookies, headers) and searches the response for each value across multiple encoding variants. Compressed payloads are decompressed before extraction and searching.
 
-Returns only parameters with at least one reflection. Skips values shorter than 4 characters.
+Returns only parameters with at least one reflection. Skips values shorter than min_length (default 4); lower it when short IDs matter, raise it to cut noise.
+
+Locations indicate where: body:<context> (html_text, html_attribute, url, script, css, html_comment, json, svg, svg_attribute, xml_text, xml_attribute, xml_comment, xml_cdata) or header:<name>. svg contexts (inline <svg> or SVG documents) allow <script> and event handlers where HTML escaping rules differ. encodings maps each location to the encoding the matched reflection used: none (reflected as sent, the most exploitable), url_query, url_path, html_entity, html_decimal, html_hex, js_unicode or js_hex; this shows which filter a payload must bypass. The raw_reflected flag signals special characters appeared unencoded (no sanitization). occurrences counts matches per location and occurrence_count across the response: a value echoed many times, especially in several contexts, usually offers more than one sink. Only the first 100 body matches of each encoding are located; occurrences_capped=true means occurrence_count includes matches missing from occurrences.
 
-Locations indicate where: body:<context> (html_text, html_attribute, url, script, css, html_comment, json) or header:<name>. The raw_reflected flag signals special characters appeared unencoded (no sanitization).`),
+jsonp_callback names the query or body parameter whose value a JavaScript response calls as a function (JSONP): an XSS vector through the callback name and a cross-site readable data endpoint.
+
+Limit returns only the first N reflections in sorted order; truncated=true indicates more exist.`),
 		mcp.WithString("flow_id", mcp.Required(), mcp.Description("Flow ID (from proxy_poll, replay_send, or crawl_poll)")),
+		mcp.WithArray("locations", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("Response sections to search: body, headers (default: both)")),
+		mcp.WithNumber("limit", mcp.Description("Maximum reflections to return (0 = all)")),
+		mcp.WithNumber("min_length", mcp.Description("Minimum parameter value length to search for (default 4)")),
 	)
 }
 
@@ -83,6 +96,29 @@ func (m *mcpServer) handleFindReflected(ctx context.Context, req mcp.CallToolReq
 	if flowID == "" {
 		return errorResult("flow_id is required"), nil
 	}
+	limit := req.GetInt("limit", 0)
+	if limit < 0 {
+		return errorResult("limit must be non-negative"), nil
+	}
+	minLen := req.GetInt("min_length", defaultMinReflectionLen)
+	if minLen < 1 {
+		return errorResult("min_length must be at least 1"), nil
+	}
+
+	searchBody, searchHeaders := true, true
+	if locations := req.GetStringSlice("locations", nil); len(locations) > 0 {
+		searchBody, searchHeaders = false, false
+		for _, loc := range locations {
+			switch strings.ToLower(strings.TrimSpace(loc)) {
+			case "body":
+				searchBody = true
+			case "headers":
+				searchHeaders = true
+			default:
+				return errorResult(fmt.Sprintf("invalid location %q: use body or headers", loc)), nil
+			}
+		}
+	}
 
 	flow, errResult := m.resolveFlow(ctx, flowID)
 	if errResult != nil {
@@ -93,9 +129,23 @@ func (m *mcpServer) handleFindReflected(ctx context.Context, req mcp.CallToolReq
 
 	params := extractParams(flow.RawRequest)
 
-	return jsonResult(&protocol.FindReflectedResponse{
-		Reflections: findReflections(params, flow.RawResponse),
-	})
+	resp := &protocol.FindReflectedResponse{
+		Reflections: findReflections(params, flow.RawResponse, minLen, searchBody, searchHeaders),
+	}
+	if searchBody {
+		respHeaders, respBody := splitHeadersBody(flow.RawResponse)
+		respBody, _ = decompressForDisplay(respBody, string(respHeaders))
+		var contentType string
+		if vals := parseHeadersToMap(string(respHeaders))["Content-Type"]; len(vals) > 0 {
+			contentType = vals[0]
+		}
+		resp.JSONPCallback = detectJSONP(params, contentType, respBody)
+	}
+	if limit > 0 && len(resp.Reflections) > limit {
+		resp.Reflections = resp.Reflections[:limit]
+		resp.Truncated = true
+	}
+	return jsonResult(resp)
 }
 
 func leafToString(val interface{}) string {
@@ -219,6 +269,9 @@ func extractParams(rawReq []byte) []protocol.Reflection {
 	return params
 }
 
+// encodingNone labels a value reflected exactly as sent, with no encoding applied.
+const encodingNone = "none"
+
 // encodedVariant pairs an encoded string with its encoding label.
 type encodedVariant struct {
 	encoded  string
@@ -228,7 +281,7 @@ type encodedVariant struct {
 // encodingVariants generates encoded forms of a value for reflection matching.
 func encodingVariants(value string) []encodedVariant {
 	seen := map[string]bool{value: true}
-	variants := []encodedVariant{{encoded: value, encoding: "raw"}}
+	variants := []encodedVariant{{encoded: value, encoding: encodingNone}}
 	add := func(encoded, encoding string) {
 		if !seen[encoded] {
 			seen[encoded] = true
@@ -274,51 +327,84 @@ func encodingVariants(value string) []encodedVariant {
 	return variants
 }
 
-// findReflections checks each parameter value against the response body and headers.
-func findReflections(params []protocol.Reflection, rawResp []byte) []protocol.Reflection {
+// findReflections checks each parameter value of at least minLen bytes against the response body and/or headers.
+func findReflections(params []protocol.Reflection, rawResp []byte, minLen int, searchBody, searchHeaders bool) []protocol.Reflection {
 	respHeaders, respBody := splitHeadersBody(rawResp)
-	respBody, _ = decompressForDisplay(respBody, string(respHeaders))
-	respBodyStr := string(respBody)
 	respHeaderMap := parseHeadersToMap(string(respHeaders))
+	var respBodyStr string
+	if searchBody {
+		respBody, _ = decompressForDisplay(respBody, string(respHeaders))
+		respBodyStr = string(respBody)
+	}
 
 	// Content-Type-based default context for non-HTML responses
 	baseContext := inferBaseContext(respHeaderMap)
+	classify := classifyReflectionContext
+	if isXMLResponse(respHeaderMap) {
+		classify = classifyXMLReflectionContext
+	}
 
 	var reflections []protocol.Reflection
 	for _, p := range params {
-		if len(p.Value) < minReflectionValueLen {
+		if len(p.Value) < minLen {
 			continue
 		}
 
 		variants := encodingVariants(p.Value)
 
 		var locations []string
+		encodings := make(map[string]string) // location -> first (least encoded) matching variant
+		occurrences := make(map[string]int)
+		var total int
+		var capped bool       // some body occurrences were counted but not located
 		var rawBodyMatch bool // at least one raw (unencoded) body match
 
-		seen := make(map[string]bool)
-		for _, v := range variants {
-			idx := strings.Index(respBodyStr, v.encoded)
-			if idx >= 0 {
-				ctx := baseContext
-				if ctx == "" {
-					ctx = classifyReflectionContext(respBodyStr, idx)
-				}
-				loc := "body:" + ctx
-				if !seen[loc] {
-					seen[loc] = true
-					locations = append(locations, loc)
-				}
-				if v.encoding == "raw" {
-					rawBodyMatch = true
+		if searchBody {
+			for _, v := range variants {
+				for n, offset := 0, 0; ; n++ {
+					idx := strings.Index(respBodyStr[offset:], v.encoded)
+					if idx < 0 {
+						break
+					} else if n == maxClassifiedOccurrences {
+						total += strings.Count(respBodyStr[offset:], v.encoded)
+						capped = true
+						break
+					}
+					idx += offset
+					offset = idx + len(v.encoded)
+
+					ctx := baseContext
+					if ctx == "" {
+						ctx = classify(respBodyStr, idx)
+					}
+					loc := "body:" + ctx
+					if _, seen := encodings[loc]; !seen {
+						encodings[loc] = v.encoding
+						locations = append(locations, loc)
+					}
+					occurrences[loc]++
+					total++
+					if v.encoding == encodingNone {
+						rawBodyMatch = true
+					}
 				}
 			}
 		}
 
-		for headerName, headerVals := range respHeaderMap {
-			for _, hv := range headerVals {
-				if slices.ContainsFunc(variants, func(v encodedVariant) bool { return strings.Contains(hv, v.encoded) }) {
-					locations = append(locations, "header:"+headerName)
-					break
+		if searchHeaders {
+			for headerName, headerVals := range respHeaderMap {
+				loc := "header:" + headerName
+				for _, hv := range headerVals {
+					for _, v := range variants {
+						if n := strings.Count(hv, v.encoded); n > 0 {
+							if _, seen := encodings[loc]; !seen {
+								encodings[loc] = v.encoding
+								locations = append(locations, loc)
+							}
+							occurrences[loc] += n
+							total += n
+						}
+					}
 				}
 			}
 		}
@@ -326,6 +412,10 @@ func findReflections(params []protocol.Reflection, rawResp []byte) []protocol.Re
 		if len(locations) > 0 {
 			sort.Strings(locations)
 			p.Locations = locations
+			p.Encodings = encodings
+			p.Occurrences = occurrences
+			p.OccurrenceCount = total
+			p.OccurrencesCapped = capped
 			p.RawReflected = rawBodyMatch && strings.ContainsAny(p.Value, `<>&'"`)
 			reflections = append(reflections, p)
 		}
@@ -365,43 +455,90 @@ func inferBaseContext(respHeaderMap map[string][]string) string {
 	}
 }
 
-// classifyReflectionContext determines the HTML/JS/CSS context at a match position.
-func classifyReflectionContext(body string, matchStart int) string {
-	before := body[:matchStart]
+// isXMLResponse reports whether the response Content-Type is an XML document type,
+// including SVG (image/svg+xml).
+func isXMLResponse(respHeaderMap map[string][]string) bool {
+	vals := respHeaderMap["Content-Type"]
+	if len(vals) == 0 {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(vals[0])
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
 
-	// Check for HTML comment context
-	commentOpen := strings.LastIndex(before, "<!--")
-	if commentOpen >= 0 {
-		commentClose := strings.LastIndex(before[commentOpen:], "-->")
-		if commentClose < 0 {
-			return "html_comment"
-		}
+// insideSection reports whether an open marker appears in before without a later close marker.
+func insideSection(before, open, close string) bool {
+	openIdx := strings.LastIndex(before, open)
+	return openIdx >= 0 && !strings.Contains(before[openIdx:], close)
+}
+
+// insideSVG reports whether lower (lowercased) ends within an open <svg> element;
+// self-closing <svg/> elements have no content.
+func insideSVG(lower string) bool {
+	openIdx := strings.LastIndex(lower, "<svg")
+	if openIdx < 0 {
+		return false
+	}
+	rest := lower[openIdx:]
+	if end := strings.Index(rest, ">"); end > 0 && rest[end-1] == '/' {
+		return false
 	}
+	return !strings.Contains(rest, "</svg")
+}
 
-	// Check for <script> context
-	scriptOpen := strings.LastIndex(strings.ToLower(before), "<script")
-	if scriptOpen >= 0 {
-		scriptClose := strings.LastIndex(strings.ToLower(before[scriptOpen:]), "</script")
-		if scriptClose < 0 {
-			return "script"
+// insideTag reports whether before ends inside a tag (< more recent than >).
+func insideTag(before string) bool {
+	lastLT := strings.LastIndex(before, "<")
+	return lastLT >= 0 && lastLT > strings.LastIndex(before, ">")
+}
+
+// classifyXMLReflectionContext determines the context at a match position in an XML
+// document. SVG content (an SVG document or <svg> embedded in XML) is reported as svg.
+func classifyXMLReflectionContext(body string, matchStart int) string {
+	before := body[:matchStart]
+	lower := strings.ToLower(before)
+
+	switch {
+	case insideSection(before, "<!--", "-->"):
+		return "xml_comment"
+	case insideSVG(lower) && insideSection(lower, "<script", "</script"):
+		return "script" // SVG scripts commonly wrap their code in CDATA
+	case insideSection(before, "<![CDATA[", "]]>"):
+		return "xml_cdata"
+	case insideSVG(lower):
+		if insideTag(before) {
+			return "svg_attribute"
 		}
+		return "svg"
+	case insideTag(before):
+		return "xml_attribute"
+	default:
+		return "xml_text"
 	}
+}
 
-	// Check for <style> context
-	styleOpen := strings.LastIndex(strings.ToLower(before), "<style")
-	if styleOpen >= 0 {
-		styleClose := strings.LastIndex(strings.ToLower(before[styleOpen:]), "</style")
-		if styleClose < 0 {
-			return "css"
+// classifyReflectionContext determines the HTML/JS/CSS context at a match position.
+func classifyReflectionContext(body string, matchStart int) string {
+	before := body[:matchStart]
+	lower := strings.ToLower(before)
+
+	if insideSection(before, "<!--", "-->") {
+		return "html_comment"
+	} else if insideSection(lower, "<script", "</script") {
+		return "script"
+	} else if insideSection(lower, "<style", "</style") {
+		return "css"
+	} else if insideSVG(lower) {
+		// Inline SVG is parsed with foreign-content rules where escaping differs from HTML
+		if insideTag(before) {
+			return "svg_attribute"
 		}
+		return "svg"
 	}
 
-	// Check if inside a tag (< more recent than >)
-	lastLT := strings.LastIndex(before, "<")
-	lastGT := strings.LastIndex(before, ">")
-	if lastLT >= 0 && lastLT > lastGT {
+	if insideTag(before) {
 		// Inside a tag — check for URL attributes
-		tagContent := strings.ToLower(before[lastLT:])
+		tagContent := lower[strings.LastIndex(lower, "<"):]
 		for _, attr := range []string{"href=", "src=", "action=", "formaction="} {
 			if strings.Contains(tagContent, attr) {
 				return "url"
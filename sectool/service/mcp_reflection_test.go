@@ -73,11 +73,21 @@ func TestHandleFindReflected(t *testing.T) {
 		"",
 	)
 
+	// Entry 5: JSONP endpoint with a short callback parameter
+	mockMCP.AddProxyEntry(
+		"GET /api/user?cb=jq1&_=1700000000 HTTP/1.1\r\n"+
+			"Host: example.com\r\n\r\n",
+		"HTTP/1.1 200 OK\r\n"+
+			"Content-Type: text/javascript; charset=utf-8\r\n\r\n"+
+			`/**/ typeof jq1 === 'function' && jq1({"email":"user@example.com"});`,
+		"",
+	)
+
 	listResp := CallMCPToolJSONOK[protocol.ProxyPollResponse](t, mcpClient, "proxy_poll", map[string]interface{}{
 		"output_mode": "flows",
 		"limit":       10,
 	})
-	require.Len(t, listResp.Flows, 5)
+	require.Len(t, listResp.Flows, 6)
 
 	t.Run("query_cookie_header_reflection", func(t *testing.T) {
 		resp := CallMCPToolJSONOK[protocol.FindReflectedResponse](t, mcpClient, "find_reflected", map[string]interface{}{
@@ -184,6 +194,109 @@ func TestHandleFindReflected(t *testing.T) {
 		assert.Contains(t, callbackRef.Locations, "body:script")
 		// Value has <> but only encoded variant matched, not raw
 		assert.False(t, callbackRef.RawReflected)
+		// The called function is not the parameter value as sent
+		assert.Empty(t, resp.JSONPCallback)
+	})
+
+	t.Run("jsonp", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.FindReflectedResponse](t, mcpClient, "find_reflected", map[string]interface{}{
+			"flow_id": listResp.Flows[5].FlowID,
+		})
+
+		assert.Equal(t, "cb", resp.JSONPCallback)
+		// The callback value is below the reflection length threshold
+		assert.Empty(t, resp.Reflections)
+	})
+
+	t.Run("headers_only", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.FindReflectedResponse](t, mcpClient, "find_reflected", map[string]interface{}{
+			"flow_id":   listResp.Flows[0].FlowID,
+			"locations": []string{"headers"},
+		})
+
+		// q is only reflected in the body
+		assert.Nil(t, findReflectionByName(resp.Reflections, "q"))
+
+		redirectRef := findReflectionByName(resp.Reflections, "redirect")
+		require.NotNil(t, redirectRef)
+		assert.Equal(t, []string{"header:Location"}, redirectRef.Locations)
+	})
+
+	t.Run("body_only", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.FindReflectedResponse](t, mcpClient, "find_reflected", map[string]interface{}{
+			"flow_id":   listResp.Flows[0].FlowID,
+			"locations": []string{"body"},
+		})
+
+		assert.NotNil(t, findReflectionByName(resp.Reflections, "q"))
+		assert.Nil(t, findReflectionByName(resp.Reflections, "redirect"))
+	})
+
+	t.Run("limit_truncates_sorted", func(t *testing.T) {
+		full := CallMCPToolJSONOK[protocol.FindReflectedResponse](t, mcpClient, "find_reflected", map[string]interface{}{
+			"flow_id": listResp.Flows[1].FlowID,
+		})
+		require.Greater(t, len(full.Reflections), 2)
+		assert.False(t, full.Truncated)
+
+		resp := CallMCPToolJSONOK[protocol.FindReflectedResponse](t, mcpClient, "find_reflected", map[string]interface{}{
+			"flow_id": listResp.Flows[1].FlowID,
+			"limit":   2,
+		})
+		assert.True(t, resp.Truncated)
+		assert.Equal(t, full.Reflections[:2], resp.Reflections)
+	})
+
+	t.Run("limit_not_reached", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.FindReflectedResponse](t, mcpClient, "find_reflected", map[string]interface{}{
+			"flow_id": listResp.Flows[3].FlowID,
+			"limit":   10,
+		})
+		assert.NotEmpty(t, resp.Reflections)
+		assert.False(t, resp.Truncated)
+	})
+
+	t.Run("negative_limit", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "find_reflected", map[string]interface{}{
+			"flow_id": listResp.Flows[0].FlowID,
+			"limit":   -1,
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "limit must be non-negative")
+	})
+
+	t.Run("min_length", func(t *testing.T) {
+		// The 3-char JSONP callback value is skipped at the default length
+		resp := CallMCPToolJSONOK[protocol.FindReflectedResponse](t, mcpClient, "find_reflected", map[string]interface{}{
+			"flow_id": listResp.Flows[5].FlowID,
+		})
+		assert.Nil(t, findReflectionByName(resp.Reflections, "cb"))
+
+		resp = CallMCPToolJSONOK[protocol.FindReflectedResponse](t, mcpClient, "find_reflected", map[string]interface{}{
+			"flow_id":    listResp.Flows[5].FlowID,
+			"min_length": 2,
+		})
+		cbRef := findReflectionByName(resp.Reflections, "cb")
+		require.NotNil(t, cbRef)
+		assert.Equal(t, "jq1", cbRef.Value)
+	})
+
+	t.Run("invalid_min_length", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "find_reflected", map[string]interface{}{
+			"flow_id":    listResp.Flows[0].FlowID,
+			"min_length": 0,
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "min_length must be at least 1")
+	})
+
+	t.Run("invalid_location", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "find_reflected", map[string]interface{}{
+			"flow_id":   listResp.Flows[0].FlowID,
+			"locations": []string{"cookies"},
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid location")
 	})
 
 	t.Run("missing_flow_id", func(t *testing.T) {
@@ -384,10 +497,11 @@ func TestFindReflections(t *testing.T) {
 		params := []protocol.Reflection{{Name: "q", Source: "query", Value: "hello world"}}
 		resp := []byte("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<p>hello world</p>")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Equal(t, "q", reflections[0].Name)
 		assert.Contains(t, reflections[0].Locations, "body:html_text")
+		assert.Equal(t, encodingNone, reflections[0].Encodings["body:html_text"])
 	})
 
 	t.Run("html_encoded_match", func(t *testing.T) {
@@ -395,35 +509,128 @@ func TestFindReflections(t *testing.T) {
 		resp := []byte("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n" +
 			"<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "body:html_text")
+		assert.Equal(t, "html_entity", reflections[0].Encodings["body:html_text"])
 		assert.False(t, reflections[0].RawReflected)
 	})
 
+	t.Run("xml_response", func(t *testing.T) {
+		params := []protocol.Reflection{{Name: "q", Source: "query", Value: "hello world"}}
+		resp := []byte("HTTP/1.1 200 OK\r\nContent-Type: application/xml\r\n\r\n" +
+			`<result query="hello world"><item>hello world</item></result>`)
+
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, false)
+		require.Len(t, reflections, 1)
+		assert.Equal(t, []string{"body:xml_attribute", "body:xml_text"}, reflections[0].Locations)
+	})
+
+	t.Run("occurrence_counts", func(t *testing.T) {
+		params := []protocol.Reflection{{Name: "q", Source: "query", Value: "needle"}}
+		resp := []byte("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nX-Echo: needle, needle\r\n\r\n" +
+			`<meta name="q" content="needle"><p>needle and needle</p><script>var q = "needle";</script>`)
+
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
+		require.Len(t, reflections, 1)
+		assert.Equal(t, map[string]int{
+			"body:html_attribute": 1,
+			"body:html_text":      2,
+			"body:script":         1,
+			"header:X-Echo":       2,
+		}, reflections[0].Occurrences)
+		assert.Equal(t, 6, reflections[0].OccurrenceCount)
+		assert.False(t, reflections[0].OccurrencesCapped)
+	})
+
+	t.Run("occurrence_counts_mixed_encodings", func(t *testing.T) {
+		params := []protocol.Reflection{{Name: "q", Source: "query", Value: "a<b>c"}}
+		resp := []byte("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n" +
+			"<p>a&lt;b&gt;c a<b>c</p>")
+
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, false)
+		require.Len(t, reflections, 1)
+		assert.Equal(t, map[string]int{"body:html_text": 2}, reflections[0].Occurrences)
+		assert.Equal(t, encodingNone, reflections[0].Encodings["body:html_text"])
+	})
+
+	t.Run("occurrence_classification_cap", func(t *testing.T) {
+		params := []protocol.Reflection{{Name: "q", Source: "query", Value: "needle"}}
+		resp := []byte("HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\n" +
+			strings.Repeat("needle ", maxClassifiedOccurrences+5))
+
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, false)
+		require.Len(t, reflections, 1)
+		assert.Equal(t, maxClassifiedOccurrences+5, reflections[0].OccurrenceCount)
+		assert.Equal(t, maxClassifiedOccurrences, reflections[0].Occurrences[reflections[0].Locations[0]])
+		assert.True(t, reflections[0].OccurrencesCapped)
+	})
+
 	t.Run("url_encoded_match", func(t *testing.T) {
 		params := []protocol.Reflection{{Name: "path", Source: "query", Value: "/foo bar/baz"}}
 		resp := []byte("HTTP/1.1 200 OK\r\n\r\nRedirect to %2Ffoo+bar%2Fbaz")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "body:html_text")
+		assert.Equal(t, "url_query", reflections[0].Encodings["body:html_text"])
 	})
 
 	t.Run("js_unicode_match", func(t *testing.T) {
 		params := []protocol.Reflection{{Name: "cb", Source: "query", Value: "test<img>"}}
 		resp := []byte("HTTP/1.1 200 OK\r\n\r\ntest\\u003cimg\\u003e({\"data\":1})")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "body:html_text")
+		assert.Equal(t, "js_unicode", reflections[0].Encodings["body:html_text"])
+	})
+
+	t.Run("encoding_per_location", func(t *testing.T) {
+		params := []protocol.Reflection{{Name: "q", Source: "query", Value: "a<b>c"}}
+		resp := []byte("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nX-Echo: a%3Cb%3Ec\r\n\r\n" +
+			"<p>a&lt;b&gt;c</p><script>var q = 'a<b>c';</script>")
+
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
+		require.Len(t, reflections, 1)
+		assert.Equal(t, map[string]string{
+			"body:script":    encodingNone,
+			"body:html_text": "html_entity",
+			"header:X-Echo":  "url_query",
+		}, reflections[0].Encodings)
+		assert.True(t, reflections[0].RawReflected)
+	})
+
+	t.Run("encoding_labels", func(t *testing.T) {
+		tests := []struct {
+			value    string
+			body     string
+			encoding string
+		}{
+			{value: "foo bar", body: "foo bar", encoding: encodingNone},
+			{value: "foo bar", body: "foo+bar", encoding: "url_query"},
+			{value: "foo bar", body: "foo%20bar", encoding: "url_path"},
+			{value: "a<b>c", body: "a&lt;b&gt;c", encoding: "html_entity"},
+			{value: "a<b>c", body: "a&#60;b&#62;c", encoding: "html_decimal"},
+			{value: "a<b>c", body: "a&#x3c;b&#x3e;c", encoding: "html_hex"},
+			{value: "a<b>c", body: `a\u003cb\u003ec`, encoding: "js_unicode"},
+			{value: "a<b>c", body: `a\x3cb\x3ec`, encoding: "js_hex"},
+		}
+		for _, tt := range tests {
+			params := []protocol.Reflection{{Name: "q", Source: "query", Value: tt.value}}
+			resp := []byte("HTTP/1.1 200 OK\r\n\r\n" + tt.body)
+
+			reflections := findReflections(params, resp, defaultMinReflectionLen, true, false)
+			require.Len(t, reflections, 1, tt.encoding)
+			assert.Equal(t, map[string]string{"body:html_text": tt.encoding}, reflections[0].Encodings, tt.body)
+		}
 	})
 
 	t.Run("js_unicode_uppercase_match", func(t *testing.T) {
 		params := []protocol.Reflection{{Name: "cb", Source: "query", Value: "test<img>"}}
 		resp := []byte("HTTP/1.1 200 OK\r\n\r\ntest\\u003Cimg\\u003E({\"data\":1})")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "body:html_text")
 	})
@@ -432,7 +639,7 @@ func TestFindReflections(t *testing.T) {
 		params := []protocol.Reflection{{Name: "cb", Source: "query", Value: "test<img>"}}
 		resp := []byte("HTTP/1.1 200 OK\r\n\r\ntest\\x3cimg\\x3e({\"data\":1})")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "body:html_text")
 	})
@@ -441,7 +648,7 @@ func TestFindReflections(t *testing.T) {
 		params := []protocol.Reflection{{Name: "q", Source: "query", Value: "<b>test</b>"}}
 		resp := []byte("HTTP/1.1 200 OK\r\n\r\n&#60;b&#62;test&#60;&#47;b&#62;")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "body:html_text")
 	})
@@ -450,7 +657,7 @@ func TestFindReflections(t *testing.T) {
 		params := []protocol.Reflection{{Name: "q", Source: "query", Value: "<b>test</b>"}}
 		resp := []byte("HTTP/1.1 200 OK\r\n\r\n&#x3c;b&#x3e;test&#x3c;&#x2f;b&#x3e;")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "body:html_text")
 	})
@@ -459,7 +666,7 @@ func TestFindReflections(t *testing.T) {
 		params := []protocol.Reflection{{Name: "redirect", Source: "query", Value: "https://evil.com"}}
 		resp := []byte("HTTP/1.1 302 Found\r\nLocation: https://evil.com\r\n\r\n")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "header:Location")
 	})
@@ -469,7 +676,7 @@ func TestFindReflections(t *testing.T) {
 		params := []protocol.Reflection{{Name: "next", Source: "query", Value: "/foo bar"}}
 		resp := []byte("HTTP/1.1 302 Found\r\nLocation: /redir?next=%2Ffoo%20bar\r\n\r\n")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "header:Location")
 	})
@@ -478,7 +685,7 @@ func TestFindReflections(t *testing.T) {
 		params := []protocol.Reflection{{Name: "val", Source: "query", Value: "reflected_value"}}
 		resp := []byte("HTTP/1.1 200 OK\r\nX-Echo: reflected_value\r\n\r\nBody: reflected_value")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "body:html_text")
 		assert.Contains(t, reflections[0].Locations, "header:X-Echo")
@@ -491,7 +698,7 @@ func TestFindReflections(t *testing.T) {
 			"<script>var x = '<img src=x>';</script>" +
 			"<p>&lt;img src=x&gt;</p>")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "body:script")
 		assert.Contains(t, reflections[0].Locations, "body:html_text")
@@ -506,17 +713,30 @@ func TestFindReflections(t *testing.T) {
 		}
 		resp := []byte("HTTP/1.1 200 OK\r\n\r\nab abc abcd")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Equal(t, "c", reflections[0].Name)
 	})
 
+	t.Run("min_length_lowered", func(t *testing.T) {
+		params := []protocol.Reflection{
+			{Name: "a", Source: "query", Value: "ab"},
+			{Name: "b", Source: "query", Value: "abc"},
+		}
+		resp := []byte("HTTP/1.1 200 OK\r\n\r\nab abc")
+
+		reflections := findReflections(params, resp, 2, true, true)
+		require.Len(t, reflections, 2)
+		assert.Equal(t, "a", reflections[0].Name)
+		assert.Equal(t, "b", reflections[1].Name)
+	})
+
 	t.Run("raw_reflected_xss", func(t *testing.T) {
 		params := []protocol.Reflection{{Name: "q", Source: "query", Value: "<script>alert(1)</script>"}}
 		resp := []byte("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n" +
 			"<p><script>alert(1)</script></p>")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.True(t, reflections[0].RawReflected)
 	})
@@ -526,7 +746,7 @@ func TestFindReflections(t *testing.T) {
 		params := []protocol.Reflection{{Name: "q", Source: "query", Value: "admin"}}
 		resp := []byte("HTTP/1.1 200 OK\r\n\r\nWelcome admin")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.False(t, reflections[0].RawReflected)
 	})
@@ -535,7 +755,7 @@ func TestFindReflections(t *testing.T) {
 		params := []protocol.Reflection{{Name: "cb", Source: "query", Value: "myCallback"}}
 		resp := []byte("HTTP/1.1 200 OK\r\nContent-Type: application/javascript\r\n\r\nmyCallback({\"data\":1})")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "body:script")
 	})
@@ -544,7 +764,7 @@ func TestFindReflections(t *testing.T) {
 		params := []protocol.Reflection{{Name: "q", Source: "query", Value: "injected"}}
 		resp := []byte("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n{\"result\":\"injected\"}")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 1)
 		assert.Contains(t, reflections[0].Locations, "body:json")
 	})
@@ -553,7 +773,7 @@ func TestFindReflections(t *testing.T) {
 		params := []protocol.Reflection{{Name: "q", Source: "query", Value: "not-in-response"}}
 		resp := []byte("HTTP/1.1 200 OK\r\n\r\nsomething else entirely")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		assert.Empty(t, reflections)
 	})
 
@@ -565,7 +785,7 @@ func TestFindReflections(t *testing.T) {
 		}
 		resp := []byte("HTTP/1.1 200 OK\r\n\r\ntest_value")
 
-		reflections := findReflections(params, resp)
+		reflections := findReflections(params, resp, defaultMinReflectionLen, true, true)
 		require.Len(t, reflections, 3)
 		// Sorted by source then name: cookie < query, and a_param < z_param
 		assert.Equal(t, "cookie", reflections[0].Source)
@@ -634,6 +854,26 @@ func TestClassifyReflectionContext(t *testing.T) {
 			body: `<script>var a=1;</script><p>MATCH</p>`,
 			want: "html_text",
 		},
+		{
+			name: "inline_svg",
+			body: `<svg><text>MATCH</text></svg>`,
+			want: "svg",
+		},
+		{
+			name: "inline_svg_attribute",
+			body: `<svg><rect fill="MATCH"/></svg>`,
+			want: "svg_attribute",
+		},
+		{
+			name: "svg_script",
+			body: `<svg><script>var x = "MATCH";</script></svg>`,
+			want: "script",
+		},
+		{
+			name: "closed_svg",
+			body: `<svg><rect/></svg><p>MATCH</p>`,
+			want: "html_text",
+		},
 	}
 
 	for _, tt := range tests {
@@ -648,20 +888,47 @@ func TestClassifyReflectionContext(t *testing.T) {
 	}
 }
 
+func TestClassifyXMLReflectionContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"xml_text", `<?xml version="1.0"?><user><name>MATCH</name></user>`, "xml_text"},
+		{"xml_attribute", `<user id="MATCH"/>`, "xml_attribute"},
+		{"xml_comment", `<user><!-- MATCH --></user>`, "xml_comment"},
+		{"xml_cdata", `<user><![CDATA[MATCH]]></user>`, "xml_cdata"},
+		{"svg_document", `<svg xmlns="http://www.w3.org/2000/svg"><text>MATCH</text></svg>`, "svg"},
+		{"svg_attribute", `<svg xmlns="http://www.w3.org/2000/svg"><a href="MATCH"/></svg>`, "svg_attribute"},
+		{"svg_cdata_script", `<svg><script><![CDATA[var x = "MATCH";]]></script></svg>`, "script"},
+		{"after_svg", `<doc><svg/></doc><doc>MATCH</doc>`, "xml_text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := strings.Index(tt.body, "MATCH")
+			require.GreaterOrEqual(t, idx, 0)
+			assert.Equal(t, tt.want, classifyXMLReflectionContext(tt.body, idx))
+		})
+	}
+}
+
 func TestEncodingVariants(t *testing.T) {
 	t.Parallel()
 
 	t.Run("plain_value", func(t *testing.T) {
 		variants := encodingVariants("hello")
-		// Should have raw + url_query + url_path + html_entity (most deduplicate for plain values)
+		// Should have none + url_query + url_path + html_entity (most deduplicate for plain values)
 		labels := make([]string, 0, len(variants))
 		for _, v := range variants {
 			labels = append(labels, v.encoding)
 		}
-		assert.Contains(t, labels, "raw")
+		assert.Contains(t, labels, encodingNone)
 		// Verify the raw value is correct
 		for _, v := range variants {
-			if v.encoding == "raw" {
+			if v.encoding == encodingNone {
 				assert.Equal(t, "hello", v.encoded)
 			}
 		}
@@ -673,7 +940,7 @@ func TestEncodingVariants(t *testing.T) {
 		for _, v := range variants {
 			labelSet[v.encoding] = true
 		}
-		assert.True(t, labelSet["raw"])
+		assert.True(t, labelSet[encodingNone])
 		assert.True(t, labelSet["html_entity"])
 		assert.True(t, labelSet["url_query"])
 		assert.True(t, labelSet["js_unicode"])
@@ -682,10 +949,10 @@ func TestEncodingVariants(t *testing.T) {
 		assert.True(t, labelSet["html_hex"])
 	})
 
-	t.Run("raw_is_first", func(t *testing.T) {
+	t.Run("none_is_first", func(t *testing.T) {
 		variants := encodingVariants("test<value>")
 		require.NotEmpty(t, variants)
-		assert.Equal(t, "raw", variants[0].encoding)
+		assert.Equal(t, encodingNone, variants[0].encoding)
 		assert.Equal(t, "test<value>", variants[0].encoded)
 	})
 }
@@ -3,14 +3,18 @@ package service
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"log"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
 	"github.com/go-appsec/toolbox/sectool/config"
 	"github.com/go-appsec/toolbox/sectool/protocol"
 	"github.com/go-appsec/toolbox/sectool/service/ids"
+	"github.com/go-appsec/toolbox/sectool/service/proxy"
 	"github.com/go-appsec/toolbox/sectool/service/store"
 )
 
@@ -48,43 +52,70 @@ Replayed requests appear in proxy_poll history alongside captured traffic.`),
 		mcp.WithObject("set_json", mcp.Description("JSON fields to set as object: {\"path\": value} (e.g., {\"user.email\": \"x\", \"items[0].id\": 5})")),
 		mcp.WithArray("remove_json", mcp.Items(map[string]interface{}{"type": "string"}), mcp.Description("JSON fields to remove (dot path: 'user.temp', 'items[2]')")),
 		mcp.WithBoolean("follow_redirects", mcp.Description("Follow HTTP redirects (default: false)")),
+		mcp.WithString("timeout", mcp.Description("Abort the request after this duration (e.g., 5s); default: the configured proxy timeouts")),
 		mcp.WithBoolean("force", mcp.Description("Skip validation for protocol-level tests (smuggling, CRLF injection)")),
+		mcp.WithObject("resolve", mcp.Description("Pin hostnames to IPs like curl --resolve: {\"host\": \"ip\"} or [\"host=ip\"]. Host header and SNI keep the original name")),
 	)
 }
 
 func (m *mcpServer) replayGetTool() mcp.Tool {
 	return mcp.NewTool("replay_get",
-		mcp.WithDescription(`Retrieve full response from a previous replay_send.
+		mcp.WithDescription(`Retrieve a previous replay_send or request_send: the request as sent, the full response, and its timing.
 
-Returns headers and body. Binary bodies are returned as "<BINARY:N Bytes>" placeholder.
+Returns request and response headers and bodies, source_flow_id (the replayed flow, empty for request_send), and redirects (each followed hop with status, url and location) when follow_redirects was used. Binary bodies are returned as "<BINARY:N Bytes>" placeholder.
 Results are ephemeral and cleared on service restart.`),
 		mcp.WithString("replay_id", mcp.Required(), mcp.Description("Replay ID from replay_send response")),
 	)
 }
 
+func (m *mcpServer) fetchTool() mcp.Tool {
+	return mcp.NewTool("fetch",
+		mcp.WithDescription(`Fetch a single URL and store it as a flow, without a crawl session.
+
+A minimal request_send: returns only flow_id, status, content_type, size and duration. The flow_id works with proxy_get, replay_get, replay_send, diff_flow, find_reflected and security_headers, and the exchange appears in proxy_poll history. Target domains are subject to scope rules.`),
+		mcp.WithString("url", mcp.Required(), mcp.Description("Target URL (e.g., 'https://example.com/page')")),
+		mcp.WithString("method", mcp.Description("HTTP method (default: GET)")),
+		mcp.WithObject("headers", mcp.Description("Headers as object: {\"Name\": \"Value\"}")),
+		mcp.WithString("body", mcp.Description("Request body content")),
+	)
+}
+
 func (m *mcpServer) requestSendTool() mcp.Tool {
 	return mcp.NewTool("request_send",
 		mcp.WithDescription(`Send a request from scratch (no captured flow required).
 
 Use this when you need to send a request to a URL without first capturing it via proxy.
 Returns: replay_id, status, headers, response_preview. Full body via replay_get.
-Sent requests appear in proxy_poll history alongside captured traffic.`),
+Sent requests appear in proxy_poll history alongside captured traffic, and the replay_id
+works as a flow_id for proxy_get, diff_flow and find_reflected. Target domains are subject to scope rules.`),
 		mcp.WithString("url", mcp.Required(), mcp.Description("Target URL (e.g., 'https://api.example.com/users')")),
 		mcp.WithString("method", mcp.Description("HTTP method (default: GET)")),
 		mcp.WithObject("headers", mcp.Description("Headers as object: {\"Name\": \"Value\"}")),
 		mcp.WithString("body", mcp.Description("Request body content")),
 		mcp.WithBoolean("follow_redirects", mcp.Description("Follow HTTP redirects (default: false)")),
+		mcp.WithString("timeout", mcp.Description("Abort the request after this duration (e.g., 5s); default: the configured proxy timeouts")),
+		mcp.WithObject("resolve", mcp.Description("Pin hostnames to IPs like curl --resolve: {\"host\": \"ip\"} or [\"host=ip\"]. Host header and SNI keep the original name")),
 	)
 }
 func (m *mcpServer) handleReplaySend(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if err := m.requireWorkflow(); err != nil {
 		return err, nil
 	}
+	ctx, done, err := m.service.activityContext(ctx)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	defer done()
 
 	flowID := req.GetString("flow_id", "")
 	if flowID == "" {
 		return errorResult("flow_id is required"), nil
 	}
+	ctx, cancel, err := withRequestTimeout(ctx, req)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	defer cancel()
 
 	// Try replay history, then proxy index, then crawler backend
 	var rawRequest []byte
@@ -121,10 +152,17 @@ func (m *mcpServer) handleReplaySend(ctx context.Context, req mcp.CallToolReques
 
 	// Parse add_headers flexibly: array ["Name: Value"] or object {"Name":"Value"}
 	var addHeaders []string
+	var hostResolution map[string]string
 	if args := req.GetArguments(); args != nil {
 		if raw, ok := args["add_headers"]; ok && raw != nil {
 			addHeaders = parseHeaderArg(raw)
 		}
+		if raw, ok := args["resolve"]; ok && raw != nil {
+			var err error
+			if hostResolution, err = parseHostResolutionArg(raw); err != nil {
+				return errorResult(err.Error()), nil
+			}
+		}
 	}
 
 	sendReq := &ReplaySendRequest{
@@ -226,11 +264,12 @@ func (m *mcpServer) handleReplaySend(ctx context.Context, req mcp.CallToolReques
 		FollowRedirects: req.GetBool("follow_redirects", false),
 		Force:           req.GetBool("force", false),
 		Protocol:        httpProtocol,
+		HostResolution:  hostResolution,
 	}
 
 	result, err := m.service.httpBackend.SendRequest(ctx, "sectool-"+replayID, sendInput)
 	if err != nil {
-		return errorResultFromErr("request failed: ", err), nil
+		return m.requestErrorResult(err), nil
 	}
 
 	respHeaders := result.Headers
@@ -253,6 +292,7 @@ func (m *mcpServer) handleReplaySend(ctx context.Context, req mcp.CallToolReques
 		RespBody:        respBody,
 		RespStatus:      respCode,
 		Duration:        result.Duration,
+		Redirects:       replayRedirects(result.Redirects),
 		SourceFlowID:    flowID,
 	})
 
@@ -285,25 +325,39 @@ func (m *mcpServer) handleReplayGet(ctx context.Context, req mcp.CallToolRequest
 	log.Printf("mcp/replay_get: retrieving %s", replayID)
 	result, ok := m.service.replayHistoryStore.Get(replayID)
 	if !ok {
-		return errorResult("replay not found: replay results are ephemeral and cleared on service restart"), nil
+		return errorResult("replay " + replayID + " " + ErrNotFound.Error() + ": replay results are ephemeral and cleared on service restart"), nil
 	}
 
+	reqHeaders, reqBody := splitHeadersBody(result.RawRequest)
+
 	respCode, respStatusLine := parseResponseStatus(result.RespHeaders)
 
 	// Decompress response for display (gzip/deflate) - applies to both modes
 	displayBody, _ := decompressForDisplay(result.RespBody, string(result.RespHeaders))
 
-	// Format body based on full_body flag
-	var respBodyStr string
+	// Format bodies based on full_body flag
+	var reqBodyStr, respBodyStr string
 	if fullBody { // Full body mode: base64-encode the decompressed content
+		reqBodyStr = base64.StdEncoding.EncodeToString(reqBody)
 		respBodyStr = base64.StdEncoding.EncodeToString(displayBody)
 	} else { // Preview mode: truncated text preview
+		reqBodyStr = previewBody(reqBody, fullBodyMaxSize)
 		respBodyStr = previewBody(displayBody, fullBodyMaxSize)
 	}
 
+	redirects := make([]protocol.ReplayRedirect, 0, len(result.Redirects))
+	for _, r := range result.Redirects {
+		redirects = append(redirects, protocol.ReplayRedirect{Status: r.Status, URL: r.URL, Location: r.Location})
+	}
+
 	return jsonResult(protocol.ReplayGetResponse{
 		ReplayID:          replayID,
+		SourceFlowID:      result.SourceFlowID,
 		Duration:          result.Duration.String(),
+		ReqHeaders:        string(reqHeaders),
+		ReqBody:           reqBodyStr,
+		ReqSize:           len(reqBody),
+		Redirects:         redirects,
 		Status:            respCode,
 		StatusLine:        respStatusLine,
 		RespHeaders:       string(result.RespHeaders),
@@ -317,105 +371,202 @@ func (m *mcpServer) handleRequestSend(ctx context.Context, req mcp.CallToolReque
 	if err := m.requireWorkflow(); err != nil {
 		return err, nil
 	}
+	ctx, done, err := m.service.activityContext(ctx)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	defer done()
 
-	urlStr := req.GetString("url", "")
-	if urlStr == "" {
+	if req.GetString("url", "") == "" {
 		return errorResult("url is required"), nil
 	}
+	ctx, cancel, err := withRequestTimeout(ctx, req)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	defer cancel()
+
+	newReq, errResult := m.parseNewRequest(req)
+	if errResult != nil {
+		return errResult, nil
+	}
+	newReq.followRedirects = req.GetBool("follow_redirects", false)
+	if raw, ok := req.GetArguments()["resolve"]; ok && raw != nil {
+		if newReq.hostResolution, err = parseHostResolutionArg(raw); err != nil {
+			return errorResult(err.Error()), nil
+		}
+	}
 
-	method := req.GetString("method", "GET")
+	entry, result, errResult := m.sendNewRequest(ctx, "request_send", newReq)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	_, respStatusLine := parseResponseStatus(result.Headers)
+	return jsonResult(protocol.ReplaySendResponse{
+		ReplayID: entry.FlowID,
+		Duration: result.Duration.String(),
+		ResponseDetails: protocol.ResponseDetails{
+			Status:      entry.RespStatus,
+			StatusLine:  respStatusLine,
+			RespHeaders: string(result.Headers),
+			RespSize:    len(result.Body),
+			RespPreview: previewBody(result.Body, responsePreviewSize),
+		},
+	})
+}
+
+func (m *mcpServer) handleFetch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+	ctx, done, err := m.service.activityContext(ctx)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	defer done()
+
+	if req.GetString("url", "") == "" {
+		return errorResult("url is required"), nil
+	}
+	newReq, errResult := m.parseNewRequest(req)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	entry, result, errResult := m.sendNewRequest(ctx, "fetch", newReq)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	return jsonResult(protocol.FetchResponse{
+		FlowID:      entry.FlowID,
+		Status:      entry.RespStatus,
+		ContentType: extractHeader(string(result.Headers), "Content-Type"),
+		Size:        len(result.Body),
+		Duration:    result.Duration.String(),
+	})
+}
+
+// newRequest is a request built from scratch by request_send or fetch.
+type newRequest struct {
+	method          string
+	url             *url.URL
+	headers         map[string]string
+	body            []byte
+	followRedirects bool
+	hostResolution  map[string]string
+}
+
+// parseNewRequest reads the url, method, headers and body arguments shared by request_send
+// and fetch, and rejects targets outside the configured domain scope.
+func (m *mcpServer) parseNewRequest(req mcp.CallToolRequest) (*newRequest, *mcp.CallToolResult) {
+	r := &newRequest{method: req.GetString("method", "GET")}
 
 	// Parse headers from object {"Name":"Value"} or array ["Name: Value"]
-	var headers map[string]string
-	if args := req.GetArguments(); args != nil {
-		if headersRaw, ok := args["headers"]; ok && headersRaw != nil {
-			headerSlice := parseHeaderArg(headersRaw)
-			if len(headerSlice) > 0 {
-				headers = make(map[string]string, len(headerSlice))
-				for _, h := range headerSlice {
-					if parts := strings.SplitN(h, ":", 2); len(parts) == 2 {
-						headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
-					}
-				}
-			}
-		}
+	if headersRaw, ok := req.GetArguments()["headers"]; ok && headersRaw != nil {
+		r.headers = headerArgToMap(headersRaw)
 	}
 
-	body := []byte(req.GetString("body", ""))
+	r.body = []byte(req.GetString("body", ""))
 
 	// If Content-Encoding header is present, compress the body
 	// This handles the case where user exported a decompressed request
 	// (e.g., from proxy_get) and is sending it back with the original encoding
-	if len(body) > 0 {
-		for k, v := range headers {
+	if len(r.body) > 0 {
+		for k, v := range r.headers {
 			if strings.EqualFold(k, "Content-Encoding") {
 				var compressionFailed bool
-				body, compressionFailed = compressBody(body, v)
+				r.body, compressionFailed = compressBody(r.body, v)
 				if compressionFailed {
-					delete(headers, k)
+					delete(r.headers, k)
 				}
 				break
 			}
 		}
 	}
 
-	parsedURL, err := parseURLWithDefaultHTTPS(urlStr)
+	parsedURL, err := parseURLWithDefaultHTTPS(req.GetString("url", ""))
 	if err != nil {
-		return errorResult("invalid URL: " + err.Error()), nil
+		return nil, errorResult("invalid URL: " + err.Error())
 	}
+	r.url = parsedURL
 
 	// Check domain scoping
 	if allowed, reason := m.service.cfg.IsDomainAllowed(parsedURL.Hostname()); !allowed {
-		return errorResult("domain rejected: " + reason), nil
+		return nil, errorResult("domain rejected: " + reason)
 	}
+	return r, nil
+}
 
-	rawRequest := buildRawRequest(method, parsedURL, headers, body)
+// sendNewRequest sends a request built from scratch and stores the exchange in replay history,
+// where proxy_poll, proxy_get, diff_flow and find_reflected can reach it by its flow ID.
+func (m *mcpServer) sendNewRequest(ctx context.Context, tool string, r *newRequest) (*store.ReplayHistoryEntry, *SendRequestResult, *mcp.CallToolResult) {
+	rawRequest := buildRawRequest(r.method, r.url, r.headers, r.body)
 	if rawRequest == nil {
-		return errorResult("failed to build request: invalid method or URL"), nil
+		return nil, nil, errorResult("failed to build request: invalid method or URL")
 	}
-	target := targetFromURL(parsedURL)
+	target := targetFromURL(r.url)
 	replayID := ids.Generate(ids.DefaultLength)
 
 	sendInput := SendRequestInput{
 		RawRequest:      rawRequest,
 		Target:          target,
-		FollowRedirects: req.GetBool("follow_redirects", false),
+		FollowRedirects: r.followRedirects,
+		HostResolution:  r.hostResolution,
 	}
 
 	result, err := m.service.httpBackend.SendRequest(ctx, "sectool-"+replayID, sendInput)
 	if err != nil {
-		return errorResultFromErr("request failed: ", err), nil
+		return nil, nil, m.requestErrorResult(err)
 	}
 
-	respCode, respStatusLine := parseResponseStatus(result.Headers)
-	log.Printf("mcp/request_send: %s %s status=%d size=%d duration=%v", replayID, parsedURL, respCode, len(result.Body), result.Duration)
+	respCode, _ := parseResponseStatus(result.Headers)
+	log.Printf("mcp/%s: %s %s status=%d size=%d duration=%v", tool, replayID, r.url, respCode, len(result.Body), result.Duration)
 
 	// Store in replay history for proxy_poll visibility
 	refOffset, _ := m.service.replayHistoryStore.UpdateReferenceOffset(m.service.proxyLastOffset.Load())
-	m.service.replayHistoryStore.Store(&store.ReplayHistoryEntry{
+	entry := &store.ReplayHistoryEntry{
 		FlowID:          replayID,
 		ReferenceOffset: refOffset,
 		RawRequest:      rawRequest,
-		Method:          method,
+		Method:          r.method,
 		Host:            target.Hostname,
-		Path:            parsedURL.Path,
+		Path:            r.url.Path,
 		Protocol:        "http/1.1",
 		RespHeaders:     result.Headers,
 		RespBody:        result.Body,
 		RespStatus:      respCode,
 		Duration:        result.Duration,
-		SourceFlowID:    "", // No source for request_send
-	})
+		Redirects:       replayRedirects(result.Redirects),
+		SourceFlowID:    "", // No source for requests built from scratch
+	}
+	m.service.replayHistoryStore.Store(entry)
+	return entry, result, nil
+}
 
-	return jsonResult(protocol.ReplaySendResponse{
-		ReplayID: replayID,
-		Duration: result.Duration.String(),
-		ResponseDetails: protocol.ResponseDetails{
-			Status:      respCode,
-			StatusLine:  respStatusLine,
-			RespHeaders: string(result.Headers),
-			RespSize:    len(result.Body),
-			RespPreview: previewBody(result.Body, responsePreviewSize),
-		},
-	})
+// withRequestTimeout bounds ctx by the optional "timeout" argument of a send tool.
+func withRequestTimeout(ctx context.Context, req mcp.CallToolRequest) (context.Context, context.CancelFunc, error) {
+	timeoutStr := req.GetString("timeout", "")
+	if timeoutStr == "" {
+		return ctx, func() {}, nil
+	}
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil || timeout <= 0 {
+		return nil, nil, errors.New("invalid timeout: must be a positive duration like 5s")
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, nil
+}
+
+// replayRedirects converts the redirects a send followed for replay history storage.
+func replayRedirects(hops []proxy.RedirectHop) []store.ReplayRedirect {
+	if len(hops) == 0 {
+		return nil
+	}
+	redirects := make([]store.ReplayRedirect, len(hops))
+	for i, h := range hops {
+		redirects[i] = store.ReplayRedirect{Status: h.Status, URL: h.URL, Location: h.Location}
+	}
+	return redirects
 }
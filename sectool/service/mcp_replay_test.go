@@ -50,7 +50,13 @@ func TestMCP_ReplayWithMock(t *testing.T) {
 		"replay_id": sendResp.ReplayID,
 	})
 	assert.Equal(t, sendResp.ReplayID, getResp.ReplayID)
+	assert.Equal(t, flowID, getResp.SourceFlowID)
+	assert.Equal(t, 200, getResp.Status)
+	assert.NotEmpty(t, getResp.Duration)
+	assert.Contains(t, getResp.ReqHeaders, "GET /replay-test HTTP/1.1")
 	assert.NotEmpty(t, getResp.RespHeaders)
+	assert.Equal(t, "replayed response", getResp.RespBody)
+	assert.Empty(t, getResp.Redirects)
 }
 
 func TestMCP_RequestSendWithMock(t *testing.T) {
@@ -110,6 +116,49 @@ func TestMCP_RequestSendWithMock(t *testing.T) {
 	}
 }
 
+func TestMCP_Fetch(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMockMCPServer(t)
+
+	mockMCP.SetSendResponse(
+		"HttpRequestResponse{httpRequest=GET /search HTTP/1.1, httpResponse=HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<p>Results for needle</p>}",
+	)
+
+	resp := CallMCPToolJSONOK[protocol.FetchResponse](t, mcpClient, "fetch", map[string]interface{}{
+		"url":     "https://example.com/search?q=needle",
+		"headers": map[string]interface{}{"X-Fetch": "1"},
+	})
+	require.NotEmpty(t, resp.FlowID)
+	assert.Equal(t, 200, resp.Status)
+	assert.Equal(t, "text/html", resp.ContentType)
+	assert.Equal(t, len("<p>Results for needle</p>"), resp.Size)
+	assert.NotEmpty(t, resp.Duration)
+
+	sent := mockMCP.LastSentRequest()
+	assert.Contains(t, sent, "GET /search?q=needle HTTP/1.1")
+	assert.Contains(t, sent, "X-Fetch: 1")
+
+	t.Run("flow_usable_by_other_tools", func(t *testing.T) {
+		got := CallMCPToolJSONOK[protocol.ProxyGetResponse](t, mcpClient, "proxy_get", map[string]interface{}{
+			"flow_id": resp.FlowID,
+		})
+		assert.Equal(t, resp.FlowID, got.FlowID)
+
+		reflected := CallMCPToolJSONOK[protocol.FindReflectedResponse](t, mcpClient, "find_reflected", map[string]interface{}{
+			"flow_id": resp.FlowID,
+		})
+		require.Len(t, reflected.Reflections, 1)
+		assert.Equal(t, "q", reflected.Reflections[0].Name)
+	})
+
+	t.Run("missing_url", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "fetch", map[string]interface{}{})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "url is required")
+	})
+}
+
 func TestMCP_RequestSendHeaderFormats(t *testing.T) {
 	t.Parallel()
 
@@ -226,6 +275,15 @@ func TestMCP_RequestSendValidation(t *testing.T) {
 		assert.True(t, result.IsError)
 		assert.Contains(t, ExtractMCPText(t, result), "invalid URL")
 	})
+
+	t.Run("invalid_timeout", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "request_send", map[string]interface{}{
+			"url":     "https://example.com/test",
+			"timeout": "-1s",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid timeout")
+	})
 }
 
 func TestMCP_ReplayValidation(t *testing.T) {
@@ -780,6 +838,20 @@ func TestMCP_DomainScoping(t *testing.T) {
 		assert.Contains(t, ExtractMCPText(t, result), "domain rejected")
 	})
 
+	t.Run("fetch_rejected", func(t *testing.T) {
+		t.Parallel()
+
+		_, mcpClient, _, _, _ := setupMockMCPServerWithConfig(t, &config.Config{
+			AllowedDomains: []string{"allowed.test"},
+		})
+
+		result := CallMCPTool(t, mcpClient, "fetch", map[string]interface{}{
+			"url": "https://blocked.test/",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "domain rejected")
+	})
+
 	t.Run("request_send_excluded_subdomain", func(t *testing.T) {
 		t.Parallel()
 
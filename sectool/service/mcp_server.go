@@ -152,6 +152,9 @@ func (m *mcpServer) registerTools() {
 		m.addCrawlTools()
 		m.addDiffTools()
 		m.addReflectionTools()
+		m.addHeaderTools()
+		m.addKillSwitchTools()
+		m.addServerTools()
 	case WorkflowModeTestReport:
 		m.addProxyTools()
 		m.addReplayTools()
@@ -161,6 +164,9 @@ func (m *mcpServer) registerTools() {
 		m.addJWTTools()
 		m.addDiffTools()
 		m.addReflectionTools()
+		m.addHeaderTools()
+		m.addKillSwitchTools()
+		m.addServerTools()
 		// crawl tools excluded
 	default: // Empty (default) workflowMode: require workflow tool call first, all tools registered
 		m.server.AddTool(m.workflowTool(), m.handleWorkflow)
@@ -173,6 +179,9 @@ func (m *mcpServer) registerTools() {
 		m.addCrawlTools()
 		m.addDiffTools()
 		m.addReflectionTools()
+		m.addHeaderTools()
+		m.addKillSwitchTools()
+		m.addServerTools()
 	}
 }
 
@@ -183,12 +192,15 @@ func (m *mcpServer) addProxyTools() {
 	m.server.AddTool(m.proxyRuleListTool(), m.handleProxyRuleList)
 	m.server.AddTool(m.proxyRuleAddTool(), m.handleProxyRuleAdd)
 	m.server.AddTool(m.proxyRuleDeleteTool(), m.handleProxyRuleDelete)
+	m.server.AddTool(m.proxyInterceptTool(), m.handleProxyIntercept)
 }
 
 func (m *mcpServer) addReplayTools() {
 	m.server.AddTool(m.replaySendTool(), m.handleReplaySend)
 	m.server.AddTool(m.replayGetTool(), m.handleReplayGet)
 	m.server.AddTool(m.requestSendTool(), m.handleRequestSend)
+	m.server.AddTool(m.fetchTool(), m.handleFetch)
+	m.server.AddTool(m.replaySmuggleTool(), m.handleReplaySmuggle)
 }
 
 func (m *mcpServer) addOastTools() {
@@ -216,14 +228,20 @@ func (m *mcpServer) addCrawlTools() {
 	m.server.AddTool(m.crawlCreateTool(), m.handleCrawlCreate)
 	m.server.AddTool(m.crawlSeedTool(), m.handleCrawlSeed)
 	m.server.AddTool(m.crawlStatusTool(), m.handleCrawlStatus)
+	m.server.AddTool(m.crawlStatsTool(), m.handleCrawlStats)
 	m.server.AddTool(m.crawlPollTool(), m.handleCrawlPoll)
 	m.server.AddTool(m.crawlSessionsTool(), m.handleCrawlSessions)
 	m.server.AddTool(m.crawlStopTool(), m.handleCrawlStop)
+	m.server.AddTool(m.crawlResumeTool(), m.handleCrawlResume)
+	m.server.AddTool(m.crawlDeleteTool(), m.handleCrawlDelete)
+	m.server.AddTool(m.crawlExportSessionTool(), m.handleCrawlExportSession)
 	m.server.AddTool(m.crawlGetTool(), m.handleCrawlGet)
+	m.server.AddTool(m.extractLinksTool(), m.handleExtractLinks)
 }
 
 func (m *mcpServer) addDiffTools() {
 	m.server.AddTool(m.diffFlowTool(), m.handleDiffFlow)
+	m.server.AddTool(m.diffMultiTool(), m.handleDiffMulti)
 }
 
 const workflowNotInitializedError = "call workflow first with the relevant task, use 'explore' if there is no better fit"
@@ -357,6 +375,7 @@ func translateTimeoutError(err error) string {
 type resolvedFlow struct {
 	RawRequest  []byte
 	RawResponse []byte
+	URL         string // request URL, only known for crawler flows
 }
 
 // resolveFlow looks up a flow by ID across replay, proxy, and crawler backends.
@@ -385,6 +404,7 @@ func (m *mcpServer) resolveFlow(ctx context.Context, flowID string) (*resolvedFl
 		return &resolvedFlow{
 			RawRequest:  flow.Request,
 			RawResponse: flow.Response,
+			URL:         flow.URL,
 		}, nil
 	}
 	return nil, errorResult("flow_id not found: run proxy_poll or crawl_poll to see available flows")
@@ -2,9 +2,15 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -49,6 +55,9 @@ func setupMockMCPServerWithConfig(t *testing.T, cfg *config.Config) (*Server, *m
 		if cfg.IncludeSubdomains != nil {
 			defaults.IncludeSubdomains = cfg.IncludeSubdomains
 		}
+		if cfg.MaxBodyBytes != 0 {
+			defaults.MaxBodyBytes = cfg.MaxBodyBytes
+		}
 		require.NoError(t, defaults.Save(configPath))
 	}
 
@@ -95,6 +104,21 @@ func setupMockMCPServerWithConfig(t *testing.T, cfg *config.Config) (*Server, *m
 	return srv, mcpClient, mockMCP, mockOast, mockCrawler
 }
 
+func TestServer_PIDFile(t *testing.T) {
+	t.Parallel()
+
+	srv, _, _, _, _ := setupMockMCPServer(t)
+
+	data, err := os.ReadFile(srv.pidPath)
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(os.Getpid())+"\n", string(data))
+	assert.Equal(t, filepath.Dir(srv.configPath), filepath.Dir(srv.pidPath))
+
+	srv.removePIDFile()
+	_, err = os.Stat(srv.pidPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
 func TestMCP_ListTools(t *testing.T) {
 	t.Parallel()
 
@@ -113,9 +137,11 @@ func TestMCP_ListTools(t *testing.T) {
 		"proxy_rule_list",
 		"proxy_rule_add",
 		"proxy_rule_delete",
+		"proxy_intercept",
 		"replay_send",
 		"replay_get",
 		"request_send",
+		"fetch",
 		"oast_create",
 		"oast_poll",
 		"oast_get",
@@ -128,12 +154,21 @@ func TestMCP_ListTools(t *testing.T) {
 		"crawl_create",
 		"crawl_seed",
 		"crawl_status",
+		"crawl_stats",
 		"crawl_poll",
 		"crawl_get",
 		"crawl_sessions",
 		"crawl_stop",
+		"crawl_resume",
+		"crawl_delete",
+		"crawl_export_session",
+		"extract_links",
 		"diff_flow",
+		"diff_multi",
 		"find_reflected",
+		"security_headers",
+		"server_status",
+		"server_stop",
 	}
 
 	toolNames := make([]string, len(result.Tools))
@@ -227,7 +262,7 @@ func (b *mockOastBackend) GetEvent(ctx context.Context, idOrDomain string, event
 			return &e, nil
 		}
 	}
-	return nil, ErrNotFound
+	return nil, ErrEventNotFound
 }
 
 func (b *mockOastBackend) ListSessions(ctx context.Context) ([]OastSessionInfo, error) {
@@ -278,26 +313,40 @@ func (b *mockOastBackend) resolveID(idOrDomain string) (string, error) {
 }
 
 type mockCrawlerBackend struct {
-	sessions map[string]*CrawlSessionInfo
-	byLabel  map[string]string
-	status   map[string]*CrawlStatus
-	flows    map[string]*CrawlFlow
-	forms    map[string][]DiscoveredForm
-	errors   map[string][]CrawlError
+	sessions  map[string]*CrawlSessionInfo
+	byLabel   map[string]string
+	status    map[string]*CrawlStatus
+	flows     map[string]*CrawlFlow
+	forms     map[string][]DiscoveredForm
+	errors    map[string][]CrawlError
+	external  map[string][]ExternalLink
+	routes    map[string][]ClientRoute
+	endpoints map[string][]JSEndpoint
+	similar   map[string][]SimilarCluster
+
+	lastCreateOpts CrawlOptions
+
+	stopMu  sync.Mutex // guards stopped, which is read while server shutdown closes the backend
+	stopped []string
 }
 
 func newMockCrawlerBackend() *mockCrawlerBackend {
 	return &mockCrawlerBackend{
-		sessions: make(map[string]*CrawlSessionInfo),
-		byLabel:  make(map[string]string),
-		status:   make(map[string]*CrawlStatus),
-		flows:    make(map[string]*CrawlFlow),
-		forms:    make(map[string][]DiscoveredForm),
-		errors:   make(map[string][]CrawlError),
+		sessions:  make(map[string]*CrawlSessionInfo),
+		byLabel:   make(map[string]string),
+		status:    make(map[string]*CrawlStatus),
+		flows:     make(map[string]*CrawlFlow),
+		forms:     make(map[string][]DiscoveredForm),
+		errors:    make(map[string][]CrawlError),
+		external:  make(map[string][]ExternalLink),
+		routes:    make(map[string][]ClientRoute),
+		endpoints: make(map[string][]JSEndpoint),
+		similar:   make(map[string][]SimilarCluster),
 	}
 }
 
 func (b *mockCrawlerBackend) CreateSession(ctx context.Context, opts CrawlOptions) (*CrawlSessionInfo, error) {
+	b.lastCreateOpts = opts
 	if len(opts.Seeds) == 0 {
 		return nil, errors.New("no valid seeds")
 	}
@@ -340,6 +389,17 @@ func (b *mockCrawlerBackend) AddSeeds(ctx context.Context, sessionID string, see
 	return nil
 }
 
+func (b *mockCrawlerBackend) StreamStatus(ctx context.Context, sessionID string) (<-chan CrawlStatus, error) {
+	status, err := b.GetStatus(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan CrawlStatus, 1)
+	ch <- *status
+	close(ch)
+	return ch, nil
+}
+
 func (b *mockCrawlerBackend) GetStatus(ctx context.Context, sessionID string) (*CrawlStatus, error) {
 	sess, err := b.resolveSession(sessionID)
 	if err != nil {
@@ -354,6 +414,26 @@ func (b *mockCrawlerBackend) GetStatus(ctx context.Context, sessionID string) (*
 	return &copy, nil
 }
 
+func (b *mockCrawlerBackend) GetStats(ctx context.Context, sessionID string) (*CrawlStats, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	stats := &CrawlStats{
+		StatusCodes:  make(map[int]int),
+		ContentTypes: make(map[string]int),
+	}
+	for _, flow := range b.flows {
+		if flow.SessionID != sess.ID {
+			continue
+		}
+		stats.TotalFlows++
+		stats.StatusCodes[flow.StatusCode]++
+		stats.ContentTypes[contentMediaType(flow.ContentType)]++
+	}
+	return stats, nil
+}
+
 func (b *mockCrawlerBackend) ListFlows(ctx context.Context, sessionID string, opts CrawlListOptions) ([]CrawlFlow, error) {
 	sess, err := b.resolveSession(sessionID)
 	if err != nil {
@@ -368,6 +448,8 @@ func (b *mockCrawlerBackend) ListFlows(ctx context.Context, sessionID string, op
 		}
 		if hasSearch && !matchesFlowSearch(flow.Request, flow.Response, opts.SearchHeaderRe, opts.SearchBodyRe) {
 			continue
+		} else if opts.Fingerprint != "" && endpointFingerprint(flow) != opts.Fingerprint {
+			continue
 		}
 		flows = append(flows, *flow)
 	}
@@ -409,6 +491,80 @@ func (b *mockCrawlerBackend) ListErrors(ctx context.Context, sessionID string, l
 	return errs, nil
 }
 
+func (b *mockCrawlerBackend) ListExternalLinks(ctx context.Context, sessionID string, limit int) ([]ExternalLink, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	links := b.external[sess.ID]
+	if limit > 0 && len(links) > limit {
+		links = links[:limit]
+	}
+	return links, nil
+}
+
+func (b *mockCrawlerBackend) ListJSEndpoints(ctx context.Context, sessionID string, limit int) ([]JSEndpoint, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	endpoints := b.endpoints[sess.ID]
+	if limit > 0 && len(endpoints) > limit {
+		endpoints = endpoints[:limit]
+	}
+	return endpoints, nil
+}
+
+func (b *mockCrawlerBackend) ListFindings(ctx context.Context, sessionID string, limit int) ([]CrawlFinding, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for id, flow := range b.flows {
+		if flow.SessionID == sess.ID {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	var findings []CrawlFinding
+	for _, id := range ids {
+		flow := b.flows[id]
+		for _, m := range flow.SensitiveData {
+			findings = append(findings, CrawlFinding{FlowID: id, URL: flow.URL, Finding: findingSensitiveData,
+				Pattern: m.Pattern, Offset: m.Offset, Snippet: m.Snippet})
+		}
+	}
+	if limit > 0 && len(findings) > limit {
+		findings = findings[:limit]
+	}
+	return findings, nil
+}
+
+func (b *mockCrawlerBackend) ListClientRoutes(ctx context.Context, sessionID string, limit int) ([]ClientRoute, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	routes := b.routes[sess.ID]
+	if limit > 0 && len(routes) > limit {
+		routes = routes[:limit]
+	}
+	return routes, nil
+}
+
+func (b *mockCrawlerBackend) ListSimilarClusters(ctx context.Context, sessionID string, limit int) ([]SimilarCluster, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	clusters := b.similar[sess.ID]
+	if limit > 0 && len(clusters) > limit {
+		clusters = clusters[:limit]
+	}
+	return clusters, nil
+}
+
 func (b *mockCrawlerBackend) GetFlow(ctx context.Context, flowID string) (*CrawlFlow, error) {
 	flow, ok := b.flows[flowID]
 	if !ok {
@@ -417,6 +573,72 @@ func (b *mockCrawlerBackend) GetFlow(ctx context.Context, flowID string) (*Crawl
 	return flow, nil
 }
 
+func (b *mockCrawlerBackend) ExportSession(ctx context.Context, sessionID, format string, w io.Writer) (int, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return 0, err
+	} else if format != CrawlExportNDJSON {
+		return 0, fmt.Errorf("unsupported export format %q", format)
+	}
+	var ids []string
+	for id, flow := range b.flows {
+		if flow.SessionID == sess.ID {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	enc := json.NewEncoder(w)
+	for _, id := range ids {
+		if err := enc.Encode(newCrawlExportFlow(b.flows[id])); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+func (b *mockCrawlerBackend) DeleteFlow(ctx context.Context, flowID string) error {
+	if _, ok := b.flows[flowID]; !ok {
+		return ErrNotFound
+	}
+	delete(b.flows, flowID)
+	return nil
+}
+
+func (b *mockCrawlerBackend) DeleteFlows(ctx context.Context, sessionID string, opts CrawlListOptions) (int, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return 0, err
+	}
+	var deleted int
+	for id, flow := range b.flows {
+		if flow.SessionID == sess.ID && matchesFlowFilters(flow, opts) != opts.Invert {
+			delete(b.flows, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (b *mockCrawlerBackend) DeleteSession(ctx context.Context, sessionID string, force bool) (int, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return 0, err
+	} else if sess.State == "running" && !force {
+		return 0, fmt.Errorf("session %s is running", sessionID)
+	}
+	var deleted int
+	for id, flow := range b.flows {
+		if flow.SessionID == sess.ID {
+			delete(b.flows, id)
+			deleted++
+		}
+	}
+	delete(b.sessions, sess.ID)
+	delete(b.status, sess.ID)
+	delete(b.byLabel, sess.Label)
+	return deleted, nil
+}
+
 func (b *mockCrawlerBackend) StopSession(ctx context.Context, sessionID string) error {
 	sess, err := b.resolveSession(sessionID)
 	if err != nil {
@@ -427,9 +649,34 @@ func (b *mockCrawlerBackend) StopSession(ctx context.Context, sessionID string)
 		status.State = "stopped"
 		status.LastActivity = time.Now()
 	}
+	b.stopMu.Lock()
+	b.stopped = append(b.stopped, sess.ID)
+	b.stopMu.Unlock()
 	return nil
 }
 
+// StoppedSessions returns the IDs of sessions stopped through StopSession, in call order.
+func (b *mockCrawlerBackend) StoppedSessions() []string {
+	b.stopMu.Lock()
+	defer b.stopMu.Unlock()
+	return slices.Clone(b.stopped)
+}
+
+func (b *mockCrawlerBackend) ResumeSession(ctx context.Context, sessionID string) (int, error) {
+	sess, err := b.resolveSession(sessionID)
+	if err != nil {
+		return 0, err
+	} else if sess.State == "running" {
+		return 0, fmt.Errorf("session %s is already running", sessionID)
+	}
+	sess.State = "running"
+	if status := b.status[sess.ID]; status != nil {
+		status.State = "running"
+		status.LastActivity = time.Now()
+	}
+	return 0, nil
+}
+
 func (b *mockCrawlerBackend) ListSessions(ctx context.Context, limit int) ([]CrawlSessionInfo, error) {
 	sessions := make([]CrawlSessionInfo, 0, len(b.sessions))
 	for _, sess := range b.sessions {
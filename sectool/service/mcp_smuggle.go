@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+	"github.com/go-appsec/toolbox/sectool/service/ids"
+)
+
+const defaultSmuggleTimeout = 10 * time.Second
+
+// Desync verdicts, ordered by increasing likelihood
+const (
+	smuggleVerdictInconclusive = "inconclusive"
+	smuggleVerdictUnlikely     = "unlikely"
+	smuggleVerdictPossible     = "possible"
+	smuggleVerdictLikely       = "likely"
+)
+
+var smuggleVerdictRank = map[string]int{
+	smuggleVerdictInconclusive: 0,
+	smuggleVerdictUnlikely:     1,
+	smuggleVerdictPossible:     2,
+	smuggleVerdictLikely:       3,
+}
+
+// smuggleTechnique describes a timing probe: a body whose framing is complete under one
+// length interpretation but leaves the other side waiting for bytes that never arrive.
+type smuggleTechnique struct {
+	name          string
+	body          string
+	contentLength int
+}
+
+// CL.TE: front-end forwards 4 bytes by Content-Length, chunked back-end waits for the next chunk.
+// TE.CL: front-end forwards the terminating chunk, Content-Length back-end waits for the 6th byte.
+var smuggleTechniques = []smuggleTechnique{
+	{name: "CL.TE", body: "1\r\nA\r\nX", contentLength: 4},
+	{name: "TE.CL", body: "0\r\n\r\nX", contentLength: 6},
+}
+
+func (m *mcpServer) replaySmuggleTool() mcp.Tool {
+	return mcp.NewTool("replay_smuggle",
+		mcp.WithDescription(`INTRUSIVE: probe a target for HTTP request smuggling (CL.TE / TE.CL desync) using timing.
+
+Sends a baseline request, then raw HTTP/1.1 requests with conflicting Content-Length and Transfer-Encoding framing. A probe that hangs until the timeout while the baseline answers promptly indicates the front-end and back-end disagree on request length. Anomalies are re-sent once to confirm.
+
+Probes are sent as exact bytes over fresh connections (no normalization). They can poison shared back-end connections and affect other users, so only run against targets you are authorized to test. Requires confirm_intrusive=true.
+
+Verdict: likely (confirmed), possible (single anomaly), unlikely, or inconclusive (baseline failed). TE.CL is skipped when CL.TE is flagged to avoid socket poisoning.`),
+		mcp.WithString("url", mcp.Required(), mcp.Description("Target URL (e.g., 'https://example.com/')")),
+		mcp.WithString("method", mcp.Description("HTTP method for probes (default: POST)")),
+		mcp.WithObject("headers", mcp.Description("Headers as object: {\"Name\": \"Value\"}; Content-Length and Transfer-Encoding are replaced")),
+		mcp.WithString("timeout", mcp.Description("Per-request timeout used to detect hangs (default: 10s)")),
+		mcp.WithBoolean("confirm_intrusive", mcp.Required(), mcp.Description("Must be true: acknowledges probes may disrupt the target")),
+	)
+}
+
+func (m *mcpServer) handleReplaySmuggle(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := m.requireWorkflow(); err != nil {
+		return err, nil
+	}
+
+	if !req.GetBool("confirm_intrusive", false) {
+		return errorResult("replay_smuggle sends malformed requests that can disrupt the target; set confirm_intrusive=true to proceed"), nil
+	}
+	ctx, done, err := m.service.activityContext(ctx)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	defer done()
+
+	urlStr := req.GetString("url", "")
+	if urlStr == "" {
+		return errorResult("url is required"), nil
+	}
+	parsedURL, err := parseURLWithDefaultHTTPS(urlStr)
+	if err != nil {
+		return errorResult("invalid URL: " + err.Error()), nil
+	}
+	if allowed, reason := m.service.cfg.IsDomainAllowed(parsedURL.Hostname()); !allowed {
+		return errorResult("domain rejected: " + reason), nil
+	}
+
+	timeout := defaultSmuggleTimeout
+	if timeoutStr := req.GetString("timeout", ""); timeoutStr != "" {
+		if timeout, err = time.ParseDuration(timeoutStr); err != nil || timeout <= 0 {
+			return errorResult("invalid timeout: must be a positive duration like 10s"), nil
+		}
+	}
+
+	var headers map[string]string
+	if args := req.GetArguments(); args != nil {
+		if raw, ok := args["headers"]; ok && raw != nil {
+			headers = headerArgToMap(raw)
+		}
+	}
+	for k := range headers {
+		if strings.EqualFold(k, "Content-Length") || strings.EqualFold(k, "Transfer-Encoding") {
+			delete(headers, k)
+		}
+	}
+
+	method := strings.ToUpper(req.GetString("method", "POST"))
+	baseline := buildRawRequest(method, parsedURL, headers, []byte("x=1"))
+	if baseline == nil {
+		return errorResult("failed to build request: invalid method or URL"), nil
+	}
+	baseHeaders, _ := splitHeadersBody(baseline)
+	baseHeaders = setHeaderIfMissing(baseHeaders, "Content-Type", "application/x-www-form-urlencoded")
+	baseline = append(baseHeaders, "x=1"...)
+
+	target := targetFromURL(parsedURL)
+	probeID := ids.Generate(ids.DefaultLength)
+	log.Printf("mcp/replay_smuggle: %s probing %s (timeout=%s)", probeID, parsedURL, timeout)
+
+	resp := protocol.SmuggleProbeResponse{
+		Baseline: m.sendSmuggleAttempt(ctx, probeID, target, baseline, timeout),
+	}
+	if err := ctx.Err(); err != nil {
+		return m.requestErrorResult(err), nil
+	} else if resp.Baseline.TimedOut || resp.Baseline.Error != "" {
+		resp.Verdict = smuggleVerdictInconclusive
+		resp.Note = "baseline request failed or timed out; timing comparison is not meaningful"
+		return jsonResult(resp)
+	}
+
+	resp.Verdict = smuggleVerdictUnlikely
+	for _, tech := range smuggleTechniques {
+		probe := protocol.SmuggleProbe{Technique: tech.name}
+		if smuggleVerdictRank[resp.Verdict] >= smuggleVerdictRank[smuggleVerdictPossible] {
+			probe.Verdict = smuggleVerdictInconclusive
+			probe.Skipped = "earlier technique flagged; skipped to avoid poisoning the back-end connection"
+			resp.Probes = append(resp.Probes, probe)
+			continue
+		}
+
+		raw := buildSmuggleProbe(baseHeaders, tech)
+		first := m.sendSmuggleAttempt(ctx, probeID, target, raw, timeout)
+		if err := ctx.Err(); err != nil {
+			return m.requestErrorResult(err), nil
+		}
+		probe.Attempts = append(probe.Attempts, first)
+		probe.Verdict = smuggleVerdictUnlikely
+		if first.TimedOut {
+			confirm := m.sendSmuggleAttempt(ctx, probeID, target, raw, timeout)
+			probe.Attempts = append(probe.Attempts, confirm)
+			if confirm.TimedOut {
+				probe.Verdict = smuggleVerdictLikely
+			} else {
+				probe.Verdict = smuggleVerdictPossible
+			}
+		}
+
+		if smuggleVerdictRank[probe.Verdict] > smuggleVerdictRank[resp.Verdict] {
+			resp.Verdict = probe.Verdict
+		}
+		resp.Probes = append(resp.Probes, probe)
+	}
+
+	log.Printf("mcp/replay_smuggle: %s verdict=%s", probeID, resp.Verdict)
+	return jsonResult(resp)
+}
+
+// buildSmuggleProbe frames the technique body with both Transfer-Encoding and a conflicting Content-Length.
+func buildSmuggleProbe(baseHeaders []byte, tech smuggleTechnique) []byte {
+	headers := setHeader(baseHeaders, "Transfer-Encoding", "chunked")
+	headers = setHeader(headers, "Content-Length", strconv.Itoa(tech.contentLength))
+	return append(headers, tech.body...)
+}
+
+// sendSmuggleAttempt sends raw bytes as HTTP/1.1 without validation, bounding the wait by timeout.
+func (m *mcpServer) sendSmuggleAttempt(ctx context.Context, probeID string, target Target, raw []byte, timeout time.Duration) protocol.SmuggleAttempt {
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := m.service.httpBackend.SendRequest(attemptCtx, "sectool-smuggle-"+probeID, SendRequestInput{
+		RawRequest: raw,
+		Target:     target,
+		Force:      true,
+		Protocol:   "http/1.1",
+	})
+	attempt := protocol.SmuggleAttempt{
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+	if err != nil {
+		if IsTimeoutError(err) {
+			attempt.TimedOut = true
+		} else {
+			attempt.Error = err.Error()
+		}
+		return attempt
+	}
+	attempt.Status, _ = parseResponseStatus(result.Headers)
+	return attempt
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func TestBuildSmuggleProbe(t *testing.T) {
+	t.Parallel()
+
+	base := []byte("POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 3\r\n\r\n")
+
+	t.Run("cl_te", func(t *testing.T) {
+		raw := string(buildSmuggleProbe(base, smuggleTechniques[0]))
+		assert.Equal(t, "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 4\r\nTransfer-Encoding: chunked\r\n\r\n1\r\nA\r\nX", raw)
+	})
+
+	t.Run("te_cl", func(t *testing.T) {
+		raw := string(buildSmuggleProbe(base, smuggleTechniques[1]))
+		assert.Equal(t, "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 6\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\nX", raw)
+	})
+}
+
+func TestMCP_ReplaySmuggle(t *testing.T) {
+	t.Parallel()
+
+	_, mcpClient, mockMCP, _, _ := setupMockMCPServer(t)
+
+	t.Run("requires_confirmation", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "replay_smuggle", map[string]interface{}{
+			"url": "https://example.com/",
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "confirm_intrusive")
+	})
+
+	t.Run("invalid_timeout", func(t *testing.T) {
+		result := CallMCPTool(t, mcpClient, "replay_smuggle", map[string]interface{}{
+			"url":               "https://example.com/",
+			"timeout":           "soon",
+			"confirm_intrusive": true,
+		})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid timeout")
+	})
+
+	t.Run("prompt_responses_unlikely", func(t *testing.T) {
+		resp := CallMCPToolJSONOK[protocol.SmuggleProbeResponse](t, mcpClient, "replay_smuggle", map[string]interface{}{
+			"url":               "https://example.com/login",
+			"headers":           map[string]interface{}{"Cookie": "sid=1", "Content-Length": "99"},
+			"confirm_intrusive": true,
+		})
+
+		assert.Equal(t, "unlikely", resp.Verdict)
+		assert.Equal(t, 200, resp.Baseline.Status)
+		require.Len(t, resp.Probes, 2)
+		assert.Equal(t, "CL.TE", resp.Probes[0].Technique)
+		assert.Equal(t, "TE.CL", resp.Probes[1].Technique)
+		for _, p := range resp.Probes {
+			assert.Equal(t, "unlikely", p.Verdict)
+			assert.Len(t, p.Attempts, 1)
+		}
+
+		sent := mockMCP.LastSentRequest()
+		assert.Contains(t, sent, "POST /login HTTP/1.1")
+		assert.Contains(t, sent, "Cookie: sid=1")
+		assert.Contains(t, sent, "Transfer-Encoding: chunked")
+		assert.Contains(t, sent, "Content-Length: 6")
+		assert.NotContains(t, sent, "Content-Length: 99")
+	})
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func (m *mcpServer) addServerTools() {
+	m.server.AddTool(m.serverStatusTool(), m.handleServerStatus)
+	m.server.AddTool(m.serverStopTool(), m.handleServerStop)
+}
+
+func (m *mcpServer) serverStatusTool() mcp.Tool {
+	return mcp.NewTool("server_status",
+		mcp.WithDescription(`Report sectool server health: version, PID, MCP and built-in proxy ports, proxy backend (native or burp), uptime, running crawl count, kill switch state, and stored flow counts.`),
+	)
+}
+
+func (m *mcpServer) handleServerStatus(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Not gated on the workflow call: health checks must work before any task is chosen
+	s := m.service
+	resp := protocol.ServerStatusResponse{
+		Version:    config.Version,
+		PID:        os.Getpid(),
+		MCPPort:    addrPort(m.Addr()),
+		Backend:    "burp",
+		StartedAt:  s.startedAt.UTC().Format(time.RFC3339),
+		Uptime:     time.Since(s.startedAt).Round(time.Second).String(),
+		KillSwitch: s.KillSwitchState().Engaged,
+		Stores:     s.HealthMetrics(),
+	}
+	if s.usingBuiltinProxy {
+		resp.Backend = "native"
+		if backend, ok := s.httpBackend.(*NativeProxyBackend); ok {
+			resp.ProxyPort = addrPort(backend.Addr())
+		}
+	}
+
+	if s.crawlerBackend != nil {
+		sessions, err := s.crawlerBackend.ListSessions(ctx, 0)
+		if err != nil {
+			return errorResultFromErr("failed to list crawl sessions: ", err), nil
+		}
+		for _, sess := range sessions {
+			if sess.State == crawlStateRunning {
+				resp.ActiveCrawls++
+			}
+		}
+	}
+
+	log.Printf("mcp/server_status: uptime=%s active_crawls=%d", resp.Uptime, resp.ActiveCrawls)
+	return jsonResult(resp)
+}
+
+// addrPort returns the port of a host:port address, or 0 when it cannot be parsed.
+func addrPort(addr string) int {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(port)
+	return n
+}
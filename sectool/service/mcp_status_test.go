@@ -0,0 +1,53 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func TestMCP_ServerStatus(t *testing.T) {
+	t.Parallel()
+
+	srv, mcpClient, _, _, _ := setupMockMCPServer(t)
+
+	running := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls": "https://example.com/",
+	})
+	stopped := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+		"seed_urls": "https://example.com/other",
+	})
+	CallMCPToolTextOK(t, mcpClient, "crawl_stop", map[string]interface{}{
+		"session_id": stopped.SessionID,
+	})
+	require.NotEqual(t, running.SessionID, stopped.SessionID)
+
+	resp := CallMCPToolJSONOK[protocol.ServerStatusResponse](t, mcpClient, "server_status", nil)
+
+	assert.Equal(t, config.Version, resp.Version)
+	assert.Equal(t, os.Getpid(), resp.PID)
+	assert.Equal(t, addrPort(srv.mcpServer.Addr()), resp.MCPPort)
+	assert.Positive(t, resp.MCPPort)
+	assert.Equal(t, "burp", resp.Backend)
+	assert.Zero(t, resp.ProxyPort)
+	assert.NotEmpty(t, resp.StartedAt)
+	assert.NotEmpty(t, resp.Uptime)
+	assert.Equal(t, 1, resp.ActiveCrawls)
+	assert.False(t, resp.KillSwitch)
+	assert.Equal(t, "0", resp.Stores["flows"])
+	assert.Equal(t, "0", resp.Stores["replay_history"])
+}
+
+func TestAddrPort(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 9119, addrPort("127.0.0.1:9119"))
+	assert.Equal(t, 8080, addrPort("[::1]:8080"))
+	assert.Zero(t, addrPort(""))
+	assert.Zero(t, addrPort("localhost"))
+}
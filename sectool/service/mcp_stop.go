@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+const (
+	defaultStopTimeout = 10 * time.Second
+	maxStopTimeout     = 2 * time.Minute
+)
+
+func (m *mcpServer) serverStopTool() mcp.Tool {
+	return mcp.NewTool("server_stop",
+		mcp.WithDescription(`Shut down the sectool server. Running crawls get up to timeout to finish, then are stopped; the server exits after responding, ending all tools, the proxy, and stored flows. Only call when the user explicitly asks.`),
+		mcp.WithString("timeout", mcp.Description("How long running crawls may take to finish before they are stopped (default '10s', max 2m, '0s' to stop immediately)")),
+	)
+}
+
+func (m *mcpServer) handleServerStop(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Not gated on the workflow call: stopping the server must always be possible
+	timeout := defaultStopTimeout
+	if timeoutStr := req.GetString("timeout", ""); timeoutStr != "" {
+		parsed, err := time.ParseDuration(timeoutStr)
+		if err != nil || parsed < 0 {
+			return errorResult("invalid timeout: use a duration like '10s'"), nil
+		}
+		timeout = min(parsed, maxStopTimeout)
+	}
+
+	log.Printf("mcp/server_stop: draining crawls (timeout=%s)", timeout)
+	drained := m.service.DrainAndShutdown(ctx, timeout)
+
+	return jsonResult(protocol.ServerStopResponse{
+		PID:            os.Getpid(),
+		FinishedCrawls: drained.FinishedCrawls,
+		StoppedCrawls:  drained.StoppedCrawls,
+	})
+}
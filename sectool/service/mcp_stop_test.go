@@ -0,0 +1,70 @@
+package service
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func TestMCP_ServerStop(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stops_running_crawl", func(t *testing.T) {
+		srv, mcpClient, _, _, mockCrawler := setupMockMCPServer(t)
+
+		// Mock crawls stay running until stopped
+		crawl := CallMCPToolJSONOK[protocol.CrawlCreateResponse](t, mcpClient, "crawl_create", map[string]interface{}{
+			"seed_urls": "https://example.com/",
+		})
+
+		start := time.Now()
+		resp := CallMCPToolJSONOK[protocol.ServerStopResponse](t, mcpClient, "server_stop", map[string]interface{}{
+			"timeout": "200ms",
+		})
+		assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+		assert.Equal(t, os.Getpid(), resp.PID)
+		assert.Equal(t, 1, resp.StoppedCrawls)
+		assert.Zero(t, resp.FinishedCrawls)
+		assert.Equal(t, []string{crawl.SessionID}, mockCrawler.StoppedSessions())
+
+		select {
+		case <-srv.shutdownCh:
+		case <-time.After(time.Second):
+			t.Fatal("server shutdown was not requested")
+		}
+	})
+
+	t.Run("no_crawls", func(t *testing.T) {
+		srv, mcpClient, _, _, _ := setupMockMCPServer(t)
+
+		resp := CallMCPToolJSONOK[protocol.ServerStopResponse](t, mcpClient, "server_stop", map[string]interface{}{
+			"timeout": "5s",
+		})
+		assert.Zero(t, resp.StoppedCrawls)
+		assert.Zero(t, resp.FinishedCrawls)
+
+		select {
+		case <-srv.shutdownCh:
+		case <-time.After(time.Second):
+			t.Fatal("server shutdown was not requested")
+		}
+	})
+
+	t.Run("invalid_timeout", func(t *testing.T) {
+		srv, mcpClient, _, _, _ := setupMockMCPServer(t)
+
+		result := CallMCPTool(t, mcpClient, "server_stop", map[string]interface{}{"timeout": "soon"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, ExtractMCPText(t, result), "invalid timeout")
+
+		select {
+		case <-srv.shutdownCh:
+			t.Fatal("server shutdown requested on invalid input")
+		default:
+		}
+	})
+}
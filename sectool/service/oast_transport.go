@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// interactshHTTPTimeout matches the interactsh client's default request timeout.
+const interactshHTTPTimeout = 10 * time.Second
+
+// interactionCounter tracks interactions the interactsh server returned against those the
+// client delivered, since the client skips entries it cannot decrypt or parse.
+type interactionCounter struct {
+	delivered atomic.Int64 // callbacks invoked
+	failed    atomic.Int64 // entries returned by the server but never delivered
+}
+
+// newInteractshHTTPClient returns a client with the interactsh defaults (timeouts, no
+// redirect following) whose transport counts undelivered poll entries into counter.
+func newInteractshHTTPClient(counter *interactionCounter) *http.Client {
+	return &http.Client{
+		Timeout: interactshHTTPTimeout,
+		Transport: &interactionCountingTransport{
+			base: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout:   5 * time.Second,
+				ResponseHeaderTimeout: interactshHTTPTimeout,
+				IdleConnTimeout:       90 * time.Second,
+				MaxIdleConns:          10,
+				MaxIdleConnsPerHost:   2,
+			},
+			counter: counter,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// interactionCountingTransport inspects /poll responses. The client decodes a poll response
+// and runs every callback before closing its body, so on close the entries that were not
+// delivered are the ones that failed to decrypt or parse.
+type interactionCountingTransport struct {
+	base    http.RoundTripper
+	counter *interactionCounter
+}
+
+func (t *interactionCountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK || !strings.HasSuffix(req.URL.Path, "/poll") {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	var entries struct {
+		Data    []string `json:"data"`
+		Extra   []string `json:"extra"`
+		TLDData []string `json:"tlddata"`
+	}
+	_ = json.Unmarshal(body, &entries)
+	total := int64(len(entries.Data) + len(entries.Extra) + len(entries.TLDData))
+
+	resp.Body = &pollBody{
+		Reader:  bytes.NewReader(body),
+		counter: t.counter,
+		total:   total,
+		start:   t.counter.delivered.Load(),
+	}
+	return resp, nil
+}
+
+type pollBody struct {
+	*bytes.Reader
+	counter *interactionCounter
+	total   int64
+	start   int64
+	once    sync.Once
+}
+
+func (b *pollBody) Close() error {
+	b.once.Do(func() {
+		if failed := b.total - (b.counter.delivered.Load() - b.start); failed > 0 {
+			b.counter.failed.Add(failed)
+			log.Printf("oast: %d of %d polled interactions could not be decrypted or parsed", failed, b.total)
+		}
+	})
+	return nil
+}
@@ -22,6 +22,8 @@ type connectHandler struct {
 	history      *HistoryStore
 	maxBodyBytes int
 
+	holder RequestHolder // optional; while intercepting, TLS connections use HTTP/1.1
+
 	// Server capability cache: host:port -> negotiated protocol ("h2" or "http/1.1")
 	// Avoids repeated probe latency for the same server
 	// TODO - Consider adding a 30-minute TTL on cache entries for long-running sessions
@@ -140,8 +142,15 @@ func (h *connectHandler) handleTLS(ctx context.Context, clientConn net.Conn, tar
 				log.Printf("proxy: SNI mismatch - CONNECT target=%s, SNI=%s (possible domain fronting)", target.Hostname, sni)
 			}
 
-			// Probe or use cached protocol
-			upstreamConn, negotiatedProto, probeErr = h.probeOrConnect(ctx, targetAddr, sni, hello.SupportedProtos)
+			// Probe or use cached protocol; held requests and block/redirect rules need one
+			// request per exchange
+			if (h.holder != nil && h.holder.Intercepting()) ||
+				(h.http1Handler.ruleApplier != nil && h.http1Handler.ruleApplier.HasRequestActions()) {
+				upstreamConn, probeErr = h.dialUpstream(ctx, targetAddr, sni, []string{"http/1.1"})
+				negotiatedProto = "http/1.1"
+			} else {
+				upstreamConn, negotiatedProto, probeErr = h.probeOrConnect(ctx, targetAddr, sni, hello.SupportedProtos)
+			}
 			if probeErr != nil {
 				return nil, probeErr
 			}
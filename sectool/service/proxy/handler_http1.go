@@ -26,6 +26,7 @@ type http1Handler struct {
 	history      *HistoryStore
 	maxBodyBytes int
 	ruleApplier  RuleApplier       // optional, nil means no rules applied
+	holder       RequestHolder     // optional, nil means requests are never held
 	wsHandler    *webSocketHandler // optional, for WebSocket upgrade handling
 	timeouts     TimeoutConfig
 }
@@ -84,6 +85,17 @@ func (h *http1Handler) handleSinglePlainHTTP(ctx context.Context, clientConn net
 	h.rewriteToOriginForm(req, target)
 	req.Protocol = protocolHTTP11
 
+	if h.ruleApplier != nil {
+		if action := h.ruleApplier.MatchRequestAction(target.Hostname, req); action != nil {
+			h.sendRuleAction(clientConn, action)
+			return false
+		}
+	}
+	if h.holder != nil && !h.holder.HoldRequest(ctx, target.Hostname, req) {
+		h.sendError(clientConn, 502, "Bad Gateway: request dropped by intercept")
+		return false
+	}
+
 	if h.wsHandler != nil && isWebSocketUpgrade(req) {
 		h.wsHandler.Handle(ctx, clientConn, clientReader, req, target)
 		return false // WebSocket takes over
@@ -263,6 +275,27 @@ func (h *http1Handler) sendError(conn net.Conn, code int, message string) {
 	_, _ = conn.Write(resp.SerializeRaw(bytes.NewBuffer(nil), false))
 }
 
+// sendRuleAction answers a request matched by a block or redirect rule. The request is
+// neither forwarded nor recorded in history.
+func (h *http1Handler) sendRuleAction(conn net.Conn, action *RequestAction) {
+	if action.Location == "" {
+		h.sendError(conn, 403, "Forbidden: blocked by proxy rule "+action.RuleID)
+		return
+	}
+	resp := &RawHTTP1Response{
+		Version:    "HTTP/1.1",
+		StatusCode: 302,
+		StatusText: "Found",
+		Headers: []Header{
+			{Name: "Location", Value: action.Location},
+			{Name: "Content-Type", Value: "text/plain"},
+			{Name: "Connection", Value: "close"},
+		},
+		Body: []byte("Redirected by proxy rule " + action.RuleID + "\n"),
+	}
+	_, _ = conn.Write(resp.SerializeRaw(bytes.NewBuffer(nil), false))
+}
+
 // storeEntry saves the request/response pair to history.
 func (h *http1Handler) storeEntry(req *RawHTTP1Request, resp *RawHTTP1Response, startTime time.Time) {
 	entry := &HistoryEntry{
@@ -321,6 +354,14 @@ func (h *http1Handler) handleSingleTLS(ctx context.Context, clientConn, upstream
 	// Apply request rules BEFORE WebSocket detection to affect Upgrade header
 	if h.ruleApplier != nil {
 		req = h.ruleApplier.ApplyRequestRules(req)
+		if action := h.ruleApplier.MatchRequestAction(target.Hostname, req); action != nil {
+			h.sendRuleAction(clientConn, action)
+			return false
+		}
+	}
+	if h.holder != nil && !h.holder.HoldRequest(ctx, target.Hostname, req) {
+		h.sendError(clientConn, 502, "Bad Gateway: request dropped by intercept")
+		return false
 	}
 
 	if h.wsHandler != nil && isWebSocketUpgrade(req) {
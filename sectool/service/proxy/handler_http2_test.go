@@ -131,6 +131,14 @@ func (m *h2MockRuleApplier) HasBodyRules(isRequest bool) bool {
 	return m.hasRespBodyRules
 }
 
+func (m *h2MockRuleApplier) MatchRequestAction(host string, req *RawHTTP1Request) *RequestAction {
+	return nil
+}
+
+func (m *h2MockRuleApplier) HasRequestActions() bool {
+	return false
+}
+
 func (m *h2MockRuleApplier) ApplyRequestBodyOnlyRules(body []byte, headers Headers) ([]byte, error) {
 	if m.reqBodyMod != nil {
 		return m.reqBodyMod(body), nil
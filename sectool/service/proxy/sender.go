@@ -36,6 +36,26 @@ type Sender struct {
 	// Timeouts holds configurable timeout values for dial, read, and write.
 	// Zero values mean no timeout.
 	Timeouts TimeoutConfig
+
+	// HostResolution pins hostnames (lowercase) to IP addresses for dialing.
+	// TLS SNI and the Host header keep the original hostname.
+	HostResolution map[string]string
+}
+
+// OverrideDialAddr replaces the host in a host:port address with its pinned IP
+// from hostResolution, returning addr unchanged when the host is not listed.
+func OverrideDialAddr(addr string, hostResolution map[string]string) string {
+	if len(hostResolution) == 0 {
+		return addr
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if ip, ok := hostResolution[strings.ToLower(host)]; ok {
+		return net.JoinHostPort(ip, port)
+	}
+	return addr
 }
 
 // SendOptions configures request sending.
@@ -64,8 +84,16 @@ type Modifications struct {
 }
 
 type SendResult struct {
-	Response *RawHTTP1Response
-	Duration time.Duration
+	Response  *RawHTTP1Response
+	Duration  time.Duration
+	Redirects []RedirectHop // Redirects followed before Response, in order
+}
+
+// RedirectHop is one redirect followed by SendWithRedirects.
+type RedirectHop struct {
+	Status   int    // Redirect status code
+	URL      string // URL that answered with the redirect
+	Location string // Resolved URL the redirect pointed to
 }
 
 // prepareRequest parses raw request bytes, applies modifications, and optionally validates.
@@ -150,6 +178,7 @@ func (s *Sender) SendWithRedirects(ctx context.Context, opts SendOptions) (*Send
 		currentPath = req.Path + "?" + req.Query
 	}
 
+	var hops []RedirectHop
 	for i := 0; i < maxRedirects; i++ {
 		resp, err := s.sendRequestWithProtocol(ctx, currentReq, currentTarget, currentProtocol)
 		if err != nil {
@@ -159,29 +188,38 @@ func (s *Sender) SendWithRedirects(ctx context.Context, opts SendOptions) (*Send
 		// Check for redirect
 		if resp.StatusCode < 300 || resp.StatusCode >= 400 {
 			return &SendResult{
-				Response: resp,
-				Duration: time.Since(start),
+				Response:  resp,
+				Duration:  time.Since(start),
+				Redirects: hops,
 			}, nil
 		}
 
 		location := resp.GetHeader("Location")
 		if location == "" {
 			return &SendResult{
-				Response: resp,
-				Duration: time.Since(start),
+				Response:  resp,
+				Duration:  time.Since(start),
+				Redirects: hops,
 			}, nil
 		}
 
 		// Build redirect request
-		var newTarget Target
-		currentReq, newTarget, currentPath, err = buildRedirectRequest(currentReq, location, currentTarget, currentPath, resp.StatusCode)
+		redirectURL := targetURL(currentTarget, currentPath)
+		nextReq, newTarget, nextPath, err := buildRedirectRequest(currentReq, location, currentTarget, currentPath, resp.StatusCode)
 		if err != nil {
 			// Can't follow redirect, return current response
 			return &SendResult{
-				Response: resp,
-				Duration: time.Since(start),
+				Response:  resp,
+				Duration:  time.Since(start),
+				Redirects: hops,
 			}, nil
 		}
+		currentReq, currentPath = nextReq, nextPath
+		hops = append(hops, RedirectHop{
+			Status:   resp.StatusCode,
+			URL:      redirectURL,
+			Location: targetURL(newTarget, currentPath),
+		})
 
 		// Check for cross-origin redirect (different scheme, host, or port).
 		// Per spec, cross-origin includes scheme/port changes for header stripping.
@@ -218,7 +256,7 @@ func (s *Sender) sendRequestWithProtocol(ctx context.Context, req *RawHTTP1Reque
 		return nil, errors.New("HTTP/2 requires HTTPS; cannot send h2 request to non-TLS target")
 	}
 
-	targetAddr := fmt.Sprintf("%s:%d", target.Hostname, target.Port)
+	targetAddr := OverrideDialAddr(fmt.Sprintf("%s:%d", target.Hostname, target.Port), s.HostResolution)
 	var conn net.Conn
 	var err error
 
@@ -258,13 +296,16 @@ func (s *Sender) sendRequestWithProtocol(ctx context.Context, req *RawHTTP1Reque
 			if negotiated == "h2" {
 				// Send as HTTP/2 — set combined deadline since H2 multiplexes reads/writes
 				defer func() { _ = conn.Close() }()
+				stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+				defer stop()
 				if s.Timeouts.ReadTimeout > 0 {
 					_ = conn.SetReadDeadline(time.Now().Add(s.Timeouts.ReadTimeout))
 				}
 				if s.Timeouts.WriteTimeout > 0 {
 					_ = conn.SetWriteDeadline(time.Now().Add(s.Timeouts.WriteTimeout))
 				}
-				return s.sendH2Request(ctx, conn, req, target)
+				resp, err := s.sendH2Request(ctx, conn, req, target)
+				return resp, contextErr(ctx, err)
 			}
 			// Server doesn't support H2, return error
 			_ = conn.Close()
@@ -278,6 +319,8 @@ func (s *Sender) sendRequestWithProtocol(ctx context.Context, req *RawHTTP1Reque
 		return nil, fmt.Errorf("connect to %s: %w", targetAddr, err)
 	}
 	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
 
 	// Send request as HTTP/1.1
 	if s.Timeouts.WriteTimeout > 0 {
@@ -285,7 +328,7 @@ func (s *Sender) sendRequestWithProtocol(ctx context.Context, req *RawHTTP1Reque
 	}
 	var buf bytes.Buffer
 	if _, err := conn.Write(req.SerializeRaw(&buf, false)); err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
+		return nil, fmt.Errorf("send request: %w", contextErr(ctx, err))
 	}
 
 	if s.Timeouts.ReadTimeout > 0 {
@@ -293,12 +336,34 @@ func (s *Sender) sendRequestWithProtocol(ctx context.Context, req *RawHTTP1Reque
 	}
 	resp, err := parseResponse(bufio.NewReader(conn), req.Method)
 	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+		return nil, fmt.Errorf("read response: %w", contextErr(ctx, err))
 	}
 
 	return resp, nil
 }
 
+// targetURL formats the absolute URL of path on target, omitting default ports.
+func targetURL(target Target, path string) string {
+	scheme, defaultPort := "http", 80
+	if target.UsesHTTPS {
+		scheme, defaultPort = "https", 443
+	}
+	host := target.Hostname
+	if target.Port != defaultPort {
+		host = net.JoinHostPort(target.Hostname, strconv.Itoa(target.Port))
+	}
+	return scheme + "://" + host + path
+}
+
+// contextErr returns ctx's error in place of err when the context ended, since the
+// connection is closed on cancellation and err is then just the resulting I/O failure.
+func contextErr(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
 // applyModifications applies all modifications to a request.
 // When force is true, Content-Length is not auto-updated on body changes
 // (allows testing scenarios like request smuggling).
@@ -393,7 +458,7 @@ func (s *Sender) sendRawRequest(ctx context.Context, opts SendOptions) (*RawHTTP
 	}
 	method := req.Method
 
-	targetAddr := fmt.Sprintf("%s:%d", opts.Target.Hostname, opts.Target.Port)
+	targetAddr := OverrideDialAddr(fmt.Sprintf("%s:%d", opts.Target.Hostname, opts.Target.Port), s.HostResolution)
 	var conn net.Conn
 
 	if opts.Target.UsesHTTPS {
@@ -415,21 +480,31 @@ func (s *Sender) sendRawRequest(ctx context.Context, opts SendOptions) (*RawHTTP
 		return nil, fmt.Errorf("connect to %s: %w", targetAddr, err)
 	}
 	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
 
 	// Send raw request bytes directly
 	if s.Timeouts.WriteTimeout > 0 {
 		_ = conn.SetWriteDeadline(time.Now().Add(s.Timeouts.WriteTimeout))
 	}
 	if _, err := conn.Write(opts.RawRequest); err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
+		return nil, fmt.Errorf("send request: %w", contextErr(ctx, err))
 	}
 
+	// Honor the context deadline when sooner, so timing probes can bound the wait
+	var readDeadline time.Time
 	if s.Timeouts.ReadTimeout > 0 {
-		_ = conn.SetReadDeadline(time.Now().Add(s.Timeouts.ReadTimeout))
+		readDeadline = time.Now().Add(s.Timeouts.ReadTimeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (readDeadline.IsZero() || d.Before(readDeadline)) {
+		readDeadline = d
+	}
+	if !readDeadline.IsZero() {
+		_ = conn.SetReadDeadline(readDeadline)
 	}
 	resp, err := parseResponse(bufio.NewReader(conn), method)
 	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+		return nil, fmt.Errorf("read response: %w", contextErr(ctx, err))
 	}
 
 	return resp, nil
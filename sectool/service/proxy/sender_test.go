@@ -1,7 +1,10 @@
 package proxy
 
 import (
+	"context"
+	"errors"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -288,6 +291,56 @@ func TestSender_Send(t *testing.T) {
 	})
 }
 
+func TestSender_Send_Context(t *testing.T) {
+	t.Parallel()
+
+	// hangingTarget accepts connections and never responds
+	hangingTarget := func(t *testing.T) Target {
+		t.Helper()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		accepted := make(chan net.Conn, 1)
+		t.Cleanup(func() {
+			_ = ln.Close()
+			select {
+			case conn := <-accepted:
+				_ = conn.Close()
+			default:
+			}
+		})
+		go func() {
+			if conn, err := ln.Accept(); err == nil {
+				accepted <- conn
+			}
+		}()
+		return Target{Hostname: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port}
+	}
+	rawReq := []byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n")
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := (&Sender{}).Send(ctx, SendOptions{RawRequest: rawReq, Target: hangingTarget(t)})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, err := (&Sender{}).Send(ctx, SendOptions{RawRequest: rawReq, Target: hangingTarget(t)})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
+
 func TestSender_SendWithRedirects(t *testing.T) {
 	t.Parallel()
 
@@ -325,6 +378,9 @@ func TestSender_SendWithRedirects(t *testing.T) {
 		assert.Equal(t, 200, result.Response.StatusCode)
 		assert.Equal(t, []byte("Final destination"), result.Response.Body)
 		assert.Equal(t, 1, redirectCount)
+		assert.Equal(t, []RedirectHop{
+			{Status: 302, URL: "http://" + serverURL.Host + "/redirect", Location: "http://" + serverURL.Host + "/final"},
+		}, result.Redirects)
 	})
 
 	t.Run("max_redirects", func(t *testing.T) {
@@ -521,6 +577,34 @@ func TestPathWithoutQuery(t *testing.T) {
 	}
 }
 
+func TestOverrideDialAddr(t *testing.T) {
+	t.Parallel()
+
+	resolution := map[string]string{"api.example.com": "10.0.0.5", "v6.example.com": "::1"}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"pinned", "api.example.com:443", "10.0.0.5:443"},
+		{"case_insensitive", "API.Example.com:8080", "10.0.0.5:8080"},
+		{"ipv6_target", "v6.example.com:443", "[::1]:443"},
+		{"unpinned", "other.example.com:443", "other.example.com:443"},
+		{"no_port", "api.example.com", "api.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, OverrideDialAddr(tt.input, resolution))
+		})
+	}
+
+	t.Run("nil_map", func(t *testing.T) {
+		assert.Equal(t, "api.example.com:443", OverrideDialAddr("api.example.com:443", nil))
+	})
+}
+
 func TestQueryFromPath(t *testing.T) {
 	t.Parallel()
 
@@ -753,6 +837,35 @@ func TestSender_ValidationBypass(t *testing.T) {
 		assert.Equal(t, 200, result.Response.StatusCode)
 		assert.Equal(t, "CUSTOMMETHOD", receivedMethod)
 	})
+
+	t.Run("force_honors_context_deadline", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+		// Hold the connection open without responding
+		accepted := make(chan net.Conn, 1)
+		go func() {
+			if conn, err := ln.Accept(); err == nil {
+				accepted <- conn
+			}
+		}()
+
+		addr := ln.Addr().(*net.TCPAddr)
+		ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err = (&Sender{}).Send(ctx, SendOptions{
+			RawRequest: []byte("POST / HTTP/1.1\r\nHost: localhost\r\nContent-Length: 6\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\nX"),
+			Target:     Target{Hostname: "127.0.0.1", Port: addr.Port},
+			Force:      true,
+		})
+		require.Error(t, err)
+		var netErr net.Error
+		assert.True(t, errors.As(err, &netErr) && netErr.Timeout())
+		assert.Less(t, time.Since(start), 5*time.Second)
+		_ = (<-accepted).Close()
+	})
 }
 
 func TestApplyModifications(t *testing.T) {
@@ -48,7 +48,8 @@ type ProxyServer struct {
 	wg          sync.WaitGroup
 	closed      atomic.Bool
 	running     atomic.Bool
-	activeConns sync.Map // tracks active connections for force-close on shutdown
+	halted      atomic.Bool // kill switch: drop client connections without forwarding
+	activeConns sync.Map    // tracks active connections for force-close on shutdown
 }
 
 // NewProxyServer creates a new proxy server with HTTPS MITM support.
@@ -124,6 +125,13 @@ func (s *ProxyServer) SetRuleApplier(applier RuleApplier) {
 	s.wsHandler.SetRuleApplier(applier)
 }
 
+// SetRequestHolder sets the request holder used for intercept.
+// Call after construction but before Serve().
+func (s *ProxyServer) SetRequestHolder(holder RequestHolder) {
+	s.http1Handler.holder = holder
+	s.connectHandler.holder = holder
+}
+
 // WaitReady blocks until Serve() has entered its accept loop.
 func (s *ProxyServer) WaitReady(ctx context.Context) error {
 	for !s.running.Load() {
@@ -165,8 +173,28 @@ func (s *ProxyServer) Serve() error {
 	}
 }
 
+// SetHalted makes the proxy drop client connections without forwarding them. Halting also
+// closes connections already open, aborting their in-flight requests.
+func (s *ProxyServer) SetHalted(halted bool) {
+	s.halted.Store(halted)
+	if !halted {
+		return
+	}
+	s.activeConns.Range(func(key, _ any) bool {
+		if conn, ok := key.(net.Conn); ok {
+			_ = conn.Close()
+		}
+		return true
+	})
+}
+
 // handleConnection determines the protocol and routes to the appropriate handler.
 func (s *ProxyServer) handleConnection(conn net.Conn) {
+	if s.halted.Load() {
+		log.Printf("proxy: halted, dropping connection from %s", conn.RemoteAddr())
+		_ = conn.Close()
+		return
+	}
 	s.activeConns.Store(conn, struct{}{})
 	defer func() {
 		s.activeConns.Delete(conn)
@@ -460,6 +460,14 @@ func (t *trackingRuleApplier) HasBodyRules(isRequest bool) bool {
 	return false
 }
 
+func (t *trackingRuleApplier) MatchRequestAction(host string, req *RawHTTP1Request) *RequestAction {
+	return nil
+}
+
+func (t *trackingRuleApplier) HasRequestActions() bool {
+	return false
+}
+
 func TestServeContextCancellation(t *testing.T) {
 	t.Parallel()
 
@@ -748,3 +756,33 @@ func TestHTTP11KeepAlive(t *testing.T) {
 		testutil.WaitForCount(t, func() int { return proxy.History().Count() }, 2)
 	})
 }
+
+func TestProxyServerHalted(t *testing.T) {
+	t.Parallel()
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(testServer.Close)
+
+	proxy, client := setupProxyClient(t)
+	client.Transport.(*http.Transport).DisableKeepAlives = true
+
+	get := func() error {
+		req, _ := http.NewRequestWithContext(t.Context(), "GET", testServer.URL+"/", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.Body.Close()
+	}
+
+	proxy.SetHalted(true)
+	require.Error(t, get())
+	assert.Equal(t, 0, proxy.History().Count())
+
+	proxy.SetHalted(false)
+	require.NoError(t, get())
+	testutil.WaitForCount(t, func() int { return proxy.History().Count() }, 1)
+}
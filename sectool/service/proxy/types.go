@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -242,6 +243,18 @@ type Target struct {
 	UsesHTTPS bool
 }
 
+// RequestHolder holds HTTP/1.x requests until they are released, for intercept.
+// Implemented by the service layer (NativeProxyBackend).
+type RequestHolder interface {
+	// Intercepting reports whether requests may currently be held. New TLS connections
+	// negotiate HTTP/1.1 while it does, since HTTP/2 streams are not held.
+	Intercepting() bool
+
+	// HoldRequest blocks while req to host is held, returning true to forward it or false
+	// to drop it. Requests not being intercepted return true at once.
+	HoldRequest(ctx context.Context, host string, req *RawHTTP1Request) bool
+}
+
 // RuleApplier applies match/replace rules to requests and responses.
 // Implemented by the service layer (NativeProxyBackend).
 // Rules are applied in the order they were added (list order).
@@ -275,4 +288,19 @@ type RuleApplier interface {
 	// Used by HTTP/2 handler to decide whether to buffer full bodies.
 	// isRequest=true checks for request_body rules, false checks for response_body rules.
 	HasBodyRules(isRequest bool) bool
+
+	// MatchRequestAction returns the first block or redirect rule matching a request to
+	// host, or nil to forward it. Checked after request rules are applied.
+	MatchRequestAction(host string, req *RawHTTP1Request) *RequestAction
+
+	// HasRequestActions returns true if there are block or redirect rules.
+	// While there are, TLS connections negotiate HTTP/1.1, since HTTP/2 streams are
+	// forwarded without being answered by the proxy.
+	HasRequestActions() bool
+}
+
+// RequestAction is how a block or redirect rule answers a request in place of forwarding it.
+type RequestAction struct {
+	RuleID   string
+	Location string // redirect target; empty blocks the request
 }
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-analyze/bulk"
+)
+
+// flowSearchIndex is an inverted index from lowercase ASCII alphanumeric tokens to
+// the positions of the flows containing them. It narrows literal searches to
+// candidate flows which are then verified with the full regex, so it only needs
+// to return a superset of the real matches.
+type flowSearchIndex struct {
+	postings map[string][]int // token -> ascending flow positions
+}
+
+func newFlowSearchIndex() *flowSearchIndex {
+	return &flowSearchIndex{postings: make(map[string][]int)}
+}
+
+// searchTerm is a token from a search literal. Edge tokens may continue past the
+// literal in the matched text, so they match index tokens by prefix/suffix.
+type searchTerm struct {
+	text      string
+	leftOpen  bool // literal starts inside this token
+	rightOpen bool // literal ends inside this token
+}
+
+// flowSearchStats tracks search activity and latency for a crawl session.
+type flowSearchStats struct {
+	searches        int
+	indexedSearches int
+	scanSearches    int
+	totalLatency    time.Duration
+	maxLatency      time.Duration
+}
+
+func (s *flowSearchStats) record(indexed bool, latency time.Duration) {
+	s.searches++
+	if indexed {
+		s.indexedSearches++
+	} else {
+		s.scanSearches++
+	}
+	s.totalLatency += latency
+	s.maxLatency = max(s.maxLatency, latency)
+}
+
+// scanSearchTokens calls fn for each run of ASCII letters and digits in data, lowercased.
+func scanSearchTokens(data []byte, fn func(token string, start, end int)) {
+	start := -1
+	for i := 0; i <= len(data); i++ {
+		if i < len(data) && isSearchTokenByte(data[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		} else if start >= 0 {
+			fn(strings.ToLower(string(data[start:i])), start, i)
+			start = -1
+		}
+	}
+}
+
+func isSearchTokenByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// flowSearchTokens returns the distinct tokens of a flow from the same sections
+// matchesFlowSearch inspects: raw headers and display-decoded bodies.
+func flowSearchTokens(request, response []byte) []string {
+	seen := make(map[string]bool)
+	add := func(token string, _, _ int) {
+		seen[token] = true
+	}
+	for _, msg := range [][]byte{request, response} {
+		headers, body := splitHeadersBody(msg)
+		body, _ = decompressForDisplay(body, string(headers))
+		scanSearchTokens(headers, add)
+		scanSearchTokens(body, add)
+	}
+	return bulk.MapKeysSlice(seen)
+}
+
+// add records tokens for the flow at pos. Positions must be added in ascending order.
+func (x *flowSearchIndex) add(pos int, tokens []string) {
+	for _, token := range tokens {
+		x.postings[token] = append(x.postings[token], pos)
+	}
+}
+
+// remove drops the flows at the ascending positions removed, renumbering later
+// positions so they stay aligned with the remaining flows.
+func (x *flowSearchIndex) remove(removed []int) {
+	for token, positions := range x.postings {
+		kept := positions[:0]
+		for _, pos := range positions {
+			if below, found := slices.BinarySearch(removed, pos); !found {
+				kept = append(kept, pos-below)
+			}
+		}
+		if len(kept) == 0 {
+			delete(x.postings, token)
+		} else {
+			x.postings[token] = kept
+		}
+	}
+}
+
+// tokenCount returns the number of distinct indexed tokens.
+func (x *flowSearchIndex) tokenCount() int {
+	return len(x.postings)
+}
+
+// flowCandidates returns ascending positions of flows that may match either regex,
+// mirroring matchesFlowSearch. ok is false when a pattern is not a plain literal
+// and a full scan is required.
+func (x *flowSearchIndex) flowCandidates(headerRe, bodyRe *regexp.Regexp) (positions []int, ok bool) {
+	for _, re := range []*regexp.Regexp{headerRe, bodyRe} {
+		if re == nil {
+			continue
+		}
+		matched, ok := x.candidates(re)
+		if !ok {
+			return nil, false
+		}
+		positions = unionSorted(positions, matched)
+	}
+	return positions, true
+}
+
+// candidates returns ascending positions of flows containing every term of a literal regex.
+func (x *flowSearchIndex) candidates(re *regexp.Regexp) ([]int, bool) {
+	literal, ok := searchLiteral(re)
+	if !ok {
+		return nil, false
+	}
+	var terms []searchTerm
+	scanSearchTokens([]byte(literal), func(token string, start, end int) {
+		terms = append(terms, searchTerm{text: token, leftOpen: start == 0, rightOpen: end == len(literal)})
+	})
+	if len(terms) == 0 {
+		return nil, false // only separators; the index cannot narrow this
+	}
+
+	var result []int
+	for i, term := range terms {
+		positions := x.termPostings(term)
+		if i == 0 {
+			result = positions
+		} else {
+			result = intersectSorted(result, positions)
+		}
+		if len(result) == 0 {
+			return nil, true
+		}
+	}
+	return result, true
+}
+
+// termPostings returns positions for a term, expanding open edges against the vocabulary.
+func (x *flowSearchIndex) termPostings(term searchTerm) []int {
+	if !term.leftOpen && !term.rightOpen {
+		return x.postings[term.text]
+	}
+	var result []int
+	for token, positions := range x.postings {
+		var match bool
+		switch {
+		case term.leftOpen && term.rightOpen:
+			match = strings.Contains(token, term.text)
+		case term.leftOpen:
+			match = strings.HasSuffix(token, term.text)
+		default:
+			match = strings.HasPrefix(token, term.text)
+		}
+		if match {
+			result = append(result, positions...)
+		}
+	}
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// searchLiteral reports the literal text a regex matches, if it is a plain
+// (optionally case-insensitive ASCII) literal.
+func searchLiteral(re *regexp.Regexp) (string, bool) {
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	parsed = parsed.Simplify()
+	if parsed.Op != syntax.OpLiteral {
+		return "", false
+	}
+	literal := string(parsed.Rune)
+	if parsed.Flags&syntax.FoldCase != 0 {
+		// The index only folds ASCII case; leave non-ASCII folding to the regex scan
+		for _, r := range literal {
+			if r > 0x7f {
+				return "", false
+			}
+		}
+	}
+	return literal, true
+}
+
+func intersectSorted(a, b []int) []int {
+	var result []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+func unionSorted(a, b []int) []int {
+	result := make([]int, 0, len(a)+len(b))
+	result = append(result, a...)
+	result = append(result, b...)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/service/proxy"
+)
+
+func TestFlowSearchIndex_Candidates(t *testing.T) {
+	t.Parallel()
+
+	gzipped, err := proxy.Compress([]byte(`{"apiKey":"sk_live_123"}`), "gzip")
+	require.NoError(t, err)
+
+	flows := [][2][]byte{
+		{[]byte("GET /admin HTTP/1.1\r\nHost: a.com\r\n\r\n"), []byte("HTTP/1.1 200 OK\r\n\r\nWelcome Administrator")},
+		{[]byte("GET /login HTTP/1.1\r\nHost: a.com\r\nX-Debug: on\r\n\r\n"), []byte("HTTP/1.1 302 Found\r\nLocation: /home\r\n\r\n")},
+		{[]byte("GET /api HTTP/1.1\r\nHost: a.com\r\n\r\n"), append([]byte("HTTP/1.1 200 OK\r\nContent-Encoding: gzip\r\n\r\n"), gzipped...)},
+	}
+	index := newFlowSearchIndex()
+	for i, f := range flows {
+		index.add(i, flowSearchTokens(f[0], f[1]))
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		body   string
+		want   []int
+		wantOK bool
+	}{
+		{name: "exact_interior_token", body: "Welcome Administrator", want: []int{0}, wantOK: true},
+		{name: "partial_token", body: "dmin", want: []int{0}, wantOK: true},
+		{name: "prefix_and_suffix_edges", body: "come Admin", want: []int{0}, wantOK: true},
+		{name: "case_insensitive_header", header: "x-debug", want: []int{1}, wantOK: true},
+		{name: "decompressed_body", body: "sk_live", want: []int{2}, wantOK: true},
+		{name: "no_match", body: "nonexistent", want: []int{}, wantOK: true},
+		{name: "either_pattern", header: "Location", body: "apiKey", want: []int{1, 2}, wantOK: true},
+		{name: "regex_falls_back", body: `admin\w+`, wantOK: false},
+		{name: "separator_only_falls_back", body: "://", wantOK: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var headerRe, bodyRe *regexp.Regexp
+			if tc.header != "" {
+				headerRe, _ = compileSearchPattern(tc.header, true)
+			}
+			if tc.body != "" {
+				bodyRe, _ = compileSearchPattern(tc.body, false)
+			}
+
+			got, ok := index.flowCandidates(headerRe, bodyRe)
+			require.Equal(t, tc.wantOK, ok)
+			if ok {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCollyBackend_ListFlows_indexed_search(t *testing.T) {
+	t.Parallel()
+
+	flows := make([]*CrawlFlow, 20)
+	for i := range flows {
+		body := "nothing here"
+		if i%5 == 0 {
+			body = fmt.Sprintf("user token=abc%d", i)
+		}
+		flows[i] = &CrawlFlow{ID: fmt.Sprintf("flow-%d", i), Host: "a.com", Path: fmt.Sprintf("/%d", i),
+			Method: "GET", StatusCode: 200,
+			Request:  []byte(fmt.Sprintf("GET /%d HTTP/1.1\r\nHost: a.com\r\n\r\n", i)),
+			Response: []byte("HTTP/1.1 200 OK\r\n\r\n" + body)}
+	}
+	b, sessionID := newTestCollySession(t, flows)
+	ctx := t.Context()
+
+	literal, _ := compileSearchPattern("token=abc", false)
+	indexed, err := b.ListFlows(ctx, sessionID, CrawlListOptions{SearchBodyRe: literal})
+	require.NoError(t, err)
+	regex, _ := compileSearchPattern(`token=abc\d+`, false)
+	scanned, err := b.ListFlows(ctx, sessionID, CrawlListOptions{SearchBodyRe: regex})
+	require.NoError(t, err)
+
+	require.Len(t, indexed, 4)
+	assert.Equal(t, indexed, scanned)
+	assert.Equal(t, "flow-0", indexed[0].ID)
+	assert.Equal(t, "flow-15", indexed[3].ID)
+
+	stats, err := b.GetStats(ctx, sessionID)
+	require.NoError(t, err)
+	assert.True(t, stats.Search.IndexEnabled)
+	assert.Equal(t, 2, stats.Search.Searches)
+	assert.Equal(t, 1, stats.Search.IndexedSearches)
+	assert.Equal(t, 1, stats.Search.ScanSearches)
+	assert.Positive(t, stats.Search.IndexedTokens)
+}
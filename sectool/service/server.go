@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -41,6 +43,7 @@ type Server struct {
 
 	// Runtime state
 	mcpServer *mcpServer
+	pidPath   string // PID file written once the MCP server listens, removed on shutdown
 	started   chan struct{}
 	startedAt time.Time
 
@@ -75,6 +78,9 @@ type Server struct {
 	// Used for "since=last" cursor to support both proxy and replay entries.
 	lastFlowID atomic.Value // stores string
 
+	// Emergency stop for all target traffic
+	killSwitch killSwitch
+
 	// Shutdown coordination
 	shutdownCh chan struct{}
 	wg         sync.WaitGroup
@@ -174,7 +180,11 @@ func (s *Server) Run(ctx context.Context) error {
 
 	// Setup Crawler backend
 	if s.crawlerBackend == nil {
-		s.crawlerBackend = NewCollyBackend(s.cfg, s.proxyIndex, s.httpBackend)
+		crawlCfg := *s.cfg
+		if crawlCfg.Crawler.PersistDir == "" { // next to the config file in use
+			crawlCfg.Crawler.PersistDir = filepath.Join(filepath.Dir(s.configPath), "crawl")
+		}
+		s.crawlerBackend = NewCollyBackend(&crawlCfg, s.proxyIndex, s.httpBackend)
 	}
 
 	// Start MCP server
@@ -185,6 +195,7 @@ func (s *Server) Run(ctx context.Context) error {
 
 	markStarted()
 	log.Printf("MCP server listening on http://%s/mcp", s.mcpServer.Addr())
+	s.writePIDFile()
 	s.printMCPConfig()
 
 	select {
@@ -241,6 +252,8 @@ func (s *Server) shutdown() error {
 	// Remove shared temp directory
 	_ = os.RemoveAll(s.storageTempDir)
 
+	s.removePIDFile()
+
 	log.Printf("sectool MCP server stopped")
 	return nil
 }
@@ -252,6 +265,18 @@ func (s *Server) RegisterHealthMetric(key string, provider HealthMetricProvider)
 	s.metricProvider[key] = provider
 }
 
+// HealthMetrics returns the current value of each registered health metric.
+func (s *Server) HealthMetrics() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	metrics := make(map[string]string, len(s.metricProvider))
+	for key, provider := range s.metricProvider {
+		metrics[key] = provider()
+	}
+	return metrics
+}
+
 // RequestShutdown initiates server shutdown.
 func (s *Server) RequestShutdown() {
 	select {
@@ -262,6 +287,32 @@ func (s *Server) RequestShutdown() {
 	}
 }
 
+// writePIDFile records the process ID under the MCP port, so `sectool stop` can confirm
+// a local process belongs to this server before signalling it.
+func (s *Server) writePIDFile() {
+	_, port, err := net.SplitHostPort(s.mcpServer.Addr())
+	if err != nil {
+		return
+	}
+	mcpPort, _ := strconv.Atoi(port)
+	s.pidPath = config.PIDPath(s.configPath, mcpPort)
+	if err := os.WriteFile(s.pidPath, []byte(strconv.Itoa(os.Getpid())+"\n"), 0600); err != nil {
+		log.Printf("warning: failed to write PID file: %v", err)
+		s.pidPath = ""
+	}
+}
+
+// removePIDFile deletes the PID file unless another server has since replaced it.
+func (s *Server) removePIDFile() {
+	if s.pidPath == "" {
+		return
+	}
+	data, err := os.ReadFile(s.pidPath)
+	if err == nil && strings.TrimSpace(string(data)) == strconv.Itoa(os.Getpid()) {
+		_ = os.Remove(s.pidPath)
+	}
+}
+
 // loadOrCreateConfig loads config and applies CLI flag overrides.
 // Precedence: CLI flags > config file > defaults
 func (s *Server) loadOrCreateConfig() error {
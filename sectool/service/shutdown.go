@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// drainPollInterval is how often running crawls are checked while draining.
+const drainPollInterval = 100 * time.Millisecond
+
+// DrainStatus reports how running crawls were wound down before shutdown.
+type DrainStatus struct {
+	FinishedCrawls int // finished on their own within the drain timeout
+	StoppedCrawls  int // still running at the timeout and stopped
+}
+
+// DrainAndShutdown waits up to timeout for running crawls to finish, stops any that
+// are still running, and then requests server shutdown.
+func (s *Server) DrainAndShutdown(ctx context.Context, timeout time.Duration) DrainStatus {
+	defer s.RequestShutdown()
+
+	var status DrainStatus
+	if s.crawlerBackend == nil {
+		return status
+	}
+
+	running := s.runningCrawls(ctx)
+	initial := len(running)
+	deadline := time.Now().Add(timeout)
+	for len(running) > 0 && time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			deadline = time.Now()
+		case <-time.After(drainPollInterval):
+			running = s.runningCrawls(ctx)
+		}
+	}
+
+	for _, id := range running {
+		if err := s.crawlerBackend.StopSession(ctx, id); err != nil {
+			log.Printf("shutdown: failed to stop crawl session %s: %v", id, err)
+			continue
+		}
+		status.StoppedCrawls++
+	}
+	status.FinishedCrawls = max(initial-len(running), 0)
+	log.Printf("shutdown: %d crawls finished, %d stopped", status.FinishedCrawls, status.StoppedCrawls)
+	return status
+}
+
+// runningCrawls returns the IDs of crawl sessions still running.
+func (s *Server) runningCrawls(ctx context.Context) []string {
+	sessions, err := s.crawlerBackend.ListSessions(ctx, 0)
+	if err != nil {
+		log.Printf("shutdown: failed to list crawl sessions: %v", err)
+		return nil
+	}
+	var ids []string
+	for _, sess := range sessions {
+		if sess.State == crawlStateRunning {
+			ids = append(ids, sess.ID)
+		}
+	}
+	return ids
+}
@@ -30,9 +30,17 @@ type ReplayHistoryMeta struct {
 
 // ReplayHistoryPayload holds the heavy request/response data for a replay entry.
 type ReplayHistoryPayload struct {
-	RawRequest  []byte `msgpack:"rq"`
-	RespHeaders []byte `msgpack:"rh"`
-	RespBody    []byte `msgpack:"rb"`
+	RawRequest  []byte           `msgpack:"rq"`
+	RespHeaders []byte           `msgpack:"rh"`
+	RespBody    []byte           `msgpack:"rb"`
+	Redirects   []ReplayRedirect `msgpack:"rd,omitempty"`
+}
+
+// ReplayRedirect is one redirect followed while sending a replay.
+type ReplayRedirect struct {
+	Status   int    `msgpack:"s"`
+	URL      string `msgpack:"u"` // URL that answered with the redirect
+	Location string `msgpack:"l"` // Resolved URL it pointed to
 }
 
 // ReplayHistoryEntry stores a replay request/response with positioning info.
@@ -53,6 +61,7 @@ type ReplayHistoryEntry struct {
 	RespBody    []byte
 	RespStatus  int
 	Duration    time.Duration
+	Redirects   []ReplayRedirect // Redirects followed before the stored response
 
 	// Lineage
 	SourceFlowID string // Original flow_id that was replayed (empty for request_send)
@@ -99,6 +108,7 @@ func (s *ReplayHistoryStore) Store(entry *ReplayHistoryEntry) {
 		RawRequest:  entry.RawRequest,
 		RespHeaders: entry.RespHeaders,
 		RespBody:    entry.RespBody,
+		Redirects:   entry.Redirects,
 	}
 
 	if metaData, err := Serialize(&meta); err != nil {
@@ -161,6 +171,7 @@ func (s *ReplayHistoryStore) getLocked(flowID string) (*ReplayHistoryEntry, bool
 		RespBody:        payload.RespBody,
 		RespStatus:      meta.RespStatus,
 		Duration:        meta.Duration,
+		Redirects:       payload.Redirects,
 		SourceFlowID:    meta.SourceFlowID,
 	}, true
 }
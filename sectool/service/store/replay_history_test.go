@@ -38,6 +38,23 @@ func TestReplayHistoryStore(t *testing.T) {
 		assert.False(t, got.CreatedAt.IsZero())
 	})
 
+	t.Run("store_redirects", func(t *testing.T) {
+		storage := NewMemStorage()
+		t.Cleanup(func() { _ = storage.Close() })
+		store := NewReplayHistoryStore(storage)
+
+		redirects := []ReplayRedirect{
+			{Status: 302, URL: "https://example.com/a", Location: "https://example.com/b"},
+			{Status: 301, URL: "https://example.com/b", Location: "https://example.com/c"},
+		}
+		store.Store(&ReplayHistoryEntry{FlowID: "redir", SourceFlowID: "src1", Redirects: redirects})
+
+		got, ok := store.Get("redir")
+		require.True(t, ok)
+		assert.Equal(t, redirects, got.Redirects)
+		assert.Equal(t, "src1", got.SourceFlowID)
+	})
+
 	t.Run("get_not_found", func(t *testing.T) {
 		storage := NewMemStorage()
 		t.Cleanup(func() { _ = storage.Close() })
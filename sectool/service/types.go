@@ -17,10 +17,15 @@ const (
 
 // Output mode constants for poll tools.
 const (
-	OutputModeFlows   = "flows"
-	OutputModeSummary = "summary"
-	OutputModeForms   = "forms"
-	OutputModeErrors  = "errors"
+	OutputModeFlows     = "flows"
+	OutputModeSummary   = "summary"
+	OutputModeForms     = "forms"
+	OutputModeErrors    = "errors"
+	OutputModeExternal  = "external"
+	OutputModeRoutes    = "routes"
+	OutputModeEndpoints = "endpoints"
+	OutputModeSimilar   = "similar"
+	OutputModeFindings  = "findings"
 )
 
 // HealthMetricProvider is a function that returns a metric value for a given key.
@@ -117,13 +122,21 @@ func formsToAPI(forms []DiscoveredForm) []protocol.CrawlForm {
 		for _, inp := range f.Inputs {
 			inputs = append(inputs, protocol.FormInput(inp))
 		}
+		var methodCheck *protocol.FormMethodCheck
+		if f.MethodCheck != nil {
+			methodCheck = (*protocol.FormMethodCheck)(f.MethodCheck)
+		}
 		result = append(result, protocol.CrawlForm{
-			FormID:  f.ID,
-			URL:     f.URL,
-			Action:  f.Action,
-			Method:  f.Method,
-			HasCSRF: f.HasCSRF,
-			Inputs:  inputs,
+			FormID:            f.ID,
+			URL:               f.URL,
+			Action:            f.Action,
+			Method:            f.Method,
+			HasCSRF:           f.HasCSRF,
+			HasJSHandlers:     f.HasJSHandlers,
+			AutoSubmit:        f.AutoSubmit,
+			Inputs:            inputs,
+			MethodCheck:       methodCheck,
+			SubmissionFlowIDs: f.SubmissionFlowIDs,
 		})
 	}
 	return result
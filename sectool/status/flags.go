@@ -0,0 +1,38 @@
+package status
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Parse handles the "sectool status" command.
+func Parse(args []string, mcpURL string) error {
+	fs := pflag.NewFlagSet("status", pflag.ContinueOnError)
+
+	var timeout time.Duration
+	fs.DurationVar(&timeout, "timeout", 5*time.Second, "how long to wait for the server to answer")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool status [options]
+
+Report whether the sectool MCP server is running, with its PID, MCP and
+proxy ports, uptime, running crawls, and stored flow counts. Exits non-zero
+when no server answers.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	return run(mcpURL, timeout)
+}
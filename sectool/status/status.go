@@ -0,0 +1,60 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-analyze/bulk"
+
+	"github.com/go-appsec/toolbox/sectool/cliutil"
+	"github.com/go-appsec/toolbox/sectool/mcpclient"
+	"github.com/go-appsec/toolbox/sectool/protocol"
+)
+
+func run(mcpURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		fmt.Println(cliutil.BoldRed("sectool server is not running"))
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	resp, err := client.ServerStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("status failed: %w", err)
+	}
+
+	printStatus(resp)
+	return nil
+}
+
+func printStatus(resp *protocol.ServerStatusResponse) {
+	fmt.Println(cliutil.BoldGreen("sectool server is running"))
+	fmt.Println()
+	fmt.Printf("Version: %s\n", resp.Version)
+	fmt.Printf("PID: %d\n", resp.PID)
+	fmt.Printf("MCP Port: %d\n", resp.MCPPort)
+	if resp.ProxyPort != 0 {
+		fmt.Printf("Proxy Port: %d (%s)\n", resp.ProxyPort, resp.Backend)
+	} else {
+		fmt.Printf("Proxy: %s\n", resp.Backend)
+	}
+	fmt.Printf("Uptime: %s (since %s)\n", resp.Uptime, resp.StartedAt)
+	fmt.Printf("Active Crawls: %d\n", resp.ActiveCrawls)
+	if resp.KillSwitch {
+		fmt.Println(cliutil.BoldRed("Kill Switch: engaged") + " (sectool panic clear to re-enable traffic)")
+	}
+	if len(resp.Stores) > 0 {
+		keys := bulk.MapKeysSlice(resp.Stores)
+		slices.Sort(keys)
+		fmt.Println("Stores:")
+		for _, k := range keys {
+			fmt.Printf("  %s: %s\n", k, resp.Stores[k])
+		}
+	}
+}
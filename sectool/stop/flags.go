@@ -0,0 +1,45 @@
+package stop
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+// Parse handles the "sectool stop" command.
+func Parse(args []string, mcpURL, configPath string) error {
+	fs := pflag.NewFlagSet("stop", pflag.ContinueOnError)
+
+	var timeout time.Duration
+	fs.DurationVar(&timeout, "timeout", 10*time.Second, "how long running crawls may take to finish before they are stopped")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, `Usage: sectool stop [options]
+
+Shut down the sectool MCP server. Running crawls get up to --timeout to
+finish and are then stopped. Waits for the server process to exit, and
+terminates it by PID if it does not exit or stops responding. The PID is
+only signalled for a server on this machine (loopback --mcp-url) whose PID
+file in the config directory matches.
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if len(fs.Args()) > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	if configPath == "" {
+		configPath = config.DefaultPath()
+	}
+	return run(mcpURL, configPath, timeout)
+}
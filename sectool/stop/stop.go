@@ -0,0 +1,139 @@
+package stop
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-appsec/toolbox/sectool/cliutil"
+	"github.com/go-appsec/toolbox/sectool/config"
+	"github.com/go-appsec/toolbox/sectool/mcpclient"
+)
+
+const (
+	connectTimeout = 5 * time.Second
+	exitTimeout    = 15 * time.Second // exceeds the server's own shutdown timeout
+	killGrace      = 3 * time.Second
+	exitPollPeriod = 100 * time.Millisecond
+)
+
+func run(mcpURL, configPath string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	client, err := mcpclient.Connect(ctx, mcpURL)
+	if err != nil {
+		cancel()
+		fmt.Println(cliutil.BoldRed("sectool server is not running"))
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	status, err := client.ServerStatus(ctx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("status failed: %w", err)
+	}
+	pid := status.PID
+	// Checked before stopping, since the server removes its PID file on shutdown
+	localErr := checkLocalPID(mcpURL, configPath, pid)
+
+	// The server answers once crawls are drained, so allow the drain timeout plus headroom
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), timeout+connectTimeout)
+	defer stopCancel()
+	resp, err := client.ServerStop(stopCtx, timeout.String())
+	if err != nil {
+		fmt.Printf("Server (PID %d) did not answer the stop request: %v\n", pid, err)
+		if localErr != nil {
+			return fmt.Errorf("not terminating PID %d: %w", pid, localErr)
+		}
+		return terminate(pid)
+	}
+
+	fmt.Printf("Stopping sectool server (PID %d): %d crawls finished, %d stopped\n",
+		resp.PID, resp.FinishedCrawls, resp.StoppedCrawls)
+	if localErr != nil { // the process cannot be watched from here
+		fmt.Println(cliutil.Success("Stop requested"))
+		return nil
+	}
+	if waitExit(resp.PID, exitTimeout) {
+		fmt.Println(cliutil.Success("Server stopped"))
+		return nil
+	}
+	fmt.Printf("Server still running after %s\n", exitTimeout)
+	return terminate(resp.PID)
+}
+
+// checkLocalPID returns an error unless pid may be signalled from this machine: mcpURL
+// must point at a loopback address and the PID file the server wrote for that port must
+// record pid. A remote or containerized server's PID means nothing locally.
+func checkLocalPID(mcpURL, configPath string, pid int) error {
+	if mcpURL == "" {
+		mcpURL = mcpclient.DefaultMCPURL
+	}
+	u, err := url.Parse(mcpURL)
+	if err != nil {
+		return fmt.Errorf("parse MCP URL: %w", err)
+	}
+	if host := u.Hostname(); host != "localhost" {
+		if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+			return fmt.Errorf("server at %s is not on this machine", u.Host)
+		}
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		return fmt.Errorf("MCP URL %s has no port", mcpURL)
+	}
+
+	pidPath := config.PIDPath(configPath, port)
+	data, err := os.ReadFile(pidPath)
+	if err != nil {
+		return fmt.Errorf("read PID file: %w", err)
+	} else if recorded, _ := strconv.Atoi(strings.TrimSpace(string(data))); recorded != pid {
+		return fmt.Errorf("PID file %s does not record PID %d", pidPath, pid)
+	}
+	return nil
+}
+
+// terminate sends SIGTERM to pid, then kills it if it has not exited after killGrace.
+func terminate(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("find server process %d: %w", pid, err)
+	}
+	if err := proc.Signal(syscall.SIGTERM); err == nil && waitExit(pid, killGrace) {
+		fmt.Println(cliutil.Warning("Server terminated"))
+		return nil
+	}
+	if err := proc.Kill(); err != nil && processRunning(pid) {
+		return fmt.Errorf("kill server process %d: %w", pid, err)
+	}
+	fmt.Println(cliutil.Warning("Server killed"))
+	return nil
+}
+
+// waitExit polls until pid exits or timeout elapses, reporting whether it exited.
+func waitExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for processRunning(pid) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(exitPollPeriod)
+	}
+	return true
+}
+
+// processRunning reports whether pid is alive. Where signal 0 is unsupported
+// (Windows) the process is treated as exited.
+func processRunning(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
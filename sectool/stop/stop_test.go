@@ -0,0 +1,45 @@
+package stop
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-appsec/toolbox/sectool/config"
+)
+
+func TestCheckLocalPID(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(config.PIDPath(configPath, 9119), []byte("4242\n"), 0600))
+
+	tests := []struct {
+		name    string
+		mcpURL  string
+		pid     int
+		wantErr string
+	}{
+		{"loopback_match", "http://127.0.0.1:9119/mcp", 4242, ""},
+		{"localhost_match", "http://localhost:9119/mcp", 4242, ""},
+		{"default_url", "", 4242, ""},
+		{"pid_mismatch", "http://127.0.0.1:9119/mcp", 1, "does not record PID 1"},
+		{"remote_host", "http://10.0.0.5:9119/mcp", 4242, "not on this machine"},
+		{"container_name", "http://sectool:9119/mcp", 4242, "not on this machine"},
+		{"no_pid_file", "http://127.0.0.1:9200/mcp", 4242, "read PID file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkLocalPID(tt.mcpURL, configPath, tt.pid)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}